@@ -13,18 +13,36 @@ import (
 )
 
 type Config struct {
-	DaemonLogFilePath                    string `envconfig:"DAYTONA_DAEMON_LOG_FILE_PATH"`
-	EntrypointLogFilePath                string `envconfig:"DAYTONA_ENTRYPOINT_LOG_FILE_PATH"`
-	EntrypointShutdownTimeoutSec         int    `envconfig:"ENTRYPOINT_SHUTDOWN_TIMEOUT_SEC"`
-	SigtermShutdownTimeoutSec            int    `envconfig:"SIGTERM_SHUTDOWN_TIMEOUT_SEC"`
-	UserHomeAsWorkDir                    bool   `envconfig:"DAYTONA_USER_HOME_AS_WORKDIR"`
-	TerminationGracePeriodSeconds        int    `envconfig:"DAYTONA_TERMINATION_GRACE_PERIOD_SECONDS"`        // Period in seconds to wait before forcefully terminating processes
-	TerminationCheckIntervalMilliseconds int    `envconfig:"DAYTONA_TERMINATION_CHECK_INTERVAL_MILLISECONDS"` // Interval in milliseconds to check for process termination
+	DaemonLogFilePath                    string   `envconfig:"DAYTONA_DAEMON_LOG_FILE_PATH"`
+	EntrypointLogFilePath                string   `envconfig:"DAYTONA_ENTRYPOINT_LOG_FILE_PATH"`
+	EntrypointShutdownTimeoutSec         int      `envconfig:"ENTRYPOINT_SHUTDOWN_TIMEOUT_SEC"`
+	SigtermShutdownTimeoutSec            int      `envconfig:"SIGTERM_SHUTDOWN_TIMEOUT_SEC"`
+	UserHomeAsWorkDir                    bool     `envconfig:"DAYTONA_USER_HOME_AS_WORKDIR"`
+	TerminationGracePeriodSeconds        int      `envconfig:"DAYTONA_TERMINATION_GRACE_PERIOD_SECONDS"`        // Period in seconds to wait before forcefully terminating processes
+	TerminationCheckIntervalMilliseconds int      `envconfig:"DAYTONA_TERMINATION_CHECK_INTERVAL_MILLISECONDS"` // Interval in milliseconds to check for process termination
+	MemoryWatchdogDisabled               bool     `envconfig:"DAYTONA_MEMORY_WATCHDOG_DISABLED"`
+	MemoryWatchdogThresholdPercent       int      `envconfig:"DAYTONA_MEMORY_WATCHDOG_THRESHOLD_PERCENT" validate:"min=0,max=100"` // Memory usage in percent of the limit at which the largest process tree is killed
+	Upgraded                             bool     `envconfig:"DAYTONA_DAEMON_UPGRADED"`                                            // Set when the daemon replaced a previous version of itself in the same process
+	DaemonSocket                         string   `envconfig:"DAYTONA_DAEMON_SOCKET"`                                              // Path of a unix socket to serve the toolbox API on
+	TcpDisabled                          bool     `envconfig:"DAYTONA_DAEMON_TCP_DISABLED"`                                        // Don't serve the toolbox API on the container network, requires DAYTONA_DAEMON_SOCKET
+	AuthToken                            string   `envconfig:"DAYTONA_DAEMON_AUTH_TOKEN"`                                          // Secret required on all toolbox API requests
+	CoreDumpsEnabled                     bool     `envconfig:"DAYTONA_CORE_DUMPS_ENABLED"`                                         // Set by the runner when the kernel writes core dumps to the sandbox
+	CoreDumpMaxCount                     int      `envconfig:"DAYTONA_CORE_DUMP_MAX_COUNT" validate:"min=0"`                       // Number of core dumps to keep, the oldest are removed
+	CoreDumpRetentionSeconds             int      `envconfig:"DAYTONA_CORE_DUMP_RETENTION_SECONDS" validate:"min=0"`               // Age in seconds after which core dumps are removed, 0 keeps them
+	FileHistoryEnabled                   bool     `envconfig:"DAYTONA_FILE_HISTORY_ENABLED"`                                       // Snapshot the working directory so that overwritten or deleted files can be restored
+	FileHistoryIntervalSeconds           int      `envconfig:"DAYTONA_FILE_HISTORY_INTERVAL_SECONDS" validate:"min=0"`             // Interval in seconds to snapshot the working directory at if anything changed
+	FileHistoryChangeThreshold           int      `envconfig:"DAYTONA_FILE_HISTORY_CHANGE_THRESHOLD" validate:"min=0"`             // Number of changed files that triggers a snapshot before the interval elapses
+	FileHistoryMaxVersions               int      `envconfig:"DAYTONA_FILE_HISTORY_MAX_VERSIONS" validate:"min=0"`
+	FileHistoryMaxFileSizeMB             int      `envconfig:"DAYTONA_FILE_HISTORY_MAX_FILE_SIZE_MB" validate:"min=0"` // Larger files are not kept in the history
+	FileHistoryIgnore                    []string `envconfig:"DAYTONA_FILE_HISTORY_IGNORE"`                            // Names of files and directories not kept in the history
 }
 
 var defaultDaemonLogFilePath = "/tmp/daytona-daemon.log"
 var defaultEntrypointLogFilePath = "/tmp/daytona-entrypoint.log"
 
+// Dependencies, caches and build output, which can be recreated
+var defaultFileHistoryIgnore = []string{".daytona", ".cache", ".venv", "__pycache__", "node_modules", "target", "dist", "build"}
+
 var config *Config
 
 func GetConfig() (*Config, error) {
@@ -74,5 +92,39 @@ func GetConfig() (*Config, error) {
 		config.TerminationCheckIntervalMilliseconds = 100
 	}
 
+	if config.MemoryWatchdogThresholdPercent <= 0 {
+		// Default to 95 percent
+		config.MemoryWatchdogThresholdPercent = 95
+	}
+
+	if config.FileHistoryIntervalSeconds <= 0 {
+		// Default to 5 minutes
+		config.FileHistoryIntervalSeconds = 300
+	}
+
+	if config.FileHistoryChangeThreshold <= 0 {
+		// Default to 20 files
+		config.FileHistoryChangeThreshold = 20
+	}
+
+	if config.FileHistoryMaxVersions <= 0 {
+		// Default to 50 versions
+		config.FileHistoryMaxVersions = 50
+	}
+
+	if config.FileHistoryMaxFileSizeMB <= 0 {
+		// Default to 50 MB
+		config.FileHistoryMaxFileSizeMB = 50
+	}
+
+	if config.FileHistoryIgnore == nil {
+		config.FileHistoryIgnore = defaultFileHistoryIgnore
+	}
+
+	if config.CoreDumpMaxCount <= 0 {
+		// Default to 5 core dumps
+		config.CoreDumpMaxCount = 5
+	}
+
 	return config, nil
 }
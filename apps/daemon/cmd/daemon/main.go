@@ -17,10 +17,16 @@ import (
 	golog "log"
 
 	"github.com/daytonaio/daemon/cmd/daemon/config"
+	"github.com/daytonaio/daemon/internal"
 	"github.com/daytonaio/daemon/internal/util"
+	"github.com/daytonaio/daemon/pkg/coredump"
+	"github.com/daytonaio/daemon/pkg/filehistory"
 	"github.com/daytonaio/daemon/pkg/ssh"
 	"github.com/daytonaio/daemon/pkg/terminal"
 	"github.com/daytonaio/daemon/pkg/toolbox"
+	toolbox_config "github.com/daytonaio/daemon/pkg/toolbox/config"
+	"github.com/daytonaio/daemon/pkg/upgrade"
+	"github.com/daytonaio/daemon/pkg/watchdog"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -63,10 +69,19 @@ func main() {
 		}
 	}
 
+	// An upgraded daemon runs in the process of the previous one, whose entrypoint is still running
+	if c.Upgraded {
+		log.Infof("Daemon upgraded to %s", internal.Version)
+		os.Unsetenv(upgrade.UpgradedEnvVar)
+	}
+
+	// Keep the auth token out of the environment of the entrypoint and the processes started by the daemon
+	os.Unsetenv(toolbox_config.AUTH_TOKEN_ENV_VAR)
+
 	// Execute passed arguments as command
 	var entrypointCmd *exec.Cmd
 	var entrypointWg sync.WaitGroup
-	if len(args) > 0 {
+	if len(args) > 0 && !c.Upgraded {
 		// used for logging in case of errors starting/waiting for the command
 		entrypointLogWriter := os.Stdout
 		entrypointErrLogWriter := os.Stderr
@@ -117,6 +132,32 @@ func main() {
 		WorkDir:                              workDir,
 		TerminationGracePeriodSeconds:        c.TerminationGracePeriodSeconds,
 		TerminationCheckIntervalMilliseconds: c.TerminationCheckIntervalMilliseconds,
+		SocketPath:                           c.DaemonSocket,
+		TcpDisabled:                          c.TcpDisabled,
+		AuthToken:                            c.AuthToken,
+	}
+
+	if !c.MemoryWatchdogDisabled {
+		memoryWatchdog := watchdog.NewWatchdog(c.MemoryWatchdogThresholdPercent, time.Second)
+		go memoryWatchdog.Start(context.Background())
+		toolBoxServer.MemoryWatchdog = memoryWatchdog
+	}
+
+	if c.FileHistoryEnabled {
+		toolBoxServer.FileHistory = &filehistory.Config{
+			Interval:        time.Duration(c.FileHistoryIntervalSeconds) * time.Second,
+			CheckInterval:   30 * time.Second,
+			ChangeThreshold: c.FileHistoryChangeThreshold,
+			MaxVersions:     c.FileHistoryMaxVersions,
+			MaxFileSize:     int64(c.FileHistoryMaxFileSizeMB) * 1024 * 1024,
+			Ignore:          c.FileHistoryIgnore,
+		}
+	}
+
+	if c.CoreDumpsEnabled {
+		coreDumps := coredump.NewManager(c.CoreDumpMaxCount, time.Duration(c.CoreDumpRetentionSeconds)*time.Second)
+		go coreDumps.Start(context.Background())
+		toolBoxServer.CoreDumps = coreDumps
 	}
 
 	// Start the toolbox server in a go routine
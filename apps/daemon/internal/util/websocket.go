@@ -4,6 +4,7 @@
 package util
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gorilla/websocket"
@@ -34,3 +35,20 @@ func UpgradeToWebSocket(w http.ResponseWriter, r *http.Request) (*websocket.Conn
 
 	return ws, nil
 }
+
+// ContextWithCancelOnClose returns a context that is cancelled once the WebSocket peer disconnects.
+// It reads and discards incoming messages, so it should only be used for server-to-client streams.
+func ContextWithCancelOnClose(ctx context.Context, ws *websocket.Conn) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	return ctx, cancel
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// ParseSignal parses a signal name with or without the SIG prefix (e.g., SIGINT or INT) or a signal number
+func ParseSignal(value string) (syscall.Signal, error) {
+	if num, err := strconv.Atoi(value); err == nil {
+		if num <= 0 || num > 64 {
+			return 0, fmt.Errorf("invalid signal number: %d", num)
+		}
+		return syscall.Signal(num), nil
+	}
+
+	name := strings.ToUpper(value)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+
+	signal := unix.SignalNum(name)
+	if signal == 0 {
+		return 0, fmt.Errorf("unknown signal: %s", value)
+	}
+
+	return signal, nil
+}
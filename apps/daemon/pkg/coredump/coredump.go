@@ -0,0 +1,190 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package coredump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Dir is where the kernel writes the core dumps of the sandbox. The runner sets the core pattern
+// of the host to core.<executable>.<pid>.<unix time> in this directory.
+const Dir = "/var/lib/daytona/cores"
+
+const cleanupInterval = time.Minute
+
+var ErrNotFound = errors.New("core dump not found")
+
+type CoreDump struct {
+	Name       string    `json:"name"`
+	Executable string    `json:"executable"`
+	Pid        int       `json:"pid"`
+	Size       int64     `json:"size"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Manager lists the core dumps of the sandbox and removes the oldest ones, keeping at most
+// maxCount dumps no older than retention
+type Manager struct {
+	maxCount  int
+	retention time.Duration
+}
+
+func NewManager(maxCount int, retention time.Duration) *Manager {
+	return &Manager{
+		maxCount:  maxCount,
+		retention: retention,
+	}
+}
+
+// Start creates the core dump directory and cleans it up periodically
+func (m *Manager) Start(ctx context.Context) {
+	if err := os.MkdirAll(Dir, 0755); err != nil {
+		log.Errorf("Failed to create the core dump directory: %v", err)
+		return
+	}
+	// Processes of any user must be able to dump, the kernel doesn't create the file otherwise
+	if err := os.Chmod(Dir, 0777|os.ModeSticky); err != nil {
+		log.Errorf("Failed to set the permissions of the core dump directory: %v", err)
+	}
+
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		m.cleanup()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) List() ([]CoreDump, error) {
+	entries, err := os.ReadDir(Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []CoreDump{}, nil
+		}
+		return nil, err
+	}
+
+	dumps := []CoreDump{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		dumps = append(dumps, parseCoreDump(info))
+	}
+
+	// Newest first
+	sort.Slice(dumps, func(i, j int) bool {
+		return dumps[i].CreatedAt.After(dumps[j].CreatedAt)
+	})
+
+	return dumps, nil
+}
+
+// Path returns the path of a core dump, rejecting names outside of the core dump directory
+func (m *Manager) Path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid core dump name %q", name)
+	}
+
+	path := filepath.Join(Dir, name)
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", ErrNotFound
+	}
+
+	return path, nil
+}
+
+func (m *Manager) Delete(name string) error {
+	path, err := m.Path(name)
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+func (m *Manager) cleanup() {
+	dumps, err := m.List()
+	if err != nil {
+		log.Errorf("Failed to list core dumps: %v", err)
+		return
+	}
+
+	for i, dump := range dumps {
+		if i < m.maxCount && (m.retention <= 0 || time.Since(dump.CreatedAt) < m.retention) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(Dir, dump.Name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Errorf("Failed to remove core dump %s: %v", dump.Name, err)
+			continue
+		}
+		log.Infof("Removed core dump %s", dump.Name)
+	}
+}
+
+// parseCoreDump reads the executable, pid and time of a dump from its name, falling back to the
+// modification time for files that don't follow the pattern
+func parseCoreDump(info os.FileInfo) CoreDump {
+	dump := CoreDump{
+		Name:      info.Name(),
+		Size:      info.Size(),
+		CreatedAt: info.ModTime(),
+	}
+
+	rest, ok := strings.CutPrefix(info.Name(), "core.")
+	if !ok {
+		return dump
+	}
+
+	// The executable name may contain dots, the pid and time are the last two fields
+	parts := strings.Split(rest, ".")
+	if len(parts) < 3 {
+		return dump
+	}
+
+	pid, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil {
+		return dump
+	}
+	timestamp, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+	if err != nil {
+		return dump
+	}
+
+	dump.Executable = strings.Join(parts[:len(parts)-2], ".")
+	dump.Pid = pid
+	dump.CreatedAt = time.Unix(timestamp, 0)
+
+	return dump
+}
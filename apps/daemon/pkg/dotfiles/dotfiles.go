@@ -0,0 +1,473 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package dotfiles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/env"
+	"github.com/daytonaio/daemon/pkg/git"
+	"github.com/daytonaio/daemon/pkg/gitprovider"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	go_git "github.com/go-git/go-git/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+// SpecPath is where the runner writes the dotfiles of a sandbox before it first starts
+const SpecPath = "/.daytona-dotfiles.json"
+
+// Output kept of a run, the end of it
+const maxOutputSize = 16 * 1024
+
+// Install scripts looked for in dotfiles repositories, in order, as other cloud development
+// environments do. Repositories without one have their dotfiles linked into the home directory.
+var installScripts = []string{
+	"install.sh",
+	"install",
+	"bootstrap.sh",
+	"bootstrap",
+	"script/bootstrap",
+	"setup.sh",
+	"setup",
+	"script/setup",
+}
+
+var ErrRunning = errors.New("dotfiles are already being applied")
+
+var ErrNoDotfiles = errors.New("sandbox has no dotfiles")
+
+type State string
+
+const (
+	StatePending   State = "pending"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+)
+
+// Phase of a run of the dotfiles
+type Phase string
+
+const (
+	PhaseClone   Phase = "clone"
+	PhaseInstall Phase = "install"
+	PhaseLink    Phase = "link"
+)
+
+// Spec is either a repository of dotfiles or a script
+type Spec struct {
+	// Git repository of the dotfiles, cloned to ~/.dotfiles
+	Repository string `json:"repository,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+	// Token of private repositories
+	Token string `json:"token,omitempty"`
+	// Command run in the clone instead of the install script of the repository
+	InstallCommand string `json:"installCommand,omitempty"`
+	// Script run in the home directory instead of cloning a repository
+	Script string `json:"script,omitempty"`
+	// Runs taking longer are killed, no timeout if 0
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+type Status struct {
+	State State `json:"state"`
+	Phase Phase `json:"phase,omitempty"`
+	// Number of times the dotfiles were applied, on the first boot and on demand
+	Runs  int    `json:"runs"`
+	Error string `json:"error,omitempty"`
+	// End of the output of the run, as it runs
+	Output string `json:"output,omitempty"`
+	// Commit of the dotfiles repository that was applied
+	Commit     string     `json:"commit,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Bootstrapper applies the dotfiles of the user once, on the first boot of the sandbox, and again
+// on demand. Its status is stored in the config dir, so a daemon restarted while applying them
+// applies them again, and applied or failed dotfiles are not applied again on boot.
+type Bootstrapper struct {
+	specPath   string
+	statusPath string
+	homeDir    string
+	mu         sync.Mutex
+	spec       *Spec
+	status     *Status
+	output     *tailWriter
+}
+
+func NewBootstrapper(specPath, configDir, homeDir string) *Bootstrapper {
+	return &Bootstrapper{
+		specPath:   specPath,
+		statusPath: filepath.Join(configDir, "dotfiles.json"),
+		homeDir:    homeDir,
+	}
+}
+
+// Start applies the dotfiles in the background unless they were already applied. It returns false
+// if the sandbox has no dotfiles.
+func (b *Bootstrapper) Start() bool {
+	data, err := os.ReadFile(b.specPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Errorf("Failed to read dotfiles: %v", err)
+		}
+		return false
+	}
+
+	var spec Spec
+	err = json.Unmarshal(data, &spec)
+	if err == nil {
+		err = validate(spec)
+	}
+	if err != nil {
+		log.Errorf("Invalid dotfiles: %v", err)
+		return false
+	}
+
+	b.mu.Lock()
+	b.spec = &spec
+	b.status = b.loadStatus()
+	pending := b.status.State == StatePending
+	if pending {
+		b.status.State = StateRunning
+	}
+	b.mu.Unlock()
+
+	if pending {
+		go b.run(spec)
+	}
+
+	return true
+}
+
+// Apply applies the dotfiles again in the background, or the given ones instead. Given dotfiles are
+// the ones applied by later calls without any, until the daemon restarts.
+func (b *Bootstrapper) Apply(spec *Spec) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.status != nil && b.status.State == StateRunning {
+		return ErrRunning
+	}
+
+	if spec == nil {
+		spec = b.spec
+	}
+	if spec == nil {
+		return ErrNoDotfiles
+	}
+	if err := validate(*spec); err != nil {
+		return err
+	}
+
+	b.spec = spec
+	if b.status == nil {
+		b.status = &Status{}
+	}
+	b.status.State = StateRunning
+
+	go b.run(*spec)
+
+	return nil
+}
+
+// Status returns the status of the dotfiles, nil if the sandbox has none
+func (b *Bootstrapper) Status() *Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.status == nil {
+		return nil
+	}
+
+	status := *b.status
+	if status.State == StateRunning && b.output != nil {
+		status.Output = b.output.String()
+	}
+	return &status
+}
+
+func (b *Bootstrapper) loadStatus() *Status {
+	status := &Status{State: StatePending}
+
+	data, err := os.ReadFile(b.statusPath)
+	if err != nil {
+		return status
+	}
+
+	var saved Status
+	if err := json.Unmarshal(data, &saved); err != nil {
+		log.Warnf("Ignoring invalid dotfiles status: %v", err)
+		return status
+	}
+
+	// Runs interrupted by a restart are run again
+	if saved.State == StateRunning {
+		saved.State = StatePending
+	}
+
+	return &saved
+}
+
+func (b *Bootstrapper) run(spec Spec) {
+	output := &tailWriter{}
+	now := time.Now()
+	b.update(func(status *Status) {
+		status.State = StateRunning
+		status.Phase = ""
+		status.Runs++
+		status.Error = ""
+		status.Output = ""
+		status.Commit = ""
+		status.StartedAt = &now
+		status.FinishedAt = nil
+		b.output = output
+	})
+
+	ctx := context.Background()
+	if spec.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(spec.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	err := b.apply(ctx, spec, output)
+	if err != nil && ctx.Err() != nil {
+		err = fmt.Errorf("timed out after %d seconds", spec.TimeoutSeconds)
+	}
+
+	finished := time.Now()
+	b.update(func(status *Status) {
+		status.State = StateSucceeded
+		if err != nil {
+			status.State = StateFailed
+			status.Error = err.Error()
+		}
+		status.Output = output.String()
+		status.FinishedAt = &finished
+		b.output = nil
+	})
+
+	if err != nil {
+		log.Errorf("Failed to apply dotfiles: %v", err)
+		return
+	}
+	log.Info("Dotfiles applied")
+}
+
+func (b *Bootstrapper) apply(ctx context.Context, spec Spec, output io.Writer) error {
+	if spec.Script != "" {
+		b.setPhase(PhaseInstall)
+		return b.runCommand(ctx, b.homeDir, output, common.GetShell(), "-c", spec.Script)
+	}
+
+	b.setPhase(PhaseClone)
+	repoDir := filepath.Join(b.homeDir, ".dotfiles")
+	commit, err := b.cloneOrPull(ctx, spec, repoDir, output)
+	if err != nil {
+		return err
+	}
+	b.update(func(status *Status) {
+		status.Commit = commit
+	})
+
+	if spec.InstallCommand != "" {
+		b.setPhase(PhaseInstall)
+		return b.runCommand(ctx, repoDir, output, common.GetShell(), "-c", spec.InstallCommand)
+	}
+
+	for _, script := range installScripts {
+		scriptPath := filepath.Join(repoDir, script)
+		info, err := os.Stat(scriptPath)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		b.setPhase(PhaseInstall)
+		fmt.Fprintf(output, "Running %s\n", script)
+		// Scripts that aren't executable are run with the shell
+		if info.Mode()&0111 == 0 {
+			return b.runCommand(ctx, repoDir, output, common.GetShell(), scriptPath)
+		}
+		return b.runCommand(ctx, repoDir, output, scriptPath)
+	}
+
+	b.setPhase(PhaseLink)
+	return b.link(repoDir, output)
+}
+
+// cloneOrPull clones the dotfiles repository, or pulls it if a previous run cloned it, and returns
+// the commit it checked out
+func (b *Bootstrapper) cloneOrPull(ctx context.Context, spec Spec, repoDir string, output io.Writer) (string, error) {
+	var auth transport.AuthMethod
+	if spec.Token != "" {
+		auth = &http.BasicAuth{Username: "git", Password: spec.Token}
+	}
+
+	gitService := &git.Service{WorkDir: repoDir, LogWriter: output}
+
+	exists, err := gitService.RepositoryExists()
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		fmt.Fprintf(output, "Pulling %s\n", spec.Repository)
+		err = gitService.Pull(auth)
+		if errors.Is(err, go_git.NoErrAlreadyUpToDate) {
+			err = nil
+		}
+	} else {
+		fmt.Fprintf(output, "Cloning %s\n", spec.Repository)
+		err = gitService.CloneRepository(&gitprovider.GitRepository{
+			Url:    spec.Repository,
+			Branch: spec.Branch,
+		}, auth)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dotfiles repository: %w", err)
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	repo, err := go_git.PlainOpen(repoDir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String(), nil
+}
+
+// link links the dotfiles at the root of the repository into the home directory. Files the user
+// already has are kept.
+func (b *Bootstrapper) link(repoDir string, output io.Writer) error {
+	entries, err := os.ReadDir(repoDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, ".") || name == ".git" || name == ".github" || name == ".gitignore" {
+			continue
+		}
+
+		target := filepath.Join(b.homeDir, name)
+		source := filepath.Join(repoDir, name)
+
+		if existing, err := os.Lstat(target); err == nil {
+			if existing.Mode()&os.ModeSymlink == 0 {
+				fmt.Fprintf(output, "Skipping %s, it already exists\n", name)
+				continue
+			}
+			if err := os.Remove(target); err != nil {
+				return err
+			}
+		}
+
+		if err := os.Symlink(source, target); err != nil {
+			return fmt.Errorf("failed to link %s: %w", name, err)
+		}
+		fmt.Fprintf(output, "Linked %s\n", name)
+	}
+
+	return nil
+}
+
+func (b *Bootstrapper) runCommand(ctx context.Context, dir string, output io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = env.Environ()
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// Kill the whole process group of the command on timeout
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("exited with code %d", exitErr.ExitCode())
+	}
+	return err
+}
+
+func (b *Bootstrapper) setPhase(phase Phase) {
+	b.update(func(status *Status) {
+		status.Phase = phase
+	})
+}
+
+// update changes the status and persists it
+func (b *Bootstrapper) update(change func(status *Status)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	change(b.status)
+
+	data, err := json.Marshal(b.status)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(b.statusPath, data, 0600); err != nil {
+		log.Errorf("Failed to save dotfiles status: %v", err)
+	}
+}
+
+func validate(spec Spec) error {
+	if (spec.Repository == "") == (spec.Script == "") {
+		return errors.New("dotfiles need either a repository or a script")
+	}
+	if spec.Script != "" && spec.InstallCommand != "" {
+		return errors.New("the install command only applies to dotfiles repositories")
+	}
+	return nil
+}
+
+// tailWriter keeps the end of the output written to it
+type tailWriter struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > maxOutputSize {
+		w.buf = w.buf[len(w.buf)-maxOutputSize:]
+	}
+	return len(p), nil
+}
+
+func (w *tailWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return string(w.buf)
+}
@@ -0,0 +1,259 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package env
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var nameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Placeholder for escaped dollar signs while expanding variable references
+const literalDollar = "\x00"
+
+// ValidateName returns an error if name can't be used as an environment variable name
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid environment variable name: %q", name)
+	}
+	return nil
+}
+
+// Parse reads variables in dotenv format. It supports comments, an optional "export" prefix,
+// single quoted literal values, double quoted values with escapes and multiline values, and
+// ${VAR} references to previously defined or process variables in unquoted and double quoted values.
+func Parse(r io.Reader) (map[string]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]string{}
+	lookup := func(name string) string {
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return os.Getenv(name)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected NAME=value", lineNum)
+		}
+		name = strings.TrimSpace(name)
+		if err := ValidateName(name); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(value, "'"):
+			// Single quoted values are literal and may span lines
+			for !closedQuote(value, '\'') && i+1 < len(lines) {
+				i++
+				value += "\n" + lines[i]
+			}
+			end := strings.IndexByte(value[1:], '\'') + 1
+			if end == 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quoted value", lineNum)
+			}
+			value = value[1:end]
+		case strings.HasPrefix(value, `"`):
+			for !closedQuote(value, '"') && i+1 < len(lines) {
+				i++
+				value += "\n" + lines[i]
+			}
+			end := closingQuoteIndex(value, '"')
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: unterminated double quoted value", lineNum)
+			}
+			value = strings.ReplaceAll(os.Expand(unescape(value[1:end]), lookup), literalDollar, "$")
+		default:
+			if idx := strings.Index(value, " #"); idx != -1 {
+				value = strings.TrimSpace(value[:idx])
+			}
+			value = os.Expand(value, lookup)
+		}
+
+		vars[name] = value
+	}
+
+	return vars, nil
+}
+
+// ParseFile reads variables from a dotenv file
+func ParseFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(file)
+}
+
+// Format renders variables in dotenv format, sorted by name
+func Format(vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name + "=" + quote(vars[name]) + "\n")
+	}
+	return sb.String()
+}
+
+// MergeFile updates a dotenv file with vars. Existing assignments are replaced in place, keeping
+// comments and ordering, and new variables are appended. The file is created if it doesn't exist.
+// It returns the resulting variables.
+func MergeFile(path string, vars map[string]string) (map[string]string, error) {
+	for name := range vars {
+		if err := ValidateName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var lines []string
+	mode := os.FileMode(0644)
+
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	written := map[string]bool{}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		name, _, ok := strings.Cut(strings.TrimPrefix(trimmed, "export "), "=")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		value, ok := vars[name]
+		if !ok {
+			continue
+		}
+
+		prefix := ""
+		if strings.HasPrefix(trimmed, "export ") {
+			prefix = "export "
+		}
+		// Multiline values of replaced assignments are not tracked, they are rare enough in practice
+		lines[i] = prefix + name + "=" + quote(value)
+		written[name] = true
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		if !written[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, name+"="+quote(vars[name]))
+	}
+
+	content := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		return nil, err
+	}
+
+	return Parse(strings.NewReader(content))
+}
+
+// quote returns value as a dotenv value that parses back to itself. Line breaks are escaped in
+// double quotes, as lines are trimmed and line endings normalized when parsing.
+func quote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\r\n\"'#$\\") {
+		return value
+	}
+	if !strings.ContainsAny(value, "'\r\n") {
+		return "'" + value + "'"
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", `\$`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+func closedQuote(value string, q byte) bool {
+	if q == '"' {
+		return closingQuoteIndex(value, q) != -1
+	}
+	return strings.IndexByte(value[1:], q) != -1
+}
+
+// closingQuoteIndex returns the index of the first unescaped quote after the opening one
+func closingQuoteIndex(value string, q byte) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case q:
+			return i
+		}
+	}
+	return -1
+}
+
+func unescape(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			sb.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '$':
+			// Keep escaped dollars literal through expansion
+			sb.WriteString(literalDollar)
+		default:
+			sb.WriteByte(value[i])
+		}
+	}
+	return sb.String()
+}
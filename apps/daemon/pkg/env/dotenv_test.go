@@ -0,0 +1,187 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Setenv("DOTENV_TEST_HOME", "/home/daytona")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]string
+		err      string
+	}{
+		{
+			name:     "plain values",
+			input:    "A=1\nB=two words\n",
+			expected: map[string]string{"A": "1", "B": "two words"},
+		},
+		{
+			name:     "comments, blank lines and export prefix",
+			input:    "# comment\n\nexport A=1\n  B = 2  \n",
+			expected: map[string]string{"A": "1", "B": "2"},
+		},
+		{
+			name:     "inline comment in unquoted value",
+			input:    "A=value # comment\nB=a#b\n",
+			expected: map[string]string{"A": "value", "B": "a#b"},
+		},
+		{
+			name:     "empty value",
+			input:    "A=\nB=''\nC=\"\"\n",
+			expected: map[string]string{"A": "", "B": "", "C": ""},
+		},
+		{
+			name:     "single quoted values are literal",
+			input:    `A='${DOTENV_TEST_HOME} \n # not a comment'`,
+			expected: map[string]string{"A": `${DOTENV_TEST_HOME} \n # not a comment`},
+		},
+		{
+			name:     "double quoted values are unescaped",
+			input:    `A="line1\nline2\t\"quoted\" \\ \$HOME"`,
+			expected: map[string]string{"A": "line1\nline2\t\"quoted\" \\ $HOME"},
+		},
+		{
+			name:     "multiline quoted values",
+			input:    "A='first\nsecond'\nB=\"third\nfourth\"\nC=after\n",
+			expected: map[string]string{"A": "first\nsecond", "B": "third\nfourth", "C": "after"},
+		},
+		{
+			name:     "references to earlier and process variables",
+			input:    "A=a\nB=${A}-b\nC=\"${DOTENV_TEST_HOME}/${B}\"\nD='${A}'\n",
+			expected: map[string]string{"A": "a", "B": "a-b", "C": "/home/daytona/a-b", "D": "${A}"},
+		},
+		{
+			name:     "CRLF line endings",
+			input:    "A=1\r\nB='2'\r\n",
+			expected: map[string]string{"A": "1", "B": "2"},
+		},
+		{
+			name:  "missing equals sign",
+			input: "A=1\nINVALID\n",
+			err:   "line 2: expected NAME=value",
+		},
+		{
+			name:  "invalid name",
+			input: "1A=1\n",
+			err:   "line 1: invalid environment variable name",
+		},
+		{
+			name:  "unterminated single quote",
+			input: "A='open\nB=1\n",
+			err:   "line 1: unterminated single quoted value",
+		},
+		{
+			name:  "unterminated double quote",
+			input: "A=\"open \\\"\n",
+			err:   "line 1: unterminated double quoted value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars, err := Parse(strings.NewReader(tt.input))
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, vars)
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	values := []string{
+		"",
+		"plain",
+		"two words",
+		"with # hash",
+		"$HOME and ${HOME}",
+		`back\slash`,
+		"single ' quote",
+		`double " quote`,
+		`both ' and " quotes`,
+		"it's $HOME\\n",
+		"tab\tseparated",
+		"multi\nline",
+		"trailing spaces   \nnext line",
+		"  leading and trailing  ",
+		"windows\r\nline endings",
+		"ends with backslash\\",
+		"'",
+		`"`,
+		"=equals=",
+	}
+
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			formatted := Format(map[string]string{"A": value})
+
+			vars, err := Parse(strings.NewReader(formatted))
+			require.NoError(t, err, "formatted as %q", formatted)
+			assert.Equal(t, value, vars["A"], "formatted as %q", formatted)
+		})
+	}
+}
+
+func TestMergeFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *string
+		vars     map[string]string
+		content  string
+	}{
+		{
+			name:    "creates the file",
+			vars:    map[string]string{"B": "2", "A": "one two"},
+			content: "A='one two'\nB=2\n",
+		},
+		{
+			name:     "replaces in place and appends",
+			existing: ptr("# settings\nexport A=old\nB=keep\n"),
+			vars:     map[string]string{"A": "new", "C": "3"},
+			content:  "# settings\nexport A=new\nB=keep\nC=3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			if tt.existing != nil {
+				require.NoError(t, os.WriteFile(path, []byte(*tt.existing), 0600))
+			}
+
+			merged, err := MergeFile(path, tt.vars)
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, string(content))
+
+			for name, value := range tt.vars {
+				assert.Equal(t, value, merged[name])
+			}
+		})
+	}
+
+	_, err := MergeFile(filepath.Join(t.TempDir(), ".env"), map[string]string{"INVALID-NAME": "1"})
+	assert.Error(t, err)
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Store holds the sandbox default environment variables. They are persisted to a dotenv
+// file in the daemon config directory and injected into new sessions and exec calls.
+type Store struct {
+	mu   sync.RWMutex
+	path string
+	vars map[string]string
+}
+
+var defaultStore = &Store{
+	vars: map[string]string{},
+}
+
+// Default returns the store used by the daemon
+func Default() *Store {
+	return defaultStore
+}
+
+// Init loads the persisted defaults from the config directory. Until it is called, the
+// default store is empty and changes are kept in memory only.
+func Init(configDir string) error {
+	return defaultStore.load(filepath.Join(configDir, "env"))
+}
+
+// Environ returns the daemon process environment with the sandbox defaults applied
+func Environ() []string {
+	return defaultStore.Environ()
+}
+
+func (s *Store) load(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.path = path
+
+	vars, err := ParseFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to load environment defaults from %s: %w", path, err)
+	}
+	s.vars = vars
+
+	return nil
+}
+
+// Get returns a copy of the default variables
+func (s *Store) Get() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vars := make(map[string]string, len(s.vars))
+	for k, v := range s.vars {
+		vars[k] = v
+	}
+	return vars
+}
+
+// Set adds or updates variables. If replace is true, variables not in vars are removed.
+func (s *Store) Set(vars map[string]string, replace bool) error {
+	for name := range vars {
+		if err := ValidateName(name); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := make(map[string]string, len(vars))
+	if !replace {
+		for k, v := range s.vars {
+			updated[k] = v
+		}
+	}
+	for k, v := range vars {
+		updated[k] = v
+	}
+
+	return s.save(updated)
+}
+
+// Unset removes variables
+func (s *Store) Unset(names ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := make(map[string]string, len(s.vars))
+	for k, v := range s.vars {
+		updated[k] = v
+	}
+	for _, name := range names {
+		delete(updated, name)
+	}
+
+	return s.save(updated)
+}
+
+// Environ returns the daemon process environment with the defaults applied
+func (s *Store) Environ() []string {
+	return Merge(os.Environ(), s.Get())
+}
+
+// save persists vars and makes them current, the caller must hold the lock
+func (s *Store) save(vars map[string]string) error {
+	if s.path != "" {
+		if err := os.WriteFile(s.path, []byte(Format(vars)), 0600); err != nil {
+			return fmt.Errorf("failed to persist environment defaults: %w", err)
+		}
+	}
+
+	s.vars = vars
+	return nil
+}
+
+// Merge returns environ in os.Environ format with vars added, replacing existing entries
+func Merge(environ []string, vars map[string]string) []string {
+	result := make([]string, 0, len(environ)+len(vars))
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if _, ok := vars[name]; ok {
+			continue
+		}
+		result = append(result, entry)
+	}
+	for k, v := range vars {
+		result = append(result, k+"="+v)
+	}
+	return result
+}
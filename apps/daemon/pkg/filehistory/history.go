@@ -0,0 +1,415 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filehistory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Trigger string
+
+const (
+	TriggerSchedule Trigger = "schedule"
+	TriggerChange   Trigger = "change"
+	TriggerManual   Trigger = "manual"
+	// Taken before a restore, so that it can be undone
+	TriggerRestore Trigger = "restore"
+)
+
+var (
+	ErrVersionNotFound = errors.New("version not found")
+	ErrFileNotFound    = errors.New("file not found in version")
+	ErrOutsideWorkDir  = errors.New("path is outside of the working directory")
+)
+
+type Config struct {
+	// A snapshot is taken at this interval if anything changed since the last one
+	Interval time.Duration
+	// Interval the workspace is checked for changes at
+	CheckInterval time.Duration
+	// Number of changed files that triggers a snapshot before the interval elapses
+	ChangeThreshold int
+	// Number of versions kept, the oldest are removed
+	MaxVersions int
+	// Files larger than this are not kept in the history
+	MaxFileSize int64
+	// Names of files and directories that are not kept in the history, e.g. node_modules
+	Ignore []string
+}
+
+type FileEntry struct {
+	Size    int64       `json:"size"`
+	ModTime time.Time   `json:"modTime"`
+	Mode    os.FileMode `json:"mode"`
+}
+
+type Version struct {
+	Id        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Trigger   Trigger   `json:"trigger"`
+	// Number of files in the version
+	Files int `json:"files"`
+	// Number of files added, modified or deleted since the previous version
+	Changes int `json:"changes"`
+}
+
+type manifest struct {
+	Version
+	Entries map[string]FileEntry `json:"entries"`
+}
+
+// FileVersion is a version in which a file differs from the version before
+type FileVersion struct {
+	VersionId string    `json:"versionId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"modTime"`
+}
+
+// History snapshots the files of the workspace so that the ones destroyed or overwritten can be
+// restored. Each version is a full tree, files unchanged since the previous version are hardlinks
+// to it, so a version only takes up the space of the files that changed.
+type History struct {
+	workDir string
+	dir     string
+	config  Config
+
+	mu       sync.Mutex
+	versions []*manifest
+}
+
+func NewHistory(workDir, dir string, config Config) *History {
+	return &History{
+		workDir: workDir,
+		dir:     dir,
+		config:  config,
+	}
+}
+
+// Start loads the versions kept by a previous daemon and snapshots the workspace periodically in
+// the background
+func (h *History) Start(ctx context.Context) error {
+	if err := h.load(); err != nil {
+		return fmt.Errorf("failed to load the file history: %w", err)
+	}
+
+	go h.run(ctx)
+	return nil
+}
+
+func (h *History) run(ctx context.Context) {
+	ticker := time.NewTicker(h.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		h.check()
+	}
+}
+
+// check takes a snapshot if enough files changed, or if any did and the interval elapsed
+func (h *History) check() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	current, err := h.scan()
+	if err != nil {
+		log.Errorf("Failed to scan the workspace: %v", err)
+		return
+	}
+
+	latest := h.latest()
+	changes := countChanges(latest, current)
+	if changes == 0 {
+		return
+	}
+
+	var trigger Trigger
+	switch {
+	case changes >= h.config.ChangeThreshold:
+		trigger = TriggerChange
+	case latest == nil || time.Since(latest.CreatedAt) >= h.config.Interval:
+		trigger = TriggerSchedule
+	default:
+		return
+	}
+
+	if _, err := h.snapshot(current, trigger); err != nil {
+		log.Errorf("Failed to snapshot the workspace: %v", err)
+	}
+}
+
+// Snapshot takes a snapshot of the workspace now, or returns the latest version if nothing changed
+func (h *History) Snapshot() (*Version, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	current, err := h.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	if latest := h.latest(); latest != nil && countChanges(latest, current) == 0 {
+		version := latest.Version
+		return &version, nil
+	}
+
+	return h.snapshot(current, TriggerManual)
+}
+
+// ListVersions returns the versions of the workspace, newest first
+func (h *History) ListVersions() []Version {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	versions := make([]Version, 0, len(h.versions))
+	for i := len(h.versions) - 1; i >= 0; i-- {
+		versions = append(versions, h.versions[i].Version)
+	}
+
+	return versions
+}
+
+// ListFileVersions returns the versions in which a file changed, newest first
+func (h *History) ListFileVersions(path string) ([]FileVersion, error) {
+	relPath, err := h.relPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fileVersions := []FileVersion{}
+	var previous *FileEntry
+	for _, version := range h.versions {
+		entry, ok := version.Entries[relPath]
+		if !ok {
+			previous = nil
+			continue
+		}
+		if previous != nil && sameFile(*previous, entry) {
+			continue
+		}
+		previous = &entry
+
+		fileVersions = append(fileVersions, FileVersion{
+			VersionId: version.Id,
+			CreatedAt: version.CreatedAt,
+			Size:      entry.Size,
+			ModTime:   entry.ModTime,
+		})
+	}
+
+	slices.Reverse(fileVersions)
+	return fileVersions, nil
+}
+
+// FilePath returns the path of the copy of a file in a version
+func (h *History) FilePath(versionId, path string) (string, error) {
+	relPath, err := h.relPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	version := h.find(versionId)
+	if version == nil {
+		return "", ErrVersionNotFound
+	}
+	if _, ok := version.Entries[relPath]; !ok {
+		return "", ErrFileNotFound
+	}
+
+	return filepath.Join(h.filesDir(version.Id), relPath), nil
+}
+
+// Restore replaces a file, or all files in a directory, of the workspace with their copies in a
+// version. The workspace is snapshotted first, so that the restore can be undone. Files created
+// after the version are not removed. It returns the restored paths.
+func (h *History) Restore(versionId, path string) ([]string, error) {
+	relPath, err := h.relPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	version := h.find(versionId)
+	if version == nil {
+		return nil, ErrVersionNotFound
+	}
+
+	var toRestore []string
+	for entryPath := range version.Entries {
+		if relPath == "." || entryPath == relPath || strings.HasPrefix(entryPath, relPath+"/") {
+			toRestore = append(toRestore, entryPath)
+		}
+	}
+	if len(toRestore) == 0 {
+		return nil, ErrFileNotFound
+	}
+	sort.Strings(toRestore)
+
+	current, err := h.scan()
+	if err != nil {
+		return nil, err
+	}
+	if countChanges(h.latest(), current) > 0 {
+		if _, err := h.snapshot(current, TriggerRestore); err != nil {
+			return nil, fmt.Errorf("failed to snapshot the workspace before restoring: %w", err)
+		}
+	}
+
+	restored := make([]string, 0, len(toRestore))
+	for _, entryPath := range toRestore {
+		entry := version.Entries[entryPath]
+		target := filepath.Join(h.workDir, entryPath)
+		if err := restoreFile(filepath.Join(h.filesDir(version.Id), entryPath), target, entry); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", entryPath, err)
+		}
+		restored = append(restored, target)
+	}
+
+	return restored, nil
+}
+
+// relPath returns the path of a file relative to the workspace, rejecting paths outside of it
+func (h *History) relPath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(h.workDir, path)
+	}
+
+	relPath, err := filepath.Rel(h.workDir, filepath.Clean(path))
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, "../") {
+		return "", fmt.Errorf("%w: %s", ErrOutsideWorkDir, path)
+	}
+
+	return filepath.ToSlash(relPath), nil
+}
+
+func (h *History) latest() *manifest {
+	if len(h.versions) == 0 {
+		return nil
+	}
+	return h.versions[len(h.versions)-1]
+}
+
+func (h *History) find(versionId string) *manifest {
+	for _, version := range h.versions {
+		if version.Id == versionId {
+			return version
+		}
+	}
+	return nil
+}
+
+func (h *History) versionDir(versionId string) string {
+	return filepath.Join(h.dir, versionId)
+}
+
+func (h *History) filesDir(versionId string) string {
+	return filepath.Join(h.versionDir(versionId), "files")
+}
+
+// load reads the manifests of the versions in the history directory. Versions without a manifest
+// were interrupted while being taken and are removed.
+func (h *History) load() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.MkdirAll(h.dir, 0700); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(h.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(h.dir, entry.Name(), "manifest.json"))
+		if err != nil {
+			log.Warnf("Removing incomplete file history version %s", entry.Name())
+			os.RemoveAll(filepath.Join(h.dir, entry.Name()))
+			continue
+		}
+
+		var version manifest
+		if err := json.Unmarshal(content, &version); err != nil {
+			log.Warnf("Removing file history version %s with an invalid manifest: %v", entry.Name(), err)
+			os.RemoveAll(filepath.Join(h.dir, entry.Name()))
+			continue
+		}
+
+		h.versions = append(h.versions, &version)
+	}
+
+	sort.Slice(h.versions, func(i, j int) bool {
+		return h.versions[i].CreatedAt.Before(h.versions[j].CreatedAt)
+	})
+
+	return nil
+}
+
+// prune removes the oldest versions beyond the maximum
+func (h *History) prune() {
+	for len(h.versions) > h.config.MaxVersions {
+		oldest := h.versions[0]
+		if err := os.RemoveAll(h.versionDir(oldest.Id)); err != nil {
+			log.Errorf("Failed to remove file history version %s: %v", oldest.Id, err)
+			return
+		}
+		h.versions = h.versions[1:]
+	}
+}
+
+func countChanges(previous *manifest, current map[string]FileEntry) int {
+	if previous == nil {
+		return len(current)
+	}
+
+	changes := 0
+	for path, entry := range current {
+		previousEntry, ok := previous.Entries[path]
+		if !ok || !sameFile(previousEntry, entry) {
+			changes++
+		}
+	}
+	for path := range previous.Entries {
+		if _, ok := current[path]; !ok {
+			changes++
+		}
+	}
+
+	return changes
+}
+
+func sameFile(a, b FileEntry) bool {
+	return a.Size == b.Size && a.ModTime.Equal(b.ModTime) && a.Mode == b.Mode
+}
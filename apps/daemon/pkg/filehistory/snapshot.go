@@ -0,0 +1,187 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filehistory
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// scan returns the files of the workspace that are kept in the history
+func (h *History) scan() (map[string]FileEntry, error) {
+	entries := map[string]FileEntry{}
+
+	err := filepath.WalkDir(h.workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped, not the whole scan
+			if d != nil && d.IsDir() && path != h.workDir {
+				return filepath.SkipDir
+			}
+			if path == h.workDir {
+				return err
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			if path != h.workDir && (path == h.dir || slices.Contains(h.config.Ignore, d.Name())) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() || slices.Contains(h.config.Ignore, d.Name()) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || info.Size() > h.config.MaxFileSize {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(h.workDir, path)
+		if err != nil {
+			return nil
+		}
+
+		entries[filepath.ToSlash(relPath)] = FileEntry{
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Mode:    info.Mode(),
+		}
+		return nil
+	})
+
+	return entries, err
+}
+
+// snapshot copies the scanned files into a new version. Files unchanged since the latest version
+// are hardlinked to it instead. The manifest is written last, so versions interrupted before it
+// are removed on the next start.
+func (h *History) snapshot(current map[string]FileEntry, trigger Trigger) (*Version, error) {
+	now := time.Now()
+	latest := h.latest()
+
+	version := &manifest{
+		Version: Version{
+			Id:        now.UTC().Format("20060102-150405.000000000"),
+			CreatedAt: now,
+			Trigger:   trigger,
+			Changes:   countChanges(latest, current),
+		},
+		Entries: make(map[string]FileEntry, len(current)),
+	}
+
+	filesDir := h.filesDir(version.Id)
+	if err := os.MkdirAll(filesDir, 0700); err != nil {
+		return nil, err
+	}
+
+	for relPath, entry := range current {
+		target := filepath.Join(filesDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return nil, err
+		}
+
+		if latest != nil {
+			if previous, ok := latest.Entries[relPath]; ok && sameFile(previous, entry) {
+				if err := os.Link(filepath.Join(h.filesDir(latest.Id), relPath), target); err == nil {
+					version.Entries[relPath] = entry
+					continue
+				}
+			}
+		}
+
+		// Files removed or made unreadable since the scan are left out of the version
+		if err := copyFile(filepath.Join(h.workDir, relPath), target, entry); err != nil {
+			if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, os.ErrPermission) {
+				log.Warnf("Failed to copy %s into the file history: %v", relPath, err)
+			}
+			continue
+		}
+		version.Entries[relPath] = entry
+	}
+	version.Files = len(version.Entries)
+
+	content, err := json.Marshal(version)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(h.versionDir(version.Id), "manifest.json"), content, 0600); err != nil {
+		os.RemoveAll(h.versionDir(version.Id))
+		return nil, err
+	}
+
+	h.versions = append(h.versions, version)
+	h.prune()
+
+	log.Debugf("Took file history version %s with %d files and %d changes", version.Id, version.Files, version.Changes)
+
+	result := version.Version
+	return &result, nil
+}
+
+// copyFile copies a file with the modification time of its entry, which later scans compare to
+func copyFile(source, target string, entry FileEntry) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(target)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Chtimes(target, entry.ModTime, entry.ModTime)
+}
+
+// restoreFile replaces a file of the workspace with a copy of it from the history. The copy is
+// written next to the file and renamed over it, so the file is never left half written.
+func restoreFile(source, target string, entry FileEntry) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".restore-")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+
+	if err := copyFile(source, tmpPath, entry); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, entry.Mode.Perm()); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, target); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
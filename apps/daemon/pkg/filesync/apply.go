@@ -0,0 +1,240 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filesync
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ConflictPolicy decides what happens to a file changed on both sides since the last sync
+type ConflictPolicy string
+
+const (
+	// The sandbox version is kept next to the file under a conflict name, the local one replaces it
+	ConflictPolicyKeepBoth ConflictPolicy = "keep-both"
+	// The local version replaces the sandbox one
+	ConflictPolicyLocal ConflictPolicy = "local"
+	// The sandbox version is kept, the change is not applied
+	ConflictPolicySandbox ConflictPolicy = "sandbox"
+)
+
+type ChangeStatus string
+
+const (
+	ChangeStatusApplied   ChangeStatus = "applied"
+	ChangeStatusUnchanged ChangeStatus = "unchanged"
+	// Not applied because of a conflict
+	ChangeStatusConflict ChangeStatus = "conflict"
+	ChangeStatusIgnored  ChangeStatus = "ignored"
+	ChangeStatusFailed   ChangeStatus = "failed"
+)
+
+// Change is a local change to apply to the sandbox
+type Change struct {
+	// Path relative to the sync root, with forward slashes
+	Path   string `json:"path"`
+	Delete bool   `json:"delete"`
+	// Hash the file had in the sandbox at the last sync, empty if it didn't exist. The change
+	// conflicts if the file in the sandbox has a different one.
+	BaseHash string      `json:"baseHash"`
+	Hash     string      `json:"hash"`
+	Mode     os.FileMode `json:"mode"`
+	ModTime  time.Time   `json:"modTime"`
+	Chunks   []string    `json:"chunks"`
+}
+
+type ChangeResult struct {
+	Path   string       `json:"path"`
+	Status ChangeStatus `json:"status"`
+	// Whether the file was changed in the sandbox too
+	Conflict bool `json:"conflict"`
+	// Path the sandbox version was moved to, relative to the sync root
+	ConflictPath string `json:"conflictPath,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Apply applies local changes to the files under a root. Each file is written to a temporary file
+// and renamed over the previous one, so it is never seen half written.
+func (s *Syncer) Apply(root string, ignore []string, policy ConflictPolicy, changes []Change) ([]ChangeResult, error) {
+	switch policy {
+	case "":
+		policy = ConflictPolicyKeepBoth
+	case ConflictPolicyKeepBoth, ConflictPolicyLocal, ConflictPolicySandbox:
+	default:
+		return nil, fmt.Errorf("invalid conflict policy %s", policy)
+	}
+
+	matcher, err := NewIgnoreMatcher(ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	s.applyMu.Lock()
+	defer s.applyMu.Unlock()
+
+	rootPath := s.rootPath(root)
+	if err := os.MkdirAll(rootPath, 0755); err != nil {
+		return nil, err
+	}
+
+	results := make([]ChangeResult, 0, len(changes))
+	used := map[string]bool{}
+	for _, change := range changes {
+		result := s.applyChange(rootPath, matcher, policy, change)
+		if result.Status == ChangeStatusApplied {
+			for _, chunkHash := range change.Chunks {
+				used[chunkHash] = true
+			}
+		}
+		results = append(results, result)
+	}
+
+	s.removeChunks(used)
+
+	return results, nil
+}
+
+func (s *Syncer) applyChange(rootPath string, matcher *IgnoreMatcher, policy ConflictPolicy, change Change) ChangeResult {
+	result := ChangeResult{Path: change.Path}
+
+	target, err := filePath(rootPath, change.Path)
+	if err != nil {
+		return failed(result, err)
+	}
+
+	if isIgnored(matcher, change.Path) {
+		result.Status = ChangeStatusIgnored
+		return result
+	}
+
+	current, err := s.currentHash(target)
+	if err != nil {
+		return failed(result, err)
+	}
+
+	if (change.Delete && current == "") || (!change.Delete && current == change.Hash) {
+		if !change.Delete && change.Mode != 0 {
+			if err := os.Chmod(target, change.Mode.Perm()); err != nil {
+				return failed(result, err)
+			}
+		}
+		result.Status = ChangeStatusUnchanged
+		return result
+	}
+
+	result.Conflict = current != change.BaseHash
+	if result.Conflict {
+		// Deleting a file changed in the sandbox would lose the change, so it's only done if the
+		// local side wins
+		if policy == ConflictPolicySandbox || (change.Delete && policy == ConflictPolicyKeepBoth) {
+			result.Status = ChangeStatusConflict
+			return result
+		}
+
+		if policy == ConflictPolicyKeepBoth && current != "" {
+			conflictPath := conflictName(change.Path, time.Now())
+			conflictTarget, err := filePath(rootPath, conflictPath)
+			if err != nil {
+				return failed(result, err)
+			}
+			if err := os.Rename(target, conflictTarget); err != nil {
+				return failed(result, err)
+			}
+			result.ConflictPath = conflictPath
+		}
+	}
+
+	if change.Delete {
+		if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+			return failed(result, err)
+		}
+	} else if err := s.writeFile(target, change); err != nil {
+		return failed(result, err)
+	}
+
+	result.Status = ChangeStatusApplied
+	return result
+}
+
+// writeFile assembles a file from its chunks and replaces the file at target with it
+func (s *Syncer) writeFile(target string, change Change) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".sync-")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	hash, err := s.writeChunks(tmp, change.Chunks)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && hash != change.Hash {
+		err = fmt.Errorf("content hash %s doesn't match %s", hash, change.Hash)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	mode := change.Mode.Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if !change.ModTime.IsZero() {
+		if err := os.Chtimes(tmpPath, change.ModTime, change.ModTime); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+
+	if err := os.Rename(tmpPath, target); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// isIgnored returns whether a path or any directory it's in is ignored
+func isIgnored(matcher *IgnoreMatcher, relPath string) bool {
+	if matcher.Match(relPath, false) {
+		return true
+	}
+	for dir := path.Dir(relPath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if matcher.Match(dir, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// conflictName returns the name the sandbox version of a conflicting file is kept under, e.g.
+// main.sync-conflict-20250102-150405.go for main.go
+func conflictName(relPath string, now time.Time) string {
+	ext := path.Ext(relPath)
+	if ext == path.Base(relPath) {
+		ext = ""
+	}
+	base := strings.TrimSuffix(relPath, ext)
+	return fmt.Sprintf("%s.sync-conflict-%s%s", base, now.UTC().Format("20060102-150405"), ext)
+}
+
+func failed(result ChangeResult, err error) ChangeResult {
+	result.Status = ChangeStatusFailed
+	result.Error = err.Error()
+	return result
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filesync
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// Uploaded chunks not used by an apply within this time are removed
+const chunkRetention = 24 * time.Hour
+
+var (
+	ErrChunkNotFound = errors.New("chunk not found")
+	ErrInvalidChunk  = errors.New("invalid chunk")
+	ErrInvalidPath   = errors.New("invalid path")
+)
+
+var chunkHashRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// hashFile returns the hash and chunk hashes of a file, from the cache if it didn't change
+func (s *Syncer) hashFile(path string, info os.FileInfo) (cachedFile, error) {
+	s.mu.Lock()
+	cached, ok := s.files[path]
+	s.mu.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return cachedFile{}, err
+	}
+	defer file.Close()
+
+	cached = cachedFile{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		chunks:  []string{},
+	}
+	locations := map[string]chunkLocation{}
+
+	fileHash := sha256.New()
+	buf := make([]byte, ChunkSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(file, buf)
+		if n > 0 {
+			fileHash.Write(buf[:n])
+			sum := sha256.Sum256(buf[:n])
+			chunkHash := hex.EncodeToString(sum[:])
+			cached.chunks = append(cached.chunks, chunkHash)
+			locations[chunkHash] = chunkLocation{path: path, offset: offset, size: int64(n)}
+			offset += int64(n)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return cachedFile{}, err
+		}
+	}
+	cached.hash = hex.EncodeToString(fileHash.Sum(nil))
+
+	s.mu.Lock()
+	s.files[path] = cached
+	for chunkHash, location := range locations {
+		s.chunks[chunkHash] = location
+	}
+	s.mu.Unlock()
+
+	return cached, nil
+}
+
+// currentHash returns the hash of a file, empty if it doesn't exist
+func (s *Syncer) currentHash(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", path)
+	}
+
+	cached, err := s.hashFile(path, info)
+	if err != nil {
+		return "", err
+	}
+	return cached.hash, nil
+}
+
+// MissingChunks returns the chunks of a list that the sandbox has neither in a synced file nor
+// uploaded
+func (s *Syncer) MissingChunks(hashes []string) []string {
+	missing := []string{}
+	for _, hash := range hashes {
+		if _, err := s.ReadChunk(hash); err != nil {
+			missing = append(missing, hash)
+		}
+	}
+	return missing
+}
+
+// PutChunk stores an uploaded chunk until an apply uses it
+func (s *Syncer) PutChunk(hash string, reader io.Reader) error {
+	if !chunkHashRegex.MatchString(hash) {
+		return fmt.Errorf("%w: malformed hash %s", ErrInvalidChunk, hash)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, ChunkSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > ChunkSize {
+		return fmt.Errorf("%w: chunks are at most %d bytes", ErrInvalidChunk, ChunkSize)
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != hash {
+		return fmt.Errorf("%w: content doesn't match hash %s", ErrInvalidChunk, hash)
+	}
+
+	if err := os.MkdirAll(s.chunksDir, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.chunksDir, ".upload-")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(s.chunksDir, hash))
+}
+
+// ReadChunk returns a chunk, uploaded or from a file of the sandbox. Chunks of files are checked
+// against their hash, since the files may have changed since they were hashed.
+func (s *Syncer) ReadChunk(hash string) ([]byte, error) {
+	if !chunkHashRegex.MatchString(hash) {
+		return nil, fmt.Errorf("%w: malformed hash %s", ErrInvalidChunk, hash)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(s.chunksDir, hash)); err == nil {
+		return data, nil
+	}
+
+	s.mu.Lock()
+	location, ok := s.chunks[hash]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrChunkNotFound
+	}
+
+	file, err := os.Open(location.path)
+	if err != nil {
+		return nil, ErrChunkNotFound
+	}
+	defer file.Close()
+
+	data := make([]byte, location.size)
+	if _, err := file.ReadAt(data, location.offset); err != nil {
+		return nil, ErrChunkNotFound
+	}
+
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != hash {
+		s.mu.Lock()
+		delete(s.chunks, hash)
+		s.mu.Unlock()
+		return nil, ErrChunkNotFound
+	}
+
+	return data, nil
+}
+
+// removeChunks removes uploaded chunks once they are used, and the ones left behind by syncs
+// that were never applied
+func (s *Syncer) removeChunks(used map[string]bool) {
+	entries, err := os.ReadDir(s.chunksDir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if used[entry.Name()] || time.Since(info.ModTime()) > chunkRetention {
+			os.Remove(filepath.Join(s.chunksDir, entry.Name()))
+		}
+	}
+}
+
+// writeChunks assembles a file from its chunks into a writer and returns the hash of the content
+func (s *Syncer) writeChunks(w io.Writer, chunks []string) (string, error) {
+	fileHash := sha256.New()
+	for _, chunkHash := range chunks {
+		data, err := s.ReadChunk(chunkHash)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", err, chunkHash)
+		}
+		if _, err := io.Copy(io.MultiWriter(w, fileHash), bytes.NewReader(data)); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(fileHash.Sum(nil)), nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filesync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Files are split into chunks of this size, chunks the other side already has are not transferred
+const ChunkSize = 256 * 1024
+
+type FileState struct {
+	// Path relative to the sync root, with forward slashes
+	Path    string      `json:"path"`
+	Size    int64       `json:"size"`
+	ModTime time.Time   `json:"modTime"`
+	Mode    os.FileMode `json:"mode"`
+	// SHA-256 of the content
+	Hash string `json:"hash"`
+	// SHA-256 of each chunk of the content, in order
+	Chunks []string `json:"chunks"`
+}
+
+type Manifest struct {
+	Root  string      `json:"root"`
+	Files []FileState `json:"files"`
+	// Changes whenever a file is added, removed or modified
+	Digest string `json:"digest"`
+}
+
+type cachedFile struct {
+	size    int64
+	modTime time.Time
+	hash    string
+	chunks  []string
+}
+
+type chunkLocation struct {
+	path   string
+	offset int64
+	size   int64
+}
+
+// Syncer is the sandbox side of the sync of a local directory with a directory of the sandbox.
+// The client compares the manifests of both sides with the state of the last sync, transfers
+// the chunks the other side is missing and applies the changes. Conflicts are detected on apply,
+// from the hash each change expects the file in the sandbox to have.
+type Syncer struct {
+	workDir   string
+	chunksDir string
+
+	// Serializes applies, so that conflicts are checked against the files they replace
+	applyMu sync.Mutex
+	mu      sync.Mutex
+	// Hashes of files by absolute path, valid as long as their size and modification time match
+	files map[string]cachedFile
+	// Where the chunks of the files hashed so far are
+	chunks map[string]chunkLocation
+}
+
+func NewSyncer(workDir, dir string) *Syncer {
+	return &Syncer{
+		workDir:   workDir,
+		chunksDir: filepath.Join(dir, "chunks"),
+		files:     map[string]cachedFile{},
+		chunks:    map[string]chunkLocation{},
+	}
+}
+
+// GetManifest hashes the files under a root, reusing the hashes of files that didn't change
+func (s *Syncer) GetManifest(root string, ignore []string) (*Manifest, error) {
+	rootPath := s.rootPath(root)
+
+	matcher, err := NewIgnoreMatcher(ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Root:  rootPath,
+		Files: []FileState{},
+	}
+
+	err = filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == rootPath {
+				return err
+			}
+			return nil
+		}
+		if path == rootPath {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if matcher.Match(relPath, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		cached, err := s.hashFile(path, info)
+		if err != nil {
+			// Files removed or made unreadable while walking are left out
+			return nil
+		}
+
+		manifest.Files = append(manifest.Files, FileState{
+			Path:    relPath,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Mode:    info.Mode().Perm(),
+			Hash:    cached.hash,
+			Chunks:  cached.chunks,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+
+	digest := sha256.New()
+	for _, file := range manifest.Files {
+		fmt.Fprintf(digest, "%s\x00%s\x00%o\n", file.Path, file.Hash, file.Mode)
+	}
+	manifest.Digest = hex.EncodeToString(digest.Sum(nil))
+
+	return manifest, nil
+}
+
+// Watch waits until the manifest digest of a root differs from the given one, polling it at the
+// interval, and returns the new manifest. It returns nil if nothing changed before the context
+// is done.
+func (s *Syncer) Watch(ctx context.Context, root string, ignore []string, digest string, interval time.Duration) (*Manifest, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		manifest, err := s.GetManifest(root, ignore)
+		if err != nil {
+			return nil, err
+		}
+		if manifest.Digest != digest {
+			return manifest, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rootPath resolves a sync root, absolute or relative to the working directory
+func (s *Syncer) rootPath(root string) string {
+	if root == "" {
+		return s.workDir
+	}
+	if !filepath.IsAbs(root) {
+		root = filepath.Join(s.workDir, root)
+	}
+	return filepath.Clean(root)
+}
+
+// filePath resolves the path of a file relative to a sync root, rejecting paths outside of it
+func filePath(rootPath, relPath string) (string, error) {
+	path := filepath.Join(rootPath, filepath.FromSlash(relPath))
+	rel, err := filepath.Rel(rootPath, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPath, relPath)
+	}
+	return path, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filesync
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+type ignorePattern struct {
+	pattern string
+	// Matched against the whole relative path instead of each name in it
+	anchored bool
+	dirOnly  bool
+}
+
+// IgnoreMatcher matches relative paths against gitignore style patterns. A pattern without a slash
+// matches a name at any depth, e.g. node_modules or *.log, one with a slash matches the path from
+// the root, e.g. /build or docs/generated. A trailing slash only matches directories. Files in an
+// ignored directory are ignored too.
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+func NewIgnoreMatcher(patterns []string) (*IgnoreMatcher, error) {
+	matcher := &IgnoreMatcher{}
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		p := ignorePattern{}
+		if strings.HasSuffix(pattern, "/") {
+			p.dirOnly = true
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		if strings.Contains(pattern, "/") {
+			p.anchored = true
+			pattern = strings.TrimPrefix(pattern, "/")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+		p.pattern = pattern
+
+		matcher.patterns = append(matcher.patterns, p)
+	}
+
+	return matcher, nil
+}
+
+// Match returns whether a path relative to the sync root, with forward slashes, is ignored
+func (m *IgnoreMatcher) Match(relPath string, isDir bool) bool {
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+
+		if p.anchored {
+			if matched, _ := path.Match(p.pattern, relPath); matched {
+				return true
+			}
+			continue
+		}
+
+		if matched, _ := path.Match(p.pattern, path.Base(relPath)); matched {
+			return true
+		}
+	}
+
+	return false
+}
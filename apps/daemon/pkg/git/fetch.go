@@ -0,0 +1,24 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"github.com/go-git/go-git/v5/plumbing/transport"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func (s *Service) Fetch(auth transport.AuthMethod) error {
+	repo, err := git.PlainOpen(s.WorkDir)
+	if err != nil {
+		return err
+	}
+
+	options := &git.FetchOptions{
+		RemoteName: "origin",
+		Auth:       auth,
+	}
+
+	return repo.Fetch(options)
+}
@@ -4,12 +4,12 @@
 package git
 
 import (
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 
 	"github.com/go-git/go-git/v5"
 )
 
-func (s *Service) Pull(auth *http.BasicAuth) error {
+func (s *Service) Pull(auth transport.AuthMethod) error {
 	repo, err := git.PlainOpen(s.WorkDir)
 	if err != nil {
 		return err
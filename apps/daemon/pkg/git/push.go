@@ -7,12 +7,12 @@ import (
 	"fmt"
 
 	"github.com/go-git/go-git/v5/config"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 
 	"github.com/go-git/go-git/v5"
 )
 
-func (s *Service) Push(auth *http.BasicAuth) error {
+func (s *Service) Push(auth transport.AuthMethod) error {
 	repo, err := git.PlainOpen(s.WorkDir)
 	if err != nil {
 		return err
@@ -10,6 +10,7 @@ import (
 
 	"github.com/daytonaio/daemon/pkg/gitprovider"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
@@ -54,7 +55,7 @@ var MapStatus map[git.StatusCode]Status = map[git.StatusCode]Status{
 }
 
 type IGitService interface {
-	CloneRepository(repo *gitprovider.GitRepository, auth *http.BasicAuth) error
+	CloneRepository(repo *gitprovider.GitRepository, auth transport.AuthMethod) error
 	CloneRepositoryCmd(repo *gitprovider.GitRepository, auth *http.BasicAuth) []string
 	RepositoryExists() (bool, error)
 	SetGitConfig(userData *gitprovider.GitUser, providerConfig *gitprovider.GitProviderConfig) error
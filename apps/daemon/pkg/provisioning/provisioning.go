@@ -0,0 +1,286 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/env"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SpecPath is where the runner writes the provisioning steps of a sandbox before it first starts
+const SpecPath = "/.daytona-provisioning.json"
+
+// Output kept per step, the end of it
+const maxOutputSize = 16 * 1024
+
+type State string
+
+const (
+	StatePending   State = "pending"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+)
+
+type Step struct {
+	Name   string `json:"name"`
+	Script string `json:"script"`
+	// Working directory, the daemon working directory if empty
+	Cwd               string            `json:"cwd,omitempty"`
+	Envs              map[string]string `json:"envs,omitempty"`
+	Retries           int               `json:"retries,omitempty"`
+	RetryDelaySeconds int               `json:"retryDelaySeconds,omitempty"`
+	// Attempts running longer are killed, no timeout if 0
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+type Spec struct {
+	Steps []Step `json:"steps"`
+}
+
+type StepStatus struct {
+	Name       string     `json:"name"`
+	State      State      `json:"state"`
+	Attempts   int        `json:"attempts"`
+	ExitCode   *int       `json:"exitCode,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Output     string     `json:"output,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+type Status struct {
+	State State        `json:"state"`
+	Steps []StepStatus `json:"steps"`
+}
+
+// Provisioner runs the provisioning steps of a sandbox once, in order, on its first boot. Its
+// status is stored in the config dir, so a daemon restarted while provisioning resumes from the
+// first step that didn't succeed, and a completed or failed provisioning is not run again.
+type Provisioner struct {
+	specPath   string
+	statusPath string
+	mu         sync.Mutex
+	spec       *Spec
+	status     *Status
+}
+
+func NewProvisioner(specPath, configDir string) *Provisioner {
+	return &Provisioner{
+		specPath:   specPath,
+		statusPath: filepath.Join(configDir, "provisioning.json"),
+	}
+}
+
+// Start runs the pending provisioning steps in the background. It returns false if the sandbox
+// has no provisioning steps.
+func (p *Provisioner) Start() bool {
+	data, err := os.ReadFile(p.specPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Errorf("Failed to read provisioning steps: %v", err)
+		}
+		return false
+	}
+
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		log.Errorf("Invalid provisioning steps: %v", err)
+		return false
+	}
+
+	p.mu.Lock()
+	p.spec = &spec
+	p.status = p.loadStatus(spec)
+	done := p.status.State == StateSucceeded || p.status.State == StateFailed
+	p.mu.Unlock()
+
+	if !done {
+		go p.run()
+	}
+
+	return true
+}
+
+// Status returns the provisioning status, nil if the sandbox has no provisioning steps
+func (p *Provisioner) Status() *Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.status == nil {
+		return nil
+	}
+
+	status := *p.status
+	status.Steps = append([]StepStatus{}, p.status.Steps...)
+	return &status
+}
+
+func (p *Provisioner) loadStatus(spec Spec) *Status {
+	status := &Status{State: StatePending}
+	for _, step := range spec.Steps {
+		status.Steps = append(status.Steps, StepStatus{Name: step.Name, State: StatePending})
+	}
+
+	data, err := os.ReadFile(p.statusPath)
+	if err != nil {
+		return status
+	}
+
+	var saved Status
+	if err := json.Unmarshal(data, &saved); err != nil || len(saved.Steps) != len(status.Steps) {
+		log.Warnf("Ignoring provisioning status that doesn't match the steps")
+		return status
+	}
+
+	// Steps interrupted by a restart are run again
+	for i := range saved.Steps {
+		if saved.Steps[i].State == StateRunning {
+			saved.Steps[i].State = StatePending
+		}
+	}
+	if saved.State == StateRunning {
+		saved.State = StatePending
+	}
+
+	return &saved
+}
+
+func (p *Provisioner) run() {
+	p.update(func(status *Status) {
+		status.State = StateRunning
+	})
+
+	for i, step := range p.spec.Steps {
+		if p.Status().Steps[i].State == StateSucceeded {
+			continue
+		}
+
+		if err := p.runStep(i, step); err != nil {
+			log.Errorf("Provisioning step %s failed: %v", step.Name, err)
+			p.update(func(status *Status) {
+				status.State = StateFailed
+			})
+			return
+		}
+	}
+
+	p.update(func(status *Status) {
+		status.State = StateSucceeded
+	})
+	log.Info("Provisioning completed")
+}
+
+func (p *Provisioner) runStep(index int, step Step) error {
+	now := time.Now()
+	p.update(func(status *Status) {
+		status.Steps[index].State = StateRunning
+		status.Steps[index].StartedAt = &now
+	})
+
+	var err error
+	for attempt := 0; attempt <= step.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(step.RetryDelaySeconds) * time.Second)
+		}
+
+		var exitCode int
+		var output []byte
+		exitCode, output, err = p.runAttempt(step)
+
+		p.update(func(status *Status) {
+			status.Steps[index].Attempts++
+			status.Steps[index].ExitCode = &exitCode
+			status.Steps[index].Output = tail(output)
+			status.Steps[index].Error = ""
+			if err != nil {
+				status.Steps[index].Error = err.Error()
+			}
+		})
+
+		if err == nil {
+			break
+		}
+		log.Warnf("Provisioning step %s attempt %d failed: %v", step.Name, attempt+1, err)
+	}
+
+	finished := time.Now()
+	p.update(func(status *Status) {
+		status.Steps[index].FinishedAt = &finished
+		status.Steps[index].State = StateSucceeded
+		if err != nil {
+			status.Steps[index].State = StateFailed
+		}
+	})
+
+	return err
+}
+
+func (p *Provisioner) runAttempt(step Step) (int, []byte, error) {
+	ctx := context.Background()
+	if step.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(step.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, common.GetShell(), "-c", step.Script)
+	cmd.Dir = step.Cwd
+	cmd.Env = env.Merge(env.Environ(), step.Envs)
+	// Kill the whole process group of the step on timeout
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+
+	output, err := cmd.CombinedOutput()
+	exitCode := cmd.ProcessState.ExitCode()
+
+	if ctx.Err() != nil {
+		return exitCode, output, fmt.Errorf("timed out after %d seconds", step.TimeoutSeconds)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitCode, output, fmt.Errorf("exited with code %d", exitCode)
+	}
+
+	return exitCode, output, err
+}
+
+// update changes the status and persists it
+func (p *Provisioner) update(change func(status *Status)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	change(p.status)
+
+	data, err := json.Marshal(p.status)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(p.statusPath, data, 0600); err != nil {
+		log.Errorf("Failed to save provisioning status: %v", err)
+	}
+}
+
+func tail(output []byte) string {
+	if len(output) > maxOutputSize {
+		output = output[len(output)-maxOutputSize:]
+	}
+	return string(output)
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{expr: "* * * *", err: "cron expression must have 5 fields, got 4"},
+		{expr: "* * * * * *", err: "cron expression must have 5 fields, got 6"},
+		{expr: "60 * * * *", err: "invalid minute: value 60 out of range 0-59"},
+		{expr: "* 24 * * *", err: "invalid hour"},
+		{expr: "* * 0 * *", err: "invalid day of month"},
+		{expr: "* * * foo *", err: "invalid month: invalid value \"foo\""},
+		{expr: "* * * * 8", err: "invalid day of week"},
+		{expr: "*/0 * * * *", err: "invalid minute: invalid step \"0\""},
+		{expr: "5-1 * * * *", err: "invalid minute: invalid range \"5-1\""},
+		{expr: "@reboot", err: "cron expression must have 5 fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := parseCron(tt.expr)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	at := func(value string) time.Time {
+		t, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		from     string
+		expected string
+	}{
+		{name: "every 15 minutes", expr: "*/15 * * * *", from: "2025-01-01 10:07", expected: "2025-01-01 10:15"},
+		{name: "step from a start value", expr: "5/20 * * * *", from: "2025-01-01 10:06", expected: "2025-01-01 10:25"},
+		{name: "strictly after the current minute", expr: "@daily", from: "2025-01-01 00:00", expected: "2025-01-02 00:00"},
+		{name: "weekdays skip the weekend", expr: "0 9 * * mon-fri", from: "2025-01-03 10:00", expected: "2025-01-06 09:00"},
+		{name: "7 is Sunday", expr: "0 12 * * 7", from: "2025-01-01 00:00", expected: "2025-01-05 12:00"},
+		{name: "lists", expr: "0,30 8,20 * * *", from: "2025-01-01 08:30", expected: "2025-01-01 20:00"},
+		{name: "month names roll over the year", expr: "0 0 1 jan *", from: "2025-03-01 00:00", expected: "2026-01-01 00:00"},
+		{name: "day of month or day of week", expr: "0 0 13 * fri", from: "2025-01-01 00:00", expected: "2025-01-03 00:00"},
+		{name: "restricted day of month only", expr: "0 0 13 * *", from: "2025-01-01 00:00", expected: "2025-01-13 00:00"},
+		{name: "leap day", expr: "0 0 29 2 *", from: "2025-01-01 00:00", expected: "2028-02-29 00:00"},
+		{name: "no matching day", expr: "0 0 30 2 *", from: "2025-01-01 00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCron(tt.expr)
+			require.NoError(t, err)
+
+			next := schedule.next(at(tt.from))
+			if tt.expected == "" {
+				assert.True(t, next.IsZero(), "expected no next run, got %s", next)
+				return
+			}
+
+			assert.Equal(t, at(tt.expected), next)
+			assert.True(t, schedule.matches(next))
+		})
+	}
+}
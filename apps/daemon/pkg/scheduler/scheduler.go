@@ -0,0 +1,442 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/session"
+	"github.com/google/uuid"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+	// The run was due while the previous run was still running
+	RunStatusSkipped = "skipped"
+)
+
+// Number of runs kept per schedule
+const maxRuns = 50
+
+type Schedule struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	Cron    string `json:"cron"`
+	Command string `json:"command"`
+	Enabled bool   `json:"enabled"`
+	// Timeout of a run in seconds, 0 means no timeout
+	Timeout   uint32    `json:"timeout,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Runs      []Run     `json:"runs"`
+}
+
+type Run struct {
+	Id          string     `json:"id"`
+	Status      string     `json:"status"`
+	ScheduledAt time.Time  `json:"scheduledAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	EndedAt     *time.Time `json:"endedAt,omitempty"`
+	SessionId   string     `json:"sessionId,omitempty"`
+	CommandId   string     `json:"commandId,omitempty"`
+	ExitCode    *int       `json:"exitCode,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+type ScheduleParams struct {
+	Name    string
+	Cron    string
+	Command string
+	Enabled bool
+	Timeout uint32
+}
+
+// Scheduler runs commands on cron schedules. Each schedule runs in its own session, which
+// is created when needed. Schedules and their recent runs are stored in the config dir.
+type Scheduler struct {
+	configDir      string
+	sessionService *session.SessionService
+
+	mu        sync.Mutex
+	schedules map[string]*Schedule
+	running   map[string]bool
+}
+
+func NewScheduler(configDir string, sessionService *session.SessionService) *Scheduler {
+	s := &Scheduler{
+		configDir:      configDir,
+		sessionService: sessionService,
+		schedules:      map[string]*Schedule{},
+		running:        map[string]bool{},
+	}
+
+	if err := s.load(); err != nil {
+		log.Errorf("Failed to load schedules: %v", err)
+	}
+
+	go s.loop()
+
+	return s
+}
+
+func (s *Scheduler) Create(params ScheduleParams) (*Schedule, error) {
+	if err := validateParams(params); err != nil {
+		return nil, common_errors.NewBadRequestError(err)
+	}
+
+	schedule := &Schedule{
+		Id:        uuid.NewString(),
+		Name:      params.Name,
+		Cron:      params.Cron,
+		Command:   params.Command,
+		Enabled:   params.Enabled,
+		Timeout:   params.Timeout,
+		CreatedAt: time.Now(),
+		Runs:      []Run{},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schedules[schedule.Id] = schedule
+	if err := s.save(); err != nil {
+		delete(s.schedules, schedule.Id)
+		return nil, err
+	}
+
+	copied := *schedule
+	return &copied, nil
+}
+
+func (s *Scheduler) Update(id string, params ScheduleParams) (*Schedule, error) {
+	if err := validateParams(params); err != nil {
+		return nil, common_errors.NewBadRequestError(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return nil, common_errors.NewNotFoundError(errors.New("schedule not found"))
+	}
+
+	schedule.Name = params.Name
+	schedule.Cron = params.Cron
+	schedule.Command = params.Command
+	schedule.Enabled = params.Enabled
+	schedule.Timeout = params.Timeout
+
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+
+	copied := *schedule
+	return &copied, nil
+}
+
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.schedules[id]; !ok {
+		return common_errors.NewNotFoundError(errors.New("schedule not found"))
+	}
+
+	delete(s.schedules, id)
+	if err := s.save(); err != nil {
+		return err
+	}
+
+	// Remove the session of the schedule, which also stops a run in progress
+	go func() {
+		if _, err := s.sessionService.Get(sessionId(id)); err != nil {
+			return
+		}
+		if err := s.sessionService.Delete(context.Background(), sessionId(id)); err != nil {
+			log.Warnf("Failed to delete session of schedule %s: %v", id, err)
+		}
+	}()
+
+	return nil
+}
+
+func (s *Scheduler) Get(id string) (*Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return nil, common_errors.NewNotFoundError(errors.New("schedule not found"))
+	}
+
+	copied := *schedule
+	copied.Runs = append([]Run{}, schedule.Runs...)
+	return &copied, nil
+}
+
+func (s *Scheduler) List() []*Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedules := make([]*Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		copied := *schedule
+		copied.Runs = append([]Run{}, schedule.Runs...)
+		schedules = append(schedules, &copied)
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+	})
+
+	return schedules
+}
+
+// NextRun returns when the schedule runs next, nil if it is disabled or never runs
+func (s *Schedule) NextRun() *time.Time {
+	if !s.Enabled {
+		return nil
+	}
+
+	cron, err := parseCron(s.Cron)
+	if err != nil {
+		return nil
+	}
+
+	next := cron.next(time.Now())
+	if next.IsZero() {
+		return nil
+	}
+
+	return &next
+}
+
+// Trigger runs a schedule now, regardless of its cron expression and whether it is enabled
+func (s *Scheduler) Trigger(id string) (*Run, error) {
+	s.mu.Lock()
+	schedule, ok := s.schedules[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, common_errors.NewNotFoundError(errors.New("schedule not found"))
+	}
+
+	run := s.startRun(schedule, time.Now())
+	if run.Status == RunStatusSkipped {
+		return nil, common_errors.NewConflictError(errors.New("schedule is already running"))
+	}
+
+	return run, nil
+}
+
+func (s *Scheduler) loop() {
+	for {
+		now := time.Now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		time.Sleep(next.Sub(now))
+
+		s.mu.Lock()
+		due := []*Schedule{}
+		for _, schedule := range s.schedules {
+			if !schedule.Enabled {
+				continue
+			}
+
+			cron, err := parseCron(schedule.Cron)
+			if err != nil {
+				continue
+			}
+			if cron.matches(next) {
+				due = append(due, schedule)
+			}
+		}
+		s.mu.Unlock()
+
+		for _, schedule := range due {
+			s.startRun(schedule, next)
+		}
+	}
+}
+
+// startRun starts a run of the schedule in the background and returns it
+func (s *Scheduler) startRun(schedule *Schedule, scheduledAt time.Time) *Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := Run{
+		Id:          uuid.NewString(),
+		ScheduledAt: scheduledAt,
+		Status:      RunStatusRunning,
+	}
+
+	if s.running[schedule.Id] {
+		run.Status = RunStatusSkipped
+		s.addRun(schedule, run)
+		return &run
+	}
+
+	now := time.Now()
+	run.StartedAt = &now
+	run.SessionId = sessionId(schedule.Id)
+
+	s.running[schedule.Id] = true
+	s.addRun(schedule, run)
+
+	go s.execute(schedule, run)
+
+	return &run
+}
+
+func (s *Scheduler) execute(schedule *Schedule, run Run) {
+	s.mu.Lock()
+	command, timeout := schedule.Command, schedule.Timeout
+	s.mu.Unlock()
+
+	result, err := s.runCommand(run.SessionId, command, timeout)
+
+	now := time.Now()
+	run.EndedAt = &now
+	run.Status = RunStatusFailed
+	if err != nil {
+		run.Error = err.Error()
+		log.Warnf("Scheduled run of %s failed: %v", schedule.Id, err)
+	} else {
+		run.CommandId = result.CommandId
+		run.ExitCode = result.ExitCode
+		if result.ExitCode != nil && *result.ExitCode == 0 {
+			run.Status = RunStatusSucceeded
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.running, schedule.Id)
+	for i := range schedule.Runs {
+		if schedule.Runs[i].Id == run.Id {
+			schedule.Runs[i] = run
+		}
+	}
+
+	if _, ok := s.schedules[schedule.Id]; ok {
+		if err := s.save(); err != nil {
+			log.Errorf("Failed to save schedules: %v", err)
+		}
+	}
+}
+
+func (s *Scheduler) runCommand(sessionId, command string, timeout uint32) (*session.SessionExecute, error) {
+	if _, err := s.sessionService.Get(sessionId); err != nil {
+		err = s.sessionService.Create(sessionId, session.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create session: %w", err)
+		}
+	}
+
+	limits := session.CommandLimits{
+		Timeout: time.Duration(timeout) * time.Second,
+	}
+
+	return s.sessionService.Execute(sessionId, command, false, true, limits)
+}
+
+func (s *Scheduler) addRun(schedule *Schedule, run Run) {
+	schedule.Runs = append(schedule.Runs, run)
+	if len(schedule.Runs) > maxRuns {
+		schedule.Runs = schedule.Runs[len(schedule.Runs)-maxRuns:]
+	}
+
+	if err := s.save(); err != nil {
+		log.Errorf("Failed to save schedules: %v", err)
+	}
+}
+
+func sessionId(scheduleId string) string {
+	return "schedule-" + scheduleId
+}
+
+func validateParams(params ScheduleParams) error {
+	if strings.TrimSpace(params.Command) == "" {
+		return errors.New("command is required")
+	}
+
+	if _, err := parseCron(params.Cron); err != nil {
+		return fmt.Errorf("invalid cron expression: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Scheduler) statePath() string {
+	return filepath.Join(s.configDir, "schedules.json")
+}
+
+func (s *Scheduler) load() error {
+	content, err := os.ReadFile(s.statePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var schedules []*Schedule
+	if err := json.Unmarshal(content, &schedules); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", s.statePath(), err)
+	}
+
+	for _, schedule := range schedules {
+		// Runs interrupted by a daemon restart won't finish
+		for i := range schedule.Runs {
+			if schedule.Runs[i].Status == RunStatusRunning {
+				schedule.Runs[i].Status = RunStatusFailed
+				schedule.Runs[i].Error = "interrupted by daemon restart"
+			}
+		}
+		s.schedules[schedule.Id] = schedule
+	}
+
+	return nil
+}
+
+// save writes the schedules to disk, the caller must hold the lock
+func (s *Scheduler) save() error {
+	schedules := make([]*Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, schedule)
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+	})
+
+	content, err := json.MarshalIndent(schedules, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.configDir, 0755); err != nil {
+		return err
+	}
+
+	tmpPath := s.statePath() + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, s.statePath())
+}
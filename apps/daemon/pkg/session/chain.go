@@ -0,0 +1,246 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ChainStatusRunning   = "running"
+	ChainStatusSucceeded = "succeeded"
+	ChainStatusFailed    = "failed"
+)
+
+const (
+	StepStatusPending   = "pending"
+	StepStatusRunning   = "running"
+	StepStatusSucceeded = "succeeded"
+	StepStatusFailed    = "failed"
+	StepStatusSkipped   = "skipped"
+)
+
+const (
+	// Run the step only if all its dependencies succeeded
+	StepConditionSuccess = "success"
+	// Run the step once its dependencies finished, whatever their result
+	StepConditionAlways = "always"
+)
+
+type ChainStep struct {
+	// Unique name of the step within the chain
+	Name    string
+	Command string
+	// Names of earlier steps the step depends on. When nil, the step depends on the previous step.
+	DependsOn []string
+	// StepConditionSuccess (default) or StepConditionAlways
+	Condition string
+	Limits    CommandLimits
+}
+
+type ChainStepStatus struct {
+	Name      string     `json:"name" validate:"required"`
+	Command   string     `json:"command" validate:"required"`
+	DependsOn []string   `json:"dependsOn" validate:"required"`
+	Status    string     `json:"status" validate:"required"`
+	CommandId string     `json:"commandId,omitempty" validate:"optional"`
+	ExitCode  *int       `json:"exitCode,omitempty" validate:"optional"`
+	StartedAt *time.Time `json:"startedAt,omitempty" validate:"optional"`
+	EndedAt   *time.Time `json:"endedAt,omitempty" validate:"optional"`
+}
+
+type Chain struct {
+	Id        string             `json:"id" validate:"required"`
+	SessionId string             `json:"sessionId" validate:"required"`
+	Status    string             `json:"status" validate:"required"`
+	Steps     []*ChainStepStatus `json:"steps" validate:"required"`
+}
+
+type chain struct {
+	mu    sync.Mutex
+	chain Chain
+	steps []ChainStep
+}
+
+func (c *chain) snapshot() *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := c.chain
+	snapshot.Steps = make([]*ChainStepStatus, 0, len(c.chain.Steps))
+	for _, step := range c.chain.Steps {
+		copied := *step
+		snapshot.Steps = append(snapshot.Steps, &copied)
+	}
+
+	return &snapshot
+}
+
+// CreateChain starts running the steps one after another in the session. Since a session has
+// a single shell, steps never run in parallel; dependencies decide which of them run at all.
+func (s *SessionService) CreateChain(sessionId string, steps []ChainStep) (*Chain, error) {
+	if _, ok := s.sessions.Get(sessionId); !ok {
+		return nil, common_errors.NewNotFoundError(errors.New("session not found"))
+	}
+
+	if err := validateChainSteps(steps); err != nil {
+		return nil, common_errors.NewBadRequestError(err)
+	}
+
+	c := &chain{
+		chain: Chain{
+			Id:        uuid.NewString(),
+			SessionId: sessionId,
+			Status:    ChainStatusRunning,
+		},
+		steps: steps,
+	}
+
+	for i, step := range steps {
+		dependsOn := step.DependsOn
+		if dependsOn == nil {
+			dependsOn = []string{}
+			if i > 0 {
+				dependsOn = []string{steps[i-1].Name}
+			}
+		}
+		c.steps[i].DependsOn = dependsOn
+
+		c.chain.Steps = append(c.chain.Steps, &ChainStepStatus{
+			Name:      step.Name,
+			Command:   step.Command,
+			DependsOn: dependsOn,
+			Status:    StepStatusPending,
+		})
+	}
+
+	s.chains.Set(c.chain.Id, c)
+	go s.runChain(c)
+
+	return c.snapshot(), nil
+}
+
+func (s *SessionService) GetChain(sessionId, chainId string) (*Chain, error) {
+	c, ok := s.chains.Get(chainId)
+	if !ok || c.chain.SessionId != sessionId {
+		return nil, common_errors.NewNotFoundError(errors.New("chain not found"))
+	}
+
+	return c.snapshot(), nil
+}
+
+func (s *SessionService) ListChains(sessionId string) []*Chain {
+	chains := []*Chain{}
+	for _, c := range s.chains.Items() {
+		if c.chain.SessionId == sessionId {
+			chains = append(chains, c.snapshot())
+		}
+	}
+
+	return chains
+}
+
+func (s *SessionService) runChain(c *chain) {
+	results := map[string]string{}
+	failed := false
+
+	for i, step := range c.steps {
+		status := c.chain.Steps[i]
+
+		run := true
+		for _, dependency := range step.DependsOn {
+			if step.Condition != StepConditionAlways && results[dependency] != StepStatusSucceeded {
+				run = false
+			}
+		}
+
+		if !run {
+			c.mu.Lock()
+			status.Status = StepStatusSkipped
+			c.mu.Unlock()
+			results[step.Name] = StepStatusSkipped
+			continue
+		}
+
+		now := time.Now()
+		c.mu.Lock()
+		status.Status = StepStatusRunning
+		status.StartedAt = &now
+		c.mu.Unlock()
+
+		result, err := s.Execute(c.chain.SessionId, step.Command, false, true, step.Limits)
+
+		now = time.Now()
+		c.mu.Lock()
+		status.EndedAt = &now
+		status.Status = StepStatusFailed
+		if err != nil {
+			log.Debugf("Step %s of chain %s failed: %v", step.Name, c.chain.Id, err)
+		} else {
+			status.CommandId = result.CommandId
+			status.ExitCode = result.ExitCode
+			if result.ExitCode != nil && *result.ExitCode == 0 {
+				status.Status = StepStatusSucceeded
+			}
+		}
+		c.mu.Unlock()
+
+		results[step.Name] = status.Status
+		if status.Status == StepStatusFailed {
+			failed = true
+		}
+	}
+
+	c.mu.Lock()
+	c.chain.Status = ChainStatusSucceeded
+	if failed {
+		c.chain.Status = ChainStatusFailed
+	}
+	c.mu.Unlock()
+}
+
+func validateChainSteps(steps []ChainStep) error {
+	if len(steps) == 0 {
+		return errors.New("chain must have at least one step")
+	}
+
+	names := []string{}
+	for _, step := range steps {
+		if step.Name == "" {
+			return errors.New("step name is required")
+		}
+		if slices.Contains(names, step.Name) {
+			return fmt.Errorf("duplicate step name %q", step.Name)
+		}
+		if step.Command == "" {
+			return fmt.Errorf("step %q has no command", step.Name)
+		}
+		if step.Condition != "" && step.Condition != StepConditionSuccess && step.Condition != StepConditionAlways {
+			return fmt.Errorf("step %q has invalid condition %q", step.Name, step.Condition)
+		}
+		if err := step.Limits.Validate(); err != nil {
+			return fmt.Errorf("step %q: %w", step.Name, err)
+		}
+
+		// Steps can only depend on earlier steps, which rules out cycles
+		for _, dependency := range step.DependsOn {
+			if !slices.Contains(names, dependency) {
+				return fmt.Errorf("step %q depends on %q, which is not an earlier step", step.Name, dependency)
+			}
+		}
+
+		names = append(names, step.Name)
+	}
+
+	return nil
+}
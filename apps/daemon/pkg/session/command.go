@@ -42,7 +42,7 @@ func (s *SessionService) GetSessionCommand(sessionId, cmdId string) (*Command, e
 		return nil, common_errors.NewNotFoundError(errors.New("command not found"))
 	}
 
-	if command.ExitCode != nil {
+	if exitCode, _ := command.Result(); exitCode != nil {
 		return command, nil
 	}
 
@@ -60,7 +60,7 @@ func (s *SessionService) GetSessionCommand(sessionId, cmdId string) (*Command, e
 		return nil, fmt.Errorf("failed to convert exit code to int: %w", err)
 	}
 
-	command.ExitCode = &exitCodeInt
+	command.setExitCode(exitCodeInt)
 
 	return command, nil
 }
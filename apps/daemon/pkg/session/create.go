@@ -11,58 +11,90 @@ import (
 	"os/exec"
 
 	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/env"
 	cmap "github.com/orcaman/concurrent-map/v2"
 
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
-func (s *SessionService) Create(sessionId string, isLegacy bool) error {
-	ctx, cancel := context.WithCancel(context.Background())
-
-	cmd := exec.CommandContext(ctx, common.GetShell())
-	cmd.Env = os.Environ()
+func (s *SessionService) Create(sessionId string, opts CreateOptions) error {
+	for name := range opts.Envs {
+		if err := env.ValidateName(name); err != nil {
+			return common_errors.NewBadRequestError(err)
+		}
+	}
 
-	if isLegacy {
+	var dir string
+	if opts.IsLegacy {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			cancel()
 			return fmt.Errorf("failed to obtain user home directory for legacy SDK compatibility: %w", err)
 		}
 
-		cmd.Dir = homeDir
+		dir = homeDir
 	}
 
 	if _, ok := s.sessions.Get(sessionId); ok {
-		cancel()
 		return common_errors.NewConflictError(errors.New("session already exists"))
 	}
 
-	stdinWriter, err := cmd.StdinPipe()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	session := &session{
+		id:         sessionId,
+		persistent: opts.Persistent,
+		commands:   cmap.New[*Command](),
+		ctx:        ctx,
+		cancel:     cancel,
+		env:        map[string]string{},
+	}
+	for k, v := range opts.Envs {
+		session.env[k] = v
+	}
+
+	err := os.MkdirAll(session.Dir(s.configDir), 0755)
 	if err != nil {
 		cancel()
 		return err
 	}
 
-	err = cmd.Start()
+	if opts.Persistent {
+		pid, err := startTmuxSession(sessionId, session.Dir(s.configDir), dir, opts.Envs)
+		if err != nil {
+			cancel()
+			return common_errors.NewBadRequestError(err)
+		}
+
+		if err := s.saveEnv(session); err != nil {
+			log.Warnf("Failed to save environment variables of persistent session %s: %v", sessionId, err)
+		}
+
+		session.shellPid = pid
+		session.stdinWriter = &tmuxWriter{target: tmuxSessionName(sessionId), dir: session.Dir(s.configDir)}
+		s.sessions.Set(sessionId, session)
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, common.GetShell())
+	cmd.Env = env.Merge(env.Environ(), opts.Envs)
+	cmd.Dir = dir
+
+	stdinWriter, err := cmd.StdinPipe()
 	if err != nil {
 		cancel()
 		return err
 	}
 
-	session := &session{
-		id:          sessionId,
-		cmd:         cmd,
-		stdinWriter: stdinWriter,
-		commands:    cmap.New[*Command](),
-		ctx:         ctx,
-		cancel:      cancel,
-	}
-	s.sessions.Set(sessionId, session)
-
-	err = os.MkdirAll(session.Dir(s.configDir), 0755)
+	err = cmd.Start()
 	if err != nil {
+		cancel()
 		return err
 	}
 
+	session.cmd = cmd
+	session.stdinWriter = stdinWriter
+	s.sessions.Set(sessionId, session)
+
 	return nil
 }
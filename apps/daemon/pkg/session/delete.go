@@ -29,6 +29,12 @@ func (s *SessionService) Delete(ctx context.Context, sessionId string) error {
 		// Continue with cleanup even if termination fails
 	}
 
+	if session.persistent {
+		if err := killTmuxSession(session.id); err != nil {
+			log.Warnf("Failed to kill tmux session of %s: %v", session.id, err)
+		}
+	}
+
 	// Cancel context after termination
 	session.cancel()
 
@@ -38,25 +44,38 @@ func (s *SessionService) Delete(ctx context.Context, sessionId string) error {
 		return common_errors.NewBadRequestError(err)
 	}
 
+	for id, c := range s.chains.Items() {
+		if c.chain.SessionId == session.id {
+			s.chains.Remove(id)
+		}
+	}
+
 	s.sessions.Remove(session.id)
 	return nil
 }
 
 func (s *SessionService) terminateSession(ctx context.Context, session *session) error {
-	if session.cmd == nil || session.cmd.Process == nil {
+	pid := session.pid()
+	if pid == 0 {
 		return nil
 	}
 
-	pid := session.cmd.Process.Pid
+	shell, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
 
 	_ = s.signalProcessTree(pid, syscall.SIGTERM)
 
-	err := session.cmd.Process.Signal(syscall.SIGTERM)
-	if err != nil {
-		// If SIGTERM fails, try SIGKILL immediately
-		log.Warnf("SIGTERM failed for session %s, trying SIGKILL: %v", session.id, err)
-		_ = s.signalProcessTree(pid, syscall.SIGKILL)
-		return session.cmd.Process.Kill()
+	// The interactive shell of a persistent session ignores SIGTERM, it exits with its tmux session
+	if !session.persistent {
+		err = shell.Signal(syscall.SIGTERM)
+		if err != nil {
+			// If SIGTERM fails, try SIGKILL immediately
+			log.Warnf("SIGTERM failed for session %s, trying SIGKILL: %v", session.id, err)
+			_ = s.signalProcessTree(pid, syscall.SIGKILL)
+			return shell.Kill()
+		}
 	}
 
 	// Wait for graceful termination
@@ -67,7 +86,32 @@ func (s *SessionService) terminateSession(ctx context.Context, session *session)
 
 	log.Debugf("Session %s timeout, sending SIGKILL to process tree", session.id)
 	_ = s.signalProcessTree(pid, syscall.SIGKILL)
-	return session.cmd.Process.Kill()
+	if session.persistent {
+		return nil
+	}
+	return shell.Kill()
+}
+
+// terminateProcessTreeGracefully sends SIGTERM to a process and its descendants and SIGKILL
+// to what is left of them after the termination grace period
+func (s *SessionService) terminateProcessTreeGracefully(ctx context.Context, pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	_ = s.signalProcessTree(pid, syscall.SIGTERM)
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		// The process already exited
+		return nil
+	}
+
+	if s.waitForTermination(ctx, pid, s.terminationGracePeriod, s.terminationCheckInterval) {
+		return nil
+	}
+
+	_ = s.signalProcessTree(pid, syscall.SIGKILL)
+	return proc.Kill()
 }
 
 func (s *SessionService) signalProcessTree(pid int, sig syscall.Signal) error {
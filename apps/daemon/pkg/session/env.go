@@ -0,0 +1,116 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/daytonaio/daemon/pkg/env"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// GetEnv returns the variables set for the session on top of the sandbox defaults
+func (s *SessionService) GetEnv(sessionId string) (map[string]string, error) {
+	session, ok := s.sessions.Get(sessionId)
+	if !ok {
+		return nil, common_errors.NewNotFoundError(errors.New("session not found"))
+	}
+
+	session.envMu.Lock()
+	defer session.envMu.Unlock()
+
+	vars := make(map[string]string, len(session.env))
+	for k, v := range session.env {
+		vars[k] = v
+	}
+	return vars, nil
+}
+
+// SetEnv exports variables in the session shell. They apply to commands executed afterwards. It is
+// rejected while a command of the session is running, which the shell would read them as input of.
+func (s *SessionService) SetEnv(sessionId string, vars map[string]string) error {
+	session, ok := s.sessions.Get(sessionId)
+	if !ok {
+		return common_errors.NewNotFoundError(errors.New("session not found"))
+	}
+
+	var script strings.Builder
+	for name, value := range vars {
+		if err := env.ValidateName(name); err != nil {
+			return common_errors.NewBadRequestError(err)
+		}
+		script.WriteString(fmt.Sprintf("export %s=%s\n", name, shellQuote(value)))
+	}
+
+	session.envMu.Lock()
+	defer session.envMu.Unlock()
+
+	if err := s.checkNoCommandRunning(session); err != nil {
+		return err
+	}
+
+	if _, err := session.stdinWriter.Write([]byte(script.String())); err != nil {
+		return common_errors.NewBadRequestError(fmt.Errorf("failed to set environment variables: %w", err))
+	}
+
+	for k, v := range vars {
+		session.env[k] = v
+	}
+	return s.saveEnv(session)
+}
+
+// UnsetEnv removes variables from the session shell, including sandbox defaults. Like SetEnv, it
+// is rejected while a command of the session is running.
+func (s *SessionService) UnsetEnv(sessionId string, names []string) error {
+	session, ok := s.sessions.Get(sessionId)
+	if !ok {
+		return common_errors.NewNotFoundError(errors.New("session not found"))
+	}
+
+	for _, name := range names {
+		if err := env.ValidateName(name); err != nil {
+			return common_errors.NewBadRequestError(err)
+		}
+	}
+
+	session.envMu.Lock()
+	defer session.envMu.Unlock()
+
+	if err := s.checkNoCommandRunning(session); err != nil {
+		return err
+	}
+
+	if _, err := session.stdinWriter.Write([]byte("unset " + strings.Join(names, " ") + "\n")); err != nil {
+		return common_errors.NewBadRequestError(fmt.Errorf("failed to unset environment variables: %w", err))
+	}
+
+	for _, name := range names {
+		delete(session.env, name)
+	}
+	return s.saveEnv(session)
+}
+
+// checkNoCommandRunning returns a conflict error if a command of the session hasn't exited yet
+func (s *SessionService) checkNoCommandRunning(session *session) error {
+	for _, command := range session.commands.Items() {
+		if exitCode, _ := command.Result(); exitCode != nil {
+			continue
+		}
+
+		_, exitCodeFilePath := command.LogFilePath(session.Dir(s.configDir))
+		if _, err := os.Stat(exitCodeFilePath); os.IsNotExist(err) {
+			return common_errors.NewConflictError(fmt.Errorf("command %s is running in the session, wait for it to exit before changing environment variables", command.Id))
+		}
+	}
+
+	return nil
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -13,19 +13,23 @@ import (
 	"strings"
 	"time"
 
-	"github.com/daytonaio/daemon/internal/util"
 	"github.com/google/uuid"
 
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
 )
 
-func (s *SessionService) Execute(sessionId, cmd string, async, isCombinedOutput bool) (*SessionExecute, error) {
+func (s *SessionService) Execute(sessionId, cmd string, async, isCombinedOutput bool, limits CommandLimits) (*SessionExecute, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, common_errors.NewBadRequestError(err)
+	}
+
 	session, ok := s.sessions.Get(sessionId)
 	if !ok {
 		return nil, common_errors.NewNotFoundError(errors.New("session not found"))
 	}
 
 	cmdId := uuid.NewString()
+	startedAt := time.Now()
 
 	command := &Command{
 		Id:      cmdId,
@@ -40,6 +44,11 @@ func (s *SessionService) Execute(sessionId, cmd string, async, isCombinedOutput
 		return nil, common_errors.NewBadRequestError(fmt.Errorf("failed to create log directory: %w", err))
 	}
 
+	// Keep the command next to its logs so persistent sessions can restore it after a restart
+	if err := os.WriteFile(filepath.Join(logDir, commandFileName), []byte(cmd), 0644); err != nil {
+		return nil, common_errors.NewBadRequestError(fmt.Errorf("failed to write command file: %w", err))
+	}
+
 	logFile, err := os.Create(logFilePath)
 	if err != nil {
 		return nil, common_errors.NewBadRequestError(fmt.Errorf("failed to create log file: %w", err))
@@ -47,21 +56,34 @@ func (s *SessionService) Execute(sessionId, cmd string, async, isCombinedOutput
 
 	defer logFile.Close()
 
+	script := cmd
+	cleanup := func() {}
+	if limits.IsSet() {
+		script, cleanup = limitCommand(cmd, logDir, limits)
+	}
+
 	cmdToExec := fmt.Sprintf(cmdWrapperFormat+"\n",
 		logFilePath, // %q  -> log
 		logDir,      // %q  -> dir
 		command.InputFilePath(session.Dir(s.configDir)), // %q  -> input
 		toOctalEscapes(STDOUT_PREFIX),                   // %s  -> stdout prefix
 		toOctalEscapes(STDERR_PREFIX),                   // %s  -> stderr prefix
-		cmd,                                             // %s  -> verbatim script body
+		script,                                          // %s  -> verbatim script body
 		exitCodeFilePath,                                // %q
 	)
 
 	_, err = session.stdinWriter.Write([]byte(cmdToExec))
 	if err != nil {
+		cleanup()
 		return nil, common_errors.NewBadRequestError(fmt.Errorf("failed to write command: %w", err))
 	}
 
+	if limits.IsSet() {
+		go s.enforceLimits(session, command, limits, cleanup)
+	}
+
+	go s.recordHistory(session, command, startedAt)
+
 	if async {
 		return &SessionExecute{
 			CommandId: cmdId,
@@ -76,7 +98,7 @@ func (s *SessionService) Execute(sessionId, cmd string, async, isCombinedOutput
 				return nil, common_errors.NewBadRequestError(errors.New("command not found"))
 			}
 
-			command.ExitCode = util.Pointer(1)
+			command.setExitCode(1)
 
 			return nil, common_errors.NewBadRequestError(errors.New("session cancelled"))
 		default:
@@ -98,7 +120,7 @@ func (s *SessionService) Execute(sessionId, cmd string, async, isCombinedOutput
 			if !ok {
 				return nil, common_errors.NewBadRequestError(errors.New("command not found"))
 			}
-			command.ExitCode = &exitCodeInt
+			command.setExitCode(exitCodeInt)
 
 			logBytes, err := os.ReadFile(logFilePath)
 			if err != nil {
@@ -10,7 +10,7 @@ import (
 )
 
 func (s *SessionService) Get(sessionId string) (*Session, error) {
-	_, ok := s.sessions.Get(sessionId)
+	session, ok := s.sessions.Get(sessionId)
 	if !ok {
 		return nil, common_errors.NewNotFoundError(errors.New("session not found"))
 	}
@@ -21,7 +21,8 @@ func (s *SessionService) Get(sessionId string) (*Session, error) {
 	}
 
 	return &Session{
-		SessionId: sessionId,
-		Commands:  commands,
+		SessionId:  sessionId,
+		Commands:   commands,
+		Persistent: session.persistent,
 	}, nil
 }
@@ -0,0 +1,231 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	historyMaxEntries = 10000
+	historyMaxAge     = 30 * 24 * time.Hour
+)
+
+// HistoryEntry describes a finished session command
+type HistoryEntry struct {
+	CommandId   string    `json:"commandId"`
+	SessionId   string    `json:"sessionId"`
+	Command     string    `json:"command"`
+	ExitCode    *int      `json:"exitCode,omitempty"`
+	StartedAt   time.Time `json:"startedAt"`
+	EndedAt     time.Time `json:"endedAt"`
+	LogFilePath string    `json:"logFilePath"`
+}
+
+func (e *HistoryEntry) Duration() time.Duration {
+	return e.EndedAt.Sub(e.StartedAt)
+}
+
+type HistoryFilter struct {
+	SessionId string
+	// Case-insensitive substring of the command
+	Query    string
+	ExitCode *int
+	Since    time.Time
+	Limit    int
+}
+
+// history keeps finished commands in a JSON lines file. The file is rewritten without
+// expired entries once it holds twice the number of retained entries.
+type history struct {
+	mu      sync.Mutex
+	path    string
+	entries []HistoryEntry
+}
+
+func newHistory(configDir string) *history {
+	h := &history{
+		path: filepath.Join(configDir, "history.jsonl"),
+	}
+
+	if err := h.load(); err != nil {
+		log.Warnf("Failed to load command history: %v", err)
+	}
+
+	return h
+}
+
+func (h *history) load() error {
+	file, err := os.Open(h.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Skip a line that was partially written
+			continue
+		}
+		h.entries = append(h.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	h.entries = retainHistory(h.entries)
+	return nil
+}
+
+func (h *history) add(entry HistoryEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = append(h.entries, entry)
+	if len(h.entries) >= 2*historyMaxEntries {
+		h.entries = retainHistory(h.entries)
+		return h.rewrite()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+func (h *history) rewrite() error {
+	tmpPath := h.path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range h.entries {
+		if err := encoder.Encode(entry); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, h.path)
+}
+
+// list returns the matching entries, newest first
+func (h *history) list(filter HistoryFilter) []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	query := strings.ToLower(filter.Query)
+	cutoff := time.Now().Add(-historyMaxAge)
+
+	result := []HistoryEntry{}
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		entry := h.entries[i]
+
+		if entry.EndedAt.Before(cutoff) || entry.EndedAt.Before(filter.Since) {
+			continue
+		}
+		if filter.SessionId != "" && entry.SessionId != filter.SessionId {
+			continue
+		}
+		if filter.ExitCode != nil && (entry.ExitCode == nil || *entry.ExitCode != *filter.ExitCode) {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(entry.Command), query) {
+			continue
+		}
+
+		result = append(result, entry)
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+
+	return result
+}
+
+func retainHistory(entries []HistoryEntry) []HistoryEntry {
+	cutoff := time.Now().Add(-historyMaxAge)
+
+	start := max(len(entries)-historyMaxEntries, 0)
+	for start < len(entries) && entries[start].EndedAt.Before(cutoff) {
+		start++
+	}
+
+	return append([]HistoryEntry{}, entries[start:]...)
+}
+
+func (s *SessionService) ListHistory(filter HistoryFilter) []HistoryEntry {
+	return s.history.list(filter)
+}
+
+// recordHistory waits for a command to finish and adds it to the history
+func (s *SessionService) recordHistory(session *session, command *Command, startedAt time.Time) {
+	logFilePath, exitCodeFilePath := command.LogFilePath(session.Dir(s.configDir))
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-session.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !hasExitCode(exitCodeFilePath) {
+			continue
+		}
+
+		entry := HistoryEntry{
+			CommandId:   command.Id,
+			SessionId:   session.id,
+			Command:     command.Command,
+			StartedAt:   startedAt,
+			EndedAt:     time.Now(),
+			LogFilePath: logFilePath,
+		}
+		if exitCode, err := readExitCode(exitCodeFilePath); err == nil {
+			entry.ExitCode = &exitCode
+		}
+
+		if err := s.history.add(entry); err != nil {
+			log.Errorf("Failed to record command %s in history: %v", command.Id, err)
+		}
+		return
+	}
+}
@@ -22,7 +22,7 @@ func (s *SessionService) SendInput(sessionId, commandId string, data string) err
 	}
 
 	// Check if the session process is still active
-	if session.cmd.ProcessState != nil && session.cmd.ProcessState.Exited() {
+	if session.cmd != nil && session.cmd.ProcessState != nil && session.cmd.ProcessState.Exited() {
 		return common_errors.NewGoneError(errors.New("session process has exited"))
 	}
 
@@ -33,8 +33,8 @@ func (s *SessionService) SendInput(sessionId, commandId string, data string) err
 	}
 
 	// Check if the command is still running (exit code not set means still running)
-	if command.ExitCode != nil {
-		return common_errors.NewGoneError(fmt.Errorf("command has already completed with exit code %d", *command.ExitCode))
+	if exitCode, _ := command.Result(); exitCode != nil {
+		return common_errors.NewGoneError(fmt.Errorf("command has already completed with exit code %d", *exitCode))
 	}
 
 	inputFilePath := command.InputFilePath(session.Dir(s.configDir))
@@ -0,0 +1,301 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	TerminationReasonTimeout     = "timeout"
+	TerminationReasonOutputLimit = "output_limit"
+)
+
+const (
+	cgroupRoot      = "/sys/fs/cgroup"
+	cgroupCpuPeriod = 100000
+	pidFileName     = "pid"
+	// Leaf cgroup the daemon moves its own processes to, so controllers can be enabled for command cgroups
+	daemonCgroupName = "daytona-daemon"
+)
+
+var (
+	commandCgroupParentOnce sync.Once
+	commandCgroupParent     string
+	commandCgroupParentErr  error
+)
+
+// CommandLimits restricts the resources a single session command may use. Zero values mean no limit.
+type CommandLimits struct {
+	// Number of CPU cores, e.g. 0.5. Only enforced when cgroup v2 is available.
+	Cpus float64
+	// Memory limit in bytes, enforced with a cgroup or with a data segment rlimit as fallback
+	MemoryBytes int64
+	// The command is terminated once its output log exceeds this size
+	MaxOutputBytes int64
+	// The command is terminated after this time
+	Timeout time.Duration
+}
+
+func (l CommandLimits) IsSet() bool {
+	return l.Cpus > 0 || l.MemoryBytes > 0 || l.MaxOutputBytes > 0 || l.Timeout > 0
+}
+
+func (l CommandLimits) Validate() error {
+	if l.Cpus < 0 || l.MemoryBytes < 0 || l.MaxOutputBytes < 0 || l.Timeout < 0 {
+		return fmt.Errorf("command limits must not be negative")
+	}
+	return nil
+}
+
+// limitCommand wraps a command so that it runs in a subshell confined by the limits. The subshell
+// writes its pid to the command directory so it can be terminated. Since the command no longer runs
+// in the session shell, changes to the shell state like cd or export do not persist.
+func limitCommand(cmd, cmdDir string, limits CommandLimits) (string, func()) {
+	var sb strings.Builder
+	sb.WriteString("(\n")
+	fmt.Fprintf(&sb, "sh -c 'echo \"$PPID\"' > %s\n", shellQuote(filepath.Join(cmdDir, pidFileName)))
+
+	cleanup := func() {}
+	if limits.Cpus > 0 || limits.MemoryBytes > 0 {
+		cgroup, err := createCommandCgroup(filepath.Base(cmdDir), limits)
+		if err == nil {
+			// Writing 0 moves the writing process, the builtin echo runs in the subshell itself
+			fmt.Fprintf(&sb, "echo 0 > %s || exit 1\n", shellQuote(filepath.Join(cgroup, "cgroup.procs")))
+			cleanup = func() { removeCgroup(cgroup) }
+		} else {
+			log.Debugf("Failed to create cgroup for command, falling back to rlimits: %v", err)
+			if limits.MemoryBytes > 0 {
+				// Unlike the virtual memory limit, the data limit doesn't count address space runtimes
+				// like the JVM, Go and Node reserve without using it
+				fmt.Fprintf(&sb, "ulimit -d %d || exit 1\n", max(limits.MemoryBytes/1024, 1))
+			}
+			if limits.Cpus > 0 {
+				log.Warnf("CPU limit of command in %s is not enforced without cgroup v2", cmdDir)
+			}
+		}
+	}
+
+	sb.WriteString(cmd)
+	sb.WriteString("\n)")
+
+	return sb.String(), cleanup
+}
+
+// ownCgroup returns the cgroup v2 directory of the daemon process
+func ownCgroup() (string, error) {
+	file, err := os.Open("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+			return filepath.Join(cgroupRoot, path), nil
+		}
+	}
+
+	return "", fmt.Errorf("cgroup v2 is not available")
+}
+
+// prepareCommandCgroupParent returns the cgroup command cgroups are created in. Controllers can
+// only be enabled for the children of a cgroup without processes of its own, so the processes in
+// the daemon's cgroup are moved to a leaf cgroup first.
+func prepareCommandCgroupParent() (string, error) {
+	commandCgroupParentOnce.Do(func() {
+		parent, err := ownCgroup()
+		if err != nil {
+			commandCgroupParentErr = err
+			return
+		}
+
+		if filepath.Base(parent) == daemonCgroupName {
+			// The daemon was restarted in the leaf cgroup it moved itself to
+			commandCgroupParent = filepath.Dir(parent)
+			return
+		}
+
+		leaf := filepath.Join(parent, daemonCgroupName)
+
+		if err := os.Mkdir(leaf, 0755); err != nil && !os.IsExist(err) {
+			commandCgroupParentErr = fmt.Errorf("failed to create daemon cgroup: %w", err)
+			return
+		}
+
+		if err := moveCgroupProcesses(parent, leaf); err != nil {
+			commandCgroupParentErr = fmt.Errorf("failed to move processes to daemon cgroup: %w", err)
+			return
+		}
+
+		commandCgroupParent = parent
+	})
+
+	return commandCgroupParent, commandCgroupParentErr
+}
+
+// moveCgroupProcesses moves all processes of a cgroup to another one
+func moveCgroupProcesses(from, to string) error {
+	content, err := os.ReadFile(filepath.Join(from, "cgroup.procs"))
+	if err != nil {
+		return err
+	}
+
+	for _, pid := range strings.Fields(string(content)) {
+		err := os.WriteFile(filepath.Join(to, "cgroup.procs"), []byte(pid), 0644)
+		// Processes may exit while they are moved
+		if err != nil && !errors.Is(err, syscall.ESRCH) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createCommandCgroup(cmdId string, limits CommandLimits) (string, error) {
+	parent, err := prepareCommandCgroupParent()
+	if err != nil {
+		return "", err
+	}
+
+	controllers := []string{}
+	if limits.Cpus > 0 {
+		controllers = append(controllers, "+cpu")
+	}
+	if limits.MemoryBytes > 0 {
+		controllers = append(controllers, "+memory")
+	}
+	err = os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte(strings.Join(controllers, " ")), 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to enable cgroup controllers: %w", err)
+	}
+
+	cgroup := filepath.Join(parent, "daytona-cmd-"+cmdId)
+	if err := os.Mkdir(cgroup, 0755); err != nil {
+		return "", err
+	}
+
+	if limits.Cpus > 0 {
+		quota := max(int64(limits.Cpus*cgroupCpuPeriod), 1000)
+		err = os.WriteFile(filepath.Join(cgroup, "cpu.max"), []byte(fmt.Sprintf("%d %d", quota, cgroupCpuPeriod)), 0644)
+		if err != nil {
+			removeCgroup(cgroup)
+			return "", fmt.Errorf("failed to set cpu limit: %w", err)
+		}
+	}
+
+	if limits.MemoryBytes > 0 {
+		err = os.WriteFile(filepath.Join(cgroup, "memory.max"), []byte(strconv.FormatInt(limits.MemoryBytes, 10)), 0644)
+		if err != nil {
+			removeCgroup(cgroup)
+			return "", fmt.Errorf("failed to set memory limit: %w", err)
+		}
+		// Don't let the command swap instead of being killed
+		_ = os.WriteFile(filepath.Join(cgroup, "memory.swap.max"), []byte("0"), 0644)
+	}
+
+	return cgroup, nil
+}
+
+func removeCgroup(cgroup string) {
+	// Kill processes the command left behind, a cgroup can only be removed once it is empty
+	_ = os.WriteFile(filepath.Join(cgroup, "cgroup.kill"), []byte("1"), 0644)
+
+	for i := 0; i < 50; i++ {
+		err := os.Remove(cgroup)
+		if err == nil || os.IsNotExist(err) {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	log.Warnf("Failed to remove command cgroup %s", cgroup)
+}
+
+// enforceLimits terminates the command when it runs past its timeout or output limit and
+// releases its cgroup once it exits
+func (s *SessionService) enforceLimits(session *session, command *Command, limits CommandLimits, cleanup func()) {
+	defer cleanup()
+
+	logFilePath, exitCodeFilePath := command.LogFilePath(session.Dir(s.configDir))
+	pidFilePath := filepath.Join(filepath.Dir(logFilePath), pidFileName)
+
+	var deadline <-chan time.Time
+	if limits.Timeout > 0 {
+		timer := time.NewTimer(limits.Timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		var reason string
+
+		select {
+		case <-session.ctx.Done():
+			return
+		case <-deadline:
+			reason = TerminationReasonTimeout
+		case <-ticker.C:
+			if hasExitCode(exitCodeFilePath) {
+				return
+			}
+			if limits.MaxOutputBytes > 0 {
+				if info, err := os.Stat(logFilePath); err == nil && info.Size() > limits.MaxOutputBytes {
+					reason = TerminationReasonOutputLimit
+				}
+			}
+		}
+
+		if reason == "" {
+			continue
+		}
+
+		pid, err := readPidFile(pidFilePath)
+		if err != nil {
+			log.Errorf("Failed to terminate command %s: %v", command.Id, err)
+			return
+		}
+
+		command.setTerminationReason(reason)
+		// Kept next to the exit code, so persistent sessions can restore it after a restart
+		_ = os.WriteFile(filepath.Join(filepath.Dir(logFilePath), terminationReasonFileName), []byte(reason), 0644)
+		log.Debugf("Terminating command %s of session %s: %s", command.Id, session.id, reason)
+
+		err = s.terminateProcessTreeGracefully(context.Background(), pid)
+		if err != nil {
+			log.Errorf("Failed to terminate command %s: %v", command.Id, err)
+		}
+		return
+	}
+}
+
+func readPidFile(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read pid file: %w", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse pid file: %w", err)
+	}
+
+	return pid, nil
+}
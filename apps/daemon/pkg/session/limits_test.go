@@ -0,0 +1,131 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandLimitsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits CommandLimits
+		err    bool
+	}{
+		{name: "no limits", limits: CommandLimits{}},
+		{name: "all limits", limits: CommandLimits{Cpus: 0.5, MemoryBytes: 1 << 20, MaxOutputBytes: 1024, Timeout: time.Second}},
+		{name: "negative cpus", limits: CommandLimits{Cpus: -1}, err: true},
+		{name: "negative memory", limits: CommandLimits{MemoryBytes: -1}, err: true},
+		{name: "negative output size", limits: CommandLimits{MaxOutputBytes: -1}, err: true},
+		{name: "negative timeout", limits: CommandLimits{Timeout: -time.Second}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Validate()
+			if tt.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLimitCommand(t *testing.T) {
+	// Keep the test process out of the cgroup hierarchy, commands fall back to rlimits
+	commandCgroupParentOnce.Do(func() {
+		commandCgroupParentErr = errors.New("cgroup v2 is not available")
+	})
+
+	tests := []struct {
+		name     string
+		command  string
+		limits   CommandLimits
+		output   string
+		contains string
+	}{
+		{
+			name:    "timeout only",
+			command: "echo hello",
+			limits:  CommandLimits{Timeout: time.Second},
+			output:  "hello\n",
+		},
+		{
+			name:    "quotes in command",
+			command: `echo "it's" '$HOME'`,
+			limits:  CommandLimits{MaxOutputBytes: 1024},
+			output:  "it's $HOME\n",
+		},
+		{
+			name:     "memory falls back to data rlimit",
+			command:  "ulimit -d",
+			limits:   CommandLimits{MemoryBytes: 64 << 20},
+			output:   "65536\n",
+			contains: "ulimit -d 65536",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmdDir := t.TempDir()
+
+			script, cleanup := limitCommand(tt.command, cmdDir, tt.limits)
+			defer cleanup()
+			if tt.contains != "" {
+				assert.Contains(t, script, tt.contains)
+			}
+
+			output, err := exec.Command("sh", "-c", script).Output()
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, string(output))
+
+			pid, err := readPidFile(filepath.Join(cmdDir, pidFileName))
+			require.NoError(t, err)
+			assert.Positive(t, pid)
+		})
+	}
+}
+
+func TestReadPidFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *string
+		pid     int
+		err     string
+	}{
+		{name: "pid with newline", content: stringPtr("123\n"), pid: 123},
+		{name: "pid without newline", content: stringPtr("456"), pid: 456},
+		{name: "missing file", err: "failed to read pid file"},
+		{name: "empty file", content: stringPtr(""), err: "failed to parse pid file"},
+		{name: "invalid pid", content: stringPtr("abc\n"), err: "failed to parse pid file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), pidFileName)
+			if tt.content != nil {
+				require.NoError(t, os.WriteFile(path, []byte(*tt.content), 0644))
+			}
+
+			pid, err := readPidFile(path)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.pid, pid)
+		})
+	}
+}
@@ -6,17 +6,40 @@ package session
 func (s *SessionService) List() ([]Session, error) {
 	sessions := []Session{}
 
-	for _, sessionId := range s.sessions.Keys() {
+	for sessionId, session := range s.sessions.Items() {
 		commands, err := s.getSessionCommands(sessionId)
 		if err != nil {
 			return nil, err
 		}
 
 		sessions = append(sessions, Session{
-			SessionId: sessionId,
-			Commands:  commands,
+			SessionId:  sessionId,
+			Commands:   commands,
+			Persistent: session.persistent,
 		})
 	}
 
 	return sessions, nil
 }
+
+// RunningCommandCount returns the number of commands still running in sessions that don't
+// survive a daemon restart
+func (s *SessionService) RunningCommandCount() int {
+	count := 0
+	for sessionId, session := range s.sessions.Items() {
+		if session.persistent {
+			continue
+		}
+
+		commands, err := s.getSessionCommands(sessionId)
+		if err != nil {
+			continue
+		}
+		for _, command := range commands {
+			if exitCode, _ := command.Result(); exitCode == nil {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -4,6 +4,9 @@
 package session
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	cmap "github.com/orcaman/concurrent-map/v2"
@@ -12,15 +15,40 @@ import (
 type SessionService struct {
 	configDir                string
 	sessions                 cmap.ConcurrentMap[string, *session]
+	chains                   cmap.ConcurrentMap[string, *chain]
+	history                  *history
 	terminationGracePeriod   time.Duration
 	terminationCheckInterval time.Duration
 }
 
 func NewSessionService(configDir string, terminationGracePeriod, terminationCheckInterval time.Duration) *SessionService {
-	return &SessionService{
+	s := &SessionService{
 		configDir:                configDir,
 		sessions:                 cmap.New[*session](),
+		chains:                   cmap.New[*chain](),
+		history:                  newHistory(configDir),
 		terminationGracePeriod:   terminationGracePeriod,
 		terminationCheckInterval: terminationCheckInterval,
 	}
+
+	s.restorePersistentSessions()
+
+	return s
+}
+
+// CheckHealth verifies that the session directory, where command logs and exit codes are
+// written, is writable
+func (s *SessionService) CheckHealth() error {
+	dir := filepath.Join(s.configDir, "sessions")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	probe, err := os.CreateTemp(dir, ".health-*")
+	if err != nil {
+		return fmt.Errorf("session directory is not writable: %w", err)
+	}
+	probe.Close()
+
+	return os.Remove(probe.Name())
 }
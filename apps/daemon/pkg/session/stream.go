@@ -0,0 +1,289 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+const (
+	OutputEventStdout = "stdout"
+	OutputEventStderr = "stderr"
+	OutputEventExit   = "exit"
+)
+
+const outputChunkSize = 32 * 1024
+
+// OutputEvent is a chunk of command output. Offset is the position in the command log after
+// the chunk and can be used to resume the stream without gaps or duplicates.
+type OutputEvent struct {
+	Type     string
+	Seq      uint64
+	Offset   int64
+	Data     string
+	ExitCode *int
+}
+
+// OutputStream reads the output of a session command incrementally from its log file.
+// Output is only read after the previous event has been sent, so slow consumers are
+// throttled by the log file instead of buffering in memory.
+type OutputStream struct {
+	session          *session
+	file             *os.File
+	exitCodeFilePath string
+	offset           int64
+	stream           string
+	seq              uint64
+}
+
+// NewOutputStream opens the output of a command, starting at the given log offset
+func (s *SessionService) NewOutputStream(sessionId, commandId string, offset int64) (*OutputStream, error) {
+	session, ok := s.sessions.Get(sessionId)
+	if !ok {
+		return nil, common_errors.NewNotFoundError(errors.New("session not found"))
+	}
+
+	command, ok := session.commands.Get(commandId)
+	if !ok {
+		return nil, common_errors.NewNotFoundError(errors.New("command not found"))
+	}
+
+	logFilePath, exitCodeFilePath := command.LogFilePath(session.Dir(s.configDir))
+
+	file, err := os.Open(logFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, common_errors.NewNotFoundError(err)
+		}
+		return nil, common_errors.NewBadRequestError(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, common_errors.NewBadRequestError(err)
+	}
+	if offset > info.Size() {
+		file.Close()
+		return nil, common_errors.NewBadRequestError(fmt.Errorf("offset %d is past the end of the output (%d bytes)", offset, info.Size()))
+	}
+
+	// The log only marks stream switches, so find out which stream the offset is in
+	stream, err := streamAtOffset(file, offset)
+	if err != nil {
+		file.Close()
+		return nil, common_errors.NewBadRequestError(err)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, common_errors.NewBadRequestError(err)
+	}
+
+	return &OutputStream{
+		session:          session,
+		file:             file,
+		exitCodeFilePath: exitCodeFilePath,
+		offset:           offset,
+		stream:           stream,
+	}, nil
+}
+
+// Run sends output events until the command exits, the session ends or the context is cancelled.
+// The last event of a finished command is of type OutputEventExit.
+func (o *OutputStream) Run(ctx context.Context, send func(OutputEvent) error) error {
+	var pending []byte
+	buf := make([]byte, outputChunkSize)
+	exited := false
+
+	emit := func(final bool) error {
+		segments, stream, consumed := demuxOutput(pending, o.stream, final)
+		for _, segment := range segments {
+			o.seq++
+			err := send(OutputEvent{
+				Type:   segment.stream,
+				Seq:    o.seq,
+				Offset: o.offset + int64(segment.end),
+				Data:   string(segment.data),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		o.stream = stream
+		o.offset += int64(consumed)
+		pending = append(pending[:0], pending[consumed:]...)
+		return nil
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		n, err := o.file.Read(buf)
+		if n > 0 {
+			pending = append(pending, buf[:n]...)
+			if err := emit(false); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		// At the end of the log: the command is done once the exit code is written and,
+		// since output may still be flushed after that, a further read comes back empty
+		if exited {
+			if err := emit(true); err != nil {
+				return err
+			}
+
+			exitCode, err := readExitCode(o.exitCodeFilePath)
+			if err != nil {
+				return err
+			}
+
+			o.seq++
+			return send(OutputEvent{
+				Type:     OutputEventExit,
+				Seq:      o.seq,
+				Offset:   o.offset,
+				ExitCode: &exitCode,
+			})
+		}
+		exited = hasExitCode(o.exitCodeFilePath)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-o.session.ctx.Done():
+			return emit(true)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+func (o *OutputStream) Close() error {
+	return o.file.Close()
+}
+
+type outputSegment struct {
+	stream string
+	data   []byte
+	// index in the demultiplexed buffer after the segment
+	end int
+}
+
+// demuxOutput splits log data into stdout and stderr segments. Unless final is set, a trailing
+// partial stream prefix or UTF-8 sequence is left unconsumed for the next call.
+func demuxOutput(data []byte, stream string, final bool) ([]outputSegment, string, int) {
+	var segments []outputSegment
+	start, i := 0, 0
+
+	emit := func(end int) {
+		if end > start {
+			segments = append(segments, outputSegment{stream: stream, data: data[start:end], end: end})
+		}
+	}
+
+	for i < len(data) {
+		rest := data[i:]
+
+		if bytes.HasPrefix(rest, STDOUT_PREFIX) || bytes.HasPrefix(rest, STDERR_PREFIX) {
+			emit(i)
+			stream = OutputEventStdout
+			if bytes.HasPrefix(rest, STDERR_PREFIX) {
+				stream = OutputEventStderr
+			}
+			i += len(STDOUT_PREFIX)
+			start = i
+			continue
+		}
+
+		if !final && len(rest) < len(STDOUT_PREFIX) && (bytes.HasPrefix(STDOUT_PREFIX, rest) || bytes.HasPrefix(STDERR_PREFIX, rest)) {
+			break
+		}
+
+		i++
+	}
+
+	end := i
+	if !final {
+		end = start + completeRunesLen(data[start:i])
+	}
+	emit(end)
+
+	return segments, stream, max(end, start)
+}
+
+// completeRunesLen returns the length of b without a trailing incomplete UTF-8 sequence
+func completeRunesLen(b []byte) int {
+	for k := 1; k <= utf8.UTFMax-1 && k <= len(b); k++ {
+		if utf8.RuneStart(b[len(b)-k]) {
+			if !utf8.FullRune(b[len(b)-k:]) {
+				return len(b) - k
+			}
+			break
+		}
+	}
+	return len(b)
+}
+
+func streamAtOffset(r io.Reader, offset int64) (string, error) {
+	stream := OutputEventStdout
+	var pending []byte
+	buf := make([]byte, outputChunkSize)
+	limited := io.LimitReader(r, offset)
+
+	for {
+		n, err := limited.Read(buf)
+		pending = append(pending, buf[:n]...)
+
+		var consumed int
+		_, stream, consumed = demuxOutput(pending, stream, false)
+		pending = append(pending[:0], pending[consumed:]...)
+
+		if errors.Is(err, io.EOF) {
+			return stream, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+func readExitCode(exitCodeFilePath string) (int, error) {
+	content, err := os.ReadFile(exitCodeFilePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read exit code file: %w", err)
+	}
+
+	exitCode, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert exit code to int: %w", err)
+	}
+
+	return exitCode, nil
+}
+
+func hasExitCode(exitCodeFilePath string) bool {
+	content, err := os.ReadFile(exitCodeFilePath)
+	if err != nil {
+		return false
+	}
+	return len(strings.TrimSpace(string(content))) > 0
+}
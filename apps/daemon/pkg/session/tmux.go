@@ -0,0 +1,248 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/env"
+	"github.com/google/uuid"
+	cmap "github.com/orcaman/concurrent-map/v2"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Persistent sessions run their shell in a tmux server on a dedicated socket, so the shell
+// and the commands it runs outlive the daemon. They are picked up again on daemon start.
+const (
+	tmuxSocketName    = "daytona"
+	tmuxSessionPrefix = "daytona-"
+	// Name of the file in a command directory holding the command, used to restore it
+	commandFileName = "command"
+	// Name of the file in a command directory holding the reason the daemon terminated it
+	terminationReasonFileName = "termination_reason"
+	// Name of the file in a session directory holding the variables set for the session
+	sessionEnvFileName = "env"
+	// Name of the script in a session directory exporting the variables of a new session's shell
+	sessionEnvScriptName = "env.sh"
+)
+
+func tmuxAvailable() bool {
+	_, err := exec.LookPath("tmux")
+	return err == nil
+}
+
+func tmuxCommand(args ...string) *exec.Cmd {
+	return exec.Command("tmux", append([]string{"-L", tmuxSocketName}, args...)...)
+}
+
+// tmuxSessionName encodes the session ID since tmux doesn't allow some characters in names
+func tmuxSessionName(sessionId string) string {
+	return tmuxSessionPrefix + hex.EncodeToString([]byte(sessionId))
+}
+
+func sessionIdFromTmuxName(name string) (string, bool) {
+	encoded, ok := strings.CutPrefix(name, tmuxSessionPrefix)
+	if !ok {
+		return "", false
+	}
+	id, err := hex.DecodeString(encoded)
+	if err != nil {
+		return "", false
+	}
+	return string(id), true
+}
+
+func startTmuxSession(sessionId, sessionDir, dir string, envs map[string]string) (int, error) {
+	if !tmuxAvailable() {
+		return 0, fmt.Errorf("persistent sessions require tmux to be installed")
+	}
+
+	// The tmux server keeps the environment it was started with, so the variables are exported
+	// explicitly. They are exported by a script the shell sources and removes, since the arguments
+	// of tmux and of the shell can be read from the process list.
+	vars := env.Default().Get()
+	for k, v := range envs {
+		vars[k] = v
+	}
+	var script strings.Builder
+	for k, v := range vars {
+		script.WriteString(fmt.Sprintf("export %s=%s\n", k, shellQuote(v)))
+	}
+	scriptPath := filepath.Join(sessionDir, sessionEnvScriptName)
+	if err := os.WriteFile(scriptPath, []byte(script.String()), 0600); err != nil {
+		return 0, fmt.Errorf("failed to write session environment: %w", err)
+	}
+
+	name := tmuxSessionName(sessionId)
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+
+	shell := common.GetShell()
+	args = append(args, shell, "-c", fmt.Sprintf(". %s; rm -f %s; exec %s", shellQuote(scriptPath), shellQuote(scriptPath), shellQuote(shell)))
+
+	cmd := tmuxCommand(args...)
+	cmd.Env = env.Environ()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(scriptPath)
+		return 0, fmt.Errorf("failed to start tmux session: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return tmuxPanePid(name)
+}
+
+func tmuxPanePid(name string) (int, error) {
+	output, err := tmuxCommand("display-message", "-p", "-t", name, "#{pane_pid}").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tmux session pid: %w", err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(output)))
+}
+
+func killTmuxSession(sessionId string) error {
+	output, err := tmuxCommand("kill-session", "-t", tmuxSessionName(sessionId)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to kill tmux session: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// tmuxWriter feeds shell input to a tmux session. Each write is stored in a script that the
+// shell sources, so multi-line command wrappers are not mangled by interactive line editing.
+type tmuxWriter struct {
+	target string
+	dir    string
+}
+
+func (w *tmuxWriter) Write(p []byte) (int, error) {
+	script := filepath.Join(w.dir, "input-"+uuid.NewString()+".sh")
+	if err := os.WriteFile(script, p, 0600); err != nil {
+		return 0, err
+	}
+
+	line := fmt.Sprintf(". %s; rm -f %s", shellQuote(script), shellQuote(script))
+	if err := tmuxCommand("send-keys", "-t", w.target, "-l", line).Run(); err != nil {
+		os.Remove(script)
+		return 0, fmt.Errorf("failed to send input to tmux session: %w", err)
+	}
+	if err := tmuxCommand("send-keys", "-t", w.target, "Enter").Run(); err != nil {
+		return 0, fmt.Errorf("failed to send input to tmux session: %w", err)
+	}
+
+	return len(p), nil
+}
+
+// restorePersistentSessions registers tmux backed sessions left by a previous daemon process
+func (s *SessionService) restorePersistentSessions() {
+	if !tmuxAvailable() {
+		return
+	}
+
+	output, err := tmuxCommand("list-sessions", "-F", "#{session_name}").Output()
+	if err != nil {
+		// No tmux server is running
+		return
+	}
+
+	for _, name := range strings.Fields(string(output)) {
+		sessionId, ok := sessionIdFromTmuxName(name)
+		if !ok {
+			continue
+		}
+
+		pid, err := tmuxPanePid(name)
+		if err != nil {
+			log.Warnf("Failed to restore persistent session %s: %v", sessionId, err)
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		session := &session{
+			id:         sessionId,
+			persistent: true,
+			shellPid:   pid,
+			commands:   cmap.New[*Command](),
+			ctx:        ctx,
+			cancel:     cancel,
+			env:        map[string]string{},
+		}
+		session.stdinWriter = &tmuxWriter{target: name, dir: session.Dir(s.configDir)}
+
+		if err := os.MkdirAll(session.Dir(s.configDir), 0755); err != nil {
+			cancel()
+			log.Warnf("Failed to restore persistent session %s: %v", sessionId, err)
+			continue
+		}
+
+		// Commands are restored from their directories
+		entries, _ := os.ReadDir(session.Dir(s.configDir))
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			command, err := restoreCommand(session.Dir(s.configDir), entry.Name())
+			if err != nil {
+				continue
+			}
+			session.commands.Set(command.Id, command)
+		}
+
+		if vars, err := env.ParseFile(filepath.Join(session.Dir(s.configDir), sessionEnvFileName)); err == nil {
+			session.env = vars
+		} else if !os.IsNotExist(err) {
+			log.Warnf("Failed to restore environment variables of persistent session %s: %v", sessionId, err)
+		}
+
+		s.sessions.Set(sessionId, session)
+		log.Infof("Restored persistent session %s with %d commands", sessionId, session.commands.Count())
+	}
+}
+
+// restoreCommand rebuilds a command of a session from its directory, with the exit code and the
+// termination reason of the command if it has exited
+func restoreCommand(sessionDir, cmdId string) (*Command, error) {
+	cmdDir := filepath.Join(sessionDir, cmdId)
+
+	content, err := os.ReadFile(filepath.Join(cmdDir, commandFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	command := &Command{
+		Id:      cmdId,
+		Command: string(content),
+	}
+
+	_, exitCodeFilePath := command.LogFilePath(sessionDir)
+	if exitCode, err := os.ReadFile(exitCodeFilePath); err == nil {
+		if exitCodeInt, err := strconv.Atoi(strings.TrimSpace(string(exitCode))); err == nil {
+			command.ExitCode = &exitCodeInt
+		}
+	}
+
+	if reason, err := os.ReadFile(filepath.Join(cmdDir, terminationReasonFileName)); err == nil {
+		terminationReason := strings.TrimSpace(string(reason))
+		command.TerminationReason = &terminationReason
+	}
+
+	return command, nil
+}
+
+// saveEnv stores the variables of a persistent session, so they can be restored with it
+func (s *SessionService) saveEnv(session *session) error {
+	if !session.persistent {
+		return nil
+	}
+	return os.WriteFile(filepath.Join(session.Dir(s.configDir), sessionEnvFileName), []byte(env.Format(session.env)), 0600)
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreCommand(t *testing.T) {
+	tests := []struct {
+		name              string
+		files             map[string]string
+		exitCode          *int
+		terminationReason *string
+		err               bool
+	}{
+		{
+			name:  "running command",
+			files: map[string]string{commandFileName: "sleep 10"},
+		},
+		{
+			name:     "exited command",
+			files:    map[string]string{commandFileName: "sleep 10", "exit_code": "3\n"},
+			exitCode: intPtr(3),
+		},
+		{
+			name: "terminated command",
+			files: map[string]string{
+				commandFileName:           "sleep 10",
+				"exit_code":               "143",
+				terminationReasonFileName: TerminationReasonTimeout,
+			},
+			exitCode:          intPtr(143),
+			terminationReason: stringPtr(TerminationReasonTimeout),
+		},
+		{
+			name:  "unparsable exit code",
+			files: map[string]string{commandFileName: "sleep 10", "exit_code": ""},
+		},
+		{
+			name:  "missing command file",
+			files: map[string]string{"exit_code": "0"},
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionDir := t.TempDir()
+			cmdDir := filepath.Join(sessionDir, "cmd")
+			require.NoError(t, os.Mkdir(cmdDir, 0755))
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(cmdDir, name), []byte(content), 0644))
+			}
+
+			command, err := restoreCommand(sessionDir, "cmd")
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "cmd", command.Id)
+			assert.Equal(t, "sleep 10", command.Command)
+			assert.Equal(t, tt.exitCode, command.ExitCode)
+			assert.Equal(t, tt.terminationReason, command.TerminationReason)
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os/exec"
 	"path/filepath"
+	"sync"
 
 	cmap "github.com/orcaman/concurrent-map/v2"
 )
@@ -19,12 +20,26 @@ var (
 )
 
 type session struct {
-	id          string
-	cmd         *exec.Cmd
+	id  string
+	cmd *exec.Cmd
+	// Persistent sessions run in tmux and have no cmd, only the pid of their shell
+	persistent  bool
+	shellPid    int
 	stdinWriter io.Writer
 	commands    cmap.ConcurrentMap[string, *Command]
 	ctx         context.Context
 	cancel      context.CancelFunc
+
+	// Variables set for this session on top of the sandbox defaults
+	envMu sync.Mutex
+	env   map[string]string
+}
+
+func (s *session) pid() int {
+	if s.cmd != nil && s.cmd.Process != nil {
+		return s.cmd.Process.Pid
+	}
+	return s.shellPid
 }
 
 func (s *session) Dir(configDir string) string {
@@ -35,6 +50,30 @@ type Command struct {
 	Id       string `json:"id" validate:"required"`
 	Command  string `json:"command" validate:"required"`
 	ExitCode *int   `json:"exitCode,omitempty" validate:"optional"`
+	// Set when the daemon terminated the command for exceeding one of its limits
+	TerminationReason *string `json:"terminationReason,omitempty" validate:"optional"`
+
+	// Guards ExitCode and TerminationReason, which are set while the command is being read
+	mu sync.RWMutex
+}
+
+// Result returns the exit code and the termination reason of the command, nil while it is running
+func (c *Command) Result() (*int, *string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ExitCode, c.TerminationReason
+}
+
+func (c *Command) setExitCode(exitCode int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ExitCode = &exitCode
+}
+
+func (c *Command) setTerminationReason(reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.TerminationReason = &reason
 }
 
 func (c *Command) LogFilePath(sessionDir string) (string, string) {
@@ -46,8 +85,17 @@ func (c *Command) InputFilePath(sessionDir string) string {
 }
 
 type Session struct {
-	SessionId string     `json:"sessionId" validate:"required"`
-	Commands  []*Command `json:"commands" validate:"required"`
+	SessionId  string     `json:"sessionId" validate:"required"`
+	Commands   []*Command `json:"commands" validate:"required"`
+	Persistent bool       `json:"persistent" validate:"required"`
+}
+
+type CreateOptions struct {
+	IsLegacy bool
+	// Variables set for the session on top of the sandbox defaults
+	Envs map[string]string
+	// Run the session in tmux so it survives daemon restarts
+	Persistent bool
 }
 
 type SessionExecute struct {
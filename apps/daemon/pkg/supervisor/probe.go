@@ -0,0 +1,53 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+
+	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/env"
+)
+
+func runProbe(probe *HealthProbe, cwd string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), probe.timeout())
+	defer cancel()
+
+	switch probe.Type {
+	case ProbeHTTP:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.Target, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+		return nil
+	case ProbeTCP:
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", probe.Target)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case ProbeExec:
+		cmd := exec.CommandContext(ctx, common.GetShell(), "-c", probe.Target)
+		cmd.Dir = cwd
+		cmd.Env = env.Environ()
+		return cmd.Run()
+	}
+
+	return fmt.Errorf("unknown probe type %q", probe.Type)
+}
@@ -0,0 +1,379 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/env"
+	"github.com/shirou/gopsutil/v4/process"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	maxLogSize       = 10 * 1024 * 1024
+	stopGracePeriod  = 10 * time.Second
+	minBackoff       = time.Second
+	maxBackoff       = 30 * time.Second
+	backoffResetTime = time.Minute
+)
+
+type service struct {
+	config ServiceConfig
+	dir    string
+
+	mu           sync.Mutex
+	state        ServiceState
+	health       Health
+	cmd          *exec.Cmd
+	restarts     int
+	startedAt    *time.Time
+	lastExitCode *int
+	lastError    string
+
+	// stopCh is closed to stop the run loop, done is closed once the run loop exited
+	stopCh chan struct{}
+	done   chan struct{}
+}
+
+func newService(config ServiceConfig, dir string) *service {
+	return &service{
+		config: config,
+		dir:    dir,
+		state:  StateStopped,
+		health: HealthUnknown,
+	}
+}
+
+func (s *service) status() *ServiceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := &ServiceStatus{
+		Config:       s.config,
+		State:        s.state,
+		Health:       s.health,
+		Restarts:     s.restarts,
+		StartedAt:    s.startedAt,
+		LastExitCode: s.lastExitCode,
+		LastError:    s.lastError,
+	}
+	if s.cmd != nil && s.cmd.Process != nil {
+		status.Pid = s.cmd.Process.Pid
+	}
+
+	return status
+}
+
+func (s *service) logFilePath() string {
+	return filepath.Join(s.dir, "output.log")
+}
+
+func (s *service) pidFilePath() string {
+	return filepath.Join(s.dir, "pid")
+}
+
+// start runs the service unless it is already running
+func (s *service) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done != nil {
+		select {
+		case <-s.done:
+		default:
+			return
+		}
+	}
+
+	s.restarts = 0
+	s.lastError = ""
+	s.stopCh = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.run(s.stopCh, s.done)
+}
+
+// stop terminates the service and waits until it exited
+func (s *service) stop() {
+	s.mu.Lock()
+	stopCh, done := s.stopCh, s.done
+	s.mu.Unlock()
+
+	if done == nil {
+		return
+	}
+
+	select {
+	case <-stopCh:
+	default:
+		close(stopCh)
+	}
+	<-done
+}
+
+func (s *service) setState(state ServiceState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+}
+
+func (s *service) run(stopCh, done chan struct{}) {
+	defer close(done)
+
+	backoff := minBackoff
+
+	for {
+		s.setState(StateStarting)
+
+		startedAt := time.Now()
+		exitCode, err := s.runOnce(stopCh)
+
+		select {
+		case <-stopCh:
+			s.setState(StateStopped)
+			return
+		default:
+		}
+
+		s.mu.Lock()
+		s.lastExitCode = exitCode
+		if err != nil {
+			s.lastError = err.Error()
+		}
+
+		succeeded := err == nil && exitCode != nil && *exitCode == 0
+		policy := s.config.restartPolicy()
+		if policy == RestartNever || (policy == RestartOnFailure && succeeded) {
+			s.state = StateStopped
+			if !succeeded {
+				s.state = StateFailed
+			}
+			s.mu.Unlock()
+			return
+		}
+
+		if s.config.MaxRestarts > 0 && s.restarts >= s.config.MaxRestarts {
+			s.state = StateFailed
+			s.lastError = fmt.Sprintf("gave up after %d restarts", s.restarts)
+			s.mu.Unlock()
+			log.Warnf("Service %s %s", s.config.Name, s.lastError)
+			return
+		}
+
+		s.restarts++
+		s.state = StateBackoff
+		s.mu.Unlock()
+
+		if time.Since(startedAt) > backoffResetTime {
+			backoff = minBackoff
+		}
+
+		log.Debugf("Restarting service %s in %s", s.config.Name, backoff)
+
+		select {
+		case <-stopCh:
+			s.setState(StateStopped)
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// runOnce starts the service process and waits for it to exit or to be stopped
+func (s *service) runOnce(stopCh chan struct{}) (*int, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	logFile, err := s.openLogFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(common.GetShell(), "-c", s.config.Command)
+	cmd.Dir = s.config.Cwd
+	cmd.Env = env.Merge(env.Environ(), s.config.Envs)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// Run in an own process group to be able to stop the whole service
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.cmd = cmd
+	s.state = StateRunning
+	s.health = HealthUnknown
+	s.startedAt = &now
+	s.mu.Unlock()
+
+	s.writePidFile(cmd.Process.Pid)
+	defer os.Remove(s.pidFilePath())
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	unhealthy := make(chan struct{})
+	probeStop := make(chan struct{})
+	defer close(probeStop)
+	if s.config.HealthProbe != nil {
+		go s.probe(probeStop, unhealthy)
+	}
+
+	var waitErr error
+	select {
+	case waitErr = <-exited:
+	case <-stopCh:
+		waitErr = s.terminate(cmd, exited)
+	case <-unhealthy:
+		log.Warnf("Service %s is unhealthy, restarting it", s.config.Name)
+		s.terminate(cmd, exited)
+		waitErr = errors.New("health probe failed")
+	}
+
+	exitCode := cmd.ProcessState.ExitCode()
+
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		waitErr = nil
+	}
+
+	return &exitCode, waitErr
+}
+
+func (s *service) terminate(cmd *exec.Cmd, exited chan error) error {
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+
+	select {
+	case err := <-exited:
+		return err
+	case <-time.After(stopGracePeriod):
+	}
+
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	return <-exited
+}
+
+func (s *service) probe(stop chan struct{}, unhealthy chan struct{}) {
+	probe := s.config.HealthProbe
+	failures := 0
+
+	ticker := time.NewTicker(probe.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := runProbe(probe, s.config.Cwd)
+
+		s.mu.Lock()
+		if err == nil {
+			failures = 0
+			s.health = HealthHealthy
+			s.mu.Unlock()
+			continue
+		}
+
+		failures++
+		log.Debugf("Health probe of service %s failed: %v", s.config.Name, err)
+		if failures < probe.failureThreshold() {
+			s.mu.Unlock()
+			continue
+		}
+
+		s.health = HealthUnhealthy
+		s.lastError = fmt.Sprintf("health probe failed: %v", err)
+		restart := s.config.restartPolicy() != RestartNever
+		s.mu.Unlock()
+
+		if restart {
+			close(unhealthy)
+			return
+		}
+	}
+}
+
+// openLogFile opens the log for appending, rotating it once it gets too large
+func (s *service) openLogFile() (*os.File, error) {
+	path := s.logFilePath()
+	if info, err := os.Stat(path); err == nil && info.Size() > maxLogSize {
+		_ = os.Rename(path, path+".1")
+	}
+
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
+func (s *service) writePidFile(pid int) {
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return
+	}
+	created, err := proc.CreateTime()
+	if err != nil {
+		return
+	}
+
+	content := fmt.Sprintf("%d %d", pid, created)
+	if err := os.WriteFile(s.pidFilePath(), []byte(content), 0644); err != nil {
+		log.Warnf("Failed to write pid file of service %s: %v", s.config.Name, err)
+	}
+}
+
+// killStale kills the process group of the service left by a previous daemon process
+func (s *service) killStale() {
+	content, err := os.ReadFile(s.pidFilePath())
+	if err != nil {
+		return
+	}
+	defer os.Remove(s.pidFilePath())
+
+	fields := strings.Fields(string(content))
+	if len(fields) != 2 {
+		return
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return
+	}
+	created, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return
+	}
+
+	// Make sure the pid was not reused by an unrelated process
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return
+	}
+	if actual, err := proc.CreateTime(); err != nil || actual != created {
+		return
+	}
+
+	log.Infof("Stopping service %s left running by a previous daemon", s.config.Name)
+	_ = syscall.Kill(-pid, syscall.SIGKILL)
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package supervisor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Supervisor runs registered services, restarts them according to their restart policy and
+// starts them again when the daemon starts. Service configs are stored in the config dir.
+type Supervisor struct {
+	configDir string
+	mu        sync.Mutex
+	services  map[string]*service
+}
+
+func NewSupervisor(configDir string) *Supervisor {
+	s := &Supervisor{
+		configDir: configDir,
+		services:  map[string]*service{},
+	}
+
+	configs, err := s.loadConfigs()
+	if err != nil {
+		log.Errorf("Failed to load supervised services: %v", err)
+	}
+
+	for _, config := range configs {
+		svc := newService(config, s.serviceDir(config.Name))
+		s.services[config.Name] = svc
+
+		// A service may still be running if the daemon was restarted
+		svc.killStale()
+
+		if config.autostart() {
+			svc.start()
+		}
+	}
+
+	return s
+}
+
+// Register adds a service or replaces the config of an existing one and (re)starts it
+func (s *Supervisor) Register(config ServiceConfig) (*ServiceStatus, error) {
+	if err := config.Validate(); err != nil {
+		return nil, common_errors.NewBadRequestError(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.services[config.Name]; ok {
+		existing.stop()
+	}
+
+	svc := newService(config, s.serviceDir(config.Name))
+	s.services[config.Name] = svc
+
+	if err := s.saveConfigs(); err != nil {
+		return nil, err
+	}
+
+	svc.start()
+
+	return svc.status(), nil
+}
+
+func (s *Supervisor) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	svc, ok := s.services[name]
+	if !ok {
+		return common_errors.NewNotFoundError(errors.New("service not found"))
+	}
+
+	svc.stop()
+	delete(s.services, name)
+
+	if err := os.RemoveAll(s.serviceDir(name)); err != nil {
+		log.Warnf("Failed to remove directory of service %s: %v", name, err)
+	}
+
+	return s.saveConfigs()
+}
+
+func (s *Supervisor) Get(name string) (*ServiceStatus, error) {
+	svc, err := s.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.status(), nil
+}
+
+func (s *Supervisor) List() []*ServiceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]*ServiceStatus, 0, len(s.services))
+	for _, svc := range s.services {
+		statuses = append(statuses, svc.status())
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Config.Name < statuses[j].Config.Name
+	})
+
+	return statuses
+}
+
+func (s *Supervisor) Start(name string) (*ServiceStatus, error) {
+	svc, err := s.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.start()
+	return svc.status(), nil
+}
+
+func (s *Supervisor) Stop(name string) (*ServiceStatus, error) {
+	svc, err := s.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.stop()
+	return svc.status(), nil
+}
+
+func (s *Supervisor) Restart(name string) (*ServiceStatus, error) {
+	svc, err := s.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.stop()
+	svc.start()
+	return svc.status(), nil
+}
+
+// Logs returns the last lines of the service output, all of it if tail is not positive
+func (s *Supervisor) Logs(name string, tail int) (string, error) {
+	svc, err := s.get(name)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(svc.logFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	offset := max(info.Size()-maxLogSize, 0)
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	if tail <= 0 {
+		return string(content), nil
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+
+	return strings.Join(lines, ""), nil
+}
+
+func (s *Supervisor) get(name string) (*service, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	svc, ok := s.services[name]
+	if !ok {
+		return nil, common_errors.NewNotFoundError(errors.New("service not found"))
+	}
+
+	return svc, nil
+}
+
+func (s *Supervisor) serviceDir(name string) string {
+	return filepath.Join(s.configDir, "services", name)
+}
+
+func (s *Supervisor) configsPath() string {
+	return filepath.Join(s.configDir, "services.json")
+}
+
+func (s *Supervisor) loadConfigs() ([]ServiceConfig, error) {
+	content, err := os.ReadFile(s.configsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var configs []ServiceConfig
+	if err := json.Unmarshal(content, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.configsPath(), err)
+	}
+
+	return configs, nil
+}
+
+func (s *Supervisor) saveConfigs() error {
+	configs := make([]ServiceConfig, 0, len(s.services))
+	for _, svc := range s.services {
+		configs = append(configs, svc.config)
+	}
+
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Name < configs[j].Name
+	})
+
+	content, err := json.MarshalIndent(configs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.configDir, 0755); err != nil {
+		return err
+	}
+
+	tmpPath := s.configsPath() + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, s.configsPath())
+}
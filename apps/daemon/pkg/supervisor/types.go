@@ -0,0 +1,153 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/env"
+)
+
+type RestartPolicy string
+
+const (
+	RestartAlways    RestartPolicy = "always"
+	RestartOnFailure RestartPolicy = "on-failure"
+	RestartNever     RestartPolicy = "never"
+)
+
+type ProbeType string
+
+const (
+	ProbeHTTP ProbeType = "http"
+	ProbeTCP  ProbeType = "tcp"
+	ProbeExec ProbeType = "exec"
+)
+
+type ServiceState string
+
+const (
+	StateStarting ServiceState = "starting"
+	StateRunning  ServiceState = "running"
+	StateBackoff  ServiceState = "backoff"
+	StateStopped  ServiceState = "stopped"
+	StateFailed   ServiceState = "failed"
+)
+
+type Health string
+
+const (
+	HealthUnknown   Health = "unknown"
+	HealthHealthy   Health = "healthy"
+	HealthUnhealthy Health = "unhealthy"
+)
+
+type HealthProbe struct {
+	Type ProbeType `json:"type"`
+	// URL for http probes, host:port for tcp probes and a shell command for exec probes
+	Target           string `json:"target"`
+	IntervalSeconds  int    `json:"intervalSeconds,omitempty"`
+	TimeoutSeconds   int    `json:"timeoutSeconds,omitempty"`
+	FailureThreshold int    `json:"failureThreshold,omitempty"`
+}
+
+func (p *HealthProbe) interval() time.Duration {
+	if p.IntervalSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(p.IntervalSeconds) * time.Second
+}
+
+func (p *HealthProbe) timeout() time.Duration {
+	if p.TimeoutSeconds <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(p.TimeoutSeconds) * time.Second
+}
+
+func (p *HealthProbe) failureThreshold() int {
+	if p.FailureThreshold <= 0 {
+		return 3
+	}
+	return p.FailureThreshold
+}
+
+type ServiceConfig struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command"`
+	Cwd     string            `json:"cwd,omitempty"`
+	Envs    map[string]string `json:"envs,omitempty"`
+	// Defaults to RestartOnFailure
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+	// Restarts after which the service is marked as failed, 0 means no limit
+	MaxRestarts int          `json:"maxRestarts,omitempty"`
+	HealthProbe *HealthProbe `json:"healthProbe,omitempty"`
+	// Start the service when the daemon starts, defaults to true
+	Autostart *bool `json:"autostart,omitempty"`
+}
+
+var serviceNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$`)
+
+func (c *ServiceConfig) Validate() error {
+	if !serviceNameRegex.MatchString(c.Name) {
+		return fmt.Errorf("invalid service name %q", c.Name)
+	}
+	if c.Command == "" {
+		return errors.New("command is required")
+	}
+
+	switch c.RestartPolicy {
+	case "", RestartAlways, RestartOnFailure, RestartNever:
+	default:
+		return fmt.Errorf("invalid restart policy %q", c.RestartPolicy)
+	}
+
+	if c.MaxRestarts < 0 {
+		return errors.New("maxRestarts must not be negative")
+	}
+
+	for name := range c.Envs {
+		if err := env.ValidateName(name); err != nil {
+			return err
+		}
+	}
+
+	if c.HealthProbe != nil {
+		switch c.HealthProbe.Type {
+		case ProbeHTTP, ProbeTCP, ProbeExec:
+		default:
+			return fmt.Errorf("invalid health probe type %q", c.HealthProbe.Type)
+		}
+		if c.HealthProbe.Target == "" {
+			return errors.New("health probe target is required")
+		}
+	}
+
+	return nil
+}
+
+func (c *ServiceConfig) restartPolicy() RestartPolicy {
+	if c.RestartPolicy == "" {
+		return RestartOnFailure
+	}
+	return c.RestartPolicy
+}
+
+func (c *ServiceConfig) autostart() bool {
+	return c.Autostart == nil || *c.Autostart
+}
+
+type ServiceStatus struct {
+	Config       ServiceConfig `json:"config"`
+	State        ServiceState  `json:"state"`
+	Health       Health        `json:"health"`
+	Pid          int           `json:"pid,omitempty"`
+	Restarts     int           `json:"restarts"`
+	StartedAt    *time.Time    `json:"startedAt,omitempty"`
+	LastExitCode *int          `json:"lastExitCode,omitempty"`
+	LastError    string        `json:"lastError,omitempty"`
+}
@@ -4,6 +4,7 @@
 package computeruse
 
 import (
+	"fmt"
 	"net/http"
 	"net/rpc"
 	"strconv"
@@ -45,6 +46,17 @@ type IComputerUse interface {
 	// Display info methods
 	GetDisplayInfo() (*DisplayInfoResponse, error)
 	GetWindows() (*WindowsResponse, error)
+	AddDisplay(*AddDisplayRequest) (*DisplayInfoResponse, error)
+	ResizeDisplay(*ResizeDisplayRequest) (*DisplayInfoResponse, error)
+
+	// Element lookup methods
+	GetAccessibilityTree(*AccessibilityTreeRequest) (*AccessibilityTreeResponse, error)
+	RecognizeText(*OcrRequest) (*OcrResponse, error)
+	ClickElement(*ClickElementRequest) (*ClickElementResponse, error)
+
+	// Clipboard methods
+	GetClipboard(*ClipboardRequest) (*ClipboardResponse, error)
+	SetClipboard(*SetClipboardRequest) (*Empty, error)
 
 	// Status method
 	GetStatus() (*ComputerUseStatusResponse, error)
@@ -68,68 +80,79 @@ type Size struct {
 // Screenshot parameter structs
 type ScreenshotRequest struct {
 	ShowCursor bool `json:"showCursor"`
+	Display    *int `json:"display,omitempty"` // target display, coordinates are relative to it
 } //	@name	ScreenshotRequest
 
 type RegionScreenshotRequest struct {
 	Position
 	Size
 	ShowCursor bool `json:"showCursor"`
+	Display    *int `json:"display,omitempty"` // target display, coordinates are relative to it
 } //	@name	RegionScreenshotRequest
 
 type CompressedScreenshotRequest struct {
 	ShowCursor bool    `json:"showCursor"`
-	Format     string  `json:"format"`  // "png" or "jpeg"
-	Quality    int     `json:"quality"` // 1-100 for JPEG quality
-	Scale      float64 `json:"scale"`   // 0.1-1.0 for scaling down
+	Format     string  `json:"format"`            // "png" or "jpeg"
+	Quality    int     `json:"quality"`           // 1-100 for JPEG quality
+	Scale      float64 `json:"scale"`             // 0.1-1.0 for scaling down
+	Display    *int    `json:"display,omitempty"` // target display, coordinates are relative to it
 } //	@name	CompressedScreenshotRequest
 
 type CompressedRegionScreenshotRequest struct {
 	Position
 	Size
 	ShowCursor bool    `json:"showCursor"`
-	Format     string  `json:"format"`  // "png" or "jpeg"
-	Quality    int     `json:"quality"` // 1-100 for JPEG quality
-	Scale      float64 `json:"scale"`   // 0.1-1.0 for scaling down
+	Format     string  `json:"format"`            // "png" or "jpeg"
+	Quality    int     `json:"quality"`           // 1-100 for JPEG quality
+	Scale      float64 `json:"scale"`             // 0.1-1.0 for scaling down
+	Display    *int    `json:"display,omitempty"` // target display, coordinates are relative to it
 } //	@name	CompressedRegionScreenshotRequest
 
 // Mouse parameter structs
 type MouseMoveRequest struct {
 	Position
+	Display *int `json:"display,omitempty"` // target display, coordinates are relative to it
 } //	@name	MouseMoveRequest
 
 type MouseClickRequest struct {
 	Position
-	Button string `json:"button"` // left, right, middle
-	Double bool   `json:"double"`
+	Button  string `json:"button"` // left, right, middle
+	Double  bool   `json:"double"`
+	Display *int   `json:"display,omitempty"` // target display, coordinates are relative to it
 } //	@name	MouseClickRequest
 
 type MouseDragRequest struct {
-	StartX int    `json:"startX"`
-	StartY int    `json:"startY"`
-	EndX   int    `json:"endX"`
-	EndY   int    `json:"endY"`
-	Button string `json:"button"`
+	StartX  int    `json:"startX"`
+	StartY  int    `json:"startY"`
+	EndX    int    `json:"endX"`
+	EndY    int    `json:"endY"`
+	Button  string `json:"button"`
+	Display *int   `json:"display,omitempty"` // target display, coordinates are relative to it
 } //	@name	MouseDragRequest
 
 type MouseScrollRequest struct {
 	Position
 	Direction string `json:"direction"` // up, down
 	Amount    int    `json:"amount"`
+	Display   *int   `json:"display,omitempty"` // target display, coordinates are relative to it
 } //	@name	MouseScrollRequest
 
 // Keyboard parameter structs
 type KeyboardTypeRequest struct {
-	Text  string `json:"text"`
-	Delay int    `json:"delay"` // milliseconds between keystrokes
+	Text    string `json:"text"`
+	Delay   int    `json:"delay"`             // milliseconds between keystrokes
+	Display *int   `json:"display,omitempty"` // target display, the pointer is moved onto it
 } //	@name	KeyboardTypeRequest
 
 type KeyboardPressRequest struct {
 	Key       string   `json:"key"`
-	Modifiers []string `json:"modifiers"` // ctrl, alt, shift, cmd
+	Modifiers []string `json:"modifiers"`         // ctrl, alt, shift, cmd
+	Display   *int     `json:"display,omitempty"` // target display, the pointer is moved onto it
 } //	@name	KeyboardPressRequest
 
 type KeyboardHotkeyRequest struct {
-	Keys string `json:"keys"` // e.g., "ctrl+c", "cmd+v"
+	Keys    string `json:"keys"`              // e.g., "ctrl+c", "cmd+v"
+	Display *int   `json:"display,omitempty"` // target display, the pointer is moved onto it
 } //	@name	KeyboardHotkeyRequest
 
 // Response structs for keyboard operations
@@ -168,6 +191,92 @@ type DisplayInfo struct {
 	IsActive bool `json:"isActive"`
 } //	@name	DisplayInfo
 
+type AddDisplayRequest struct {
+	Size
+} //	@name	AddDisplayRequest
+
+type ResizeDisplayRequest struct {
+	ID int `json:"id"`
+	Size
+} //	@name	ResizeDisplayRequest
+
+// Element lookup structs
+// Only nodes intersecting the region are returned, the whole screen if the size is empty
+type AccessibilityTreeRequest struct {
+	Position
+	Size
+	MaxDepth int `json:"maxDepth"` // 0 for no limit
+} //	@name	AccessibilityTreeRequest
+
+type AccessibilityNode struct {
+	Role        string `json:"role"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Position
+	Size
+	States   []string            `json:"states,omitempty"`
+	Children []AccessibilityNode `json:"children,omitempty"`
+} //	@name	AccessibilityNode
+
+type AccessibilityTreeResponse struct {
+	Nodes []AccessibilityNode `json:"nodes"`
+} //	@name	AccessibilityTreeResponse
+
+// The region to recognize, the whole display if the size is empty
+type OcrRequest struct {
+	Position
+	Size
+	Display  *int   `json:"display,omitempty"`  // target display, coordinates are relative to it
+	Language string `json:"language,omitempty"` // tesseract language, e.g. "eng"
+} //	@name	OcrRequest
+
+type OcrWord struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"` // 0-100
+	Position
+	Size
+} //	@name	OcrWord
+
+type OcrResponse struct {
+	Text  string    `json:"text"`
+	Words []OcrWord `json:"words"`
+} //	@name	OcrResponse
+
+type ClickElementRequest struct {
+	Text   string `json:"text,omitempty"`   // case-insensitive substring of the element name or text
+	Role   string `json:"role,omitempty"`   // accessibility role, e.g. "push button"
+	Index  int    `json:"index,omitempty"`  // which of the matching elements to click
+	Source string `json:"source,omitempty"` // accessibility, ocr or empty to try both
+	Button string `json:"button,omitempty"` // left, right, middle
+	Double bool   `json:"double,omitempty"`
+} //	@name	ClickElementRequest
+
+type ClickElementResponse struct {
+	Source string `json:"source"` // accessibility or ocr
+	Name   string `json:"name"`
+	Role   string `json:"role,omitempty"`
+	Position
+	Size
+} //	@name	ClickElementResponse
+
+// Clipboard structs
+type ClipboardRequest struct {
+	MimeType string `json:"mimeType"` // defaults to text/plain
+} //	@name	ClipboardRequest
+
+type ClipboardResponse struct {
+	MimeType       string   `json:"mimeType"`
+	Text           string   `json:"text,omitempty"` // content of text/* types
+	Data           string   `json:"data,omitempty"` // base64 encoded content of other types
+	AvailableTypes []string `json:"availableTypes"`
+} //	@name	ClipboardResponse
+
+type SetClipboardRequest struct {
+	MimeType string `json:"mimeType"`       // defaults to text/plain
+	Text     string `json:"text,omitempty"` // content of text/* types
+	Data     string `json:"data,omitempty"` // base64 encoded content of other types, e.g. image/png
+} //	@name	SetClipboardRequest
+
 type WindowsResponse struct {
 	Windows []WindowInfo `json:"windows"`
 } //	@name	WindowsResponse
@@ -242,14 +351,22 @@ func (p *ComputerUsePlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (any, err
 //	@Tags			computer-use
 //	@Produce		json
 //	@Param			showCursor	query		bool	false	"Whether to show cursor in screenshot"
+//	@Param			display		query		int		false	"Display to capture, coordinates are relative to it"
 //	@Success		200			{object}	ScreenshotResponse
 //	@Router			/computeruse/screenshot [get]
 //
 //	@id				TakeScreenshot
 func WrapScreenshotHandler(fn func(*ScreenshotRequest) (*ScreenshotResponse, error)) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		display, err := displayQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		req := &ScreenshotRequest{
 			ShowCursor: c.Query("showCursor") == "true",
+			Display:    display,
 		}
 		response, err := fn(req)
 		if err != nil {
@@ -271,6 +388,7 @@ func WrapScreenshotHandler(fn func(*ScreenshotRequest) (*ScreenshotResponse, err
 //	@Param			width		query		int		true	"Width of the region"
 //	@Param			height		query		int		true	"Height of the region"
 //	@Param			showCursor	query		bool	false	"Whether to show cursor in screenshot"
+//	@Param			display		query		int		false	"Display to capture, coordinates are relative to it"
 //	@Success		200			{object}	ScreenshotResponse
 //	@Router			/computeruse/screenshot/region [get]
 //
@@ -284,6 +402,13 @@ func WrapRegionScreenshotHandler(fn func(*RegionScreenshotRequest) (*ScreenshotR
 		}
 		req.ShowCursor = c.Query("showCursor") == "true"
 
+		display, err := displayQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		req.Display = display
+
 		response, err := fn(&req)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -303,17 +428,25 @@ func WrapRegionScreenshotHandler(fn func(*RegionScreenshotRequest) (*ScreenshotR
 //	@Param			format		query		string	false	"Image format (png or jpeg)"
 //	@Param			quality		query		int		false	"JPEG quality (1-100)"
 //	@Param			scale		query		float64	false	"Scale factor (0.1-1.0)"
+//	@Param			display		query		int		false	"Display to capture, coordinates are relative to it"
 //	@Success		200			{object}	ScreenshotResponse
 //	@Router			/computeruse/screenshot/compressed [get]
 //
 //	@id				TakeCompressedScreenshot
 func WrapCompressedScreenshotHandler(fn func(*CompressedScreenshotRequest) (*ScreenshotResponse, error)) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		display, err := displayQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		req := &CompressedScreenshotRequest{
 			ShowCursor: c.Query("showCursor") == "true",
 			Format:     c.Query("format"),
 			Quality:    85,
 			Scale:      1.0,
+			Display:    display,
 		}
 
 		// Parse quality
@@ -353,6 +486,7 @@ func WrapCompressedScreenshotHandler(fn func(*CompressedScreenshotRequest) (*Scr
 //	@Param			format		query		string	false	"Image format (png or jpeg)"
 //	@Param			quality		query		int		false	"JPEG quality (1-100)"
 //	@Param			scale		query		float64	false	"Scale factor (0.1-1.0)"
+//	@Param			display		query		int		false	"Display to capture, coordinates are relative to it"
 //	@Success		200			{object}	ScreenshotResponse
 //	@Router			/computeruse/screenshot/region/compressed [get]
 //
@@ -369,6 +503,13 @@ func WrapCompressedRegionScreenshotHandler(fn func(*CompressedRegionScreenshotRe
 		req.Quality = 85
 		req.Scale = 1.0
 
+		display, err := displayQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		req.Display = display
+
 		// Parse quality
 		if qualityStr := c.Query("quality"); qualityStr != "" {
 			if quality, err := strconv.Atoi(qualityStr); err == nil && quality >= 1 && quality <= 100 {
@@ -658,6 +799,242 @@ func WrapWindowsHandler(fn func() (*WindowsResponse, error)) gin.HandlerFunc {
 	}
 }
 
+// AddDisplay godoc
+//
+//	@Summary		Add a virtual display
+//	@Description	Add a virtual display to the right of the existing ones. The desktop is restarted to apply the new layout
+//	@Tags			computer-use
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		AddDisplayRequest	true	"Add display request"
+//	@Success		200		{object}	DisplayInfoResponse
+//	@Router			/computeruse/display [post]
+//
+//	@id				AddDisplay
+func WrapAddDisplayHandler(fn func(*AddDisplayRequest) (*DisplayInfoResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AddDisplayRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid display size"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ResizeDisplay godoc
+//
+//	@Summary		Resize a virtual display
+//	@Description	Change the resolution of a virtual display. The desktop is restarted to apply the new layout
+//	@Tags			computer-use
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int			true	"Display ID"
+//	@Param			request	body		Size		true	"New display size"
+//	@Success		200		{object}	DisplayInfoResponse
+//	@Router			/computeruse/display/{id}/resize [post]
+//
+//	@id				ResizeDisplay
+func WrapResizeDisplayHandler(fn func(*ResizeDisplayRequest) (*DisplayInfoResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid display ID"})
+			return
+		}
+
+		var size Size
+		if err := c.ShouldBindJSON(&size); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid display size"})
+			return
+		}
+
+		response, err := fn(&ResizeDisplayRequest{ID: id, Size: size})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetAccessibilityTree godoc
+//
+//	@Summary		Get accessibility tree
+//	@Description	Get the accessibility (AT-SPI) tree of the applications on screen, optionally limited to a region
+//	@Tags			computer-use
+//	@Produce		json
+//	@Param			x			query		int	false	"X coordinate of the region"
+//	@Param			y			query		int	false	"Y coordinate of the region"
+//	@Param			width		query		int	false	"Width of the region"
+//	@Param			height		query		int	false	"Height of the region"
+//	@Param			maxDepth	query		int	false	"Maximum depth of the tree"
+//	@Success		200			{object}	AccessibilityTreeResponse
+//	@Router			/computeruse/accessibility/tree [get]
+//
+//	@id				GetAccessibilityTree
+func WrapAccessibilityTreeHandler(fn func(*AccessibilityTreeRequest) (*AccessibilityTreeResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AccessibilityTreeRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parameters"})
+			return
+		}
+
+		if maxDepthStr := c.Query("maxDepth"); maxDepthStr != "" {
+			maxDepth, err := strconv.Atoi(maxDepthStr)
+			if err != nil || maxDepth < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maxDepth"})
+				return
+			}
+			req.MaxDepth = maxDepth
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// RecognizeText godoc
+//
+//	@Summary		Recognize text on screen
+//	@Description	Run OCR on the screen or a region and return the recognized words with their bounding boxes
+//	@Tags			computer-use
+//	@Produce		json
+//	@Param			x			query		int		false	"X coordinate of the region"
+//	@Param			y			query		int		false	"Y coordinate of the region"
+//	@Param			width		query		int		false	"Width of the region"
+//	@Param			height		query		int		false	"Height of the region"
+//	@Param			display		query		int		false	"Display to capture, coordinates are relative to it"
+//	@Param			language	query		string	false	"OCR language, e.g. eng"
+//	@Success		200			{object}	OcrResponse
+//	@Router			/computeruse/ocr [get]
+//
+//	@id				RecognizeText
+func WrapOcrHandler(fn func(*OcrRequest) (*OcrResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req OcrRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parameters"})
+			return
+		}
+		req.Language = c.Query("language")
+
+		display, err := displayQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		req.Display = display
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ClickElement godoc
+//
+//	@Summary		Click an element
+//	@Description	Find an element by its text and/or accessibility role and click its center
+//	@Tags			computer-use
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ClickElementRequest	true	"Element query"
+//	@Success		200		{object}	ClickElementResponse
+//	@Router			/computeruse/element/click [post]
+//
+//	@id				ClickElement
+func WrapClickElementHandler(fn func(*ClickElementRequest) (*ClickElementResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ClickElementRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid element query"})
+			return
+		}
+
+		if req.Text == "" && req.Role == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "text or role is required"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetClipboard godoc
+//
+//	@Summary		Get clipboard content
+//	@Description	Get the content of the clipboard in the requested MIME type, e.g. text/plain or image/png
+//	@Tags			computer-use
+//	@Produce		json
+//	@Param			mimeType	query		string	false	"MIME type of the content (default text/plain)"
+//	@Success		200			{object}	ClipboardResponse
+//	@Router			/computeruse/clipboard [get]
+//
+//	@id				GetClipboard
+func WrapGetClipboardHandler(fn func(*ClipboardRequest) (*ClipboardResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := &ClipboardRequest{
+			MimeType: c.Query("mimeType"),
+		}
+
+		response, err := fn(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// SetClipboard godoc
+//
+//	@Summary		Set clipboard content
+//	@Description	Set the content of the clipboard, text for text/* types and base64 encoded data for other types
+//	@Tags			computer-use
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SetClipboardRequest	true	"Clipboard content"
+//	@Success		200		{object}	Empty
+//	@Router			/computeruse/clipboard [post]
+//
+//	@id				SetClipboard
+func WrapSetClipboardHandler(fn func(*SetClipboardRequest) (*Empty, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req SetClipboardRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clipboard content"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 // GetStatus godoc
 //
 //	@Summary		Get computer use status
@@ -678,3 +1055,18 @@ func WrapStatusHandler(fn func() (*ComputerUseStatusResponse, error)) gin.Handle
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+// displayQuery parses the optional display query parameter
+func displayQuery(c *gin.Context) (*int, error) {
+	value := c.Query("display")
+	if value == "" {
+		return nil, nil
+	}
+
+	display, err := strconv.Atoi(value)
+	if err != nil || display < 0 {
+		return nil, fmt.Errorf("invalid display %q", value)
+	}
+
+	return &display, nil
+}
@@ -0,0 +1,517 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MacroActionMove         = "move"
+	MacroActionClick        = "click"
+	MacroActionDrag         = "drag"
+	MacroActionScroll       = "scroll"
+	MacroActionType         = "type"
+	MacroActionKey          = "key"
+	MacroActionHotkey       = "hotkey"
+	MacroActionAssertPixel  = "assert_pixel"
+	MacroActionAssertRegion = "assert_region"
+)
+
+type MacroAssertion struct {
+	Position
+	// Size of the region, only for region assertions
+	Size
+	// Expected color as #rrggbb, only for pixel assertions
+	Color string `json:"color,omitempty"`
+	// Expected region as a base64 encoded PNG, only for region assertions
+	Image string `json:"image,omitempty"`
+	// Maximum difference per color channel for a pixel to match (0-255)
+	Tolerance int `json:"tolerance,omitempty"`
+	// Percentage of region pixels allowed to not match
+	MaxMismatchPercent float64 `json:"maxMismatchPercent,omitempty"`
+	// Time in milliseconds to keep retrying the assertion until it matches
+	TimeoutMs int `json:"timeoutMs,omitempty"`
+} //	@name	MacroAssertion
+
+type MacroAction struct {
+	Type string `json:"type"`
+	// Time in milliseconds to wait before the action, as recorded
+	DelayMs   int                    `json:"delayMs"`
+	Move      *MouseMoveRequest      `json:"move,omitempty"`
+	Click     *MouseClickRequest     `json:"click,omitempty"`
+	Drag      *MouseDragRequest      `json:"drag,omitempty"`
+	Scroll    *MouseScrollRequest    `json:"scroll,omitempty"`
+	Text      *KeyboardTypeRequest   `json:"text,omitempty"`
+	Key       *KeyboardPressRequest  `json:"key,omitempty"`
+	Hotkey    *KeyboardHotkeyRequest `json:"hotkey,omitempty"`
+	Assertion *MacroAssertion        `json:"assertion,omitempty"`
+} //	@name	MacroAction
+
+type Macro struct {
+	Name      string        `json:"name"`
+	Actions   []MacroAction `json:"actions"`
+	CreatedAt time.Time     `json:"createdAt"`
+} //	@name	Macro
+
+type MacroStepResult struct {
+	Index   int    `json:"index"`
+	Type    string `json:"type"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	// Percentage of pixels that did not match, for assertions
+	MismatchPercent *float64 `json:"mismatchPercent,omitempty"`
+} //	@name	MacroStepResult
+
+type MacroReplayResponse struct {
+	Success    bool              `json:"success"`
+	Steps      []MacroStepResult `json:"steps"`
+	DurationMs int64             `json:"durationMs"`
+} //	@name	MacroReplayResponse
+
+var macroNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+// MacroRecorder records the mouse and keyboard actions sent through it while a recording is
+// active and replays stored macros. Macros are stored as JSON files.
+type MacroRecorder struct {
+	IComputerUse
+	dir string
+
+	mu           sync.Mutex
+	recording    *Macro
+	lastActionAt time.Time
+	replaying    bool
+}
+
+func NewMacroRecorder(computerUse IComputerUse, configDir string) *MacroRecorder {
+	return &MacroRecorder{
+		IComputerUse: computerUse,
+		dir:          filepath.Join(configDir, "macros"),
+	}
+}
+
+func (m *MacroRecorder) StartRecording(name string) error {
+	if !macroNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid macro name %q", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.recording != nil {
+		return fmt.Errorf("macro %s is already being recorded", m.recording.Name)
+	}
+
+	m.recording = &Macro{
+		Name:      name,
+		Actions:   []MacroAction{},
+		CreatedAt: time.Now(),
+	}
+	m.lastActionAt = time.Now()
+
+	return nil
+}
+
+func (m *MacroRecorder) StopRecording() (*Macro, error) {
+	m.mu.Lock()
+	macro := m.recording
+	m.recording = nil
+	m.mu.Unlock()
+
+	if macro == nil {
+		return nil, errors.New("no macro is being recorded")
+	}
+
+	if err := m.SaveMacro(macro); err != nil {
+		return nil, err
+	}
+
+	return macro, nil
+}
+
+// RecordAssertion adds an assertion to the macro being recorded. Without an expected color or
+// image, the current screen content is captured as the expected value.
+func (m *MacroRecorder) RecordAssertion(assertion MacroAssertion) (*MacroAction, error) {
+	action := MacroAction{
+		Type:      MacroActionAssertPixel,
+		Assertion: &assertion,
+	}
+	if assertion.Width > 0 || assertion.Height > 0 || assertion.Image != "" {
+		action.Type = MacroActionAssertRegion
+	}
+
+	if action.Type == MacroActionAssertPixel && assertion.Color == "" {
+		img, err := m.captureRegion(assertion.Position, Size{Width: 1, Height: 1})
+		if err != nil {
+			return nil, err
+		}
+		r, g, b, _ := img.At(img.Bounds().Min.X, img.Bounds().Min.Y).RGBA()
+		assertion.Color = fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	}
+
+	if action.Type == MacroActionAssertRegion && assertion.Image == "" {
+		screenshot, err := m.TakeRegionScreenshot(&RegionScreenshotRequest{Position: assertion.Position, Size: assertion.Size})
+		if err != nil {
+			return nil, err
+		}
+		assertion.Image = screenshot.Screenshot
+	}
+
+	if !m.record(action) {
+		return nil, errors.New("no macro is being recorded")
+	}
+
+	return &action, nil
+}
+
+func (m *MacroRecorder) record(action MacroAction) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.recording == nil || m.replaying {
+		return false
+	}
+
+	action.DelayMs = int(time.Since(m.lastActionAt).Milliseconds())
+	m.lastActionAt = time.Now()
+	m.recording.Actions = append(m.recording.Actions, action)
+
+	return true
+}
+
+// Recorded actions
+
+func (m *MacroRecorder) MoveMouse(req *MouseMoveRequest) (*MousePositionResponse, error) {
+	resp, err := m.IComputerUse.MoveMouse(req)
+	if err == nil {
+		m.record(MacroAction{Type: MacroActionMove, Move: req})
+	}
+	return resp, err
+}
+
+func (m *MacroRecorder) Click(req *MouseClickRequest) (*MouseClickResponse, error) {
+	resp, err := m.IComputerUse.Click(req)
+	if err == nil {
+		m.record(MacroAction{Type: MacroActionClick, Click: req})
+	}
+	return resp, err
+}
+
+func (m *MacroRecorder) Drag(req *MouseDragRequest) (*MouseDragResponse, error) {
+	resp, err := m.IComputerUse.Drag(req)
+	if err == nil {
+		m.record(MacroAction{Type: MacroActionDrag, Drag: req})
+	}
+	return resp, err
+}
+
+func (m *MacroRecorder) Scroll(req *MouseScrollRequest) (*ScrollResponse, error) {
+	resp, err := m.IComputerUse.Scroll(req)
+	if err == nil {
+		m.record(MacroAction{Type: MacroActionScroll, Scroll: req})
+	}
+	return resp, err
+}
+
+func (m *MacroRecorder) TypeText(req *KeyboardTypeRequest) (*Empty, error) {
+	resp, err := m.IComputerUse.TypeText(req)
+	if err == nil {
+		m.record(MacroAction{Type: MacroActionType, Text: req})
+	}
+	return resp, err
+}
+
+func (m *MacroRecorder) PressKey(req *KeyboardPressRequest) (*Empty, error) {
+	resp, err := m.IComputerUse.PressKey(req)
+	if err == nil {
+		m.record(MacroAction{Type: MacroActionKey, Key: req})
+	}
+	return resp, err
+}
+
+func (m *MacroRecorder) PressHotkey(req *KeyboardHotkeyRequest) (*Empty, error) {
+	resp, err := m.IComputerUse.PressHotkey(req)
+	if err == nil {
+		m.record(MacroAction{Type: MacroActionHotkey, Hotkey: req})
+	}
+	return resp, err
+}
+
+// Replay runs the actions of a macro. Speed scales the recorded delays, 2 replays twice as fast
+// and 0 runs the actions without delays.
+func (m *MacroRecorder) Replay(name string, speed float64, stopOnFailure bool) (*MacroReplayResponse, error) {
+	macro, err := m.GetMacro(name)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if m.replaying {
+		m.mu.Unlock()
+		return nil, errors.New("another macro is being replayed")
+	}
+	m.replaying = true
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.replaying = false
+		m.mu.Unlock()
+	}()
+
+	start := time.Now()
+	result := &MacroReplayResponse{
+		Success: true,
+		Steps:   []MacroStepResult{},
+	}
+
+	for i, action := range macro.Actions {
+		if speed > 0 && action.DelayMs > 0 {
+			time.Sleep(time.Duration(float64(action.DelayMs)/speed) * time.Millisecond)
+		}
+
+		step := MacroStepResult{
+			Index: i,
+			Type:  action.Type,
+		}
+
+		mismatch, err := m.runAction(action)
+		step.MismatchPercent = mismatch
+		step.Success = err == nil
+		if err != nil {
+			step.Error = err.Error()
+			result.Success = false
+		}
+		result.Steps = append(result.Steps, step)
+
+		if err != nil && stopOnFailure {
+			break
+		}
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+func (m *MacroRecorder) runAction(action MacroAction) (*float64, error) {
+	var err error
+
+	switch action.Type {
+	case MacroActionMove:
+		_, err = m.IComputerUse.MoveMouse(action.Move)
+	case MacroActionClick:
+		_, err = m.IComputerUse.Click(action.Click)
+	case MacroActionDrag:
+		_, err = m.IComputerUse.Drag(action.Drag)
+	case MacroActionScroll:
+		_, err = m.IComputerUse.Scroll(action.Scroll)
+	case MacroActionType:
+		_, err = m.IComputerUse.TypeText(action.Text)
+	case MacroActionKey:
+		_, err = m.IComputerUse.PressKey(action.Key)
+	case MacroActionHotkey:
+		_, err = m.IComputerUse.PressHotkey(action.Hotkey)
+	case MacroActionAssertPixel, MacroActionAssertRegion:
+		return m.checkAssertion(action)
+	default:
+		err = fmt.Errorf("unknown action type %q", action.Type)
+	}
+
+	return nil, err
+}
+
+// checkAssertion compares the screen with the expected content until it matches or the
+// assertion timeout expires
+func (m *MacroRecorder) checkAssertion(action MacroAction) (*float64, error) {
+	assertion := action.Assertion
+	deadline := time.Now().Add(time.Duration(assertion.TimeoutMs) * time.Millisecond)
+
+	for {
+		mismatch, err := m.compare(action.Type, assertion)
+		if err != nil {
+			return nil, err
+		}
+		if mismatch <= assertion.MaxMismatchPercent {
+			return &mismatch, nil
+		}
+		if time.Now().After(deadline) {
+			return &mismatch, fmt.Errorf("screen does not match, %.2f%% of the pixels differ", mismatch)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func (m *MacroRecorder) compare(actionType string, assertion *MacroAssertion) (float64, error) {
+	if actionType == MacroActionAssertPixel {
+		var expected [3]uint32
+		if _, err := fmt.Sscanf(strings.TrimPrefix(assertion.Color, "#"), "%02x%02x%02x", &expected[0], &expected[1], &expected[2]); err != nil {
+			return 0, fmt.Errorf("invalid color %q", assertion.Color)
+		}
+
+		actual, err := m.captureRegion(assertion.Position, Size{Width: 1, Height: 1})
+		if err != nil {
+			return 0, err
+		}
+
+		r, g, b, _ := actual.At(actual.Bounds().Min.X, actual.Bounds().Min.Y).RGBA()
+		if channelsMatch([3]uint32{r >> 8, g >> 8, b >> 8}, expected, assertion.Tolerance) {
+			return 0, nil
+		}
+		return 100, nil
+	}
+
+	expected, err := decodeImage(assertion.Image)
+	if err != nil {
+		return 0, fmt.Errorf("invalid expected image: %w", err)
+	}
+
+	size := Size{Width: expected.Bounds().Dx(), Height: expected.Bounds().Dy()}
+	actual, err := m.captureRegion(assertion.Position, size)
+	if err != nil {
+		return 0, err
+	}
+
+	if actual.Bounds().Dx() != size.Width || actual.Bounds().Dy() != size.Height {
+		return 100, nil
+	}
+
+	mismatched := 0
+	for y := 0; y < size.Height; y++ {
+		for x := 0; x < size.Width; x++ {
+			er, eg, eb, _ := expected.At(expected.Bounds().Min.X+x, expected.Bounds().Min.Y+y).RGBA()
+			ar, ag, ab, _ := actual.At(actual.Bounds().Min.X+x, actual.Bounds().Min.Y+y).RGBA()
+			if !channelsMatch([3]uint32{ar >> 8, ag >> 8, ab >> 8}, [3]uint32{er >> 8, eg >> 8, eb >> 8}, assertion.Tolerance) {
+				mismatched++
+			}
+		}
+	}
+
+	return float64(mismatched) * 100 / float64(size.Width*size.Height), nil
+}
+
+func (m *MacroRecorder) captureRegion(position Position, size Size) (image.Image, error) {
+	screenshot, err := m.TakeRegionScreenshot(&RegionScreenshotRequest{Position: position, Size: size})
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeImage(screenshot.Screenshot)
+}
+
+func channelsMatch(actual, expected [3]uint32, tolerance int) bool {
+	for i := range actual {
+		diff := int(actual[i]) - int(expected[i])
+		if diff < -tolerance || diff > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeImage(encoded string) (image.Image, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return png.Decode(bytes.NewReader(data))
+}
+
+// Storage
+
+func (m *MacroRecorder) macroPath(name string) string {
+	return filepath.Join(m.dir, name+".json")
+}
+
+func (m *MacroRecorder) SaveMacro(macro *Macro) error {
+	if !macroNameRegex.MatchString(macro.Name) {
+		return fmt.Errorf("invalid macro name %q", macro.Name)
+	}
+
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(macro, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(m.macroPath(macro.Name), content, 0644)
+}
+
+func (m *MacroRecorder) GetMacro(name string) (*Macro, error) {
+	if !macroNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("invalid macro name %q", name)
+	}
+
+	content, err := os.ReadFile(m.macroPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+
+	var macro Macro
+	if err := json.Unmarshal(content, &macro); err != nil {
+		return nil, fmt.Errorf("failed to parse macro: %w", err)
+	}
+
+	return &macro, nil
+}
+
+func (m *MacroRecorder) ListMacros() ([]*Macro, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Macro{}, nil
+		}
+		return nil, err
+	}
+
+	macros := []*Macro{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		macro, err := m.GetMacro(name)
+		if err != nil {
+			continue
+		}
+		macros = append(macros, macro)
+	}
+
+	sort.Slice(macros, func(i, j int) bool {
+		return macros[i].Name < macros[j].Name
+	})
+
+	return macros, nil
+}
+
+func (m *MacroRecorder) DeleteMacro(name string) error {
+	if !macroNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid macro name %q", name)
+	}
+
+	err := os.Remove(m.macroPath(name))
+	if os.IsNotExist(err) {
+		return os.ErrNotExist
+	}
+	return err
+}
@@ -0,0 +1,250 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StartMacroRecordingRequest struct {
+	Name string `json:"name" validate:"required"`
+} //	@name	StartMacroRecordingRequest
+
+type SaveMacroRequest struct {
+	Actions []MacroAction `json:"actions" validate:"required"`
+} //	@name	SaveMacroRequest
+
+type ReplayMacroRequest struct {
+	// Replay speed multiplier, 2 replays twice as fast and 0 skips the recorded delays. Defaults to 1
+	Speed *float64 `json:"speed,omitempty" validate:"optional"`
+	// Stop the replay at the first failed action or assertion
+	StopOnFailure bool `json:"stopOnFailure,omitempty" validate:"optional"`
+} //	@name	ReplayMacroRequest
+
+type MacroHandler struct {
+	Recorder *MacroRecorder
+}
+
+// StartMacroRecording godoc
+//
+//	@Summary		Start recording a macro
+//	@Description	Start recording the mouse and keyboard actions sent to the computer use endpoints as a named macro
+//	@Tags			computer-use
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	StartMacroRecordingRequest	true	"Start macro recording request"
+//	@Success		200
+//	@Router			/computeruse/macros/record/start [post]
+//
+//	@id				StartMacroRecording
+func (h *MacroHandler) StartMacroRecording(c *gin.Context) {
+	var req StartMacroRecordingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.Recorder.StartRecording(req.Name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// StopMacroRecording godoc
+//
+//	@Summary		Stop recording a macro
+//	@Description	Stop the current recording and save the macro
+//	@Tags			computer-use
+//	@Produce		json
+//	@Success		200	{object}	Macro
+//	@Router			/computeruse/macros/record/stop [post]
+//
+//	@id				StopMacroRecording
+func (h *MacroHandler) StopMacroRecording(c *gin.Context) {
+	macro, err := h.Recorder.StopRecording()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, macro)
+}
+
+// AddMacroAssertion godoc
+//
+//	@Summary		Add an assertion to the recorded macro
+//	@Description	Add a pixel or region assertion to the macro being recorded. Without an expected color or image, the current screen content is used
+//	@Tags			computer-use
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		MacroAssertion	true	"Macro assertion"
+//	@Success		200		{object}	MacroAction
+//	@Router			/computeruse/macros/record/assertion [post]
+//
+//	@id				AddMacroAssertion
+func (h *MacroHandler) AddMacroAssertion(c *gin.Context) {
+	var req MacroAssertion
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assertion"})
+		return
+	}
+
+	action, err := h.Recorder.RecordAssertion(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, action)
+}
+
+// ListMacros godoc
+//
+//	@Summary		List macros
+//	@Description	List all saved macros
+//	@Tags			computer-use
+//	@Produce		json
+//	@Success		200	{array}	Macro
+//	@Router			/computeruse/macros [get]
+//
+//	@id				ListMacros
+func (h *MacroHandler) ListMacros(c *gin.Context) {
+	macros, err := h.Recorder.ListMacros()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, macros)
+}
+
+// GetMacro godoc
+//
+//	@Summary		Get a macro
+//	@Description	Get a saved macro with its actions
+//	@Tags			computer-use
+//	@Produce		json
+//	@Param			name	path		string	true	"Macro name"
+//	@Success		200		{object}	Macro
+//	@Router			/computeruse/macros/{name} [get]
+//
+//	@id				GetMacro
+func (h *MacroHandler) GetMacro(c *gin.Context) {
+	macro, err := h.Recorder.GetMacro(c.Param("name"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, macro)
+}
+
+// SaveMacro godoc
+//
+//	@Summary		Save a macro
+//	@Description	Create or replace a macro with the given actions
+//	@Tags			computer-use
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string				true	"Macro name"
+//	@Param			request	body		SaveMacroRequest	true	"Macro actions"
+//	@Success		200		{object}	Macro
+//	@Router			/computeruse/macros/{name} [put]
+//
+//	@id				SaveMacro
+func (h *MacroHandler) SaveMacro(c *gin.Context) {
+	var req SaveMacroRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	macro := &Macro{
+		Name:      c.Param("name"),
+		Actions:   req.Actions,
+		CreatedAt: time.Now(),
+	}
+
+	if err := h.Recorder.SaveMacro(macro); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, macro)
+}
+
+// DeleteMacro godoc
+//
+//	@Summary		Delete a macro
+//	@Description	Delete a saved macro
+//	@Tags			computer-use
+//	@Param			name	path	string	true	"Macro name"
+//	@Success		204
+//	@Router			/computeruse/macros/{name} [delete]
+//
+//	@id				DeleteMacro
+func (h *MacroHandler) DeleteMacro(c *gin.Context) {
+	if err := h.Recorder.DeleteMacro(c.Param("name")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReplayMacro godoc
+//
+//	@Summary		Replay a macro
+//	@Description	Replay the actions of a macro with an adjustable speed and check its assertions
+//	@Tags			computer-use
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string				true	"Macro name"
+//	@Param			request	body		ReplayMacroRequest	false	"Replay options"
+//	@Success		200		{object}	MacroReplayResponse
+//	@Router			/computeruse/macros/{name}/replay [post]
+//
+//	@id				ReplayMacro
+func (h *MacroHandler) ReplayMacro(c *gin.Context) {
+	var req ReplayMacroRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+
+	speed := 1.0
+	if req.Speed != nil {
+		if *req.Speed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "speed must not be negative"})
+			return
+		}
+		speed = *req.Speed
+	}
+
+	result, err := h.Recorder.Replay(c.Param("name"), speed, req.StopOnFailure)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *MacroHandler) handleError(c *gin.Context, err error) {
+	if errors.Is(err, os.ErrNotExist) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "macro not found"})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -332,3 +332,8 @@ func GetComputerUse(path string) (computeruse.IComputerUse, error) {
 
 	return impl, nil
 }
+
+// Shutdown kills the computer-use plugin process
+func Shutdown() {
+	plugin.CleanupClients()
+}
@@ -144,6 +144,49 @@ func (m *ComputerUseRPCClient) GetWindows() (*WindowsResponse, error) {
 	return &resp, err
 }
 
+func (m *ComputerUseRPCClient) AddDisplay(request *AddDisplayRequest) (*DisplayInfoResponse, error) {
+	var resp DisplayInfoResponse
+	err := m.client.Call("Plugin.AddDisplay", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) ResizeDisplay(request *ResizeDisplayRequest) (*DisplayInfoResponse, error) {
+	var resp DisplayInfoResponse
+	err := m.client.Call("Plugin.ResizeDisplay", request, &resp)
+	return &resp, err
+}
+
+// Element lookup methods
+func (m *ComputerUseRPCClient) GetAccessibilityTree(request *AccessibilityTreeRequest) (*AccessibilityTreeResponse, error) {
+	var resp AccessibilityTreeResponse
+	err := m.client.Call("Plugin.GetAccessibilityTree", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) RecognizeText(request *OcrRequest) (*OcrResponse, error) {
+	var resp OcrResponse
+	err := m.client.Call("Plugin.RecognizeText", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) ClickElement(request *ClickElementRequest) (*ClickElementResponse, error) {
+	var resp ClickElementResponse
+	err := m.client.Call("Plugin.ClickElement", request, &resp)
+	return &resp, err
+}
+
+// Clipboard methods
+func (m *ComputerUseRPCClient) GetClipboard(request *ClipboardRequest) (*ClipboardResponse, error) {
+	var resp ClipboardResponse
+	err := m.client.Call("Plugin.GetClipboard", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) SetClipboard(request *SetClipboardRequest) (*Empty, error) {
+	err := m.client.Call("Plugin.SetClipboard", request, new(Empty))
+	return new(Empty), err
+}
+
 // Status method
 func (m *ComputerUseRPCClient) GetStatus() (*ComputerUseStatusResponse, error) {
 	var resp ComputerUseStatusResponse
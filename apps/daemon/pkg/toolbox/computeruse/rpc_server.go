@@ -182,6 +182,67 @@ func (m *ComputerUseRPCServer) GetWindows(arg any, resp *WindowsResponse) error
 	return nil
 }
 
+func (m *ComputerUseRPCServer) AddDisplay(arg *AddDisplayRequest, resp *DisplayInfoResponse) error {
+	response, err := m.Impl.AddDisplay(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) ResizeDisplay(arg *ResizeDisplayRequest, resp *DisplayInfoResponse) error {
+	response, err := m.Impl.ResizeDisplay(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+// Element lookup methods
+func (m *ComputerUseRPCServer) GetAccessibilityTree(arg *AccessibilityTreeRequest, resp *AccessibilityTreeResponse) error {
+	response, err := m.Impl.GetAccessibilityTree(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) RecognizeText(arg *OcrRequest, resp *OcrResponse) error {
+	response, err := m.Impl.RecognizeText(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) ClickElement(arg *ClickElementRequest, resp *ClickElementResponse) error {
+	response, err := m.Impl.ClickElement(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+// Clipboard methods
+func (m *ComputerUseRPCServer) GetClipboard(arg *ClipboardRequest, resp *ClipboardResponse) error {
+	response, err := m.Impl.GetClipboard(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) SetClipboard(arg *SetClipboardRequest, resp *Empty) error {
+	_, err := m.Impl.SetClipboard(arg)
+	return err
+}
+
 // Status method
 func (m *ComputerUseRPCServer) GetStatus(arg any, resp *ComputerUseStatusResponse) error {
 	response, err := m.Impl.GetStatus()
@@ -0,0 +1,213 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStreamFps = 10
+	maxStreamFps     = 30
+	mjpegBoundary    = "frame"
+)
+
+type streamConfig struct {
+	display int // -1 for the default display
+	fps     int
+	quality int
+	scale   float64
+}
+
+// captureLoop captures frames for all the viewers of a stream configuration, so each frame is
+// captured once regardless of the number of viewers
+type captureLoop struct {
+	config  streamConfig
+	cancel  context.CancelFunc
+	viewers map[chan []byte]struct{}
+}
+
+// ScreenStreamer streams the display as JPEG frames. Capture loops are started with the first
+// viewer of a configuration and stopped when its last viewer leaves.
+type ScreenStreamer struct {
+	computerUse IComputerUse
+
+	mu    sync.Mutex
+	loops map[streamConfig]*captureLoop
+}
+
+func NewScreenStreamer(computerUse IComputerUse) *ScreenStreamer {
+	return &ScreenStreamer{
+		computerUse: computerUse,
+		loops:       map[streamConfig]*captureLoop{},
+	}
+}
+
+// subscribe returns a channel receiving the frames of the stream and a function to unsubscribe
+func (s *ScreenStreamer) subscribe(config streamConfig) (chan []byte, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Frames are dropped for viewers that can't keep up
+	frames := make(chan []byte, 1)
+
+	loop, ok := s.loops[config]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		loop = &captureLoop{
+			config:  config,
+			cancel:  cancel,
+			viewers: map[chan []byte]struct{}{},
+		}
+		s.loops[config] = loop
+		go s.capture(ctx, loop)
+	}
+	loop.viewers[frames] = struct{}{}
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(loop.viewers, frames)
+		if len(loop.viewers) == 0 {
+			loop.cancel()
+			delete(s.loops, config)
+		}
+	}
+
+	return frames, unsubscribe
+}
+
+func (s *ScreenStreamer) capture(ctx context.Context, loop *captureLoop) {
+	ticker := time.NewTicker(time.Second / time.Duration(loop.config.fps))
+	defer ticker.Stop()
+
+	req := &CompressedScreenshotRequest{
+		ShowCursor: true,
+		Format:     "jpeg",
+		Quality:    loop.config.quality,
+		Scale:      loop.config.scale,
+	}
+	if loop.config.display >= 0 {
+		req.Display = &loop.config.display
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		screenshot, err := s.computerUse.TakeCompressedScreenshot(req)
+		if err != nil {
+			log.Debugf("Failed to capture stream frame: %v", err)
+			continue
+		}
+
+		frame, err := base64.StdEncoding.DecodeString(screenshot.Screenshot)
+		if err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		for viewer := range loop.viewers {
+			select {
+			case viewer <- frame:
+			default:
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// StreamMjpeg godoc
+//
+//	@Summary		Stream the display
+//	@Description	Stream the display live as MJPEG (multipart/x-mixed-replace), which can be shown in an img element
+//	@Tags			computer-use
+//	@Produce		multipart/x-mixed-replace
+//	@Param			fps		query	int		false	"Frames per second (1-30, default 10)"
+//	@Param			quality	query	int		false	"JPEG quality (1-100, default 70)"
+//	@Param			scale	query	number	false	"Scale factor (0.1-1.0, default 1.0)"
+//	@Param			display	query	int		false	"Display to stream"
+//	@Success		200
+//	@Router			/computeruse/stream/mjpeg [get]
+//
+//	@id				StreamMjpeg
+func (s *ScreenStreamer) StreamMjpeg(c *gin.Context) {
+	config := streamConfig{
+		display: -1,
+		fps:     defaultStreamFps,
+		quality: 70,
+		scale:   1.0,
+	}
+
+	if fpsStr := c.Query("fps"); fpsStr != "" {
+		fps, err := strconv.Atoi(fpsStr)
+		if err != nil || fps < 1 || fps > maxStreamFps {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("fps must be between 1 and %d", maxStreamFps)})
+			return
+		}
+		config.fps = fps
+	}
+
+	if qualityStr := c.Query("quality"); qualityStr != "" {
+		if quality, err := strconv.Atoi(qualityStr); err == nil && quality >= 1 && quality <= 100 {
+			config.quality = quality
+		}
+	}
+
+	if scaleStr := c.Query("scale"); scaleStr != "" {
+		if scale, err := strconv.ParseFloat(scaleStr, 64); err == nil && scale >= 0.1 && scale <= 1.0 {
+			config.scale = scale
+		}
+	}
+
+	display, err := displayQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if display != nil {
+		config.display = *display
+	}
+
+	frames, unsubscribe := s.subscribe(config)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "multipart/x-mixed-replace; boundary="+mjpegBoundary)
+	c.Header("Cache-Control", "no-cache, no-store")
+	c.Header("Connection", "close")
+	c.Status(http.StatusOK)
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case frame := <-frames:
+			_, err := fmt.Fprintf(c.Writer, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", mjpegBoundary, len(frame))
+			if err == nil {
+				_, err = c.Writer.Write(frame)
+			}
+			if err == nil {
+				_, err = c.Writer.Write([]byte("\r\n"))
+			}
+			if err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
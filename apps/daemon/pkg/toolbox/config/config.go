@@ -4,3 +4,6 @@
 package config
 
 const TOOLBOX_API_PORT = 2280
+
+// Environment variable the runner passes the daemon auth token of the sandbox in
+const AUTH_TOKEN_ENV_VAR = "DAYTONA_DAEMON_AUTH_TOKEN"
@@ -0,0 +1,104 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package coredump
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daytonaio/daemon/pkg/coredump"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+type CoreDumpController struct {
+	manager *coredump.Manager
+}
+
+func NewCoreDumpController(manager *coredump.Manager) *CoreDumpController {
+	return &CoreDumpController{
+		manager: manager,
+	}
+}
+
+// ListCoreDumps godoc
+//
+//	@Summary		List core dumps
+//	@Description	List the core dumps of processes that crashed in the sandbox, newest first
+//	@Tags			coredumps
+//	@Produce		json
+//	@Success		200	{array}	CoreDump
+//	@Router			/coredumps [get]
+//
+//	@id				ListCoreDumps
+func (ctrl *CoreDumpController) ListCoreDumps(c *gin.Context) {
+	dumps, err := ctrl.manager.List()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result := make([]CoreDump, 0, len(dumps))
+	for _, dump := range dumps {
+		result = append(result, CoreDump{
+			Name:       dump.Name,
+			Executable: dump.Executable,
+			Pid:        dump.Pid,
+			Size:       dump.Size,
+			CreatedAt:  dump.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DownloadCoreDump godoc
+//
+//	@Summary		Download a core dump
+//	@Description	Download a core dump of a process that crashed in the sandbox
+//	@Tags			coredumps
+//	@Produce		application/octet-stream
+//	@Param			name	path	string	true	"Core dump name"
+//	@Success		200		{file}	binary
+//	@Router			/coredumps/{name} [get]
+//
+//	@id				DownloadCoreDump
+func (ctrl *CoreDumpController) DownloadCoreDump(c *gin.Context) {
+	name := c.Param("name")
+	path, err := ctrl.manager.Path(name)
+	if err != nil {
+		c.Error(coreDumpError(err))
+		return
+	}
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.FileAttachment(path, name)
+}
+
+// DeleteCoreDump godoc
+//
+//	@Summary		Delete a core dump
+//	@Description	Delete a core dump of a process that crashed in the sandbox
+//	@Tags			coredumps
+//	@Param			name	path	string	true	"Core dump name"
+//	@Success		204
+//	@Router			/coredumps/{name} [delete]
+//
+//	@id				DeleteCoreDump
+func (ctrl *CoreDumpController) DeleteCoreDump(c *gin.Context) {
+	if err := ctrl.manager.Delete(c.Param("name")); err != nil {
+		c.Error(coreDumpError(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func coreDumpError(err error) error {
+	if errors.Is(err, coredump.ErrNotFound) {
+		return common_errors.NewNotFoundError(err)
+	}
+	return common_errors.NewBadRequestError(err)
+}
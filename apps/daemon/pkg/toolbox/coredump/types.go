@@ -0,0 +1,17 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package coredump
+
+import "time"
+
+type CoreDump struct {
+	Name string `json:"name" validate:"required"`
+	// Name of the crashed executable, empty if the file doesn't follow the core pattern
+	Executable string `json:"executable" validate:"required"`
+	// Pid of the crashed process in the sandbox
+	Pid int `json:"pid" validate:"required"`
+	// Size of the dump in bytes
+	Size      int64     `json:"size" validate:"required"`
+	CreatedAt time.Time `json:"createdAt" validate:"required"`
+} // @name CoreDump
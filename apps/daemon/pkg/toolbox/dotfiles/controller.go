@@ -0,0 +1,92 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package dotfiles
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daytonaio/daemon/pkg/dotfiles"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+type DotfilesController struct {
+	bootstrapper *dotfiles.Bootstrapper
+}
+
+// NewDotfilesController applies the dotfiles of the user on the first boot of the sandbox, if it has any
+func NewDotfilesController(configDir, homeDir string) *DotfilesController {
+	bootstrapper := dotfiles.NewBootstrapper(dotfiles.SpecPath, configDir, homeDir)
+	bootstrapper.Start()
+
+	return &DotfilesController{
+		bootstrapper: bootstrapper,
+	}
+}
+
+// GetDotfilesStatus godoc
+//
+//	@Summary		Get dotfiles status
+//	@Description	Get the status of the last time the dotfiles of the user were applied
+//	@Tags			dotfiles
+//	@Produce		json
+//	@Success		200	{object}	DotfilesStatus
+//	@Router			/dotfiles [get]
+//
+//	@id				GetDotfilesStatus
+func (d *DotfilesController) GetDotfilesStatus(c *gin.Context) {
+	status := d.bootstrapper.Status()
+	if status == nil {
+		c.Error(common_errors.NewNotFoundError(dotfiles.ErrNoDotfiles))
+		return
+	}
+
+	c.JSON(http.StatusOK, DotfilesStatusToDTO(status))
+}
+
+// ApplyDotfiles godoc
+//
+//	@Summary		Apply dotfiles
+//	@Description	Apply the dotfiles of the sandbox again, or the given ones instead, in the background
+//	@Tags			dotfiles
+//	@Accept			json
+//	@Param			request	body	ApplyDotfilesRequest	false	"Dotfiles to apply instead"
+//	@Success		202
+//	@Router			/dotfiles/apply [post]
+//
+//	@id				ApplyDotfiles
+func (d *DotfilesController) ApplyDotfiles(c *gin.Context) {
+	var spec *dotfiles.Spec
+	if c.Request.ContentLength != 0 {
+		var request ApplyDotfilesRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(common_errors.NewInvalidBodyRequestError(err))
+			return
+		}
+		if request != (ApplyDotfilesRequest{}) {
+			spec = &dotfiles.Spec{
+				Repository:     request.Repository,
+				Branch:         request.Branch,
+				Token:          request.Token,
+				InstallCommand: request.InstallCommand,
+				Script:         request.Script,
+				TimeoutSeconds: request.TimeoutSeconds,
+			}
+		}
+	}
+
+	err := d.bootstrapper.Apply(spec)
+	switch {
+	case err == nil:
+		c.Status(http.StatusAccepted)
+	case errors.Is(err, dotfiles.ErrRunning):
+		c.Error(common_errors.NewConflictError(err))
+	case errors.Is(err, dotfiles.ErrNoDotfiles):
+		c.Error(common_errors.NewNotFoundError(err))
+	default:
+		c.Error(common_errors.NewBadRequestError(err))
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package dotfiles
+
+import (
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/dotfiles"
+)
+
+type DotfilesStatus struct {
+	// pending, running, succeeded or failed
+	State string `json:"state" validate:"required"`
+	// clone, install or link, the phase the run is in or failed in
+	Phase string `json:"phase,omitempty" validate:"optional"`
+	Runs  int    `json:"runs" validate:"required"`
+	Error string `json:"error,omitempty" validate:"optional"`
+	// End of the output of the last run
+	Output     string     `json:"output,omitempty" validate:"optional"`
+	Commit     string     `json:"commit,omitempty" validate:"optional"`
+	StartedAt  *time.Time `json:"startedAt,omitempty" validate:"optional"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty" validate:"optional"`
+} // @name DotfilesStatus
+
+type ApplyDotfilesRequest struct {
+	Repository     string `json:"repository,omitempty" validate:"optional"`
+	Branch         string `json:"branch,omitempty" validate:"optional"`
+	Token          string `json:"token,omitempty" validate:"optional"`
+	InstallCommand string `json:"installCommand,omitempty" validate:"optional"`
+	Script         string `json:"script,omitempty" validate:"optional"`
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty" validate:"optional"`
+} // @name ApplyDotfilesRequest
+
+func DotfilesStatusToDTO(status *dotfiles.Status) *DotfilesStatus {
+	return &DotfilesStatus{
+		State:      string(status.State),
+		Phase:      string(status.Phase),
+		Runs:       status.Runs,
+		Error:      status.Error,
+		Output:     status.Output,
+		Commit:     status.Commit,
+		StartedAt:  status.StartedAt,
+		FinishedAt: status.FinishedAt,
+	}
+}
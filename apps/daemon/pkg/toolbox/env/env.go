@@ -0,0 +1,198 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/daytonaio/daemon/pkg/env"
+	"github.com/gin-gonic/gin"
+)
+
+// GetEnv godoc
+//
+//	@Summary		Get default environment variables
+//	@Description	Get the sandbox default environment variables injected into new sessions and exec calls
+//	@Tags			env
+//	@Produce		json
+//	@Success		200	{object}	EnvResponse
+//	@Router			/env [get]
+//
+//	@id				GetEnv
+func GetEnv(c *gin.Context) {
+	c.JSON(http.StatusOK, EnvResponse{
+		Envs: env.Default().Get(),
+	})
+}
+
+// SetEnv godoc
+//
+//	@Summary		Set default environment variables
+//	@Description	Set sandbox default environment variables. They are persisted and apply to sessions and commands started afterwards.
+//	@Tags			env
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SetEnvRequest	true	"Environment variables"
+//	@Success		200		{object}	EnvResponse
+//	@Router			/env [put]
+//
+//	@id				SetEnv
+func SetEnv(c *gin.Context) {
+	var req SetEnvRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	if err := env.Default().Set(req.Envs, req.Replace); err != nil {
+		abortWithEnvError(c, err)
+		return
+	}
+
+	GetEnv(c)
+}
+
+// UnsetEnv godoc
+//
+//	@Summary		Unset default environment variables
+//	@Description	Remove sandbox default environment variables
+//	@Tags			env
+//	@Produce		json
+//	@Param			name	query		[]string	true	"Variable names"
+//	@Success		200		{object}	EnvResponse
+//	@Router			/env [delete]
+//
+//	@id				UnsetEnv
+func UnsetEnv(c *gin.Context) {
+	names := c.QueryArray("name")
+	if len(names) == 0 {
+		c.AbortWithError(http.StatusBadRequest, errors.New("name is required"))
+		return
+	}
+
+	if err := env.Default().Unset(names...); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	GetEnv(c)
+}
+
+// ParseDotenv godoc
+//
+//	@Summary		Parse a .env file
+//	@Description	Parse dotenv content or a .env file and return its variables
+//	@Tags			env
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		DotenvParseRequest	true	"Parse request"
+//	@Success		200		{object}	EnvResponse
+//	@Router			/env/dotenv/parse [post]
+//
+//	@id				ParseDotenv
+func ParseDotenv(c *gin.Context) {
+	var req DotenvParseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	var vars map[string]string
+	var err error
+	switch {
+	case req.Content != "":
+		vars, err = env.Parse(strings.NewReader(req.Content))
+	case req.Path != "":
+		vars, err = env.ParseFile(req.Path)
+	default:
+		c.AbortWithError(http.StatusBadRequest, errors.New("path or content is required"))
+		return
+	}
+	if err != nil {
+		abortWithEnvError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EnvResponse{
+		Envs: vars,
+	})
+}
+
+// MergeDotenv godoc
+//
+//	@Summary		Merge variables into a .env file
+//	@Description	Update a .env file with the given variables, keeping its comments and ordering. The file is created if it doesn't exist.
+//	@Tags			env
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		DotenvMergeRequest	true	"Merge request"
+//	@Success		200		{object}	EnvResponse
+//	@Router			/env/dotenv/merge [post]
+//
+//	@id				MergeDotenv
+func MergeDotenv(c *gin.Context) {
+	var req DotenvMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	vars, err := env.MergeFile(req.Path, req.Envs)
+	if err != nil {
+		abortWithEnvError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EnvResponse{
+		Envs: vars,
+	})
+}
+
+// LoadDotenv godoc
+//
+//	@Summary		Load a .env file into the defaults
+//	@Description	Set sandbox default environment variables from a .env file
+//	@Tags			env
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		DotenvLoadRequest	true	"Load request"
+//	@Success		200		{object}	EnvResponse
+//	@Router			/env/dotenv/load [post]
+//
+//	@id				LoadDotenv
+func LoadDotenv(c *gin.Context) {
+	var req DotenvLoadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	vars, err := env.ParseFile(req.Path)
+	if err != nil {
+		abortWithEnvError(c, err)
+		return
+	}
+
+	if err := env.Default().Set(vars, req.Replace); err != nil {
+		abortWithEnvError(c, err)
+		return
+	}
+
+	GetEnv(c)
+}
+
+func abortWithEnvError(c *gin.Context, err error) {
+	switch {
+	case os.IsNotExist(err):
+		c.AbortWithError(http.StatusNotFound, err)
+	case os.IsPermission(err):
+		c.AbortWithError(http.StatusForbidden, err)
+	default:
+		c.AbortWithError(http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package env
+
+type EnvResponse struct {
+	Envs map[string]string `json:"envs" validate:"required"`
+} // @name EnvResponse
+
+type SetEnvRequest struct {
+	Envs map[string]string `json:"envs" validate:"required"`
+	// Remove defaults that are not in envs
+	Replace bool `json:"replace,omitempty" validate:"optional"`
+} // @name SetEnvRequest
+
+type DotenvParseRequest struct {
+	// Path of a .env file, ignored if content is set
+	Path    string `json:"path,omitempty" validate:"optional"`
+	Content string `json:"content,omitempty" validate:"optional"`
+} // @name DotenvParseRequest
+
+type DotenvMergeRequest struct {
+	Path string            `json:"path" validate:"required"`
+	Envs map[string]string `json:"envs" validate:"required"`
+} // @name DotenvMergeRequest
+
+type DotenvLoadRequest struct {
+	Path string `json:"path" validate:"required"`
+	// Remove defaults that are not in the file
+	Replace bool `json:"replace,omitempty" validate:"optional"`
+} // @name DotenvLoadRequest
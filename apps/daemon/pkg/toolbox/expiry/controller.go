@@ -0,0 +1,107 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package expiry
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ExpiryController receives the warning the runner sends shortly before the sandbox expires and
+// passes it on to API clients and to tools in the sandbox, which read it from a file
+type ExpiryController struct {
+	statusFile string
+
+	mu      sync.Mutex
+	warning *ExpiryWarning
+}
+
+func NewExpiryController(configDir string) *ExpiryController {
+	return &ExpiryController{
+		statusFile: filepath.Join(configDir, "expiry.json"),
+	}
+}
+
+// ReportExpiryWarning godoc
+//
+//	@Summary		Report expiry warning
+//	@Description	Report that the sandbox is about to expire. Sent by the runner.
+//	@Tags			expiry
+//	@Accept			json
+//	@Param			warning	body	ExpiryWarning	true	"Expiry warning"
+//	@Success		204
+//	@Router			/expiry/warning [post]
+//
+//	@id				ReportExpiryWarning
+func (e *ExpiryController) ReportExpiryWarning(c *gin.Context) {
+	var warning ExpiryWarning
+	if err := c.ShouldBindJSON(&warning); err != nil {
+		c.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	if warning.Time.IsZero() {
+		warning.Time = time.Now()
+	}
+
+	log.Warnf("Sandbox expires at %s, policy %s", warning.ExpiresAt.Format(time.RFC3339), warning.Policy)
+
+	e.mu.Lock()
+	e.warning = &warning
+	e.mu.Unlock()
+
+	if err := e.writeStatusFile(warning); err != nil {
+		log.Errorf("Failed to write expiry status file: %v", err)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetExpiryWarning godoc
+//
+//	@Summary		Get expiry warning
+//	@Description	Get the warning the runner sent shortly before the sandbox expires
+//	@Tags			expiry
+//	@Produce		json
+//	@Success		200	{object}	ExpiryWarning
+//	@Router			/expiry [get]
+//
+//	@id				GetExpiryWarning
+func (e *ExpiryController) GetExpiryWarning(c *gin.Context) {
+	e.mu.Lock()
+	warning := e.warning
+	e.mu.Unlock()
+
+	if warning == nil {
+		c.Error(common_errors.NewNotFoundError(errors.New("sandbox is not about to expire")))
+		return
+	}
+
+	c.JSON(http.StatusOK, warning)
+}
+
+// writeStatusFile replaces the status file atomically so that readers never see a partial warning
+func (e *ExpiryController) writeStatusFile(warning ExpiryWarning) error {
+	data, err := json.Marshal(warning)
+	if err != nil {
+		return err
+	}
+
+	tmpFile := e.statusFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, e.statusFile)
+}
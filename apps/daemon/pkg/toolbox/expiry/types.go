@@ -0,0 +1,14 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package expiry
+
+import "time"
+
+type ExpiryWarning struct {
+	// Time at which the sandbox expires
+	ExpiresAt time.Time `json:"expiresAt" validate:"required"`
+	// What happens to the sandbox once it expires, stop or destroy
+	Policy string    `json:"policy" validate:"required"`
+	Time   time.Time `json:"time" validate:"required"`
+} // @name ExpiryWarning
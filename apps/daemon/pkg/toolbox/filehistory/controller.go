@@ -0,0 +1,170 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filehistory
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/daytonaio/daemon/pkg/filehistory"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+type FileHistoryController struct {
+	history *filehistory.History
+}
+
+func NewFileHistoryController(history *filehistory.History) *FileHistoryController {
+	return &FileHistoryController{
+		history: history,
+	}
+}
+
+// ListVersions godoc
+//
+//	@Summary		List workspace versions
+//	@Description	List the versions of the workspace kept in the file history, newest first
+//	@Tags			history
+//	@Produce		json
+//	@Success		200	{array}	HistoryVersion
+//	@Router			/history/versions [get]
+//
+//	@id				ListHistoryVersions
+func (ctrl *FileHistoryController) ListVersions(c *gin.Context) {
+	versions := ctrl.history.ListVersions()
+
+	result := make([]HistoryVersion, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, HistoryVersionToDTO(version))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateVersion godoc
+//
+//	@Summary		Snapshot the workspace
+//	@Description	Take a version of the workspace now, e.g. before a risky change. The latest version is returned if nothing changed since it.
+//	@Tags			history
+//	@Produce		json
+//	@Success		200	{object}	HistoryVersion
+//	@Router			/history/versions [post]
+//
+//	@id				CreateHistoryVersion
+func (ctrl *FileHistoryController) CreateVersion(c *gin.Context) {
+	version, err := ctrl.history.Snapshot()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, HistoryVersionToDTO(*version))
+}
+
+// ListFileVersions godoc
+//
+//	@Summary		List file versions
+//	@Description	List the versions in which a file changed, newest first
+//	@Tags			history
+//	@Produce		json
+//	@Param			path	query	string	true	"File path"
+//	@Success		200		{array}	HistoryFileVersion
+//	@Router			/history/files [get]
+//
+//	@id				ListHistoryFileVersions
+func (ctrl *FileHistoryController) ListFileVersions(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		c.Error(common_errors.NewBadRequestError(errors.New("path is required")))
+		return
+	}
+
+	fileVersions, err := ctrl.history.ListFileVersions(path)
+	if err != nil {
+		c.Error(historyError(err))
+		return
+	}
+
+	result := make([]HistoryFileVersion, 0, len(fileVersions))
+	for _, fileVersion := range fileVersions {
+		result = append(result, HistoryFileVersion{
+			VersionId: fileVersion.VersionId,
+			CreatedAt: fileVersion.CreatedAt,
+			Size:      fileVersion.Size,
+			ModTime:   fileVersion.ModTime,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DownloadFileVersion godoc
+//
+//	@Summary		Download a file version
+//	@Description	Download the content of a file in a version of the workspace
+//	@Tags			history
+//	@Produce		octet-stream
+//	@Param			versionId	path	string	true	"Version ID"
+//	@Param			path		query	string	true	"File path"
+//	@Success		200			{file}	binary
+//	@Router			/history/versions/{versionId}/files [get]
+//
+//	@id				DownloadHistoryFileVersion
+func (ctrl *FileHistoryController) DownloadFileVersion(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		c.Error(common_errors.NewBadRequestError(errors.New("path is required")))
+		return
+	}
+
+	filePath, err := ctrl.history.FilePath(c.Param("versionId"), path)
+	if err != nil {
+		c.Error(historyError(err))
+		return
+	}
+
+	c.FileAttachment(filePath, filepath.Base(path))
+}
+
+// Restore godoc
+//
+//	@Summary		Restore files
+//	@Description	Restore a file, or all files in a directory, from a version of the workspace. The workspace is snapshotted first, so the restore can be undone.
+//	@Tags			history
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		RestoreHistoryRequest	true	"Restore request"
+//	@Success		200		{object}	RestoreHistoryResponse
+//	@Router			/history/restore [post]
+//
+//	@id				RestoreHistory
+func (ctrl *FileHistoryController) Restore(c *gin.Context) {
+	var req RestoreHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid request body: %w", err)))
+		return
+	}
+
+	restored, err := ctrl.history.Restore(req.VersionId, req.Path)
+	if err != nil {
+		c.Error(historyError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, RestoreHistoryResponse{Restored: restored})
+}
+
+func historyError(err error) error {
+	if errors.Is(err, filehistory.ErrVersionNotFound) || errors.Is(err, filehistory.ErrFileNotFound) {
+		return common_errors.NewNotFoundError(err)
+	}
+	if errors.Is(err, filehistory.ErrOutsideWorkDir) {
+		return common_errors.NewBadRequestError(err)
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filehistory
+
+import (
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/filehistory"
+)
+
+type HistoryVersion struct {
+	Id        string    `json:"id" validate:"required"`
+	CreatedAt time.Time `json:"createdAt" validate:"required"`
+	// What took the version: schedule, change, manual or restore
+	Trigger string `json:"trigger" validate:"required"`
+	// Number of files in the version
+	Files int `json:"files" validate:"required"`
+	// Number of files added, modified or deleted since the previous version
+	Changes int `json:"changes" validate:"required"`
+} // @name HistoryVersion
+
+type HistoryFileVersion struct {
+	VersionId string    `json:"versionId" validate:"required"`
+	CreatedAt time.Time `json:"createdAt" validate:"required"`
+	// Size of the file in bytes
+	Size    int64     `json:"size" validate:"required"`
+	ModTime time.Time `json:"modTime" validate:"required"`
+} // @name HistoryFileVersion
+
+type RestoreHistoryRequest struct {
+	VersionId string `json:"versionId" validate:"required"`
+	// File or directory to restore, absolute or relative to the working directory
+	Path string `json:"path" validate:"required"`
+} // @name RestoreHistoryRequest
+
+type RestoreHistoryResponse struct {
+	// Paths of the restored files
+	Restored []string `json:"restored" validate:"required"`
+} // @name RestoreHistoryResponse
+
+func HistoryVersionToDTO(version filehistory.Version) HistoryVersion {
+	return HistoryVersion{
+		Id:        version.Id,
+		CreatedAt: version.CreatedAt,
+		Trigger:   string(version.Trigger),
+		Files:     version.Files,
+		Changes:   version.Changes,
+	}
+}
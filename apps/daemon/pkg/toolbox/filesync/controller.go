@@ -0,0 +1,212 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/filesync"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+const (
+	maxWatchTimeout = 60 * time.Second
+	watchInterval   = 500 * time.Millisecond
+)
+
+type SyncController struct {
+	syncer *filesync.Syncer
+}
+
+func NewSyncController(workDir, configDir string) *SyncController {
+	return &SyncController{
+		syncer: filesync.NewSyncer(workDir, configDir),
+	}
+}
+
+// GetManifest godoc
+//
+//	@Summary		Get sync manifest
+//	@Description	Get the hashes and chunk hashes of the files under a sync root, which the client compares with its own files and the state of the last sync
+//	@Tags			sync
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SyncManifestRequest	true	"Manifest request"
+//	@Success		200		{object}	SyncManifest
+//	@Router			/sync/manifest [post]
+//
+//	@id				GetSyncManifest
+func (ctrl *SyncController) GetManifest(c *gin.Context) {
+	var req SyncManifestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid request body: %w", err)))
+		return
+	}
+
+	manifest, err := ctrl.syncer.GetManifest(req.Root, req.Ignore)
+	if err != nil {
+		c.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, SyncManifestToDTO(manifest))
+}
+
+// Watch godoc
+//
+//	@Summary		Watch sync root
+//	@Description	Wait until the files under a sync root differ from a manifest digest and return the new manifest. Responds with 204 if nothing changed within the timeout.
+//	@Tags			sync
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SyncWatchRequest	true	"Watch request"
+//	@Success		200		{object}	SyncManifest
+//	@Success		204
+//	@Router			/sync/watch [post]
+//
+//	@id				WatchSyncRoot
+func (ctrl *SyncController) Watch(c *gin.Context) {
+	var req SyncWatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid request body: %w", err)))
+		return
+	}
+
+	timeout := time.Duration(req.TimeoutSeconds) * time.Second
+	if timeout <= 0 || timeout > maxWatchTimeout {
+		timeout = maxWatchTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	manifest, err := ctrl.syncer.Watch(ctx, req.Root, req.Ignore, req.Digest, watchInterval)
+	if err != nil {
+		c.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+	if manifest == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, SyncManifestToDTO(manifest))
+}
+
+// GetMissingChunks godoc
+//
+//	@Summary		Get missing chunks
+//	@Description	Get the chunks of a list that the sandbox doesn't have, which must be uploaded before the changes using them are applied
+//	@Tags			sync
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SyncMissingChunksRequest	true	"Chunk hashes"
+//	@Success		200		{object}	SyncMissingChunksResponse
+//	@Router			/sync/chunks/missing [post]
+//
+//	@id				GetSyncMissingChunks
+func (ctrl *SyncController) GetMissingChunks(c *gin.Context) {
+	var req SyncMissingChunksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid request body: %w", err)))
+		return
+	}
+
+	c.JSON(http.StatusOK, SyncMissingChunksResponse{Missing: ctrl.syncer.MissingChunks(req.Hashes)})
+}
+
+// UploadChunk godoc
+//
+//	@Summary		Upload chunk
+//	@Description	Upload a chunk of a file, addressed by the SHA-256 of its content
+//	@Tags			sync
+//	@Accept			octet-stream
+//	@Param			hash	path	string	true	"SHA-256 of the chunk"
+//	@Success		204
+//	@Router			/sync/chunks/{hash} [put]
+//
+//	@id				UploadSyncChunk
+func (ctrl *SyncController) UploadChunk(c *gin.Context) {
+	if err := ctrl.syncer.PutChunk(c.Param("hash"), c.Request.Body); err != nil {
+		c.Error(syncError(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DownloadChunk godoc
+//
+//	@Summary		Download chunk
+//	@Description	Download a chunk of a file in the sandbox, addressed by the SHA-256 of its content, as listed in a manifest
+//	@Tags			sync
+//	@Produce		octet-stream
+//	@Param			hash	path	string	true	"SHA-256 of the chunk"
+//	@Success		200		{file}	binary
+//	@Router			/sync/chunks/{hash} [get]
+//
+//	@id				DownloadSyncChunk
+func (ctrl *SyncController) DownloadChunk(c *gin.Context) {
+	data, err := ctrl.syncer.ReadChunk(c.Param("hash"))
+	if err != nil {
+		c.Error(syncError(err))
+		return
+	}
+
+	c.Data(http.StatusOK, "application/octet-stream", data)
+}
+
+// Apply godoc
+//
+//	@Summary		Apply changes
+//	@Description	Apply local changes to the files under a sync root. A change conflicts if the file in the sandbox no longer has the hash it had at the last sync, the conflict policy decides which version wins.
+//	@Tags			sync
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SyncApplyRequest	true	"Changes"
+//	@Success		200		{object}	SyncApplyResponse
+//	@Router			/sync/apply [post]
+//
+//	@id				ApplySyncChanges
+func (ctrl *SyncController) Apply(c *gin.Context) {
+	var req SyncApplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid request body: %w", err)))
+		return
+	}
+
+	changes := make([]filesync.Change, 0, len(req.Changes))
+	for _, change := range req.Changes {
+		changes = append(changes, SyncChangeFromDTO(change))
+	}
+
+	results, err := ctrl.syncer.Apply(req.Root, req.Ignore, filesync.ConflictPolicy(req.ConflictPolicy), changes)
+	if err != nil {
+		c.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
+	response := SyncApplyResponse{Results: make([]SyncChangeResult, 0, len(results))}
+	for _, result := range results {
+		response.Results = append(response.Results, SyncChangeResultToDTO(result))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func syncError(err error) error {
+	if errors.Is(err, filesync.ErrChunkNotFound) {
+		return common_errors.NewNotFoundError(err)
+	}
+	if errors.Is(err, filesync.ErrInvalidChunk) {
+		return common_errors.NewBadRequestError(err)
+	}
+	return err
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package filesync
+
+import (
+	"os"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/filesync"
+)
+
+type SyncManifestRequest struct {
+	// Directory to sync, absolute or relative to the working directory
+	Root string `json:"root"`
+	// Gitignore style patterns of files not synced
+	Ignore []string `json:"ignore"`
+} // @name SyncManifestRequest
+
+type SyncWatchRequest struct {
+	Root   string   `json:"root"`
+	Ignore []string `json:"ignore"`
+	// Digest of the last manifest the client has
+	Digest string `json:"digest" validate:"required"`
+	// Time to wait for a change, at most 60 seconds
+	TimeoutSeconds int `json:"timeoutSeconds"`
+} // @name SyncWatchRequest
+
+type SyncFileState struct {
+	// Path relative to the sync root
+	Path    string    `json:"path" validate:"required"`
+	Size    int64     `json:"size" validate:"required"`
+	ModTime time.Time `json:"modTime" validate:"required"`
+	// Permission bits
+	Mode uint32 `json:"mode" validate:"required"`
+	// SHA-256 of the content
+	Hash string `json:"hash" validate:"required"`
+	// SHA-256 of each chunk of the content, in order
+	Chunks []string `json:"chunks" validate:"required"`
+} // @name SyncFileState
+
+type SyncManifest struct {
+	Root  string          `json:"root" validate:"required"`
+	Files []SyncFileState `json:"files" validate:"required"`
+	// Changes whenever a file is added, removed or modified
+	Digest string `json:"digest" validate:"required"`
+} // @name SyncManifest
+
+type SyncMissingChunksRequest struct {
+	Hashes []string `json:"hashes" validate:"required"`
+} // @name SyncMissingChunksRequest
+
+type SyncMissingChunksResponse struct {
+	Missing []string `json:"missing" validate:"required"`
+} // @name SyncMissingChunksResponse
+
+type SyncChange struct {
+	// Path relative to the sync root
+	Path   string `json:"path" validate:"required"`
+	Delete bool   `json:"delete"`
+	// Hash the file had in the sandbox at the last sync, empty if it didn't exist
+	BaseHash string    `json:"baseHash"`
+	Hash     string    `json:"hash"`
+	Mode     uint32    `json:"mode"`
+	ModTime  time.Time `json:"modTime"`
+	Chunks   []string  `json:"chunks"`
+} // @name SyncChange
+
+type SyncApplyRequest struct {
+	Root   string   `json:"root"`
+	Ignore []string `json:"ignore"`
+	// keep-both, local or sandbox, keep-both if empty
+	ConflictPolicy string       `json:"conflictPolicy" enums:"keep-both,local,sandbox"`
+	Changes        []SyncChange `json:"changes" validate:"required"`
+} // @name SyncApplyRequest
+
+type SyncChangeResult struct {
+	Path string `json:"path" validate:"required"`
+	// applied, unchanged, conflict, ignored or failed
+	Status string `json:"status" validate:"required"`
+	// Whether the file was changed in the sandbox too
+	Conflict bool `json:"conflict" validate:"required"`
+	// Path the sandbox version was kept under, relative to the sync root
+	ConflictPath string `json:"conflictPath,omitempty"`
+	Error        string `json:"error,omitempty"`
+} // @name SyncChangeResult
+
+type SyncApplyResponse struct {
+	Results []SyncChangeResult `json:"results" validate:"required"`
+} // @name SyncApplyResponse
+
+func SyncManifestToDTO(manifest *filesync.Manifest) SyncManifest {
+	files := make([]SyncFileState, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		files = append(files, SyncFileState{
+			Path:    file.Path,
+			Size:    file.Size,
+			ModTime: file.ModTime,
+			Mode:    uint32(file.Mode.Perm()),
+			Hash:    file.Hash,
+			Chunks:  file.Chunks,
+		})
+	}
+
+	return SyncManifest{
+		Root:   manifest.Root,
+		Files:  files,
+		Digest: manifest.Digest,
+	}
+}
+
+func SyncChangeFromDTO(change SyncChange) filesync.Change {
+	return filesync.Change{
+		Path:     change.Path,
+		Delete:   change.Delete,
+		BaseHash: change.BaseHash,
+		Hash:     change.Hash,
+		Mode:     os.FileMode(change.Mode).Perm(),
+		ModTime:  change.ModTime,
+		Chunks:   change.Chunks,
+	}
+}
+
+func SyncChangeResultToDTO(result filesync.ChangeResult) SyncChangeResult {
+	return SyncChangeResult{
+		Path:         result.Path,
+		Status:       string(result.Status),
+		Conflict:     result.Conflict,
+		ConflictPath: result.ConflictPath,
+		Error:        result.Error,
+	}
+}
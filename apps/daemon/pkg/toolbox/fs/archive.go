@@ -0,0 +1,501 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateArchive godoc
+//
+//	@Summary		Create an archive
+//	@Description	Stream a tar, tar.gz or zip archive of a file or directory. Entry names are relative to the given path.
+//	@Tags			file-system
+//	@Accept			json
+//	@Produce		octet-stream
+//	@Param			request	body	CreateArchiveRequest	true	"Archive request"
+//	@Success		200		{file}	binary
+//	@Router			/files/archive [post]
+//
+//	@id				CreateArchive
+func CreateArchive(c *gin.Context) {
+	var req CreateArchiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = ArchiveFormatTarGz
+	}
+
+	var contentType string
+	switch req.Format {
+	case ArchiveFormatTar:
+		contentType = "application/x-tar"
+	case ArchiveFormatTarGz:
+		contentType = "application/gzip"
+	case ArchiveFormatZip:
+		contentType = "application/zip"
+	default:
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("unsupported archive format: %s", req.Format))
+		return
+	}
+
+	absPath, err := filepath.Abs(req.Path)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid path: %w", err))
+		return
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		}
+		if os.IsPermission(err) {
+			c.AbortWithError(http.StatusForbidden, err)
+			return
+		}
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	// Entries of a single file archive are named after the file itself
+	root := absPath
+	if !info.IsDir() {
+		root = filepath.Dir(absPath)
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", filepath.Base(absPath), req.Format))
+	c.Status(http.StatusOK)
+
+	var writer archiveWriter
+	switch req.Format {
+	case ArchiveFormatZip:
+		writer = &zipArchiveWriter{zw: zip.NewWriter(c.Writer)}
+	case ArchiveFormatTarGz:
+		gw := gzip.NewWriter(c.Writer)
+		writer = &tarArchiveWriter{tw: tar.NewWriter(gw), closer: gw}
+	default:
+		writer = &tarArchiveWriter{tw: tar.NewWriter(c.Writer)}
+	}
+
+	err = filepath.WalkDir(absPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(root, path)
+		if err != nil || name == "." {
+			return nil
+		}
+		name = filepath.ToSlash(name)
+
+		if path != absPath {
+			if matchesAnyGlob(absPath, path, req.Exclude) {
+				return skipEntry(d)
+			}
+			if !d.IsDir() && len(req.Include) > 0 && !matchesAnyGlob(absPath, path, req.Include) {
+				return nil
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		return writer.add(name, path, info)
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+
+	// The response has already started, the error can only be logged
+	if err != nil {
+		log.Errorf("Failed to create archive of %s: %v", absPath, err)
+	}
+}
+
+// ExtractArchive godoc
+//
+//	@Summary		Extract an archive
+//	@Description	Extract an uploaded tar, tar.gz or zip archive into a directory. The format is detected from the content.
+//	@Description	Entries that would be written outside the destination directory are rejected.
+//	@Tags			file-system
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			path	query		string	true	"Destination directory"
+//	@Param			file	formData	file	true	"Archive to extract"
+//	@Success		200		{object}	ExtractArchiveResponse
+//	@Router			/files/extract [post]
+//
+//	@id				ExtractArchive
+func ExtractArchive(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		c.AbortWithError(http.StatusBadRequest, errors.New("path is required"))
+		return
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid path: %w", err))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	if err := os.MkdirAll(absPath, 0755); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("failed to create destination directory: %w", err))
+		return
+	}
+
+	// Symlinked destinations are fine, but entries are validated against the resolved directory
+	dest, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	var count int
+	head := make([]byte, 4)
+	n, _ := io.ReadFull(file, head)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	switch {
+	case bytes.HasPrefix(head[:n], []byte("PK\x03\x04")):
+		count, err = extractZip(file, fileHeader.Size, dest)
+	case bytes.HasPrefix(head[:n], []byte{0x1f, 0x8b}):
+		var gr *gzip.Reader
+		gr, err = gzip.NewReader(file)
+		if err == nil {
+			count, err = extractTar(gr, dest)
+			gr.Close()
+		}
+	default:
+		count, err = extractTar(file, dest)
+	}
+
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("failed to extract archive: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ExtractArchiveResponse{
+		Path:  absPath,
+		Files: count,
+	})
+}
+
+type archiveWriter interface {
+	add(name, path string, info fs.FileInfo) error
+	Close() error
+}
+
+type tarArchiveWriter struct {
+	tw     *tar.Writer
+	closer io.Closer
+}
+
+func (w *tarArchiveWriter) add(name, path string, info fs.FileInfo) error {
+	var link string
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		link = target
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+
+	if err := w.tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	return copyFileTo(w.tw, path)
+}
+
+func (w *tarArchiveWriter) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	if w.closer != nil {
+		return w.closer.Close()
+	}
+	return nil
+}
+
+type zipArchiveWriter struct {
+	zw *zip.Writer
+}
+
+func (w *zipArchiveWriter) add(name, path string, info fs.FileInfo) error {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	} else {
+		header.Method = zip.Deflate
+	}
+
+	entry, err := w.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		// Zip stores the symlink target as the entry content
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		_, err = entry.Write([]byte(target))
+		return err
+	case info.Mode().IsRegular():
+		return copyFileTo(entry, path)
+	}
+
+	return nil
+}
+
+func (w *zipArchiveWriter) Close() error {
+	return w.zw.Close()
+}
+
+func copyFileTo(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, bufio.NewReader(file))
+	return err
+}
+
+func extractTar(r io.Reader, dest string) (int, error) {
+	tr := tar.NewReader(r)
+	count := 0
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		target, err := safeArchivePath(dest, header.Name)
+		if err != nil {
+			return count, err
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, mode|0700)
+		case tar.TypeReg:
+			err = writeArchiveFile(target, tr, mode)
+		case tar.TypeSymlink:
+			err = createArchiveSymlink(dest, target, header.Linkname)
+		case tar.TypeLink:
+			var linkTarget string
+			linkTarget, err = safeArchivePath(dest, header.Linkname)
+			if err == nil {
+				os.Remove(target)
+				err = os.Link(linkTarget, target)
+			}
+		default:
+			log.Debugf("Skipping unsupported archive entry %s of type %c", header.Name, header.Typeflag)
+			continue
+		}
+		if err != nil {
+			return count, fmt.Errorf("%s: %w", header.Name, err)
+		}
+
+		count++
+	}
+}
+
+func extractZip(r io.ReaderAt, size int64, dest string) (int, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, entry := range zr.File {
+		target, err := safeArchivePath(dest, entry.Name)
+		if err != nil {
+			return count, err
+		}
+
+		mode := entry.Mode()
+
+		switch {
+		case mode.IsDir():
+			err = os.MkdirAll(target, mode.Perm()|0700)
+		case mode&os.ModeSymlink != 0:
+			var linkname []byte
+			linkname, err = readZipEntry(entry)
+			if err == nil {
+				err = createArchiveSymlink(dest, target, string(linkname))
+			}
+		default:
+			var rc io.ReadCloser
+			rc, err = entry.Open()
+			if err == nil {
+				err = writeArchiveFile(target, rc, mode.Perm())
+				rc.Close()
+			}
+		}
+		if err != nil {
+			return count, fmt.Errorf("%s: %w", entry.Name, err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+func writeArchiveFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	// Never write through an existing symlink, it could point outside the destination
+	os.Remove(target)
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func createArchiveSymlink(dest, target, linkname string) error {
+	resolved := linkname
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(filepath.Dir(target), linkname)
+	}
+	if !isWithin(dest, resolved) {
+		return fmt.Errorf("symlink target %s is outside of the destination", linkname)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	os.Remove(target)
+	return os.Symlink(linkname, target)
+}
+
+// safeArchivePath returns the path an archive entry is extracted to, rejecting entries that
+// would escape dest through ".." components, absolute names or previously extracted symlinks
+func safeArchivePath(dest, name string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+
+	target := filepath.Join(dest, cleaned)
+	if !isWithin(dest, target) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+
+	// Resolve the existing part of the parent directory to catch symlinks pointing outside
+	parent := filepath.Dir(target)
+	for {
+		resolved, err := filepath.EvalSymlinks(parent)
+		if err == nil {
+			if !isWithin(dest, resolved) {
+				return "", fmt.Errorf("illegal path in archive: %s", name)
+			}
+			break
+		}
+		if !os.IsNotExist(err) || parent == dest {
+			return "", err
+		}
+		parent = filepath.Dir(parent)
+	}
+
+	return target, nil
+}
+
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
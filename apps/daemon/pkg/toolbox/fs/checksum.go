@@ -0,0 +1,87 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFileChecksum godoc
+//
+//	@Summary		Get file checksum
+//	@Description	Get the SHA-256 checksum of a file, e.g. to verify a download
+//	@Tags			file-system
+//	@Produce		json
+//	@Param			path	query		string	true	"File path"
+//	@Success		200		{object}	FileChecksumResponse
+//	@Router			/files/checksum [get]
+//
+//	@id				GetFileChecksum
+func GetFileChecksum(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		c.AbortWithError(http.StatusBadRequest, errors.New("path is required"))
+		return
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, errors.New("invalid path"))
+		return
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		}
+		if os.IsPermission(err) {
+			c.AbortWithError(http.StatusForbidden, err)
+			return
+		}
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if info.IsDir() {
+		c.AbortWithError(http.StatusBadRequest, errors.New("path must be a file"))
+		return
+	}
+
+	checksum, err := fileSha256(absPath)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, FileChecksumResponse{
+		Path:   absPath,
+		Size:   info.Size(),
+		Sha256: checksum,
+	})
+}
+
+func fileSha256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -16,11 +16,14 @@ import (
 // DownloadFile godoc
 //
 //	@Summary		Download a file
-//	@Description	Download a file by providing its path
+//	@Description	Download a file by providing its path. Range requests are supported so that interrupted
+//	@Description	downloads can be resumed; use /files/checksum to verify the result.
 //	@Tags			file-system
 //	@Produce		octet-stream
 //	@Param			path	query	string	true	"File path to download"
+//	@Param			Range	header	string	false	"Byte range to download (e.g., bytes=1024-)"
 //	@Success		200		{file}	binary
+//	@Success		206		{file}	binary	"Partial content"
 //	@Router			/files/download [get]
 //
 //	@id				DownloadFile
@@ -64,5 +67,6 @@ func DownloadFile(c *gin.Context) {
 	c.Header("Cache-Control", "must-revalidate")
 	c.Header("Pragma", "public")
 
+	// Serves Range and If-Range requests
 	c.File(absPath)
 }
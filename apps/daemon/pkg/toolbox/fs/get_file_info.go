@@ -49,21 +49,38 @@ func GetFileInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, info)
 }
 
+// getFileInfo describes the file at path, following symlinks. Broken symlinks are described by the link itself.
 func getFileInfo(path string) (FileInfo, error) {
-	info, err := os.Stat(path)
+	info, err := os.Lstat(path)
 	if err != nil {
 		return FileInfo{}, err
 	}
 
+	var symlinkTarget string
+	isSymlink := info.Mode()&os.ModeSymlink != 0
+	if isSymlink {
+		symlinkTarget, err = os.Readlink(path)
+		if err != nil {
+			return FileInfo{}, err
+		}
+		if targetInfo, err := os.Stat(path); err == nil {
+			info = targetInfo
+		}
+	}
+
 	stat := info.Sys().(*syscall.Stat_t)
 	return FileInfo{
-		Name:        info.Name(),
-		Size:        info.Size(),
-		Mode:        info.Mode().String(),
-		ModTime:     info.ModTime().String(),
-		IsDir:       info.IsDir(),
-		Owner:       strconv.FormatUint(uint64(stat.Uid), 10),
-		Group:       strconv.FormatUint(uint64(stat.Gid), 10),
-		Permissions: fmt.Sprintf("%04o", info.Mode().Perm()),
+		Name:          info.Name(),
+		Size:          info.Size(),
+		Mode:          info.Mode().String(),
+		ModTime:       info.ModTime().String(),
+		IsDir:         info.IsDir(),
+		Owner:         strconv.FormatUint(uint64(stat.Uid), 10),
+		Group:         strconv.FormatUint(uint64(stat.Gid), 10),
+		Permissions:   fmt.Sprintf("%04o", info.Mode().Perm()),
+		Uid:           stat.Uid,
+		Gid:           stat.Gid,
+		IsSymlink:     isSymlink,
+		SymlinkTarget: symlinkTarget,
 	}, nil
 }
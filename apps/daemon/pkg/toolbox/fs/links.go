@@ -0,0 +1,91 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSymlink godoc
+//
+//	@Summary		Create a symbolic link
+//	@Description	Create a symbolic link at path pointing to target. The target is stored as given and does not need to exist.
+//	@Tags			file-system
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateLinkRequest	true	"Link request"
+//	@Success		201		{object}	FileInfo
+//	@Router			/files/symlink [post]
+//
+//	@id				CreateSymlink
+func CreateSymlink(c *gin.Context) {
+	createLink(c, os.Symlink)
+}
+
+// CreateHardlink godoc
+//
+//	@Summary		Create a hard link
+//	@Description	Create a hard link at path to the existing file target
+//	@Tags			file-system
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateLinkRequest	true	"Link request"
+//	@Success		201		{object}	FileInfo
+//	@Router			/files/hardlink [post]
+//
+//	@id				CreateHardlink
+func CreateHardlink(c *gin.Context) {
+	createLink(c, os.Link)
+}
+
+func createLink(c *gin.Context, link func(target, path string) error) {
+	var req CreateLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	absPath, err := filepath.Abs(req.Path)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid path: %w", err))
+		return
+	}
+
+	if _, err := os.Lstat(absPath); err == nil {
+		if !req.Force {
+			c.AbortWithError(http.StatusConflict, fmt.Errorf("%s already exists", absPath))
+			return
+		}
+		if err := os.Remove(absPath); err != nil {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("failed to replace %s: %w", absPath, err))
+			return
+		}
+	}
+
+	if err := link(req.Target, absPath); err != nil {
+		if os.IsNotExist(err) {
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		}
+		if os.IsPermission(err) {
+			c.AbortWithError(http.StatusForbidden, err)
+			return
+		}
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	info, err := getFileInfo(absPath)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, info)
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daemon/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const maxStoragePressureEvents = 20
+
+// StoragePressureController receives storage pressure events from the runner and passes them on to
+// clients of the event stream and to tools in the sandbox, which read the latest event from a file
+type StoragePressureController struct {
+	statusFile string
+
+	mu          sync.Mutex
+	events      []StoragePressureEvent
+	subscribers map[chan StoragePressureEvent]struct{}
+}
+
+func NewStoragePressureController(configDir string) *StoragePressureController {
+	return &StoragePressureController{
+		statusFile:  filepath.Join(configDir, "storage-pressure.json"),
+		subscribers: make(map[chan StoragePressureEvent]struct{}),
+	}
+}
+
+// ReportStoragePressure godoc
+//
+//	@Summary		Report storage pressure
+//	@Description	Report that the storage usage of the sandbox reached a threshold of its quota. Sent by the runner.
+//	@Tags			file-system
+//	@Accept			json
+//	@Param			event	body	StoragePressureEvent	true	"Storage pressure event"
+//	@Success		204
+//	@Router			/files/usage/pressure [post]
+//
+//	@id				ReportStoragePressure
+func (s *StoragePressureController) ReportStoragePressure(c *gin.Context) {
+	var event StoragePressureEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	log.Warnf("Storage usage reached %d%% of the quota (%d of %d bytes)", event.ThresholdPercent, event.UsedBytes, event.QuotaBytes)
+
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	if len(s.events) > maxStoragePressureEvents {
+		s.events = s.events[len(s.events)-maxStoragePressureEvents:]
+	}
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.writeStatusFile(event); err != nil {
+		log.Errorf("Failed to write storage pressure status file: %v", err)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListStoragePressureEvents godoc
+//
+//	@Summary		List storage pressure events
+//	@Description	List the latest storage pressure events reported for the sandbox
+//	@Tags			file-system
+//	@Produce		json
+//	@Success		200	{array}	StoragePressureEvent
+//	@Router			/files/usage/pressure [get]
+//
+//	@id				ListStoragePressureEvents
+func (s *StoragePressureController) ListStoragePressureEvents(c *gin.Context) {
+	s.mu.Lock()
+	events := append([]StoragePressureEvent{}, s.events...)
+	s.mu.Unlock()
+
+	c.JSON(http.StatusOK, events)
+}
+
+// WatchStoragePressure godoc
+//
+//	@Summary		Watch storage pressure
+//	@Description	Stream storage pressure events as JSON objects, over a WebSocket if the request is an upgrade
+//	@Description	request and as Server-Sent Events otherwise.
+//	@Tags			file-system
+//	@Produce		json
+//	@Success		200	{object}	StoragePressureEvent
+//	@Success		101	"Switching Protocols - WebSocket connection established"
+//	@Router			/files/usage/pressure/events [get]
+//
+//	@id				WatchStoragePressure
+func (s *StoragePressureController) WatchStoragePressure(c *gin.Context) {
+	events, unsubscribe := s.subscribe()
+	defer unsubscribe()
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		ws, err := util.UpgradeToWebSocket(c.Writer, c.Request)
+		if err != nil {
+			log.Errorf("Failed to upgrade storage pressure events connection: %v", err)
+			return
+		}
+		defer ws.Close()
+
+		ctx, cancel := util.ContextWithCancelOnClose(c.Request.Context(), ws)
+		defer cancel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				_ = ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+				if err := ws.WriteJSON(event); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	ctx := c.Request.Context()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event := <-events:
+			c.SSEvent("storage-pressure", event)
+			return true
+		}
+	})
+}
+
+func (s *StoragePressureController) subscribe() (<-chan StoragePressureEvent, func()) {
+	ch := make(chan StoragePressureEvent, 16)
+
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+		close(ch)
+	}
+}
+
+// writeStatusFile replaces the status file atomically so that readers never see a partial event
+func (s *StoragePressureController) writeStatusFile(event StoragePressureEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.statusFile), 0755); err != nil {
+		return err
+	}
+
+	tmpFile := s.statusFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, s.statusFile)
+}
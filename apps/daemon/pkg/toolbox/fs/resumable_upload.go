@@ -0,0 +1,421 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Uploads that have not received data for this long are removed
+const uploadExpiration = 24 * time.Hour
+
+// UploadController manages resumable uploads. Upload state is kept on disk so that
+// clients can resume after a dropped connection or a daemon restart.
+type UploadController struct {
+	dir string
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func NewUploadController(configDir string) *UploadController {
+	return &UploadController{
+		dir:   filepath.Join(configDir, "uploads"),
+		locks: make(map[string]*sync.Mutex),
+	}
+}
+
+// CreateUpload godoc
+//
+//	@Summary		Start a resumable upload
+//	@Description	Start a chunked upload to the specified path. Chunks are written to a staging file
+//	@Description	and moved to the destination path when the upload is finalized.
+//	@Tags			file-system
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateUploadRequest	true	"Upload request"
+//	@Success		201		{object}	UploadSession
+//	@Router			/files/uploads [post]
+//
+//	@id				CreateUpload
+func (u *UploadController) CreateUpload(c *gin.Context) {
+	var req CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	absPath, err := filepath.Abs(req.Path)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid path: %w", err))
+		return
+	}
+
+	if err := os.MkdirAll(u.dir, 0755); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to create uploads directory: %w", err))
+		return
+	}
+
+	u.removeExpired()
+
+	upload := UploadSession{
+		Id:        uuid.NewString(),
+		Path:      absPath,
+		Size:      req.Size,
+		Sha256:    strings.ToLower(req.Sha256),
+		CreatedAt: time.Now(),
+	}
+
+	if err := os.WriteFile(u.dataPath(upload.Id), nil, 0600); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to create upload file: %w", err))
+		return
+	}
+
+	if err := u.saveMetadata(upload); err != nil {
+		os.Remove(u.dataPath(upload.Id))
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, upload)
+}
+
+// GetUpload godoc
+//
+//	@Summary		Get resumable upload status
+//	@Description	Get the status of a resumable upload. The offset is the number of bytes received so far
+//	@Description	and is where the next chunk should start.
+//	@Tags			file-system
+//	@Produce		json
+//	@Param			uploadId	path		string	true	"Upload ID"
+//	@Success		200			{object}	UploadSession
+//	@Router			/files/uploads/{uploadId} [get]
+//
+//	@id				GetUpload
+func (u *UploadController) GetUpload(c *gin.Context) {
+	upload, err := u.load(c.Param("uploadId"))
+	if err != nil {
+		u.abortWithUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, upload)
+}
+
+// UploadChunk godoc
+//
+//	@Summary		Upload a chunk
+//	@Description	Write the request body at the given offset. The offset must not be past the bytes already received;
+//	@Description	data after the offset is discarded so that a chunk can be retried safely.
+//	@Tags			file-system
+//	@Accept			octet-stream
+//	@Produce		json
+//	@Param			uploadId	path		string	true	"Upload ID"
+//	@Param			offset		query		int		true	"Offset of the chunk in the file"
+//	@Success		200			{object}	UploadSession
+//	@Failure		409			{object}	UploadSession	"Offset does not match the received bytes"
+//	@Router			/files/uploads/{uploadId} [put]
+//
+//	@id				UploadChunk
+func (u *UploadController) UploadChunk(c *gin.Context) {
+	uploadId := c.Param("uploadId")
+
+	offset, err := strconv.ParseInt(c.Query("offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.AbortWithError(http.StatusBadRequest, errors.New("offset must be a non-negative integer"))
+		return
+	}
+
+	lock := u.lock(uploadId)
+	lock.Lock()
+	defer lock.Unlock()
+
+	upload, err := u.load(uploadId)
+	if err != nil {
+		u.abortWithUploadError(c, err)
+		return
+	}
+
+	if offset > upload.Offset {
+		c.JSON(http.StatusConflict, upload)
+		return
+	}
+
+	file, err := os.OpenFile(u.dataPath(uploadId), os.O_WRONLY, 0600)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	defer file.Close()
+
+	// Drop anything past the offset so a retried chunk replaces the partially received one
+	if err := file.Truncate(offset); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	written, err := io.Copy(io.NewOffsetWriter(file, offset), c.Request.Body)
+	upload.Offset = offset + written
+
+	if err != nil {
+		// Keep what was received, the client can resume from the returned offset
+		log.Debugf("Upload %s interrupted at offset %d: %v", uploadId, upload.Offset, err)
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("failed to write chunk: %w", err))
+		return
+	}
+
+	if upload.Size > 0 && upload.Offset > upload.Size {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("upload exceeds the declared size of %d bytes", upload.Size))
+		return
+	}
+
+	c.JSON(http.StatusOK, upload)
+}
+
+// FinalizeUpload godoc
+//
+//	@Summary		Finalize a resumable upload
+//	@Description	Verify the size and SHA-256 checksum of the received data and move it to the destination path
+//	@Tags			file-system
+//	@Accept			json
+//	@Produce		json
+//	@Param			uploadId	path		string					true	"Upload ID"
+//	@Param			request		body		FinalizeUploadRequest	false	"Finalize request"
+//	@Success		200			{object}	FileChecksumResponse
+//	@Router			/files/uploads/{uploadId}/finalize [post]
+//
+//	@id				FinalizeUpload
+func (u *UploadController) FinalizeUpload(c *gin.Context) {
+	uploadId := c.Param("uploadId")
+
+	var req FinalizeUploadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+	}
+
+	lock := u.lock(uploadId)
+	lock.Lock()
+	defer lock.Unlock()
+
+	upload, err := u.load(uploadId)
+	if err != nil {
+		u.abortWithUploadError(c, err)
+		return
+	}
+
+	if upload.Size > 0 && upload.Offset != upload.Size {
+		c.AbortWithError(http.StatusConflict, fmt.Errorf("upload is incomplete: received %d of %d bytes", upload.Offset, upload.Size))
+		return
+	}
+
+	checksum, err := fileSha256(u.dataPath(uploadId))
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, expected := range []string{upload.Sha256, strings.ToLower(req.Sha256)} {
+		if expected != "" && expected != checksum {
+			c.AbortWithError(http.StatusUnprocessableEntity, fmt.Errorf("checksum mismatch: expected %s, got %s", expected, checksum))
+			return
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(upload.Path), 0755); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("failed to create parent directory: %w", err))
+		return
+	}
+
+	if err := moveFile(u.dataPath(uploadId), upload.Path); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("failed to move upload to %s: %w", upload.Path, err))
+		return
+	}
+
+	u.remove(uploadId)
+
+	c.JSON(http.StatusOK, FileChecksumResponse{
+		Path:   upload.Path,
+		Size:   upload.Offset,
+		Sha256: checksum,
+	})
+}
+
+// AbortUpload godoc
+//
+//	@Summary		Abort a resumable upload
+//	@Description	Abort a resumable upload and discard the received data
+//	@Tags			file-system
+//	@Param			uploadId	path	string	true	"Upload ID"
+//	@Success		204
+//	@Router			/files/uploads/{uploadId} [delete]
+//
+//	@id				AbortUpload
+func (u *UploadController) AbortUpload(c *gin.Context) {
+	uploadId := c.Param("uploadId")
+
+	lock := u.lock(uploadId)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := u.load(uploadId); err != nil {
+		u.abortWithUploadError(c, err)
+		return
+	}
+
+	u.remove(uploadId)
+	c.Status(http.StatusNoContent)
+}
+
+var errUploadNotFound = errors.New("upload not found")
+
+func (u *UploadController) abortWithUploadError(c *gin.Context, err error) {
+	if errors.Is(err, errUploadNotFound) {
+		c.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+	c.AbortWithError(http.StatusInternalServerError, err)
+}
+
+func (u *UploadController) lock(uploadId string) *sync.Mutex {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	lock, ok := u.locks[uploadId]
+	if !ok {
+		lock = &sync.Mutex{}
+		u.locks[uploadId] = lock
+	}
+	return lock
+}
+
+func (u *UploadController) dataPath(uploadId string) string {
+	return filepath.Join(u.dir, uploadId+".part")
+}
+
+func (u *UploadController) metadataPath(uploadId string) string {
+	return filepath.Join(u.dir, uploadId+".json")
+}
+
+// load reads the upload metadata; the offset is derived from the size of the staged data
+func (u *UploadController) load(uploadId string) (UploadSession, error) {
+	// Upload IDs are used in file names, reject anything that is not one of ours
+	if _, err := uuid.Parse(uploadId); err != nil {
+		return UploadSession{}, errUploadNotFound
+	}
+
+	data, err := os.ReadFile(u.metadataPath(uploadId))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return UploadSession{}, errUploadNotFound
+		}
+		return UploadSession{}, err
+	}
+
+	var upload UploadSession
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return UploadSession{}, fmt.Errorf("failed to read upload metadata: %w", err)
+	}
+
+	info, err := os.Stat(u.dataPath(uploadId))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return UploadSession{}, errUploadNotFound
+		}
+		return UploadSession{}, err
+	}
+	upload.Offset = info.Size()
+
+	return upload, nil
+}
+
+func (u *UploadController) saveMetadata(upload UploadSession) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(u.metadataPath(upload.Id), data, 0600); err != nil {
+		return fmt.Errorf("failed to write upload metadata: %w", err)
+	}
+
+	return nil
+}
+
+func (u *UploadController) remove(uploadId string) {
+	os.Remove(u.dataPath(uploadId))
+	os.Remove(u.metadataPath(uploadId))
+
+	u.mu.Lock()
+	delete(u.locks, uploadId)
+	u.mu.Unlock()
+}
+
+func (u *UploadController) removeExpired() {
+	entries, err := os.ReadDir(u.dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		uploadId, ok := strings.CutSuffix(entry.Name(), ".part")
+		if !ok {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < uploadExpiration {
+			continue
+		}
+
+		log.Debugf("Removing expired upload %s", uploadId)
+		u.remove(uploadId)
+	}
+}
+
+// moveFile renames src to dst, falling back to a copy if they are on different filesystems
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(src)
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchMaxResults  = 1000
+	defaultSearchMaxFileSize = 10 * 1024 * 1024
+	maxSearchLineLength      = 1024 * 1024
+)
+
+var errSearchLimitReached = errors.New("search limit reached")
+
+// Grep godoc
+//
+//	@Summary		Search files and contents
+//	@Description	Search file contents with a regular expression, or file names only if no query is given.
+//	@Description	Files can be filtered with include/exclude globs; .gitignore, .ignore and .rgignore files are honored unless noIgnore is set.
+//	@Description	Files that could not be searched completely, e.g. because of lines over 1 MiB, are listed in errors.
+//	@Tags			file-system
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SearchRequest	true	"Search request"
+//	@Success		200		{object}	SearchResponse
+//	@Router			/files/grep [post]
+//
+//	@id				Grep
+func Grep(c *gin.Context) {
+	var req SearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	var matcher *regexp.Regexp
+	if req.Query != "" {
+		expr := req.Query
+		if req.FixedStrings {
+			expr = regexp.QuoteMeta(expr)
+		}
+		if !req.CaseSensitive {
+			expr = "(?i)" + expr
+		}
+
+		var err error
+		matcher, err = regexp.Compile(expr)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid query: %w", err))
+			return
+		}
+	}
+
+	if req.MaxResults <= 0 {
+		req.MaxResults = defaultSearchMaxResults
+	}
+	if req.MaxFileSize <= 0 {
+		req.MaxFileSize = defaultSearchMaxFileSize
+	}
+
+	response := SearchResponse{
+		Files:   []string{},
+		Matches: []SearchMatch{},
+		Errors:  []SearchError{},
+	}
+	ignores := &ignoreMatcher{}
+
+	err := filepath.WalkDir(req.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == req.Path {
+				return err
+			}
+			return nil
+		}
+
+		if path != req.Path {
+			if !req.IncludeHidden && strings.HasPrefix(d.Name(), ".") {
+				return skipEntry(d)
+			}
+			if d.IsDir() && d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if !req.NoIgnore && ignores.ignored(path, d.IsDir()) {
+				return skipEntry(d)
+			}
+			if matchesAnyGlob(req.Path, path, req.Exclude) {
+				return skipEntry(d)
+			}
+		}
+
+		if d.IsDir() {
+			if !req.NoIgnore {
+				ignores.loadDir(path)
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if len(req.Include) > 0 && !matchesAnyGlob(req.Path, path, req.Include) {
+			return nil
+		}
+
+		if matcher == nil {
+			if len(response.Files) >= req.MaxResults {
+				response.Truncated = true
+				return errSearchLimitReached
+			}
+			response.Files = append(response.Files, path)
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || info.Size() > req.MaxFileSize {
+			return nil
+		}
+
+		matches, limitReached, err := searchFile(path, matcher, req.ContextLines, req.MaxResults-len(response.Matches))
+		response.Matches = append(response.Matches, matches...)
+		if err != nil {
+			response.Errors = append(response.Errors, SearchError{File: path, Error: err.Error()})
+		}
+		if limitReached {
+			response.Truncated = true
+			return errSearchLimitReached
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errSearchLimitReached) {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func skipEntry(d fs.DirEntry) error {
+	if d.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+func matchesAnyGlob(root, path string, globs []string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	name := filepath.Base(path)
+
+	for _, glob := range globs {
+		if ok, _ := filepath.Match(glob, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(glob, rel); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// searchFile returns up to limit matching lines of a text file and whether the limit was hit. If the
+// file can't be read to its end, the matches found until then are returned along with the error.
+func searchFile(path string, matcher *regexp.Regexp, contextLines int, limit int) ([]SearchMatch, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	// skip binary files
+	head := make([]byte, 512)
+	n, err := file.Read(head)
+	if err != nil && err != io.EOF {
+		return nil, false, err
+	}
+	for _, b := range head[:n] {
+		if b == 0 {
+			return nil, false, nil
+		}
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, false, err
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxSearchLineLength)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	scanErr := scanner.Err()
+	if errors.Is(scanErr, bufio.ErrTooLong) {
+		scanErr = fmt.Errorf("line %d is longer than %d bytes, the rest of the file was not searched", len(lines)+1, maxSearchLineLength)
+	}
+
+	var matches []SearchMatch
+	for i, line := range lines {
+		loc := matcher.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+
+		if len(matches) >= limit {
+			return matches, true, scanErr
+		}
+
+		match := SearchMatch{
+			File:    path,
+			Line:    i + 1,
+			Column:  loc[0] + 1,
+			Content: line,
+		}
+		if contextLines > 0 {
+			match.Before = lines[max(i-contextLines, 0):i]
+			match.After = lines[i+1 : min(i+1+contextLines, len(lines))]
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, false, scanErr
+}
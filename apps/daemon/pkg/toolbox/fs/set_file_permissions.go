@@ -6,6 +6,7 @@ package fs
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
 	"os/user"
@@ -20,10 +21,11 @@ import (
 //	@Summary		Set file permissions
 //	@Description	Set file permissions, ownership, and group for a file or directory
 //	@Tags			file-system
-//	@Param			path	query	string	true	"File or directory path"
-//	@Param			owner	query	string	false	"Owner (username or UID)"
-//	@Param			group	query	string	false	"Group (group name or GID)"
-//	@Param			mode	query	string	false	"File mode in octal format (e.g., 0755)"
+//	@Param			path		query	string	true	"File or directory path"
+//	@Param			owner		query	string	false	"Owner (username or UID)"
+//	@Param			group		query	string	false	"Group (group name or GID)"
+//	@Param			mode		query	string	false	"File mode in octal format (e.g., 0755)"
+//	@Param			recursive	query	bool	false	"Apply to the directory contents as well. Symlinks are not followed."
 //	@Success		200
 //	@Router			/files/permissions [post]
 //
@@ -33,6 +35,7 @@ func SetFilePermissions(c *gin.Context) {
 	ownerParam := c.Query("owner")
 	groupParam := c.Query("group")
 	mode := c.Query("mode")
+	recursive := c.Query("recursive") == "true"
 
 	if path == "" {
 		c.AbortWithError(http.StatusBadRequest, errors.New("path is required"))
@@ -60,68 +63,100 @@ func SetFilePermissions(c *gin.Context) {
 		return
 	}
 
-	// handle mode change
+	var fileMode *os.FileMode
 	if mode != "" {
 		modeNum, err := strconv.ParseUint(mode, 8, 32)
 		if err != nil {
 			c.AbortWithError(http.StatusBadRequest, errors.New("invalid mode format"))
 			return
 		}
-
-		if err := os.Chmod(absPath, os.FileMode(modeNum)); err != nil {
-			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("failed to change mode: %w", err))
-			return
-		}
+		m := os.FileMode(modeNum)
+		fileMode = &m
 	}
 
-	// handle ownership change
-	if ownerParam != "" || groupParam != "" {
-		uid := -1
-		gid := -1
+	uid := -1
+	gid := -1
 
-		// resolve owner
-		if ownerParam != "" {
-			// first try as numeric UID
-			if uidNum, err := strconv.Atoi(ownerParam); err == nil {
-				uid = uidNum
-			} else {
-				// try as username
-				if u, err := user.Lookup(ownerParam); err == nil {
-					if uid, err = strconv.Atoi(u.Uid); err != nil {
-						c.AbortWithError(http.StatusBadRequest, errors.New("invalid user ID"))
-						return
-					}
-				} else {
-					c.AbortWithError(http.StatusBadRequest, errors.New("user not found"))
+	// resolve owner
+	if ownerParam != "" {
+		// first try as numeric UID
+		if uidNum, err := strconv.Atoi(ownerParam); err == nil {
+			uid = uidNum
+		} else {
+			// try as username
+			if u, err := user.Lookup(ownerParam); err == nil {
+				if uid, err = strconv.Atoi(u.Uid); err != nil {
+					c.AbortWithError(http.StatusBadRequest, errors.New("invalid user ID"))
 					return
 				}
+			} else {
+				c.AbortWithError(http.StatusBadRequest, errors.New("user not found"))
+				return
 			}
 		}
+	}
 
-		// resolve group
-		if groupParam != "" {
-			// first try as numeric GID
-			if gidNum, err := strconv.Atoi(groupParam); err == nil {
-				gid = gidNum
-			} else {
-				// try as group name
-				if g, err := user.LookupGroup(groupParam); err == nil {
-					if gid, err = strconv.Atoi(g.Gid); err != nil {
-						c.AbortWithError(http.StatusBadRequest, errors.New("invalid group ID"))
-						return
-					}
-				} else {
-					c.AbortWithError(http.StatusBadRequest, errors.New("group not found"))
+	// resolve group
+	if groupParam != "" {
+		// first try as numeric GID
+		if gidNum, err := strconv.Atoi(groupParam); err == nil {
+			gid = gidNum
+		} else {
+			// try as group name
+			if g, err := user.LookupGroup(groupParam); err == nil {
+				if gid, err = strconv.Atoi(g.Gid); err != nil {
+					c.AbortWithError(http.StatusBadRequest, errors.New("invalid group ID"))
 					return
 				}
+			} else {
+				c.AbortWithError(http.StatusBadRequest, errors.New("group not found"))
+				return
 			}
 		}
+	}
 
-		if err := os.Chown(absPath, uid, gid); err != nil {
-			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("failed to change ownership: %w", err))
+	if !recursive {
+		if err := applyFilePermissions(absPath, fileMode, uid, gid, false); err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
 			return
 		}
+		c.Status(http.StatusOK)
+		return
+	}
+
+	err = filepath.WalkDir(absPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return applyFilePermissions(path, fileMode, uid, gid, d.Type()&os.ModeSymlink != 0)
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
 	}
 
 	c.Status(http.StatusOK)
 }
+
+func applyFilePermissions(path string, mode *os.FileMode, uid, gid int, isSymlink bool) error {
+	// symlink permissions can't be changed on Linux, only their ownership
+	if mode != nil && !isSymlink {
+		if err := os.Chmod(path, *mode); err != nil {
+			return fmt.Errorf("failed to change mode of %s: %w", path, err)
+		}
+	}
+
+	if uid == -1 && gid == -1 {
+		return nil
+	}
+
+	chown := os.Chown
+	if isSymlink {
+		chown = os.Lchown
+	}
+	if err := chown(path, uid, gid); err != nil {
+		return fmt.Errorf("failed to change ownership of %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@
 
 package fs
 
+import "time"
+
 type FileInfo struct {
 	Name        string `json:"name" validate:"required"`
 	Size        int64  `json:"size" validate:"required"`
@@ -12,6 +14,11 @@ type FileInfo struct {
 	Owner       string `json:"owner" validate:"required"`
 	Group       string `json:"group" validate:"required"`
 	Permissions string `json:"permissions" validate:"required"`
+	Uid         uint32 `json:"uid" validate:"required"`
+	Gid         uint32 `json:"gid" validate:"required"`
+	IsSymlink   bool   `json:"isSymlink" validate:"required"`
+	// Target of the symlink as stored in the link, only set if IsSymlink is true
+	SymlinkTarget string `json:"symlinkTarget,omitempty" validate:"optional"`
 } // @name FileInfo
 
 type ReplaceRequest struct {
@@ -39,3 +46,141 @@ type SearchFilesResponse struct {
 type FilesDownloadRequest struct {
 	Paths []string `json:"paths" validate:"required"`
 } // @name FilesDownloadRequest
+
+type FileWatchEventType string // @name FileWatchEventType
+
+const (
+	FileWatchEventCreate   FileWatchEventType = "create"
+	FileWatchEventWrite    FileWatchEventType = "write"
+	FileWatchEventRemove   FileWatchEventType = "remove"
+	FileWatchEventRename   FileWatchEventType = "rename"
+	FileWatchEventChmod    FileWatchEventType = "chmod"
+	FileWatchEventOverflow FileWatchEventType = "overflow"
+)
+
+type FileWatchEvent struct {
+	Type  FileWatchEventType `json:"type" validate:"required"`
+	Path  string             `json:"path" validate:"required"`
+	IsDir bool               `json:"isDir" validate:"required"`
+	Time  time.Time          `json:"time" validate:"required"`
+} // @name FileWatchEvent
+
+type SearchRequest struct {
+	Path string `json:"path" validate:"required"`
+	// Regular expression searched for in file contents. If empty, only file names are matched.
+	Query         string `json:"query,omitempty" validate:"optional"`
+	FixedStrings  bool   `json:"fixedStrings,omitempty" validate:"optional"`
+	CaseSensitive bool   `json:"caseSensitive,omitempty" validate:"optional"`
+	// Globs matched against file names or paths relative to Path
+	Include       []string `json:"include,omitempty" validate:"optional"`
+	Exclude       []string `json:"exclude,omitempty" validate:"optional"`
+	NoIgnore      bool     `json:"noIgnore,omitempty" validate:"optional"`
+	IncludeHidden bool     `json:"includeHidden,omitempty" validate:"optional"`
+	ContextLines  int      `json:"contextLines,omitempty" validate:"optional,min=0,max=100"`
+	MaxResults    int      `json:"maxResults,omitempty" validate:"optional,min=0"`
+	MaxFileSize   int64    `json:"maxFileSize,omitempty" validate:"optional,min=0"`
+} // @name SearchRequest
+
+type SearchMatch struct {
+	File    string   `json:"file" validate:"required"`
+	Line    int      `json:"line" validate:"required"`
+	Column  int      `json:"column" validate:"required"`
+	Content string   `json:"content" validate:"required"`
+	Before  []string `json:"before,omitempty" validate:"optional"`
+	After   []string `json:"after,omitempty" validate:"optional"`
+} // @name SearchMatch
+
+type SearchError struct {
+	File  string `json:"file" validate:"required"`
+	Error string `json:"error" validate:"required"`
+} // @name SearchError
+
+type SearchResponse struct {
+	Files   []string      `json:"files,omitempty" validate:"optional"`
+	Matches []SearchMatch `json:"matches,omitempty" validate:"optional"`
+	// Files that could not be searched completely
+	Errors    []SearchError `json:"errors,omitempty" validate:"optional"`
+	Truncated bool          `json:"truncated" validate:"required"`
+} // @name SearchResponse
+
+type CreateUploadRequest struct {
+	Path string `json:"path" validate:"required"`
+	// Expected total size in bytes, verified on finalize if set
+	Size int64 `json:"size,omitempty" validate:"optional,min=0"`
+	// Expected hex encoded SHA-256 of the whole file, verified on finalize if set
+	Sha256 string `json:"sha256,omitempty" validate:"optional"`
+} // @name CreateUploadRequest
+
+type UploadSession struct {
+	Id        string    `json:"id" validate:"required"`
+	Path      string    `json:"path" validate:"required"`
+	Size      int64     `json:"size,omitempty" validate:"optional"`
+	Sha256    string    `json:"sha256,omitempty" validate:"optional"`
+	Offset    int64     `json:"offset" validate:"required"`
+	CreatedAt time.Time `json:"createdAt" validate:"required"`
+} // @name UploadSession
+
+type FinalizeUploadRequest struct {
+	Sha256 string `json:"sha256,omitempty" validate:"optional"`
+} // @name FinalizeUploadRequest
+
+type FileChecksumResponse struct {
+	Path   string `json:"path" validate:"required"`
+	Size   int64  `json:"size" validate:"required"`
+	Sha256 string `json:"sha256" validate:"required"`
+} // @name FileChecksumResponse
+
+type ArchiveFormat string // @name ArchiveFormat
+
+const (
+	ArchiveFormatTar   ArchiveFormat = "tar"
+	ArchiveFormatTarGz ArchiveFormat = "tar.gz"
+	ArchiveFormatZip   ArchiveFormat = "zip"
+)
+
+type CreateArchiveRequest struct {
+	Path string `json:"path" validate:"required"`
+	// Defaults to tar.gz
+	Format ArchiveFormat `json:"format,omitempty" validate:"optional"`
+	// Globs matched against file names or paths relative to Path
+	Include []string `json:"include,omitempty" validate:"optional"`
+	Exclude []string `json:"exclude,omitempty" validate:"optional"`
+} // @name CreateArchiveRequest
+
+type ExtractArchiveResponse struct {
+	Path  string `json:"path" validate:"required"`
+	Files int    `json:"files" validate:"required"`
+} // @name ExtractArchiveResponse
+
+type CreateLinkRequest struct {
+	// Path the link points to
+	Target string `json:"target" validate:"required"`
+	// Path of the link to create
+	Path string `json:"path" validate:"required"`
+	// Replace an existing file at Path
+	Force bool `json:"force,omitempty" validate:"optional"`
+} // @name CreateLinkRequest
+
+type MountUsage struct {
+	Path           string `json:"path" validate:"required"`
+	FsType         string `json:"fsType" validate:"required"`
+	TotalBytes     uint64 `json:"totalBytes" validate:"required"`
+	UsedBytes      uint64 `json:"usedBytes" validate:"required"`
+	AvailableBytes uint64 `json:"availableBytes" validate:"required"`
+} // @name MountUsage
+
+type FilesystemUsage struct {
+	// Root filesystem of the sandbox, its total is the storage quota
+	Root MountUsage `json:"root" validate:"required"`
+	// Mounted volumes, which don't count against the storage quota
+	Volumes   []MountUsage `json:"volumes" validate:"required"`
+	SampledAt time.Time    `json:"sampledAt" validate:"required"`
+} // @name FilesystemUsage
+
+type StoragePressureEvent struct {
+	// Usage in percent of the storage quota that was reached
+	ThresholdPercent int       `json:"thresholdPercent" validate:"required"`
+	UsedBytes        int64     `json:"usedBytes" validate:"required"`
+	QuotaBytes       int64     `json:"quotaBytes" validate:"required"`
+	Time             time.Time `json:"time" validate:"required"`
+} // @name StoragePressureEvent
@@ -0,0 +1,160 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"bufio"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Volumes are FUSE mounts backed by object storage, so statfs calls on them are not free
+const usageCacheTTL = 30 * time.Second
+
+var (
+	usageMutex  sync.Mutex
+	cachedUsage *FilesystemUsage
+)
+
+// GetFilesystemUsage godoc
+//
+//	@Summary		Get filesystem usage
+//	@Description	Get the used and available space of the sandbox root filesystem, which is limited by the storage
+//	@Description	quota of the sandbox, and of the mounted volumes. The usage is sampled at most every 30 seconds.
+//	@Tags			file-system
+//	@Produce		json
+//	@Success		200	{object}	FilesystemUsage
+//	@Router			/files/usage [get]
+//
+//	@id				GetFilesystemUsage
+func GetFilesystemUsage(c *gin.Context) {
+	usageMutex.Lock()
+	defer usageMutex.Unlock()
+
+	if cachedUsage == nil || time.Since(cachedUsage.SampledAt) > usageCacheTTL {
+		usage, err := getFilesystemUsage()
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		cachedUsage = usage
+	}
+
+	c.JSON(http.StatusOK, cachedUsage)
+}
+
+func getFilesystemUsage() (*FilesystemUsage, error) {
+	root, err := getMountUsage("/", "")
+	if err != nil {
+		return nil, err
+	}
+
+	mounts, err := readMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := []MountUsage{}
+	for path, fsType := range mounts {
+		if path == "/" {
+			root.FsType = fsType
+			continue
+		}
+
+		if !strings.HasPrefix(fsType, "fuse") {
+			continue
+		}
+
+		usage, err := getMountUsage(path, fsType)
+		if err != nil {
+			continue
+		}
+		volumes = append(volumes, usage)
+	}
+
+	slices.SortFunc(volumes, func(a, b MountUsage) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return &FilesystemUsage{
+		Root:      root,
+		Volumes:   volumes,
+		SampledAt: time.Now(),
+	}, nil
+}
+
+func getMountUsage(path, fsType string) (MountUsage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return MountUsage{}, err
+	}
+
+	total := stat.Blocks * uint64(stat.Bsize)
+	free := stat.Bfree * uint64(stat.Bsize)
+
+	return MountUsage{
+		Path:           path,
+		FsType:         fsType,
+		TotalBytes:     total,
+		UsedBytes:      total - free,
+		AvailableBytes: stat.Bavail * uint64(stat.Bsize),
+	}, nil
+}
+
+// readMounts returns the filesystem types of the mount points of the sandbox by path
+func readMounts() (map[string]string, error) {
+	file, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mounts := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// The mount point is the fifth field, the filesystem type follows the "-" separator
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		for i := 5; i < len(fields)-1; i++ {
+			if fields[i] == "-" {
+				mounts[unescapeMountPath(fields[4])] = fields[i+1]
+				break
+			}
+		}
+	}
+
+	return mounts, scanner.Err()
+}
+
+// unescapeMountPath decodes the octal escapes of spaces and other special characters in mountinfo
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, `\`) {
+		return path
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if c, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(path[i])
+	}
+
+	return b.String()
+}
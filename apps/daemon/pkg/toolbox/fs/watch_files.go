@@ -0,0 +1,114 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/daytonaio/daemon/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultWatchDebounce = 100 * time.Millisecond
+
+// WatchFiles godoc
+//
+//	@Summary		Watch files for changes
+//	@Description	Stream file change events for a path. Events are sent in debounced batches as JSON arrays,
+//	@Description	over a WebSocket if the request is an upgrade request and as Server-Sent Events otherwise.
+//	@Tags			file-system
+//	@Produce		json
+//	@Param			path		query	string		true	"File or directory path to watch"
+//	@Param			recursive	query	bool		false	"Watch subdirectories"
+//	@Param			pattern		query	[]string	false	"Glob patterns matched against file names or paths relative to the watched path"
+//	@Param			debounceMs	query	int			false	"Debounce window in milliseconds (default 100)"
+//	@Success		200			{array}	FileWatchEvent
+//	@Success		101			"Switching Protocols - WebSocket connection established"
+//	@Router			/files/watch [get]
+//
+//	@id				WatchFiles
+func WatchFiles(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		c.AbortWithError(http.StatusBadRequest, errors.New("path is required"))
+		return
+	}
+
+	opts := watchOptions{
+		recursive: c.Query("recursive") == "true",
+		patterns:  c.QueryArray("pattern"),
+		debounce:  defaultWatchDebounce,
+	}
+
+	if debounceMs := c.Query("debounceMs"); debounceMs != "" {
+		ms, err := strconv.Atoi(debounceMs)
+		if err != nil || ms < 0 {
+			c.AbortWithError(http.StatusBadRequest, errors.New("debounceMs must be a non-negative integer"))
+			return
+		}
+		opts.debounce = time.Duration(ms) * time.Millisecond
+	}
+
+	watcher, err := newFileWatcher(path, opts)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	defer watcher.Close()
+
+	ctx := c.Request.Context()
+	events := make(chan []FileWatchEvent, 16)
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		ws, err := util.UpgradeToWebSocket(c.Writer, c.Request)
+		if err != nil {
+			log.Errorf("Failed to upgrade watch connection: %v", err)
+			return
+		}
+		defer ws.Close()
+
+		ctx, cancel := util.ContextWithCancelOnClose(ctx, ws)
+		defer cancel()
+
+		go func() {
+			if err := watcher.Run(ctx, events); err != nil {
+				log.Errorf("File watcher for %s failed: %v", path, err)
+			}
+		}()
+
+		for batch := range events {
+			_ = ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := ws.WriteJSON(batch); err != nil {
+				cancel()
+			}
+		}
+		return
+	}
+
+	go func() {
+		if err := watcher.Run(ctx, events); err != nil {
+			log.Errorf("File watcher for %s failed: %v", path, err)
+		}
+	}()
+
+	c.Stream(func(w io.Writer) bool {
+		batch, ok := <-events
+		if !ok {
+			return false
+		}
+		c.SSEvent("change", batch)
+		return true
+	})
+
+	// Drain so the watcher can exit if the client went away mid-send
+	for range events {
+	}
+}
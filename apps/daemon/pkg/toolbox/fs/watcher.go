@@ -0,0 +1,273 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const watchMask = unix.IN_CREATE | unix.IN_CLOSE_WRITE | unix.IN_MODIFY | unix.IN_DELETE | unix.IN_DELETE_SELF |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_MOVE_SELF | unix.IN_ATTRIB
+
+type watchOptions struct {
+	recursive bool
+	patterns  []string
+	debounce  time.Duration
+}
+
+// fileWatcher streams batches of file change events under a root path using inotify
+type fileWatcher struct {
+	fd      int
+	root    string
+	opts    watchOptions
+	watches map[int]string // watch descriptor -> directory path
+
+	closeOnce sync.Once
+}
+
+func newFileWatcher(root string, opts watchOptions) (*fileWatcher, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := unix.InotifyInit1(unix.IN_NONBLOCK | unix.IN_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+
+	w := &fileWatcher{
+		fd:      fd,
+		root:    root,
+		opts:    opts,
+		watches: make(map[int]string),
+	}
+
+	if !info.IsDir() || !opts.recursive {
+		err = w.addWatch(root)
+	} else {
+		err = w.addRecursive(root)
+	}
+	if err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *fileWatcher) addWatch(path string) error {
+	wd, err := unix.InotifyAddWatch(w.fd, path, watchMask)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+	w.watches[wd] = path
+	return nil
+}
+
+func (w *fileWatcher) addRecursive(root string) error {
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			// The directory may have disappeared or may not be readable, skip it
+			if path != root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err := w.addWatch(path); err != nil {
+			if path == root {
+				return err
+			}
+			log.Debugf("Skipping watch of %s: %v", path, err)
+		}
+		return nil
+	})
+}
+
+// Close releases the inotify instance. It is closed by Run, and must be closed by the caller if
+// Run isn't called.
+func (w *fileWatcher) Close() {
+	w.closeOnce.Do(func() {
+		unix.Close(w.fd)
+	})
+}
+
+// Run reads events until the context is cancelled and sends them to out in debounced batches.
+// Events for the same path within a debounce window are coalesced into the latest one.
+func (w *fileWatcher) Run(ctx context.Context, out chan<- []FileWatchEvent) error {
+	defer w.Close()
+	defer close(out)
+
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	pending := make(map[string]FileWatchEvent)
+	order := []string{}
+	var flushAt time.Time
+
+	flush := func() bool {
+		if len(order) == 0 {
+			return true
+		}
+		batch := make([]FileWatchEvent, 0, len(order))
+		for _, p := range order {
+			batch = append(batch, pending[p])
+		}
+		pending = make(map[string]FileWatchEvent)
+		order = order[:0]
+
+		select {
+		case out <- batch:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	pollFds := []unix.PollFd{{Fd: int32(w.fd), Events: unix.POLLIN}}
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		timeout := 250
+		if len(order) > 0 {
+			timeout = max(int(time.Until(flushAt).Milliseconds()), 0)
+		}
+
+		n, err := unix.Poll(pollFds, timeout)
+		if err != nil && !errors.Is(err, unix.EINTR) {
+			return fmt.Errorf("failed to poll inotify: %w", err)
+		}
+
+		if n > 0 {
+			read, err := unix.Read(w.fd, buf)
+			if err != nil && !errors.Is(err, unix.EAGAIN) {
+				return fmt.Errorf("failed to read inotify events: %w", err)
+			}
+
+			for _, event := range w.parse(buf[:max(read, 0)]) {
+				if !w.matches(event.Path) {
+					continue
+				}
+				if _, ok := pending[event.Path]; !ok {
+					if len(order) == 0 {
+						flushAt = time.Now().Add(w.opts.debounce)
+					}
+					order = append(order, event.Path)
+				}
+				pending[event.Path] = event
+			}
+		}
+
+		if len(order) > 0 && !time.Now().Before(flushAt) {
+			if !flush() {
+				return nil
+			}
+		}
+	}
+}
+
+func (w *fileWatcher) parse(buf []byte) []FileWatchEvent {
+	var events []FileWatchEvent
+
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		raw := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		nameStart := offset + unix.SizeofInotifyEvent
+		nameEnd := nameStart + int(raw.Len)
+		offset = nameEnd
+
+		if raw.Mask&unix.IN_Q_OVERFLOW != 0 {
+			events = append(events, FileWatchEvent{Type: FileWatchEventOverflow, Path: w.root, Time: time.Now()})
+			continue
+		}
+
+		dir, ok := w.watches[int(raw.Wd)]
+		if !ok {
+			continue
+		}
+
+		if raw.Mask&unix.IN_IGNORED != 0 {
+			delete(w.watches, int(raw.Wd))
+			continue
+		}
+
+		path := dir
+		if raw.Len > 0 {
+			path = filepath.Join(dir, strings.TrimRight(string(buf[nameStart:nameEnd]), "\x00"))
+		}
+
+		isDir := raw.Mask&unix.IN_ISDIR != 0
+
+		// Newly created or moved in directories need their own watches in recursive mode
+		if isDir && w.opts.recursive && raw.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+			if err := w.addRecursive(path); err != nil {
+				log.Debugf("Failed to watch new directory %s: %v", path, err)
+			}
+		}
+
+		events = append(events, FileWatchEvent{
+			Type:  eventType(raw.Mask),
+			Path:  path,
+			IsDir: isDir,
+			Time:  time.Now(),
+		})
+	}
+
+	return events
+}
+
+func eventType(mask uint32) FileWatchEventType {
+	switch {
+	case mask&unix.IN_CREATE != 0, mask&unix.IN_MOVED_TO != 0:
+		return FileWatchEventCreate
+	case mask&(unix.IN_DELETE|unix.IN_DELETE_SELF) != 0:
+		return FileWatchEventRemove
+	case mask&(unix.IN_MOVED_FROM|unix.IN_MOVE_SELF) != 0:
+		return FileWatchEventRename
+	case mask&unix.IN_ATTRIB != 0:
+		return FileWatchEventChmod
+	default:
+		return FileWatchEventWrite
+	}
+}
+
+// matches reports whether the path matches any of the glob patterns.
+// Patterns are matched against both the file name and the path relative to the watched root.
+func (w *fileWatcher) matches(path string) bool {
+	if len(w.opts.patterns) == 0 {
+		return true
+	}
+
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		rel = path
+	}
+	name := filepath.Base(path)
+
+	for _, pattern := range w.opts.patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+	}
+
+	return false
+}
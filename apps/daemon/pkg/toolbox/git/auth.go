@@ -0,0 +1,132 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	go_git_http "github.com/go-git/go-git/v5/plumbing/transport/http"
+	go_git_ssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Credentials passed along with a single request. They are only kept in memory
+// for the duration of the operation and are never written to the git config.
+type GitCredentials struct {
+	Username *string `json:"username,omitempty" validate:"optional"`
+	Password *string `json:"password,omitempty" validate:"optional"`
+	// Access token, used as the password for HTTP(S) remotes
+	Token *string `json:"token,omitempty" validate:"optional"`
+	// PEM encoded private key, used for SSH remotes
+	SshPrivateKey *string `json:"sshPrivateKey,omitempty" validate:"optional"`
+	SshPassphrase *string `json:"sshPassphrase,omitempty" validate:"optional"`
+	// known_hosts entries the SSH remote is verified against
+	SshKnownHosts *string `json:"sshKnownHosts,omitempty" validate:"optional"`
+	// Public key of the SSH remote in authorized_keys format, e.g. "ssh-ed25519 AAAA...", as an
+	// alternative to sshKnownHosts
+	SshHostKey *string `json:"sshHostKey,omitempty" validate:"optional"`
+}
+
+func (c GitCredentials) authMethod() (transport.AuthMethod, error) {
+	if c.SshPrivateKey != nil && *c.SshPrivateKey != "" {
+		user := "git"
+		if c.Username != nil && *c.Username != "" {
+			user = *c.Username
+		}
+
+		passphrase := ""
+		if c.SshPassphrase != nil {
+			passphrase = *c.SshPassphrase
+		}
+
+		auth, err := go_git_ssh.NewPublicKeys(user, []byte(*c.SshPrivateKey), passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSH private key: %w", err)
+		}
+
+		auth.HostKeyCallback, err = c.hostKeyCallback()
+		if err != nil {
+			return nil, err
+		}
+
+		return auth, nil
+	}
+
+	if c.Token != nil && *c.Token != "" {
+		user := "git"
+		if c.Username != nil && *c.Username != "" {
+			user = *c.Username
+		}
+
+		return &go_git_http.BasicAuth{
+			Username: user,
+			Password: *c.Token,
+		}, nil
+	}
+
+	if c.Username != nil && c.Password != nil {
+		return &go_git_http.BasicAuth{
+			Username: *c.Username,
+			Password: *c.Password,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// hostKeyCallback verifies SSH remotes against the known hosts or the host key of the credentials.
+// The sandbox has no known_hosts file of its own, so connecting without either is refused.
+func (c GitCredentials) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if c.SshKnownHosts != nil && *c.SshKnownHosts != "" {
+		return knownHostsCallback(*c.SshKnownHosts)
+	}
+
+	if c.SshHostKey != nil && *c.SshHostKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(*c.SshHostKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSH host key: %w", err)
+		}
+
+		// Mismatches are reported like known_hosts ones, which go-git relies on to negotiate the
+		// host key algorithm of the key
+		return func(hostname string, remote net.Addr, presented ssh.PublicKey) error {
+			if bytes.Equal(presented.Marshal(), key.Marshal()) {
+				return nil
+			}
+			return &knownhosts.KeyError{Want: []knownhosts.KnownKey{{Key: key, Filename: "sshHostKey"}}}
+		}, nil
+	}
+
+	return nil, errors.New("sshKnownHosts or sshHostKey is required to verify the SSH remote")
+}
+
+func knownHostsCallback(knownHosts string) (ssh.HostKeyCallback, error) {
+	// Known hosts are read from files, which are only needed until they are parsed
+	f, err := os.CreateTemp("", "known_hosts-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(knownHosts + "\n")
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	callback, err := go_git_ssh.NewKnownHostsCallback(f.Name())
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH known hosts: %w", err)
+	}
+
+	return callback, nil
+}
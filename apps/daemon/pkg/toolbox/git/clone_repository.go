@@ -10,7 +10,6 @@ import (
 	"github.com/daytonaio/daemon/pkg/git"
 	"github.com/daytonaio/daemon/pkg/gitprovider"
 	"github.com/gin-gonic/gin"
-	go_git_http "github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
 // CloneRepository godoc
@@ -51,17 +50,13 @@ func CloneRepository(c *gin.Context) {
 		WorkDir: req.Path,
 	}
 
-	var auth *go_git_http.BasicAuth
-
-	// Set authentication if provided
-	if req.Username != nil && req.Password != nil {
-		auth = &go_git_http.BasicAuth{
-			Username: *req.Username,
-			Password: *req.Password,
-		}
+	auth, err := req.authMethod()
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
 	}
 
-	err := gitService.CloneRepository(&repo, auth)
+	err = gitService.CloneRepository(&repo, auth)
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -0,0 +1,51 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/daytonaio/daemon/pkg/git"
+	"github.com/gin-gonic/gin"
+	go_git "github.com/go-git/go-git/v5"
+)
+
+// FetchChanges godoc
+//
+//	@Summary		Fetch changes from remote
+//	@Description	Fetch branches and tags from the remote Git repository without merging them
+//	@Tags			git
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	GitRepoRequest	true	"Fetch request"
+//	@Success		200
+//	@Router			/git/fetch [post]
+//
+//	@id				FetchChanges
+func FetchChanges(c *gin.Context) {
+	var req GitRepoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	auth, err := req.authMethod()
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	gitService := git.Service{
+		WorkDir: req.Path,
+	}
+
+	err = gitService.Fetch(auth)
+	if err != nil && err != go_git.NoErrAlreadyUpToDate {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/daytonaio/daemon/pkg/git"
 	"github.com/gin-gonic/gin"
 	go_git "github.com/go-git/go-git/v5"
-	go_git_http "github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
 // PullChanges godoc
@@ -32,19 +31,17 @@ func PullChanges(c *gin.Context) {
 		return
 	}
 
-	var auth *go_git_http.BasicAuth
-	if req.Username != nil && req.Password != nil {
-		auth = &go_git_http.BasicAuth{
-			Username: *req.Username,
-			Password: *req.Password,
-		}
+	auth, err := req.authMethod()
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
 	}
 
 	gitService := git.Service{
 		WorkDir: req.Path,
 	}
 
-	err := gitService.Pull(auth)
+	err = gitService.Pull(auth)
 	if err != nil && err != go_git.NoErrAlreadyUpToDate {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -10,10 +10,9 @@ type GitAddRequest struct {
 } // @name GitAddRequest
 
 type GitCloneRequest struct {
+	GitCredentials
 	URL      string  `json:"url" validate:"required"`
 	Path     string  `json:"path" validate:"required"`
-	Username *string `json:"username,omitempty" validate:"optional"`
-	Password *string `json:"password,omitempty" validate:"optional"`
 	Branch   *string `json:"branch,omitempty" validate:"optional"`
 	CommitID *string `json:"commit_id,omitempty" validate:"optional"`
 } // @name GitCloneRequest
@@ -45,9 +44,8 @@ type ListBranchResponse struct {
 } // @name ListBranchResponse
 
 type GitRepoRequest struct {
-	Path     string  `json:"path" validate:"required"`
-	Username *string `json:"username,omitempty" validate:"optional"`
-	Password *string `json:"password,omitempty" validate:"optional"`
+	GitCredentials
+	Path string `json:"path" validate:"required"`
 } // @name GitRepoRequest
 
 type GitCheckoutRequest struct {
@@ -0,0 +1,172 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package toolbox
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/daemon/internal"
+	"github.com/daytonaio/daemon/pkg/session"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+
+	healthCheckTimeout = 3 * time.Second
+
+	// Below this the daemon can't write session logs anymore
+	minFreeDiskBytes = 16 * 1024 * 1024
+	lowFreeDiskBytes = 512 * 1024 * 1024
+)
+
+type HealthCheck struct {
+	// healthy, degraded or unhealthy
+	Status  string `json:"status" validate:"required"`
+	Message string `json:"message,omitempty" validate:"optional"`
+} // @name HealthCheck
+
+type HealthResponse struct {
+	// unhealthy if any check is unhealthy, degraded if any check is degraded
+	Status  string                 `json:"status" validate:"required"`
+	Version string                 `json:"version" validate:"required"`
+	Checks  map[string]HealthCheck `json:"checks" validate:"required"`
+} // @name HealthResponse
+
+type healthChecker struct {
+	server         *Server
+	sessionService *session.SessionService
+}
+
+// GetHealth godoc
+//
+//	@Summary		Get daemon health
+//	@Description	Check the subsystems of the daemon: the session service, the toolbox working directory, the
+//	@Description	computer-use plugin and the free disk space. Responds with 503 if any of them is unhealthy.
+//	@Tags			info
+//	@Produce		json
+//	@Success		200	{object}	HealthResponse
+//	@Failure		503	{object}	HealthResponse
+//	@Router			/health [get]
+//
+//	@id				GetHealth
+func (h *healthChecker) GetHealth(ctx *gin.Context) {
+	checks := map[string]func() HealthCheck{
+		"sessions":    h.checkSessions,
+		"toolbox":     h.checkToolbox,
+		"computerUse": h.checkComputerUse,
+		"disk":        h.checkDisk,
+	}
+
+	response := HealthResponse{
+		Status:  HealthStatusHealthy,
+		Version: internal.Version,
+		Checks:  make(map[string]HealthCheck, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := runHealthCheck(check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Checks[name] = result
+		}()
+	}
+	wg.Wait()
+
+	for _, check := range response.Checks {
+		if check.Status == HealthStatusUnhealthy {
+			response.Status = HealthStatusUnhealthy
+			break
+		}
+		if check.Status == HealthStatusDegraded {
+			response.Status = HealthStatusDegraded
+		}
+	}
+
+	statusCode := http.StatusOK
+	if response.Status == HealthStatusUnhealthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	ctx.JSON(statusCode, response)
+}
+
+// runHealthCheck reports a check that doesn't complete in time as unhealthy
+func runHealthCheck(check func() HealthCheck) HealthCheck {
+	result := make(chan HealthCheck, 1)
+	go func() {
+		result <- check()
+	}()
+
+	select {
+	case r := <-result:
+		return r
+	case <-time.After(healthCheckTimeout):
+		return HealthCheck{Status: HealthStatusUnhealthy, Message: "check timed out"}
+	}
+}
+
+func (h *healthChecker) checkSessions() HealthCheck {
+	if err := h.sessionService.CheckHealth(); err != nil {
+		return HealthCheck{Status: HealthStatusUnhealthy, Message: err.Error()}
+	}
+	return HealthCheck{Status: HealthStatusHealthy}
+}
+
+func (h *healthChecker) checkToolbox() HealthCheck {
+	info, err := os.Stat(h.server.WorkDir)
+	if err == nil && !info.IsDir() {
+		err = errors.New("not a directory")
+	}
+	if err != nil {
+		return HealthCheck{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("working directory %s: %v", h.server.WorkDir, err)}
+	}
+	return HealthCheck{Status: HealthStatusHealthy}
+}
+
+// checkComputerUse is healthy if the plugin is not installed, as computer use is optional
+func (h *healthChecker) checkComputerUse() HealthCheck {
+	if h.server.ComputerUse == nil {
+		return HealthCheck{Status: HealthStatusHealthy, Message: "plugin not available"}
+	}
+
+	status, err := h.server.ComputerUse.GetStatus()
+	if err != nil {
+		return HealthCheck{Status: HealthStatusDegraded, Message: fmt.Sprintf("plugin not responding: %v", err)}
+	}
+	return HealthCheck{Status: HealthStatusHealthy, Message: status.Status}
+}
+
+func (h *healthChecker) checkDisk() HealthCheck {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(h.server.WorkDir, &stat); err != nil {
+		return HealthCheck{Status: HealthStatusDegraded, Message: err.Error()}
+	}
+
+	free := stat.Bavail * uint64(stat.Bsize)
+	total := stat.Blocks * uint64(stat.Bsize)
+	message := fmt.Sprintf("%d MB free of %d MB", free/1024/1024, total/1024/1024)
+
+	switch {
+	case free < minFreeDiskBytes:
+		return HealthCheck{Status: HealthStatusUnhealthy, Message: message}
+	case free < lowFreeDiskBytes || free < total/20:
+		return HealthCheck{Status: HealthStatusDegraded, Message: message}
+	}
+	return HealthCheck{Status: HealthStatusHealthy, Message: message}
+}
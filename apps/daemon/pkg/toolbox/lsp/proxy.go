@@ -0,0 +1,447 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	proxyWriteWait = 10 * time.Second
+	proxyReadLimit = 16 * 1024 * 1024
+	// How long a language server is kept running after its last client disconnected
+	proxyIdleTimeout = 5 * time.Minute
+)
+
+// jsonrpcMessage is a JSON-RPC 2.0 request, response or notification.
+// Only the fields needed for routing are decoded, the rest is passed through untouched.
+type jsonrpcMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  *json.RawMessage `json:"params,omitempty"`
+	Result  *json.RawMessage `json:"result,omitempty"`
+	Error   *json.RawMessage `json:"error,omitempty"`
+}
+
+type proxyClient struct {
+	id        string
+	conn      *websocket.Conn
+	send      chan []byte
+	closeOnce sync.Once
+}
+
+func (cl *proxyClient) close() {
+	cl.closeOnce.Do(func() {
+		close(cl.send)
+		_ = cl.conn.Close()
+	})
+}
+
+type pendingRequest struct {
+	clientId   string
+	originalId json.RawMessage
+	method     string
+}
+
+// ProxyServer runs a single language server process for a workspace folder and
+// multiplexes raw LSP traffic of any number of WebSocket clients onto it.
+//
+// Request ids are rewritten so responses can be routed back to the client that sent the request.
+// The server is initialized once by the first client; later clients get the cached initialize result.
+type ProxyServer struct {
+	languageId    string
+	pathToProject string
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+
+	writeMutex sync.Mutex
+
+	mutex            sync.Mutex
+	clients          map[string]*proxyClient
+	pending          map[int64]pendingRequest
+	nextId           int64
+	initializeResult *json.RawMessage
+	initializedSent  bool
+	idleTimer        *time.Timer
+	onExit           func()
+
+	done chan struct{}
+}
+
+func newProxyServer(def LanguageServerDefinition, pathToProject string, onExit func()) (*ProxyServer, error) {
+	if !def.IsInstalled() {
+		return nil, fmt.Errorf("language server %s is not installed", def.Binary)
+	}
+
+	cmd := exec.Command(def.Binary, def.Args...)
+	cmd.Dir = pathToProject
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", def.Binary, err)
+	}
+
+	s := &ProxyServer{
+		languageId:    def.LanguageId,
+		pathToProject: pathToProject,
+		cmd:           cmd,
+		stdin:         stdin,
+		stdout:        bufio.NewReader(stdout),
+		clients:       make(map[string]*proxyClient),
+		pending:       make(map[int64]pendingRequest),
+		onExit:        onExit,
+		done:          make(chan struct{}),
+	}
+
+	go s.run(def.Binary)
+
+	return s, nil
+}
+
+// run routes the messages of the language server until it closes its stdout and then waits for it
+// to exit. Waiting any earlier would close stdout while it is still being read.
+func (s *ProxyServer) run(binary string) {
+	if err := s.readServer(); err != nil {
+		log.Warnf("Stopping %s language server for %s: %v", s.languageId, s.pathToProject, err)
+		_ = s.cmd.Process.Kill()
+		_, _ = io.Copy(io.Discard, s.stdout)
+	}
+
+	err := s.cmd.Wait()
+	log.Infof("Language server %s for %s exited: %v", binary, s.pathToProject, err)
+	close(s.done)
+	s.closeClients()
+	s.onExit()
+}
+
+func (s *ProxyServer) Info() LspProxyServerInfo {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return LspProxyServerInfo{
+		LanguageId:    s.languageId,
+		PathToProject: s.pathToProject,
+		Pid:           s.cmd.Process.Pid,
+		Clients:       len(s.clients),
+	}
+}
+
+// Attach serves a WebSocket client until it disconnects
+func (s *ProxyServer) Attach(ws *websocket.Conn) {
+	cl := &proxyClient{
+		id:   uuid.NewString(),
+		conn: ws,
+		send: make(chan []byte, 256),
+	}
+
+	s.mutex.Lock()
+	s.clients[cl.id] = cl
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+		s.idleTimer = nil
+	}
+	s.mutex.Unlock()
+
+	log.Debugf("LSP client %s attached to %s server for %s", cl.id, s.languageId, s.pathToProject)
+
+	go s.clientWriter(cl)
+	s.clientReader(cl)
+
+	s.mutex.Lock()
+	delete(s.clients, cl.id)
+	for id, p := range s.pending {
+		if p.clientId == cl.id {
+			delete(s.pending, id)
+		}
+	}
+	if len(s.clients) == 0 {
+		s.idleTimer = time.AfterFunc(proxyIdleTimeout, func() {
+			log.Infof("Stopping idle %s language server for %s", s.languageId, s.pathToProject)
+			_ = s.Stop()
+		})
+	}
+	s.mutex.Unlock()
+
+	cl.close()
+	log.Debugf("LSP client %s detached from %s server for %s", cl.id, s.languageId, s.pathToProject)
+}
+
+// Stop shuts down the language server process
+func (s *ProxyServer) Stop() error {
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+
+	// Ask politely first, then kill if the server doesn't exit
+	_ = s.writeServer([]byte(`{"jsonrpc":"2.0","id":"daytona-shutdown","method":"shutdown"}`))
+	_ = s.writeServer([]byte(`{"jsonrpc":"2.0","method":"exit"}`))
+
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(5 * time.Second):
+		return s.cmd.Process.Kill()
+	}
+}
+
+func (s *ProxyServer) closeClients() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, cl := range s.clients {
+		cl.close()
+	}
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+	}
+}
+
+func (s *ProxyServer) clientWriter(cl *proxyClient) {
+	for b := range cl.send {
+		_ = cl.conn.SetWriteDeadline(time.Now().Add(proxyWriteWait))
+		if err := cl.conn.WriteMessage(websocket.TextMessage, b); err != nil {
+			return
+		}
+	}
+}
+
+func (s *ProxyServer) clientReader(cl *proxyClient) {
+	cl.conn.SetReadLimit(proxyReadLimit)
+
+	for {
+		_, data, err := cl.conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Debug("LSP ws read error: ", err)
+			}
+			return
+		}
+
+		if err := s.handleClientMessage(cl, data); err != nil {
+			log.Debugf("Failed to handle LSP client message: %v", err)
+		}
+	}
+}
+
+func (s *ProxyServer) handleClientMessage(cl *proxyClient, data []byte) error {
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return fmt.Errorf("invalid JSON-RPC message: %w", err)
+	}
+
+	switch {
+	case msg.Method != "" && msg.ID != nil:
+		return s.forwardClientRequest(cl, msg)
+	case msg.Method != "":
+		// The server lifecycle is managed by the daemon, not by individual clients
+		if msg.Method == "exit" {
+			return nil
+		}
+		if msg.Method == "initialized" {
+			s.mutex.Lock()
+			alreadyInitialized := s.initializedSent
+			s.initializedSent = true
+			s.mutex.Unlock()
+			if alreadyInitialized {
+				return nil
+			}
+		}
+		return s.writeServer(data)
+	case msg.ID != nil:
+		// Response to a request the server sent to this client
+		return s.writeServer(data)
+	}
+
+	return errors.New("message is neither a request, a response nor a notification")
+}
+
+func (s *ProxyServer) forwardClientRequest(cl *proxyClient, msg jsonrpcMessage) error {
+	s.mutex.Lock()
+
+	switch msg.Method {
+	case "initialize":
+		if s.initializeResult != nil {
+			result := s.initializeResult
+			s.mutex.Unlock()
+			return s.sendToClient(cl, jsonrpcMessage{JSONRPC: "2.0", ID: msg.ID, Result: result})
+		}
+	case "shutdown":
+		s.mutex.Unlock()
+		null := json.RawMessage("null")
+		return s.sendToClient(cl, jsonrpcMessage{JSONRPC: "2.0", ID: msg.ID, Result: &null})
+	}
+
+	s.nextId++
+	id := s.nextId
+	s.pending[id] = pendingRequest{
+		clientId:   cl.id,
+		originalId: *msg.ID,
+		method:     msg.Method,
+	}
+	s.mutex.Unlock()
+
+	rewrittenId := json.RawMessage(strconv.FormatInt(id, 10))
+	msg.ID = &rewrittenId
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return s.writeServer(b)
+}
+
+func (s *ProxyServer) sendToClient(cl *proxyClient, msg jsonrpcMessage) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// The client may have been closed in the meantime
+	if _, ok := s.clients[cl.id]; ok {
+		s.enqueue(cl, b)
+	}
+
+	return nil
+}
+
+// readServer routes the messages of the language server to the clients until its stdout is closed.
+// It returns an error if the server broke the protocol.
+func (s *ProxyServer) readServer() error {
+	for {
+		data, err := readLspMessage(s.stdout)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read from language server: %w", err)
+		}
+
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			log.Debugf("Invalid message from %s language server: %v", s.languageId, err)
+			continue
+		}
+
+		s.routeServerMessage(msg, data)
+	}
+}
+
+func (s *ProxyServer) routeServerMessage(msg jsonrpcMessage, data []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case msg.Method != "" && msg.ID != nil:
+		// Server to client request, sent to a single client which is expected to reply
+		for _, cl := range s.clients {
+			s.enqueue(cl, data)
+			return
+		}
+	case msg.Method != "":
+		// Notifications (diagnostics, progress, logs) are broadcast to everyone
+		for _, cl := range s.clients {
+			s.enqueue(cl, data)
+		}
+	case msg.ID != nil:
+		id, err := strconv.ParseInt(string(*msg.ID), 10, 64)
+		if err != nil {
+			return
+		}
+
+		p, ok := s.pending[id]
+		if !ok {
+			return
+		}
+		delete(s.pending, id)
+
+		if p.method == "initialize" && msg.Error == nil {
+			s.initializeResult = msg.Result
+		}
+
+		cl, ok := s.clients[p.clientId]
+		if !ok {
+			return
+		}
+
+		originalId := p.originalId
+		msg.ID = &originalId
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return
+		}
+		s.enqueue(cl, b)
+	}
+}
+
+// enqueue must be called with s.mutex held
+func (s *ProxyServer) enqueue(cl *proxyClient, b []byte) {
+	select {
+	case cl.send <- b:
+	default:
+		log.Warnf("LSP client %s is too slow, dropping message", cl.id)
+	}
+}
+
+func (s *ProxyServer) writeServer(data []byte) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	if _, err := fmt.Fprintf(s.stdin, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err := s.stdin.Write(data)
+	return err
+}
+
+// readLspMessage reads a single base protocol message (headers + content) from the reader
+func readLspMessage(r *bufio.Reader) ([]byte, error) {
+	headers, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+)
+
+// LanguageServerDefinition describes how to install and run a language server speaking LSP over stdio
+type LanguageServerDefinition struct {
+	LanguageId string
+	Binary     string
+	Args       []string
+	InstallCmd []string
+}
+
+var languageServers = map[string]LanguageServerDefinition{
+	"go": {
+		LanguageId: "go",
+		Binary:     "gopls",
+		Args:       []string{"serve"},
+		InstallCmd: []string{"go", "install", "golang.org/x/tools/gopls@latest"},
+	},
+	"python": {
+		LanguageId: "python",
+		Binary:     "pyright-langserver",
+		Args:       []string{"--stdio"},
+		InstallCmd: []string{"npm", "install", "-g", "pyright"},
+	},
+	"typescript": {
+		LanguageId: "typescript",
+		Binary:     "typescript-language-server",
+		Args:       []string{"--stdio"},
+		InstallCmd: []string{"npm", "install", "-g", "typescript", "typescript-language-server"},
+	},
+	"javascript": {
+		LanguageId: "javascript",
+		Binary:     "typescript-language-server",
+		Args:       []string{"--stdio"},
+		InstallCmd: []string{"npm", "install", "-g", "typescript", "typescript-language-server"},
+	},
+}
+
+func getLanguageServerDefinition(languageId string) (LanguageServerDefinition, error) {
+	def, ok := languageServers[languageId]
+	if !ok {
+		return LanguageServerDefinition{}, fmt.Errorf("unsupported language: %s", languageId)
+	}
+	return def, nil
+}
+
+func (d LanguageServerDefinition) IsInstalled() bool {
+	_, err := exec.LookPath(d.Binary)
+	return err == nil
+}
+
+// Install installs the language server if it is not already available on PATH
+func (d LanguageServerDefinition) Install(ctx context.Context) (string, error) {
+	if d.IsInstalled() {
+		return "", nil
+	}
+
+	if _, err := exec.LookPath(d.InstallCmd[0]); err != nil {
+		return "", fmt.Errorf("cannot install %s: %s is not available", d.Binary, d.InstallCmd[0])
+	}
+
+	cmd := exec.CommandContext(ctx, d.InstallCmd[0], d.InstallCmd[1:]...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("failed to install %s: %w", d.Binary, err)
+	}
+
+	if !d.IsInstalled() {
+		return string(out), fmt.Errorf("%s installed but not found on PATH", d.Binary)
+	}
+
+	return string(out), nil
+}
+
+func supportedLanguages() []LanguageServerInfo {
+	languages := make([]LanguageServerInfo, 0, len(languageServers))
+	for _, def := range languageServers {
+		languages = append(languages, LanguageServerInfo{
+			LanguageId: def.LanguageId,
+			Binary:     def.Binary,
+			Installed:  def.IsInstalled(),
+		})
+	}
+
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].LanguageId < languages[j].LanguageId
+	})
+
+	return languages
+}
@@ -6,11 +6,18 @@ package lsp
 import (
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"sync"
 )
 
+// LSPService owns the language servers of the daemon, per language and workspace folder. Servers
+// used through the LSP endpoints are driven by the daemon, servers of WebSocket connections carry
+// the raw LSP traffic of the clients.
 type LSPService struct {
 	servers map[string]LSPServer
+
+	proxiesMutex sync.Mutex
+	proxies      map[string]*ProxyServer
 }
 
 var (
@@ -22,6 +29,7 @@ func GetLSPService() *LSPService {
 	once.Do(func() {
 		instance = &LSPService{
 			servers: make(map[string]LSPServer),
+			proxies: make(map[string]*ProxyServer),
 		}
 	})
 	return instance
@@ -49,22 +57,18 @@ func (s *LSPService) Get(languageId string, pathToProject string) (LSPServer, er
 }
 
 func (s *LSPService) Start(languageId string, pathToProject string) error {
-	key := generateKey(languageId, pathToProject)
+	server, err := s.Get(languageId, pathToProject)
+	if err != nil {
+		return err
+	}
 
-	server, ok := s.servers[key]
-	if ok {
-		if server.IsInitialized() {
-			return nil
-		}
-	} else {
-		newServer := NewTypeScriptLSPServer()
-		s.servers[key] = newServer
-		server = newServer
+	if server.IsInitialized() {
+		return nil
 	}
 
-	err := server.Initialize(pathToProject)
+	err = server.Initialize(pathToProject)
 	if err != nil {
-		return fmt.Errorf("failed to create TypeScript LSP server: %w", err)
+		return fmt.Errorf("failed to create %s LSP server: %w", languageId, err)
 	}
 
 	return nil
@@ -82,6 +86,74 @@ func (s *LSPService) Shutdown(languageId string, pathToProject string) error {
 	return err
 }
 
+// Connect returns the language server of the workspace folder for WebSocket clients, starting it
+// if needed
+func (s *LSPService) Connect(languageId string, pathToProject string) (*ProxyServer, error) {
+	def, err := getLanguageServerDefinition(languageId)
+	if err != nil {
+		return nil, err
+	}
+
+	key := generateKey(languageId, pathToProject)
+
+	s.proxiesMutex.Lock()
+	defer s.proxiesMutex.Unlock()
+
+	if server, ok := s.proxies[key]; ok {
+		return server, nil
+	}
+
+	var server *ProxyServer
+	server, err = newProxyServer(def, pathToProject, func() {
+		s.proxiesMutex.Lock()
+		defer s.proxiesMutex.Unlock()
+
+		if s.proxies[key] == server {
+			delete(s.proxies, key)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.proxies[key] = server
+	return server, nil
+}
+
+// Disconnect stops the language server of the workspace folder and disconnects its WebSocket
+// clients
+func (s *LSPService) Disconnect(languageId string, pathToProject string) error {
+	s.proxiesMutex.Lock()
+	server, ok := s.proxies[generateKey(languageId, pathToProject)]
+	s.proxiesMutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no %s language server running for %s", languageId, pathToProject)
+	}
+
+	return server.Stop()
+}
+
+// ListConnected returns the language servers of WebSocket clients
+func (s *LSPService) ListConnected() []LspProxyServerInfo {
+	s.proxiesMutex.Lock()
+	defer s.proxiesMutex.Unlock()
+
+	servers := make([]LspProxyServerInfo, 0, len(s.proxies))
+	for _, server := range s.proxies {
+		servers = append(servers, server.Info())
+	}
+
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].PathToProject == servers[j].PathToProject {
+			return servers[i].LanguageId < servers[j].LanguageId
+		}
+		return servers[i].PathToProject < servers[j].PathToProject
+	})
+
+	return servers
+}
+
 func generateKey(languageId, pathToProject string) string {
 	data := fmt.Sprintf("%s:%s", languageId, pathToProject)
 	return base64.StdEncoding.EncodeToString([]byte(data))
@@ -21,3 +21,24 @@ type LspCompletionParams struct {
 	Position      LspPosition        `json:"position" validate:"required"`
 	Context       *CompletionContext `json:"context,omitempty" validate:"optional"`
 } // @name LspCompletionParams
+
+type LspInstallRequest struct {
+	LanguageId string `json:"languageId" validate:"required"`
+} // @name LspInstallRequest
+
+type LspInstallResponse struct {
+	Output string `json:"output"`
+} // @name LspInstallResponse
+
+type LanguageServerInfo struct {
+	LanguageId string `json:"languageId" validate:"required"`
+	Binary     string `json:"binary" validate:"required"`
+	Installed  bool   `json:"installed" validate:"required"`
+} // @name LanguageServerInfo
+
+type LspProxyServerInfo struct {
+	LanguageId    string `json:"languageId" validate:"required"`
+	PathToProject string `json:"pathToProject" validate:"required"`
+	Pid           int    `json:"pid" validate:"required"`
+	Clients       int    `json:"clients" validate:"required"`
+} // @name LspProxyServerInfo
@@ -0,0 +1,147 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/daytonaio/daemon/internal/util"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Connect godoc
+//
+//	@Summary		Connect to a language server
+//	@Description	Establish a WebSocket connection carrying raw LSP JSON-RPC messages (one message per frame).
+//	@Description	The language server for the workspace folder is started on first use and shared between connections.
+//	@Tags			lsp
+//	@Param			languageId		query	string	true	"Language ID (e.g., go, python, typescript)"
+//	@Param			pathToProject	query	string	true	"Path to the workspace folder"
+//	@Success		101				"Switching Protocols - WebSocket connection established"
+//	@Router			/lsp/connect [get]
+//
+//	@id				ConnectLsp
+func Connect(c *gin.Context) {
+	languageId := c.Query("languageId")
+	if languageId == "" {
+		c.AbortWithError(http.StatusBadRequest, errors.New("languageId is required"))
+		return
+	}
+
+	pathToProject := c.Query("pathToProject")
+	if pathToProject == "" {
+		c.AbortWithError(http.StatusBadRequest, errors.New("pathToProject is required"))
+		return
+	}
+
+	server, err := GetLSPService().Connect(languageId, pathToProject)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ws, err := util.UpgradeToWebSocket(c.Writer, c.Request)
+	if err != nil {
+		log.Errorf("Failed to upgrade LSP connection: %v", err)
+		return
+	}
+
+	server.Attach(ws)
+}
+
+// Install godoc
+//
+//	@Summary		Install a language server
+//	@Description	Install the language server for the specified language if it is not already available
+//	@Tags			lsp
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		LspInstallRequest	true	"Install request"
+//	@Success		200		{object}	LspInstallResponse
+//	@Router			/lsp/install [post]
+//
+//	@id				InstallLsp
+func Install(c *gin.Context) {
+	var req LspInstallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	def, err := getLanguageServerDefinition(req.LanguageId)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	output, err := def.Install(c.Request.Context())
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("%w: %s", err, output))
+		return
+	}
+
+	c.JSON(http.StatusOK, LspInstallResponse{
+		Output: output,
+	})
+}
+
+// ListLanguages godoc
+//
+//	@Summary		List supported languages
+//	@Description	List languages with a known language server and whether it is installed
+//	@Tags			lsp
+//	@Produce		json
+//	@Success		200	{array}	LanguageServerInfo
+//	@Router			/lsp/languages [get]
+//
+//	@id				ListLspLanguages
+func ListLanguages(c *gin.Context) {
+	c.JSON(http.StatusOK, supportedLanguages())
+}
+
+// ListServers godoc
+//
+//	@Summary		List running language servers
+//	@Description	List language servers started for WebSocket connections
+//	@Tags			lsp
+//	@Produce		json
+//	@Success		200	{array}	LspProxyServerInfo
+//	@Router			/lsp/servers [get]
+//
+//	@id				ListLspServers
+func ListServers(c *gin.Context) {
+	c.JSON(http.StatusOK, GetLSPService().ListConnected())
+}
+
+// StopServer godoc
+//
+//	@Summary		Stop a running language server
+//	@Description	Stop the language server of a workspace folder and disconnect its clients
+//	@Tags			lsp
+//	@Param			languageId		query	string	true	"Language ID"
+//	@Param			pathToProject	query	string	true	"Path to the workspace folder"
+//	@Success		204
+//	@Router			/lsp/servers [delete]
+//
+//	@id				StopLspServer
+func StopServer(c *gin.Context) {
+	languageId := c.Query("languageId")
+	pathToProject := c.Query("pathToProject")
+	if languageId == "" || pathToProject == "" {
+		c.AbortWithError(http.StatusBadRequest, errors.New("languageId and pathToProject are required"))
+		return
+	}
+
+	err := GetLSPService().Disconnect(languageId, pathToProject)
+	if err != nil {
+		c.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package middlewares
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthTokenHeader carries the secret of the sandbox on requests from the runner
+const AuthTokenHeader = "X-Daytona-Daemon-Token"
+
+// AuthMiddleware rejects requests without the auth token of the sandbox. Sandboxes created before
+// the token was introduced have none, in which case all requests are allowed.
+func AuthMiddleware(authToken string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if authToken == "" {
+			ctx.Next()
+			return
+		}
+
+		token := ctx.GetHeader(AuthTokenHeader)
+		if token == "" {
+			token, _ = strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(authToken)) != 1 {
+			ctx.Error(common_errors.NewUnauthorizedError(errors.New("invalid or missing daemon auth token")))
+			ctx.Abort()
+			return
+		}
+
+		// Requests proxied to services in the sandbox must not carry the token
+		ctx.Request.Header.Del(AuthTokenHeader)
+
+		ctx.Next()
+	}
+}
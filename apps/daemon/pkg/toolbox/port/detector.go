@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/cakturk/go-netstat/netstat"
@@ -19,11 +21,18 @@ import (
 
 type portsDetector struct {
 	portMap cmap.ConcurrentMap[string, bool]
+	// All listening sockets keyed by protocol, address and port
+	listeners cmap.ConcurrentMap[string, ListeningPort]
+
+	subscribersMu sync.Mutex
+	subscribers   map[chan PortEvent]struct{}
 }
 
 func NewPortsDetector() *portsDetector {
 	return &portsDetector{
-		portMap: cmap.New[bool](),
+		portMap:     cmap.New[bool](),
+		listeners:   cmap.New[ListeningPort](),
+		subscribers: make(map[chan PortEvent]struct{}),
 	}
 }
 
@@ -34,19 +43,24 @@ func (d *portsDetector) Start(ctx context.Context) {
 			return
 		default:
 			time.Sleep(1 * time.Second)
-			// get only listening TCP sockets
-			tabs, err := netstat.TCPSocks(func(s *netstat.SockTabEntry) bool {
-				return s.State == netstat.Listen
-			})
+
+			listening, err := scanListeningPorts()
 			if err != nil {
 				continue
 			}
 
 			freshMap := map[string]bool{}
-			for _, e := range tabs {
-				s := strconv.Itoa(int(e.LocalAddr.Port))
-				freshMap[s] = true
-				d.portMap.Set(s, true)
+			for key, p := range listening {
+				if p.Protocol == PortProtocolTCP {
+					s := strconv.Itoa(int(p.Port))
+					freshMap[s] = true
+					d.portMap.Set(s, true)
+				}
+
+				if !d.listeners.Has(key) {
+					d.publish(PortEventOpen, p)
+				}
+				d.listeners.Set(key, p)
 			}
 
 			for _, port := range d.portMap.Keys() {
@@ -54,6 +68,95 @@ func (d *portsDetector) Start(ctx context.Context) {
 					d.portMap.Remove(port)
 				}
 			}
+
+			for _, key := range d.listeners.Keys() {
+				if _, ok := listening[key]; ok {
+					continue
+				}
+				if p, ok := d.listeners.Pop(key); ok {
+					d.publish(PortEventClose, p)
+				}
+			}
+		}
+	}
+}
+
+// scanListeningPorts returns listening TCP sockets and bound, unconnected UDP sockets
+func scanListeningPorts() (map[string]ListeningPort, error) {
+	listening := map[string]ListeningPort{}
+
+	add := func(protocol PortProtocol, entries []netstat.SockTabEntry) {
+		for _, e := range entries {
+			p := ListeningPort{
+				Port:     e.LocalAddr.Port,
+				Protocol: protocol,
+				Address:  e.LocalAddr.IP.String(),
+			}
+			if e.Process != nil {
+				p.Pid = e.Process.Pid
+				p.ProcessName = e.Process.Name
+			}
+			listening[fmt.Sprintf("%s/%s", protocol, net.JoinHostPort(p.Address, strconv.Itoa(int(p.Port))))] = p
+		}
+	}
+
+	tcpListen := func(s *netstat.SockTabEntry) bool {
+		return s.State == netstat.Listen
+	}
+	udpBound := func(s *netstat.SockTabEntry) bool {
+		return s.RemoteAddr.Port == 0
+	}
+
+	tabs, err := netstat.TCPSocks(tcpListen)
+	if err != nil {
+		return nil, err
+	}
+	add(PortProtocolTCP, tabs)
+
+	// IPv6 and UDP tables may be missing in some sandboxes, they are best effort
+	if tabs, err := netstat.TCP6Socks(tcpListen); err == nil {
+		add(PortProtocolTCP, tabs)
+	}
+	if tabs, err := netstat.UDPSocks(udpBound); err == nil {
+		add(PortProtocolUDP, tabs)
+	}
+	if tabs, err := netstat.UDP6Socks(udpBound); err == nil {
+		add(PortProtocolUDP, tabs)
+	}
+
+	return listening, nil
+}
+
+func (d *portsDetector) subscribe() (<-chan PortEvent, func()) {
+	ch := make(chan PortEvent, 64)
+
+	d.subscribersMu.Lock()
+	d.subscribers[ch] = struct{}{}
+	d.subscribersMu.Unlock()
+
+	return ch, func() {
+		d.subscribersMu.Lock()
+		delete(d.subscribers, ch)
+		d.subscribersMu.Unlock()
+		close(ch)
+	}
+}
+
+func (d *portsDetector) publish(eventType PortEventType, p ListeningPort) {
+	event := PortEvent{
+		Type: eventType,
+		Port: p,
+		Time: time.Now(),
+	}
+
+	d.subscribersMu.Lock()
+	defer d.subscribersMu.Unlock()
+
+	for ch := range d.subscribers {
+		// Never block detection on a slow subscriber
+		select {
+		case ch <- event:
+		default:
 		}
 	}
 }
@@ -84,6 +187,35 @@ func (d *portsDetector) GetPorts(c *gin.Context) {
 	c.JSON(http.StatusOK, ports)
 }
 
+// GetListeningPorts godoc
+//
+//	@Summary		Get listening ports
+//	@Description	Get listening TCP and UDP ports with the process owning each socket
+//	@Tags			port
+//	@Produce		json
+//	@Success		200	{array}	ListeningPort
+//	@Router			/port/listening [get]
+//
+//	@id				GetListeningPorts
+func (d *portsDetector) GetListeningPorts(c *gin.Context) {
+	ports := make([]ListeningPort, 0, d.listeners.Count())
+	for _, p := range d.listeners.Items() {
+		ports = append(ports, p)
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return ports[i].Address < ports[j].Address
+	})
+
+	c.JSON(http.StatusOK, ports)
+}
+
 // IsPortInUse godoc
 //
 //	@Summary		Check if port is in use
@@ -0,0 +1,67 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package port
+
+import (
+	"io"
+	"time"
+
+	"github.com/daytonaio/daemon/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// WatchPorts godoc
+//
+//	@Summary		Watch port changes
+//	@Description	Stream events when ports start or stop listening. Events are sent as JSON objects,
+//	@Description	over a WebSocket if the request is an upgrade request and as Server-Sent Events otherwise.
+//	@Tags			port
+//	@Produce		json
+//	@Success		200	{object}	PortEvent
+//	@Success		101	"Switching Protocols - WebSocket connection established"
+//	@Router			/port/events [get]
+//
+//	@id				WatchPorts
+func (d *portsDetector) WatchPorts(c *gin.Context) {
+	events, unsubscribe := d.subscribe()
+	defer unsubscribe()
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		ws, err := util.UpgradeToWebSocket(c.Writer, c.Request)
+		if err != nil {
+			log.Errorf("Failed to upgrade port events connection: %v", err)
+			return
+		}
+		defer ws.Close()
+
+		ctx, cancel := util.ContextWithCancelOnClose(c.Request.Context(), ws)
+		defer cancel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				_ = ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+				if err := ws.WriteJSON(event); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	ctx := c.Request.Context()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event := <-events:
+			c.SSEvent(string(event.Type), event)
+			return true
+		}
+	})
+}
@@ -3,6 +3,8 @@
 
 package port
 
+import "time"
+
 type PortList struct {
 	Ports []uint `json:"ports"`
 } // @name PortList
@@ -10,3 +12,32 @@ type PortList struct {
 type IsPortInUseResponse struct {
 	IsInUse bool `json:"isInUse"`
 } // @name IsPortInUseResponse
+
+type PortProtocol string // @name PortProtocol
+
+const (
+	PortProtocolTCP PortProtocol = "tcp"
+	PortProtocolUDP PortProtocol = "udp"
+)
+
+type ListeningPort struct {
+	Port     uint16       `json:"port" validate:"required"`
+	Protocol PortProtocol `json:"protocol" validate:"required"`
+	Address  string       `json:"address" validate:"required"`
+	// Owning process, only set if the daemon is allowed to inspect it
+	Pid         int    `json:"pid,omitempty" validate:"optional"`
+	ProcessName string `json:"processName,omitempty" validate:"optional"`
+} // @name ListeningPort
+
+type PortEventType string // @name PortEventType
+
+const (
+	PortEventOpen  PortEventType = "open"
+	PortEventClose PortEventType = "close"
+)
+
+type PortEvent struct {
+	Type PortEventType `json:"type" validate:"required"`
+	Port ListeningPort `json:"port" validate:"required"`
+	Time time.Time     `json:"time" validate:"required"`
+} // @name PortEvent
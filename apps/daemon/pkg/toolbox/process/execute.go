@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"time"
 
+	"github.com/daytonaio/daemon/pkg/env"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/gin-gonic/gin"
@@ -41,6 +42,7 @@ func ExecuteCommand(c *gin.Context) {
 	}
 
 	cmd := exec.Command(cmdParts[0], cmdParts[1:]...)
+	cmd.Env = env.Environ()
 	if request.Cwd != nil {
 		cmd.Dir = *request.Cwd
 	}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/daemon/pkg/env"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	log "github.com/sirupsen/logrus"
@@ -201,7 +202,7 @@ func (c *Context) start() error {
 	pyCmd := detectPythonCommand()
 	cmd := exec.CommandContext(ctx, pyCmd, workerPath)
 	cmd.Dir = c.info.Cwd
-	cmd.Env = os.Environ()
+	cmd.Env = env.Environ()
 
 	// Get stdin/stdout pipes
 	stdin, err := cmd.StdinPipe()
@@ -0,0 +1,171 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package process
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/shirou/gopsutil/v4/process"
+	"golang.org/x/sys/unix"
+)
+
+const cpuSampleInterval = 250 * time.Millisecond
+
+// ListProcesses godoc
+//
+//	@Summary		List processes
+//	@Description	List processes running in the sandbox with their CPU and memory usage.
+//	@Description	CPU usage is sampled over a short interval, so the request takes about 250ms.
+//	@Tags			process
+//	@Produce		json
+//	@Param			sort	query	string	false	"Sort by cpu, memory or pid (default cpu)"
+//	@Success		200		{array}	ProcessInfo
+//	@Router			/process/list [get]
+//
+//	@id				ListProcesses
+func ListProcesses(c *gin.Context) {
+	sortBy := c.DefaultQuery("sort", "cpu")
+	if sortBy != "cpu" && sortBy != "memory" && sortBy != "pid" {
+		c.AbortWithError(http.StatusBadRequest, errors.New("sort must be one of cpu, memory or pid"))
+		return
+	}
+
+	procs, err := process.ProcessesWithContext(c.Request.Context())
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to list processes: %w", err))
+		return
+	}
+
+	// Sample CPU times twice to report current rather than lifetime usage
+	before := make(map[int32]float64, len(procs))
+	for _, p := range procs {
+		if times, err := p.Times(); err == nil {
+			before[p.Pid] = times.User + times.System
+		}
+	}
+	start := time.Now()
+
+	select {
+	case <-time.After(cpuSampleInterval):
+	case <-c.Request.Context().Done():
+		return
+	}
+	elapsed := time.Since(start).Seconds()
+
+	result := make([]ProcessInfo, 0, len(procs))
+	for _, p := range procs {
+		info, err := processInfo(p)
+		if err != nil {
+			// The process exited in the meantime
+			continue
+		}
+
+		if times, err := p.Times(); err == nil {
+			if prev, ok := before[p.Pid]; ok {
+				info.CpuPercent = max((times.User+times.System-prev)/elapsed*100, 0)
+			}
+		}
+
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		switch sortBy {
+		case "memory":
+			return result[i].Rss > result[j].Rss
+		case "pid":
+			return result[i].Pid < result[j].Pid
+		default:
+			return result[i].CpuPercent > result[j].CpuPercent
+		}
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// KillProcess godoc
+//
+//	@Summary		Send a signal to a process
+//	@Description	Send a signal to a process, SIGTERM by default
+//	@Tags			process
+//	@Produce		json
+//	@Param			pid		path		int		true	"Process ID"
+//	@Param			signal	query		string	false	"Signal name or number (e.g., SIGKILL, KILL or 9)"
+//	@Success		200		{object}	KillProcessResponse
+//	@Router			/process/{pid}/kill [post]
+//
+//	@id				KillProcess
+func KillProcess(c *gin.Context) {
+	pid, err := strconv.Atoi(c.Param("pid"))
+	if err != nil || pid <= 0 {
+		c.AbortWithError(http.StatusBadRequest, errors.New("invalid pid"))
+		return
+	}
+
+	if pid == os.Getpid() {
+		c.AbortWithError(http.StatusForbidden, errors.New("cannot signal the daemon process"))
+		return
+	}
+
+	signal, err := common.ParseSignal(c.DefaultQuery("signal", "SIGTERM"))
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if err := syscall.Kill(pid, signal); err != nil {
+		switch {
+		case errors.Is(err, syscall.ESRCH):
+			c.AbortWithError(http.StatusNotFound, fmt.Errorf("process %d not found", pid))
+		case errors.Is(err, syscall.EPERM):
+			c.AbortWithError(http.StatusForbidden, err)
+		default:
+			c.AbortWithError(http.StatusBadRequest, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, KillProcessResponse{
+		Pid:    int32(pid),
+		Signal: unix.SignalName(signal),
+	})
+}
+
+func processInfo(p *process.Process) (ProcessInfo, error) {
+	name, err := p.Name()
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+
+	info := ProcessInfo{
+		Pid:  p.Pid,
+		Name: name,
+	}
+
+	// The remaining fields are best effort, some are unavailable for other users' processes
+	info.Ppid, _ = p.Ppid()
+	info.Command, _ = p.Cmdline()
+	info.User, _ = p.Username()
+	info.NumThreads, _ = p.NumThreads()
+	info.CreateTime, _ = p.CreateTime()
+
+	if status, err := p.Status(); err == nil && len(status) > 0 {
+		info.State = status[0]
+	}
+
+	if mem, err := p.MemoryInfo(); err == nil {
+		info.Rss = mem.RSS
+	}
+
+	return info, nil
+}
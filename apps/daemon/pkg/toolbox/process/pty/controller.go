@@ -7,18 +7,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/daytonaio/daemon/internal/util"
+	"github.com/daytonaio/daemon/pkg/common"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	cmap "github.com/orcaman/concurrent-map/v2"
 	log "github.com/sirupsen/logrus"
 )
 
-// NewPTYController creates a new PTY controller
-func NewPTYController(workDir string) *PTYController {
-	return &PTYController{workDir: workDir}
+// NewPTYController creates a new PTY controller, recordings are stored in configDir
+func NewPTYController(workDir, configDir string) *PTYController {
+	return &PTYController{
+		workDir:       workDir,
+		recordingsDir: filepath.Join(configDir, "recordings", "terminal"),
+	}
 }
 
 // CreatePTYSession godoc
@@ -92,6 +99,11 @@ func (p *PTYController) CreatePTYSession(c *gin.Context) {
 		clients: cmap.New[*wsClient](),
 	}
 
+	if req.Record {
+		session.info.RecordingID = uuid.NewString()
+		session.recordingPath = filepath.Join(p.recordingsDir, session.info.RecordingID+recordingExtension)
+	}
+
 	// Add to manager first to prevent race conditions
 	ptyManager.Add(session)
 
@@ -178,9 +190,12 @@ func (p *PTYController) DeletePTYSession(c *gin.Context) {
 // ConnectPTYSession godoc
 //
 //	@Summary		Connect to PTY session via WebSocket
-//	@Description	Establish a WebSocket connection to interact with a pseudo-terminal session
+//	@Description	Establish a WebSocket connection to interact with a pseudo-terminal session.
+//	@Description	With control=true, binary frames are raw terminal input and text frames are PtyControlMessage JSON
+//	@Description	messages to resize the terminal or send a signal to the foreground process.
 //	@Tags			process
 //	@Param			sessionId	path	string	true	"PTY session ID"
+//	@Param			control		query	bool	false	"Treat text frames as control messages"
 //	@Success		101			"Switching Protocols - WebSocket connection established"
 //	@Router			/process/pty/{sessionId}/connect [get]
 //
@@ -216,7 +231,7 @@ func (p *PTYController) ConnectPTYSession(c *gin.Context) {
 	}
 
 	// Attach to session - this will send the control message internally
-	session.attachWebSocket(ws)
+	session.attachWebSocket(ws, c.Query("control") == "true")
 }
 
 // ResizePTYSession godoc
@@ -270,3 +285,131 @@ func (p *PTYController) ResizePTYSession(c *gin.Context) {
 	updatedInfo := session.Info()
 	c.JSON(http.StatusOK, updatedInfo)
 }
+
+// SignalPTYSession godoc
+//
+//	@Summary		Send a signal to a PTY session
+//	@Description	Send a signal to the foreground process of a pseudo-terminal session, e.g. SIGINT to interrupt it
+//	@Tags			process
+//	@Accept			json
+//	@Produce		json
+//	@Param			sessionId	path		string				true	"PTY session ID"
+//	@Param			request		body		PTYSignalRequest	true	"Signal request"
+//	@Success		200			{object}	PTYSessionInfo
+//	@Router			/process/pty/{sessionId}/signal [post]
+//
+//	@id				SignalPtySession
+func (p *PTYController) SignalPTYSession(c *gin.Context) {
+	id := c.Param("sessionId")
+
+	var req PTYSignalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sig, err := common.ParseSignal(req.Signal)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, ok := ptyManager.Get(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("PTY session %s not found", id)})
+		return
+	}
+
+	if err := session.signal(sig); err != nil {
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session.Info())
+}
+
+// ListPTYRecordings godoc
+//
+//	@Summary		List PTY recordings
+//	@Description	Get a list of the asciicast recordings of PTY sessions created with record=true
+//	@Tags			process
+//	@Produce		json
+//	@Success		200	{object}	PTYRecordingListResponse
+//	@Router			/process/pty/recordings [get]
+//
+//	@id				ListPtyRecordings
+func (p *PTYController) ListPTYRecordings(c *gin.Context) {
+	recordings, err := p.listRecordings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PTYRecordingListResponse{Recordings: recordings})
+}
+
+// DownloadPTYRecording godoc
+//
+//	@Summary		Download a PTY recording
+//	@Description	Download the asciicast v2 file of a PTY recording, which can be played with asciinema
+//	@Tags			process
+//	@Produce		application/x-asciicast
+//	@Param			recordingId	path	string	true	"Recording ID"
+//	@Success		200			{file}	binary
+//	@Router			/process/pty/recordings/{recordingId} [get]
+//
+//	@id				DownloadPtyRecording
+func (p *PTYController) DownloadPTYRecording(c *gin.Context) {
+	id := c.Param("recordingId")
+	path, err := p.recordingPath(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "PTY recording not found"})
+		return
+	}
+
+	c.Header("Content-Type", "application/x-asciicast")
+	c.FileAttachment(path, id+recordingExtension)
+}
+
+// DeletePTYRecording godoc
+//
+//	@Summary		Delete a PTY recording
+//	@Description	Delete the asciicast file of a PTY recording. Recordings of active sessions can't be deleted.
+//	@Tags			process
+//	@Produce		json
+//	@Param			recordingId	path		string	true	"Recording ID"
+//	@Success		200			{object}	gin.H
+//	@Router			/process/pty/recordings/{recordingId} [delete]
+//
+//	@id				DeletePtyRecording
+func (p *PTYController) DeletePTYRecording(c *gin.Context) {
+	id := c.Param("recordingId")
+	path, err := p.recordingPath(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, s := range ptyManager.List() {
+		if s.RecordingID == id && s.Active {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("PTY session %s is still being recorded", s.ID)})
+			return
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "PTY recording not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "PTY recording deleted"})
+}
@@ -91,3 +91,14 @@ func (m *PTYManager) VerifyPTYSessionForResize(id string) (*PTYSession, error) {
 
 	return session, nil
 }
+
+// ActiveSessionCount returns the number of running PTY sessions
+func ActiveSessionCount() int {
+	count := 0
+	for _, s := range ptyManager.List() {
+		if s.Active {
+			count++
+		}
+	}
+	return count
+}
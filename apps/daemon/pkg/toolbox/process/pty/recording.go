@@ -0,0 +1,220 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package pty
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const recordingExtension = ".cast"
+
+// castHeader is the first line of an asciicast v2 file
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// castRecorder writes the output of a PTY session as asciicast v2 events. Input is not
+// recorded, as it may contain secrets typed at prompts.
+type castRecorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	started time.Time
+	// Bytes of a UTF-8 sequence split between two reads
+	pending []byte
+}
+
+func newCastRecorder(path string, header castHeader) (*castRecorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &castRecorder{
+		file:    file,
+		writer:  bufio.NewWriter(file),
+		started: time.Now(),
+	}
+
+	header.Version = 2
+	header.Timestamp = r.started.Unix()
+
+	line, err := json.Marshal(header)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	r.writeLine(line)
+
+	return r, nil
+}
+
+func (r *castRecorder) output(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data = append(r.pending, data...)
+	r.pending = nil
+
+	// Keep an incomplete UTF-8 sequence at the end for the next read
+	for i := 1; i <= 3 && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				r.pending = append([]byte{}, data[len(data)-i:]...)
+				data = data[:len(data)-i]
+			}
+			break
+		}
+	}
+
+	if len(data) > 0 {
+		r.event("o", string(data))
+	}
+}
+
+func (r *castRecorder) resize(cols, rows uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.event("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+func (r *castRecorder) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return
+	}
+
+	if len(r.pending) > 0 {
+		r.event("o", string(r.pending))
+		r.pending = nil
+	}
+
+	if err := r.writer.Flush(); err != nil {
+		log.Warnf("Failed to flush PTY recording %s: %v", r.file.Name(), err)
+	}
+	_ = r.file.Close()
+	r.file = nil
+}
+
+// event writes an event line, the caller must hold the lock
+func (r *castRecorder) event(eventType, data string) {
+	if r.file == nil {
+		return
+	}
+
+	elapsed := float64(time.Since(r.started).Microseconds()) / 1e6
+	line, err := json.Marshal([]any{elapsed, eventType, data})
+	if err != nil {
+		return
+	}
+	r.writeLine(line)
+
+	// Keep the file close to the session so it can be downloaded while recording
+	if r.writer.Buffered() > 16*1024 || eventType == "r" {
+		_ = r.writer.Flush()
+	}
+}
+
+func (r *castRecorder) writeLine(line []byte) {
+	_, _ = r.writer.Write(line)
+	_ = r.writer.WriteByte('\n')
+}
+
+// recordingPath returns the path of a recording, validating that the ID can't escape the directory
+func (p *PTYController) recordingPath(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid recording ID %q", id)
+	}
+	return filepath.Join(p.recordingsDir, id+recordingExtension), nil
+}
+
+func (p *PTYController) listRecordings() ([]PTYRecording, error) {
+	entries, err := os.ReadDir(p.recordingsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []PTYRecording{}, nil
+		}
+		return nil, err
+	}
+
+	active := map[string]bool{}
+	for _, s := range ptyManager.List() {
+		if s.RecordingID != "" && s.Active {
+			active[s.RecordingID] = true
+		}
+	}
+
+	recordings := []PTYRecording{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), recordingExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		recording, err := readRecording(filepath.Join(p.recordingsDir, entry.Name()))
+		if err != nil {
+			log.Debugf("Skipping PTY recording %s: %v", entry.Name(), err)
+			continue
+		}
+		recording.ID = id
+		recording.Active = active[id]
+		recordings = append(recordings, *recording)
+	}
+
+	return recordings, nil
+}
+
+// readRecording reads the metadata of a recording from its header
+func readRecording(path string) (*PTYRecording, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	line, err := bufio.NewReader(file).ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	var header castHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, err
+	}
+
+	return &PTYRecording{
+		SessionID: header.Title,
+		Cols:      header.Width,
+		Rows:      header.Height,
+		StartedAt: time.Unix(header.Timestamp, 0),
+		Size:      stat.Size(),
+	}, nil
+}
@@ -7,12 +7,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"os/exec"
+	"syscall"
 
 	"github.com/creack/pty"
 	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/env"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 // Info returns the current session information
@@ -54,7 +56,7 @@ func (s *PTYSession) start() error {
 	cmd.Dir = s.info.Cwd
 
 	// Env
-	cmd.Env = os.Environ()
+	cmd.Env = env.Environ()
 	for k, v := range s.info.Envs {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
 	}
@@ -65,6 +67,22 @@ func (s *PTYSession) start() error {
 		return fmt.Errorf("pty.StartWithSize: %w", err)
 	}
 
+	if s.recordingPath != "" {
+		recorder, err := newCastRecorder(s.recordingPath, castHeader{
+			Width:  s.info.Cols,
+			Height: s.info.Rows,
+			Title:  s.info.ID,
+			Env:    map[string]string{"TERM": s.info.Envs["TERM"], "SHELL": shell},
+		})
+		if err != nil {
+			// The session is still usable without its recording
+			log.Errorf("Failed to start recording PTY session %s: %v", s.info.ID, err)
+			s.info.RecordingID = ""
+		} else {
+			s.recorder = recorder
+		}
+	}
+
 	s.cmd = cmd
 	s.ptmx = ptmx
 	s.info.Active = true
@@ -158,6 +176,11 @@ func (s *PTYSession) kill() {
 
 // ptyReadLoop reads from PTY and broadcasts to all clients
 func (s *PTYSession) ptyReadLoop() {
+	// The loop ends once the PTY is closed, after the last of the output was recorded
+	if s.recorder != nil {
+		defer s.recorder.close()
+	}
+
 	buf := make([]byte, 32*1024)
 	for {
 		n, err := s.ptmx.Read(buf)
@@ -165,6 +188,9 @@ func (s *PTYSession) ptyReadLoop() {
 			b := make([]byte, n)
 			copy(b, buf[:n])
 			s.broadcast(b)
+			if s.recorder != nil {
+				s.recorder.output(b)
+			}
 		}
 		if err != nil {
 			return
@@ -229,8 +255,35 @@ func (s *PTYSession) resize(cols, rows uint16) error {
 			log.Debug("PTY resize error:", err)
 			return err
 		}
+		if s.recorder != nil {
+			s.recorder.resize(cols, rows)
+		}
 	} else {
 		return errors.New("PTY file descriptor is not available")
 	}
 	return nil
 }
+
+// signal sends a signal to the foreground process group of the terminal, e.g. SIGINT to
+// interrupt the running program like Ctrl+C would, falling back to the shell process
+func (s *PTYSession) signal(sig syscall.Signal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.info.Active || s.ptmx == nil || s.cmd == nil || s.cmd.Process == nil {
+		return errors.New("cannot signal inactive PTY session")
+	}
+
+	pgrp := 0
+	conn, err := s.ptmx.SyscallConn()
+	if err == nil {
+		_ = conn.Control(func(fd uintptr) {
+			pgrp, err = unix.IoctlGetInt(int(fd), unix.TIOCGPGRP)
+		})
+	}
+	if err != nil || pgrp <= 0 {
+		return s.cmd.Process.Signal(sig)
+	}
+
+	return syscall.Kill(-pgrp, sig)
+}
@@ -22,7 +22,8 @@ const (
 
 // PTYController handles PTY-related HTTP endpoints
 type PTYController struct {
-	workDir string
+	workDir       string
+	recordingsDir string
 }
 
 // PTYManager manages multiple PTY sessions
@@ -36,6 +37,8 @@ type wsClient struct {
 	conn      *websocket.Conn
 	send      chan []byte // outbound queue for this client (PTY -> WS)
 	closeOnce sync.Once
+	// text frames are control messages and binary frames raw input
+	controlMessages bool
 }
 
 // PTYSession represents a single PTY session with multi-client support
@@ -54,6 +57,10 @@ type PTYSession struct {
 	// funnel of all client inputs -> single PTY writer (preserves ordering)
 	inCh chan []byte
 
+	// asciicast recording of the output, nil unless the session is recorded
+	recordingPath string
+	recorder      *castRecorder
+
 	// guards general session fields (info/cmd/ptmx)
 	mu sync.Mutex
 }
@@ -68,6 +75,8 @@ type PTYSessionInfo struct {
 	CreatedAt time.Time         `json:"createdAt" validate:"required"`
 	Active    bool              `json:"active" validate:"required"`
 	LazyStart bool              `json:"lazyStart" validate:"required"` // Whether this session uses lazy start
+	// ID of the asciicast recording of the session, if it is recorded
+	RecordingID string `json:"recordingId,omitempty" validate:"optional"`
 } // @name PtySessionInfo
 
 // API Request/Response types
@@ -80,6 +89,7 @@ type PTYCreateRequest struct {
 	Cols      *uint16           `json:"cols" validate:"optional"`
 	Rows      *uint16           `json:"rows" validate:"optional"`
 	LazyStart bool              `json:"lazyStart,omitempty"` // Don't start PTY until first client connects
+	Record    bool              `json:"record,omitempty"`    // Record the output of the session in asciicast v2 format
 } // @name PtyCreateRequest
 
 // PTYCreateResponse represents the response when creating a PTY session
@@ -97,3 +107,41 @@ type PTYResizeRequest struct {
 	Cols uint16 `json:"cols" binding:"required,min=1,max=1000"`
 	Rows uint16 `json:"rows" binding:"required,min=1,max=1000"`
 } // @name PtyResizeRequest
+
+// PTYSignalRequest represents a request to send a signal to a PTY session
+type PTYSignalRequest struct {
+	// Signal name or number (e.g., SIGINT, INT or 2)
+	Signal string `json:"signal" validate:"required"`
+} // @name PtySignalRequest
+
+// PTYRecording contains metadata about an asciicast recording of a PTY session
+type PTYRecording struct {
+	ID        string    `json:"id" validate:"required"`
+	SessionID string    `json:"sessionId" validate:"required"`
+	Cols      uint16    `json:"cols" validate:"required"`
+	Rows      uint16    `json:"rows" validate:"required"`
+	StartedAt time.Time `json:"startedAt" validate:"required"`
+	Size      int64     `json:"size" validate:"required"`   // Size of the recording file in bytes
+	Active    bool      `json:"active" validate:"required"` // Whether the session is still being recorded
+} // @name PtyRecording
+
+// PTYRecordingListResponse represents the response when listing PTY recordings
+type PTYRecordingListResponse struct {
+	Recordings []PTYRecording `json:"recordings" validate:"required"`
+} // @name PtyRecordingListResponse
+
+// PTYControlMessageType is the type of a control message sent by clients that connect with control=true
+type PTYControlMessageType string // @name PtyControlMessageType
+
+const (
+	PTYControlMessageResize PTYControlMessageType = "resize"
+	PTYControlMessageSignal PTYControlMessageType = "signal"
+)
+
+// PTYControlMessage is a text frame sent by clients that connect with control=true
+type PTYControlMessage struct {
+	Type   PTYControlMessageType `json:"type" validate:"required"`
+	Cols   uint16                `json:"cols,omitempty" validate:"optional"`
+	Rows   uint16                `json:"rows,omitempty" validate:"optional"`
+	Signal string                `json:"signal,omitempty" validate:"optional"`
+} // @name PtyControlMessage
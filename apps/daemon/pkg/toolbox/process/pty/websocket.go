@@ -5,20 +5,23 @@ package pty
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/daytonaio/daemon/pkg/common"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	log "github.com/sirupsen/logrus"
 )
 
 // attachWebSocket connects a new WebSocket client to the PTY session
-func (s *PTYSession) attachWebSocket(ws *websocket.Conn) {
+func (s *PTYSession) attachWebSocket(ws *websocket.Conn, controlMessages bool) {
 	cl := &wsClient{
-		id:   uuid.NewString(),
-		conn: ws,
-		send: make(chan []byte, 256), // if full, drop slow client
+		id:              uuid.NewString(),
+		conn:            ws,
+		send:            make(chan []byte, 256), // if full, drop slow client
+		controlMessages: controlMessages,
 	}
 
 	// Register client FIRST so it can receive PTY output via broadcast
@@ -74,13 +77,19 @@ func (s *PTYSession) clientReader(cl *wsClient) {
 	conn.SetReadLimit(readLimit)
 
 	for {
-		_, data, err := conn.ReadMessage()
+		msgType, data, err := conn.ReadMessage()
 		if err != nil {
 			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
 				log.Debug("ws read error:", err)
 			}
 			return
 		}
+		if cl.controlMessages && msgType == websocket.TextMessage {
+			if err := s.handleControlMessage(data); err != nil {
+				log.Debugf("PTY session %s control message error: %v", s.info.ID, err)
+			}
+			continue
+		}
 		// Send all message data to PTY (text or binary)
 		if err := s.sendToPTY(data); err != nil {
 			// Send error to client and close connection
@@ -167,3 +176,27 @@ func (s *PTYSession) closeClientsWithExitCode(exitCode int, exitReason string) {
 	}
 	s.clientsMu.Unlock()
 }
+
+// handleControlMessage applies a resize or signal control message sent by a client
+func (s *PTYSession) handleControlMessage(data []byte) error {
+	var msg PTYControlMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return fmt.Errorf("invalid control message: %w", err)
+	}
+
+	switch msg.Type {
+	case PTYControlMessageResize:
+		if msg.Cols == 0 || msg.Rows == 0 {
+			return errors.New("cols and rows are required")
+		}
+		return s.resize(msg.Cols, msg.Rows)
+	case PTYControlMessageSignal:
+		sig, err := common.ParseSignal(msg.Signal)
+		if err != nil {
+			return err
+		}
+		return s.signal(sig)
+	default:
+		return fmt.Errorf("unknown control message type: %s", msg.Type)
+	}
+}
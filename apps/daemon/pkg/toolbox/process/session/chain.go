@@ -0,0 +1,98 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"net/http"
+
+	"github.com/daytonaio/daemon/pkg/session"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// CreateSessionCommandChain godoc
+//
+//	@Summary		Run a chain of commands in a session
+//	@Description	Queue a batch of commands that run one after another in the session. By default a step depends on
+//	@Description	the previous one and only runs if it succeeded; dependsOn and condition change that per step.
+//	@Tags			process
+//	@Accept			json
+//	@Produce		json
+//	@Param			sessionId	path		string				true	"Session ID"
+//	@Param			request		body		CreateChainRequest	true	"Chain steps"
+//	@Success		202			{object}	CommandChain
+//	@Router			/process/session/{sessionId}/chain [post]
+//
+//	@id				CreateSessionCommandChain
+func (s *SessionController) CreateSessionCommandChain(c *gin.Context) {
+	var request CreateChainRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	steps := make([]session.ChainStep, 0, len(request.Steps))
+	for _, step := range request.Steps {
+		steps = append(steps, session.ChainStep{
+			Name:      step.Name,
+			Command:   step.Command,
+			DependsOn: step.DependsOn,
+			Condition: step.Condition,
+			Limits:    step.Limits.toLimits(),
+		})
+	}
+
+	chain, err := s.sessionService.CreateChain(c.Param("sessionId"), steps)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, ChainToDTO(chain))
+}
+
+// GetSessionCommandChain godoc
+//
+//	@Summary		Get command chain status
+//	@Description	Get the status of a command chain and each of its steps
+//	@Tags			process
+//	@Produce		json
+//	@Param			sessionId	path		string	true	"Session ID"
+//	@Param			chainId		path		string	true	"Chain ID"
+//	@Success		200			{object}	CommandChain
+//	@Router			/process/session/{sessionId}/chain/{chainId} [get]
+//
+//	@id				GetSessionCommandChain
+func (s *SessionController) GetSessionCommandChain(c *gin.Context) {
+	chain, err := s.sessionService.GetChain(c.Param("sessionId"), c.Param("chainId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ChainToDTO(chain))
+}
+
+// ListSessionCommandChains godoc
+//
+//	@Summary		List command chains
+//	@Description	List the command chains of a session
+//	@Tags			process
+//	@Produce		json
+//	@Param			sessionId	path	string	true	"Session ID"
+//	@Success		200			{array}	CommandChain
+//	@Router			/process/session/{sessionId}/chain [get]
+//
+//	@id				ListSessionCommandChains
+func (s *SessionController) ListSessionCommandChains(c *gin.Context) {
+	chains := s.sessionService.ListChains(c.Param("sessionId"))
+
+	result := make([]*CommandChain, 0, len(chains))
+	for _, chain := range chains {
+		result = append(result, ChainToDTO(chain))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -33,3 +33,8 @@ func NewSessionController(configDir, workDir string, terminationGracePeriodSecon
 		sessionService: service,
 	}
 }
+
+// SessionService returns the service backing the controller, for other modules that run commands in sessions
+func (s *SessionController) SessionService() *session.SessionService {
+	return s.sessionService
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// GetSessionEnv godoc
+//
+//	@Summary		Get session environment variables
+//	@Description	Get the environment variables set for a session on top of the sandbox defaults
+//	@Tags			process
+//	@Produce		json
+//	@Param			sessionId	path		string	true	"Session ID"
+//	@Success		200			{object}	SessionEnvResponse
+//	@Router			/process/session/{sessionId}/env [get]
+//
+//	@id				GetSessionEnv
+func (s *SessionController) GetSessionEnv(c *gin.Context) {
+	envs, err := s.sessionService.GetEnv(c.Param("sessionId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SessionEnvResponse{
+		Envs: envs,
+	})
+}
+
+// SetSessionEnv godoc
+//
+//	@Summary		Set session environment variables
+//	@Description	Export environment variables in a session. They apply to commands executed afterwards.
+//	@Description	Fails with 409 while a command of the session is running.
+//	@Tags			process
+//	@Accept			json
+//	@Produce		json
+//	@Param			sessionId	path		string				true	"Session ID"
+//	@Param			request		body		SessionEnvRequest	true	"Environment variables"
+//	@Success		200			{object}	SessionEnvResponse
+//	@Failure		409			{object}	common_errors.ErrorResponse
+//	@Router			/process/session/{sessionId}/env [put]
+//
+//	@id				SetSessionEnv
+func (s *SessionController) SetSessionEnv(c *gin.Context) {
+	sessionId := c.Param("sessionId")
+
+	var request SessionEnvRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	if err := s.sessionService.SetEnv(sessionId, request.Envs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	s.GetSessionEnv(c)
+}
+
+// UnsetSessionEnv godoc
+//
+//	@Summary		Unset session environment variables
+//	@Description	Unset environment variables in a session, including sandbox defaults.
+//	@Description	Fails with 409 while a command of the session is running.
+//	@Tags			process
+//	@Produce		json
+//	@Param			sessionId	path		string		true	"Session ID"
+//	@Param			name		query		[]string	true	"Variable names"
+//	@Success		200			{object}	SessionEnvResponse
+//	@Failure		409			{object}	common_errors.ErrorResponse
+//	@Router			/process/session/{sessionId}/env [delete]
+//
+//	@id				UnsetSessionEnv
+func (s *SessionController) UnsetSessionEnv(c *gin.Context) {
+	sessionId := c.Param("sessionId")
+
+	names := c.QueryArray("name")
+	if len(names) == 0 {
+		c.Error(common_errors.NewBadRequestError(errors.New("name is required")))
+		return
+	}
+
+	if err := s.sessionService.UnsetEnv(sessionId, names); err != nil {
+		c.Error(err)
+		return
+	}
+
+	s.GetSessionEnv(c)
+}
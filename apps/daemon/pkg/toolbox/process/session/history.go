@@ -0,0 +1,114 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/session"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+const defaultHistoryLimit = 100
+
+// ListCommandHistory godoc
+//
+//	@Summary		List command history
+//	@Description	List finished session commands, newest first. History is kept for 30 days and up to 10000 commands.
+//	@Tags			process
+//	@Produce		json
+//	@Param			sessionId	query	string	false	"Only commands of this session"
+//	@Param			exitCode	query	int		false	"Only commands that exited with this code"
+//	@Param			since		query	string	false	"Only commands that finished after this time (RFC 3339)"
+//	@Param			limit		query	int		false	"Maximum number of entries (default 100)"
+//	@Success		200			{array}	CommandHistoryEntry
+//	@Router			/process/history [get]
+//
+//	@id				ListCommandHistory
+func (s *SessionController) ListCommandHistory(c *gin.Context) {
+	s.listHistory(c, "")
+}
+
+// SearchCommandHistory godoc
+//
+//	@Summary		Search command history
+//	@Description	Search finished session commands by a case-insensitive substring of the command, newest first
+//	@Tags			process
+//	@Produce		json
+//	@Param			q			query	string	true	"Text to search for in the command"
+//	@Param			sessionId	query	string	false	"Only commands of this session"
+//	@Param			exitCode	query	int		false	"Only commands that exited with this code"
+//	@Param			since		query	string	false	"Only commands that finished after this time (RFC 3339)"
+//	@Param			limit		query	int		false	"Maximum number of entries (default 100)"
+//	@Success		200			{array}	CommandHistoryEntry
+//	@Router			/process/history/search [get]
+//
+//	@id				SearchCommandHistory
+func (s *SessionController) SearchCommandHistory(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.Error(common_errors.NewBadRequestError(errors.New("q is required")))
+		return
+	}
+
+	s.listHistory(c, query)
+}
+
+func (s *SessionController) listHistory(c *gin.Context, query string) {
+	filter := session.HistoryFilter{
+		SessionId: c.Query("sessionId"),
+		Query:     query,
+		Limit:     defaultHistoryLimit,
+	}
+
+	if exitCodeParam := c.Query("exitCode"); exitCodeParam != "" {
+		exitCode, err := strconv.Atoi(exitCodeParam)
+		if err != nil {
+			c.Error(common_errors.NewBadRequestError(errors.New("exitCode must be an integer")))
+			return
+		}
+		filter.ExitCode = &exitCode
+	}
+
+	if sinceParam := c.Query("since"); sinceParam != "" {
+		since, err := time.Parse(time.RFC3339, sinceParam)
+		if err != nil {
+			c.Error(common_errors.NewBadRequestError(errors.New("since must be an RFC 3339 timestamp")))
+			return
+		}
+		filter.Since = since
+	}
+
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 {
+			c.Error(common_errors.NewBadRequestError(errors.New("limit must be a positive integer")))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries := s.sessionService.ListHistory(filter)
+
+	result := make([]CommandHistoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, CommandHistoryEntry{
+			CommandId:   entry.CommandId,
+			SessionId:   entry.SessionId,
+			Command:     entry.Command,
+			ExitCode:    entry.ExitCode,
+			StartedAt:   entry.StartedAt,
+			EndedAt:     entry.EndedAt,
+			DurationMs:  entry.Duration().Milliseconds(),
+			LogFilePath: entry.LogFilePath,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
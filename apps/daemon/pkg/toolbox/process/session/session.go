@@ -6,6 +6,7 @@ package session
 import (
 	"net/http"
 
+	"github.com/daytonaio/daemon/pkg/session"
 	"github.com/gin-gonic/gin"
 
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
@@ -42,7 +43,11 @@ func (s *SessionController) CreateSession(c *gin.Context) {
 
 	isLegacy := versionComparison != nil && *versionComparison < 0 && sdkVersion != "0.0.0-dev"
 
-	err = s.sessionService.Create(request.SessionId, isLegacy)
+	err = s.sessionService.Create(request.SessionId, session.CreateOptions{
+		IsLegacy:   isLegacy,
+		Envs:       request.Envs,
+		Persistent: request.Persistent,
+	})
 	if err != nil {
 		c.Error(err)
 		return
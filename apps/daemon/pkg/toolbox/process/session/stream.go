@@ -0,0 +1,85 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/daytonaio/daemon/internal/util"
+	"github.com/daytonaio/daemon/pkg/session"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// StreamSessionCommandOutput godoc
+//
+//	@Summary		Stream session command output
+//	@Description	Stream stdout and stderr of a session command over a WebSocket as CommandOutputEvent JSON messages.
+//	@Description	Each event carries a sequence number and the log offset after it; reconnect with that offset to resume.
+//	@Description	The stream ends with an exit event once the command finishes.
+//	@Tags			process
+//	@Param			sessionId	path	string	true	"Session ID"
+//	@Param			commandId	path	string	true	"Command ID"
+//	@Param			offset		query	int		false	"Log offset to resume from (default 0)"
+//	@Success		101			{object}	CommandOutputEvent	"Switching Protocols - WebSocket connection established"
+//	@Router			/process/session/{sessionId}/command/{commandId}/stream [get]
+//
+//	@id				StreamSessionCommandOutput
+func (s *SessionController) StreamSessionCommandOutput(c *gin.Context) {
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		c.Error(common_errors.NewBadRequestError(errors.New("websocket upgrade required")))
+		return
+	}
+
+	var offset int64
+	if offsetParam := c.Query("offset"); offsetParam != "" {
+		var err error
+		offset, err = strconv.ParseInt(offsetParam, 10, 64)
+		if err != nil || offset < 0 {
+			c.Error(common_errors.NewBadRequestError(errors.New("offset must be a non-negative integer")))
+			return
+		}
+	}
+
+	stream, err := s.sessionService.NewOutputStream(c.Param("sessionId"), c.Param("commandId"), offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer stream.Close()
+
+	ws, err := util.UpgradeToWebSocket(c.Writer, c.Request)
+	if err != nil {
+		log.Errorf("Failed to upgrade command output connection: %v", err)
+		return
+	}
+	defer ws.Close()
+
+	ctx, cancel := util.ContextWithCancelOnClose(c.Request.Context(), ws)
+	defer cancel()
+
+	// Writes block until the client has read enough, which throttles reading the log
+	err = stream.Run(ctx, func(event session.OutputEvent) error {
+		_ = ws.SetWriteDeadline(time.Now().Add(30 * time.Second))
+		return ws.WriteJSON(CommandOutputEvent{
+			Type:     event.Type,
+			Seq:      event.Seq,
+			Offset:   event.Offset,
+			Data:     event.Data,
+			ExitCode: event.ExitCode,
+		})
+	})
+
+	closeCode := websocket.CloseNormalClosure
+	if err != nil {
+		log.Debugf("Command output stream ended: %v", err)
+		closeCode = websocket.CloseInternalServerErr
+	}
+	_ = ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, ""), time.Now().Add(time.Second))
+}
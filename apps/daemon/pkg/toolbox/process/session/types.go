@@ -3,18 +3,47 @@
 
 package session
 
-import "github.com/daytonaio/daemon/pkg/session"
+import (
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/session"
+)
 
 type CreateSessionRequest struct {
 	SessionId string `json:"sessionId" validate:"required"`
+	// Variables set for the session on top of the sandbox defaults
+	Envs map[string]string `json:"envs,omitempty" validate:"optional"`
+	// Run the session in tmux so that it and its commands survive daemon restarts
+	Persistent bool `json:"persistent,omitempty" validate:"optional"`
 } // @name CreateSessionRequest
 
+type SessionEnvRequest struct {
+	Envs map[string]string `json:"envs" validate:"required"`
+} // @name SessionEnvRequest
+
+type SessionEnvResponse struct {
+	Envs map[string]string `json:"envs" validate:"required"`
+} // @name SessionEnvResponse
+
 type SessionExecuteRequest struct {
 	Command  string `json:"command" validate:"required"`
 	RunAsync bool   `json:"runAsync" validate:"optional"`
 	Async    bool   `json:"async" validate:"optional"`
+	// Limits run the command in a subshell, so changes to the shell state (e.g. cd) do not persist
+	Limits *CommandLimits `json:"limits,omitempty" validate:"optional"`
 } // @name SessionExecuteRequest
 
+type CommandLimits struct {
+	// Number of CPU cores, e.g. 0.5. Only enforced when cgroup v2 is available
+	Cpus float64 `json:"cpus,omitempty" validate:"optional"`
+	// Memory limit in bytes
+	Memory int64 `json:"memory,omitempty" validate:"optional"`
+	// The command is terminated once it has written more output (in bytes)
+	MaxOutputSize int64 `json:"maxOutputSize,omitempty" validate:"optional"`
+	// Timeout in seconds after which the command and its child processes are terminated
+	Timeout uint32 `json:"timeout,omitempty" validate:"optional"`
+} // @name CommandLimits
+
 type SessionSendInputRequest struct {
 	Data string `json:"data" validate:"required"`
 } // @name SessionSendInputRequest
@@ -36,18 +65,36 @@ type CommandDTO struct {
 	Id       string `json:"id" validate:"required"`
 	Command  string `json:"command" validate:"required"`
 	ExitCode *int   `json:"exitCode,omitempty" validate:"optional"`
+	// timeout or output_limit if the command was terminated for exceeding its limits
+	TerminationReason *string `json:"terminationReason,omitempty" validate:"optional"`
 } // @name Command
 
 type SessionDTO struct {
-	SessionId string        `json:"sessionId" validate:"required"`
-	Commands  []*CommandDTO `json:"commands" validate:"required"`
+	SessionId  string        `json:"sessionId" validate:"required"`
+	Commands   []*CommandDTO `json:"commands" validate:"required"`
+	Persistent bool          `json:"persistent" validate:"required"`
 } // @name Session
 
 func CommandToDTO(c *session.Command) *CommandDTO {
+	exitCode, terminationReason := c.Result()
 	return &CommandDTO{
-		Id:       c.Id,
-		Command:  c.Command,
-		ExitCode: c.ExitCode,
+		Id:                c.Id,
+		Command:           c.Command,
+		ExitCode:          exitCode,
+		TerminationReason: terminationReason,
+	}
+}
+
+func (l *CommandLimits) toLimits() session.CommandLimits {
+	if l == nil {
+		return session.CommandLimits{}
+	}
+
+	return session.CommandLimits{
+		Cpus:           l.Cpus,
+		MemoryBytes:    l.Memory,
+		MaxOutputBytes: l.MaxOutputSize,
+		Timeout:        time.Duration(l.Timeout) * time.Second,
 	}
 }
 
@@ -58,7 +105,88 @@ func SessionToDTO(s *session.Session) *SessionDTO {
 	}
 
 	return &SessionDTO{
-		SessionId: s.SessionId,
-		Commands:  commands,
+		SessionId:  s.SessionId,
+		Commands:   commands,
+		Persistent: s.Persistent,
+	}
+}
+
+type ChainStepRequest struct {
+	// Unique name of the step, used to reference it in dependsOn
+	Name    string `json:"name" validate:"required"`
+	Command string `json:"command" validate:"required"`
+	// Names of earlier steps this step depends on, defaults to the previous step
+	DependsOn []string `json:"dependsOn,omitempty" validate:"optional"`
+	// success (default) runs the step only if its dependencies succeeded, always runs it regardless
+	Condition string         `json:"condition,omitempty" validate:"optional"`
+	Limits    *CommandLimits `json:"limits,omitempty" validate:"optional"`
+} // @name ChainStepRequest
+
+type CreateChainRequest struct {
+	Steps []ChainStepRequest `json:"steps" binding:"required,min=1" validate:"required"`
+} // @name CreateChainRequest
+
+type ChainStep struct {
+	Name      string   `json:"name" validate:"required"`
+	Command   string   `json:"command" validate:"required"`
+	DependsOn []string `json:"dependsOn" validate:"required"`
+	// pending, running, succeeded, failed or skipped
+	Status    string     `json:"status" validate:"required"`
+	CommandId string     `json:"commandId,omitempty" validate:"optional"`
+	ExitCode  *int       `json:"exitCode,omitempty" validate:"optional"`
+	StartedAt *time.Time `json:"startedAt,omitempty" validate:"optional"`
+	EndedAt   *time.Time `json:"endedAt,omitempty" validate:"optional"`
+} // @name ChainStep
+
+type CommandChain struct {
+	Id        string `json:"id" validate:"required"`
+	SessionId string `json:"sessionId" validate:"required"`
+	// running, succeeded or failed
+	Status string      `json:"status" validate:"required"`
+	Steps  []ChainStep `json:"steps" validate:"required"`
+} // @name CommandChain
+
+func ChainToDTO(c *session.Chain) *CommandChain {
+	steps := make([]ChainStep, 0, len(c.Steps))
+	for _, step := range c.Steps {
+		steps = append(steps, ChainStep{
+			Name:      step.Name,
+			Command:   step.Command,
+			DependsOn: step.DependsOn,
+			Status:    step.Status,
+			CommandId: step.CommandId,
+			ExitCode:  step.ExitCode,
+			StartedAt: step.StartedAt,
+			EndedAt:   step.EndedAt,
+		})
+	}
+
+	return &CommandChain{
+		Id:        c.Id,
+		SessionId: c.SessionId,
+		Status:    c.Status,
+		Steps:     steps,
 	}
 }
+
+type CommandHistoryEntry struct {
+	CommandId  string    `json:"commandId" validate:"required"`
+	SessionId  string    `json:"sessionId" validate:"required"`
+	Command    string    `json:"command" validate:"required"`
+	ExitCode   *int      `json:"exitCode,omitempty" validate:"optional"`
+	StartedAt  time.Time `json:"startedAt" validate:"required"`
+	EndedAt    time.Time `json:"endedAt" validate:"required"`
+	DurationMs int64     `json:"durationMs" validate:"required"`
+	// Path of the command output, removed together with its session
+	LogFilePath string `json:"logFilePath" validate:"required"`
+} // @name CommandHistoryEntry
+
+type CommandOutputEvent struct {
+	// stdout, stderr or exit
+	Type string `json:"type" validate:"required"`
+	Seq  uint64 `json:"seq" validate:"required"`
+	// Log offset after this event, pass it as the offset parameter to resume
+	Offset   int64  `json:"offset" validate:"required"`
+	Data     string `json:"data,omitempty" validate:"optional"`
+	ExitCode *int   `json:"exitCode,omitempty" validate:"optional"`
+} // @name CommandOutputEvent
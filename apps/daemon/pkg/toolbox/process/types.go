@@ -16,3 +16,25 @@ type ExecuteResponse struct {
 	ExitCode int    `json:"exitCode"`
 	Result   string `json:"result" validate:"required"`
 } // @name ExecuteResponse
+
+type ProcessInfo struct {
+	Pid     int32  `json:"pid" validate:"required"`
+	Ppid    int32  `json:"ppid" validate:"required"`
+	Name    string `json:"name" validate:"required"`
+	Command string `json:"command" validate:"required"`
+	User    string `json:"user" validate:"required"`
+	// Process state as reported by /proc, e.g. running, sleep, stop, zombie
+	State string `json:"state" validate:"required"`
+	// CPU usage over the sampling interval, 100 equals one fully used core
+	CpuPercent float64 `json:"cpuPercent" validate:"required"`
+	// Resident set size in bytes
+	Rss        uint64 `json:"rss" validate:"required"`
+	NumThreads int32  `json:"numThreads" validate:"required"`
+	// Unix timestamp in milliseconds
+	CreateTime int64 `json:"createTime" validate:"required"`
+} // @name ProcessInfo
+
+type KillProcessResponse struct {
+	Pid    int32  `json:"pid" validate:"required"`
+	Signal string `json:"signal" validate:"required"`
+} // @name KillProcessResponse
@@ -0,0 +1,48 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package provisioning
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daytonaio/daemon/pkg/provisioning"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+type ProvisioningController struct {
+	provisioner *provisioning.Provisioner
+}
+
+// NewProvisioningController starts the first boot provisioning of the sandbox, if it has any
+func NewProvisioningController(configDir string) *ProvisioningController {
+	provisioner := provisioning.NewProvisioner(provisioning.SpecPath, configDir)
+	provisioner.Start()
+
+	return &ProvisioningController{
+		provisioner: provisioner,
+	}
+}
+
+// GetProvisioningStatus godoc
+//
+//	@Summary		Get provisioning status
+//	@Description	Get the status of the provisioning steps run on the first boot of the sandbox
+//	@Tags			provisioning
+//	@Produce		json
+//	@Success		200	{object}	ProvisioningStatus
+//	@Router			/provisioning [get]
+//
+//	@id				GetProvisioningStatus
+func (p *ProvisioningController) GetProvisioningStatus(c *gin.Context) {
+	status := p.provisioner.Status()
+	if status == nil {
+		c.Error(common_errors.NewNotFoundError(errors.New("sandbox has no provisioning steps")))
+		return
+	}
+
+	c.JSON(http.StatusOK, ProvisioningStatusToDTO(status))
+}
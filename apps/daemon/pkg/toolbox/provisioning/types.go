@@ -0,0 +1,51 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package provisioning
+
+import (
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/provisioning"
+)
+
+type ProvisioningStepStatus struct {
+	Name string `json:"name" validate:"required"`
+	// pending, running, succeeded or failed
+	State    string `json:"state" validate:"required"`
+	Attempts int    `json:"attempts" validate:"required"`
+	ExitCode *int   `json:"exitCode,omitempty" validate:"optional"`
+	Error    string `json:"error,omitempty" validate:"optional"`
+	// End of the output of the last attempt
+	Output     string     `json:"output,omitempty" validate:"optional"`
+	StartedAt  *time.Time `json:"startedAt,omitempty" validate:"optional"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty" validate:"optional"`
+} // @name ProvisioningStepStatus
+
+type ProvisioningStatus struct {
+	// pending, running, succeeded or failed
+	State string                   `json:"state" validate:"required"`
+	Steps []ProvisioningStepStatus `json:"steps" validate:"required"`
+} // @name ProvisioningStatus
+
+func ProvisioningStatusToDTO(status *provisioning.Status) *ProvisioningStatus {
+	result := &ProvisioningStatus{
+		State: string(status.State),
+		Steps: make([]ProvisioningStepStatus, 0, len(status.Steps)),
+	}
+
+	for _, step := range status.Steps {
+		result.Steps = append(result.Steps, ProvisioningStepStatus{
+			Name:       step.Name,
+			State:      string(step.State),
+			Attempts:   step.Attempts,
+			ExitCode:   step.ExitCode,
+			Error:      step.Error,
+			Output:     step.Output,
+			StartedAt:  step.StartedAt,
+			FinishedAt: step.FinishedAt,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,19 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package scheduler
+
+import (
+	"github.com/daytonaio/daemon/pkg/scheduler"
+	"github.com/daytonaio/daemon/pkg/session"
+)
+
+type SchedulerController struct {
+	scheduler *scheduler.Scheduler
+}
+
+func NewSchedulerController(configDir string, sessionService *session.SessionService) *SchedulerController {
+	return &SchedulerController{
+		scheduler: scheduler.NewScheduler(configDir, sessionService),
+	}
+}
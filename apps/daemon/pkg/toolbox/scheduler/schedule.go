@@ -0,0 +1,177 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package scheduler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// CreateSchedule godoc
+//
+//	@Summary		Create a schedule
+//	@Description	Schedule a command to run periodically. Runs of a schedule execute one at a time in a session
+//	@Description	dedicated to the schedule; a run that is due while the previous one is still running is skipped.
+//	@Tags			scheduler
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ScheduleRequest	true	"Schedule"
+//	@Success		201		{object}	Schedule
+//	@Router			/schedules [post]
+//
+//	@id				CreateSchedule
+func (s *SchedulerController) CreateSchedule(c *gin.Context) {
+	var request ScheduleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	schedule, err := s.scheduler.Create(request.toParams())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, ScheduleToDTO(schedule))
+}
+
+// ListSchedules godoc
+//
+//	@Summary		List schedules
+//	@Description	List schedules with their next and last run
+//	@Tags			scheduler
+//	@Produce		json
+//	@Success		200	{array}	Schedule
+//	@Router			/schedules [get]
+//
+//	@id				ListSchedules
+func (s *SchedulerController) ListSchedules(c *gin.Context) {
+	schedules := s.scheduler.List()
+
+	result := make([]*Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		result = append(result, ScheduleToDTO(schedule))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetSchedule godoc
+//
+//	@Summary		Get a schedule
+//	@Tags			scheduler
+//	@Produce		json
+//	@Param			id	path		string	true	"Schedule ID"
+//	@Success		200	{object}	Schedule
+//	@Router			/schedules/{id} [get]
+//
+//	@id				GetSchedule
+func (s *SchedulerController) GetSchedule(c *gin.Context) {
+	schedule, err := s.scheduler.Get(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ScheduleToDTO(schedule))
+}
+
+// UpdateSchedule godoc
+//
+//	@Summary		Update a schedule
+//	@Description	Replace the cron expression, command and settings of a schedule. A run in progress is not affected.
+//	@Tags			scheduler
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Schedule ID"
+//	@Param			request	body		ScheduleRequest	true	"Schedule"
+//	@Success		200		{object}	Schedule
+//	@Router			/schedules/{id} [put]
+//
+//	@id				UpdateSchedule
+func (s *SchedulerController) UpdateSchedule(c *gin.Context) {
+	var request ScheduleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	schedule, err := s.scheduler.Update(c.Param("id"), request.toParams())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ScheduleToDTO(schedule))
+}
+
+// DeleteSchedule godoc
+//
+//	@Summary		Delete a schedule
+//	@Description	Delete a schedule and its session, stopping a run in progress
+//	@Tags			scheduler
+//	@Param			id	path	string	true	"Schedule ID"
+//	@Success		204
+//	@Router			/schedules/{id} [delete]
+//
+//	@id				DeleteSchedule
+func (s *SchedulerController) DeleteSchedule(c *gin.Context) {
+	if err := s.scheduler.Delete(c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// TriggerSchedule godoc
+//
+//	@Summary		Run a schedule now
+//	@Description	Start a run of the schedule immediately, even if it is disabled
+//	@Tags			scheduler
+//	@Produce		json
+//	@Param			id	path		string	true	"Schedule ID"
+//	@Success		202	{object}	ScheduleRun
+//	@Router			/schedules/{id}/run [post]
+//
+//	@id				TriggerSchedule
+func (s *SchedulerController) TriggerSchedule(c *gin.Context) {
+	run, err := s.scheduler.Trigger(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, RunToDTO(run))
+}
+
+// ListScheduleRuns godoc
+//
+//	@Summary		List runs of a schedule
+//	@Description	List the recent runs of a schedule, newest first. The last 50 runs are kept.
+//	@Tags			scheduler
+//	@Produce		json
+//	@Param			id	path	string	true	"Schedule ID"
+//	@Success		200	{array}	ScheduleRun
+//	@Router			/schedules/{id}/runs [get]
+//
+//	@id				ListScheduleRuns
+func (s *SchedulerController) ListScheduleRuns(c *gin.Context) {
+	schedule, err := s.scheduler.Get(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result := make([]*ScheduleRun, 0, len(schedule.Runs))
+	for i := len(schedule.Runs) - 1; i >= 0; i-- {
+		result = append(result, RunToDTO(&schedule.Runs[i]))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package scheduler
+
+import (
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/scheduler"
+)
+
+type ScheduleRequest struct {
+	Name string `json:"name" validate:"optional"`
+	// Cron expression with 5 fields (minute, hour, day of month, month, day of week) or a macro like @hourly
+	Cron    string `json:"cron" validate:"required"`
+	Command string `json:"command" validate:"required"`
+	// Defaults to true
+	Enabled *bool `json:"enabled,omitempty" validate:"optional"`
+	// Timeout of a run in seconds
+	Timeout uint32 `json:"timeout,omitempty" validate:"optional"`
+} // @name ScheduleRequest
+
+type ScheduleRun struct {
+	Id string `json:"id" validate:"required"`
+	// running, succeeded, failed or skipped
+	Status      string     `json:"status" validate:"required"`
+	ScheduledAt time.Time  `json:"scheduledAt" validate:"required"`
+	StartedAt   *time.Time `json:"startedAt,omitempty" validate:"optional"`
+	EndedAt     *time.Time `json:"endedAt,omitempty" validate:"optional"`
+	// Session the run was executed in, use it to get the command logs
+	SessionId string `json:"sessionId,omitempty" validate:"optional"`
+	CommandId string `json:"commandId,omitempty" validate:"optional"`
+	ExitCode  *int   `json:"exitCode,omitempty" validate:"optional"`
+	Error     string `json:"error,omitempty" validate:"optional"`
+} // @name ScheduleRun
+
+type Schedule struct {
+	Id        string       `json:"id" validate:"required"`
+	Name      string       `json:"name" validate:"required"`
+	Cron      string       `json:"cron" validate:"required"`
+	Command   string       `json:"command" validate:"required"`
+	Enabled   bool         `json:"enabled" validate:"required"`
+	Timeout   uint32       `json:"timeout,omitempty" validate:"optional"`
+	CreatedAt time.Time    `json:"createdAt" validate:"required"`
+	NextRunAt *time.Time   `json:"nextRunAt,omitempty" validate:"optional"`
+	LastRun   *ScheduleRun `json:"lastRun,omitempty" validate:"optional"`
+} // @name Schedule
+
+func (r *ScheduleRequest) toParams() scheduler.ScheduleParams {
+	return scheduler.ScheduleParams{
+		Name:    r.Name,
+		Cron:    r.Cron,
+		Command: r.Command,
+		Enabled: r.Enabled == nil || *r.Enabled,
+		Timeout: r.Timeout,
+	}
+}
+
+func ScheduleToDTO(s *scheduler.Schedule) *Schedule {
+	schedule := &Schedule{
+		Id:        s.Id,
+		Name:      s.Name,
+		Cron:      s.Cron,
+		Command:   s.Command,
+		Enabled:   s.Enabled,
+		Timeout:   s.Timeout,
+		CreatedAt: s.CreatedAt,
+		NextRunAt: s.NextRun(),
+	}
+
+	if len(s.Runs) > 0 {
+		schedule.LastRun = RunToDTO(&s.Runs[len(s.Runs)-1])
+	}
+
+	return schedule
+}
+
+func RunToDTO(r *scheduler.Run) *ScheduleRun {
+	return &ScheduleRun{
+		Id:          r.Id,
+		Status:      r.Status,
+		ScheduledAt: r.ScheduledAt,
+		StartedAt:   r.StartedAt,
+		EndedAt:     r.EndedAt,
+		SessionId:   r.SessionId,
+		CommandId:   r.CommandId,
+		ExitCode:    r.ExitCode,
+		Error:       r.Error,
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package supervisor
+
+import (
+	"github.com/daytonaio/daemon/pkg/supervisor"
+)
+
+type SupervisorController struct {
+	supervisor *supervisor.Supervisor
+}
+
+func NewSupervisorController(configDir string) *SupervisorController {
+	return &SupervisorController{
+		supervisor: supervisor.NewSupervisor(configDir),
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package supervisor
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// RegisterService godoc
+//
+//	@Summary		Register a service
+//	@Description	Register a long-running service that the daemon keeps alive and starts again when it restarts.
+//	@Description	Registering an existing service replaces its config and restarts it.
+//	@Tags			supervisor
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		RegisterServiceRequest	true	"Service config"
+//	@Success		200		{object}	ServiceStatus
+//	@Router			/services [post]
+//
+//	@id				RegisterService
+func (s *SupervisorController) RegisterService(c *gin.Context) {
+	var request RegisterServiceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	status, err := s.supervisor.Register(request.toConfig())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ServiceStatusToDTO(status))
+}
+
+// ListServices godoc
+//
+//	@Summary		List services
+//	@Description	List registered services and their status
+//	@Tags			supervisor
+//	@Produce		json
+//	@Success		200	{array}	ServiceStatus
+//	@Router			/services [get]
+//
+//	@id				ListServices
+func (s *SupervisorController) ListServices(c *gin.Context) {
+	statuses := s.supervisor.List()
+
+	result := make([]*ServiceStatus, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, ServiceStatusToDTO(status))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetService godoc
+//
+//	@Summary		Get service status
+//	@Description	Get the config and status of a service
+//	@Tags			supervisor
+//	@Produce		json
+//	@Param			name	path		string	true	"Service name"
+//	@Success		200		{object}	ServiceStatus
+//	@Router			/services/{name} [get]
+//
+//	@id				GetService
+func (s *SupervisorController) GetService(c *gin.Context) {
+	status, err := s.supervisor.Get(c.Param("name"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ServiceStatusToDTO(status))
+}
+
+// RemoveService godoc
+//
+//	@Summary		Remove a service
+//	@Description	Stop a service and remove it with its logs
+//	@Tags			supervisor
+//	@Param			name	path	string	true	"Service name"
+//	@Success		204
+//	@Router			/services/{name} [delete]
+//
+//	@id				RemoveService
+func (s *SupervisorController) RemoveService(c *gin.Context) {
+	if err := s.supervisor.Remove(c.Param("name")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// StartService godoc
+//
+//	@Summary		Start a service
+//	@Description	Start a stopped or failed service
+//	@Tags			supervisor
+//	@Produce		json
+//	@Param			name	path		string	true	"Service name"
+//	@Success		200		{object}	ServiceStatus
+//	@Router			/services/{name}/start [post]
+//
+//	@id				StartService
+func (s *SupervisorController) StartService(c *gin.Context) {
+	status, err := s.supervisor.Start(c.Param("name"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ServiceStatusToDTO(status))
+}
+
+// StopService godoc
+//
+//	@Summary		Stop a service
+//	@Description	Stop a service with SIGTERM, followed by SIGKILL after 10 seconds. It stays stopped until started again.
+//	@Tags			supervisor
+//	@Produce		json
+//	@Param			name	path		string	true	"Service name"
+//	@Success		200		{object}	ServiceStatus
+//	@Router			/services/{name}/stop [post]
+//
+//	@id				StopService
+func (s *SupervisorController) StopService(c *gin.Context) {
+	status, err := s.supervisor.Stop(c.Param("name"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ServiceStatusToDTO(status))
+}
+
+// RestartService godoc
+//
+//	@Summary		Restart a service
+//	@Description	Stop a service and start it again
+//	@Tags			supervisor
+//	@Produce		json
+//	@Param			name	path		string	true	"Service name"
+//	@Success		200		{object}	ServiceStatus
+//	@Router			/services/{name}/restart [post]
+//
+//	@id				RestartService
+func (s *SupervisorController) RestartService(c *gin.Context) {
+	status, err := s.supervisor.Restart(c.Param("name"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ServiceStatusToDTO(status))
+}
+
+// GetServiceLogs godoc
+//
+//	@Summary		Get service logs
+//	@Description	Get the combined stdout and stderr of a service
+//	@Tags			supervisor
+//	@Produce		json
+//	@Param			name	path		string	true	"Service name"
+//	@Param			tail	query		int		false	"Number of lines from the end (default all)"
+//	@Success		200		{object}	ServiceLogsResponse
+//	@Router			/services/{name}/logs [get]
+//
+//	@id				GetServiceLogs
+func (s *SupervisorController) GetServiceLogs(c *gin.Context) {
+	tail := 0
+	if tailParam := c.Query("tail"); tailParam != "" {
+		var err error
+		tail, err = strconv.Atoi(tailParam)
+		if err != nil || tail < 0 {
+			c.Error(common_errors.NewBadRequestError(errors.New("tail must be a non-negative integer")))
+			return
+		}
+	}
+
+	logs, err := s.supervisor.Logs(c.Param("name"), tail)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ServiceLogsResponse{
+		Logs: logs,
+	})
+}
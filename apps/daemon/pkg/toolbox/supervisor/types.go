@@ -0,0 +1,120 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/supervisor"
+)
+
+type HealthProbe struct {
+	// http, tcp or exec
+	Type string `json:"type" validate:"required"`
+	// URL for http probes, host:port for tcp probes and a shell command for exec probes
+	Target string `json:"target" validate:"required"`
+	// Seconds between probes, defaults to 10
+	IntervalSeconds int `json:"intervalSeconds,omitempty" validate:"optional"`
+	// Defaults to 5 seconds
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" validate:"optional"`
+	// Consecutive failures after which the service is restarted, defaults to 3
+	FailureThreshold int `json:"failureThreshold,omitempty" validate:"optional"`
+} // @name HealthProbe
+
+type RegisterServiceRequest struct {
+	Name    string            `json:"name" validate:"required"`
+	Command string            `json:"command" validate:"required"`
+	Cwd     string            `json:"cwd,omitempty" validate:"optional"`
+	Envs    map[string]string `json:"envs,omitempty" validate:"optional"`
+	// always, on-failure (default) or never
+	RestartPolicy string `json:"restartPolicy,omitempty" validate:"optional"`
+	// Restarts after which the service is marked as failed, 0 means no limit
+	MaxRestarts int          `json:"maxRestarts,omitempty" validate:"optional"`
+	HealthProbe *HealthProbe `json:"healthProbe,omitempty" validate:"optional"`
+	// Start the service when the daemon starts, defaults to true
+	Autostart *bool `json:"autostart,omitempty" validate:"optional"`
+} // @name RegisterServiceRequest
+
+type ServiceStatus struct {
+	Name          string            `json:"name" validate:"required"`
+	Command       string            `json:"command" validate:"required"`
+	Cwd           string            `json:"cwd,omitempty" validate:"optional"`
+	Envs          map[string]string `json:"envs,omitempty" validate:"optional"`
+	RestartPolicy string            `json:"restartPolicy" validate:"required"`
+	MaxRestarts   int               `json:"maxRestarts" validate:"required"`
+	HealthProbe   *HealthProbe      `json:"healthProbe,omitempty" validate:"optional"`
+	Autostart     bool              `json:"autostart" validate:"required"`
+	// starting, running, backoff, stopped or failed
+	State string `json:"state" validate:"required"`
+	// unknown, healthy or unhealthy
+	Health       string     `json:"health" validate:"required"`
+	Pid          int        `json:"pid,omitempty" validate:"optional"`
+	Restarts     int        `json:"restarts" validate:"required"`
+	StartedAt    *time.Time `json:"startedAt,omitempty" validate:"optional"`
+	LastExitCode *int       `json:"lastExitCode,omitempty" validate:"optional"`
+	LastError    string     `json:"lastError,omitempty" validate:"optional"`
+} // @name ServiceStatus
+
+type ServiceLogsResponse struct {
+	Logs string `json:"logs" validate:"required"`
+} // @name ServiceLogsResponse
+
+func (r *RegisterServiceRequest) toConfig() supervisor.ServiceConfig {
+	config := supervisor.ServiceConfig{
+		Name:          r.Name,
+		Command:       r.Command,
+		Cwd:           r.Cwd,
+		Envs:          r.Envs,
+		RestartPolicy: supervisor.RestartPolicy(r.RestartPolicy),
+		MaxRestarts:   r.MaxRestarts,
+		Autostart:     r.Autostart,
+	}
+
+	if r.HealthProbe != nil {
+		config.HealthProbe = &supervisor.HealthProbe{
+			Type:             supervisor.ProbeType(r.HealthProbe.Type),
+			Target:           r.HealthProbe.Target,
+			IntervalSeconds:  r.HealthProbe.IntervalSeconds,
+			TimeoutSeconds:   r.HealthProbe.TimeoutSeconds,
+			FailureThreshold: r.HealthProbe.FailureThreshold,
+		}
+	}
+
+	return config
+}
+
+func ServiceStatusToDTO(s *supervisor.ServiceStatus) *ServiceStatus {
+	status := &ServiceStatus{
+		Name:          s.Config.Name,
+		Command:       s.Config.Command,
+		Cwd:           s.Config.Cwd,
+		Envs:          s.Config.Envs,
+		RestartPolicy: string(s.Config.RestartPolicy),
+		MaxRestarts:   s.Config.MaxRestarts,
+		Autostart:     s.Config.Autostart == nil || *s.Config.Autostart,
+		State:         string(s.State),
+		Health:        string(s.Health),
+		Pid:           s.Pid,
+		Restarts:      s.Restarts,
+		StartedAt:     s.StartedAt,
+		LastExitCode:  s.LastExitCode,
+		LastError:     s.LastError,
+	}
+
+	if status.RestartPolicy == "" {
+		status.RestartPolicy = string(supervisor.RestartOnFailure)
+	}
+
+	if probe := s.Config.HealthProbe; probe != nil {
+		status.HealthProbe = &HealthProbe{
+			Type:             string(probe.Type),
+			Target:           probe.Target,
+			IntervalSeconds:  probe.IntervalSeconds,
+			TimeoutSeconds:   probe.TimeoutSeconds,
+			FailureThreshold: probe.FailureThreshold,
+		}
+	}
+
+	return status
+}
@@ -18,9 +18,18 @@ import (
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
 	common_proxy "github.com/daytonaio/common-go/pkg/proxy"
 	"github.com/daytonaio/daemon/internal"
+	"github.com/daytonaio/daemon/pkg/coredump"
+	"github.com/daytonaio/daemon/pkg/env"
+	"github.com/daytonaio/daemon/pkg/filehistory"
 	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
 	"github.com/daytonaio/daemon/pkg/toolbox/computeruse/manager"
 	"github.com/daytonaio/daemon/pkg/toolbox/config"
+	toolbox_coredump "github.com/daytonaio/daemon/pkg/toolbox/coredump"
+	toolbox_dotfiles "github.com/daytonaio/daemon/pkg/toolbox/dotfiles"
+	toolbox_env "github.com/daytonaio/daemon/pkg/toolbox/env"
+	toolbox_expiry "github.com/daytonaio/daemon/pkg/toolbox/expiry"
+	toolbox_filehistory "github.com/daytonaio/daemon/pkg/toolbox/filehistory"
+	toolbox_filesync "github.com/daytonaio/daemon/pkg/toolbox/filesync"
 	"github.com/daytonaio/daemon/pkg/toolbox/fs"
 	"github.com/daytonaio/daemon/pkg/toolbox/git"
 	"github.com/daytonaio/daemon/pkg/toolbox/lsp"
@@ -30,7 +39,15 @@ import (
 	"github.com/daytonaio/daemon/pkg/toolbox/process/interpreter"
 	"github.com/daytonaio/daemon/pkg/toolbox/process/pty"
 	"github.com/daytonaio/daemon/pkg/toolbox/process/session"
+	toolbox_provisioning "github.com/daytonaio/daemon/pkg/toolbox/provisioning"
 	"github.com/daytonaio/daemon/pkg/toolbox/proxy"
+	"github.com/daytonaio/daemon/pkg/toolbox/scheduler"
+	"github.com/daytonaio/daemon/pkg/toolbox/supervisor"
+	toolbox_toolchain "github.com/daytonaio/daemon/pkg/toolbox/toolchain"
+	toolbox_upgrade "github.com/daytonaio/daemon/pkg/toolbox/upgrade"
+	toolbox_watchdog "github.com/daytonaio/daemon/pkg/toolbox/watchdog"
+	"github.com/daytonaio/daemon/pkg/upgrade"
+	"github.com/daytonaio/daemon/pkg/watchdog"
 
 	"github.com/daytonaio/daemon/pkg/toolbox/docs"
 	"github.com/gin-gonic/gin"
@@ -46,6 +63,16 @@ type Server struct {
 	ComputerUse                          computeruse.IComputerUse
 	TerminationGracePeriodSeconds        int
 	TerminationCheckIntervalMilliseconds int
+	MemoryWatchdog                       *watchdog.Watchdog
+	CoreDumps                            *coredump.Manager
+	// Snapshots of the working directory, disabled if nil
+	FileHistory *filehistory.Config
+	// If set, the toolbox API is also served on this unix socket
+	SocketPath string
+	// Serve the toolbox API on SocketPath only
+	TcpDisabled bool
+	// Secret required on all requests, requests are not authenticated if empty
+	AuthToken string
 }
 
 type WorkDirResponse struct {
@@ -113,6 +140,21 @@ func (s *Server) GetVersion(ctx *gin.Context) {
 	})
 }
 
+// beforeUpgrade stops the computer-use processes and plugin, which the new daemon starts again, and
+// passes the auth token on to the new daemon
+func (s *Server) beforeUpgrade() {
+	if s.AuthToken != "" {
+		os.Setenv(config.AUTH_TOKEN_ENV_VAR, s.AuthToken)
+	}
+
+	if s.ComputerUse != nil {
+		if _, err := s.ComputerUse.Stop(); err != nil {
+			log.Errorf("Failed to stop computer use before upgrade: %v", err)
+		}
+	}
+	manager.Shutdown()
+}
+
 func (s *Server) Start() error {
 	docs.SwaggerInfo.Description = "Daytona Toolbox API"
 	docs.SwaggerInfo.Title = "Daytona Toolbox API"
@@ -132,6 +174,7 @@ func (s *Server) Start() error {
 			Message:    err.Error(),
 		}
 	}))
+	r.Use(middlewares.AuthMiddleware(s.AuthToken))
 	binding.Validator = new(DefaultValidator)
 
 	// Add swagger UI in development mode
@@ -159,33 +202,149 @@ func (s *Server) Start() error {
 
 	log.Println("configDir", configDir)
 
+	if err := env.Init(configDir); err != nil {
+		log.Errorf("Failed to initialize environment defaults: %v", err)
+	}
+
 	fsController := r.Group("/files")
 	{
 		// read operations
 		fsController.GET("/", fs.ListFiles)
+		fsController.POST("/archive", fs.CreateArchive)
+		fsController.GET("/checksum", fs.GetFileChecksum)
 		fsController.GET("/download", fs.DownloadFile)
 		fsController.POST("/bulk-download", fs.DownloadFiles)
 		fsController.GET("/find", fs.FindInFiles)
+		fsController.POST("/grep", fs.Grep)
 		fsController.GET("/info", fs.GetFileInfo)
 		fsController.GET("/search", fs.SearchFiles)
+		fsController.GET("/usage", fs.GetFilesystemUsage)
+		storagePressureController := fs.NewStoragePressureController(configDir)
+		fsController.GET("/usage/pressure", storagePressureController.ListStoragePressureEvents)
+		fsController.POST("/usage/pressure", storagePressureController.ReportStoragePressure)
+		fsController.GET("/usage/pressure/events", storagePressureController.WatchStoragePressure)
+		fsController.GET("/watch", fs.WatchFiles)
 
 		// create/modify operations
+		fsController.POST("/extract", fs.ExtractArchive)
 		fsController.POST("/folder", fs.CreateFolder)
+		fsController.POST("/hardlink", fs.CreateHardlink)
 		fsController.POST("/move", fs.MoveFile)
 		fsController.POST("/permissions", fs.SetFilePermissions)
 		fsController.POST("/replace", fs.ReplaceInFiles)
+		fsController.POST("/symlink", fs.CreateSymlink)
 		fsController.POST("/upload", fs.UploadFile)
 		fsController.POST("/bulk-upload", fs.UploadFiles)
 
+		// resumable uploads
+		uploadController := fs.NewUploadController(configDir)
+		fsController.POST("/uploads", uploadController.CreateUpload)
+		fsController.GET("/uploads/:uploadId", uploadController.GetUpload)
+		fsController.PUT("/uploads/:uploadId", uploadController.UploadChunk)
+		fsController.POST("/uploads/:uploadId/finalize", uploadController.FinalizeUpload)
+		fsController.DELETE("/uploads/:uploadId", uploadController.AbortUpload)
+
 		// delete operations
 		fsController.DELETE("/", fs.DeleteFile)
 	}
 
+	envController := r.Group("/env")
+	{
+		envController.GET("", toolbox_env.GetEnv)
+		envController.PUT("", toolbox_env.SetEnv)
+		envController.DELETE("", toolbox_env.UnsetEnv)
+		envController.POST("/dotenv/parse", toolbox_env.ParseDotenv)
+		envController.POST("/dotenv/merge", toolbox_env.MergeDotenv)
+		envController.POST("/dotenv/load", toolbox_env.LoadDotenv)
+	}
+
+	if s.MemoryWatchdog != nil {
+		watchdogController := toolbox_watchdog.NewWatchdogController(s.MemoryWatchdog)
+		watchdogGroup := r.Group("/watchdog")
+		{
+			watchdogGroup.GET("/memory", watchdogController.GetMemoryStatus)
+			watchdogGroup.GET("/events", watchdogController.ListWatchdogEvents)
+		}
+	}
+
+	if s.CoreDumps != nil {
+		coreDumpController := toolbox_coredump.NewCoreDumpController(s.CoreDumps)
+		coreDumpsGroup := r.Group("/coredumps")
+		{
+			coreDumpsGroup.GET("", coreDumpController.ListCoreDumps)
+			coreDumpsGroup.GET("/:name", coreDumpController.DownloadCoreDump)
+			coreDumpsGroup.DELETE("/:name", coreDumpController.DeleteCoreDump)
+		}
+	}
+
+	supervisorController := supervisor.NewSupervisorController(configDir)
+	servicesGroup := r.Group("/services")
+	{
+		servicesGroup.GET("", supervisorController.ListServices)
+		servicesGroup.POST("", supervisorController.RegisterService)
+		servicesGroup.GET("/:name", supervisorController.GetService)
+		servicesGroup.DELETE("/:name", supervisorController.RemoveService)
+		servicesGroup.POST("/:name/start", supervisorController.StartService)
+		servicesGroup.POST("/:name/stop", supervisorController.StopService)
+		servicesGroup.POST("/:name/restart", supervisorController.RestartService)
+		servicesGroup.GET("/:name/logs", supervisorController.GetServiceLogs)
+	}
+
+	if s.FileHistory != nil {
+		history := filehistory.NewHistory(s.WorkDir, path.Join(configDir, "history"), *s.FileHistory)
+		if err := history.Start(context.Background()); err != nil {
+			log.Errorf("Failed to start the file history: %v", err)
+		} else {
+			historyController := toolbox_filehistory.NewFileHistoryController(history)
+			historyGroup := r.Group("/history")
+			{
+				historyGroup.GET("/versions", historyController.ListVersions)
+				historyGroup.POST("/versions", historyController.CreateVersion)
+				historyGroup.GET("/versions/:versionId/files", historyController.DownloadFileVersion)
+				historyGroup.GET("/files", historyController.ListFileVersions)
+				historyGroup.POST("/restore", historyController.Restore)
+			}
+		}
+	}
+
+	syncController := toolbox_filesync.NewSyncController(s.WorkDir, path.Join(configDir, "sync"))
+	syncGroup := r.Group("/sync")
+	{
+		syncGroup.POST("/manifest", syncController.GetManifest)
+		syncGroup.POST("/watch", syncController.Watch)
+		syncGroup.POST("/chunks/missing", syncController.GetMissingChunks)
+		syncGroup.PUT("/chunks/:hash", syncController.UploadChunk)
+		syncGroup.GET("/chunks/:hash", syncController.DownloadChunk)
+		syncGroup.POST("/apply", syncController.Apply)
+	}
+
+	expiryController := toolbox_expiry.NewExpiryController(configDir)
+	r.GET("/expiry", expiryController.GetExpiryWarning)
+	r.POST("/expiry/warning", expiryController.ReportExpiryWarning)
+
+	provisioningController := toolbox_provisioning.NewProvisioningController(configDir)
+	r.GET("/provisioning", provisioningController.GetProvisioningStatus)
+
+	dotfilesController := toolbox_dotfiles.NewDotfilesController(configDir, dirname)
+	r.GET("/dotfiles", dotfilesController.GetDotfilesStatus)
+	r.POST("/dotfiles/apply", dotfilesController.ApplyDotfiles)
+
+	toolchainController := toolbox_toolchain.NewToolchainController(s.WorkDir)
+	r.GET("/toolchain", toolchainController.DetectToolchain)
+
 	processController := r.Group("/process")
 	{
 		processController.POST("/execute", process.ExecuteCommand)
+		processController.GET("/list", process.ListProcesses)
+		processController.POST("/:pid/kill", process.KillProcess)
 
 		sessionController := session.NewSessionController(configDir, s.WorkDir, s.TerminationGracePeriodSeconds, s.TerminationCheckIntervalMilliseconds)
+
+		healthChecker := &healthChecker{server: s, sessionService: sessionController.SessionService()}
+		r.GET("/health", healthChecker.GetHealth)
+
+		processController.GET("/history", sessionController.ListCommandHistory)
+		processController.GET("/history/search", sessionController.SearchCommandHistory)
 		sessionGroup := processController.Group("/session")
 		{
 			sessionGroup.GET("", sessionController.ListSessions)
@@ -193,21 +352,56 @@ func (s *Server) Start() error {
 			sessionGroup.POST("/:sessionId/exec", sessionController.SessionExecuteCommand)
 			sessionGroup.GET("/:sessionId", sessionController.GetSession)
 			sessionGroup.DELETE("/:sessionId", sessionController.DeleteSession)
+			sessionGroup.GET("/:sessionId/env", sessionController.GetSessionEnv)
+			sessionGroup.PUT("/:sessionId/env", sessionController.SetSessionEnv)
+			sessionGroup.DELETE("/:sessionId/env", sessionController.UnsetSessionEnv)
 			sessionGroup.GET("/:sessionId/command/:commandId", sessionController.GetSessionCommand)
 			sessionGroup.POST("/:sessionId/command/:commandId/input", sessionController.SendInput)
 			sessionGroup.GET("/:sessionId/command/:commandId/logs", sessionController.GetSessionCommandLogs)
+			sessionGroup.GET("/:sessionId/command/:commandId/stream", sessionController.StreamSessionCommandOutput)
+			sessionGroup.GET("/:sessionId/chain", sessionController.ListSessionCommandChains)
+			sessionGroup.POST("/:sessionId/chain", sessionController.CreateSessionCommandChain)
+			sessionGroup.GET("/:sessionId/chain/:chainId", sessionController.GetSessionCommandChain)
+		}
+
+		schedulerController := scheduler.NewSchedulerController(configDir, sessionController.SessionService())
+		schedulesGroup := r.Group("/schedules")
+		{
+			schedulesGroup.GET("", schedulerController.ListSchedules)
+			schedulesGroup.POST("", schedulerController.CreateSchedule)
+			schedulesGroup.GET("/:id", schedulerController.GetSchedule)
+			schedulesGroup.PUT("/:id", schedulerController.UpdateSchedule)
+			schedulesGroup.DELETE("/:id", schedulerController.DeleteSchedule)
+			schedulesGroup.POST("/:id/run", schedulerController.TriggerSchedule)
+			schedulesGroup.GET("/:id/runs", schedulerController.ListScheduleRuns)
 		}
 
 		// PTY endpoints
-		ptyController := pty.NewPTYController(s.WorkDir)
+		ptyController := pty.NewPTYController(s.WorkDir, configDir)
 		ptyGroup := processController.Group("/pty")
 		{
 			ptyGroup.GET("", ptyController.ListPTYSessions)
 			ptyGroup.POST("", ptyController.CreatePTYSession)
+			ptyGroup.GET("/recordings", ptyController.ListPTYRecordings)
+			ptyGroup.GET("/recordings/:recordingId", ptyController.DownloadPTYRecording)
+			ptyGroup.DELETE("/recordings/:recordingId", ptyController.DeletePTYRecording)
 			ptyGroup.GET("/:sessionId", ptyController.GetPTYSession)
 			ptyGroup.DELETE("/:sessionId", ptyController.DeletePTYSession)
 			ptyGroup.GET("/:sessionId/connect", ptyController.ConnectPTYSession)
 			ptyGroup.POST("/:sessionId/resize", ptyController.ResizePTYSession)
+			ptyGroup.POST("/:sessionId/signal", ptyController.SignalPTYSession)
+		}
+
+		// The upgrade waits for the work that doesn't survive replacing the daemon process
+		upgrader := upgrade.NewUpgrader(map[string]upgrade.Drainer{
+			"session commands": sessionController.SessionService().RunningCommandCount,
+			"PTY sessions":     pty.ActiveSessionCount,
+		}, s.beforeUpgrade)
+		upgradeController := toolbox_upgrade.NewUpgradeController(upgrader)
+		upgradeGroup := r.Group("/upgrade")
+		{
+			upgradeGroup.GET("", upgradeController.GetUpgradeStatus)
+			upgradeGroup.POST("", upgradeController.Upgrade)
 		}
 
 		// Interpreter endpoints
@@ -233,6 +427,7 @@ func (s *Server) Start() error {
 		gitController.DELETE("/branches", git.DeleteBranch)
 		gitController.POST("/clone", git.CloneRepository)
 		gitController.POST("/commit", git.CommitChanges)
+		gitController.POST("/fetch", git.FetchChanges)
 		gitController.POST("/pull", git.PullChanges)
 		gitController.POST("/push", git.PushChanges)
 	}
@@ -250,6 +445,13 @@ func (s *Server) Start() error {
 
 		lspController.GET("/document-symbols", lsp.DocumentSymbols)
 		lspController.GET("/workspacesymbols", lsp.WorkspaceSymbols)
+
+		//	language server management and raw LSP over WebSocket
+		lspController.GET("/languages", lsp.ListLanguages)
+		lspController.POST("/install", lsp.Install)
+		lspController.GET("/servers", lsp.ListServers)
+		lspController.DELETE("/servers", lsp.StopServer)
+		lspController.GET("/connect", lsp.Connect)
 	}
 
 	// Initialize plugin-based computer use
@@ -271,6 +473,11 @@ func (s *Server) Start() error {
 			computerUseHandler := computeruse.Handler{
 				ComputerUse: s.ComputerUse,
 			}
+			// Mouse and keyboard actions go through the recorder so they can be recorded as macros
+			macroRecorder := computeruse.NewMacroRecorder(s.ComputerUse, configDir)
+			macroHandler := computeruse.MacroHandler{
+				Recorder: macroRecorder,
+			}
 
 			// Computer use status endpoint
 			computerUseController.GET("/status", computeruse.WrapStatusHandler(s.ComputerUse.GetStatus))
@@ -292,19 +499,44 @@ func (s *Server) Start() error {
 
 			// Mouse control endpoints
 			computerUseController.GET("/mouse/position", computeruse.WrapMousePositionHandler(s.ComputerUse.GetMousePosition))
-			computerUseController.POST("/mouse/move", computeruse.WrapMoveMouseHandler(s.ComputerUse.MoveMouse))
-			computerUseController.POST("/mouse/click", computeruse.WrapClickHandler(s.ComputerUse.Click))
-			computerUseController.POST("/mouse/drag", computeruse.WrapDragHandler(s.ComputerUse.Drag))
-			computerUseController.POST("/mouse/scroll", computeruse.WrapScrollHandler(s.ComputerUse.Scroll))
+			computerUseController.POST("/mouse/move", computeruse.WrapMoveMouseHandler(macroRecorder.MoveMouse))
+			computerUseController.POST("/mouse/click", computeruse.WrapClickHandler(macroRecorder.Click))
+			computerUseController.POST("/mouse/drag", computeruse.WrapDragHandler(macroRecorder.Drag))
+			computerUseController.POST("/mouse/scroll", computeruse.WrapScrollHandler(macroRecorder.Scroll))
 
 			// Keyboard control endpoints
-			computerUseController.POST("/keyboard/type", computeruse.WrapTypeTextHandler(s.ComputerUse.TypeText))
-			computerUseController.POST("/keyboard/key", computeruse.WrapPressKeyHandler(s.ComputerUse.PressKey))
-			computerUseController.POST("/keyboard/hotkey", computeruse.WrapPressHotkeyHandler(s.ComputerUse.PressHotkey))
+			computerUseController.POST("/keyboard/type", computeruse.WrapTypeTextHandler(macroRecorder.TypeText))
+			computerUseController.POST("/keyboard/key", computeruse.WrapPressKeyHandler(macroRecorder.PressKey))
+			computerUseController.POST("/keyboard/hotkey", computeruse.WrapPressHotkeyHandler(macroRecorder.PressHotkey))
 
 			// Display info endpoints
 			computerUseController.GET("/display/info", computeruse.WrapDisplayInfoHandler(s.ComputerUse.GetDisplayInfo))
 			computerUseController.GET("/display/windows", computeruse.WrapWindowsHandler(s.ComputerUse.GetWindows))
+			computerUseController.POST("/display", computeruse.WrapAddDisplayHandler(s.ComputerUse.AddDisplay))
+			computerUseController.POST("/display/:id/resize", computeruse.WrapResizeDisplayHandler(s.ComputerUse.ResizeDisplay))
+
+			// Element lookup endpoints
+			computerUseController.GET("/accessibility/tree", computeruse.WrapAccessibilityTreeHandler(s.ComputerUse.GetAccessibilityTree))
+			computerUseController.GET("/ocr", computeruse.WrapOcrHandler(s.ComputerUse.RecognizeText))
+			computerUseController.POST("/element/click", computeruse.WrapClickElementHandler(s.ComputerUse.ClickElement))
+
+			// Clipboard endpoints
+			computerUseController.GET("/clipboard", computeruse.WrapGetClipboardHandler(s.ComputerUse.GetClipboard))
+			computerUseController.POST("/clipboard", computeruse.WrapSetClipboardHandler(s.ComputerUse.SetClipboard))
+
+			// Live stream endpoints
+			screenStreamer := computeruse.NewScreenStreamer(s.ComputerUse)
+			computerUseController.GET("/stream/mjpeg", screenStreamer.StreamMjpeg)
+
+			// Macro endpoints
+			computerUseController.GET("/macros", macroHandler.ListMacros)
+			computerUseController.POST("/macros/record/start", macroHandler.StartMacroRecording)
+			computerUseController.POST("/macros/record/stop", macroHandler.StopMacroRecording)
+			computerUseController.POST("/macros/record/assertion", macroHandler.AddMacroAssertion)
+			computerUseController.GET("/macros/:name", macroHandler.GetMacro)
+			computerUseController.PUT("/macros/:name", macroHandler.SaveMacro)
+			computerUseController.DELETE("/macros/:name", macroHandler.DeleteMacro)
+			computerUseController.POST("/macros/:name/replay", macroHandler.ReplayMacro)
 		} else {
 			// Register all endpoints with disabled middleware when plugin is not available
 			computerUseController.GET("/status", computeruse.ComputerUseDisabledMiddleware())
@@ -329,6 +561,22 @@ func (s *Server) Start() error {
 			computerUseController.POST("/keyboard/hotkey", computeruse.ComputerUseDisabledMiddleware())
 			computerUseController.GET("/display/info", computeruse.ComputerUseDisabledMiddleware())
 			computerUseController.GET("/display/windows", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.POST("/display", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.POST("/display/:id/resize", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.GET("/accessibility/tree", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.GET("/ocr", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.POST("/element/click", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.GET("/clipboard", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.POST("/clipboard", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.GET("/stream/mjpeg", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.GET("/macros", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.POST("/macros/record/start", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.POST("/macros/record/stop", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.POST("/macros/record/assertion", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.GET("/macros/:name", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.PUT("/macros/:name", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.DELETE("/macros/:name", computeruse.ComputerUseDisabledMiddleware())
+			computerUseController.POST("/macros/:name/replay", computeruse.ComputerUseDisabledMiddleware())
 		}
 	}
 
@@ -337,6 +585,8 @@ func (s *Server) Start() error {
 	portController := r.Group("/port")
 	{
 		portController.GET("", portDetector.GetPorts)
+		portController.GET("/listening", portDetector.GetListeningPorts)
+		portController.GET("/events", portDetector.WatchPorts)
 		portController.GET("/:port/in-use", portDetector.IsPortInUse)
 	}
 
@@ -352,13 +602,72 @@ func (s *Server) Start() error {
 		Handler: r,
 	}
 
-	// Print to stdout so the runner can know that the daemon is ready
-	fmt.Println("Starting toolbox server on port", config.TOOLBOX_API_PORT)
+	// The runner multiplexes its requests on a single HTTP/2 connection without TLS
+	httpServer.Protocols = &http.Protocols{}
+	httpServer.Protocols.SetHTTP1(true)
+	httpServer.Protocols.SetUnencryptedHTTP2(true)
+
+	if s.SocketPath == "" {
+		// Print to stdout so the runner can know that the daemon is ready
+		fmt.Println("Starting toolbox server on port", config.TOOLBOX_API_PORT)
+
+		listener, err := net.Listen("tcp", httpServer.Addr)
+		if err != nil {
+			return err
+		}
+
+		return httpServer.Serve(listener)
+	}
+
+	socketListener, err := listenUnixSocket(s.SocketPath)
+	if err != nil {
+		return err
+	}
+
+	if s.TcpDisabled {
+		fmt.Println("Starting toolbox server on socket", s.SocketPath)
+		return httpServer.Serve(socketListener)
+	}
+
+	fmt.Println("Starting toolbox server on port", config.TOOLBOX_API_PORT, "and socket", s.SocketPath)
 
 	listener, err := net.Listen("tcp", httpServer.Addr)
 	if err != nil {
+		socketListener.Close()
 		return err
 	}
 
-	return httpServer.Serve(listener)
+	errChan := make(chan error, 2)
+	go func() {
+		errChan <- httpServer.Serve(socketListener)
+	}()
+	go func() {
+		errChan <- httpServer.Serve(listener)
+	}()
+
+	return <-errChan
+}
+
+// listenUnixSocket listens on a unix socket that any user of the sandbox can connect to, replacing
+// the socket left behind by a previous daemon
+func listenUnixSocket(socketPath string) (net.Listener, error) {
+	if err := os.MkdirAll(path.Dir(socketPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(socketPath, 0666); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+
+	return listener, nil
 }
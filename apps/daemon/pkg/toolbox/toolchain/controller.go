@@ -0,0 +1,72 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package toolchain
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/daytonaio/daemon/pkg/toolchain"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+const (
+	defaultDepth = 2
+	maxDepth     = 5
+)
+
+type ToolchainController struct {
+	workDir string
+}
+
+func NewToolchainController(workDir string) *ToolchainController {
+	return &ToolchainController{
+		workDir: workDir,
+	}
+}
+
+// DetectToolchain godoc
+//
+//	@Summary		Detect toolchains
+//	@Description	Inspect the manifests and lockfiles of a directory and its subdirectories and report the languages and package managers of its projects, with recommended install, build, run and test commands
+//	@Tags			toolchain
+//	@Produce		json
+//	@Param			path	query		string	false	"Directory to inspect (defaults to working directory)"
+//	@Param			depth	query		int		false	"Levels of subdirectories to inspect, 2 by default, at most 5"
+//	@Success		200		{object}	ToolchainReport
+//	@Router			/toolchain [get]
+//
+//	@id				DetectToolchain
+func (t *ToolchainController) DetectToolchain(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		path = t.workDir
+	}
+
+	depth := defaultDepth
+	if value := c.Query("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > maxDepth {
+			c.Error(common_errors.NewBadRequestError(errors.New("depth must be an integer between 0 and 5")))
+			return
+		}
+		depth = parsed
+	}
+
+	report, err := toolchain.Detect(path, depth)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.Error(common_errors.NewNotFoundError(err))
+			return
+		}
+		c.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ToolchainReportToDTO(report))
+}
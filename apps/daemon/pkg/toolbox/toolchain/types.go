@@ -0,0 +1,63 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package toolchain
+
+import (
+	"github.com/daytonaio/daemon/pkg/toolchain"
+)
+
+type ToolchainCommands struct {
+	Install string `json:"install,omitempty" validate:"optional"`
+	Build   string `json:"build,omitempty" validate:"optional"`
+	Run     string `json:"run,omitempty" validate:"optional"`
+	Test    string `json:"test,omitempty" validate:"optional"`
+} // @name ToolchainCommands
+
+type ToolchainProject struct {
+	// Relative to the inspected directory, . for the directory itself
+	Path string `json:"path" validate:"required"`
+	// e.g. typescript, python, go
+	Language string `json:"language" validate:"required"`
+	// e.g. pnpm, uv, cargo
+	PackageManager string   `json:"packageManager,omitempty" validate:"optional"`
+	Frameworks     []string `json:"frameworks,omitempty" validate:"optional"`
+	// Manifests and lockfiles the project was detected from
+	Files []string `json:"files" validate:"required"`
+	// Recommended commands, run in the directory of the project
+	Commands ToolchainCommands `json:"commands" validate:"required"`
+} // @name ToolchainProject
+
+type ToolchainReport struct {
+	Root            string             `json:"root" validate:"required"`
+	Languages       []string           `json:"languages" validate:"required"`
+	PackageManagers []string           `json:"packageManagers" validate:"required"`
+	Projects        []ToolchainProject `json:"projects" validate:"required"`
+} // @name ToolchainReport
+
+func ToolchainReportToDTO(report *toolchain.Report) *ToolchainReport {
+	result := &ToolchainReport{
+		Root:            report.Root,
+		Languages:       report.Languages,
+		PackageManagers: report.PackageManagers,
+		Projects:        make([]ToolchainProject, 0, len(report.Projects)),
+	}
+
+	for _, project := range report.Projects {
+		result.Projects = append(result.Projects, ToolchainProject{
+			Path:           project.Path,
+			Language:       project.Language,
+			PackageManager: project.PackageManager,
+			Frameworks:     project.Frameworks,
+			Files:          project.Files,
+			Commands: ToolchainCommands{
+				Install: project.Commands.Install,
+				Build:   project.Commands.Build,
+				Run:     project.Commands.Run,
+				Test:    project.Commands.Test,
+			},
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,29 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package upgrade
+
+type UpgradeRequest struct {
+	// Path of the new daemon binary inside the sandbox
+	Path string `json:"path" validate:"required"`
+	// Version of the new binary, reported in the upgrade status
+	Version string `json:"version,omitempty" validate:"optional"`
+	// Expected SHA-256 checksum of the binary, hex encoded
+	Sha256 string `json:"sha256,omitempty" validate:"optional"`
+	// Seconds to wait for running commands and PTY sessions to finish, defaults to 60
+	DrainTimeoutSeconds int `json:"drainTimeoutSeconds,omitempty" validate:"optional"`
+	// Upgrade even if commands are still running after the drain timeout
+	Force bool `json:"force,omitempty" validate:"optional"`
+} // @name UpgradeRequest
+
+type UpgradeStatus struct {
+	// idle, draining or failed
+	State string `json:"state" validate:"required"`
+	// Version of the running daemon
+	CurrentVersion string `json:"currentVersion" validate:"required"`
+	// Version being upgraded to
+	TargetVersion string `json:"targetVersion,omitempty" validate:"optional"`
+	// Operations the upgrade is waiting for
+	Pending map[string]int `json:"pending,omitempty" validate:"optional"`
+	Error   string         `json:"error,omitempty" validate:"optional"`
+} // @name UpgradeStatus
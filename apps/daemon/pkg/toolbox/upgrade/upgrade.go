@@ -0,0 +1,102 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package upgrade
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/daemon/internal"
+	"github.com/daytonaio/daemon/pkg/upgrade"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultDrainTimeout = 60 * time.Second
+
+type UpgradeController struct {
+	upgrader *upgrade.Upgrader
+}
+
+func NewUpgradeController(upgrader *upgrade.Upgrader) *UpgradeController {
+	return &UpgradeController{
+		upgrader: upgrader,
+	}
+}
+
+// Upgrade godoc
+//
+//	@Summary		Upgrade the daemon
+//	@Description	Replace the running daemon with a new binary without restarting the sandbox. The upgrade waits for
+//	@Description	running session commands and PTY sessions to finish, then executes the new binary in place of the
+//	@Description	daemon. Persistent sessions and the entrypoint keep running. Poll /version for the new version.
+//	@Tags			upgrade
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		UpgradeRequest	true	"Upgrade request"
+//	@Success		202		{object}	UpgradeStatus
+//	@Router			/upgrade [post]
+//
+//	@id				Upgrade
+func (u *UpgradeController) Upgrade(c *gin.Context) {
+	var req UpgradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	if req.Path == "" {
+		c.Error(common_errors.NewBadRequestError(errors.New("path is required")))
+		return
+	}
+
+	drainTimeout := defaultDrainTimeout
+	if req.DrainTimeoutSeconds > 0 {
+		drainTimeout = time.Duration(req.DrainTimeoutSeconds) * time.Second
+	}
+
+	err := u.upgrader.Start(upgrade.Request{
+		Path:         req.Path,
+		Sha256:       req.Sha256,
+		DrainTimeout: drainTimeout,
+		Force:        req.Force,
+	}, req.Version)
+	if errors.Is(err, upgrade.ErrUpgradeInProgress) {
+		c.Error(common_errors.NewConflictError(err))
+		return
+	}
+	if err != nil {
+		c.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, u.status())
+}
+
+// GetUpgradeStatus godoc
+//
+//	@Summary		Get the upgrade status
+//	@Description	Get the status of the last daemon upgrade
+//	@Tags			upgrade
+//	@Produce		json
+//	@Success		200	{object}	UpgradeStatus
+//	@Router			/upgrade [get]
+//
+//	@id				GetUpgradeStatus
+func (u *UpgradeController) GetUpgradeStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, u.status())
+}
+
+func (u *UpgradeController) status() UpgradeStatus {
+	status := u.upgrader.Status()
+
+	return UpgradeStatus{
+		State:          string(status.State),
+		CurrentVersion: internal.Version,
+		TargetVersion:  status.Version,
+		Pending:        status.Pending,
+		Error:          status.Error,
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package watchdog
+
+import "time"
+
+type MemoryStatus struct {
+	// Memory limit of the sandbox in bytes
+	Limit uint64 `json:"limit" validate:"required"`
+	// Memory used in the sandbox in bytes, without reclaimable page cache
+	Usage uint64 `json:"usage" validate:"required"`
+	// Usage in percent of the limit at which the watchdog kills the largest process tree
+	ThresholdPercent int       `json:"thresholdPercent" validate:"required"`
+	CheckedAt        time.Time `json:"checkedAt" validate:"required"`
+} // @name MemoryStatus
+
+type WatchdogKillEvent struct {
+	Time time.Time `json:"time" validate:"required"`
+	// Pid of the root of the killed process tree
+	Pid     int32  `json:"pid" validate:"required"`
+	Command string `json:"command" validate:"required"`
+	// Resident memory of the process tree in bytes
+	Rss       uint64 `json:"rss" validate:"required"`
+	Processes int    `json:"processes" validate:"required"`
+	// Sandbox memory usage and limit in bytes when the tree was killed
+	Usage  uint64 `json:"usage" validate:"required"`
+	Limit  uint64 `json:"limit" validate:"required"`
+	Reason string `json:"reason" validate:"required"`
+} // @name WatchdogKillEvent
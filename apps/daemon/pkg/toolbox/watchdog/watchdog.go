@@ -0,0 +1,72 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package watchdog
+
+import (
+	"net/http"
+
+	"github.com/daytonaio/daemon/pkg/watchdog"
+	"github.com/gin-gonic/gin"
+)
+
+type WatchdogController struct {
+	watchdog *watchdog.Watchdog
+}
+
+func NewWatchdogController(w *watchdog.Watchdog) *WatchdogController {
+	return &WatchdogController{
+		watchdog: w,
+	}
+}
+
+// GetMemoryStatus godoc
+//
+//	@Summary		Get memory status
+//	@Description	Get the memory usage and limit of the sandbox as last checked by the memory watchdog
+//	@Tags			watchdog
+//	@Produce		json
+//	@Success		200	{object}	MemoryStatus
+//	@Router			/watchdog/memory [get]
+//
+//	@id				GetMemoryStatus
+func (w *WatchdogController) GetMemoryStatus(c *gin.Context) {
+	status := w.watchdog.Status()
+
+	c.JSON(http.StatusOK, MemoryStatus{
+		Limit:            status.Limit,
+		Usage:            status.Usage,
+		ThresholdPercent: status.ThresholdPercent,
+		CheckedAt:        status.CheckedAt,
+	})
+}
+
+// ListWatchdogEvents godoc
+//
+//	@Summary		List memory watchdog events
+//	@Description	List the process trees the memory watchdog killed because the sandbox was running out of memory
+//	@Tags			watchdog
+//	@Produce		json
+//	@Success		200	{array}	WatchdogKillEvent
+//	@Router			/watchdog/events [get]
+//
+//	@id				ListWatchdogEvents
+func (w *WatchdogController) ListWatchdogEvents(c *gin.Context) {
+	events := w.watchdog.Events()
+
+	result := make([]WatchdogKillEvent, 0, len(events))
+	for _, event := range events {
+		result = append(result, WatchdogKillEvent{
+			Time:      event.Time,
+			Pid:       event.Pid,
+			Command:   event.Command,
+			Rss:       event.Rss,
+			Processes: event.Processes,
+			Usage:     event.Usage,
+			Limit:     event.Limit,
+			Reason:    event.Reason,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,651 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package toolchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Directories the detection doesn't descend into, dependencies and build output
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+	"out":          true,
+	"venv":         true,
+	"__pycache__":  true,
+	"bin":          true,
+	"obj":          true,
+}
+
+type Commands struct {
+	Install string `json:"install,omitempty"`
+	Build   string `json:"build,omitempty"`
+	Run     string `json:"run,omitempty"`
+	Test    string `json:"test,omitempty"`
+}
+
+// Project is a directory of the workspace with the manifest of a toolchain
+type Project struct {
+	// Relative to the root of the workspace
+	Path           string   `json:"path"`
+	Language       string   `json:"language"`
+	PackageManager string   `json:"packageManager,omitempty"`
+	Frameworks     []string `json:"frameworks,omitempty"`
+	// Manifests and lockfiles the project was detected from
+	Files []string `json:"files"`
+	// Recommended commands, run in the directory of the project
+	Commands Commands `json:"commands"`
+}
+
+type Report struct {
+	Root            string    `json:"root"`
+	Languages       []string  `json:"languages"`
+	PackageManagers []string  `json:"packageManagers"`
+	Projects        []Project `json:"projects"`
+}
+
+// detector returns the project of a directory for one toolchain, if the directory has its manifest
+type detector func(dir *directory) *Project
+
+var detectors = []detector{
+	detectNode,
+	detectDeno,
+	detectPython,
+	detectGo,
+	detectRust,
+	detectJava,
+	detectRuby,
+	detectPhp,
+	detectDotnet,
+	detectElixir,
+}
+
+// Detect inspects the manifests and lockfiles of a workspace and of its directories up to maxDepth
+// levels below it. Projects are reported in the order of their path, the root first.
+func Detect(root string, maxDepth int) (*Report, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	report := &Report{
+		Root:            root,
+		Languages:       []string{},
+		PackageManagers: []string{},
+		Projects:        []Project{},
+	}
+
+	err = filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			// Unreadable directories are skipped
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if path != root {
+			if strings.HasPrefix(entry.Name(), ".") || skippedDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			if strings.Count(filepath.ToSlash(rel), "/") >= maxDepth {
+				return filepath.SkipDir
+			}
+		}
+
+		dir, err := readDirectory(path)
+		if err != nil {
+			return filepath.SkipDir
+		}
+
+		for _, detect := range detectors {
+			if project := detect(dir); project != nil {
+				project.Path = filepath.ToSlash(rel)
+				report.Projects = append(report.Projects, *project)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range report.Projects {
+		if !slices.Contains(report.Languages, project.Language) {
+			report.Languages = append(report.Languages, project.Language)
+		}
+		if project.PackageManager != "" && !slices.Contains(report.PackageManagers, project.PackageManager) {
+			report.PackageManagers = append(report.PackageManagers, project.PackageManager)
+		}
+	}
+
+	return report, nil
+}
+
+type directory struct {
+	path  string
+	files map[string]bool
+}
+
+func readDirectory(path string) (*directory, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := &directory{
+		path:  path,
+		files: make(map[string]bool, len(entries)),
+	}
+	for _, entry := range entries {
+		dir.files[entry.Name()] = true
+	}
+
+	return dir, nil
+}
+
+func (d *directory) has(name string) bool {
+	return d.files[name]
+}
+
+// first returns the first of the names the directory has
+func (d *directory) first(names ...string) string {
+	for _, name := range names {
+		if d.files[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// withSuffix returns the first file of the directory whose name ends with suffix
+func (d *directory) withSuffix(suffix string) string {
+	names := make([]string, 0, len(d.files))
+	for name := range d.files {
+		if strings.HasSuffix(name, suffix) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	slices.Sort(names)
+	return names[0]
+}
+
+// read returns the content of a file of the directory, empty if it can't be read. Manifests larger
+// than 1 MiB are ignored.
+func (d *directory) read(name string) string {
+	path := filepath.Join(d.path, name)
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > 1024*1024 {
+		return ""
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+// present returns the names the directory has, in the given order
+func (d *directory) present(names ...string) []string {
+	present := []string{}
+	for _, name := range names {
+		if d.files[name] {
+			present = append(present, name)
+		}
+	}
+	return present
+}
+
+var errNoManifest = errors.New("no manifest")
+
+type packageJson struct {
+	PackageManager  string            `json:"packageManager"`
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+func readPackageJson(dir *directory) (*packageJson, error) {
+	content := dir.read("package.json")
+	if content == "" {
+		return nil, errNoManifest
+	}
+	var manifest packageJson
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// Frameworks detected from the dependencies of a package.json, the more specific ones first
+var nodeFrameworks = []struct {
+	dependency string
+	name       string
+}{
+	{"next", "nextjs"},
+	{"nuxt", "nuxt"},
+	{"@remix-run/react", "remix"},
+	{"@sveltejs/kit", "sveltekit"},
+	{"astro", "astro"},
+	{"@angular/core", "angular"},
+	{"@nestjs/core", "nestjs"},
+	{"vite", "vite"},
+	{"react", "react"},
+	{"vue", "vue"},
+	{"svelte", "svelte"},
+	{"express", "express"},
+	{"fastify", "fastify"},
+}
+
+func detectNode(dir *directory) *Project {
+	if !dir.has("package.json") {
+		return nil
+	}
+	manifest, err := readPackageJson(dir)
+	if err != nil {
+		manifest = &packageJson{}
+	}
+
+	project := &Project{
+		Language: "javascript",
+		Files:    dir.present("package.json", "pnpm-lock.yaml", "yarn.lock", "bun.lock", "bun.lockb", "package-lock.json", "tsconfig.json"),
+	}
+	if dir.has("tsconfig.json") || manifest.Dependencies["typescript"] != "" || manifest.DevDependencies["typescript"] != "" {
+		project.Language = "typescript"
+	}
+
+	// The packageManager field of corepack wins over lockfiles
+	packageManager, _, _ := strings.Cut(manifest.PackageManager, "@")
+	if packageManager == "" {
+		switch dir.first("pnpm-lock.yaml", "yarn.lock", "bun.lock", "bun.lockb", "package-lock.json") {
+		case "pnpm-lock.yaml":
+			packageManager = "pnpm"
+		case "yarn.lock":
+			packageManager = "yarn"
+		case "bun.lock", "bun.lockb":
+			packageManager = "bun"
+		default:
+			packageManager = "npm"
+		}
+	}
+	project.PackageManager = packageManager
+
+	for _, framework := range nodeFrameworks {
+		if manifest.Dependencies[framework.dependency] != "" || manifest.DevDependencies[framework.dependency] != "" {
+			project.Frameworks = append(project.Frameworks, framework.name)
+		}
+	}
+
+	project.Commands.Install = packageManager + " install"
+	if packageManager == "npm" && dir.has("package-lock.json") {
+		project.Commands.Install = "npm ci"
+	}
+
+	script := func(name string) string {
+		if manifest.Scripts[name] == "" {
+			return ""
+		}
+		if packageManager == "npm" {
+			return "npm run " + name
+		}
+		return packageManager + " run " + name
+	}
+	project.Commands.Build = script("build")
+	project.Commands.Test = script("test")
+	for _, name := range []string{"dev", "start", "serve"} {
+		if run := script(name); run != "" {
+			project.Commands.Run = run
+			break
+		}
+	}
+
+	return project
+}
+
+func detectDeno(dir *directory) *Project {
+	manifest := dir.first("deno.json", "deno.jsonc")
+	if manifest == "" || dir.has("package.json") {
+		return nil
+	}
+
+	project := &Project{
+		Language:       "typescript",
+		PackageManager: "deno",
+		Files:          dir.present(manifest, "deno.lock"),
+		Commands: Commands{
+			Install: "deno install",
+			Test:    "deno test",
+		},
+	}
+	if content := dir.read(manifest); strings.Contains(content, `"dev"`) {
+		project.Commands.Run = "deno task dev"
+	} else if strings.Contains(content, `"start"`) {
+		project.Commands.Run = "deno task start"
+	} else if entry := dir.first("main.ts", "mod.ts", "server.ts"); entry != "" {
+		project.Commands.Run = "deno run -A " + entry
+	}
+
+	return project
+}
+
+var pythonFrameworks = []struct {
+	dependency string
+	name       string
+}{
+	{"django", "django"},
+	{"fastapi", "fastapi"},
+	{"flask", "flask"},
+	{"streamlit", "streamlit"},
+	{"gradio", "gradio"},
+}
+
+func detectPython(dir *directory) *Project {
+	files := dir.present("pyproject.toml", "uv.lock", "poetry.lock", "pdm.lock", "Pipfile", "Pipfile.lock", "requirements.txt", "setup.py", "environment.yml")
+	if len(files) == 0 {
+		return nil
+	}
+
+	project := &Project{
+		Language: "python",
+		Files:    files,
+	}
+
+	pyproject := dir.read("pyproject.toml")
+	switch {
+	case dir.has("uv.lock") || strings.Contains(pyproject, "[tool.uv"):
+		project.PackageManager = "uv"
+		project.Commands.Install = "uv sync"
+	case dir.has("poetry.lock") || strings.Contains(pyproject, "[tool.poetry"):
+		project.PackageManager = "poetry"
+		project.Commands.Install = "poetry install"
+	case dir.has("pdm.lock") || strings.Contains(pyproject, "[tool.pdm"):
+		project.PackageManager = "pdm"
+		project.Commands.Install = "pdm install"
+	case dir.has("Pipfile"):
+		project.PackageManager = "pipenv"
+		project.Commands.Install = "pipenv install"
+	case dir.has("environment.yml"):
+		project.PackageManager = "conda"
+		project.Commands.Install = "conda env update --file environment.yml"
+	case dir.has("requirements.txt"):
+		project.PackageManager = "pip"
+		project.Commands.Install = "pip install -r requirements.txt"
+	default:
+		project.PackageManager = "pip"
+		project.Commands.Install = "pip install -e ."
+	}
+
+	// Commands run in the environment of the package manager
+	prefix := ""
+	switch project.PackageManager {
+	case "uv", "poetry", "pdm", "pipenv":
+		prefix = project.PackageManager + " run "
+	}
+
+	dependencies := strings.ToLower(pyproject + dir.read("requirements.txt") + dir.read("Pipfile"))
+	for _, framework := range pythonFrameworks {
+		if strings.Contains(dependencies, framework.dependency) {
+			project.Frameworks = append(project.Frameworks, framework.name)
+		}
+	}
+
+	entry := dir.first("main.py", "app.py", "server.py")
+	switch {
+	case dir.has("manage.py"):
+		project.Commands.Run = prefix + "python manage.py runserver"
+	case slices.Contains(project.Frameworks, "streamlit") && entry != "":
+		project.Commands.Run = prefix + "streamlit run " + entry
+	case entry != "":
+		project.Commands.Run = prefix + "python " + entry
+	}
+
+	if dir.has("manage.py") {
+		project.Commands.Test = prefix + "python manage.py test"
+	} else if strings.Contains(dependencies, "pytest") || dir.has("pytest.ini") || dir.has("conftest.py") || dir.has("tests") {
+		project.Commands.Test = prefix + "pytest"
+	}
+
+	return project
+}
+
+func detectGo(dir *directory) *Project {
+	if !dir.has("go.mod") {
+		return nil
+	}
+
+	project := &Project{
+		Language:       "go",
+		PackageManager: "go",
+		Files:          dir.present("go.mod", "go.sum", "go.work"),
+		Commands: Commands{
+			Install: "go mod download",
+			Build:   "go build ./...",
+			Test:    "go test ./...",
+		},
+	}
+	if dir.has("main.go") {
+		project.Commands.Run = "go run ."
+	} else if dir.has("cmd") {
+		if entries, err := os.ReadDir(filepath.Join(dir.path, "cmd")); err == nil && len(entries) == 1 && entries[0].IsDir() {
+			project.Commands.Run = "go run ./cmd/" + entries[0].Name()
+		}
+	}
+
+	return project
+}
+
+func detectRust(dir *directory) *Project {
+	if !dir.has("Cargo.toml") {
+		return nil
+	}
+
+	project := &Project{
+		Language:       "rust",
+		PackageManager: "cargo",
+		Files:          dir.present("Cargo.toml", "Cargo.lock"),
+		Commands: Commands{
+			Install: "cargo fetch",
+			Build:   "cargo build --release",
+			Test:    "cargo test",
+		},
+	}
+	// Workspaces have no single binary to run
+	if !strings.Contains(dir.read("Cargo.toml"), "[workspace]") {
+		project.Commands.Run = "cargo run"
+	}
+
+	return project
+}
+
+func detectJava(dir *directory) *Project {
+	var project *Project
+
+	if dir.has("pom.xml") {
+		mvn := "mvn"
+		if dir.has("mvnw") {
+			mvn = "./mvnw"
+		}
+		project = &Project{
+			PackageManager: "maven",
+			Files:          dir.present("pom.xml", "mvnw"),
+			Commands: Commands{
+				Install: mvn + " -B dependency:resolve",
+				Build:   mvn + " -B package -DskipTests",
+				Test:    mvn + " -B test",
+			},
+		}
+		if strings.Contains(dir.read("pom.xml"), "spring-boot") {
+			project.Frameworks = []string{"spring-boot"}
+			project.Commands.Run = mvn + " spring-boot:run"
+		}
+	} else if manifest := dir.first("build.gradle.kts", "build.gradle"); manifest != "" {
+		gradle := "gradle"
+		if dir.has("gradlew") {
+			gradle = "./gradlew"
+		}
+		project = &Project{
+			PackageManager: "gradle",
+			Files:          dir.present(manifest, "settings.gradle.kts", "settings.gradle", "gradlew"),
+			Commands: Commands{
+				Install: gradle + " dependencies",
+				Build:   gradle + " build -x test",
+				Test:    gradle + " test",
+			},
+		}
+		content := dir.read(manifest)
+		if strings.Contains(content, "org.springframework.boot") {
+			project.Frameworks = []string{"spring-boot"}
+			project.Commands.Run = gradle + " bootRun"
+		} else if strings.Contains(content, "application") {
+			project.Commands.Run = gradle + " run"
+		}
+	} else {
+		return nil
+	}
+
+	project.Language = "java"
+	if _, err := os.Stat(filepath.Join(dir.path, "src", "main", "kotlin")); err == nil {
+		project.Language = "kotlin"
+	}
+
+	return project
+}
+
+func detectRuby(dir *directory) *Project {
+	if !dir.has("Gemfile") {
+		return nil
+	}
+
+	project := &Project{
+		Language:       "ruby",
+		PackageManager: "bundler",
+		Files:          dir.present("Gemfile", "Gemfile.lock"),
+		Commands: Commands{
+			Install: "bundle install",
+		},
+	}
+	if _, err := os.Stat(filepath.Join(dir.path, "bin", "rails")); err == nil {
+		project.Frameworks = []string{"rails"}
+		project.Commands.Run = "bin/rails server"
+		project.Commands.Test = "bin/rails test"
+	} else if dir.has("config.ru") {
+		project.Commands.Run = "bundle exec rackup"
+	}
+	if project.Commands.Test == "" && dir.has("Rakefile") {
+		project.Commands.Test = "bundle exec rake test"
+	}
+
+	return project
+}
+
+func detectPhp(dir *directory) *Project {
+	if !dir.has("composer.json") {
+		return nil
+	}
+
+	project := &Project{
+		Language:       "php",
+		PackageManager: "composer",
+		Files:          dir.present("composer.json", "composer.lock"),
+		Commands: Commands{
+			Install: "composer install",
+		},
+	}
+	if dir.has("artisan") {
+		project.Frameworks = []string{"laravel"}
+		project.Commands.Run = "php artisan serve"
+		project.Commands.Test = "php artisan test"
+	} else if dir.has("phpunit.xml") || dir.has("phpunit.xml.dist") {
+		project.Commands.Test = "vendor/bin/phpunit"
+	}
+
+	return project
+}
+
+func detectDotnet(dir *directory) *Project {
+	manifest := dir.withSuffix(".sln")
+	if manifest == "" {
+		manifest = dir.withSuffix(".csproj")
+	}
+	language := "csharp"
+	if manifest == "" {
+		manifest = dir.withSuffix(".fsproj")
+		language = "fsharp"
+	}
+	if manifest == "" {
+		return nil
+	}
+
+	project := &Project{
+		Language:       language,
+		PackageManager: "nuget",
+		Files:          []string{manifest},
+		Commands: Commands{
+			Install: "dotnet restore",
+			Build:   "dotnet build",
+			Test:    "dotnet test",
+		},
+	}
+	// Solutions have no single project to run
+	if !strings.HasSuffix(manifest, ".sln") {
+		project.Commands.Run = "dotnet run"
+	}
+
+	return project
+}
+
+func detectElixir(dir *directory) *Project {
+	if !dir.has("mix.exs") {
+		return nil
+	}
+
+	project := &Project{
+		Language:       "elixir",
+		PackageManager: "mix",
+		Files:          dir.present("mix.exs", "mix.lock"),
+		Commands: Commands{
+			Install: "mix deps.get",
+			Build:   "mix compile",
+			Run:     "mix run --no-halt",
+			Test:    "mix test",
+		},
+	}
+	if strings.Contains(dir.read("mix.exs"), ":phoenix") {
+		project.Frameworks = []string{"phoenix"}
+		project.Commands.Run = "mix phx.server"
+	}
+
+	return project
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package upgrade
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// UpgradedEnvVar is set for the daemon started by an upgrade, which must not start the
+// entrypoint again as it is still running as a child of the same process
+const UpgradedEnvVar = "DAYTONA_DAEMON_UPGRADED"
+
+const drainCheckInterval = 500 * time.Millisecond
+
+var ErrUpgradeInProgress = errors.New("an upgrade is already in progress")
+
+type State string
+
+const (
+	StateIdle     State = "idle"
+	StateDraining State = "draining"
+	StateFailed   State = "failed"
+)
+
+// Drainer returns the number of operations an upgrade waits for before replacing the daemon
+type Drainer func() int
+
+type Request struct {
+	Path string
+	// Expected SHA-256 checksum of the binary, hex encoded
+	Sha256       string
+	DrainTimeout time.Duration
+	// Replace the daemon even if operations are still running after the drain timeout
+	Force bool
+}
+
+type Status struct {
+	State   State
+	Version string
+	// Operations the upgrade is waiting for, by drainer name
+	Pending map[string]int
+	Error   string
+}
+
+// Upgrader replaces the running daemon with a new binary in place. The new binary is executed
+// in the same process, so the container keeps running and the entrypoint keeps its parent.
+// Sessions that live in the daemon process are drained first; persistent sessions survive.
+type Upgrader struct {
+	drainers   map[string]Drainer
+	beforeExec func()
+
+	mu     sync.Mutex
+	status Status
+}
+
+func NewUpgrader(drainers map[string]Drainer, beforeExec func()) *Upgrader {
+	return &Upgrader{
+		drainers:   drainers,
+		beforeExec: beforeExec,
+		status:     Status{State: StateIdle},
+	}
+}
+
+func (u *Upgrader) Status() Status {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	status := u.status
+	if status.Pending != nil {
+		pending := make(map[string]int, len(status.Pending))
+		for name, count := range status.Pending {
+			pending[name] = count
+		}
+		status.Pending = pending
+	}
+	return status
+}
+
+// Start validates the binary and replaces the daemon with it in the background once drained
+func (u *Upgrader) Start(req Request, version string) error {
+	if err := verifyBinary(req.Path, req.Sha256); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.status.State == StateDraining {
+		return ErrUpgradeInProgress
+	}
+	u.status = Status{State: StateDraining, Version: version}
+
+	go u.run(req)
+
+	return nil
+}
+
+func (u *Upgrader) run(req Request) {
+	deadline := time.Now().Add(req.DrainTimeout)
+
+	for {
+		pending := map[string]int{}
+		for name, drainer := range u.drainers {
+			if count := drainer(); count > 0 {
+				pending[name] = count
+			}
+		}
+
+		u.mu.Lock()
+		u.status.Pending = pending
+		u.mu.Unlock()
+
+		if len(pending) == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			if !req.Force {
+				u.fail(fmt.Errorf("timed out waiting for %s to finish", describePending(pending)))
+				return
+			}
+			log.Warnf("Upgrading the daemon with %s still running", describePending(pending))
+			break
+		}
+
+		time.Sleep(drainCheckInterval)
+	}
+
+	log.Infof("Replacing the daemon with %s", req.Path)
+
+	if u.beforeExec != nil {
+		u.beforeExec()
+	}
+
+	env := append(os.Environ(), UpgradedEnvVar+"=true")
+	args := append([]string{req.Path}, os.Args[1:]...)
+
+	// Only returns on failure
+	err := syscall.Exec(req.Path, args, env)
+	u.fail(fmt.Errorf("failed to execute the new daemon: %w", err))
+}
+
+func (u *Upgrader) fail(err error) {
+	log.Errorf("Daemon upgrade failed: %v", err)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.status.State = StateFailed
+	u.status.Error = err.Error()
+}
+
+func verifyBinary(path, checksum string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("%s is not an executable file", path)
+	}
+
+	if checksum == "" {
+		return nil
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(actual, checksum) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, actual)
+	}
+
+	return nil
+}
+
+func describePending(pending map[string]int) string {
+	parts := make([]string, 0, len(pending))
+	for name, count := range pending {
+		parts = append(parts, fmt.Sprintf("%d %s", count, name))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package watchdog
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// memoryUsage returns the memory usage and limit of the sandbox, from its cgroup if it has a
+// limit and from the host memory otherwise
+func memoryUsage() (uint64, uint64, error) {
+	if usage, limit, err := cgroupV2Memory(); err == nil {
+		return usage, limit, nil
+	}
+
+	if usage, limit, err := cgroupV1Memory(); err == nil {
+		return usage, limit, nil
+	}
+
+	return hostMemory()
+}
+
+func cgroupV2Memory() (uint64, uint64, error) {
+	limit, err := readUint("/sys/fs/cgroup/memory.max")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	usage, err := readUint("/sys/fs/cgroup/memory.current")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Page cache can be reclaimed, so it does not count towards the usage
+	if inactive, err := readStat("/sys/fs/cgroup/memory.stat", "inactive_file"); err == nil && inactive < usage {
+		usage -= inactive
+	}
+
+	return usage, limit, nil
+}
+
+func cgroupV1Memory() (uint64, uint64, error) {
+	dir := "/sys/fs/cgroup/memory"
+
+	limit, err := readUint(filepath.Join(dir, "memory.limit_in_bytes"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Without a limit the value is close to the maximum int64
+	_, hostLimit, err := hostMemory()
+	if err == nil && limit >= hostLimit {
+		return 0, 0, errors.New("no cgroup memory limit")
+	}
+
+	usage, err := readUint(filepath.Join(dir, "memory.usage_in_bytes"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if inactive, err := readStat(filepath.Join(dir, "memory.stat"), "total_inactive_file"); err == nil && inactive < usage {
+		usage -= inactive
+	}
+
+	return usage, limit, nil
+}
+
+func hostMemory() (uint64, uint64, error) {
+	total, err := readStat("/proc/meminfo", "MemTotal:")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	available, err := readStat("/proc/meminfo", "MemAvailable:")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// meminfo values are in kB
+	return (total - available) * 1024, total * 1024, nil
+}
+
+// readUint reads a file with a single number, "max" is reported as an error
+func readUint(path string) (uint64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	value := strings.TrimSpace(string(content))
+	if value == "max" {
+		return 0, errors.New("no limit")
+	}
+
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// readStat reads the value of a key from a file with "key value" lines
+func readStat(path, key string) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == key {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+
+	return 0, errors.New(key + " not found in " + path)
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// The daemon is protected, but not made unkillable
+	daemonOomScoreAdj = -900
+	// Processes started by users are killed by the kernel before anything else
+	userOomScoreAdj = 500
+
+	maxEvents = 100
+	// Time to let the kernel reclaim memory after a kill before acting again
+	killCooldown = 10 * time.Second
+)
+
+type MemoryStatus struct {
+	// Memory limit of the sandbox in bytes
+	Limit uint64 `json:"limit"`
+	// Memory used in the sandbox in bytes
+	Usage            uint64    `json:"usage"`
+	ThresholdPercent int       `json:"thresholdPercent"`
+	CheckedAt        time.Time `json:"checkedAt"`
+}
+
+// KillEvent describes a process tree the watchdog killed to keep the sandbox alive
+type KillEvent struct {
+	Time    time.Time `json:"time"`
+	Pid     int32     `json:"pid"`
+	Command string    `json:"command"`
+	// Resident memory of the process tree in bytes
+	Rss uint64 `json:"rss"`
+	// Number of processes in the tree
+	Processes int    `json:"processes"`
+	Usage     uint64 `json:"usage"`
+	Limit     uint64 `json:"limit"`
+	Reason    string `json:"reason"`
+}
+
+// Watchdog protects the daemon from the OOM killer and kills the process tree using the most
+// memory when the sandbox gets close to its memory limit, before the kernel kills at random. Only
+// the commands users run are killed, not the session shells they run in.
+type Watchdog struct {
+	thresholdPercent int
+	interval         time.Duration
+
+	mu         sync.Mutex
+	status     MemoryStatus
+	events     []KillEvent
+	lastKillAt time.Time
+}
+
+func NewWatchdog(thresholdPercent int, interval time.Duration) *Watchdog {
+	return &Watchdog{
+		thresholdPercent: thresholdPercent,
+		interval:         interval,
+		events:           []KillEvent{},
+	}
+}
+
+func (w *Watchdog) Start(ctx context.Context) {
+	if err := writeOomScoreAdj(int32(os.Getpid()), daemonOomScoreAdj); err != nil {
+		log.Warnf("Failed to lower the OOM score of the daemon: %v", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+func (w *Watchdog) Status() MemoryStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *Watchdog) Events() []KillEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]KillEvent{}, w.events...)
+}
+
+func (w *Watchdog) check() {
+	usage, limit, err := memoryUsage()
+	if err != nil {
+		log.Debugf("Failed to read memory usage: %v", err)
+		return
+	}
+
+	w.mu.Lock()
+	w.status = MemoryStatus{
+		Limit:            limit,
+		Usage:            usage,
+		ThresholdPercent: w.thresholdPercent,
+		CheckedAt:        time.Now(),
+	}
+	coolingDown := time.Since(w.lastKillAt) < killCooldown
+	w.mu.Unlock()
+
+	trees := userProcessTrees()
+	for _, tree := range trees {
+		for _, p := range tree.processes {
+			adjustOomScore(p.Pid)
+		}
+	}
+
+	if limit == 0 || usage*100 < limit*uint64(w.thresholdPercent) || coolingDown || len(trees) == 0 {
+		return
+	}
+
+	sort.Slice(trees, func(i, j int) bool {
+		return trees[i].rss > trees[j].rss
+	})
+	offender := trees[0]
+
+	event := KillEvent{
+		Time:      time.Now(),
+		Pid:       offender.root.Pid,
+		Command:   offender.command,
+		Rss:       offender.rss,
+		Processes: len(offender.processes),
+		Usage:     usage,
+		Limit:     limit,
+		Reason:    fmt.Sprintf("sandbox memory usage reached %d%% of the limit", usage*100/limit),
+	}
+
+	log.Warnf("Memory watchdog: killing process tree %d (%s) using %d bytes: %s", event.Pid, event.Command, event.Rss, event.Reason)
+
+	for _, p := range offender.processes {
+		_ = syscall.Kill(int(p.Pid), syscall.SIGKILL)
+	}
+
+	w.mu.Lock()
+	w.lastKillAt = time.Now()
+	w.events = append(w.events, event)
+	if len(w.events) > maxEvents {
+		w.events = w.events[len(w.events)-maxEvents:]
+	}
+	w.mu.Unlock()
+}
+
+type processTree struct {
+	root      *process.Process
+	command   string
+	processes []*process.Process
+	rss       uint64
+}
+
+// Shells the daemon and tmux run sessions in, which the watchdog never kills
+var shells = []string{"sh", "bash", "zsh", "dash", "ash", "ksh", "fish"}
+
+// userProcessTrees groups the processes started by users by their topmost ancestor below a
+// protected process, so that a tree is a command run in a session and everything it started
+func userProcessTrees() []*processTree {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil
+	}
+
+	parents := map[int32]int32{}
+	names := map[int32]string{}
+	byPid := map[int32]*process.Process{}
+	for _, p := range procs {
+		ppid, err := p.Ppid()
+		if err != nil {
+			continue
+		}
+		parents[p.Pid] = ppid
+		names[p.Pid], _ = p.Name()
+		byPid[p.Pid] = p
+	}
+
+	trees := map[int32]*processTree{}
+	for pid, root := range treeRoots(parents, names, int32(os.Getpid())) {
+		tree, ok := trees[root]
+		if !ok {
+			tree = &processTree{root: byPid[root]}
+			tree.command, _ = tree.root.Cmdline()
+			trees[root] = tree
+		}
+
+		p := byPid[pid]
+		tree.processes = append(tree.processes, p)
+		if mem, err := p.MemoryInfo(); err == nil {
+			tree.rss += mem.RSS
+		}
+	}
+
+	result := make([]*processTree, 0, len(trees))
+	for _, tree := range trees {
+		result = append(result, tree)
+	}
+
+	return result
+}
+
+// treeRoots returns the root of the tree of every process that may be killed. The daemon, init,
+// kernel threads, tmux servers and the session shells the daemon or tmux started are protected:
+// killing them would end every command of a session, or every session at once.
+func treeRoots(parents map[int32]int32, names map[int32]string, self int32) map[int32]int32 {
+	isTmuxServer := func(pid int32) bool {
+		return strings.HasPrefix(names[pid], "tmux")
+	}
+	isProtected := func(pid int32) bool {
+		if pid == self || pid <= 2 || parents[pid] == 2 || isTmuxServer(pid) {
+			return true
+		}
+		parent := parents[pid]
+		return (parent == self || isTmuxServer(parent)) && slices.Contains(shells, names[pid])
+	}
+
+	roots := map[int32]int32{}
+	for pid := range parents {
+		if isProtected(pid) {
+			continue
+		}
+
+		root := pid
+		for {
+			parent, ok := parents[root]
+			if !ok || isProtected(parent) {
+				break
+			}
+			root = parent
+		}
+		roots[pid] = root
+	}
+
+	return roots
+}
+
+// adjustOomScore raises the OOM score of a process that inherited the lowered score of the daemon
+func adjustOomScore(pid int32) {
+	current, err := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score_adj", pid))
+	if err != nil {
+		return
+	}
+
+	score, err := strconv.Atoi(strings.TrimSpace(string(current)))
+	if err != nil || score >= userOomScoreAdj {
+		return
+	}
+
+	_ = writeOomScoreAdj(pid, userOomScoreAdj)
+}
+
+func writeOomScoreAdj(pid int32, score int) error {
+	return os.WriteFile(fmt.Sprintf("/proc/%d/oom_score_adj", pid), []byte(strconv.Itoa(score)), 0644)
+}
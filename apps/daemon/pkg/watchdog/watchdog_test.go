@@ -0,0 +1,67 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package watchdog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeRoots(t *testing.T) {
+	const daemon = 10
+
+	// pid: parent, name
+	processes := map[int32]struct {
+		parent int32
+		name   string
+	}{
+		1:      {0, "sleep"},
+		2:      {0, "kthreadd"},
+		3:      {2, "kworker/0:0"},
+		daemon: {1, "daytona"},
+		// Session shell of the daemon running a command with a child
+		20: {daemon, "bash"},
+		21: {20, "python3"},
+		22: {21, "node"},
+		// A second command of the same session
+		23: {20, "make"},
+		// Command the daemon executes directly
+		30: {daemon, "npm"},
+		31: {30, "node"},
+		// tmux server with two persistent sessions
+		40: {1, "tmux: server"},
+		41: {40, "bash"},
+		42: {41, "java"},
+		43: {40, "zsh"},
+		44: {43, "cargo"},
+		45: {44, "rustc"},
+		// Process users daemonized
+		50: {1, "redis-server"},
+		// Shell a command started is killed with the command
+		60: {21, "sh"},
+	}
+
+	parents := map[int32]int32{}
+	names := map[int32]string{}
+	for pid, p := range processes {
+		parents[pid] = p.parent
+		names[pid] = p.name
+	}
+
+	expected := map[int32]int32{
+		21: 21,
+		22: 21,
+		60: 21,
+		23: 23,
+		30: 30,
+		31: 30,
+		42: 42,
+		44: 44,
+		45: 44,
+		50: 50,
+	}
+
+	assert.Equal(t, expected, treeRoots(parents, names, daemon))
+}
@@ -4,11 +4,15 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -17,56 +21,205 @@ import (
 )
 
 type Config struct {
-	DaytonaApiUrl                      string        `envconfig:"DAYTONA_API_URL"`
-	ApiToken                           string        `envconfig:"DAYTONA_RUNNER_TOKEN"`
-	ApiPort                            int           `envconfig:"API_PORT"`
-	TLSCertFile                        string        `envconfig:"TLS_CERT_FILE"`
-	TLSKeyFile                         string        `envconfig:"TLS_KEY_FILE"`
-	EnableTLS                          bool          `envconfig:"ENABLE_TLS"`
-	CacheRetentionDays                 int           `envconfig:"CACHE_RETENTION_DAYS"`
-	Environment                        string        `envconfig:"ENVIRONMENT"`
-	ContainerRuntime                   string        `envconfig:"CONTAINER_RUNTIME"`
-	ContainerNetwork                   string        `envconfig:"CONTAINER_NETWORK"`
-	LogFilePath                        string        `envconfig:"LOG_FILE_PATH"`
-	AWSRegion                          string        `envconfig:"AWS_REGION"`
-	AWSEndpointUrl                     string        `envconfig:"AWS_ENDPOINT_URL"`
-	AWSAccessKeyId                     string        `envconfig:"AWS_ACCESS_KEY_ID"`
-	AWSSecretAccessKey                 string        `envconfig:"AWS_SECRET_ACCESS_KEY"`
-	AWSDefaultBucket                   string        `envconfig:"AWS_DEFAULT_BUCKET"`
-	ResourceLimitsDisabled             bool          `envconfig:"RESOURCE_LIMITS_DISABLED"`
-	DaemonStartTimeoutSec              int           `envconfig:"DAEMON_START_TIMEOUT_SEC"`
-	SandboxStartTimeoutSec             int           `envconfig:"SANDBOX_START_TIMEOUT_SEC"`
-	UseSnapshotEntrypoint              bool          `envconfig:"USE_SNAPSHOT_ENTRYPOINT"`
-	Domain                             string        `envconfig:"RUNNER_DOMAIN" validate:"omitempty,hostname|ip"`
-	VolumeCleanupIntervalSec           int           `envconfig:"VOLUME_CLEANUP_INTERVAL_SEC" default:"30" validate:"min=10"`
-	VolumeCleanupDryRun                bool          `envconfig:"VOLUME_CLEANUP_DRY_RUN" default:"true"`
-	PollTimeout                        time.Duration `envconfig:"POLL_TIMEOUT" default:"30s"`
-	PollLimit                          int           `envconfig:"POLL_LIMIT" default:"10" validate:"min=1,max=100"`
-	CollectorWindowSize                int           `envconfig:"COLLECTOR_WINDOW_SIZE" default:"60" validate:"min=1"`
-	CPUUsageSnapshotInterval           time.Duration `envconfig:"CPU_USAGE_SNAPSHOT_INTERVAL" default:"5s" validate:"min=1s"`
-	AllocatedResourcesSnapshotInterval time.Duration `envconfig:"ALLOCATED_RESOURCES_SNAPSHOT_INTERVAL" default:"5s" validate:"min=1s"`
-	HealthcheckInterval                time.Duration `envconfig:"HEALTHCHECK_INTERVAL" default:"30s" validate:"min=10s"`
-	HealthcheckTimeout                 time.Duration `envconfig:"HEALTHCHECK_TIMEOUT" default:"10s"`
-	BackupTimeoutMin                   int           `envconfig:"BACKUP_TIMEOUT_MIN" default:"60" validate:"min=1"`
-	ApiVersion                         int           `envconfig:"API_VERSION" default:"2"`
+	DaytonaApiUrl                      string            `envconfig:"DAYTONA_API_URL"`
+	ApiToken                           string            `envconfig:"DAYTONA_RUNNER_TOKEN"`
+	ApiPort                            int               `envconfig:"API_PORT"`
+	TLSCertFile                        string            `envconfig:"TLS_CERT_FILE"`
+	TLSKeyFile                         string            `envconfig:"TLS_KEY_FILE"`
+	EnableTLS                          bool              `envconfig:"ENABLE_TLS"`
+	CacheRetentionDays                 int               `envconfig:"CACHE_RETENTION_DAYS"`
+	Environment                        string            `envconfig:"ENVIRONMENT"`
+	ContainerRuntime                   string            `envconfig:"CONTAINER_RUNTIME"`
+	ContainerNetwork                   string            `envconfig:"CONTAINER_NETWORK"`
+	LogFilePath                        string            `envconfig:"LOG_FILE_PATH"`
+	AWSRegion                          string            `envconfig:"AWS_REGION"`
+	AWSEndpointUrl                     string            `envconfig:"AWS_ENDPOINT_URL"`
+	AWSAccessKeyId                     string            `envconfig:"AWS_ACCESS_KEY_ID"`
+	AWSSecretAccessKey                 string            `envconfig:"AWS_SECRET_ACCESS_KEY"`
+	AWSDefaultBucket                   string            `envconfig:"AWS_DEFAULT_BUCKET"`
+	ResourceLimitsDisabled             bool              `envconfig:"RESOURCE_LIMITS_DISABLED"`
+	DaemonStartTimeoutSec              int               `envconfig:"DAEMON_START_TIMEOUT_SEC"`
+	SandboxStartTimeoutSec             int               `envconfig:"SANDBOX_START_TIMEOUT_SEC"`
+	UseSnapshotEntrypoint              bool              `envconfig:"USE_SNAPSHOT_ENTRYPOINT"`
+	DaemonAutoUpgrade                  bool              `envconfig:"DAEMON_AUTO_UPGRADE"`
+	DaemonSocketsDir                   string            `envconfig:"DAEMON_SOCKETS_DIR"`
+	Domain                             string            `envconfig:"RUNNER_DOMAIN" validate:"omitempty,hostname|ip"`
+	VolumeCleanupIntervalSec           int               `envconfig:"VOLUME_CLEANUP_INTERVAL_SEC" default:"30" validate:"min=10"`
+	VolumeCleanupDryRun                bool              `envconfig:"VOLUME_CLEANUP_DRY_RUN" default:"true"`
+	VolumeCleanupGracePeriod           time.Duration     `envconfig:"VOLUME_CLEANUP_GRACE_PERIOD" default:"10m" validate:"min=0"`
+	VolumeQuotasEnabled                bool              `envconfig:"VOLUME_QUOTAS_ENABLED" default:"true"`
+	BucketMountsEnabled                bool              `envconfig:"BUCKET_MOUNTS_ENABLED"`
+	BucketMountDir                     string            `envconfig:"BUCKET_MOUNT_DIR" default:"/mnt/daytona-buckets"`
+	BucketMountStateDir                string            `envconfig:"BUCKET_MOUNT_STATE_DIR" default:"/var/lib/daytona/bucket-mounts"`
+	BucketMountS3Driver                string            `envconfig:"BUCKET_MOUNT_S3_DRIVER" default:"mount-s3" validate:"oneof=mount-s3 s3fs goofys"`
+	BucketMountHealthCheckInterval     time.Duration     `envconfig:"BUCKET_MOUNT_HEALTH_CHECK_INTERVAL" default:"30s" validate:"min=5s"`
+	PollTimeout                        time.Duration     `envconfig:"POLL_TIMEOUT" default:"30s"`
+	PollLimit                          int               `envconfig:"POLL_LIMIT" default:"10" validate:"min=1,max=100"`
+	CollectorWindowSize                int               `envconfig:"COLLECTOR_WINDOW_SIZE" default:"60" validate:"min=1"`
+	CPUUsageSnapshotInterval           time.Duration     `envconfig:"CPU_USAGE_SNAPSHOT_INTERVAL" default:"5s" validate:"min=1s"`
+	AllocatedResourcesSnapshotInterval time.Duration     `envconfig:"ALLOCATED_RESOURCES_SNAPSHOT_INTERVAL" default:"5s" validate:"min=1s"`
+	HealthcheckInterval                time.Duration     `envconfig:"HEALTHCHECK_INTERVAL" default:"30s" validate:"min=10s"`
+	HealthcheckTimeout                 time.Duration     `envconfig:"HEALTHCHECK_TIMEOUT" default:"10s"`
+	BackupTimeoutMin                   int               `envconfig:"BACKUP_TIMEOUT_MIN" default:"60" validate:"min=1"`
+	ApiVersion                         int               `envconfig:"API_VERSION" default:"2" validate:"oneof=1 2"`
+	ApiVersions                        []int             `envconfig:"API_VERSIONS" validate:"dive,oneof=1 2"`
+	ApiDeprecatedVersions              []int             `envconfig:"API_DEPRECATED_VERSIONS" validate:"dive,oneof=1 2"`
+	ApiSunset                          string            `envconfig:"API_SUNSET" validate:"omitempty,datetime=2006-01-02"`
+	AdmissionControlEnabled            bool              `envconfig:"ADMISSION_CONTROL_ENABLED" default:"true"`
+	CPUOvercommitRatio                 float32           `envconfig:"CPU_OVERCOMMIT_RATIO" default:"4" validate:"min=0"`
+	MemoryOvercommitRatio              float32           `envconfig:"MEMORY_OVERCOMMIT_RATIO" default:"1.5" validate:"min=0"`
+	AdmissionCPUUsageThreshold         float32           `envconfig:"ADMISSION_CPU_USAGE_THRESHOLD" default:"90" validate:"min=0,max=100"`
+	AdmissionMemoryUsageThreshold      float32           `envconfig:"ADMISSION_MEMORY_USAGE_THRESHOLD" default:"90" validate:"min=0,max=100"`
+	AdmissionRetryAfter                time.Duration     `envconfig:"ADMISSION_RETRY_AFTER" default:"15s" validate:"min=1s"`
+	StorageUsageSampleInterval         time.Duration     `envconfig:"STORAGE_USAGE_SAMPLE_INTERVAL" default:"5m" validate:"min=30s"`
+	StoragePressureThresholds          []int             `envconfig:"STORAGE_PRESSURE_THRESHOLDS" default:"80,95" validate:"dive,min=1,max=100"`
+	StoragePressureAutoRecover         bool              `envconfig:"STORAGE_PRESSURE_AUTO_RECOVER"`
+	StorageRecoveryImageGC             bool              `envconfig:"STORAGE_RECOVERY_IMAGE_GC"`
+	CompressionAlgorithm               string            `envconfig:"COMPRESSION_ALGORITHM" default:"zstd" validate:"oneof=none gzip zstd"`
+	CompressionLevel                   int               `envconfig:"COMPRESSION_LEVEL" default:"0" validate:"min=0,max=22"`
+	CompressionWorkers                 int               `envconfig:"COMPRESSION_WORKERS" default:"2" validate:"min=1"`
+	CopyWorkers                        int               `envconfig:"COPY_WORKERS" default:"4" validate:"min=1,max=32"`
+	CopyBandwidthLimitKBps             int               `envconfig:"COPY_BANDWIDTH_LIMIT_KBPS" default:"0" validate:"min=0"`
+	CopyStallTimeout                   time.Duration     `envconfig:"COPY_STALL_TIMEOUT" default:"5m" validate:"min=30s"`
+	CopyReflink                        bool              `envconfig:"COPY_REFLINK" default:"true"`
+	CopyVerify                         string            `envconfig:"COPY_VERIFY" default:"sampled" validate:"oneof=none sampled full"`
+	InspectCacheTTL                    time.Duration     `envconfig:"INSPECT_CACHE_TTL" default:"5s" validate:"min=0,max=1m"`
+	LazyPullEnabled                    bool              `envconfig:"LAZY_PULL_ENABLED"`
+	LayerCacheEnabled                  bool              `envconfig:"LAYER_CACHE_ENABLED"`
+	LayerCacheListenAddress            string            `envconfig:"LAYER_CACHE_LISTEN_ADDRESS" default:":5050"`
+	LayerCacheDir                      string            `envconfig:"LAYER_CACHE_DIR" default:"/var/lib/daytona/layer-cache"`
+	LayerCacheMaxSizeGB                int               `envconfig:"LAYER_CACHE_MAX_SIZE_GB" default:"100" validate:"min=1"`
+	LayerCachePeers                    []string          `envconfig:"LAYER_CACHE_PEERS" validate:"dive,url"`
+	LayerCachePeerToken                string            `envconfig:"LAYER_CACHE_PEER_TOKEN"`
+	LayerCacheUpstream                 string            `envconfig:"LAYER_CACHE_UPSTREAM" default:"registry-1.docker.io"`
+	PackageCacheEnabled                bool              `envconfig:"PACKAGE_CACHE_ENABLED"`
+	PackageCacheListenAddress          string            `envconfig:"PACKAGE_CACHE_LISTEN_ADDRESS" default:"172.17.0.1:5060" validate:"hostname_port"`
+	PackageCacheDir                    string            `envconfig:"PACKAGE_CACHE_DIR" default:"/var/lib/daytona/package-cache"`
+	PackageCacheMaxSizeGB              int               `envconfig:"PACKAGE_CACHE_MAX_SIZE_GB" default:"50" validate:"min=1"`
+	PackageCacheMetadataTTL            time.Duration     `envconfig:"PACKAGE_CACHE_METADATA_TTL" default:"5m" validate:"min=0"`
+	PackageCacheNpmRegistry            string            `envconfig:"PACKAGE_CACHE_NPM_REGISTRY" default:"https://registry.npmjs.org" validate:"url"`
+	PackageCachePypiIndex              string            `envconfig:"PACKAGE_CACHE_PYPI_INDEX" default:"https://pypi.org" validate:"url"`
+	PackageCachePypiFiles              string            `envconfig:"PACKAGE_CACHE_PYPI_FILES" default:"https://files.pythonhosted.org" validate:"url"`
+	PackageCacheGoProxy                string            `envconfig:"PACKAGE_CACHE_GO_PROXY" default:"https://proxy.golang.org" validate:"url"`
+	DnsForwarderEnabled                bool              `envconfig:"DNS_FORWARDER_ENABLED"`
+	DnsForwarderListenAddress          string            `envconfig:"DNS_FORWARDER_LISTEN_ADDRESS" default:"172.17.0.1:53" validate:"hostname_port"`
+	DnsForwarderUpstream               string            `envconfig:"DNS_FORWARDER_UPSTREAM" validate:"omitempty,hostname_port"`
+	EgressProxyEnabled                 bool              `envconfig:"EGRESS_PROXY_ENABLED"`
+	EgressProxyPort                    int               `envconfig:"EGRESS_PROXY_PORT" default:"3129" validate:"min=1,max=65535"`
+	WireGuardKey                       string            `envconfig:"WIREGUARD_KEY" validate:"omitempty,base64"`
+	OrganizationEgressIps              map[string]string `envconfig:"ORGANIZATION_EGRESS_IPS" validate:"dive,ipv4"`
+	RegistrationToken                  string            `envconfig:"RUNNER_REGISTRATION_TOKEN"`
+	RegionId                           string            `envconfig:"RUNNER_REGION_ID"`
+	RunnerName                         string            `envconfig:"RUNNER_NAME"`
+	CredentialsFile                    string            `envconfig:"RUNNER_CREDENTIALS_FILE" default:"/var/lib/daytona/runner-credentials.json"`
+	DeregisterOnShutdown               bool              `envconfig:"RUNNER_DEREGISTER_ON_SHUTDOWN"`
+	HeartbeatLeaseDuration             time.Duration     `envconfig:"HEARTBEAT_LEASE_DURATION" default:"2m"`
+	LogLevel                           string            `envconfig:"LOG_LEVEL"`
+	SecretsRefreshInterval             time.Duration     `envconfig:"SECRETS_REFRESH_INTERVAL" default:"5m"`
+	EnvEncryptionEnabled               bool              `envconfig:"ENV_ENCRYPTION_ENABLED"`
+	EnvEncryptionKeyFile               string            `envconfig:"ENV_ENCRYPTION_KEY_FILE" default:"/var/lib/daytona/env-encryption-key.pem"`
+	CpuPinningEnabled                  bool              `envconfig:"CPU_PINNING_ENABLED"`
+	CpuPinningReservedCpus             string            `envconfig:"CPU_PINNING_RESERVED_CPUS" default:"0"`
+	KvmEnabled                         bool              `envconfig:"KVM_ENABLED"`
+	TunEnabled                         bool              `envconfig:"TUN_ENABLED"`
+	FirecrackerEnabled                 bool              `envconfig:"FIRECRACKER_ENABLED"`
+	FirecrackerBinaryPath              string            `envconfig:"FIRECRACKER_BINARY_PATH" default:"/usr/local/bin/firecracker"`
+	FirecrackerKernelPath              string            `envconfig:"FIRECRACKER_KERNEL_PATH" default:"/var/lib/daytona/firecracker/vmlinux"`
+	FirecrackerDataDir                 string            `envconfig:"FIRECRACKER_DATA_DIR" default:"/var/lib/daytona/firecracker/vms"`
+	FirecrackerNetworkCidr             string            `envconfig:"FIRECRACKER_NETWORK_CIDR" default:"172.31.0.0/16" validate:"cidrv4"`
+	NestedDockerDindImage              string            `envconfig:"NESTED_DOCKER_DIND_IMAGE"`
+	SysboxRuntime                      string            `envconfig:"SYSBOX_RUNTIME"`
+	AbuseDetectionEnabled              bool              `envconfig:"ABUSE_DETECTION_ENABLED"`
+	AbuseDetectionInterval             time.Duration     `envconfig:"ABUSE_DETECTION_INTERVAL" default:"1m" validate:"min=10s"`
+	AbuseDetectionCpuThreshold         int               `envconfig:"ABUSE_DETECTION_CPU_THRESHOLD" default:"90" validate:"min=1,max=100"`
+	AbuseDetectionCpuWindow            time.Duration     `envconfig:"ABUSE_DETECTION_CPU_WINDOW" default:"30m"`
+	AbuseDetectionPoolDomains          []string          `envconfig:"ABUSE_DETECTION_POOL_DOMAINS" default:"2miners.com,f2pool.com,hashvault.pro,herominers.com,minexmr.com,moneroocean.stream,nanopool.org,nicehash.com,supportxmr.com,unmineable.com"`
+	AbuseDetectionProcessNames         []string          `envconfig:"ABUSE_DETECTION_PROCESS_NAMES" default:"ccminer,cpuminer,ethminer,gminer,lolminer,minerd,nbminer,phoenixminer,srbminer,t-rex,xmr-stak,xmrig"`
+	AbuseDetectionAutoSuspend          bool              `envconfig:"ABUSE_DETECTION_AUTO_SUSPEND"`
+	AbuseDetectionSuspendSignals       int               `envconfig:"ABUSE_DETECTION_SUSPEND_SIGNALS" default:"2" validate:"min=1,max=3"`
+	AuditEnabled                       bool              `envconfig:"AUDIT_ENABLED"`
+	AuditMaxEvents                     int               `envconfig:"AUDIT_MAX_EVENTS" default:"100000" validate:"min=1000"`
+	AuditConnectionPollInterval        time.Duration     `envconfig:"AUDIT_CONNECTION_POLL_INTERVAL" default:"1s" validate:"min=100ms"`
+	AuditExportEnabled                 bool              `envconfig:"AUDIT_EXPORT_ENABLED"`
+	AuditExportInterval                time.Duration     `envconfig:"AUDIT_EXPORT_INTERVAL" default:"5m" validate:"min=10s"`
+	SandboxExpiryCheckInterval         time.Duration     `envconfig:"SANDBOX_EXPIRY_CHECK_INTERVAL" default:"30s" validate:"min=1s"`
+	SandboxExpiryWarning               time.Duration     `envconfig:"SANDBOX_EXPIRY_WARNING" default:"10m"`
+	CoreDumpsEnabled                   bool              `envconfig:"CORE_DUMPS_ENABLED"` // Requires kernel.core_pattern to be set to docker.CorePattern on the host
+	CoreDumpMaxSizeMB                  int64             `envconfig:"CORE_DUMP_MAX_SIZE_MB" default:"1024" validate:"min=1"`
+	CoreDumpMaxCount                   int               `envconfig:"CORE_DUMP_MAX_COUNT" default:"5" validate:"min=1"`
+	CoreDumpRetention                  time.Duration     `envconfig:"CORE_DUMP_RETENTION" default:"168h"`
+	ReaperEnabled                      bool              `envconfig:"REAPER_ENABLED"`
+	ReaperInterval                     time.Duration     `envconfig:"REAPER_INTERVAL" default:"1h" validate:"min=1m"`
+	ReaperKinds                        []string          `envconfig:"REAPER_KINDS" default:"container,sidecar,volume,volume_mount,chain,home_volume" validate:"dive,oneof=container sidecar volume volume_mount network chain image home_volume"`
+	ReaperDryRun                       bool              `envconfig:"REAPER_DRY_RUN" default:"true"`
+	ReaperMinAge                       time.Duration     `envconfig:"REAPER_MIN_AGE" default:"1h"`
+	RecoveryJanitorEnabled             bool              `envconfig:"RECOVERY_JANITOR_ENABLED" default:"true"`
+	RecoveryJanitorInterval            time.Duration     `envconfig:"RECOVERY_JANITOR_INTERVAL" default:"10m" validate:"min=1m"`
+	RecoveryJanitorGracePeriod         time.Duration     `envconfig:"RECOVERY_JANITOR_GRACE_PERIOD" default:"30m" validate:"min=10m"`
+	AdminApiToken                      string            `envconfig:"ADMIN_API_TOKEN"`
+	ProfilingEnabled                   bool              `envconfig:"PROFILING_ENABLED"`
+	WatchdogEnabled                    bool              `envconfig:"WATCHDOG_ENABLED" default:"true"`
+	WatchdogInterval                   time.Duration     `envconfig:"WATCHDOG_INTERVAL" default:"1m" validate:"min=1s"`
+	WatchdogGoroutineGrowth            int               `envconfig:"WATCHDOG_GOROUTINE_GROWTH" default:"1000" validate:"min=0"`
+	WatchdogFdGrowth                   int               `envconfig:"WATCHDOG_FD_GROWTH" default:"500" validate:"min=0"`
+	WatchdogConnectionGrowth           int               `envconfig:"WATCHDOG_CONNECTION_GROWTH" default:"50" validate:"min=0"`
 }
 
 var DEFAULT_API_PORT int = 8080
 
-var config *Config
+// The current config. Once the runner started its services it is no longer changed, reloads and
+// secret rotations publish a changed copy instead, so that readers don't race them.
+var current atomic.Pointer[Config]
+
+// Serializes loading and updating the config
+var updateMutex sync.Mutex
 
 func GetConfig() (*Config, error) {
-	if config != nil {
-		return config, nil
+	if c := current.Load(); c != nil {
+		return c, nil
+	}
+
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	if c := current.Load(); c != nil {
+		return c, nil
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
 	}
 
-	config = &Config{}
+	current.Store(config)
+	return config, nil
+}
+
+// updateConfig publishes a copy of the current config changed by update. Callers must hold
+// updateMutex.
+func updateConfig(update func(c *Config)) {
+	updated := *current.Load()
+	update(&updated)
+	current.Store(&updated)
+}
+
+func loadConfig() (*Config, error) {
+	config := &Config{}
 
 	err := envconfig.Process("", config)
 	if err != nil {
 		return nil, err
 	}
 
+	refs, err := resolveSecrets(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
+
+	secretRefsMutex.Lock()
+	secretRefs = refs
+	secretRefsMutex.Unlock()
+
 	var validate = validator.New()
 	err = validate.Struct(config)
 	if err != nil {
@@ -85,7 +238,8 @@ func GetConfig() (*Config, error) {
 	if config.ApiToken == "" {
 		// For backward compatibility
 		apiToken := os.Getenv("API_TOKEN")
-		if apiToken == "" {
+		// Registered runners get their token from the control plane
+		if apiToken == "" && config.RegistrationToken == "" {
 			return nil, fmt.Errorf("DAYTONA_RUNNER_TOKEN or API_TOKEN is required")
 		}
 		config.ApiToken = apiToken
@@ -95,6 +249,10 @@ func GetConfig() (*Config, error) {
 		config.ApiPort = DEFAULT_API_PORT
 	}
 
+	if config.RegistrationToken != "" && config.RegionId == "" {
+		return nil, fmt.Errorf("RUNNER_REGION_ID is required to register the runner")
+	}
+
 	if config.Domain == "" {
 		ip, err := getOutboundIP()
 		if err != nil {
@@ -103,19 +261,36 @@ func GetConfig() (*Config, error) {
 		config.Domain = ip.String()
 	}
 
+	if config.RunnerName == "" {
+		config.RunnerName = config.Domain
+	}
+
+	if len(config.ApiVersions) == 0 {
+		// For backward compatibility, v2 runners also serve the v1 routes
+		config.ApiVersions = []int{1}
+		if config.ApiVersion == 2 {
+			config.ApiVersions = append(config.ApiVersions, 2)
+		}
+	}
+
 	return config, nil
 }
 
+// HasApiVersion returns whether the runner serves a version of the control API
+func (c *Config) HasApiVersion(version int) bool {
+	return slices.Contains(c.ApiVersions, version)
+}
+
 func GetContainerRuntime() string {
-	return config.ContainerRuntime
+	return current.Load().ContainerRuntime
 }
 
 func GetContainerNetwork() string {
-	return config.ContainerNetwork
+	return current.Load().ContainerNetwork
 }
 
 func GetEnvironment() string {
-	return config.Environment
+	return current.Load().Environment
 }
 
 func GetBuildLogFilePath(snapshotRef string) (string, error) {
@@ -0,0 +1,56 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+const redactedValue = "<redacted>"
+
+// Fields holding credentials, which are never shown
+var secretFields = []string{
+	"ApiToken",
+	"AdminApiToken",
+	"AWSAccessKeyId",
+	"AWSSecretAccessKey",
+	"LayerCachePeerToken",
+	"WireGuardKey",
+	"RegistrationToken",
+}
+
+// Redacted returns the config by environment variable, with the values of the secrets replaced.
+// Fields resolved from a secret store show their secret reference instead.
+func (c *Config) Redacted() map[string]any {
+	secretRefsMutex.Lock()
+	refs := maps.Clone(secretRefs)
+	secretRefsMutex.Unlock()
+
+	redacted := map[string]any{}
+	value := reflect.ValueOf(c).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := field.Tag.Get("envconfig")
+		if name == "" {
+			continue
+		}
+
+		switch {
+		case refs[field.Name] != "":
+			redacted[name] = refs[field.Name]
+		case slices.Contains(secretFields, field.Name):
+			if value.Field(i).String() != "" {
+				redacted[name] = redactedValue
+			} else {
+				redacted[name] = ""
+			}
+		default:
+			redacted[name] = value.Field(i).Interface()
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/joho/godotenv"
+	"github.com/kelseyhightower/envconfig"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Fields that can be changed without restarting the runner. Other fields are only read on startup.
+var hotReloadableFields = []string{
+	"LogLevel",
+	"AdmissionControlEnabled",
+	"CPUOvercommitRatio",
+	"MemoryOvercommitRatio",
+	"AdmissionCPUUsageThreshold",
+	"AdmissionMemoryUsageThreshold",
+	"AdmissionRetryAfter",
+	"StoragePressureThresholds",
+	"StoragePressureAutoRecover",
+	"LayerCachePeers",
+	"LayerCacheUpstream",
+}
+
+// Reload reads the config again from the .env file and the environment. The whole config is
+// validated first and nothing is changed if it is invalid, otherwise the hot-reloadable fields
+// of the current config are updated. It returns the environment variables of the changed fields.
+func Reload() ([]string, error) {
+	if _, err := GetConfig(); err != nil {
+		return nil, err
+	}
+
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	if err := godotenv.Overload(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	reloaded := &Config{}
+	if err := envconfig.Process("", reloaded); err != nil {
+		return nil, err
+	}
+
+	if _, err := resolveSecrets(context.Background(), reloaded); err != nil {
+		return nil, err
+	}
+
+	if err := validator.New().Struct(reloaded); err != nil {
+		return nil, err
+	}
+
+	if reloaded.LogLevel != "" {
+		if _, err := log.ParseLevel(reloaded.LogLevel); err != nil {
+			return nil, err
+		}
+	}
+
+	var changed []string
+	updateConfig(func(c *Config) {
+		currentValue := reflect.ValueOf(c).Elem()
+		reloadedValue := reflect.ValueOf(reloaded).Elem()
+
+		for _, name := range hotReloadableFields {
+			field, _ := currentValue.Type().FieldByName(name)
+			if reflect.DeepEqual(currentValue.FieldByName(name).Interface(), reloadedValue.FieldByName(name).Interface()) {
+				continue
+			}
+
+			currentValue.FieldByName(name).Set(reloadedValue.FieldByName(name))
+			changed = append(changed, field.Tag.Get("envconfig"))
+		}
+	})
+
+	return changed, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Config values starting with this prefix are resolved from a secret store:
+//   - secretref://file/run/secrets/runner-token reads a file
+//   - secretref://vault/secret/data/runner#token reads a key of a Vault secret
+//   - secretref://aws-secretsmanager/runner/token#token reads a secret of AWS Secrets Manager,
+//     or a key of it if the secret is a JSON object
+const secretRefPrefix = "secretref://"
+
+const secretResolveTimeout = 30 * time.Second
+
+type secretProvider interface {
+	resolve(ctx context.Context, ref *url.URL) (string, error)
+}
+
+var secretProviders = map[string]secretProvider{
+	"file":               fileSecretProvider{},
+	"vault":              &vaultSecretProvider{},
+	"aws-secretsmanager": &awsSecretsManagerProvider{},
+}
+
+var (
+	// Secret references of the config fields resolved from them, by field name
+	secretRefs      = map[string]string{}
+	secretRefsMutex sync.Mutex
+)
+
+// resolveSecrets replaces the string fields of the config that reference a secret with the value
+// of the secret. It returns the references by field name.
+func resolveSecrets(ctx context.Context, c *Config) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, secretResolveTimeout)
+	defer cancel()
+
+	refs := map[string]string{}
+
+	value := reflect.ValueOf(c).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() != reflect.String || !strings.HasPrefix(field.String(), secretRefPrefix) {
+			continue
+		}
+
+		name := value.Type().Field(i).Name
+		ref := field.String()
+
+		secret, err := resolveSecret(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", value.Type().Field(i).Tag.Get("envconfig"), err)
+		}
+
+		field.SetString(secret)
+		refs[name] = ref
+	}
+
+	return refs, nil
+}
+
+func resolveSecret(ctx context.Context, ref string) (string, error) {
+	refUrl, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret reference: %w", err)
+	}
+
+	provider, ok := secretProviders[refUrl.Host]
+	if !ok {
+		return "", fmt.Errorf("unknown secret provider %q", refUrl.Host)
+	}
+
+	return provider.resolve(ctx, refUrl)
+}
+
+// RefreshSecrets resolves the secret references of the config again, so rotated secrets are
+// picked up without a restart. It returns the environment variables of the rotated fields.
+func RefreshSecrets(ctx context.Context) ([]string, error) {
+	if _, err := GetConfig(); err != nil {
+		return nil, err
+	}
+
+	secretRefsMutex.Lock()
+	defer secretRefsMutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, secretResolveTimeout)
+	defer cancel()
+
+	// Resolved before the config is locked, secret stores may be slow to respond
+	secrets := make(map[string]string, len(secretRefs))
+	var resolveErr error
+	for name, ref := range secretRefs {
+		secret, err := resolveSecret(ctx, ref)
+		if err != nil {
+			resolveErr = err
+			continue
+		}
+		secrets[name] = secret
+	}
+
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	var rotated []string
+	updateConfig(func(c *Config) {
+		value := reflect.ValueOf(c).Elem()
+		for name, secret := range secrets {
+			field := value.FieldByName(name)
+			if field.String() == secret {
+				continue
+			}
+
+			field.SetString(secret)
+			structField, _ := value.Type().FieldByName(name)
+			rotated = append(rotated, structField.Tag.Get("envconfig"))
+		}
+	})
+
+	return rotated, resolveErr
+}
+
+// WatchSecrets refreshes the secrets of the config periodically until the context is canceled,
+// calling onRotate with the environment variables of the rotated fields
+func WatchSecrets(ctx context.Context, interval time.Duration, onRotate func(rotated []string)) {
+	secretRefsMutex.Lock()
+	hasRefs := len(secretRefs) > 0
+	secretRefsMutex.Unlock()
+
+	if !hasRefs || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rotated, err := RefreshSecrets(ctx)
+			if err != nil {
+				log.Errorf("Failed to refresh config secrets: %v", err)
+			}
+			if len(rotated) > 0 {
+				log.Infof("Config secrets rotated: %v", rotated)
+				onRotate(rotated)
+			}
+		}
+	}
+}
+
+type fileSecretProvider struct{}
+
+func (fileSecretProvider) resolve(ctx context.Context, ref *url.URL) (string, error) {
+	content, err := os.ReadFile(ref.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
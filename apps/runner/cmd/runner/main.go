@@ -5,12 +5,16 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"io"
 	"log/slog"
+	"net"
 	"os"
 	"os/signal"
+	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	golog "log"
@@ -18,17 +22,34 @@ import (
 	"github.com/daytonaio/runner/cmd/runner/config"
 	"github.com/daytonaio/runner/internal/metrics"
 	"github.com/daytonaio/runner/internal/util"
+	"github.com/daytonaio/runner/pkg/admission"
 	"github.com/daytonaio/runner/pkg/api"
+	"github.com/daytonaio/runner/pkg/api/middlewares"
+	"github.com/daytonaio/runner/pkg/audit"
+	"github.com/daytonaio/runner/pkg/bucketmount"
 	"github.com/daytonaio/runner/pkg/cache"
+	"github.com/daytonaio/runner/pkg/common"
 	"github.com/daytonaio/runner/pkg/daemon"
+	"github.com/daytonaio/runner/pkg/dnsforwarder"
 	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/egressproxy"
+	"github.com/daytonaio/runner/pkg/envelope"
+	"github.com/daytonaio/runner/pkg/firecracker"
+	"github.com/daytonaio/runner/pkg/layercache"
 	"github.com/daytonaio/runner/pkg/netrules"
+	"github.com/daytonaio/runner/pkg/packagecache"
 	"github.com/daytonaio/runner/pkg/runner"
 	"github.com/daytonaio/runner/pkg/runner/v2/executor"
 	"github.com/daytonaio/runner/pkg/runner/v2/healthcheck"
 	"github.com/daytonaio/runner/pkg/runner/v2/poller"
+	"github.com/daytonaio/runner/pkg/runner/v2/registration"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
 	"github.com/daytonaio/runner/pkg/services"
 	"github.com/daytonaio/runner/pkg/sshgateway"
+	"github.com/daytonaio/runner/pkg/storage"
+	"github.com/daytonaio/runner/pkg/topology"
+	"github.com/daytonaio/runner/pkg/watchdog"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/client"
 	"github.com/joho/godotenv"
 	"github.com/lmittmann/tint"
@@ -40,6 +61,11 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// Lines of the runner logs kept for the diagnostics bundle
+const recentLogLines = 5000
+
+var logBuffer = util.NewLogBuffer(recentLogLines)
+
 func main() {
 	cfg, err := config.GetConfig()
 	if err != nil {
@@ -47,7 +73,37 @@ func main() {
 		return
 	}
 
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	// Setup structured logger
+	slogLogger := newSLogger()
+
+	var registrationService *registration.Service
+	if cfg.RegistrationToken != "" && cfg.ApiToken == "" {
+		registrationService = registration.NewService(&registration.RegistrationServiceConfig{
+			ApiUrl:            cfg.DaytonaApiUrl,
+			RegistrationToken: cfg.RegistrationToken,
+			RegionId:          cfg.RegionId,
+			Name:              cfg.RunnerName,
+			CredentialsFile:   cfg.CredentialsFile,
+			Logger:            slogLogger,
+		})
+
+		credentials, err := registrationService.Register(context.Background())
+		if err != nil {
+			log.Errorf("Failed to register runner: %v", err)
+			return
+		}
+		// The config is shared, so the API client and server pick up the token of the registered runner
+		cfg.ApiToken = credentials.ApiKey
+	}
+
+	dockerDialContext, err := watchdog.DockerDialContext()
+	if err != nil {
+		log.Errorf("Error creating Docker client: %v", err)
+		return
+	}
+
+	dockerConnections := &watchdog.ConnectionCounter{}
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation(), client.WithDialContext(dockerConnections.Wrap(dockerDialContext)))
 	if err != nil {
 		log.Errorf("Error creating Docker client: %v", err)
 		return
@@ -83,8 +139,113 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if cfg.WatchdogEnabled {
+		runnerWatchdog := watchdog.NewWatchdog(watchdog.Config{
+			Logger:            slogLogger,
+			Interval:          cfg.WatchdogInterval,
+			GoroutineGrowth:   cfg.WatchdogGoroutineGrowth,
+			FdGrowth:          cfg.WatchdogFdGrowth,
+			ConnectionGrowth:  cfg.WatchdogConnectionGrowth,
+			DockerConnections: dockerConnections,
+		})
+		go runnerWatchdog.Start(ctx)
+	}
+
 	statesCache := cache.GetStatesCache(cfg.CacheRetentionDays)
 
+	var dnsForwarderAddress string
+	if cfg.DnsForwarderEnabled {
+		dnsForwarderAddress, _, err = net.SplitHostPort(cfg.DnsForwarderListenAddress)
+		if err != nil {
+			log.Fatalf("Invalid DNS forwarder listen address: %v", err)
+		}
+	}
+
+	var egressProxyPort int
+	if cfg.EgressProxyEnabled {
+		egressProxyPort = cfg.EgressProxyPort
+	}
+
+	var packageCacheUrl string
+	if cfg.PackageCacheEnabled {
+		packageCacheUrl, err = packagecache.Url(cfg.PackageCacheListenAddress)
+		if err != nil {
+			log.Fatalf("Invalid package cache listen address: %v", err)
+		}
+	}
+
+	var wireGuardKey []byte
+	if cfg.WireGuardKey != "" {
+		wireGuardKey, err = base64.StdEncoding.DecodeString(cfg.WireGuardKey)
+		if err != nil || len(wireGuardKey) != 32 {
+			log.Fatal("WIREGUARD_KEY must be a base64 encoded 32 byte key")
+		}
+	}
+
+	var envDecrypter *envelope.Decrypter
+	if cfg.EnvEncryptionEnabled {
+		envDecrypter, err = envelope.LoadOrCreateKey(cfg.EnvEncryptionKeyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var coreDumps *docker.CoreDumpsConfig
+	if cfg.CoreDumpsEnabled {
+		if err := docker.CheckCorePattern(); err != nil {
+			log.Warnf("Core dumps are disabled: %v", err)
+		} else {
+			coreDumps = &docker.CoreDumpsConfig{
+				MaxSize:   cfg.CoreDumpMaxSizeMB * 1024 * 1024,
+				MaxCount:  cfg.CoreDumpMaxCount,
+				Retention: cfg.CoreDumpRetention,
+			}
+		}
+	}
+
+	var cpuAllocator *topology.Allocator
+	if cfg.CpuPinningEnabled {
+		nodes, err := topology.Discover()
+		if err != nil {
+			log.Fatalf("Failed to discover the NUMA topology: %v", err)
+		}
+		reservedCpus, err := topology.ParseCpuList(cfg.CpuPinningReservedCpus)
+		if err != nil {
+			log.Fatalf("Invalid CPU_PINNING_RESERVED_CPUS: %v", err)
+		}
+		cpuAllocator = topology.NewAllocator(nodes, reservedCpus)
+	}
+
+	var microVMs *firecracker.Client
+	if cfg.FirecrackerEnabled {
+		microVMs, err = firecracker.NewClient(firecracker.Config{
+			BinaryPath:      cfg.FirecrackerBinaryPath,
+			KernelPath:      cfg.FirecrackerKernelPath,
+			DataDir:         cfg.FirecrackerDataDir,
+			DaemonPath:      daemonPath,
+			NetworkCidr:     cfg.FirecrackerNetworkCidr,
+			ApiClient:       cli,
+			NetRulesManager: netRulesManager,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var bucketMounts *bucketmount.Service
+	if cfg.BucketMountsEnabled {
+		bucketMounts, err = bucketmount.NewService(bucketmount.Config{
+			MountDir:            cfg.BucketMountDir,
+			StateDir:            cfg.BucketMountStateDir,
+			S3Driver:            bucketmount.Driver(cfg.BucketMountS3Driver),
+			HealthCheckInterval: cfg.BucketMountHealthCheckInterval,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		go bucketMounts.Start(ctx)
+	}
+
 	dockerClient := docker.NewDockerClient(docker.DockerClientConfig{
 		ApiClient:                cli,
 		StatesCache:              statesCache,
@@ -98,18 +259,86 @@ func main() {
 		NetRulesManager:          netRulesManager,
 		ResourceLimitsDisabled:   cfg.ResourceLimitsDisabled,
 		UseSnapshotEntrypoint:    cfg.UseSnapshotEntrypoint,
+		DaemonAutoUpgrade:        cfg.DaemonAutoUpgrade,
+		DaemonSocketsDir:         cfg.DaemonSocketsDir,
 		VolumeCleanupIntervalSec: cfg.VolumeCleanupIntervalSec,
 		VolumeCleanupDryRun:      cfg.VolumeCleanupDryRun,
+		VolumeCleanupGracePeriod: cfg.VolumeCleanupGracePeriod,
 		BackupTimeoutMin:         cfg.BackupTimeoutMin,
+		Compression: docker.CompressionConfig{
+			Algorithm: docker.Compression(cfg.CompressionAlgorithm),
+			Level:     cfg.CompressionLevel,
+			Workers:   cfg.CompressionWorkers,
+		},
+		Copy: common.CopyConfig{
+			Workers:            cfg.CopyWorkers,
+			BandwidthLimitKBps: cfg.CopyBandwidthLimitKBps,
+			StallTimeout:       cfg.CopyStallTimeout,
+			Reflink:            cfg.CopyReflink,
+			Verify:             common.VerifyMode(cfg.CopyVerify),
+		},
+		StorageRecoveryImageGC: cfg.StorageRecoveryImageGC,
+		LazyPullEnabled:        cfg.LazyPullEnabled,
+		DnsForwarderAddress:    dnsForwarderAddress,
+		EgressProxyPort:        egressProxyPort,
+		WireGuardKey:           wireGuardKey,
+		OrganizationEgressIps:  cfg.OrganizationEgressIps,
+		EnvDecrypter:           envDecrypter,
+		CpuAllocator:           cpuAllocator,
+		KvmEnabled:             cfg.KvmEnabled,
+		TunEnabled:             cfg.TunEnabled,
+		MicroVMs:               microVMs,
+		DindImage:              cfg.NestedDockerDindImage,
+		SysboxRuntime:          cfg.SysboxRuntime,
+		Domain:                 cfg.Domain,
+		CoreDumps:              coreDumps,
+		InspectCacheTTL:        cfg.InspectCacheTTL,
+		VolumeQuotasEnabled:    cfg.VolumeQuotasEnabled,
+		BucketMounts:           bucketMounts,
+		PackageCacheUrl:        packageCacheUrl,
 	})
 
+	if err := dockerClient.RestoreCpuPinning(ctx); err != nil {
+		log.Fatalf("Failed to restore the CPUs of pinned sandboxes: %v", err)
+	}
+
 	// Start Docker events monitor
 	monitorOpts := docker.MonitorOptions{
-		OnDestroyEvent: func(ctx context.Context) {
+		OnResync: func(ctx context.Context) {
+			dockerClient.ClearInspectCache()
 			dockerClient.CleanupOrphanedVolumeMounts(ctx)
+			dockerClient.CleanupOrphanedDaemonSockets(ctx)
+			dockerClient.ReleaseOrphanedCpuPinning(ctx)
 		},
 	}
 	monitor := docker.NewDockerMonitor(cli, netRulesManager, monitorOpts)
+	monitor.Subscribe(docker.Subscription{
+		Name:    "inspect-cache",
+		Handler: dockerClient.InvalidateInspectCache,
+	})
+	monitor.Subscribe(docker.Subscription{
+		Name:    "states-cache",
+		Actions: []events.Action{events.ActionDie},
+		Handler: dockerClient.SyncSandboxState,
+		Workers: 4,
+	})
+	if bucketMounts != nil {
+		monitor.Subscribe(docker.Subscription{
+			Name:    "bucket-mounts",
+			Actions: []events.Action{events.ActionDie},
+			Handler: dockerClient.UnmountStoppedSandboxBuckets,
+			Workers: 4,
+		})
+	}
+	monitor.Subscribe(docker.Subscription{
+		Name:    "orphan-cleanup",
+		Actions: []events.Action{events.ActionDestroy},
+		Handler: func(ctx context.Context, _ events.Message) {
+			dockerClient.CleanupOrphanedVolumeMounts(ctx)
+			dockerClient.CleanupOrphanedDaemonSockets(ctx)
+			dockerClient.ReleaseOrphanedCpuPinning(ctx)
+		},
+	})
 	go func() {
 		err = monitor.Start()
 		if err != nil {
@@ -119,14 +348,177 @@ func main() {
 	defer monitor.Stop()
 
 	sandboxService := services.NewSandboxService(statesCache, dockerClient)
+	organizationQuotaService := services.NewOrganizationQuotaService(dockerClient)
+	sandboxLocks := sandboxlock.NewManager()
 
 	// Initialize sandbox state synchronization service
 	sandboxSyncService := services.NewSandboxSyncService(services.SandboxSyncServiceConfig{
 		Docker:   dockerClient,
 		Interval: 10 * time.Second, // Sync every 10 seconds
+		Locks:    sandboxLocks,
 	})
 	sandboxSyncService.StartSyncProcess(ctx)
 
+	storageUsageService := services.NewStorageUsageService(services.StorageUsageServiceConfig{
+		Docker:              dockerClient,
+		Interval:            cfg.StorageUsageSampleInterval,
+		PressureThresholds:  cfg.StoragePressureThresholds,
+		PressureAutoRecover: cfg.StoragePressureAutoRecover,
+		Locks:               sandboxLocks,
+	})
+	storageUsageService.StartSampling(ctx)
+
+	var abuseDetectionService *services.AbuseDetectionService
+	if cfg.AbuseDetectionEnabled {
+		abuseDetectionService = services.NewAbuseDetectionService(services.AbuseDetectionServiceConfig{
+			Docker:         dockerClient,
+			Interval:       cfg.AbuseDetectionInterval,
+			CpuThreshold:   cfg.AbuseDetectionCpuThreshold,
+			CpuWindow:      cfg.AbuseDetectionCpuWindow,
+			PoolDomains:    cfg.AbuseDetectionPoolDomains,
+			ProcessNames:   cfg.AbuseDetectionProcessNames,
+			AutoSuspend:    cfg.AbuseDetectionAutoSuspend,
+			SuspendSignals: cfg.AbuseDetectionSuspendSignals,
+			Locks:          sandboxLocks,
+		})
+		abuseDetectionService.StartDetection(ctx)
+	}
+
+	sandboxExpiryService := services.NewSandboxExpiryService(services.SandboxExpiryServiceConfig{
+		Docker:        dockerClient,
+		Interval:      cfg.SandboxExpiryCheckInterval,
+		WarningBefore: cfg.SandboxExpiryWarning,
+		Locks:         sandboxLocks,
+	})
+	sandboxExpiryService.StartExpiryCheck(ctx)
+
+	reaperService := services.NewOrphanReaperService(services.OrphanReaperServiceConfig{
+		Docker:   dockerClient,
+		Interval: cfg.ReaperInterval,
+		Kinds:    cfg.ReaperKinds,
+		DryRun:   cfg.ReaperDryRun,
+		MinAge:   cfg.ReaperMinAge,
+		Locks:    sandboxLocks,
+	})
+	if cfg.ReaperEnabled {
+		reaperService.StartReaping(ctx)
+	}
+
+	if cfg.RecoveryJanitorEnabled {
+		recoveryJanitorService := services.NewRecoveryJanitorService(services.RecoveryJanitorServiceConfig{
+			Docker:      dockerClient,
+			Interval:    cfg.RecoveryJanitorInterval,
+			GracePeriod: cfg.RecoveryJanitorGracePeriod,
+			Locks:       sandboxLocks,
+		})
+		recoveryJanitorService.StartCleanup(ctx)
+	}
+
+	var auditCollector *audit.Collector
+	if cfg.AuditEnabled {
+		auditConfig := audit.Config{
+			ApiClient:              cli,
+			MaxEvents:              cfg.AuditMaxEvents,
+			ConnectionPollInterval: cfg.AuditConnectionPollInterval,
+			ExportInterval:         cfg.AuditExportInterval,
+		}
+		if cfg.AuditExportEnabled {
+			storageClient, err := storage.GetObjectStorageClient()
+			if err != nil {
+				log.Fatalf("Failed to create the audit export storage client: %v", err)
+			}
+			auditConfig.Exporter = audit.ObjectStorageExporter(storageClient, path.Join("audit", cfg.RunnerName))
+		}
+
+		auditCollector = audit.NewCollector(auditConfig)
+		if err := auditCollector.Start(ctx); err != nil {
+			log.Fatalf("Failed to start the audit collector: %v", err)
+		}
+	}
+
+	if cfg.DnsForwarderEnabled {
+		dnsForwarderConfig := dnsforwarder.Config{
+			ListenAddress:   cfg.DnsForwarderListenAddress,
+			Upstream:        cfg.DnsForwarderUpstream,
+			ApiClient:       cli,
+			NetRulesManager: netRulesManager,
+		}
+		if abuseDetectionService != nil {
+			dnsForwarderConfig.QueryObserver = abuseDetectionService.ObserveDnsQuery
+		}
+
+		dnsForwarder, err := dnsforwarder.NewForwarder(dnsForwarderConfig)
+		if err != nil {
+			log.Fatalf("Failed to create DNS forwarder: %v", err)
+		}
+
+		go func() {
+			if err := dnsForwarder.Start(ctx); err != nil {
+				log.Errorf("DNS forwarder error: %v", err)
+			}
+		}()
+	}
+
+	var egressProxy *egressproxy.Proxy
+	if cfg.EgressProxyEnabled {
+		egressProxy = egressproxy.NewProxy(egressproxy.Config{
+			Port:         cfg.EgressProxyPort,
+			ApiClient:    cli,
+			WireGuardKey: wireGuardKey,
+		})
+
+		go func() {
+			if err := egressProxy.Start(ctx); err != nil {
+				log.Errorf("Egress proxy error: %v", err)
+			}
+		}()
+	}
+
+	var layerCacheService *layercache.Service
+	if cfg.LayerCacheEnabled {
+		layerCacheService, err = layercache.NewService(layercache.Config{
+			ListenAddress: cfg.LayerCacheListenAddress,
+			CacheDir:      cfg.LayerCacheDir,
+			MaxSizeBytes:  int64(cfg.LayerCacheMaxSizeGB) * 1024 * 1024 * 1024,
+			Peers:         cfg.LayerCachePeers,
+			PeerToken:     cfg.LayerCachePeerToken,
+			Upstream:      cfg.LayerCacheUpstream,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create layer cache: %v", err)
+		}
+
+		go func() {
+			if err := layerCacheService.Start(ctx); err != nil {
+				log.Errorf("Layer cache error: %v", err)
+			}
+		}()
+	}
+
+	if cfg.PackageCacheEnabled {
+		packageCacheService, err := packagecache.NewService(packagecache.Config{
+			ListenAddress: cfg.PackageCacheListenAddress,
+			CacheDir:      cfg.PackageCacheDir,
+			MaxSizeBytes:  int64(cfg.PackageCacheMaxSizeGB) * 1024 * 1024 * 1024,
+			MetadataTTL:   cfg.PackageCacheMetadataTTL,
+			Url:           packageCacheUrl,
+			NpmRegistry:   cfg.PackageCacheNpmRegistry,
+			PypiIndex:     cfg.PackageCachePypiIndex,
+			PypiFiles:     cfg.PackageCachePypiFiles,
+			GoProxy:       cfg.PackageCacheGoProxy,
+			Authorize:     dockerClient.IsPackageCacheClient,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create package cache: %v", err)
+		}
+
+		go func() {
+			if err := packageCacheService.Start(ctx); err != nil {
+				log.Errorf("Package cache error: %v", err)
+			}
+		}()
+	}
+
 	// Initialize SSH Gateway if enabled
 	var sshGatewayService *sshgateway.Service
 	if sshgateway.IsSSHGatewayEnabled() {
@@ -142,9 +534,6 @@ func main() {
 		log.Info("Gateway disabled - set SSH_GATEWAY_ENABLE=true to enable")
 	}
 
-	// Setup structured logger
-	slogLogger := newSLogger()
-
 	// Create metrics collector
 	metricsCollector := metrics.NewCollector(metrics.CollectorConfig{
 		Logger:                             slogLogger,
@@ -155,6 +544,19 @@ func main() {
 	})
 	metricsCollector.Start(ctx)
 
+	admissionController := admission.NewAdmissionController(admission.AdmissionControllerConfig{
+		Logger:                slogLogger,
+		Collector:             metricsCollector,
+		Enabled:               cfg.AdmissionControlEnabled,
+		CPUOvercommitRatio:    cfg.CPUOvercommitRatio,
+		MemoryOvercommitRatio: cfg.MemoryOvercommitRatio,
+		CPUUsageThreshold:     cfg.AdmissionCPUUsageThreshold,
+		MemoryUsageThreshold:  cfg.AdmissionMemoryUsageThreshold,
+		RetryAfter:            cfg.AdmissionRetryAfter,
+	})
+
+	jobHistoryService := services.NewJobHistoryService()
+
 	_ = runner.GetInstance(&runner.RunnerInstanceConfig{
 		StatesCache:       statesCache,
 		Docker:            dockerClient,
@@ -162,18 +564,34 @@ func main() {
 		MetricsCollector:  metricsCollector,
 		NetRulesManager:   netRulesManager,
 		SSHGatewayService: sshGatewayService,
+		Admission:         admissionController,
+		OrganizationQuota: organizationQuotaService,
+		StorageUsage:      storageUsageService,
+		AbuseDetection:    abuseDetectionService,
+		Audit:             auditCollector,
+		EgressProxy:       egressProxy,
+		LayerCache:        layerCacheService,
+		Reaper:            reaperService,
+		Jobs:              jobHistoryService,
+		Logs:              logBuffer,
+		Locks:             sandboxLocks,
 	})
 
-	if cfg.ApiVersion == 2 {
+	// Polling for jobs is the v2 control API, the REST routes of all enabled versions are served either way
+	if cfg.HasApiVersion(2) {
 		healthcheckService, err := healthcheck.NewService(&healthcheck.HealthcheckServiceConfig{
-			Interval:   cfg.HealthcheckInterval,
-			Timeout:    cfg.HealthcheckTimeout,
-			Collector:  metricsCollector,
-			Logger:     slogLogger,
-			Domain:     cfg.Domain,
-			ApiPort:    cfg.ApiPort,
-			ProxyPort:  cfg.ApiPort,
-			TlsEnabled: cfg.EnableTLS,
+			Interval:      cfg.HealthcheckInterval,
+			Timeout:       cfg.HealthcheckTimeout,
+			Collector:     metricsCollector,
+			Logger:        slogLogger,
+			Domain:        cfg.Domain,
+			ApiPort:       cfg.ApiPort,
+			ProxyPort:     cfg.ApiPort,
+			TlsEnabled:    cfg.EnableTLS,
+			LeaseDuration: cfg.HeartbeatLeaseDuration,
+			Checks: []healthcheck.Check{
+				{Name: "docker events monitor", Healthy: monitor.Healthy},
+			},
 		})
 		if err != nil {
 			log.Fatalf("Failed to create healthcheck service: %v", err)
@@ -185,9 +603,13 @@ func main() {
 		}()
 
 		executorService, err := executor.NewExecutor(&executor.ExecutorConfig{
-			Logger:    slogLogger,
-			Docker:    dockerClient,
-			Collector: metricsCollector,
+			Logger:            slogLogger,
+			Docker:            dockerClient,
+			Collector:         metricsCollector,
+			Admission:         admissionController,
+			OrganizationQuota: organizationQuotaService,
+			Jobs:              jobHistoryService,
+			Locks:             sandboxLocks,
 		})
 		if err != nil {
 			log.Fatalf("Failed to create executor service: %v", err)
@@ -198,6 +620,7 @@ func main() {
 			PollLimit:   cfg.PollLimit,
 			Logger:      slogLogger,
 			Executor:    executorService,
+			Healthcheck: healthcheckService,
 		})
 		if err != nil {
 			log.Fatalf("Failed to create poller service: %v", err)
@@ -212,14 +635,47 @@ func main() {
 		}()
 	}
 
+	// The format is validated with the config
+	apiSunset, _ := time.Parse(time.DateOnly, cfg.ApiSunset)
+
 	apiServer := api.NewApiServer(api.ApiServerConfig{
-		ApiPort:     cfg.ApiPort,
-		ApiToken:    cfg.ApiToken,
-		TLSCertFile: cfg.TLSCertFile,
-		TLSKeyFile:  cfg.TLSKeyFile,
-		EnableTLS:   cfg.EnableTLS,
+		ApiPort:          cfg.ApiPort,
+		ApiToken:         cfg.ApiToken,
+		AdminApiToken:    cfg.AdminApiToken,
+		TLSCertFile:      cfg.TLSCertFile,
+		TLSKeyFile:       cfg.TLSKeyFile,
+		EnableTLS:        cfg.EnableTLS,
+		ProfilingEnabled: cfg.ProfilingEnabled,
+		ApiVersions: middlewares.ApiVersions{
+			Enabled:    cfg.ApiVersions,
+			Deprecated: cfg.ApiDeprecatedVersions,
+			Sunset:     apiSunset,
+		},
+	})
+
+	go config.WatchSecrets(ctx, cfg.SecretsRefreshInterval, func(rotated []string) {
+		for _, name := range rotated {
+			switch name {
+			case "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY":
+				// Rotations publish a new config, cfg keeps the values the runner started with
+				rotatedCfg, _ := config.GetConfig()
+				dockerClient.UpdateAWSCredentials(rotatedCfg.AWSAccessKeyId, rotatedCfg.AWSSecretAccessKey)
+			default:
+				log.Warnf("%s was rotated, restart the runner to apply it", name)
+			}
+		}
 	})
 
+	reloadChannel := make(chan os.Signal, 1)
+	signal.Notify(reloadChannel, syscall.SIGHUP)
+	go func() {
+		for range reloadChannel {
+			if _, err := runner.GetInstance(nil).ReloadConfig(); err != nil {
+				log.Errorf("Failed to reload config, keeping the current one: %v", err)
+			}
+		}
+	}()
+
 	apiServerErrChan := make(chan error)
 
 	go func() {
@@ -228,7 +684,7 @@ func main() {
 	}()
 
 	interruptChannel := make(chan os.Signal, 1)
-	signal.Notify(interruptChannel, os.Interrupt)
+	signal.Notify(interruptChannel, os.Interrupt, syscall.SIGTERM)
 
 	select {
 	case err := <-apiServerErrChan:
@@ -237,6 +693,14 @@ func main() {
 	case <-interruptChannel:
 		apiServer.Stop()
 	}
+
+	if registrationService != nil && cfg.DeregisterOnShutdown {
+		deregisterCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := registrationService.Deregister(deregisterCtx); err != nil {
+			log.Errorf("Failed to deregister runner: %v", err)
+		}
+	}
 }
 
 func init() {
@@ -261,7 +725,7 @@ func init() {
 	}
 
 	log.SetLevel(logLevel)
-	log.SetOutput(os.Stdout)
+	log.SetOutput(io.MultiWriter(os.Stdout, logBuffer))
 
 	logFilePath, logFilePathSet := os.LookupEnv("LOG_FILE_PATH")
 	if logFilePathSet {
@@ -278,7 +742,7 @@ func init() {
 			os.Exit(1)
 		}
 
-		log.SetOutput(io.MultiWriter(os.Stdout, file))
+		log.SetOutput(io.MultiWriter(os.Stdout, file, logBuffer))
 	}
 
 	zerologLevel, err := zerolog.ParseLevel(logLevel.String())
@@ -299,7 +763,7 @@ func init() {
 }
 
 func newSLogger() *slog.Logger {
-	log := slog.New(tint.NewHandler(os.Stdout, &tint.Options{
+	log := slog.New(tint.NewHandler(io.MultiWriter(os.Stdout, logBuffer), &tint.Options{
 		NoColor:    !isatty.IsTerminal(os.Stdout.Fd()),
 		TimeFormat: time.RFC3339,
 		Level:      parseLogLevel(os.Getenv("LOG_LEVEL")),
@@ -0,0 +1,310 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/client"
+	"github.com/docker/go-units"
+)
+
+// How often new jobs are polled when following them
+const jobsPollInterval = time.Second
+
+type runnerCtl struct {
+	client *client.Client
+	ctx    context.Context
+	json   bool
+}
+
+func (c *runnerCtl) sandboxes() error {
+	sandboxes, err := c.client.ListSandboxes(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		return printJson(sandboxes)
+	}
+
+	w := newTable("ID", "STATE", "SNAPSHOT", "STORAGE", "CREATED")
+	for _, sandbox := range sandboxes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%dGB\t%s\n", sandbox.Id, sandbox.State, sandbox.Snapshot, sandbox.StorageQuota, units.HumanDuration(time.Since(sandbox.CreatedAt))+" ago")
+	}
+	return w.Flush()
+}
+
+func (c *runnerCtl) jobs(args []string) error {
+	flags := flag.NewFlagSet("jobs", flag.ExitOnError)
+	follow := flags.Bool("f", false, "Follow the jobs the runner executes")
+	_ = flags.Parse(args)
+
+	jobs, err := c.client.ListJobs(c.ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	if c.json && !*follow {
+		return printJson(jobs)
+	}
+
+	w := newTable("TIME", "JOB", "TYPE", "RESOURCE", "STATUS", "DURATION", "ERROR")
+	if err := c.printJobs(w, jobs); err != nil {
+		return err
+	}
+	if !*follow {
+		return nil
+	}
+
+	var since int64
+	if len(jobs) > 0 {
+		since = jobs[len(jobs)-1].Sequence
+	}
+
+	for {
+		time.Sleep(jobsPollInterval)
+
+		jobs, err := c.client.ListJobs(c.ctx, since)
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			continue
+		}
+		since = jobs[len(jobs)-1].Sequence
+
+		if c.json {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, job := range jobs {
+				if err := encoder.Encode(job); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := c.printJobs(w, jobs); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *runnerCtl) printJobs(w *tabwriter.Writer, jobs []dto.JobDTO) error {
+	for _, job := range jobs {
+		timestamp, duration := job.StartedAt, "-"
+		if job.FinishedAt != nil {
+			timestamp = *job.FinishedAt
+			duration = job.FinishedAt.Sub(job.StartedAt).Round(time.Millisecond).String()
+		} else if job.Progress != nil && job.Progress.BytesTotal > 0 {
+			duration = fmt.Sprintf("%d%% copied, %s left", job.Progress.BytesCopied*100/job.Progress.BytesTotal, time.Duration(job.Progress.EtaSeconds)*time.Second)
+		}
+
+		resource := job.ResourceId
+		if job.ResourceType != "" {
+			resource = job.ResourceType + "/" + job.ResourceId
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", timestamp.Local().Format(time.DateTime), job.Id, job.Type, resource, job.Status, duration, job.Error)
+	}
+	return w.Flush()
+}
+
+func (c *runnerCtl) drain(args []string) error {
+	action := "on"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	var status *dto.DrainStatusDTO
+	var err error
+	switch action {
+	case "on":
+		status, err = c.client.SetDrainStatus(c.ctx, true)
+	case "off":
+		status, err = c.client.SetDrainStatus(c.ctx, false)
+	case "status":
+		status, err = c.client.GetDrainStatus(c.ctx)
+	default:
+		return fmt.Errorf("unknown drain action %s, expected on, off or status", action)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		return printJson(status)
+	}
+
+	if status.Draining {
+		fmt.Println("Runner is draining, new sandboxes are rejected")
+	} else {
+		fmt.Println("Runner accepts new sandboxes")
+	}
+	return nil
+}
+
+func (c *runnerCtl) diagnostics(args []string) error {
+	flags := flag.NewFlagSet("diagnostics", flag.ExitOnError)
+	output := flags.String("o", fmt.Sprintf("runner-diagnostics-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z")), "File to write the bundle to, - for stdout")
+	_ = flags.Parse(args)
+
+	bundle, err := c.client.GetDiagnostics(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer bundle.Close()
+
+	if *output == "-" {
+		_, err = io.Copy(os.Stdout, bundle)
+		return err
+	}
+
+	file, err := os.OpenFile(*output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	size, err := io.Copy(file, bundle)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote the diagnostics bundle to %s (%s)\n", *output, units.HumanSize(float64(size)))
+	return nil
+}
+
+func (c *runnerCtl) prune(args []string) error {
+	if len(args) == 0 {
+		return errors.New("prune expects images or volumes")
+	}
+
+	var kinds []string
+	switch args[0] {
+	case "images":
+		kinds = []string{dto.OrphanKindImage}
+	case "volumes":
+		kinds = []string{dto.OrphanKindVolume, dto.OrphanKindVolumeMount}
+	default:
+		return fmt.Errorf("unknown prune target %s, expected images or volumes", args[0])
+	}
+
+	flags := flag.NewFlagSet("prune", flag.ExitOnError)
+	dryRun := flags.Bool("n", false, "Only list what would be removed")
+	_ = flags.Parse(args[1:])
+
+	report, err := c.client.ReapOrphans(c.ctx, dto.ReapOrphansDTO{Kinds: kinds, DryRun: dryRun})
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		return printJson(report)
+	}
+
+	w := newTable("KIND", "ID", "SIZE", "REASON", "RESULT")
+	var freed int64
+	for _, resource := range report.Resources {
+		result := "would remove"
+		switch {
+		case resource.Error != "":
+			result = "failed: " + resource.Error
+		case resource.Removed:
+			result = "removed"
+			freed += resource.Size
+		}
+
+		size := "-"
+		if resource.Size > 0 {
+			size = units.HumanSize(float64(resource.Size))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", resource.Kind, resource.Id, size, resource.Reason, result)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for kind, message := range report.Errors {
+		fmt.Fprintf(os.Stderr, "Failed to check %s resources: %s\n", kind, message)
+	}
+
+	if freed > 0 {
+		fmt.Printf("Freed %s\n", units.HumanSize(float64(freed)))
+	}
+	return nil
+}
+
+// netRules lists the network rule chains, of a sandbox if its container ID, or a prefix of it,
+// is given
+func (c *runnerCtl) netRules(args []string) error {
+	chains, err := c.client.ListNetRules(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		filter := args[0]
+		if len(filter) > 12 {
+			filter = filter[:12]
+		}
+
+		filtered := []dto.NetRulesChainDTO{}
+		for _, chain := range chains {
+			var rules []string
+			for _, rule := range chain.Rules {
+				if strings.Contains(rule, filter) {
+					rules = append(rules, rule)
+				}
+			}
+
+			if strings.Contains(chain.Chain, filter) {
+				filtered = append(filtered, chain)
+			} else if len(rules) > 0 {
+				filtered = append(filtered, dto.NetRulesChainDTO{Table: chain.Table, Chain: chain.Chain, Rules: rules})
+			}
+		}
+		chains = filtered
+	}
+
+	if c.json {
+		return printJson(chains)
+	}
+
+	for i, chain := range chains {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s/%s\n", chain.Table, chain.Chain)
+		for _, rule := range chain.Rules {
+			fmt.Printf("  %s\n", rule)
+		}
+	}
+	return nil
+}
+
+func newTable(columns ...string) *tabwriter.Writer {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(columns, "\t"))
+	return w
+}
+
+func printJson(value any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
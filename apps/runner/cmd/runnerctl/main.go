@@ -0,0 +1,99 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// daytona-runnerctl manages the runner of the host it runs on through the runner API
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/daytonaio/runner/cmd/runner/config"
+	"github.com/daytonaio/runner/pkg/client"
+)
+
+const usage = `Usage: daytona-runnerctl [flags] <command> [arguments]
+
+Commands:
+  sandboxes                     List the sandboxes with their state
+  jobs [-f]                     List the jobs the runner executed last, -f to follow new ones
+  drain [on|off|status]         Reject new sandboxes while the existing ones keep running
+  diagnostics [-o file]         Download a diagnostics bundle of the runner
+  prune <images|volumes> [-n]   Remove images or volumes no sandbox uses, -n for a dry run
+  netrules [container-id]       List the network rules of the sandboxes, or of one of them
+
+Flags:
+`
+
+func main() {
+	flags := flag.NewFlagSet("daytona-runnerctl", flag.ExitOnError)
+	url := flags.String("url", defaultUrl(), "URL of the runner API, DAYTONA_RUNNER_URL")
+	token := flags.String("token", os.Getenv("DAYTONA_RUNNER_TOKEN"), "Token of the runner API, DAYTONA_RUNNER_TOKEN")
+	adminToken := flags.String("admin-token", os.Getenv("ADMIN_API_TOKEN"), "Token of the admin endpoints of the runner API if it differs, ADMIN_API_TOKEN")
+	jsonOutput := flags.Bool("json", false, "Print the responses of the runner API as JSON")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	_ = flags.Parse(os.Args[1:])
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ctl := &runnerCtl{
+		client: client.New(*url, *token, client.WithAdminToken(*adminToken)),
+		ctx:    context.Background(),
+		json:   *jsonOutput,
+	}
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+
+	var err error
+	switch command {
+	case "sandboxes":
+		err = ctl.sandboxes()
+	case "jobs":
+		err = ctl.jobs(args)
+	case "drain":
+		err = ctl.drain(args)
+	case "diagnostics":
+		err = ctl.diagnostics(args)
+	case "prune":
+		err = ctl.prune(args)
+	case "netrules":
+		err = ctl.netRules(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %s\n\n", command)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// defaultUrl returns the URL of the runner on the port it is configured with
+func defaultUrl() string {
+	if url := os.Getenv("DAYTONA_RUNNER_URL"); url != "" {
+		return url
+	}
+
+	port := config.DEFAULT_API_PORT
+	if value, err := strconv.Atoi(os.Getenv("API_PORT")); err == nil {
+		port = value
+	}
+
+	scheme := "http"
+	if os.Getenv("ENABLE_TLS") == "true" {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://localhost:%d", scheme, port)
+}
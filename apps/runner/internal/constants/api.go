@@ -0,0 +1,8 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package constants
+
+const DAYTONA_API_VERSION_HEADER = "X-Daytona-Api-Version"
+
+const DAYTONA_REQUEST_TIMEOUT_HEADER = "X-Daytona-Request-Timeout"
@@ -47,6 +47,11 @@ type Collector struct {
 	allocatedDiskGiB    float32
 	startedSandboxCount float32
 
+	// Last metrics collected successfully, for callers that can't wait for a collection
+	latestMutex sync.RWMutex
+	latest      *Metrics
+	latestAt    time.Time
+
 	// Intervals for snapshotting metrics in seconds
 	cpuUsageSnapshotInterval           time.Duration
 	allocatedResourcesSnapshotInterval time.Duration
@@ -89,6 +94,7 @@ func NewCollector(cfg CollectorConfig) *Collector {
 func (c *Collector) Start(ctx context.Context) {
 	go c.snapshotCPUUsage(ctx)
 	go c.snapshotAllocatedResources(ctx)
+	go c.snapshotMetrics(ctx)
 }
 
 // Collect gathers current system metrics
@@ -114,6 +120,23 @@ func (c *Collector) Collect(ctx context.Context) (*Metrics, error) {
 	}
 }
 
+// Latest returns the last metrics collected by the collector without collecting them. It fails if
+// no metrics were collected within maxAge, e.g. right after the runner started.
+func (c *Collector) Latest(maxAge time.Duration) (*Metrics, error) {
+	c.latestMutex.RLock()
+	defer c.latestMutex.RUnlock()
+
+	if c.latest == nil {
+		return nil, errors.New("metrics not yet available")
+	}
+	if time.Since(c.latestAt) > maxAge {
+		return nil, fmt.Errorf("metrics are stale, last collected at %s", c.latestAt.Format(time.RFC3339))
+	}
+
+	latest := *c.latest
+	return &latest, nil
+}
+
 func (c *Collector) collect(ctx context.Context) (*Metrics, error) {
 	metrics := &Metrics{}
 
@@ -174,9 +197,32 @@ func (c *Collector) collect(ctx context.Context) (*Metrics, error) {
 	metrics.StartedSandboxCount = c.startedSandboxCount
 	c.resourcesMutex.RUnlock()
 
+	c.latestMutex.Lock()
+	c.latest = metrics
+	c.latestAt = time.Now()
+	c.latestMutex.Unlock()
+
 	return metrics, nil
 }
 
+// snapshotMetrics runs in a background goroutine, keeping the latest metrics up to date
+func (c *Collector) snapshotMetrics(ctx context.Context) {
+	ticker := time.NewTicker(c.allocatedResourcesSnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.log.Info("Metrics snapshotting stopped")
+			return
+		case <-ticker.C:
+			if _, err := c.collect(ctx); err != nil {
+				c.log.Debug("Failed to collect metrics snapshot", slog.Any("error", err))
+			}
+		}
+	}
+}
+
 // snapshotCPUUsage runs in a background goroutine, continuously monitoring CPU usage
 func (c *Collector) snapshotCPUUsage(ctx context.Context) {
 	ticker := time.NewTicker(c.cpuUsageSnapshotInterval)
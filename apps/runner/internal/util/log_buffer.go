@@ -0,0 +1,67 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package util
+
+import (
+	"bytes"
+	"sync"
+)
+
+// LogBuffer is a writer keeping the last lines written to it, for the diagnostics of the runner
+type LogBuffer struct {
+	mutex    sync.Mutex
+	maxLines int
+	lines    []string
+	// Next line to overwrite once the buffer is full
+	next int
+	// Last line written without a trailing newline
+	partial []byte
+}
+
+func NewLogBuffer(maxLines int) *LogBuffer {
+	return &LogBuffer{
+		maxLines: maxLines,
+		lines:    make([]string, 0, maxLines),
+	}
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	data := append(b.partial, p...)
+	for {
+		index := bytes.IndexByte(data, '\n')
+		if index < 0 {
+			break
+		}
+		b.add(string(data[:index]))
+		data = data[index+1:]
+	}
+	b.partial = append([]byte(nil), data...)
+
+	return len(p), nil
+}
+
+func (b *LogBuffer) add(line string) {
+	if len(b.lines) < b.maxLines {
+		b.lines = append(b.lines, line)
+		return
+	}
+
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % b.maxLines
+}
+
+// Lines returns the lines kept by the buffer, oldest first
+func (b *LogBuffer) Lines() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	lines := make([]string, 0, len(b.lines))
+	lines = append(lines, b.lines[b.next:]...)
+	lines = append(lines, b.lines[:b.next]...)
+
+	return lines
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/runner/internal/metrics"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+)
+
+// Metrics older than this are not used for admission decisions
+const maxMetricsAge = time.Minute
+
+// AdmissionControllerConfig holds configuration for the admission controller
+type AdmissionControllerConfig struct {
+	Logger                *slog.Logger
+	Collector             *metrics.Collector
+	Enabled               bool
+	CPUOvercommitRatio    float32
+	MemoryOvercommitRatio float32
+	CPUUsageThreshold     float32
+	MemoryUsageThreshold  float32
+	RetryAfter            time.Duration
+}
+
+// AdmissionController decides whether a new sandbox fits on the runner.
+//
+// Allocated (requested) resources may exceed the physical capacity up to the configured
+// overcommit ratio. Because sandboxes rarely use their full limits, this is safe only as long
+// as real usage stays below the usage thresholds, so both are checked.
+type AdmissionController struct {
+	log       *slog.Logger
+	collector *metrics.Collector
+
+	// Guards the limits below, which can be updated at runtime
+	mu                    sync.RWMutex
+	enabled               bool
+	cpuOvercommitRatio    float32
+	memoryOvercommitRatio float32
+	cpuUsageThreshold     float32
+	memoryUsageThreshold  float32
+	retryAfter            time.Duration
+	// Set while the runner is drained, rejecting all new sandboxes
+	draining bool
+}
+
+// NewAdmissionController creates a new admission controller
+func NewAdmissionController(cfg AdmissionControllerConfig) *AdmissionController {
+	return &AdmissionController{
+		log:                   cfg.Logger.With(slog.String("component", "admission")),
+		collector:             cfg.Collector,
+		enabled:               cfg.Enabled,
+		cpuOvercommitRatio:    cfg.CPUOvercommitRatio,
+		memoryOvercommitRatio: cfg.MemoryOvercommitRatio,
+		cpuUsageThreshold:     cfg.CPUUsageThreshold,
+		memoryUsageThreshold:  cfg.MemoryUsageThreshold,
+		retryAfter:            cfg.RetryAfter,
+	}
+}
+
+// UpdateLimits changes the limits of the admission controller at runtime. The logger and
+// collector of the config are ignored.
+func (a *AdmissionController) UpdateLimits(cfg AdmissionControllerConfig) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.enabled = cfg.Enabled
+	a.cpuOvercommitRatio = cfg.CPUOvercommitRatio
+	a.memoryOvercommitRatio = cfg.MemoryOvercommitRatio
+	a.cpuUsageThreshold = cfg.CPUUsageThreshold
+	a.memoryUsageThreshold = cfg.MemoryUsageThreshold
+	a.retryAfter = cfg.RetryAfter
+}
+
+// SetDraining drains the runner, or stops draining it. A drained runner rejects new sandboxes
+// regardless of its limits, while the existing ones keep running.
+func (a *AdmissionController) SetDraining(draining bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.draining = draining
+}
+
+func (a *AdmissionController) Draining() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.draining
+}
+
+// AdmitCreate checks whether a sandbox with the requested resources can be created.
+// It returns a *common.ResourceExhaustedError if the sandbox should be rejected.
+func (a *AdmissionController) AdmitCreate(ctx context.Context, sandboxDto dto.CreateSandboxDTO) error {
+	if a == nil {
+		return nil
+	}
+
+	a.mu.RLock()
+	enabled := a.enabled
+	draining := a.draining
+	retryAfter := a.retryAfter
+	a.mu.RUnlock()
+
+	if draining {
+		return common.NewResourceExhaustedError(
+			http.StatusServiceUnavailable,
+			"RUNNER_DRAINING",
+			"runner is draining and doesn't accept new sandboxes",
+			retryAfter*4,
+		)
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	// Collecting metrics takes too long for the create path, so the background sample is used
+	m, err := a.collector.Latest(maxMetricsAge)
+	if err != nil {
+		a.log.WarnContext(ctx, "Metrics unavailable, admitting sandbox without checks", slog.String("sandbox_id", sandboxDto.Id), slog.Any("error", err))
+		return nil
+	}
+
+	return a.admit(m, float32(sandboxDto.CpuQuota), float32(sandboxDto.MemoryQuota))
+}
+
+func (a *AdmissionController) admit(m *metrics.Metrics, cpu, memoryGiB float32) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	// Real usage is transient, so the caller is asked to retry shortly
+	if a.cpuUsageThreshold > 0 && m.CPUUsagePercentage >= a.cpuUsageThreshold {
+		return common.NewResourceExhaustedError(
+			http.StatusTooManyRequests,
+			"RUNNER_CPU_PRESSURE",
+			fmt.Sprintf("runner CPU usage %.1f%% is above the admission threshold of %.1f%%", m.CPUUsagePercentage, a.cpuUsageThreshold),
+			a.retryAfter,
+		)
+	}
+
+	if a.memoryUsageThreshold > 0 && m.MemoryUsagePercentage >= a.memoryUsageThreshold {
+		return common.NewResourceExhaustedError(
+			http.StatusTooManyRequests,
+			"RUNNER_MEMORY_PRESSURE",
+			fmt.Sprintf("runner memory usage %.1f%% is above the admission threshold of %.1f%%", m.MemoryUsagePercentage, a.memoryUsageThreshold),
+			a.retryAfter,
+		)
+	}
+
+	// Allocations only free up when sandboxes are stopped or destroyed, so the retry hint is longer
+	if a.cpuOvercommitRatio > 0 {
+		cpuCapacity := m.TotalCPU * a.cpuOvercommitRatio
+		if m.AllocatedCPU+cpu > cpuCapacity {
+			return common.NewResourceExhaustedError(
+				http.StatusInsufficientStorage,
+				"RUNNER_CPU_EXHAUSTED",
+				fmt.Sprintf("requested %.0f vCPU would exceed runner CPU capacity (%.1f/%.1f vCPU allocated)", cpu, m.AllocatedCPU, cpuCapacity),
+				a.retryAfter*4,
+			)
+		}
+	}
+
+	if a.memoryOvercommitRatio > 0 {
+		memoryCapacity := m.TotalRAMGiB * a.memoryOvercommitRatio
+		if m.AllocatedMemoryGiB+memoryGiB > memoryCapacity {
+			return common.NewResourceExhaustedError(
+				http.StatusInsufficientStorage,
+				"RUNNER_MEMORY_EXHAUSTED",
+				fmt.Sprintf("requested %.0f GiB memory would exceed runner memory capacity (%.1f/%.1f GiB allocated)", memoryGiB, m.AllocatedMemoryGiB, memoryCapacity),
+				a.retryAfter*4,
+			)
+		}
+	}
+
+	return nil
+}
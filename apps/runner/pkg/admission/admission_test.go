@@ -0,0 +1,147 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package admission
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/runner/internal/metrics"
+	"github.com/daytonaio/runner/pkg/common"
+)
+
+func TestAdmit(t *testing.T) {
+	config := AdmissionControllerConfig{
+		Logger:                slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Enabled:               true,
+		CPUOvercommitRatio:    2,
+		MemoryOvercommitRatio: 1.5,
+		CPUUsageThreshold:     90,
+		MemoryUsageThreshold:  85,
+		RetryAfter:            10 * time.Second,
+	}
+
+	// 8 vCPU and 32 GiB, so 16 vCPU and 48 GiB can be allocated
+	idle := metrics.Metrics{
+		TotalCPU:              8,
+		TotalRAMGiB:           32,
+		CPUUsagePercentage:    20,
+		MemoryUsagePercentage: 30,
+		AllocatedCPU:          10,
+		AllocatedMemoryGiB:    40,
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(m *metrics.Metrics, c *AdmissionControllerConfig)
+		cpu        float32
+		memoryGiB  float32
+		wantCode   string
+		wantStatus int
+		wantRetry  time.Duration
+	}{
+		{
+			name:      "fits",
+			cpu:       4,
+			memoryGiB: 8,
+		},
+		{
+			name:      "fills CPU and memory capacity exactly",
+			cpu:       6,
+			memoryGiB: 8,
+		},
+		{
+			name:       "CPU usage at threshold",
+			modify:     func(m *metrics.Metrics, _ *AdmissionControllerConfig) { m.CPUUsagePercentage = 90 },
+			cpu:        1,
+			memoryGiB:  1,
+			wantCode:   "RUNNER_CPU_PRESSURE",
+			wantStatus: http.StatusTooManyRequests,
+			wantRetry:  10 * time.Second,
+		},
+		{
+			name:       "memory usage above threshold",
+			modify:     func(m *metrics.Metrics, _ *AdmissionControllerConfig) { m.MemoryUsagePercentage = 95 },
+			cpu:        1,
+			memoryGiB:  1,
+			wantCode:   "RUNNER_MEMORY_PRESSURE",
+			wantStatus: http.StatusTooManyRequests,
+			wantRetry:  10 * time.Second,
+		},
+		{
+			name:       "usage is checked before allocations",
+			modify:     func(m *metrics.Metrics, _ *AdmissionControllerConfig) { m.CPUUsagePercentage = 99 },
+			cpu:        100,
+			memoryGiB:  100,
+			wantCode:   "RUNNER_CPU_PRESSURE",
+			wantStatus: http.StatusTooManyRequests,
+			wantRetry:  10 * time.Second,
+		},
+		{
+			name:       "CPU over capacity",
+			cpu:        7,
+			memoryGiB:  1,
+			wantCode:   "RUNNER_CPU_EXHAUSTED",
+			wantStatus: http.StatusInsufficientStorage,
+			wantRetry:  40 * time.Second,
+		},
+		{
+			name:       "memory over capacity",
+			cpu:        1,
+			memoryGiB:  9,
+			wantCode:   "RUNNER_MEMORY_EXHAUSTED",
+			wantStatus: http.StatusInsufficientStorage,
+			wantRetry:  40 * time.Second,
+		},
+		{
+			name: "disabled thresholds and ratios admit everything",
+			modify: func(m *metrics.Metrics, c *AdmissionControllerConfig) {
+				m.CPUUsagePercentage = 100
+				m.MemoryUsagePercentage = 100
+				c.CPUUsageThreshold = 0
+				c.MemoryUsageThreshold = 0
+				c.CPUOvercommitRatio = 0
+				c.MemoryOvercommitRatio = 0
+			},
+			cpu:       100,
+			memoryGiB: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := idle
+			c := config
+			if tt.modify != nil {
+				tt.modify(&m, &c)
+			}
+
+			err := NewAdmissionController(c).admit(&m, tt.cpu, tt.memoryGiB)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("expected sandbox to be admitted, got %v", err)
+				}
+				return
+			}
+
+			var exhaustedErr *common.ResourceExhaustedError
+			if !errors.As(err, &exhaustedErr) {
+				t.Fatalf("expected a resource exhausted error, got %v", err)
+			}
+			if exhaustedErr.Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", exhaustedErr.Code, tt.wantCode)
+			}
+			if exhaustedErr.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", exhaustedErr.StatusCode, tt.wantStatus)
+			}
+			if exhaustedErr.RetryAfter != tt.wantRetry {
+				t.Errorf("retry after = %s, want %s", exhaustedErr.RetryAfter, tt.wantRetry)
+			}
+		})
+	}
+}
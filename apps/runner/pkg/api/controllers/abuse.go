@@ -0,0 +1,38 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daytonaio/runner/pkg/runner"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// ListAbuseEvents godoc
+//
+//	@Tags			abuse
+//	@Summary		List abuse events
+//	@Description	List the cryptocurrency mining signals raised for sandboxes on this runner, oldest first
+//	@Produce		json
+//	@Param			sandboxId	query		string	false	"Only list the events of the sandbox"
+//	@Success		200			{array}		dto.AbuseEventDTO
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/abuse/events [get]
+//
+//	@id				ListAbuseEvents
+func ListAbuseEvents(ctx *gin.Context) {
+	runner := runner.GetInstance(nil)
+	if runner.AbuseDetection == nil {
+		ctx.Error(common_errors.NewBadRequestError(errors.New("abuse detection is not enabled on the runner")))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runner.AbuseDetection.ListEvents(ctx.Query("sandboxId")))
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/runner"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ListJobs godoc
+//
+//	@Tags			admin
+//	@Summary		List jobs
+//	@Description	List the jobs the runner executed last, in the order of their last update by default. Jobs are only executed with version 2 of the runner API. All jobs are listed if no limit is set, a page of them otherwise, with the cursor of the next page in the X-Next-Cursor header.
+//	@Produce		json
+//	@Param			since		query		int			false	"Only list the jobs updated after this sequence"
+//	@Param			status		query		[]string	false	"Only list the jobs with these statuses"	collectionFormat(multi)
+//	@Param			type		query		[]string	false	"Only list the jobs of these types"	collectionFormat(multi)
+//	@Param			resourceId	query		string		false	"Only list the jobs of this resource"
+//	@Param			sort		query		string		false	"Sort key"	Enums(sequence, startedAt, id)
+//	@Param			order		query		string		false	"Sort order"	Enums(asc, desc)
+//	@Param			limit		query		int			false	"Maximum number of jobs, at most 1000"
+//	@Param			cursor		query		string		false	"Cursor of the page, from the X-Next-Cursor header of the previous page"
+//	@Param			fields		query		string		false	"Comma separated fields of the jobs to return, all fields if empty"
+//	@Success		200			{array}		dto.JobDTO
+//	@Header			200			{string}	X-Next-Cursor	"Cursor of the next page, unset on the last page"
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Router			/jobs [get]
+//
+//	@id				ListJobs
+func ListJobs(ctx *gin.Context) {
+	var since int64
+	if value := ctx.Query("since"); value != "" {
+		var err error
+		since, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid since: %w", err)))
+			return
+		}
+	}
+
+	params, err := parseListParams(ctx, "sequence", "startedAt", "id")
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
+	fields, err := parseFields[dto.JobDTO](ctx)
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
+	statuses := ctx.QueryArray("status")
+	types := ctx.QueryArray("type")
+	resourceId := ctx.Query("resourceId")
+
+	runner := runner.GetInstance(nil)
+
+	jobs := slices.DeleteFunc(runner.Jobs.List(since), func(job dto.JobDTO) bool {
+		return (len(statuses) > 0 && !slices.Contains(statuses, job.Status)) ||
+			(len(types) > 0 && !slices.Contains(types, job.Type)) ||
+			(resourceId != "" && job.ResourceId != resourceId)
+	})
+
+	jobs = paginate(ctx, params, jobs, func(job dto.JobDTO, sort string) string {
+		switch sort {
+		case "startedAt":
+			return sortableInt(job.StartedAt.UnixNano())
+		case "id":
+			return job.Id
+		}
+		return sortableInt(job.Sequence)
+	}, func(job dto.JobDTO) string {
+		return job.Id
+	})
+
+	respondWithFields(ctx, http.StatusOK, jobs, fields)
+}
+
+// GetDrainStatus godoc
+//
+//	@Tags			admin
+//	@Summary		Get drain status
+//	@Description	Get whether the runner is drained
+//	@Produce		json
+//	@Success		200	{object}	dto.DrainStatusDTO
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Router			/drain [get]
+//
+//	@id				GetDrainStatus
+func GetDrainStatus(ctx *gin.Context) {
+	runner := runner.GetInstance(nil)
+
+	ctx.JSON(http.StatusOK, dto.DrainStatusDTO{Draining: runner.Admission.Draining()})
+}
+
+// SetDrainStatus godoc
+//
+//	@Tags			admin
+//	@Summary		Drain the runner
+//	@Description	Drain the runner, so that it rejects new sandboxes while the existing ones keep running, or stop draining it. The drain is lost when the runner restarts.
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.DrainStatusDTO	true	"Drain status"
+//	@Success		200		{object}	dto.DrainStatusDTO
+//	@Failure		400		{object}	common_errors.ErrorResponse
+//	@Failure		401		{object}	common_errors.ErrorResponse
+//	@Router			/drain [post]
+//
+//	@id				SetDrainStatus
+func SetDrainStatus(ctx *gin.Context) {
+	var drainDto dto.DrainStatusDTO
+	err := ctx.ShouldBindJSON(&drainDto)
+	if err != nil {
+		ctx.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	runner := runner.GetInstance(nil)
+	runner.Admission.SetDraining(drainDto.Draining)
+
+	if drainDto.Draining {
+		log.Info("Runner is draining, new sandboxes are rejected")
+	} else {
+		log.Info("Runner stopped draining")
+	}
+
+	ctx.JSON(http.StatusOK, dto.DrainStatusDTO{Draining: runner.Admission.Draining()})
+}
+
+// ListNetRules godoc
+//
+//	@Tags			admin
+//	@Summary		List network rules
+//	@Description	List the iptables chains of the sandboxes with their rules, along with the DOCKER-USER rules jumping to them
+//	@Produce		json
+//	@Success		200	{array}		dto.NetRulesChainDTO
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Failure		500	{object}	common_errors.ErrorResponse
+//	@Router			/netrules [get]
+//
+//	@id				ListNetRules
+func ListNetRules(ctx *gin.Context) {
+	runner := runner.GetInstance(nil)
+
+	chains, err := runner.ListNetRules()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, chains)
+}
+
+// GetDiagnostics godoc
+//
+//	@Tags			admin
+//	@Summary		Get diagnostics bundle
+//	@Description	Get a gzipped tar of the state of the runner: its recent logs, config with the secrets redacted, metrics, sandboxes, jobs, network rules, orphaned resources, Docker info, disk usage and goroutine and heap profiles
+//	@Produce		application/gzip
+//	@Success		200	{file}		binary
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Router			/admin/diagnostics [get]
+//
+//	@id				GetDiagnostics
+func GetDiagnostics(ctx *gin.Context) {
+	runner := runner.GetInstance(nil)
+
+	ctx.Header("Content-Type", "application/gzip")
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="runner-diagnostics-%s.tar.gz"`, time.Now().UTC().Format("20060102T150405Z")))
+	ctx.Status(http.StatusOK)
+
+	// The response has started, so errors can only be logged
+	if err := runner.WriteDiagnostics(ctx.Request.Context(), ctx.Writer); err != nil {
+		log.Errorf("Failed to write diagnostics bundle: %v", err)
+	}
+}
+
+// GetVolumeMountCleanup godoc
+//
+//	@Tags			admin
+//	@Summary		Get volume mount cleanup plan
+//	@Description	List the volume mount directories of the runner with what the next volume mount cleanup would do with them and why, without removing anything. Mounts used by a container, reserved for a sandbox being created or mounted within the grace period are kept.
+//	@Produce		json
+//	@Success		200	{object}	dto.VolumeMountCleanupDTO
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Failure		500	{object}	common_errors.ErrorResponse
+//	@Router			/admin/volume-mounts/cleanup [get]
+//
+//	@id				GetVolumeMountCleanup
+func GetVolumeMountCleanup(ctx *gin.Context) {
+	runner := runner.GetInstance(nil)
+
+	plan, err := runner.Docker.PlanVolumeMountCleanup(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, plan)
+}
@@ -6,11 +6,13 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/daytonaio/common-go/pkg/errors"
 	"github.com/daytonaio/common-go/pkg/proxy"
+	"github.com/daytonaio/runner/pkg/runner"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 
@@ -24,16 +26,23 @@ func ProxyCommandLogsStream(ctx *gin.Context) {
 		return
 	}
 
+	runner := runner.GetInstance(nil)
+
 	if ctx.Query("follow") != "true" {
-		proxy.NewProxyRequestHandler(func(ctx *gin.Context) (*url.URL, map[string]string, error) {
+		proxy.NewProxyRequestHandlerWithTransport(func(ctx *gin.Context) (*url.URL, map[string]string, error) {
 			return targetURL, extraHeaders, nil
-		}, nil)(ctx)
+		}, nil, runner.Docker.DaemonTransport())(ctx)
 		return
 	}
 
 	fullTargetURL := strings.Replace(targetURL.String(), "http://", "ws://", 1)
 
-	ws, _, err := websocket.DefaultDialer.DialContext(context.Background(), fullTargetURL+"?follow=true", nil)
+	header := http.Header{}
+	for key, value := range extraHeaders {
+		header.Set(key, value)
+	}
+
+	ws, _, err := runner.Docker.DaemonWebsocketDialer().DialContext(context.Background(), fullTargetURL+"?follow=true", header)
 	if err != nil {
 		ctx.Error(errors.NewBadRequestError(fmt.Errorf("failed to create outgoing request: %w", err)))
 		return
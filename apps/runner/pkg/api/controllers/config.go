@@ -0,0 +1,39 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/runner"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// ReloadConfig 			godoc
+//
+//	@Summary		Reload runner config
+//	@Description	Reload the runner config from its .env file and environment. Only limits, policies, log levels and mirrors are applied, other changes require a restart. Invalid configs are rejected without changing the running config.
+//	@Produce		json
+//	@Success		200	{object}	dto.ReloadConfigResponseDTO
+//	@Failure		400	{object}	common_errors.ErrorResponse
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Router			/config/reload [post]
+//
+//	@id				ReloadConfig
+func ReloadConfig(ctx *gin.Context) {
+	changed, err := runner.GetInstance(nil).ReloadConfig()
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
+	if changed == nil {
+		changed = []string{}
+	}
+
+	ctx.JSON(http.StatusOK, dto.ReloadConfigResponseDTO{Changed: changed})
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondWithETag responds with a JSON body and its ETag, or with 304 if the caller already has
+// it. The ETag is derived from the states cache version the body was read at and a hash of the
+// body, which also covers the changes made outside of the runner, e.g. containers removed with
+// the Docker CLI.
+func respondWithETag(ctx *gin.Context, version uint64, value any) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	hash := fnv.New64a()
+	hash.Write(body)
+	etag := fmt.Sprintf(`W/"%d-%x"`, version, hash.Sum64())
+
+	ctx.Header("ETag", etag)
+
+	if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// etagMatches compares the ETags of an If-None-Match header with an ETag, weakly as RFC 9110
+// requires for If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
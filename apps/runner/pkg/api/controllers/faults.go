@@ -0,0 +1,97 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/faults"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ListFaults godoc
+//
+//	@Tags			admin
+//	@Summary		List injected faults
+//	@Description	List the faults injected into the operations of the runner. Only available on runners built with the faultinjection build tag.
+//	@Produce		json
+//	@Success		200	{array}		dto.FaultDTO
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Router			/admin/faults [get]
+//
+//	@id				ListFaults
+func ListFaults(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, faults.List())
+}
+
+// AddFault godoc
+//
+//	@Tags			admin
+//	@Summary		Inject a fault
+//	@Description	Inject an error or a delay into an operation of the runner. Only available on runners built with the faultinjection build tag.
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.FaultDTO	true	"Fault"
+//	@Success		201		{object}	dto.FaultDTO
+//	@Failure		400		{object}	common_errors.ErrorResponse
+//	@Failure		401		{object}	common_errors.ErrorResponse
+//	@Router			/admin/faults [post]
+//
+//	@id				AddFault
+func AddFault(ctx *gin.Context) {
+	var faultDto dto.FaultDTO
+	err := ctx.ShouldBindJSON(&faultDto)
+	if err != nil {
+		ctx.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	fault := faults.Add(faultDto)
+	log.Warnf("Fault %s injected into %s", fault.Id, fault.Point)
+
+	ctx.JSON(http.StatusCreated, fault)
+}
+
+// RemoveFault godoc
+//
+//	@Tags			admin
+//	@Summary		Remove an injected fault
+//	@Description	Remove an injected fault
+//	@Param			id	path	string	true	"Fault ID"
+//	@Success		204
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Failure		404	{object}	common_errors.ErrorResponse
+//	@Router			/admin/faults/{id} [delete]
+//
+//	@id				RemoveFault
+func RemoveFault(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if !faults.Remove(id) {
+		ctx.Error(common_errors.NewNotFoundError(fmt.Errorf("fault not found: %s", id)))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ClearFaults godoc
+//
+//	@Tags			admin
+//	@Summary		Remove all injected faults
+//	@Description	Remove all injected faults
+//	@Success		204
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Router			/admin/faults [delete]
+//
+//	@id				ClearFaults
+func ClearFaults(ctx *gin.Context) {
+	faults.Clear()
+
+	ctx.Status(http.StatusNoContent)
+}
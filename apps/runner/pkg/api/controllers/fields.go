@@ -0,0 +1,108 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldSelection is the set of response fields requested with the fields query parameter, all
+// fields if nil
+type fieldSelection map[string]bool
+
+// parseFields parses the comma separated fields query parameter, checking that the fields are JSON
+// fields of the response DTO
+func parseFields[T any](ctx *gin.Context) (fieldSelection, error) {
+	value := ctx.Query("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	known := jsonFields(reflect.TypeFor[T]())
+
+	fields := fieldSelection{}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !known[field] {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		fields[field] = true
+	}
+
+	return fields, nil
+}
+
+// has returns whether a field is part of the response, so that handlers can skip computing the
+// fields that weren't requested
+func (f fieldSelection) has(field string) bool {
+	return f == nil || f[field]
+}
+
+// respondWithFields responds with the requested fields of a DTO, or of each DTO of a list
+func respondWithFields(ctx *gin.Context, status int, value any, fields fieldSelection) {
+	selected, err := selectFields(value, fields)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(status, selected)
+}
+
+// selectFields returns the requested fields of a DTO, or of each DTO of a list
+func selectFields(value any, fields fieldSelection) (any, error) {
+	if fields == nil {
+		return value, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if reflect.ValueOf(value).Kind() == reflect.Slice {
+		var items []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			fields.filter(item)
+		}
+		return items, nil
+	}
+
+	var item map[string]json.RawMessage
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	fields.filter(item)
+	return item, nil
+}
+
+func (f fieldSelection) filter(item map[string]json.RawMessage) {
+	for field := range item {
+		if !f[field] {
+			delete(item, field)
+		}
+	}
+}
+
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
@@ -42,7 +42,33 @@ func RunnerInfo(ctx *gin.Context) {
 			CurrentSnapshotCount:         int(metrics.SnapshotCount),
 			CurrentStartedSandboxes:      int64(metrics.StartedSandboxCount),
 		},
-		AppVersion: internal.Version,
+		AppVersion:          internal.Version,
+		LazyPullSnapshotter: runnerInstance.Docker.LazyPullSnapshotter(ctx.Request.Context()),
+	}
+
+	response.EnvEncryptionPublicKey, err = runnerInstance.Docker.EnvEncryptionPublicKey()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	deviceSupport := runnerInstance.Docker.DeviceSupport()
+	response.Devices = dto.DeviceSupportDTO{
+		Kvm:                  deviceSupport.Kvm,
+		NestedVirtualization: deviceSupport.NestedVirtualization,
+		Tun:                  deviceSupport.Tun,
+	}
+
+	response.NestedDockerModes = runnerInstance.Docker.NestedDockerModes()
+
+	for _, node := range runnerInstance.Docker.CpuTopology() {
+		response.NumaNodes = append(response.NumaNodes, dto.NumaNodeDTO{
+			Id:                 node.Id,
+			Cpus:               len(node.Cpus),
+			FreeCpus:           node.FreeCpus,
+			MemoryGiB:          node.MemoryBytes / (1024 * 1024 * 1024),
+			AllocatedMemoryGiB: node.AllocatedMemoryBytes / (1024 * 1024 * 1024),
+		})
 	}
 
 	ctx.JSON(http.StatusOK, response)
@@ -0,0 +1,95 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/runner"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// GetOrganizationUsage godoc
+//
+//	@Tags			organization
+//	@Summary		Get organization usage
+//	@Description	Get resources consumed by the organization on this runner along with its quota
+//	@Produce		json
+//	@Param			organizationId	path		string	true	"Organization ID"
+//	@Success		200				{object}	dto.OrganizationUsageDTO
+//	@Failure		400				{object}	common_errors.ErrorResponse
+//	@Failure		401				{object}	common_errors.ErrorResponse
+//	@Failure		500				{object}	common_errors.ErrorResponse
+//	@Router			/organizations/{organizationId}/usage [get]
+//
+//	@id				GetOrganizationUsage
+func GetOrganizationUsage(ctx *gin.Context) {
+	organizationId := ctx.Param("organizationId")
+
+	runner := runner.GetInstance(nil)
+
+	usage, err := runner.OrganizationQuota.GetUsage(ctx.Request.Context(), organizationId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, usage)
+}
+
+// SetOrganizationQuota godoc
+//
+//	@Tags			organization
+//	@Summary		Set organization quota
+//	@Description	Set the quota enforced for the organization on this runner
+//	@Produce		json
+//	@Param			organizationId	path		string					true	"Organization ID"
+//	@Param			quota			body		dto.OrganizationQuotaDTO	true	"Organization quota"
+//	@Success		200				{string}	string					"Organization quota set"
+//	@Failure		400				{object}	common_errors.ErrorResponse
+//	@Failure		401				{object}	common_errors.ErrorResponse
+//	@Failure		500				{object}	common_errors.ErrorResponse
+//	@Router			/organizations/{organizationId}/quota [put]
+//
+//	@id				SetOrganizationQuota
+func SetOrganizationQuota(ctx *gin.Context) {
+	var quotaDto dto.OrganizationQuotaDTO
+	err := ctx.ShouldBindJSON(&quotaDto)
+	if err != nil {
+		ctx.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	organizationId := ctx.Param("organizationId")
+
+	runner := runner.GetInstance(nil)
+	runner.OrganizationQuota.SetQuota(organizationId, quotaDto)
+
+	ctx.JSON(http.StatusOK, "Organization quota set")
+}
+
+// RemoveOrganizationQuota godoc
+//
+//	@Tags			organization
+//	@Summary		Remove organization quota
+//	@Description	Stop enforcing a quota for the organization on this runner
+//	@Produce		json
+//	@Param			organizationId	path		string	true	"Organization ID"
+//	@Success		200				{string}	string	"Organization quota removed"
+//	@Failure		401				{object}	common_errors.ErrorResponse
+//	@Failure		500				{object}	common_errors.ErrorResponse
+//	@Router			/organizations/{organizationId}/quota [delete]
+//
+//	@id				RemoveOrganizationQuota
+func RemoveOrganizationQuota(ctx *gin.Context) {
+	organizationId := ctx.Param("organizationId")
+
+	runner := runner.GetInstance(nil)
+	runner.OrganizationQuota.RemoveQuota(organizationId)
+
+	ctx.JSON(http.StatusOK, "Organization quota removed")
+}
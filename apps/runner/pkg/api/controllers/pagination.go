@@ -0,0 +1,139 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxPageLimit = 1000
+	// Set on pages that aren't the last one
+	nextCursorHeader = "X-Next-Cursor"
+)
+
+// listParams are the sort and pagination of a list request
+type listParams struct {
+	// All items if 0, for clients that predate pagination
+	limit int
+	sort  string
+	desc  bool
+	after *cursor
+}
+
+// cursor points after the last item of a page. It carries the sort of the page, so that the next
+// pages are sorted the same way.
+type cursor struct {
+	Sort string `json:"s"`
+	Desc bool   `json:"d,omitempty"`
+	Key  string `json:"k"`
+	Id   string `json:"i"`
+}
+
+// parseListParams parses the limit, cursor, sort and order query parameters. The first sort is the
+// default one.
+func parseListParams(ctx *gin.Context, sorts ...string) (listParams, error) {
+	params := listParams{sort: sorts[0]}
+
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 || limit > maxPageLimit {
+			return params, fmt.Errorf("invalid limit %q, must be between 0 and %d", value, maxPageLimit)
+		}
+		params.limit = limit
+	}
+
+	if value := ctx.Query("sort"); value != "" {
+		if !slices.Contains(sorts, value) {
+			return params, fmt.Errorf("invalid sort %q, must be one of %v", value, sorts)
+		}
+		params.sort = value
+	}
+
+	switch ctx.Query("order") {
+	case "", "asc":
+	case "desc":
+		params.desc = true
+	default:
+		return params, fmt.Errorf("invalid order %q, must be asc or desc", ctx.Query("order"))
+	}
+
+	if value := ctx.Query("cursor"); value != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return params, errors.New("invalid cursor")
+		}
+
+		var after cursor
+		if err := json.Unmarshal(decoded, &after); err != nil || !slices.Contains(sorts, after.Sort) {
+			return params, errors.New("invalid cursor")
+		}
+
+		if (ctx.Query("sort") != "" && after.Sort != params.sort) || (ctx.Query("order") != "" && after.Desc != params.desc) {
+			return params, errors.New("the sort of the cursor differs from the requested sort")
+		}
+
+		params.sort = after.Sort
+		params.desc = after.Desc
+		params.after = &after
+	}
+
+	return params, nil
+}
+
+// paginate sorts items by their sort key, then by their ID so that the order is stable, and
+// returns the page after the cursor. The cursor of the next page is set in the X-Next-Cursor header.
+func paginate[T any](ctx *gin.Context, params listParams, items []T, key func(item T, sort string) string, id func(item T) string) []T {
+	compare := func(aKey, aId, bKey, bId string) int {
+		result := cmp.Or(cmp.Compare(aKey, bKey), cmp.Compare(aId, bId))
+		if params.desc {
+			return -result
+		}
+		return result
+	}
+
+	slices.SortFunc(items, func(a, b T) int {
+		return compare(key(a, params.sort), id(a), key(b, params.sort), id(b))
+	})
+
+	if params.after != nil {
+		start := slices.IndexFunc(items, func(item T) bool {
+			return compare(key(item, params.sort), id(item), params.after.Key, params.after.Id) > 0
+		})
+		if start == -1 {
+			start = len(items)
+		}
+		items = items[start:]
+	}
+
+	if params.limit == 0 || len(items) <= params.limit {
+		return items
+	}
+
+	items = items[:params.limit]
+	last := items[len(items)-1]
+
+	next, _ := json.Marshal(cursor{
+		Sort: params.sort,
+		Desc: params.desc,
+		Key:  key(last, params.sort),
+		Id:   id(last),
+	})
+	ctx.Header(nextCursorHeader, base64.RawURLEncoding.EncodeToString(next))
+
+	return items
+}
+
+// sortableInt formats a non-negative number so that it sorts as a string
+func sortableInt(value int64) string {
+	return fmt.Sprintf("%020d", value)
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// Longest CPU profile or execution trace that can be captured, in seconds
+const maxProfileSeconds = 300
+
+// Profiles of the runtime served by GetProfile
+var runtimeProfiles = []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"}
+
+var (
+	// The runtime doesn't report the block profile rate, so the last one set is kept
+	blockProfileRate      int
+	blockProfileRateMutex sync.Mutex
+)
+
+// GetCpuProfile godoc
+//
+//	@Tags			admin
+//	@Summary		Capture a CPU profile
+//	@Description	Capture a CPU profile of the runner in the pprof format
+//	@Produce		application/octet-stream
+//	@Param			seconds	query		int	false	"Duration of the profile, 30 seconds by default"
+//	@Success		200		{file}		binary
+//	@Failure		400		{object}	common_errors.ErrorResponse
+//	@Failure		401		{object}	common_errors.ErrorResponse
+//	@Router			/admin/pprof/profile [get]
+//
+//	@id				GetCpuProfile
+func GetCpuProfile(ctx *gin.Context) {
+	if err := validateProfileSeconds(ctx); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	pprof.Profile(ctx.Writer, ctx.Request)
+}
+
+// GetExecutionTrace godoc
+//
+//	@Tags			admin
+//	@Summary		Capture an execution trace
+//	@Description	Capture an execution trace of the runner, to be inspected with go tool trace
+//	@Produce		application/octet-stream
+//	@Param			seconds	query		int	false	"Duration of the trace, 1 second by default"
+//	@Success		200		{file}		binary
+//	@Failure		400		{object}	common_errors.ErrorResponse
+//	@Failure		401		{object}	common_errors.ErrorResponse
+//	@Router			/admin/pprof/trace [get]
+//
+//	@id				GetExecutionTrace
+func GetExecutionTrace(ctx *gin.Context) {
+	if err := validateProfileSeconds(ctx); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	pprof.Trace(ctx.Writer, ctx.Request)
+}
+
+// GetProfile godoc
+//
+//	@Tags			admin
+//	@Summary		Get a runtime profile
+//	@Description	Get a profile of the runner runtime in the pprof format, or as text with debug=1. The mutex and block profiles are empty until their sampling is enabled.
+//	@Produce		application/octet-stream
+//	@Param			name	path		string	true	"Profile name"	Enums(allocs, block, goroutine, heap, mutex, threadcreate)
+//	@Param			debug	query		int		false	"1 or 2 for a text profile"
+//	@Param			gc		query		int		false	"1 to run a garbage collection before a heap profile"
+//	@Param			seconds	query		int		false	"Return the difference of the profile over this duration"
+//	@Success		200		{file}		binary
+//	@Failure		400		{object}	common_errors.ErrorResponse
+//	@Failure		401		{object}	common_errors.ErrorResponse
+//	@Router			/admin/pprof/profiles/{name} [get]
+//
+//	@id				GetProfile
+func GetProfile(ctx *gin.Context) {
+	name := ctx.Param("name")
+	if !slices.Contains(runtimeProfiles, name) {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("unknown profile %s", name)))
+		return
+	}
+
+	if err := validateProfileSeconds(ctx); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	pprof.Handler(name).ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// GetProfilingSampling godoc
+//
+//	@Tags			admin
+//	@Summary		Get profiling sampling
+//	@Description	Get the sampling of the mutex and block profiles
+//	@Produce		json
+//	@Success		200	{object}	dto.ProfilingSamplingDTO
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Router			/admin/pprof/sampling [get]
+//
+//	@id				GetProfilingSampling
+func GetProfilingSampling(ctx *gin.Context) {
+	blockProfileRateMutex.Lock()
+	defer blockProfileRateMutex.Unlock()
+
+	ctx.JSON(http.StatusOK, dto.ProfilingSamplingDTO{
+		MutexProfileFraction: runtime.SetMutexProfileFraction(-1),
+		BlockProfileRate:     blockProfileRate,
+	})
+}
+
+// SetProfilingSampling godoc
+//
+//	@Tags			admin
+//	@Summary		Set profiling sampling
+//	@Description	Set the sampling of the mutex and block profiles. Sampling has a cost, it should be disabled again once the profiles are captured.
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.ProfilingSamplingDTO	true	"Profiling sampling"
+//	@Success		200		{object}	dto.ProfilingSamplingDTO
+//	@Failure		400		{object}	common_errors.ErrorResponse
+//	@Failure		401		{object}	common_errors.ErrorResponse
+//	@Router			/admin/pprof/sampling [post]
+//
+//	@id				SetProfilingSampling
+func SetProfilingSampling(ctx *gin.Context) {
+	var samplingDto dto.ProfilingSamplingDTO
+	err := ctx.ShouldBindJSON(&samplingDto)
+	if err != nil {
+		ctx.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	blockProfileRateMutex.Lock()
+	defer blockProfileRateMutex.Unlock()
+
+	runtime.SetMutexProfileFraction(samplingDto.MutexProfileFraction)
+	runtime.SetBlockProfileRate(samplingDto.BlockProfileRate)
+	blockProfileRate = samplingDto.BlockProfileRate
+
+	ctx.JSON(http.StatusOK, samplingDto)
+}
+
+func validateProfileSeconds(ctx *gin.Context) error {
+	value := ctx.Query("seconds")
+	if value == "" {
+		return nil
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return common_errors.NewBadRequestError(errors.New("seconds must be a positive integer"))
+	}
+	if seconds > maxProfileSeconds {
+		return common_errors.NewBadRequestError(fmt.Errorf("seconds must be at most %d", maxProfileSeconds))
+	}
+
+	return nil
+}
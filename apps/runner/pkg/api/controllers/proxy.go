@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	proxy "github.com/daytonaio/common-go/pkg/proxy"
+	"github.com/daytonaio/runner/pkg/common"
 	"github.com/daytonaio/runner/pkg/runner"
 	"github.com/gin-gonic/gin"
 
@@ -41,7 +42,7 @@ func ProxyRequest(ctx *gin.Context) {
 		}
 	}
 
-	proxy.NewProxyRequestHandler(getProxyTarget, nil)(ctx)
+	proxy.NewProxyRequestHandlerWithTransport(getProxyTarget, nil, runner.GetInstance(nil).Docker.DaemonTransport())(ctx)
 }
 
 func getProxyTarget(ctx *gin.Context) (*url.URL, map[string]string, error) {
@@ -60,21 +61,12 @@ func getProxyTarget(ctx *gin.Context) (*url.URL, map[string]string, error) {
 		return nil, nil, fmt.Errorf("sandbox container not found: %w", err)
 	}
 
-	var containerIP string
-	for _, network := range container.NetworkSettings.Networks {
-		containerIP = network.IPAddress
-		break
-	}
-
-	if containerIP == "" {
-		message := "no IP address found. Is the Sandbox started?"
-		ctx.Error(common_errors.NewBadRequestError(errors.New(message)))
-		return nil, nil, errors.New(message)
+	targetURL, err := runner.Docker.GetDaemonUrl(ctx.Request.Context(), container)
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(err))
+		return nil, nil, err
 	}
 
-	// Build the target URL
-	targetURL := fmt.Sprintf("http://%s:2280", containerIP)
-
 	// Get the wildcard path and normalize it
 	path := ctx.Param("path")
 
@@ -92,5 +84,41 @@ func getProxyTarget(ctx *gin.Context) (*url.URL, map[string]string, error) {
 		return nil, nil, fmt.Errorf("failed to parse target URL: %w", err)
 	}
 
-	return target, nil, nil
+	// Only the runner may authenticate to the daemon
+	ctx.Request.Header.Del(common.DAEMON_AUTH_TOKEN_HEADER)
+
+	extraHeaders := map[string]string{}
+	if authToken := runner.Docker.GetDaemonAuthToken(ctx.Request.Context(), container); authToken != "" {
+		extraHeaders[common.DAEMON_AUTH_TOKEN_HEADER] = authToken
+	}
+
+	return target, extraHeaders, nil
+}
+
+// ProxySyncRequest proxies the workspace sync protocol to the daemon of a sandbox
+//
+//	@Tags			toolbox
+//	@Summary		Proxy workspace sync requests
+//	@Description	Forwards the request to the sync endpoints of the sandbox daemon, which sync a local directory with a directory of the sandbox
+//	@Param			sandboxId	path		string	true	"Sandbox ID"
+//	@Param			path		path		string	true	"Sync path, e.g. manifest, chunks/{hash} or apply"
+//	@Success		200			{object}	any		"Proxied response"
+//	@Failure		400			{object}	string	"Bad request"
+//	@Failure		404			{object}	string	"Sandbox container not found"
+//	@Failure		500			{object}	string	"Internal server error"
+//	@Router			/sandboxes/{sandboxId}/sync/{path} [get]
+//	@Router			/sandboxes/{sandboxId}/sync/{path} [post]
+//	@Router			/sandboxes/{sandboxId}/sync/{path} [put]
+func ProxySyncRequest(ctx *gin.Context) {
+	proxy.NewProxyRequestHandlerWithTransport(getSyncProxyTarget, nil, runner.GetInstance(nil).Docker.DaemonTransport())(ctx)
+}
+
+func getSyncProxyTarget(ctx *gin.Context) (*url.URL, map[string]string, error) {
+	target, extraHeaders, err := getProxyTarget(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target.Path = "/sync" + target.Path
+	return target, extraHeaders, nil
 }
@@ -0,0 +1,60 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/runner"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// GetOrphanReport godoc
+//
+//	@Tags			reaper
+//	@Summary		Get orphaned resources
+//	@Description	List the containers, volumes, networks, network rule chains and images on the runner that aren't associated with a known sandbox, without removing them
+//	@Produce		json
+//	@Success		200	{object}	dto.OrphanReportDTO
+//	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Failure		500	{object}	common_errors.ErrorResponse
+//	@Router			/reaper/report [get]
+//
+//	@id				GetOrphanReport
+func GetOrphanReport(ctx *gin.Context) {
+	runner := runner.GetInstance(nil)
+
+	ctx.JSON(http.StatusOK, runner.Reaper.Report(ctx.Request.Context()))
+}
+
+// ReapOrphans godoc
+//
+//	@Tags			reaper
+//	@Summary		Remove orphaned resources
+//	@Description	Remove the orphaned resources of the given kinds, or of those of the runner policy. Nothing is removed if the reaper is configured as a dry run, unless the request overrides it.
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.ReapOrphansDTO	true	"Kinds of resources to remove"
+//	@Success		200		{object}	dto.OrphanReportDTO
+//	@Failure		400		{object}	common_errors.ErrorResponse
+//	@Failure		401		{object}	common_errors.ErrorResponse
+//	@Failure		500		{object}	common_errors.ErrorResponse
+//	@Router			/reaper/run [post]
+//
+//	@id				ReapOrphans
+func ReapOrphans(ctx *gin.Context) {
+	var reapDto dto.ReapOrphansDTO
+	err := ctx.ShouldBindJSON(&reapDto)
+	if err != nil {
+		ctx.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	runner := runner.GetInstance(nil)
+
+	ctx.JSON(http.StatusOK, runner.Reaper.Reap(ctx.Request.Context(), reapDto.Kinds, reapDto.DryRun))
+}
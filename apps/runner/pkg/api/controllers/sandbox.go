@@ -4,17 +4,115 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/audit"
 	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/docker"
 	"github.com/daytonaio/runner/pkg/models/enums"
 	"github.com/daytonaio/runner/pkg/runner"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
 	"github.com/gin-gonic/gin"
 
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
+// List godoc
+//
+//	@Tags			sandbox
+//	@Summary		List sandboxes
+//	@Description	List the sandboxes on the runner with their state, oldest first by default. All sandboxes are listed if no limit is set, a page of them otherwise, with the cursor of the next page in the X-Next-Cursor header.
+//	@Produce		json
+//	@Param			state	query		[]string	false	"Only list the sandboxes in these states"	collectionFormat(multi)
+//	@Param			label	query		[]string	false	"Only list the sandboxes with these container labels, as key=value"	collectionFormat(multi)
+//	@Param			sort	query		string		false	"Sort key"	Enums(createdAt, id)
+//	@Param			order	query		string		false	"Sort order"	Enums(asc, desc)
+//	@Param			limit	query		int			false	"Maximum number of sandboxes, at most 1000"
+//	@Param			cursor	query		string		false	"Cursor of the page, from the X-Next-Cursor header of the previous page"
+//	@Param			fields	query		string		false	"Comma separated fields of the sandboxes to return, all fields if empty"
+//	@Param			If-None-Match	header	string	false	"ETag of a previous response, 304 is returned if the response is unchanged"
+//	@Success		200		{array}		dto.SandboxSummaryDTO
+//	@Header			200		{string}	X-Next-Cursor	"Cursor of the next page, unset on the last page"
+//	@Header			200		{string}	ETag	"ETag of the response"
+//	@Success		304
+//	@Failure		400		{object}	common_errors.ErrorResponse
+//	@Failure		401		{object}	common_errors.ErrorResponse
+//	@Failure		500		{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes [get]
+//
+//	@id				List
+func List(ctx *gin.Context) {
+	params, err := parseListParams(ctx, "createdAt", "id")
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
+	fields, err := parseFields[dto.SandboxSummaryDTO](ctx)
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
+	labels := map[string]string{}
+	for _, label := range ctx.QueryArray("label") {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || key == "" {
+			ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid label %q, must be key=value", label)))
+			return
+		}
+		labels[key] = value
+	}
+
+	states := ctx.QueryArray("state")
+
+	runner := runner.GetInstance(nil)
+
+	sandboxes, err := runner.Docker.ListSandboxes(ctx.Request.Context(), labels)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// Keeps the version of the states cache, which the ETag is derived from, in step with Docker
+	for _, sandbox := range sandboxes {
+		runner.StatesCache.SetSandboxState(ctx.Request.Context(), sandbox.Id, enums.SandboxState(sandbox.State))
+	}
+	version := runner.StatesCache.Version()
+
+	if len(states) > 0 {
+		sandboxes = slices.DeleteFunc(sandboxes, func(sandbox dto.SandboxSummaryDTO) bool {
+			return !slices.Contains(states, sandbox.State)
+		})
+	}
+
+	sandboxes = paginate(ctx, params, sandboxes, func(sandbox dto.SandboxSummaryDTO, sort string) string {
+		if sort == "id" {
+			return sandbox.Id
+		}
+		return sortableInt(sandbox.CreatedAt.UnixNano())
+	}, func(sandbox dto.SandboxSummaryDTO) string {
+		return sandbox.Id
+	})
+
+	response, err := selectFields(sandboxes, fields)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondWithETag(ctx, version, response)
+}
+
 // Create 			godoc
 //
 //	@Tags			sandbox
@@ -25,9 +123,12 @@ import (
 //	@Success		201	{object}	dto.StartSandboxResponse
 //	@Failure		400	{object}	common_errors.ErrorResponse
 //	@Failure		401	{object}	common_errors.ErrorResponse
+//	@Failure		403	{object}	common_errors.ErrorResponse
 //	@Failure		404	{object}	common_errors.ErrorResponse
 //	@Failure		409	{object}	common_errors.ErrorResponse
+//	@Failure		429	{object}	common_errors.ErrorResponse
 //	@Failure		500	{object}	common_errors.ErrorResponse
+//	@Failure		507	{object}	common_errors.ErrorResponse
 //	@Router			/sandboxes [post]
 //
 //	@id				Create
@@ -41,7 +142,28 @@ func Create(ctx *gin.Context) {
 
 	runner := runner.GetInstance(nil)
 
-	_, daemonVersion, err := runner.Docker.Create(ctx.Request.Context(), createSandboxDto)
+	err = runner.Admission.AdmitCreate(ctx.Request.Context(), createSandboxDto)
+	if err != nil {
+		common.ContainerOperationCount.WithLabelValues("create", string(common.PrometheusOperationStatusFailure)).Inc()
+		ctx.Error(err)
+		return
+	}
+
+	releaseQuota, err := runner.OrganizationQuota.ReserveCreate(ctx.Request.Context(), createSandboxDto)
+	if err != nil {
+		common.ContainerOperationCount.WithLabelValues("create", string(common.PrometheusOperationStatusFailure)).Inc()
+		ctx.Error(err)
+		return
+	}
+	defer releaseQuota()
+
+	lockCtx, release, ok := lockSandbox(ctx, createSandboxDto.Id, sandboxlock.OperationCreate)
+	if !ok {
+		return
+	}
+	defer release()
+
+	_, daemonVersion, err := runner.Docker.Create(lockCtx, createSandboxDto)
 	if err != nil {
 		runner.StatesCache.SetSandboxState(ctx, createSandboxDto.Id, enums.SandboxStateError)
 		common.ContainerOperationCount.WithLabelValues("create", string(common.PrometheusOperationStatusFailure)).Inc()
@@ -51,9 +173,21 @@ func Create(ctx *gin.Context) {
 
 	common.ContainerOperationCount.WithLabelValues("create", string(common.PrometheusOperationStatusSuccess)).Inc()
 
-	ctx.JSON(http.StatusCreated, dto.StartSandboxResponse{
+	response := dto.StartSandboxResponse{
 		DaemonVersion: daemonVersion,
-	})
+	}
+
+	if createSandboxDto.Devcontainer != nil {
+		response.Devcontainer, err = runner.Docker.ApplyDevcontainer(lockCtx, createSandboxDto.Id, createSandboxDto.OsUser, *createSandboxDto.Devcontainer, func(progress dto.DevcontainerProgressDTO) {
+			log.Infof("Provisioning sandbox %s from devcontainer.json: %s %s", createSandboxDto.Id, progress.Step, progress.Detail)
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, response)
 }
 
 // Destroy 			godoc
@@ -77,7 +211,13 @@ func Destroy(ctx *gin.Context) {
 
 	runner := runner.GetInstance(nil)
 
-	err := runner.Docker.Destroy(ctx.Request.Context(), sandboxId)
+	lockCtx, release, ok := lockSandbox(ctx, sandboxId, sandboxlock.OperationDestroy)
+	if !ok {
+		return
+	}
+	defer release()
+
+	err := runner.Docker.Destroy(lockCtx, sandboxId)
 	if err != nil {
 		runner.StatesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateError)
 		common.ContainerOperationCount.WithLabelValues("destroy", string(common.PrometheusOperationStatusFailure)).Inc()
@@ -119,7 +259,14 @@ func CreateBackup(ctx *gin.Context) {
 
 	runner := runner.GetInstance(nil)
 
-	err = runner.Docker.CreateBackupAsync(ctx.Request.Context(), sandboxId, createBackupDTO)
+	// The backup outlives the request, it holds the lock until it completes
+	lockCtx, release, err := runner.Locks.Acquire(context.WithoutCancel(ctx.Request.Context()), sandboxId, sandboxlock.OperationBackup)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = runner.Docker.CreateBackupAsync(lockCtx, sandboxId, createBackupDTO, release)
 	if err != nil {
 		runner.StatesCache.SetBackupState(ctx, sandboxId, enums.BackupStateFailed, err)
 		ctx.Error(err)
@@ -158,7 +305,13 @@ func Resize(ctx *gin.Context) {
 
 	runner := runner.GetInstance(nil)
 
-	err = runner.Docker.Resize(ctx.Request.Context(), sandboxId, resizeDto)
+	lockCtx, release, ok := lockSandbox(ctx, sandboxId, sandboxlock.OperationResize)
+	if !ok {
+		return
+	}
+	defer release()
+
+	err = runner.Docker.Resize(lockCtx, sandboxId, resizeDto)
 	if err != nil {
 		runner.StatesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateError)
 		common.ContainerOperationCount.WithLabelValues("resize", string(common.PrometheusOperationStatusFailure)).Inc()
@@ -171,6 +324,92 @@ func Resize(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, "Sandbox resized")
 }
 
+// Clone 			godoc
+//
+//	@Tags			sandbox
+//	@Summary		Clone sandbox
+//	@Description	Create and start a copy of a sandbox from a snapshot of its filesystem and configuration.
+//	@Description	Volumes are shared with the source sandbox.
+//	@Produce		json
+//	@Param			sandboxId	path		string				true	"Sandbox ID"
+//	@Param			sandbox		body		dto.CloneSandboxDTO	true	"Clone sandbox"
+//	@Success		201			{object}	dto.StartSandboxResponse
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		403			{object}	common_errors.ErrorResponse
+//	@Failure		404			{object}	common_errors.ErrorResponse
+//	@Failure		409			{object}	common_errors.ErrorResponse
+//	@Failure		429			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Failure		507			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/clone [post]
+//
+//	@id				Clone
+func Clone(ctx *gin.Context) {
+	var cloneDto dto.CloneSandboxDTO
+	err := ctx.ShouldBindJSON(&cloneDto)
+	if err != nil {
+		ctx.Error(common_errors.NewInvalidBodyRequestError(err))
+		return
+	}
+
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+
+	// A clone takes up the resources of a new sandbox, so it is admitted like a create
+	createSandboxDto, err := runner.Docker.GetCloneCreateDto(ctx.Request.Context(), sandboxId, cloneDto)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = runner.Admission.AdmitCreate(ctx.Request.Context(), createSandboxDto)
+	if err != nil {
+		common.ContainerOperationCount.WithLabelValues("clone", string(common.PrometheusOperationStatusFailure)).Inc()
+		ctx.Error(err)
+		return
+	}
+
+	releaseQuota, err := runner.OrganizationQuota.ReserveCreate(ctx.Request.Context(), createSandboxDto)
+	if err != nil {
+		common.ContainerOperationCount.WithLabelValues("clone", string(common.PrometheusOperationStatusFailure)).Inc()
+		ctx.Error(err)
+		return
+	}
+	defer releaseQuota()
+
+	// The source is always locked before the clone
+	sourceCtx, releaseSource, ok := lockSandbox(ctx, sandboxId, sandboxlock.OperationClone)
+	if !ok {
+		return
+	}
+	defer releaseSource()
+
+	_, release, ok := lockSandbox(ctx, cloneDto.Id, sandboxlock.OperationCreate)
+	if !ok {
+		return
+	}
+	defer release()
+
+	daemonVersion, err := runner.Docker.Clone(sourceCtx, sandboxId, cloneDto)
+	if err != nil {
+		// A conflict means the ID belongs to another sandbox
+		if !common_errors.IsConflictError(err) {
+			runner.StatesCache.SetSandboxState(ctx, cloneDto.Id, enums.SandboxStateError)
+		}
+		common.ContainerOperationCount.WithLabelValues("clone", string(common.PrometheusOperationStatusFailure)).Inc()
+		ctx.Error(err)
+		return
+	}
+
+	common.ContainerOperationCount.WithLabelValues("clone", string(common.PrometheusOperationStatusSuccess)).Inc()
+
+	ctx.JSON(http.StatusCreated, dto.StartSandboxResponse{
+		DaemonVersion: daemonVersion,
+	})
+}
+
 // UpdateNetworkSettings godoc
 //
 //	@Tags			sandbox
@@ -199,7 +438,13 @@ func UpdateNetworkSettings(ctx *gin.Context) {
 	sandboxId := ctx.Param("sandboxId")
 	runner := runner.GetInstance(nil)
 
-	err = runner.Docker.UpdateNetworkSettings(ctx.Request.Context(), sandboxId, updateNetworkSettingsDto)
+	lockCtx, release, ok := lockSandbox(ctx, sandboxId, sandboxlock.OperationNetworkSettings)
+	if !ok {
+		return
+	}
+	defer release()
+
+	err = runner.Docker.UpdateNetworkSettings(lockCtx, sandboxId, updateNetworkSettingsDto)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -273,7 +518,13 @@ func Start(ctx *gin.Context) {
 		return
 	}
 
-	daemonVersion, err := runner.Docker.Start(ctx.Request.Context(), sandboxId, metadata)
+	lockCtx, release, ok := lockSandbox(ctx, sandboxId, sandboxlock.OperationStart)
+	if !ok {
+		return
+	}
+	defer release()
+
+	daemonVersion, err := runner.Docker.Start(lockCtx, sandboxId, metadata)
 
 	if err != nil {
 		runner.StatesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateError)
@@ -286,6 +537,54 @@ func Start(ctx *gin.Context) {
 	})
 }
 
+// UpgradeDaemon godoc
+//
+//	@Tags			sandbox
+//	@Summary		Upgrade sandbox daemon
+//	@Description	Replace the daemon of a running sandbox with the version embedded in the runner, without restarting the sandbox
+//	@Produce		json
+//	@Param			sandboxId	path		string					true	"Sandbox ID"
+//	@Param			upgrade		body		dto.UpgradeDaemonDTO	false	"Upgrade options"
+//	@Success		200			{object}	dto.UpgradeDaemonResponse	"Daemon upgraded"
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		404			{object}	common_errors.ErrorResponse
+//	@Failure		409			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/daemon/upgrade [post]
+//
+//	@id				UpgradeDaemon
+func UpgradeDaemon(ctx *gin.Context) {
+	var upgradeDto dto.UpgradeDaemonDTO
+	if ctx.Request.ContentLength != 0 {
+		err := ctx.ShouldBindJSON(&upgradeDto)
+		if err != nil {
+			ctx.Error(common_errors.NewInvalidBodyRequestError(err))
+			return
+		}
+	}
+
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+
+	lockCtx, release, ok := lockSandbox(ctx, sandboxId, sandboxlock.OperationDaemonUpgrade)
+	if !ok {
+		return
+	}
+	defer release()
+
+	daemonVersion, err := runner.Docker.UpgradeDaemon(lockCtx, sandboxId, upgradeDto)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.UpgradeDaemonResponse{
+		DaemonVersion: daemonVersion,
+	})
+}
+
 // Stop 			godoc
 //
 //	@Tags			sandbox
@@ -307,7 +606,13 @@ func Stop(ctx *gin.Context) {
 
 	runner := runner.GetInstance(nil)
 
-	err := runner.Docker.Stop(ctx.Request.Context(), sandboxId)
+	lockCtx, release, ok := lockSandbox(ctx, sandboxId, sandboxlock.OperationStop)
+	if !ok {
+		return
+	}
+	defer release()
+
+	err := runner.Docker.Stop(lockCtx, sandboxId)
 	if err != nil {
 		runner.StatesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateError)
 		ctx.Error(err)
@@ -324,7 +629,11 @@ func Stop(ctx *gin.Context) {
 //	@Description	Get sandbox info
 //	@Produce		json
 //	@Param			sandboxId	path		string				true	"Sandbox ID"
-//	@Success		200			{object}	SandboxInfoResponse	"Sandbox info"
+//	@Param			fields		query		string				false	"Comma separated fields to return, all fields if empty"
+//	@Param			If-None-Match	header	string			false	"ETag of a previous response, 304 is returned if the response is unchanged"
+//	@Success		200			{object}	dto.SandboxInfoResponse	"Sandbox info"
+//	@Header			200			{string}	ETag	"ETag of the response"
+//	@Success		304
 //	@Failure		400			{object}	common_errors.ErrorResponse
 //	@Failure		401			{object}	common_errors.ErrorResponse
 //	@Failure		404			{object}	common_errors.ErrorResponse
@@ -336,32 +645,347 @@ func Stop(ctx *gin.Context) {
 func Info(ctx *gin.Context) {
 	sandboxId := ctx.Param("sandboxId")
 
+	fields, err := parseFields[dto.SandboxInfoResponse](ctx)
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(err))
+		return
+	}
+
 	runner := runner.GetInstance(nil)
 
 	info := runner.SandboxService.GetSandboxStatesInfo(ctx.Request.Context(), sandboxId)
 
 	var daemonVersion *string
-	if info.SandboxState == enums.SandboxStateStarted {
+	daemonUnreachable := false
+	// Asking the daemon for its version is skipped if the caller only wants the states
+	if info.SandboxState == enums.SandboxStateStarted && (fields.has("daemonVersion") || fields.has("daemonUnreachable")) {
 		daemonVersionStr, err := runner.Docker.GetDaemonVersion(ctx.Request.Context(), sandboxId)
 		if err == nil {
 			daemonVersion = &daemonVersionStr
+		} else if errors.Is(err, docker.ErrDaemonUnreachable) {
+			daemonUnreachable = true
 		}
 	}
 
-	ctx.JSON(http.StatusOK, SandboxInfoResponse{
-		State:         info.SandboxState,
-		BackupState:   info.BackupState,
-		BackupError:   info.BackupErrorReason,
-		DaemonVersion: daemonVersion,
-	})
+	response, err := selectFields(dto.SandboxInfoResponse{
+		State:             info.SandboxState,
+		BackupState:       info.BackupState,
+		BackupError:       info.BackupErrorReason,
+		DaemonVersion:     daemonVersion,
+		DaemonUnreachable: daemonUnreachable,
+	}, fields)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondWithETag(ctx, info.Version, response)
+}
+
+// GetStorageUsage godoc
+//
+//	@Tags			sandbox
+//	@Summary		Get sandbox storage usage
+//	@Description	Get the storage used by the sandbox against its quota, along with the usage of its volumes. The usage is sampled periodically.
+//	@Produce		json
+//	@Param			sandboxId	path		string	true	"Sandbox ID"
+//	@Success		200			{object}	dto.SandboxStorageUsageDTO
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		404			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/storage [get]
+//
+//	@id				GetStorageUsage
+func GetStorageUsage(ctx *gin.Context) {
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+
+	usage, err := runner.StorageUsage.GetSandboxStorageUsage(ctx.Request.Context(), sandboxId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, usage)
+}
+
+// GetStats godoc
+//
+//	@Tags			sandbox
+//	@Summary		Get sandbox stats
+//	@Description	Get the CPU, memory and network usage of the sandbox, in total and per container of the sandbox and its sidecars
+//	@Produce		json
+//	@Param			sandboxId	path		string	true	"Sandbox ID"
+//	@Success		200			{object}	dto.SandboxStatsDTO
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		404			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/stats [get]
+//
+//	@id				GetStats
+func GetStats(ctx *gin.Context) {
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+
+	stats, err := runner.Docker.GetSandboxStats(ctx.Request.Context(), sandboxId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
+// GetProvisioningStatus godoc
+//
+//	@Tags			sandbox
+//	@Summary		Get sandbox provisioning status
+//	@Description	Get the status of the provisioning steps the daemon runs on the first boot of the sandbox
+//	@Produce		json
+//	@Param			sandboxId	path		string	true	"Sandbox ID"
+//	@Success		200			{object}	dto.ProvisioningStatusDTO
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		404			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/provisioning [get]
+//
+//	@id				GetProvisioningStatus
+func GetProvisioningStatus(ctx *gin.Context) {
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+
+	status, err := runner.Docker.GetProvisioningStatus(ctx.Request.Context(), sandboxId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+// GetDotfilesStatus godoc
+//
+//	@Tags			sandbox
+//	@Summary		Get sandbox dotfiles status
+//	@Description	Get the status of the last time the daemon applied the dotfiles of the user
+//	@Produce		json
+//	@Param			sandboxId	path		string	true	"Sandbox ID"
+//	@Success		200			{object}	dto.DotfilesStatusDTO
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		404			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/dotfiles [get]
+//
+//	@id				GetDotfilesStatus
+func GetDotfilesStatus(ctx *gin.Context) {
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+
+	status, err := runner.Docker.GetDotfilesStatus(ctx.Request.Context(), sandboxId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+// ApplyDotfiles godoc
+//
+//	@Tags			sandbox
+//	@Summary		Apply sandbox dotfiles
+//	@Description	Have the daemon apply the dotfiles of the sandbox again, or the given ones instead. The dotfiles are applied in the background, their progress is reported by the dotfiles status.
+//	@Param			sandboxId	path	string			true	"Sandbox ID"
+//	@Param			dotfiles	body	dto.DotfilesDTO	false	"Dotfiles to apply instead"
+//	@Success		202			"Dotfiles are being applied"
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		404			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/dotfiles/apply [post]
+//
+//	@id				ApplyDotfiles
+func ApplyDotfiles(ctx *gin.Context) {
+	var dotfiles *dto.DotfilesDTO
+	if ctx.Request.ContentLength != 0 {
+		dotfiles = &dto.DotfilesDTO{}
+		err := ctx.ShouldBindJSON(dotfiles)
+		if err != nil {
+			ctx.Error(common_errors.NewInvalidBodyRequestError(err))
+			return
+		}
+	}
+
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+
+	err := runner.Docker.ApplyDotfiles(ctx.Request.Context(), sandboxId, dotfiles)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+// GetBucketMounts godoc
+//
+//	@Tags			sandbox
+//	@Summary		Get sandbox bucket mounts
+//	@Description	Get the buckets the runner mounts into the sandbox and the health of their mounts
+//	@Produce		json
+//	@Param			sandboxId	path		string	true	"Sandbox ID"
+//	@Success		200			{array}		dto.BucketMountStatusDTO
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		404			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/bucket-mounts [get]
+//
+//	@id				GetBucketMounts
+func GetBucketMounts(ctx *gin.Context) {
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+
+	mounts, err := runner.Docker.GetBucketMounts(sandboxId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mounts)
 }
 
-type SandboxInfoResponse struct {
-	State         enums.SandboxState `json:"state"`
-	BackupState   enums.BackupState  `json:"backupState"`
-	BackupError   *string            `json:"backupError,omitempty"`
-	DaemonVersion *string            `json:"daemonVersion,omitempty"`
-} //	@name	SandboxInfoResponse
+// GetAuditEvents godoc
+//
+//	@Tags			sandbox
+//	@Summary		Get sandbox audit events
+//	@Description	Get the processes the sandbox executed and the outbound connections it opened, oldest first.
+//	@Description	With follow, events are streamed as newline delimited JSON as they are recorded.
+//	@Produce		json
+//	@Param			sandboxId	path		string	true	"Sandbox ID"
+//	@Param			type		query		string	false	"Only exec or connect events"
+//	@Param			since		query		string	false	"Only events recorded since, RFC 3339"
+//	@Param			follow		query		boolean	false	"Stream events as they are recorded"
+//	@Success		200			{array}		dto.AuditEventDTO
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/audit [get]
+//
+//	@id				GetAuditEvents
+func GetAuditEvents(ctx *gin.Context) {
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+	if runner.Audit == nil {
+		ctx.Error(common_errors.NewBadRequestError(errors.New("auditing is not enabled on the runner")))
+		return
+	}
+
+	filter := audit.Filter{
+		SandboxId: sandboxId,
+		Type:      ctx.Query("type"),
+	}
+	if filter.Type != "" && filter.Type != audit.EventTypeExec && filter.Type != audit.EventTypeConnect {
+		ctx.Error(common_errors.NewBadRequestError(errors.New("type must be exec or connect")))
+		return
+	}
+	if since := ctx.Query("since"); since != "" {
+		var err error
+		filter.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid since: %w", err)))
+			return
+		}
+	}
+
+	if ctx.Query("follow") != "true" {
+		events := []dto.AuditEventDTO{}
+		for _, event := range runner.Audit.Query(filter) {
+			events = append(events, auditEventToDto(event))
+		}
+		ctx.JSON(http.StatusOK, events)
+		return
+	}
+
+	events, live := runner.Audit.Subscribe(ctx.Request.Context(), filter)
+
+	ctx.Header("Content-Type", "application/x-ndjson")
+	ctx.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(ctx.Writer)
+	for _, event := range events {
+		if err := encoder.Encode(auditEventToDto(event)); err != nil {
+			return
+		}
+	}
+	ctx.Writer.Flush()
+
+	for event := range live {
+		if err := encoder.Encode(auditEventToDto(event)); err != nil {
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+func auditEventToDto(event audit.Event) dto.AuditEventDTO {
+	return dto.AuditEventDTO{
+		Time:        event.Time,
+		Type:        event.Type,
+		Pid:         event.Pid,
+		Exe:         event.Exe,
+		Args:        event.Args,
+		Protocol:    event.Protocol,
+		Destination: event.Destination,
+	}
+}
+
+// GetEgressTraffic godoc
+//
+//	@Tags			sandbox
+//	@Summary		Get sandbox egress traffic
+//	@Description	Get the bytes the sandbox sent and received per domain through the runner egress proxy
+//	@Produce		json
+//	@Param			sandboxId	path		string	true	"Sandbox ID"
+//	@Success		200			{array}		dto.EgressTrafficDTO
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/sandboxes/{sandboxId}/egress [get]
+//
+//	@id				GetEgressTraffic
+func GetEgressTraffic(ctx *gin.Context) {
+	sandboxId := ctx.Param("sandboxId")
+
+	runner := runner.GetInstance(nil)
+	if runner.EgressProxy == nil {
+		ctx.Error(common_errors.NewBadRequestError(errors.New("egress proxy is not enabled on the runner")))
+		return
+	}
+
+	traffic := []dto.EgressTrafficDTO{}
+	for _, stats := range runner.EgressProxy.GetTraffic(sandboxId) {
+		traffic = append(traffic, dto.EgressTrafficDTO{
+			Domain:        stats.Domain,
+			SentBytes:     stats.SentBytes,
+			ReceivedBytes: stats.ReceivedBytes,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, traffic)
+}
 
 // Recover godoc
 //
@@ -392,7 +1016,13 @@ func Recover(ctx *gin.Context) {
 	sandboxId := ctx.Param("sandboxId")
 	runner := runner.GetInstance(nil)
 
-	err = runner.Docker.RecoverSandbox(ctx.Request.Context(), sandboxId, recoverDto)
+	lockCtx, release, ok := lockSandbox(ctx, sandboxId, sandboxlock.OperationRecover)
+	if !ok {
+		return
+	}
+	defer release()
+
+	err = runner.Docker.RecoverSandbox(lockCtx, sandboxId, recoverDto)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -422,7 +1052,13 @@ func RemoveDestroyed(ctx *gin.Context) {
 
 	runner := runner.GetInstance(nil)
 
-	err := runner.SandboxService.RemoveDestroyedSandbox(ctx.Request.Context(), sandboxId)
+	lockCtx, release, ok := lockSandbox(ctx, sandboxId, sandboxlock.OperationDestroy)
+	if !ok {
+		return
+	}
+	defer release()
+
+	err := runner.SandboxService.RemoveDestroyedSandbox(lockCtx, sandboxId)
 	if err != nil {
 		if !common_errors.IsNotFoundError(err) {
 			ctx.Error(err)
@@ -460,3 +1096,14 @@ func IsRecoverable(ctx *gin.Context) {
 		Recoverable: recoverable,
 	})
 }
+
+// lockSandbox locks a sandbox for the operation of a request, which runs with the returned context
+// and releases the lock when done. The request fails if the lock can't be acquired.
+func lockSandbox(ctx *gin.Context, sandboxId string, operation sandboxlock.Operation) (context.Context, func(), bool) {
+	lockCtx, release, err := runner.GetInstance(nil).Locks.Acquire(ctx.Request.Context(), sandboxId, operation)
+	if err != nil {
+		ctx.Error(err)
+		return nil, nil, false
+	}
+	return lockCtx, release, true
+}
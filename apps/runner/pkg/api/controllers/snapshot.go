@@ -15,6 +15,7 @@ import (
 
 	"github.com/daytonaio/runner/cmd/runner/config"
 	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/docker"
 	"github.com/daytonaio/runner/pkg/runner"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
@@ -154,7 +155,7 @@ func BuildSnapshot(ctx *gin.Context) {
 //	@Description	Check if a specified snapshot exists locally
 //	@Produce		json
 //	@Param			snapshot	query		string	true	"Snapshot name and tag"	example:"nginx:latest"
-//	@Success		200			{object}	SnapshotExistsResponse
+//	@Success		200			{object}	dto.SnapshotExistsResponse
 //	@Failure		400			{object}	common_errors.ErrorResponse
 //	@Failure		401			{object}	common_errors.ErrorResponse
 //	@Failure		404			{object}	common_errors.ErrorResponse
@@ -178,7 +179,7 @@ func SnapshotExists(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, SnapshotExistsResponse{
+	ctx.JSON(http.StatusOK, dto.SnapshotExistsResponse{
 		Exists: exists,
 	})
 }
@@ -217,10 +218,6 @@ func RemoveSnapshot(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, "Snapshot removed successfully")
 }
 
-type SnapshotExistsResponse struct {
-	Exists bool `json:"exists" example:"true"`
-} //	@name	SnapshotExistsResponse
-
 // GetBuildLogs godoc
 //
 //	@Tags			snapshots
@@ -327,6 +324,65 @@ func GetBuildLogs(ctx *gin.Context) {
 	}
 }
 
+// ExportSnapshot godoc
+//
+//	@Tags			snapshots
+//	@Summary		Export a snapshot
+//	@Description	Stream a snapshot as a docker save archive that can be imported with docker load
+//	@Produce		application/octet-stream
+//	@Param			snapshot	query		string	true	"Snapshot name and tag"	example:"nginx:latest"
+//	@Param			compression	query		string	false	"Compression of the archive, defaults to the runner compression"	Enums(none, gzip, zstd)
+//	@Success		200			{file}		binary
+//	@Failure		400			{object}	common_errors.ErrorResponse
+//	@Failure		401			{object}	common_errors.ErrorResponse
+//	@Failure		404			{object}	common_errors.ErrorResponse
+//	@Failure		500			{object}	common_errors.ErrorResponse
+//	@Router			/snapshots/export [get]
+//
+//	@id				ExportSnapshot
+func ExportSnapshot(ctx *gin.Context) {
+	snapshot := ctx.Query("snapshot")
+	if snapshot == "" {
+		ctx.Error(common_errors.NewBadRequestError(errors.New("snapshot parameter is required")))
+		return
+	}
+
+	runner := runner.GetInstance(nil)
+
+	compression := runner.Docker.DefaultCompression()
+	if name := ctx.Query("compression"); name != "" {
+		var err error
+		compression, err = docker.ParseCompression(name)
+		if err != nil {
+			ctx.Error(common_errors.NewBadRequestError(err))
+			return
+		}
+	}
+
+	exists, err := runner.Docker.ImageExists(ctx.Request.Context(), snapshot, false)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if !exists {
+		ctx.Error(common_errors.NewNotFoundError(fmt.Errorf("snapshot not found: %s", snapshot)))
+		return
+	}
+
+	fileName := strings.NewReplacer("/", "_", ":", "_").Replace(snapshot) + compression.Extension()
+	ctx.Header("Content-Type", compression.ContentType())
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Status(http.StatusOK)
+
+	// The status is already sent, so a failed export leaves the client with a truncated archive
+	err = runner.Docker.ExportImage(ctx.Request.Context(), snapshot, compression, ctx.Writer)
+	if err != nil {
+		log.Errorf("Failed to export snapshot %s: %v", snapshot, err)
+		ctx.Abort()
+	}
+}
+
 // GetSnapshotInfo godoc
 //
 //	@Tags			snapshots
@@ -0,0 +1,35 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package dto
+
+import "time"
+
+const (
+	// The sandbox used most of its CPU quota for longer than the detection window
+	AbuseSignalCpu = "cpu"
+	// The sandbox resolved the domain of a known mining pool
+	AbuseSignalPoolDomain = "pool_domain"
+	// A process of the sandbox has the name of a known miner
+	AbuseSignalProcess = "process"
+)
+
+type AbuseEventDTO struct {
+	SandboxId      string `json:"sandboxId"`
+	OrganizationId string `json:"organizationId,omitempty"`
+	// One of cpu, pool_domain and process
+	Signal string `json:"signal"`
+	// The CPU usage, domain or process name the signal was raised for
+	Detail string `json:"detail"`
+	// Whether the sandbox was suspended because of the signals raised for it
+	Suspended bool      `json:"suspended"`
+	Time      time.Time `json:"time"`
+} //	@name	AbuseEventDTO
+
+type SandboxProcessDTO struct {
+	Pid     int32  `json:"pid"`
+	Name    string `json:"name"`
+	Command string `json:"command"`
+	// CPU usage, 100 equals one fully used core
+	CpuPercent float64 `json:"cpuPercent"`
+} //	@name	SandboxProcessDTO
@@ -0,0 +1,73 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package dto
+
+import "time"
+
+type SandboxSummaryDTO struct {
+	Id       string `json:"id"`
+	State    string `json:"state"`
+	Snapshot string `json:"snapshot"`
+	// Storage quota in GB
+	StorageQuota int64     `json:"storageQuota"`
+	CreatedAt    time.Time `json:"createdAt"`
+} //	@name	SandboxSummaryDTO
+
+type JobDTO struct {
+	// Increases with every update of a job, to list the jobs updated since a previous listing
+	Sequence     int64      `json:"sequence"`
+	Id           string     `json:"id"`
+	Type         string     `json:"type"`
+	ResourceType string     `json:"resourceType,omitempty"`
+	ResourceId   string     `json:"resourceId,omitempty"`
+	Status       string     `json:"status"`
+	Error        string     `json:"error,omitempty"`
+	StartedAt    time.Time  `json:"startedAt"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	// Progress of the data copy of jobs that recreate a sandbox container
+	Progress *JobProgressDTO `json:"progress,omitempty"`
+} //	@name	JobDTO
+
+type JobProgressDTO struct {
+	BytesTotal  int64 `json:"bytesTotal"`
+	BytesCopied int64 `json:"bytesCopied"`
+	FilesTotal  int64 `json:"filesTotal"`
+	FilesCopied int64 `json:"filesCopied"`
+	// Estimated seconds until the copy completes, 0 if unknown
+	EtaSeconds int64 `json:"etaSeconds"`
+} //	@name	JobProgressDTO
+
+type DrainStatusDTO struct {
+	// Whether the runner rejects new sandboxes
+	Draining bool `json:"draining"`
+} //	@name	DrainStatusDTO
+
+type NetRulesChainDTO struct {
+	Table string   `json:"table"`
+	Chain string   `json:"chain"`
+	Rules []string `json:"rules"`
+} //	@name	NetRulesChainDTO
+
+type ProfilingSamplingDTO struct {
+	// On average 1/n of the mutex contention events are sampled, 0 disables the mutex profile
+	MutexProfileFraction int `json:"mutexProfileFraction" validate:"min=0"`
+	// On average one blocking event per n nanoseconds spent blocked is sampled, 0 disables the block profile
+	BlockProfileRate int `json:"blockProfileRate" validate:"min=0"`
+} //	@name	ProfilingSamplingDTO
+
+type FaultDTO struct {
+	Id string `json:"id"`
+	// Operation the fault is injected into
+	Point string `json:"point" validate:"required,oneof=docker.pull docker.inspect docker.create docker.start daemon.wait executor.job"`
+	// Only inject the fault into operations on this target, e.g. a sandbox ID, image or job type
+	Target string `json:"target,omitempty"`
+	// enospc, timeout or the message of the error returned by the operation, none if empty
+	Error string `json:"error,omitempty"`
+	// Delay of the operation before the error is returned, if any
+	DelayMs int64 `json:"delayMs,omitempty" validate:"min=0"`
+	// Probability of the fault being injected into a matching operation, always if 0
+	Probability float64 `json:"probability,omitempty" validate:"min=0,max=1"`
+	// Injections left before the fault is removed, unlimited if 0
+	Count int `json:"count,omitempty" validate:"min=0"`
+} //	@name	FaultDTO
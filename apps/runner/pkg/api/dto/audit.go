@@ -0,0 +1,20 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package dto
+
+import "time"
+
+type AuditEventDTO struct {
+	Time time.Time `json:"time"`
+	// exec or connect
+	Type string `json:"type"`
+	// PID of an executed process in the sandbox
+	Pid  int      `json:"pid,omitempty"`
+	Exe  string   `json:"exe,omitempty"`
+	Args []string `json:"args,omitempty"`
+	// tcp, udp or icmp for connections
+	Protocol string `json:"protocol,omitempty"`
+	// Address of the connection destination, host:port
+	Destination string `json:"destination,omitempty"`
+} //	@name	AuditEventDTO
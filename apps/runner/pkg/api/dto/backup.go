@@ -4,6 +4,20 @@
 package dto
 
 type CreateBackupDTO struct {
-	Registry RegistryDTO `json:"registry" validate:"required"`
-	Snapshot string      `json:"snapshot" validate:"required"`
+	Registry      RegistryDTO       `json:"registry" validate:"required"`
+	Snapshot      string            `json:"snapshot" validate:"required"`
+	CommitOptions *CommitOptionsDTO `json:"commitOptions,omitempty"`
+	// Snapshots the sidecars of the sandbox are backed up to by sidecar name, pushed to the same registry
+	SidecarSnapshots map[string]string `json:"sidecarSnapshots,omitempty"`
 } //	@name	CreateBackupDTO
+
+type CommitOptionsDTO struct {
+	// Flatten the filesystem into a single layer and drop the build history of the image
+	Squash bool `json:"squash,omitempty"`
+	// Drop environment variables that are not part of the sandbox's original image, such as
+	// variables and secrets set at sandbox creation. Implies squash.
+	ScrubEnv bool `json:"scrubEnv,omitempty"`
+	// Set the image creation time and file modification times to the Unix epoch, so that
+	// identical filesystems produce identical digests. Implies squash.
+	DeterministicTimestamps bool `json:"deterministicTimestamps,omitempty"`
+} //	@name	CommitOptionsDTO
@@ -0,0 +1,9 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package dto
+
+type ReloadConfigResponseDTO struct {
+	// Environment variables whose values changed
+	Changed []string `json:"changed"`
+} //	@name	ReloadConfigResponseDTO
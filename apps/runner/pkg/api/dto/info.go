@@ -18,4 +18,28 @@ type RunnerMetrics struct {
 type RunnerInfoResponseDTO struct {
 	Metrics    *RunnerMetrics `json:"metrics,omitempty"`
 	AppVersion string         `json:"appVersion"`
+	// Snapshotter images are lazily pulled with, empty if images are pulled in full
+	LazyPullSnapshotter string `json:"lazyPullSnapshotter,omitempty"`
+	// PEM encoded public key the secret env of sandboxes is encrypted to, empty if it is disabled
+	EnvEncryptionPublicKey string `json:"envEncryptionPublicKey,omitempty"`
+	// NUMA nodes pinned sandboxes are placed on, empty if CPU pinning is disabled
+	NumaNodes []NumaNodeDTO    `json:"numaNodes,omitempty"`
+	Devices   DeviceSupportDTO `json:"devices"`
+	// Nested Docker modes sandboxes may request
+	NestedDockerModes []string `json:"nestedDockerModes"`
 } //	@name	RunnerInfoResponseDTO
+
+// DeviceSupportDTO reports the host devices sandboxes may request
+type DeviceSupportDTO struct {
+	Kvm                  bool `json:"kvm"`
+	NestedVirtualization bool `json:"nestedVirtualization"`
+	Tun                  bool `json:"tun"`
+} //	@name	DeviceSupportDTO
+
+type NumaNodeDTO struct {
+	Id                 int   `json:"id"`
+	Cpus               int   `json:"cpus"`
+	FreeCpus           int   `json:"freeCpus"`
+	MemoryGiB          int64 `json:"memoryGiB"`
+	AllocatedMemoryGiB int64 `json:"allocatedMemoryGiB"`
+} //	@name	NumaNodeDTO
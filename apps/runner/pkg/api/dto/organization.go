@@ -0,0 +1,21 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package dto
+
+// OrganizationQuotaDTO holds per-organization limits enforced on this runner. Zero means unlimited.
+type OrganizationQuotaDTO struct {
+	MaxSandboxes int64 `json:"maxSandboxes" validate:"min=0"`
+	MaxCpu       int64 `json:"maxCpu" validate:"min=0"`
+	MaxMemory    int64 `json:"maxMemory" validate:"min=0"`
+	MaxDisk      int64 `json:"maxDisk" validate:"min=0"`
+} //	@name	OrganizationQuotaDTO
+
+type OrganizationUsageDTO struct {
+	OrganizationId string                `json:"organizationId"`
+	SandboxCount   int64                 `json:"sandboxCount"`
+	Cpu            float64               `json:"cpu"`
+	Memory         float64               `json:"memory"`
+	Disk           float64               `json:"disk"`
+	Quota          *OrganizationQuotaDTO `json:"quota,omitempty"`
+} //	@name	OrganizationUsageDTO
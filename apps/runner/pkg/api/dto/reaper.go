@@ -0,0 +1,52 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package dto
+
+import "time"
+
+const (
+	// Stopped sandbox containers the control plane doesn't know or destroyed
+	OrphanKindContainer = "container"
+	// Sidecars whose sandbox no longer exists
+	OrphanKindSidecar = "sidecar"
+	// Anonymous volumes no container uses
+	OrphanKindVolume = "volume"
+	// Mount directories of sandbox volumes no container uses
+	OrphanKindVolumeMount = "volume_mount"
+	// Networks the runner created that no container is connected to
+	OrphanKindNetwork = "network"
+	// Network rule chains of containers that no longer exist
+	OrphanKindChain = "chain"
+	// Images no container uses
+	OrphanKindImage = "image"
+	// Home volumes no container uses whose sandbox the control plane doesn't know
+	OrphanKindHomeVolume = "home_volume"
+)
+
+type OrphanResourceDTO struct {
+	// One of container, sidecar, volume, volume_mount, network, chain, image and home_volume
+	Kind   string `json:"kind"`
+	Id     string `json:"id"`
+	Reason string `json:"reason"`
+	// Size in bytes, for images
+	Size int64 `json:"size,omitempty"`
+	// Whether the resource was removed, false in dry runs
+	Removed bool   `json:"removed"`
+	Error   string `json:"error,omitempty"`
+} //	@name	OrphanResourceDTO
+
+type OrphanReportDTO struct {
+	DryRun    bool                `json:"dryRun"`
+	Resources []OrphanResourceDTO `json:"resources"`
+	// Kinds that couldn't be checked, with the reason
+	Errors      map[string]string `json:"errors,omitempty"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+} //	@name	OrphanReportDTO
+
+type ReapOrphansDTO struct {
+	// Kinds to remove, those of the runner policy if empty
+	Kinds []string `json:"kinds,omitempty" validate:"dive,oneof=container sidecar volume volume_mount network chain image home_volume"`
+	// Overrides the dry run of the runner policy
+	DryRun *bool `json:"dryRun,omitempty"`
+} //	@name	ReapOrphansDTO
@@ -18,6 +18,10 @@ type SnapshotDigestResponse struct {
 	SizeGB float64 `json:"sizeGB" example:"0.13"`
 } //	@name	SnapshotDigestResponse
 
+type SnapshotExistsResponse struct {
+	Exists bool `json:"exists" example:"true"`
+} //	@name	SnapshotExistsResponse
+
 type InspectSnapshotInRegistryRequestDTO struct {
 	Snapshot string       `json:"snapshot" validate:"required" example:"nginx:latest"`
 	Registry *RegistryDTO `json:"registry,omitempty"`
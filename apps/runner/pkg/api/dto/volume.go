@@ -3,8 +3,81 @@
 
 package dto
 
+import "time"
+
 type VolumeDTO struct {
 	VolumeId  string  `json:"volumeId"`
 	MountPath string  `json:"mountPath"`
 	Subpath   *string `json:"subpath,omitempty"`
 }
+
+// What the volume mount cleanup does with a volume mount directory
+const (
+	VolumeMountActionKeep   = "keep"
+	VolumeMountActionRemove = "remove"
+)
+
+type VolumeMountDTO struct {
+	Path     string  `json:"path"`
+	VolumeId string  `json:"volumeId,omitempty"`
+	Subpath  *string `json:"subpath,omitempty"`
+	// The sandbox the volume was last mounted for
+	SandboxId string     `json:"sandboxId,omitempty"`
+	MountedAt *time.Time `json:"mountedAt,omitempty"`
+	// One of keep and remove
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+} //	@name	VolumeMountDTO
+
+type VolumeMountCleanupDTO struct {
+	// Whether the cleanup only logs the mounts it would remove
+	DryRun             bool             `json:"dryRun"`
+	GracePeriodSeconds int64            `json:"gracePeriodSeconds"`
+	Mounts             []VolumeMountDTO `json:"mounts"`
+	GeneratedAt        time.Time        `json:"generatedAt"`
+} //	@name	VolumeMountCleanupDTO
+
+const (
+	BucketProviderS3  = "s3"
+	BucketProviderGCS = "gcs"
+)
+
+// BucketMountDTO is an S3 or GCS bucket the runner mounts into a sandbox with FUSE
+type BucketMountDTO struct {
+	// s3 or gcs
+	Provider string `json:"provider" validate:"required,oneof=s3 gcs"`
+	Bucket   string `json:"bucket" validate:"required"`
+	// Only mount the objects under this prefix
+	Prefix    string `json:"prefix,omitempty"`
+	MountPath string `json:"mountPath" validate:"required,startswith=/"`
+	// Endpoint of S3 compatible storage, AWS if empty
+	Endpoint string `json:"endpoint,omitempty" validate:"omitempty,url"`
+	Region   string `json:"region,omitempty"`
+	ReadOnly bool   `json:"readOnly,omitempty"`
+	// The bucket is accessed anonymously without credentials
+	Credentials *BucketCredentialsDTO `json:"credentials,omitempty"`
+} //	@name	BucketMountDTO
+
+// BucketCredentialsDTO holds the access keys of S3 buckets or the service account key of GCS buckets
+type BucketCredentialsDTO struct {
+	AccessKeyId     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	SessionToken    string `json:"sessionToken,omitempty"`
+	// JSON key of a GCS service account
+	ServiceAccountKey string `json:"serviceAccountKey,omitempty"`
+} //	@name	BucketCredentialsDTO
+
+type BucketMountStatusDTO struct {
+	MountPath string `json:"mountPath"`
+	Provider  string `json:"provider"`
+	Bucket    string `json:"bucket"`
+	// FUSE client the bucket is mounted with
+	Driver  string `json:"driver"`
+	Mounted bool   `json:"mounted"`
+	Healthy bool   `json:"healthy"`
+	// Why the mount is unhealthy, if it is
+	Error string `json:"error,omitempty"`
+	// Times the bucket was mounted again after failing
+	Remounts      int        `json:"remounts"`
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+} //	@name	BucketMountStatusDTO
@@ -0,0 +1,97 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/runner/internal/constants"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+const apiVersionKey = "apiVersion"
+
+// ApiVersions are the versions of the control API the runner serves
+type ApiVersions struct {
+	Enabled []int
+	// Deprecated versions are served with Deprecation, Sunset and Warning headers
+	Deprecated []int
+	// Date after which the deprecated versions are removed, not announced if zero
+	Sunset time.Time
+}
+
+// Default returns the version of unversioned requests without a version header, which come from
+// control planes that predate versioning
+func (v ApiVersions) Default() int {
+	if len(v.Enabled) == 0 || slices.Contains(v.Enabled, 1) {
+		return 1
+	}
+	return slices.Min(v.Enabled)
+}
+
+// ApiVersionMiddleware negotiates the API version of a request. Routes under a /v<n> prefix pass
+// their version, unversioned routes pass 0 and take the version from the version header or the
+// default version.
+func ApiVersionMiddleware(versions ApiVersions, routeVersion int) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		version := routeVersion
+
+		if requested := ctx.GetHeader(constants.DAYTONA_API_VERSION_HEADER); requested != "" {
+			parsed, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(requested), "v"))
+			if err != nil {
+				ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid API version %q", requested)))
+				ctx.Abort()
+				return
+			}
+
+			if routeVersion != 0 && parsed != routeVersion {
+				ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("API version %d requested on a v%d route", parsed, routeVersion)))
+				ctx.Abort()
+				return
+			}
+
+			version = parsed
+		}
+
+		if version == 0 {
+			version = versions.Default()
+		}
+
+		if !slices.Contains(versions.Enabled, version) {
+			ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("API version %d is not supported, supported versions are %v", version, versions.Enabled)))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(apiVersionKey, version)
+		ctx.Header(constants.DAYTONA_API_VERSION_HEADER, strconv.Itoa(version))
+
+		if slices.Contains(versions.Deprecated, version) {
+			ctx.Header("Deprecation", "true")
+			message := fmt.Sprintf("API version %d is deprecated", version)
+			if !versions.Sunset.IsZero() {
+				ctx.Header("Sunset", versions.Sunset.UTC().Format(http.TimeFormat))
+				message += " and will be removed after " + versions.Sunset.Format(time.DateOnly)
+			}
+			ctx.Header("Warning", fmt.Sprintf("299 - %q", message))
+		}
+
+		ctx.Next()
+	}
+}
+
+// GetApiVersion returns the negotiated API version of a request
+func GetApiVersion(ctx *gin.Context) int {
+	if version, ok := ctx.Get(apiVersionKey); ok {
+		return version.(int)
+	}
+	return 1
+}
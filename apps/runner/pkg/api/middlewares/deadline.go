@@ -0,0 +1,61 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/daytonaio/runner/internal/constants"
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// Longest deadline a caller can set
+const maxRequestTimeout = 2 * time.Hour
+
+// DeadlineMiddleware sets the deadline of a request, which the docker operations of the request
+// are canceled at. Callers set it with the timeout header, as a duration or in seconds, otherwise
+// the default timeout of the route applies.
+func DeadlineMiddleware(defaultTimeout time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		timeout := defaultTimeout
+
+		if value := ctx.GetHeader(constants.DAYTONA_REQUEST_TIMEOUT_HEADER); value != "" {
+			parsed, err := parseTimeout(value)
+			if err != nil {
+				ctx.Error(common_errors.NewBadRequestError(err))
+				ctx.Abort()
+				return
+			}
+			timeout = parsed
+		}
+
+		deadlineCtx, cancel := context.WithTimeoutCause(ctx.Request.Context(), timeout, fmt.Errorf("request timeout of %s exceeded: %w", timeout, context.DeadlineExceeded))
+		defer cancel()
+
+		ctx.Request = ctx.Request.WithContext(deadlineCtx)
+		ctx.Next()
+	}
+}
+
+func parseTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, atoiErr := strconv.Atoi(value)
+		if atoiErr != nil {
+			return 0, fmt.Errorf("invalid request timeout %q", value)
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	if timeout <= 0 || timeout > maxRequestTimeout {
+		return 0, fmt.Errorf("request timeout must be positive and at most %s", maxRequestTimeout)
+	}
+
+	return timeout, nil
+}
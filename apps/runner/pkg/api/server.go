@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/pprof"
 	"time"
 
 	"github.com/daytonaio/runner/cmd/runner/config"
@@ -26,6 +27,7 @@ import (
 	"github.com/daytonaio/runner/pkg/api/docs"
 	"github.com/daytonaio/runner/pkg/api/middlewares"
 	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/faults"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/gin-gonic/gin"
@@ -38,32 +40,47 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+const (
+	sandboxOperationTimeout  = 15 * time.Minute
+	snapshotOperationTimeout = time.Hour
+)
+
 type ApiServerConfig struct {
-	ApiPort     int
-	ApiToken    string
-	TLSCertFile string
-	TLSKeyFile  string
-	EnableTLS   bool
+	ApiPort  int
+	ApiToken string
+	// Token of the admin endpoints, the API token if empty
+	AdminApiToken    string
+	TLSCertFile      string
+	TLSKeyFile       string
+	EnableTLS        bool
+	ProfilingEnabled bool
+	ApiVersions      middlewares.ApiVersions
 }
 
 func NewApiServer(config ApiServerConfig) *ApiServer {
 	return &ApiServer{
-		apiPort:     config.ApiPort,
-		apiToken:    config.ApiToken,
-		tlsCertFile: config.TLSCertFile,
-		tlsKeyFile:  config.TLSKeyFile,
-		enableTLS:   config.EnableTLS,
+		apiPort:          config.ApiPort,
+		apiToken:         config.ApiToken,
+		adminApiToken:    config.AdminApiToken,
+		tlsCertFile:      config.TLSCertFile,
+		tlsKeyFile:       config.TLSKeyFile,
+		enableTLS:        config.EnableTLS,
+		profilingEnabled: config.ProfilingEnabled,
+		apiVersions:      config.ApiVersions,
 	}
 }
 
 type ApiServer struct {
-	apiPort     int
-	apiToken    string
-	tlsCertFile string
-	tlsKeyFile  string
-	enableTLS   bool
-	httpServer  *http.Server
-	router      *gin.Engine
+	apiPort          int
+	apiToken         string
+	adminApiToken    string
+	tlsCertFile      string
+	tlsKeyFile       string
+	enableTLS        bool
+	profilingEnabled bool
+	apiVersions      middlewares.ApiVersions
+	httpServer       *http.Server
+	router           *gin.Engine
 }
 
 func (a *ApiServer) Start() error {
@@ -97,48 +114,51 @@ func (a *ApiServer) Start() error {
 		public.GET("/api/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	}
 
+	// Unversioned routes are served for control planes that predate versioning
 	protected := a.router.Group("/")
-	protected.Use(middlewares.AuthMiddleware(a.apiToken))
+	protected.Use(middlewares.AuthMiddleware(a.apiToken), middlewares.ApiVersionMiddleware(a.apiVersions, 0))
+	a.registerControlRoutes(protected)
 
-	metricsController := protected.Group("/metrics")
-	{
-		metricsController.GET("", gin.WrapH(promhttp.Handler()))
+	for _, version := range a.apiVersions.Enabled {
+		versioned := a.router.Group(fmt.Sprintf("/v%d", version))
+		versioned.Use(middlewares.AuthMiddleware(a.apiToken), middlewares.ApiVersionMiddleware(a.apiVersions, version))
+		a.registerControlRoutes(versioned)
 	}
 
-	infoController := protected.Group("/info")
-	{
-		infoController.GET("", controllers.RunnerInfo)
+	adminApiToken := a.adminApiToken
+	if adminApiToken == "" {
+		adminApiToken = a.apiToken
 	}
 
-	sandboxController := protected.Group("/sandboxes")
+	adminController := a.router.Group("/admin")
+	adminController.Use(middlewares.AuthMiddleware(adminApiToken))
 	{
-		sandboxController.POST("", controllers.Create)
-		sandboxController.GET("/:sandboxId", controllers.Info)
-		sandboxController.POST("/:sandboxId/destroy", controllers.Destroy)
-		sandboxController.POST("/:sandboxId/start", controllers.Start)
-		sandboxController.POST("/:sandboxId/stop", controllers.Stop)
-		sandboxController.POST("/:sandboxId/backup", controllers.CreateBackup)
-		sandboxController.POST("/:sandboxId/resize", controllers.Resize)
-		sandboxController.POST("/:sandboxId/recover", controllers.Recover)
-		sandboxController.POST("/:sandboxId/is-recoverable", controllers.IsRecoverable)
-		sandboxController.DELETE("/:sandboxId", controllers.RemoveDestroyed)
-		sandboxController.POST("/:sandboxId/network-settings", controllers.UpdateNetworkSettings)
+		adminController.GET("/diagnostics", controllers.GetDiagnostics)
+		adminController.GET("/volume-mounts/cleanup", controllers.GetVolumeMountCleanup)
 
-		// Add proxy endpoint within the sandbox controller for toolbox
-		// Using Any() to handle all HTTP methods for the toolbox proxy
-		sandboxController.Any("/:sandboxId/toolbox/*path", controllers.ProxyRequest)
-	}
+		if a.profilingEnabled {
+			pprofController := adminController.Group("/pprof")
+			{
+				pprofController.GET("/profile", controllers.GetCpuProfile)
+				pprofController.GET("/trace", controllers.GetExecutionTrace)
+				pprofController.GET("/profiles/:name", controllers.GetProfile)
+				pprofController.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+				pprofController.GET("/symbol", gin.WrapF(pprof.Symbol))
+				pprofController.POST("/symbol", gin.WrapF(pprof.Symbol))
+				pprofController.GET("/sampling", controllers.GetProfilingSampling)
+				pprofController.POST("/sampling", controllers.SetProfilingSampling)
+			}
+		}
 
-	snapshotController := protected.Group("/snapshots")
-	{
-		snapshotController.POST("/pull", controllers.PullSnapshot)
-		snapshotController.POST("/build", controllers.BuildSnapshot)
-		snapshotController.POST("/tag", controllers.TagImage)
-		snapshotController.GET("/exists", controllers.SnapshotExists)
-		snapshotController.GET("/info", controllers.GetSnapshotInfo)
-		snapshotController.POST("/remove", controllers.RemoveSnapshot)
-		snapshotController.GET("/logs", controllers.GetBuildLogs)
-		snapshotController.POST("/inspect", controllers.InspectSnapshotInRegistry)
+		if faults.Enabled {
+			faultsController := adminController.Group("/faults")
+			{
+				faultsController.GET("", controllers.ListFaults)
+				faultsController.POST("", controllers.AddFault)
+				faultsController.DELETE("", controllers.ClearFaults)
+				faultsController.DELETE("/:id", controllers.RemoveFault)
+			}
+		}
 	}
 
 	a.httpServer = &http.Server{
@@ -172,3 +192,106 @@ func (a *ApiServer) Stop() {
 		log.Error(err)
 	}
 }
+
+// registerControlRoutes registers the routes of the control API. The versions share the routes
+// and controllers until a version changes them, controllers that differ between versions read the
+// negotiated version with middlewares.GetApiVersion.
+func (a *ApiServer) registerControlRoutes(protected *gin.RouterGroup) {
+	// Docker operations of the lifecycle routes are canceled at these deadlines unless the caller
+	// sets its own. Proxied toolbox requests and streams have none.
+	sandboxDeadline := middlewares.DeadlineMiddleware(sandboxOperationTimeout)
+	snapshotDeadline := middlewares.DeadlineMiddleware(snapshotOperationTimeout)
+
+	metricsController := protected.Group("/metrics")
+	{
+		metricsController.GET("", gin.WrapH(promhttp.Handler()))
+	}
+
+	infoController := protected.Group("/info")
+	{
+		infoController.GET("", controllers.RunnerInfo)
+	}
+
+	configController := protected.Group("/config")
+	{
+		configController.POST("/reload", controllers.ReloadConfig)
+	}
+
+	sandboxController := protected.Group("/sandboxes")
+	{
+		sandboxController.GET("", controllers.List)
+		sandboxController.POST("", sandboxDeadline, controllers.Create)
+		sandboxController.GET("/:sandboxId", controllers.Info)
+		sandboxController.GET("/:sandboxId/storage", controllers.GetStorageUsage)
+		sandboxController.GET("/:sandboxId/stats", controllers.GetStats)
+		sandboxController.GET("/:sandboxId/provisioning", controllers.GetProvisioningStatus)
+		sandboxController.GET("/:sandboxId/dotfiles", controllers.GetDotfilesStatus)
+		sandboxController.POST("/:sandboxId/dotfiles/apply", controllers.ApplyDotfiles)
+		sandboxController.GET("/:sandboxId/bucket-mounts", controllers.GetBucketMounts)
+		sandboxController.GET("/:sandboxId/egress", controllers.GetEgressTraffic)
+		sandboxController.GET("/:sandboxId/audit", controllers.GetAuditEvents)
+		sandboxController.POST("/:sandboxId/destroy", sandboxDeadline, controllers.Destroy)
+		sandboxController.POST("/:sandboxId/start", sandboxDeadline, controllers.Start)
+		sandboxController.POST("/:sandboxId/stop", sandboxDeadline, controllers.Stop)
+		sandboxController.POST("/:sandboxId/backup", controllers.CreateBackup)
+		sandboxController.POST("/:sandboxId/resize", sandboxDeadline, controllers.Resize)
+		sandboxController.POST("/:sandboxId/clone", sandboxDeadline, controllers.Clone)
+		sandboxController.POST("/:sandboxId/recover", sandboxDeadline, controllers.Recover)
+		sandboxController.POST("/:sandboxId/is-recoverable", controllers.IsRecoverable)
+		sandboxController.DELETE("/:sandboxId", sandboxDeadline, controllers.RemoveDestroyed)
+		sandboxController.POST("/:sandboxId/network-settings", sandboxDeadline, controllers.UpdateNetworkSettings)
+		sandboxController.POST("/:sandboxId/daemon/upgrade", sandboxDeadline, controllers.UpgradeDaemon)
+
+		// Add proxy endpoint within the sandbox controller for toolbox
+		// Using Any() to handle all HTTP methods for the toolbox proxy
+		sandboxController.Any("/:sandboxId/toolbox/*path", controllers.ProxyRequest)
+		sandboxController.Any("/:sandboxId/sync/*path", controllers.ProxySyncRequest)
+	}
+
+	organizationController := protected.Group("/organizations")
+	{
+		organizationController.GET("/:organizationId/usage", controllers.GetOrganizationUsage)
+		organizationController.PUT("/:organizationId/quota", controllers.SetOrganizationQuota)
+		organizationController.DELETE("/:organizationId/quota", controllers.RemoveOrganizationQuota)
+	}
+
+	abuseController := protected.Group("/abuse")
+	{
+		abuseController.GET("/events", controllers.ListAbuseEvents)
+	}
+
+	jobsController := protected.Group("/jobs")
+	{
+		jobsController.GET("", controllers.ListJobs)
+	}
+
+	drainController := protected.Group("/drain")
+	{
+		drainController.GET("", controllers.GetDrainStatus)
+		drainController.POST("", controllers.SetDrainStatus)
+	}
+
+	netRulesController := protected.Group("/netrules")
+	{
+		netRulesController.GET("", controllers.ListNetRules)
+	}
+
+	reaperController := protected.Group("/reaper")
+	{
+		reaperController.GET("/report", controllers.GetOrphanReport)
+		reaperController.POST("/run", controllers.ReapOrphans)
+	}
+
+	snapshotController := protected.Group("/snapshots")
+	{
+		snapshotController.POST("/pull", snapshotDeadline, controllers.PullSnapshot)
+		snapshotController.POST("/build", snapshotDeadline, controllers.BuildSnapshot)
+		snapshotController.POST("/tag", snapshotDeadline, controllers.TagImage)
+		snapshotController.GET("/exists", controllers.SnapshotExists)
+		snapshotController.GET("/info", controllers.GetSnapshotInfo)
+		snapshotController.GET("/export", controllers.ExportSnapshot)
+		snapshotController.POST("/remove", controllers.RemoveSnapshot)
+		snapshotController.GET("/logs", controllers.GetBuildLogs)
+		snapshotController.POST("/inspect", sandboxDeadline, controllers.InspectSnapshotInRegistry)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package audit records the processes sandboxes execute and the outbound connections they open.
+// Executions are reported by the process events connector of the kernel and connections are read
+// from its conntrack table, so no eBPF programs have to be loaded on the runner.
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	EventTypeExec    = "exec"
+	EventTypeConnect = "connect"
+)
+
+type Event struct {
+	Time      time.Time `json:"time"`
+	SandboxId string    `json:"sandboxId"`
+	Type      string    `json:"type"`
+	// PID of an executed process in the sandbox
+	Pid  int      `json:"pid,omitempty"`
+	Exe  string   `json:"exe,omitempty"`
+	Args []string `json:"args,omitempty"`
+	// tcp, udp or icmp for connections
+	Protocol string `json:"protocol,omitempty"`
+	// Address of the connection destination, host:port
+	Destination string `json:"destination,omitempty"`
+}
+
+// Exporter receives the events recorded since it was last called
+type Exporter func(ctx context.Context, events []Event) error
+
+type Config struct {
+	ApiClient client.APIClient
+	// Events kept in memory for queries, the oldest are dropped first
+	MaxEvents int
+	// How often the conntrack table is read. Connections closed in between may be missed.
+	ConnectionPollInterval time.Duration
+	// Exports events every ExportInterval if set
+	Exporter       Exporter
+	ExportInterval time.Duration
+}
+
+type Filter struct {
+	SandboxId string
+	// exec or connect, all types if empty
+	Type  string
+	Since time.Time
+}
+
+func (f Filter) matches(event Event) bool {
+	return (f.SandboxId == "" || event.SandboxId == f.SandboxId) &&
+		(f.Type == "" || event.Type == f.Type) &&
+		!event.Time.Before(f.Since)
+}
+
+type Collector struct {
+	config    Config
+	sandboxes *sandboxIndex
+
+	mutex       sync.Mutex
+	events      []Event
+	unexported  []Event
+	subscribers map[chan Event]Filter
+}
+
+func NewCollector(config Config) *Collector {
+	return &Collector{
+		config:      config,
+		sandboxes:   newSandboxIndex(config.ApiClient),
+		subscribers: make(map[chan Event]Filter),
+	}
+}
+
+// Start listens for process executions and polls connections until the context is canceled
+func (c *Collector) Start(ctx context.Context) error {
+	execEvents, err := listenExecEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for pid := range execEvents {
+			c.recordExec(ctx, pid)
+		}
+	}()
+
+	go c.pollConnections(ctx)
+
+	if c.config.Exporter != nil {
+		go c.export(ctx)
+	}
+
+	log.Info("Sandbox audit collector started")
+
+	return nil
+}
+
+// Query returns the recorded events matching the filter, oldest first
+func (c *Collector) Query(filter Filter) []Event {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	events := []Event{}
+	for _, event := range c.events {
+		if filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// Subscribe returns the recorded events matching the filter and a channel of the events matching
+// it that are recorded later, which is closed when the context is canceled. Events are dropped
+// if the subscriber falls behind.
+func (c *Collector) Subscribe(ctx context.Context, filter Filter) ([]Event, <-chan Event) {
+	ch := make(chan Event, 256)
+
+	c.mutex.Lock()
+	events := []Event{}
+	for _, event := range c.events {
+		if filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+	c.subscribers[ch] = filter
+	c.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		c.mutex.Lock()
+		delete(c.subscribers, ch)
+		c.mutex.Unlock()
+		close(ch)
+	}()
+
+	return events, ch
+}
+
+func (c *Collector) record(event Event) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.events = append(c.events, event)
+	if len(c.events) > c.config.MaxEvents {
+		c.events = c.events[len(c.events)-c.config.MaxEvents:]
+	}
+
+	if c.config.Exporter != nil {
+		c.unexported = append(c.unexported, event)
+		if len(c.unexported) > c.config.MaxEvents {
+			c.unexported = c.unexported[len(c.unexported)-c.config.MaxEvents:]
+		}
+	}
+
+	for ch, filter := range c.subscribers {
+		if !filter.matches(event) {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (c *Collector) export(ctx context.Context) {
+	ticker := time.NewTicker(c.config.ExportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		c.mutex.Lock()
+		events := c.unexported
+		c.unexported = nil
+		c.mutex.Unlock()
+
+		if len(events) == 0 {
+			continue
+		}
+
+		if err := c.config.Exporter(ctx, events); err != nil {
+			log.Errorf("Failed to export %d audit events: %v", len(events), err)
+
+			// Exported again with the next batch
+			c.mutex.Lock()
+			c.unexported = append(events, c.unexported...)
+			if len(c.unexported) > c.config.MaxEvents {
+				c.unexported = c.unexported[len(c.unexported)-c.config.MaxEvents:]
+			}
+			c.mutex.Unlock()
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/vishvananda/netlink"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// pollConnections records the connections of sandboxes that appear in the conntrack table
+func (c *Collector) pollConnections(ctx context.Context) {
+	ticker := time.NewTicker(c.config.ConnectionPollInterval)
+	defer ticker.Stop()
+
+	// Connections seen in the previous poll, so that each is only recorded once
+	seen := make(map[string]bool)
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		flows, err := netlink.ConntrackTableList(netlink.ConntrackTable, netlink.FAMILY_V4)
+		if err != nil {
+			log.Errorf("Failed to read the conntrack table: %v", err)
+			continue
+		}
+
+		current := make(map[string]bool, len(flows))
+		for _, flow := range flows {
+			key := flow.Forward.SrcIP.String() + ":" + strconv.Itoa(int(flow.Forward.SrcPort)) + "-" +
+				net.JoinHostPort(flow.Forward.DstIP.String(), strconv.Itoa(int(flow.Forward.DstPort))) + "/" + strconv.Itoa(int(flow.Forward.Protocol))
+			current[key] = true
+			if seen[key] {
+				continue
+			}
+
+			sandboxId, ok := c.sandboxes.byIpAddress(ctx, flow.Forward.SrcIP.String())
+			if !ok {
+				continue
+			}
+
+			c.record(Event{
+				Time:        time.Now(),
+				SandboxId:   sandboxId,
+				Type:        EventTypeConnect,
+				Protocol:    protocolName(flow.Forward.Protocol),
+				Destination: net.JoinHostPort(flow.Forward.DstIP.String(), strconv.Itoa(int(flow.Forward.DstPort))),
+			})
+		}
+		seen = current
+	}
+}
+
+func protocolName(protocol uint8) string {
+	switch protocol {
+	case 1:
+		return "icmp"
+	case 6:
+		return "tcp"
+	case 17:
+		return "udp"
+	default:
+		return fmt.Sprintf("%d", protocol)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Process events connector, see linux/connector.h and linux/cn_proc.h
+const (
+	netlinkConnector  = 11
+	cnIdxProc         = 1
+	cnValProc         = 1
+	procCnMcastListen = 1
+	procEventExec     = 2
+
+	nlmsgHeaderSize = 16
+	cnMsgSize       = 20
+)
+
+// Docker names the cgroups of containers after their IDs
+var containerIdRegex = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// listenExecEvents subscribes to the process events of the kernel and returns the PIDs of the
+// processes that execute a program. Requires CAP_NET_ADMIN.
+func listenExecEvents(ctx context.Context) (<-chan int, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM, netlinkConnector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the process events connector: %w", err)
+	}
+
+	err = syscall.Bind(fd, &syscall.SockaddrNetlink{
+		Family: syscall.AF_NETLINK,
+		Groups: cnIdxProc,
+		Pid:    uint32(os.Getpid()),
+	})
+	if err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("failed to bind the process events connector: %w", err)
+	}
+
+	message := make([]byte, nlmsgHeaderSize+cnMsgSize+4)
+	binary.NativeEndian.PutUint32(message[0:], uint32(len(message)))
+	binary.NativeEndian.PutUint16(message[4:], syscall.NLMSG_DONE)
+	binary.NativeEndian.PutUint32(message[12:], uint32(os.Getpid()))
+	binary.NativeEndian.PutUint32(message[nlmsgHeaderSize:], cnIdxProc)
+	binary.NativeEndian.PutUint32(message[nlmsgHeaderSize+4:], cnValProc)
+	binary.NativeEndian.PutUint16(message[nlmsgHeaderSize+16:], 4)
+	binary.NativeEndian.PutUint32(message[nlmsgHeaderSize+cnMsgSize:], procCnMcastListen)
+
+	err = syscall.Sendto(fd, message, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK})
+	if err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("failed to subscribe to process events: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		syscall.Close(fd)
+	}()
+
+	pids := make(chan int, 1024)
+	go func() {
+		defer close(pids)
+
+		buf := make([]byte, 64*1024)
+		for {
+			n, _, err := syscall.Recvfrom(fd, buf, 0)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				// ENOBUFS when events are produced faster than they are read
+				log.Debugf("Failed to read process events: %v", err)
+				continue
+			}
+
+			messages, err := syscall.ParseNetlinkMessage(buf[:n])
+			if err != nil {
+				continue
+			}
+
+			for _, message := range messages {
+				// proc_event: what, cpu, timestamp_ns, then the PID and TGID of exec events
+				data := message.Data
+				if len(data) < cnMsgSize+24 || binary.NativeEndian.Uint32(data[0:]) != cnIdxProc {
+					continue
+				}
+				if binary.NativeEndian.Uint32(data[cnMsgSize:]) != procEventExec {
+					continue
+				}
+
+				select {
+				case pids <- int(binary.NativeEndian.Uint32(data[cnMsgSize+20:])):
+				default:
+				}
+			}
+		}
+	}()
+
+	return pids, nil
+}
+
+// recordExec records the execution of a process if it runs in a sandbox
+func (c *Collector) recordExec(ctx context.Context, pid int) {
+	containerId, err := processContainerId(pid)
+	if err != nil || containerId == "" {
+		return
+	}
+
+	sandboxId, ok := c.sandboxes.byContainerId(ctx, containerId)
+	if !ok {
+		return
+	}
+
+	event := Event{
+		Time:      time.Now(),
+		SandboxId: sandboxId,
+		Type:      EventTypeExec,
+		Pid:       namespacePid(pid),
+	}
+
+	// The process may already have exited
+	event.Exe, _ = os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil && len(cmdline) > 0 {
+		event.Args = strings.Split(strings.TrimSuffix(string(cmdline), "\x00"), "\x00")
+	}
+
+	c.record(event)
+}
+
+// processContainerId returns the ID of the container a process runs in, empty if it runs on the host
+func processContainerId(pid int) (string, error) {
+	cgroups, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+
+	return containerIdRegex.FindString(string(cgroups)), nil
+}
+
+// namespacePid returns the PID of a process in its own PID namespace, i.e. as seen in the sandbox
+func namespacePid(pid int) int {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return pid
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "NSpid:" {
+			continue
+		}
+		if nsPid, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			return nsPid
+		}
+	}
+
+	return pid
+}
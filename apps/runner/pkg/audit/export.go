@@ -0,0 +1,36 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+)
+
+type ObjectStorage interface {
+	PutObject(ctx context.Context, objectPath string, data []byte) error
+}
+
+// ObjectStorageExporter writes each batch of events as a newline delimited JSON object under the
+// prefix, e.g. audit/runner-1/2025/01/31/1738281600000000000.ndjson
+func ObjectStorageExporter(storage ObjectStorage, prefix string) Exporter {
+	return func(ctx context.Context, events []Event) error {
+		var data bytes.Buffer
+		encoder := json.NewEncoder(&data)
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now().UTC()
+		objectPath := path.Join(prefix, now.Format("2006/01/02"), fmt.Sprintf("%d.ndjson", now.UnixNano()))
+
+		return storage.PutObject(ctx, objectPath, data.Bytes())
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// How often the index may be rebuilt when an unknown container or address is looked up
+const sandboxIndexRefreshInterval = time.Second
+
+// sandboxIndex maps the containers and addresses of running sandboxes to their IDs. Sidecars are
+// mapped to the sandbox they belong to.
+type sandboxIndex struct {
+	apiClient client.APIClient
+
+	mutex       sync.Mutex
+	byContainer map[string]string
+	byIp        map[string]string
+	refreshedAt time.Time
+}
+
+func newSandboxIndex(apiClient client.APIClient) *sandboxIndex {
+	return &sandboxIndex{
+		apiClient:   apiClient,
+		byContainer: make(map[string]string),
+		byIp:        make(map[string]string),
+	}
+}
+
+func (i *sandboxIndex) byContainerId(ctx context.Context, containerId string) (string, bool) {
+	return i.lookup(ctx, func() (string, bool) {
+		sandboxId, ok := i.byContainer[containerId]
+		return sandboxId, ok
+	})
+}
+
+func (i *sandboxIndex) byIpAddress(ctx context.Context, ip string) (string, bool) {
+	return i.lookup(ctx, func() (string, bool) {
+		sandboxId, ok := i.byIp[ip]
+		return sandboxId, ok
+	})
+}
+
+func (i *sandboxIndex) lookup(ctx context.Context, find func() (string, bool)) (string, bool) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if sandboxId, ok := find(); ok {
+		return sandboxId, true
+	}
+
+	if time.Since(i.refreshedAt) < sandboxIndexRefreshInterval {
+		return "", false
+	}
+
+	i.refresh(ctx)
+	return find()
+}
+
+func (i *sandboxIndex) refresh(ctx context.Context) {
+	i.refreshedAt = time.Now()
+
+	containers, err := i.apiClient.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		log.Errorf("Failed to list sandboxes for auditing: %v", err)
+		return
+	}
+
+	i.byContainer = make(map[string]string, len(containers))
+	i.byIp = make(map[string]string, len(containers))
+	for _, c := range containers {
+		if len(c.Names) == 0 || len(c.Names[0]) < 2 {
+			continue
+		}
+
+		sandboxId := c.Names[0][1:]
+		if owner, ok := c.Labels[common.SIDECAR_OF_LABEL]; ok {
+			sandboxId = owner
+		}
+		i.byContainer[c.ID] = sandboxId
+
+		if c.NetworkSettings == nil {
+			continue
+		}
+		for _, network := range c.NetworkSettings.Networks {
+			if network != nil && network.IPAddress != "" {
+				i.byIp[network.IPAddress] = sandboxId
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package bucketmount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/daytonaio/runner/internal/util"
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+// Driver is the FUSE client buckets are mounted with
+type Driver string
+
+const (
+	DriverMountpointS3 Driver = "mount-s3"
+	DriverS3fs         Driver = "s3fs"
+	DriverGoofys       Driver = "goofys"
+	DriverGcsfuse      Driver = "gcsfuse"
+)
+
+// mountCommand returns the command that mounts a bucket on a directory. The clients daemonize once
+// the bucket is mounted. Credentials are passed through the environment and files in the
+// credentials directory of the mount, never the environment of the runner, whose own credentials
+// must not reach sandboxes.
+func mountCommand(ctx context.Context, driver Driver, spec dto.BucketMountDTO, credentialsDir, path string) (*exec.Cmd, error) {
+	env := []string{
+		"PATH=" + os.Getenv("PATH"),
+		// Keeps the clients from reading the config and credentials in the home of the runner user
+		"HOME=" + credentialsDir,
+		// Keeps the clients from falling back to the instance role of the host
+		"AWS_EC2_METADATA_DISABLED=true",
+	}
+
+	credentials := spec.Credentials
+	if credentials == nil {
+		credentials = &dto.BucketCredentialsDTO{}
+	}
+
+	var args []string
+	switch driver {
+	case DriverMountpointS3:
+		args = []string{"--allow-other", "--file-mode", "0666", "--dir-mode", "0777"}
+		if spec.ReadOnly {
+			args = append(args, "--read-only")
+		} else {
+			args = append(args, "--allow-delete", "--allow-overwrite")
+		}
+		if spec.Prefix != "" {
+			args = append(args, "--prefix", strings.TrimSuffix(spec.Prefix, "/")+"/")
+		}
+		if spec.Endpoint != "" {
+			args = append(args, "--endpoint-url", spec.Endpoint)
+		}
+		if spec.Region != "" {
+			args = append(args, "--region", spec.Region)
+		}
+		if credentials.AccessKeyId == "" {
+			args = append(args, "--no-sign-request")
+		}
+		args = append(args, spec.Bucket, path)
+		env = append(env, awsEnv(credentials)...)
+	case DriverS3fs:
+		options := []string{"allow_other", "umask=0000", "mp_umask=0000"}
+		if spec.ReadOnly {
+			options = append(options, "ro")
+		}
+		if spec.Endpoint != "" {
+			options = append(options, "url="+spec.Endpoint, "use_path_request_style")
+		}
+		if spec.Region != "" {
+			options = append(options, "endpoint="+spec.Region)
+		}
+		if credentials.AccessKeyId == "" {
+			options = append(options, "public_bucket=1")
+		} else {
+			passwdFile := filepath.Join(credentialsDir, "passwd-s3fs")
+			err := os.WriteFile(passwdFile, []byte(credentials.AccessKeyId+":"+credentials.SecretAccessKey), 0600)
+			if err != nil {
+				return nil, fmt.Errorf("failed to write s3fs credentials: %w", err)
+			}
+			options = append(options, "passwd_file="+passwdFile)
+			if credentials.SessionToken != "" {
+				env = append(env, "AWSSESSIONTOKEN="+credentials.SessionToken)
+			}
+		}
+		bucket := spec.Bucket
+		if spec.Prefix != "" {
+			bucket += ":/" + strings.Trim(spec.Prefix, "/")
+		}
+		args = []string{bucket, path, "-o", strings.Join(options, ",")}
+	case DriverGoofys:
+		// Without credentials goofys falls back to the credentials of the host
+		if credentials.AccessKeyId == "" {
+			return nil, errors.New("goofys can't mount buckets anonymously")
+		}
+		args = []string{"-o", "allow_other", "--file-mode", "0666", "--dir-mode", "0777"}
+		if spec.ReadOnly {
+			args = append(args, "-o", "ro")
+		}
+		if spec.Endpoint != "" {
+			args = append(args, "--endpoint", spec.Endpoint)
+		}
+		if spec.Region != "" {
+			args = append(args, "--region", spec.Region)
+		}
+		bucket := spec.Bucket
+		if spec.Prefix != "" {
+			bucket += ":" + strings.Trim(spec.Prefix, "/")
+		}
+		args = append(args, bucket, path)
+		env = append(env, awsEnv(credentials)...)
+	case DriverGcsfuse:
+		args = []string{"-o", "allow_other", "--file-mode", "666", "--dir-mode", "777", "--implicit-dirs"}
+		if spec.ReadOnly {
+			args = append(args, "-o", "ro")
+		}
+		if spec.Prefix != "" {
+			args = append(args, "--only-dir", strings.Trim(spec.Prefix, "/"))
+		}
+		if credentials.ServiceAccountKey == "" {
+			args = append(args, "--anonymous-access")
+		} else {
+			keyFile := filepath.Join(credentialsDir, "service-account.json")
+			err := os.WriteFile(keyFile, []byte(credentials.ServiceAccountKey), 0600)
+			if err != nil {
+				return nil, fmt.Errorf("failed to write gcsfuse credentials: %w", err)
+			}
+			args = append(args, "--key-file", keyFile)
+		}
+		args = append(args, spec.Bucket, path)
+	default:
+		return nil, fmt.Errorf("unknown bucket mount driver %s", driver)
+	}
+
+	cmd := exec.CommandContext(ctx, string(driver), args...)
+	cmd.Env = env
+	cmd.Stderr = io.Writer(&util.ErrorLogWriter{})
+	cmd.Stdout = io.Writer(&util.InfoLogWriter{})
+
+	return cmd, nil
+}
+
+func awsEnv(credentials *dto.BucketCredentialsDTO) []string {
+	if credentials.AccessKeyId == "" {
+		return nil
+	}
+
+	env := []string{
+		"AWS_ACCESS_KEY_ID=" + credentials.AccessKeyId,
+		"AWS_SECRET_ACCESS_KEY=" + credentials.SecretAccessKey,
+	}
+	if credentials.SessionToken != "" {
+		env = append(env, "AWS_SESSION_TOKEN="+credentials.SessionToken)
+	}
+	return env
+}
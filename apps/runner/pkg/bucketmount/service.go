@@ -0,0 +1,702 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package bucketmount mounts S3 and GCS buckets into sandboxes with FUSE clients running on the
+// host, so sandboxes don't need their own FUSE clients.
+//
+// Each bucket is mounted on a directory that is a shared bind mount of itself and is bound into the
+// sandbox container with slave propagation. Buckets mounted on the directory after the container
+// is created, e.g. when a failed mount is replaced, show up in the sandbox without restarting it.
+//
+// The bucket mounts of sandboxes, including their credentials, are kept in the state directory so
+// they can be mounted again after the runner restarts. The directory is only readable by the
+// runner.
+package bucketmount
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	log "github.com/sirupsen/logrus"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+const (
+	mountsFile = "mounts.json"
+	// How long a mounted bucket may take to respond before it is considered failed
+	responseTimeout = 10 * time.Second
+	// How long a client may take to mount a bucket
+	mountTimeout = 30 * time.Second
+)
+
+var (
+	bucketMountFailureCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "runner_bucket_mount_failures_total",
+		Help: "Bucket mounts of running sandboxes found failed by health checks, by FUSE client",
+	}, []string{"driver"})
+	bucketRemountCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "runner_bucket_remounts_total",
+		Help: "Remounts of failed bucket mounts, by FUSE client and result",
+	}, []string{"driver", "result"})
+	bucketMounts = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "runner_bucket_mounts",
+		Help: "Bucket mounts of running sandboxes as of the last health check, by health",
+	}, []string{"state"})
+)
+
+type Config struct {
+	// Directory buckets are mounted in, with a directory per sandbox
+	MountDir string
+	// Directory the bucket mounts of sandboxes and their credentials are kept in
+	StateDir string
+	// FUSE client S3 buckets are mounted with, mount-s3 if empty. GCS buckets are mounted with
+	// gcsfuse.
+	S3Driver Driver
+	// How often the buckets of running sandboxes are checked and failed mounts replaced
+	HealthCheckInterval time.Duration
+}
+
+type Service struct {
+	config    Config
+	mutex     sync.Mutex
+	sandboxes map[string]*sandboxMounts
+}
+
+type sandboxMounts struct {
+	mutex sync.Mutex
+	// Whether the buckets are kept mounted, while the sandbox runs
+	active bool
+	// When the buckets were last mounted for the sandbox
+	mountedAt time.Time
+	status    []mountStatus
+}
+
+type mountStatus struct {
+	healthy       bool
+	err           string
+	remounts      int
+	lastCheckedAt time.Time
+}
+
+func NewService(config Config) (*Service, error) {
+	if config.S3Driver == "" {
+		config.S3Driver = DriverMountpointS3
+	}
+	switch config.S3Driver {
+	case DriverMountpointS3, DriverS3fs, DriverGoofys:
+	default:
+		return nil, fmt.Errorf("unsupported S3 bucket mount driver %s", config.S3Driver)
+	}
+
+	err := os.MkdirAll(config.StateDir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket mount state directory: %w", err)
+	}
+	err = os.MkdirAll(config.MountDir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket mount directory: %w", err)
+	}
+
+	return &Service{
+		config:    config,
+		sandboxes: make(map[string]*sandboxMounts),
+	}, nil
+}
+
+// Prepare saves the bucket mounts of a sandbox and returns the mounts of its container. The
+// buckets are mounted by Mount, before the sandbox starts.
+func (s *Service) Prepare(sandboxId string, mounts []dto.BucketMountDTO) ([]mount.Mount, error) {
+	for _, spec := range mounts {
+		if err := s.validate(spec); err != nil {
+			return nil, common_errors.NewBadRequestError(err)
+		}
+	}
+
+	err := s.writeSpecs(sandboxId, mounts)
+	if err != nil {
+		return nil, err
+	}
+
+	containerMounts := make([]mount.Mount, 0, len(mounts))
+	for i, spec := range mounts {
+		path := s.mountPath(sandboxId, i)
+
+		err := os.MkdirAll(path, 0755)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bucket mount directory %s: %w", path, err)
+		}
+
+		err = makeSharedMountPoint(path)
+		if err != nil {
+			return nil, err
+		}
+
+		containerMounts = append(containerMounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   path,
+			Target:   filepath.Clean(spec.MountPath),
+			ReadOnly: spec.ReadOnly,
+			BindOptions: &mount.BindOptions{
+				Propagation: mount.PropagationRSlave,
+			},
+		})
+	}
+
+	return containerMounts, nil
+}
+
+// Clone prepares the bucket mounts of a sandbox for its clone, which mounts the same buckets
+func (s *Service) Clone(sourceId, cloneId string) ([]mount.Mount, error) {
+	specs, err := s.readSpecs(sourceId)
+	if err != nil || len(specs) == 0 {
+		return nil, err
+	}
+
+	return s.Prepare(cloneId, specs)
+}
+
+// IsMountPath returns whether a host path is a bucket mount directory of a sandbox
+func (s *Service) IsMountPath(sandboxId, path string) bool {
+	return filepath.Dir(filepath.Clean(path)) == filepath.Join(s.config.MountDir, sandboxId)
+}
+
+// Mount mounts the buckets of a sandbox and keeps them mounted until Unmount is called. Buckets
+// that are already mounted and respond are kept.
+func (s *Service) Mount(ctx context.Context, sandboxId string) error {
+	specs, err := s.readSpecs(sandboxId)
+	if err != nil || len(specs) == 0 {
+		return err
+	}
+
+	sm := s.sandbox(sandboxId)
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.active = true
+	sm.mountedAt = time.Now()
+	sm.resize(len(specs))
+
+	for i, spec := range specs {
+		path := s.mountPath(sandboxId, i)
+
+		if checkMount(path) == nil {
+			continue
+		}
+
+		err := s.mount(ctx, sandboxId, i, spec)
+		if err != nil {
+			sm.status[i].healthy = false
+			sm.status[i].err = err.Error()
+			return fmt.Errorf("failed to mount bucket %s on %s: %w", spec.Bucket, spec.MountPath, err)
+		}
+
+		sm.status[i].healthy = true
+		sm.status[i].err = ""
+	}
+
+	return nil
+}
+
+// Unmount unmounts the buckets of a stopped sandbox, unless they were mounted again after it
+// stopped. The bucket mounts are kept for when the sandbox starts again.
+func (s *Service) Unmount(ctx context.Context, sandboxId string, stoppedAt time.Time) {
+	specs, err := s.readSpecs(sandboxId)
+	if err != nil || len(specs) == 0 {
+		return
+	}
+
+	sm := s.sandbox(sandboxId)
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if sm.mountedAt.After(stoppedAt) {
+		return
+	}
+	sm.active = false
+
+	for i := range specs {
+		path := s.mountPath(sandboxId, i)
+		if err := unmountBucket(ctx, path); err != nil {
+			log.Warnf("Failed to unmount the bucket on %s: %v", path, err)
+		}
+	}
+}
+
+// Remove unmounts the buckets of a destroyed sandbox and removes its bucket mounts and their
+// credentials
+func (s *Service) Remove(ctx context.Context, sandboxId string) error {
+	sm := s.sandbox(sandboxId)
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.active = false
+
+	sandboxMountDir := filepath.Join(s.config.MountDir, sandboxId)
+	entries, err := os.ReadDir(sandboxMountDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(sandboxMountDir, entry.Name())
+
+		err := unmountBucket(ctx, path)
+		if err == nil && isMountPoint(path) {
+			err = runMount(ctx, "umount", path)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to unmount %s: %w", path, err)
+		}
+	}
+
+	err = os.RemoveAll(sandboxMountDir)
+	if err != nil {
+		return fmt.Errorf("failed to remove bucket mount directory: %w", err)
+	}
+
+	err = os.RemoveAll(filepath.Join(s.config.StateDir, sandboxId))
+	if err != nil {
+		return fmt.Errorf("failed to remove bucket mount state: %w", err)
+	}
+
+	s.mutex.Lock()
+	delete(s.sandboxes, sandboxId)
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// Status returns the bucket mounts of a sandbox and their health
+func (s *Service) Status(sandboxId string) ([]dto.BucketMountStatusDTO, error) {
+	specs, err := s.readSpecs(sandboxId)
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		return nil, common_errors.NewNotFoundError(errors.New("sandbox has no bucket mounts"))
+	}
+
+	sm := s.sandbox(sandboxId)
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.resize(len(specs))
+
+	result := make([]dto.BucketMountStatusDTO, 0, len(specs))
+	for i, spec := range specs {
+		fsType, _ := topFilesystem(s.mountPath(sandboxId, i))
+
+		status := dto.BucketMountStatusDTO{
+			MountPath: spec.MountPath,
+			Provider:  spec.Provider,
+			Bucket:    spec.Bucket,
+			Driver:    string(s.driver(spec)),
+			Mounted:   isFuse(fsType),
+			Healthy:   sm.status[i].healthy,
+			Error:     sm.status[i].err,
+			Remounts:  sm.status[i].remounts,
+		}
+		if lastCheckedAt := sm.status[i].lastCheckedAt; !lastCheckedAt.IsZero() {
+			status.LastCheckedAt = &lastCheckedAt
+		}
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+// Start checks the buckets of running sandboxes until the context is canceled and mounts the ones
+// that failed again. Sandboxes with mounted buckets are kept mounted across runner restarts.
+func (s *Service) Start(ctx context.Context) {
+	s.restore()
+
+	ticker := time.NewTicker(s.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.checkAll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// restore marks the sandboxes whose buckets are mounted as active
+func (s *Service) restore() {
+	entries, err := os.ReadDir(s.config.StateDir)
+	if err != nil {
+		log.Errorf("Failed to list bucket mounts: %v", err)
+		return
+	}
+
+	for _, entry := range entries {
+		sandboxId := entry.Name()
+
+		specs, err := s.readSpecs(sandboxId)
+		if err != nil || len(specs) == 0 {
+			continue
+		}
+
+		for i := range specs {
+			fsType, _ := topFilesystem(s.mountPath(sandboxId, i))
+			if isFuse(fsType) {
+				sm := s.sandbox(sandboxId)
+				sm.mutex.Lock()
+				sm.active = true
+				sm.mutex.Unlock()
+				break
+			}
+		}
+	}
+}
+
+func (s *Service) checkAll(ctx context.Context) {
+	s.mutex.Lock()
+	sandboxIds := make([]string, 0, len(s.sandboxes))
+	for sandboxId := range s.sandboxes {
+		sandboxIds = append(sandboxIds, sandboxId)
+	}
+	s.mutex.Unlock()
+
+	healthy, unhealthy := 0, 0
+	for _, sandboxId := range sandboxIds {
+		h, u := s.check(ctx, sandboxId)
+		healthy += h
+		unhealthy += u
+	}
+
+	bucketMounts.WithLabelValues("healthy").Set(float64(healthy))
+	bucketMounts.WithLabelValues("unhealthy").Set(float64(unhealthy))
+}
+
+// check checks the buckets of an active sandbox and mounts the ones that failed again. Returns the
+// number of healthy and unhealthy mounts after remounting.
+func (s *Service) check(ctx context.Context, sandboxId string) (int, int) {
+	specs, err := s.readSpecs(sandboxId)
+	if err != nil {
+		log.Errorf("Failed to read the bucket mounts of sandbox %s: %v", sandboxId, err)
+		return 0, 0
+	}
+
+	sm := s.sandbox(sandboxId)
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if !sm.active {
+		return 0, 0
+	}
+	sm.resize(len(specs))
+
+	healthy, unhealthy := 0, 0
+	for i, spec := range specs {
+		path := s.mountPath(sandboxId, i)
+		driver := string(s.driver(spec))
+		status := &sm.status[i]
+		status.lastCheckedAt = time.Now()
+
+		err := checkMount(path)
+		if err == nil {
+			status.healthy = true
+			status.err = ""
+			healthy++
+			continue
+		}
+
+		bucketMountFailureCount.WithLabelValues(driver).Inc()
+		log.Warnf("Bucket %s of sandbox %s failed, remounting: %v", spec.Bucket, sandboxId, err)
+
+		err = s.mount(ctx, sandboxId, i, spec)
+		if err != nil {
+			bucketRemountCount.WithLabelValues(driver, "failure").Inc()
+			log.Errorf("Failed to remount bucket %s of sandbox %s: %v", spec.Bucket, sandboxId, err)
+			status.healthy = false
+			status.err = err.Error()
+			unhealthy++
+			continue
+		}
+
+		bucketRemountCount.WithLabelValues(driver, "success").Inc()
+		log.Infof("Remounted bucket %s of sandbox %s", spec.Bucket, sandboxId)
+		status.healthy = true
+		status.err = ""
+		status.remounts++
+		healthy++
+	}
+
+	return healthy, unhealthy
+}
+
+// mount mounts a bucket on its directory, replacing a failed mount of the bucket
+func (s *Service) mount(ctx context.Context, sandboxId string, index int, spec dto.BucketMountDTO) error {
+	path := s.mountPath(sandboxId, index)
+
+	// The container only sees mounts made on the shared directory
+	if !isMountPoint(path) {
+		err := makeSharedMountPoint(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Processes in the sandbox may still use the failed mount, it is detached once they don't
+	err := unmountBucket(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	credentialsDir := filepath.Join(s.config.StateDir, sandboxId, strconv.Itoa(index))
+	err = os.MkdirAll(credentialsDir, 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create bucket credentials directory: %w", err)
+	}
+
+	mountCtx, cancel := context.WithTimeout(ctx, mountTimeout)
+	defer cancel()
+
+	cmd, err := mountCommand(mountCtx, s.driver(spec), spec, credentialsDir, path)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Mounting bucket %s of sandbox %s on %s with %s", spec.Bucket, sandboxId, path, s.driver(spec))
+
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", s.driver(spec), err)
+	}
+
+	return waitForMount(mountCtx, path)
+}
+
+func (s *Service) validate(spec dto.BucketMountDTO) error {
+	credentials := spec.Credentials
+	if credentials == nil {
+		credentials = &dto.BucketCredentialsDTO{}
+	}
+
+	switch spec.Provider {
+	case dto.BucketProviderS3:
+		if (credentials.AccessKeyId == "") != (credentials.SecretAccessKey == "") {
+			return fmt.Errorf("bucket %s needs both an access key ID and a secret access key", spec.Bucket)
+		}
+		if credentials.AccessKeyId == "" && s.config.S3Driver == DriverGoofys {
+			return fmt.Errorf("bucket %s needs credentials, the runner can't mount buckets anonymously", spec.Bucket)
+		}
+	case dto.BucketProviderGCS:
+		if spec.Endpoint != "" {
+			return fmt.Errorf("bucket %s can't have an endpoint, GCS buckets are mounted from GCS", spec.Bucket)
+		}
+		if credentials.ServiceAccountKey != "" && !json.Valid([]byte(credentials.ServiceAccountKey)) {
+			return fmt.Errorf("the service account key of bucket %s is not valid JSON", spec.Bucket)
+		}
+	default:
+		return fmt.Errorf("unsupported bucket provider %s", spec.Provider)
+	}
+
+	if filepath.Clean(spec.MountPath) == "/" {
+		return errors.New("buckets can't be mounted on the root path")
+	}
+
+	return nil
+}
+
+func (s *Service) driver(spec dto.BucketMountDTO) Driver {
+	if spec.Provider == dto.BucketProviderGCS {
+		return DriverGcsfuse
+	}
+	return s.config.S3Driver
+}
+
+func (s *Service) sandbox(sandboxId string) *sandboxMounts {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sm, ok := s.sandboxes[sandboxId]
+	if !ok {
+		sm = &sandboxMounts{}
+		s.sandboxes[sandboxId] = sm
+	}
+	return sm
+}
+
+func (sm *sandboxMounts) resize(count int) {
+	for len(sm.status) < count {
+		sm.status = append(sm.status, mountStatus{})
+	}
+}
+
+func (s *Service) mountPath(sandboxId string, index int) string {
+	return filepath.Join(s.config.MountDir, sandboxId, strconv.Itoa(index))
+}
+
+func (s *Service) writeSpecs(sandboxId string, specs []dto.BucketMountDTO) error {
+	content, err := json.Marshal(specs)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.config.StateDir, sandboxId)
+	err = os.MkdirAll(dir, 0700)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, mountsFile), content, 0600)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save bucket mounts: %w", err)
+	}
+	return nil
+}
+
+// readSpecs returns the bucket mounts of a sandbox, none if it has none
+func (s *Service) readSpecs(sandboxId string) ([]dto.BucketMountDTO, error) {
+	content, err := os.ReadFile(filepath.Join(s.config.StateDir, sandboxId, mountsFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read bucket mounts: %w", err)
+	}
+
+	var specs []dto.BucketMountDTO
+	err = json.Unmarshal(content, &specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bucket mounts: %w", err)
+	}
+	return specs, nil
+}
+
+// makeSharedMountPoint bind mounts a directory on itself and makes it shared, so that mounts made
+// on it propagate to the containers it is bound into with slave propagation
+func makeSharedMountPoint(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), mountTimeout)
+	defer cancel()
+
+	if !isMountPoint(path) {
+		err := runMount(ctx, "mount", "--bind", path, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return runMount(ctx, "mount", "--make-shared", path)
+}
+
+// unmountBucket lazily unmounts the bucket mounted on a directory, if any, keeping the shared bind
+// mount of the directory
+func unmountBucket(ctx context.Context, path string) error {
+	fsType, err := topFilesystem(path)
+	if err != nil || !isFuse(fsType) {
+		return err
+	}
+
+	return runMount(ctx, "umount", "-l", path)
+}
+
+func runMount(ctx context.Context, name string, args ...string) error {
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// checkMount returns an error if no bucket is mounted on a directory or it doesn't respond. FUSE
+// mounts whose client exited fail with ENOTCONN, hung clients don't return at all.
+func checkMount(path string) error {
+	fsType, err := topFilesystem(path)
+	if err != nil {
+		return err
+	}
+	if !isFuse(fsType) {
+		return errors.New("bucket is not mounted")
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := os.Stat(path)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(responseTimeout):
+		return fmt.Errorf("bucket did not respond within %s", responseTimeout)
+	}
+}
+
+// waitForMount waits for a client to mount a bucket, as some daemonize before the mount is ready
+func waitForMount(ctx context.Context, path string) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		err := checkMount(path)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("bucket mount not ready: %w", err)
+		}
+	}
+}
+
+func isMountPoint(path string) bool {
+	fsType, err := topFilesystem(path)
+	return err == nil && fsType != ""
+}
+
+func isFuse(fsType string) bool {
+	return fsType == "fuse" || strings.HasPrefix(fsType, "fuse.")
+}
+
+// topFilesystem returns the type of the last filesystem mounted on a path, empty if the path is
+// not a mount point
+func topFilesystem(path string) (string, error) {
+	file, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	path = filepath.Clean(path)
+	fsType := ""
+
+	// <id> <parent id> <major:minor> <root> <mount point> <options> [<optional fields>] - <type> ...
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[4] != path {
+			continue
+		}
+		for i, field := range fields {
+			if field == "-" && i+1 < len(fields) {
+				fsType = fields[i+1]
+				break
+			}
+		}
+	}
+
+	return fsType, scanner.Err()
+}
@@ -5,6 +5,7 @@ package cache
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/daytonaio/runner/pkg/models"
@@ -16,6 +17,8 @@ import (
 type StatesCache struct {
 	common_cache.ICache[models.CachedStates]
 	cacheRetentionDays int
+	// Increases with every change of a sandbox or backup state
+	version atomic.Uint64
 }
 
 var statesCache *StatesCache
@@ -49,7 +52,10 @@ func (sc *StatesCache) SetSandboxState(ctx context.Context, sandboxId string, st
 	}
 
 	// Update sandbox state
-	existing.SandboxState = state
+	if existing.SandboxState != state {
+		existing.SandboxState = state
+		existing.Version = sc.version.Add(1)
+	}
 
 	// Save back to cache
 	_ = sc.Set(ctx, sandboxId, *existing, sc.getEntryExpiration())
@@ -63,6 +69,8 @@ func (sc *StatesCache) SetBackupState(ctx context.Context, sandboxId string, sta
 		existing = &models.CachedStates{}
 	}
 
+	previous := *existing
+
 	// Update backup state
 	existing.BackupState = state
 
@@ -74,10 +82,38 @@ func (sc *StatesCache) SetBackupState(ctx context.Context, sandboxId string, sta
 		existing.BackupErrorReason = nil
 	}
 
+	if previous.BackupState != existing.BackupState || errorReason(previous.BackupErrorReason) != errorReason(existing.BackupErrorReason) {
+		existing.Version = sc.version.Add(1)
+	}
+
 	// Save back to cache
 	_ = sc.Set(ctx, sandboxId, *existing, sc.getEntryExpiration())
 }
 
+func (sc *StatesCache) SetDaemonAuthToken(ctx context.Context, sandboxId string, token string) {
+	existing, err := sc.Get(ctx, sandboxId)
+	if err != nil {
+		existing = &models.CachedStates{}
+	}
+
+	existing.DaemonAuthToken = token
+
+	_ = sc.Set(ctx, sandboxId, *existing, sc.getEntryExpiration())
+}
+
+// Version returns the version of the cache, which increases with every change of a sandbox or
+// backup state
+func (sc *StatesCache) Version() uint64 {
+	return sc.version.Load()
+}
+
 func (sc *StatesCache) getEntryExpiration() time.Duration {
 	return time.Duration(sc.cacheRetentionDays) * 24 * time.Hour
 }
+
+func errorReason(reason *string) string {
+	if reason == nil {
+		return ""
+	}
+	return *reason
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package client is a typed client of the runner API. Requests and responses are the DTOs the
+// runner itself serves, so that the client can't drift from the server.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/runner/internal/constants"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+const (
+	// Diagnostics bundles and orphan reports can take a while on busy runners
+	defaultTimeout      = 5 * time.Minute
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 500 * time.Millisecond
+	// Longest wait between retries, also when the runner asks for a longer one
+	maxRetryWait = 30 * time.Second
+)
+
+// Client calls the API of a runner
+type Client struct {
+	url   string
+	token string
+	// Token of the /admin endpoints, the token if empty
+	adminToken string
+	// Version of the control API requests negotiate, the default version of the runner if 0
+	apiVersion   int
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+type Option func(*Client)
+
+// WithAdminToken sets the token of the /admin endpoints, when the runner has a separate one
+func WithAdminToken(token string) Option {
+	return func(c *Client) {
+		c.adminToken = token
+	}
+}
+
+// WithApiVersion sets the version of the control API requests negotiate with the runner
+func WithApiVersion(version int) Option {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// WithHTTPClient sets the HTTP client requests are sent with, e.g. to configure TLS
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how often a failed request is retried, and the backoff before the first retry,
+// which doubles with every retry
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+func New(url, token string, opts ...Option) *Client {
+	c := &Client{
+		url:          strings.TrimSuffix(url, "/"),
+		token:        token,
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		maxRetries:   defaultMaxRetries,
+		retryBackoff: defaultRetryBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.adminToken == "" {
+		c.adminToken = c.token
+	}
+
+	return c
+}
+
+// Error is returned when the runner responds with an error status
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// IsNotFound returns whether an error is a not found response of the runner
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Do sends a request with an optional JSON body and returns the response if it succeeded. The
+// caller closes its body. Requests the runner rejected before handling them, with 429 or 503,
+// are retried, as are requests that failed to connect if they are idempotent.
+func (c *Client) Do(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, payload)
+
+		wait, retry := c.shouldRetry(method, resp, err, attempt)
+		if !retry {
+			if err != nil {
+				return nil, err
+			}
+			if resp.StatusCode >= 300 {
+				return nil, readError(method, path, resp)
+			}
+			return resp, nil
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// call sends a request and decodes the JSON response into result, if set
+func (c *Client) call(ctx context.Context, method, path string, body, result any) error {
+	resp, err := c.Do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (c *Client) send(ctx context.Context, method, path string, payload []byte) (*http.Response, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	token := c.token
+	if strings.HasPrefix(path, "/admin/") {
+		token = c.adminToken
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if c.apiVersion != 0 && !strings.HasPrefix(path, "/admin/") {
+		req.Header.Set(constants.DAYTONA_API_VERSION_HEADER, strconv.Itoa(c.apiVersion))
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// The runner cancels the operations of the request when the caller gives up
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining > 0 {
+			req.Header.Set(constants.DAYTONA_REQUEST_TIMEOUT_HEADER, remaining.Round(time.Millisecond).String())
+		}
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// shouldRetry returns whether a request is retried and how long to wait before
+func (c *Client) shouldRetry(method string, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= c.maxRetries {
+		return 0, false
+	}
+
+	backoff := c.retryBackoff << attempt
+	// Jitter keeps clients that failed together from retrying together
+	backoff += time.Duration(rand.Int64N(int64(backoff)/2 + 1))
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		// The runner may have handled a request it failed to respond to
+		return min(backoff, maxRetryWait), isIdempotent(method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			return min(time.Duration(seconds)*time.Second, maxRetryWait), true
+		}
+		return min(backoff, maxRetryWait), true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return min(backoff, maxRetryWait), isIdempotent(method)
+	}
+
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func readError(method, path string, resp *http.Response) error {
+	defer resp.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	apiErr := &Error{
+		Method:     method,
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(message)),
+	}
+
+	var errorResponse common_errors.ErrorResponse
+	if json.Unmarshal(message, &errorResponse) == nil && errorResponse.Message != "" {
+		apiErr.Message = errorResponse.Message
+		apiErr.Code = errorResponse.Code
+	}
+
+	return apiErr
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Largest page the runner serves
+const maxPageLimit = 1000
+
+// ListOptions are the sort and pagination of a list request
+type ListOptions struct {
+	// Sort key, the default one of the list if empty
+	Sort       string
+	Descending bool
+	// Maximum number of items, all items if 0
+	Limit int
+	// Cursor of the page, returned with the previous page
+	Cursor string
+	// JSON fields of the items to return, all fields if empty. The other fields are left unset.
+	Fields []string
+}
+
+func (o ListOptions) apply(query url.Values) {
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Descending {
+		query.Set("order", "desc")
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if len(o.Fields) > 0 {
+		query.Set("fields", strings.Join(o.Fields, ","))
+	}
+}
+
+// list requests a page of a list and returns the cursor of the next page, empty on the last page
+func (c *Client) list(ctx context.Context, path string, query url.Values, result any) (string, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.Do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	return resp.Header.Get("X-Next-Cursor"), json.NewDecoder(resp.Body).Decode(result)
+}
+
+// listAll requests all pages of a list
+func listAll[T any](ctx context.Context, page func(ctx context.Context, opts ListOptions) ([]T, string, error)) ([]T, error) {
+	var items []T
+	opts := ListOptions{Limit: maxPageLimit}
+	for {
+		pageItems, cursor, err := page(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+		if cursor == "" {
+			return items, nil
+		}
+		opts.Cursor = cursor
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+func (c *Client) GetInfo(ctx context.Context) (*dto.RunnerInfoResponseDTO, error) {
+	var info dto.RunnerInfoResponseDTO
+	if err := c.call(ctx, http.MethodGet, "/info", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (c *Client) ReloadConfig(ctx context.Context) (*dto.ReloadConfigResponseDTO, error) {
+	var response dto.ReloadConfigResponseDTO
+	if err := c.call(ctx, http.MethodPost, "/config/reload", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) GetOrganizationUsage(ctx context.Context, organizationId string) (*dto.OrganizationUsageDTO, error) {
+	var usage dto.OrganizationUsageDTO
+	if err := c.call(ctx, http.MethodGet, "/organizations/"+url.PathEscape(organizationId)+"/usage", nil, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (c *Client) SetOrganizationQuota(ctx context.Context, organizationId string, quota dto.OrganizationQuotaDTO) error {
+	return c.call(ctx, http.MethodPut, "/organizations/"+url.PathEscape(organizationId)+"/quota", quota, nil)
+}
+
+func (c *Client) RemoveOrganizationQuota(ctx context.Context, organizationId string) error {
+	return c.call(ctx, http.MethodDelete, "/organizations/"+url.PathEscape(organizationId)+"/quota", nil, nil)
+}
+
+// ListAbuseEvents lists the abuse events, of a sandbox if sandboxId is set
+func (c *Client) ListAbuseEvents(ctx context.Context, sandboxId string) ([]dto.AbuseEventDTO, error) {
+	path := "/abuse/events"
+	if sandboxId != "" {
+		path += "?sandboxId=" + url.QueryEscape(sandboxId)
+	}
+
+	var events []dto.AbuseEventDTO
+	err := c.call(ctx, http.MethodGet, path, nil, &events)
+	return events, err
+}
+
+// JobFilter selects the jobs to list
+type JobFilter struct {
+	// Only jobs updated after this sequence, all jobs if 0
+	Since      int64
+	Statuses   []string
+	Types      []string
+	ResourceId string
+}
+
+// ListJobs lists the jobs updated after a sequence, all jobs if it is 0
+func (c *Client) ListJobs(ctx context.Context, since int64) ([]dto.JobDTO, error) {
+	return listAll(ctx, func(ctx context.Context, opts ListOptions) ([]dto.JobDTO, string, error) {
+		return c.ListJobsPage(ctx, JobFilter{Since: since}, opts)
+	})
+}
+
+// ListJobsPage lists a page of the jobs and returns the cursor of the next page, empty on the last
+// page
+func (c *Client) ListJobsPage(ctx context.Context, filter JobFilter, opts ListOptions) ([]dto.JobDTO, string, error) {
+	query := url.Values{"since": {strconv.FormatInt(filter.Since, 10)}}
+	for _, status := range filter.Statuses {
+		query.Add("status", status)
+	}
+	for _, jobType := range filter.Types {
+		query.Add("type", jobType)
+	}
+	if filter.ResourceId != "" {
+		query.Set("resourceId", filter.ResourceId)
+	}
+	opts.apply(query)
+
+	var jobs []dto.JobDTO
+	cursor, err := c.list(ctx, "/jobs", query, &jobs)
+	return jobs, cursor, err
+}
+
+func (c *Client) GetDrainStatus(ctx context.Context) (*dto.DrainStatusDTO, error) {
+	var status dto.DrainStatusDTO
+	if err := c.call(ctx, http.MethodGet, "/drain", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *Client) SetDrainStatus(ctx context.Context, draining bool) (*dto.DrainStatusDTO, error) {
+	var status dto.DrainStatusDTO
+	if err := c.call(ctx, http.MethodPost, "/drain", dto.DrainStatusDTO{Draining: draining}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *Client) ListNetRules(ctx context.Context) ([]dto.NetRulesChainDTO, error) {
+	var chains []dto.NetRulesChainDTO
+	err := c.call(ctx, http.MethodGet, "/netrules", nil, &chains)
+	return chains, err
+}
+
+func (c *Client) GetOrphanReport(ctx context.Context) (*dto.OrphanReportDTO, error) {
+	var report dto.OrphanReportDTO
+	if err := c.call(ctx, http.MethodGet, "/reaper/report", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (c *Client) ReapOrphans(ctx context.Context, request dto.ReapOrphansDTO) (*dto.OrphanReportDTO, error) {
+	var report dto.OrphanReportDTO
+	if err := c.call(ctx, http.MethodPost, "/reaper/run", request, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetDiagnostics returns the diagnostics bundle of the runner as a tar.gz archive. The caller
+// closes the archive.
+func (c *Client) GetDiagnostics(ctx context.Context) (io.ReadCloser, error) {
+	resp, err := c.Do(ctx, http.MethodGet, "/admin/diagnostics", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// ListFaults lists the injected faults, on runners built with fault injection
+func (c *Client) ListFaults(ctx context.Context) ([]dto.FaultDTO, error) {
+	var faults []dto.FaultDTO
+	err := c.call(ctx, http.MethodGet, "/admin/faults", nil, &faults)
+	return faults, err
+}
+
+func (c *Client) AddFault(ctx context.Context, fault dto.FaultDTO) (*dto.FaultDTO, error) {
+	var added dto.FaultDTO
+	if err := c.call(ctx, http.MethodPost, "/admin/faults", fault, &added); err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+func (c *Client) RemoveFault(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, "/admin/faults/"+url.PathEscape(id), nil, nil)
+}
+
+func (c *Client) ClearFaults(ctx context.Context) error {
+	return c.call(ctx, http.MethodDelete, "/admin/faults", nil, nil)
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+func sandboxPath(sandboxId string, suffix string) string {
+	return "/sandboxes/" + url.PathEscape(sandboxId) + suffix
+}
+
+// SandboxFilter selects the sandboxes to list
+type SandboxFilter struct {
+	States []string
+	// Container labels the sandboxes have
+	Labels map[string]string
+}
+
+// ListSandboxes lists the sandbox containers on the runner, oldest first
+func (c *Client) ListSandboxes(ctx context.Context) ([]dto.SandboxSummaryDTO, error) {
+	return listAll(ctx, func(ctx context.Context, opts ListOptions) ([]dto.SandboxSummaryDTO, string, error) {
+		return c.ListSandboxesPage(ctx, SandboxFilter{}, opts)
+	})
+}
+
+// ListSandboxesPage lists a page of the sandbox containers on the runner and returns the cursor of
+// the next page, empty on the last page
+func (c *Client) ListSandboxesPage(ctx context.Context, filter SandboxFilter, opts ListOptions) ([]dto.SandboxSummaryDTO, string, error) {
+	query := url.Values{}
+	for _, state := range filter.States {
+		query.Add("state", state)
+	}
+	for key, value := range filter.Labels {
+		query.Add("label", key+"="+value)
+	}
+	opts.apply(query)
+
+	var sandboxes []dto.SandboxSummaryDTO
+	cursor, err := c.list(ctx, "/sandboxes", query, &sandboxes)
+	return sandboxes, cursor, err
+}
+
+func (c *Client) CreateSandbox(ctx context.Context, createDto dto.CreateSandboxDTO) (*dto.StartSandboxResponse, error) {
+	var response dto.StartSandboxResponse
+	if err := c.call(ctx, http.MethodPost, "/sandboxes", createDto, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) GetSandbox(ctx context.Context, sandboxId string) (*dto.SandboxInfoResponse, error) {
+	var info dto.SandboxInfoResponse
+	if err := c.call(ctx, http.MethodGet, sandboxPath(sandboxId, ""), nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetSandboxFields returns only the given JSON fields of the sandbox info, e.g. "state" for callers
+// that poll the state, the other fields are left unset
+func (c *Client) GetSandboxFields(ctx context.Context, sandboxId string, fields ...string) (*dto.SandboxInfoResponse, error) {
+	path := sandboxPath(sandboxId, "")
+	if len(fields) > 0 {
+		path += "?fields=" + url.QueryEscape(strings.Join(fields, ","))
+	}
+
+	var info dto.SandboxInfoResponse
+	if err := c.call(ctx, http.MethodGet, path, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (c *Client) StartSandbox(ctx context.Context, sandboxId string, metadata map[string]string) (*dto.StartSandboxResponse, error) {
+	var response dto.StartSandboxResponse
+	if err := c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/start"), metadata, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) StopSandbox(ctx context.Context, sandboxId string) error {
+	return c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/stop"), nil, nil)
+}
+
+func (c *Client) DestroySandbox(ctx context.Context, sandboxId string) error {
+	return c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/destroy"), nil, nil)
+}
+
+// RemoveDestroyedSandbox removes the leftovers of a destroyed sandbox, destroying it first if it isn't
+func (c *Client) RemoveDestroyedSandbox(ctx context.Context, sandboxId string) error {
+	return c.call(ctx, http.MethodDelete, sandboxPath(sandboxId, ""), nil, nil)
+}
+
+// CreateBackup starts a backup of a sandbox, its progress is reported by GetSandbox
+func (c *Client) CreateBackup(ctx context.Context, sandboxId string, backupDto dto.CreateBackupDTO) error {
+	return c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/backup"), backupDto, nil)
+}
+
+func (c *Client) ResizeSandbox(ctx context.Context, sandboxId string, resizeDto dto.ResizeSandboxDTO) error {
+	return c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/resize"), resizeDto, nil)
+}
+
+func (c *Client) CloneSandbox(ctx context.Context, sandboxId string, cloneDto dto.CloneSandboxDTO) (*dto.StartSandboxResponse, error) {
+	var response dto.StartSandboxResponse
+	if err := c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/clone"), cloneDto, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) RecoverSandbox(ctx context.Context, sandboxId string, recoverDto dto.RecoverSandboxDTO) error {
+	return c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/recover"), recoverDto, nil)
+}
+
+func (c *Client) IsRecoverable(ctx context.Context, sandboxId string, request dto.IsRecoverableDTO) (*dto.IsRecoverableResponse, error) {
+	var response dto.IsRecoverableResponse
+	if err := c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/is-recoverable"), request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) UpdateNetworkSettings(ctx context.Context, sandboxId string, settings dto.UpdateNetworkSettingsDTO) error {
+	return c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/network-settings"), settings, nil)
+}
+
+func (c *Client) UpgradeDaemon(ctx context.Context, sandboxId string, upgradeDto dto.UpgradeDaemonDTO) (*dto.UpgradeDaemonResponse, error) {
+	var response dto.UpgradeDaemonResponse
+	if err := c.call(ctx, http.MethodPost, sandboxPath(sandboxId, "/daemon/upgrade"), upgradeDto, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) GetStorageUsage(ctx context.Context, sandboxId string) (*dto.SandboxStorageUsageDTO, error) {
+	var usage dto.SandboxStorageUsageDTO
+	if err := c.call(ctx, http.MethodGet, sandboxPath(sandboxId, "/storage"), nil, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (c *Client) GetStats(ctx context.Context, sandboxId string) (*dto.SandboxStatsDTO, error) {
+	var stats dto.SandboxStatsDTO
+	if err := c.call(ctx, http.MethodGet, sandboxPath(sandboxId, "/stats"), nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (c *Client) GetProvisioningStatus(ctx context.Context, sandboxId string) (*dto.ProvisioningStatusDTO, error) {
+	var status dto.ProvisioningStatusDTO
+	if err := c.call(ctx, http.MethodGet, sandboxPath(sandboxId, "/provisioning"), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *Client) GetEgressTraffic(ctx context.Context, sandboxId string) ([]dto.EgressTrafficDTO, error) {
+	var traffic []dto.EgressTrafficDTO
+	err := c.call(ctx, http.MethodGet, sandboxPath(sandboxId, "/egress"), nil, &traffic)
+	return traffic, err
+}
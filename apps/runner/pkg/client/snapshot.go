@@ -0,0 +1,81 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+func (c *Client) PullSnapshot(ctx context.Context, request dto.PullSnapshotRequestDTO) error {
+	return c.call(ctx, http.MethodPost, "/snapshots/pull", request, nil)
+}
+
+func (c *Client) BuildSnapshot(ctx context.Context, request dto.BuildSnapshotRequestDTO) error {
+	return c.call(ctx, http.MethodPost, "/snapshots/build", request, nil)
+}
+
+func (c *Client) TagImage(ctx context.Context, request dto.TagImageRequestDTO) error {
+	return c.call(ctx, http.MethodPost, "/snapshots/tag", request, nil)
+}
+
+func (c *Client) SnapshotExists(ctx context.Context, snapshot string) (bool, error) {
+	var response dto.SnapshotExistsResponse
+	err := c.call(ctx, http.MethodGet, "/snapshots/exists?snapshot="+url.QueryEscape(snapshot), nil, &response)
+	return response.Exists, err
+}
+
+func (c *Client) GetSnapshotInfo(ctx context.Context, snapshot string) (*dto.SnapshotInfoResponse, error) {
+	var info dto.SnapshotInfoResponse
+	if err := c.call(ctx, http.MethodGet, "/snapshots/info?snapshot="+url.QueryEscape(snapshot), nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (c *Client) RemoveSnapshot(ctx context.Context, snapshot string) error {
+	return c.call(ctx, http.MethodPost, "/snapshots/remove?snapshot="+url.QueryEscape(snapshot), nil, nil)
+}
+
+func (c *Client) InspectSnapshotInRegistry(ctx context.Context, request dto.InspectSnapshotInRegistryRequestDTO) (*dto.SnapshotDigestResponse, error) {
+	var digest dto.SnapshotDigestResponse
+	if err := c.call(ctx, http.MethodPost, "/snapshots/inspect", request, &digest); err != nil {
+		return nil, err
+	}
+	return &digest, nil
+}
+
+// GetBuildLogs returns the build logs of a snapshot, which are streamed until the build completes
+// if follow is set. The caller closes the logs.
+func (c *Client) GetBuildLogs(ctx context.Context, snapshotRef string, follow bool) (io.ReadCloser, error) {
+	query := url.Values{"snapshotRef": {snapshotRef}}
+	if follow {
+		query.Set("follow", "true")
+	}
+
+	resp, err := c.Do(ctx, http.MethodGet, "/snapshots/logs?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// ExportSnapshot returns a snapshot as an image archive, compressed with the compression of the
+// runner if empty. The caller closes the archive.
+func (c *Client) ExportSnapshot(ctx context.Context, snapshot, compression string) (io.ReadCloser, error) {
+	query := url.Values{"snapshot": {snapshot}}
+	if compression != "" {
+		query.Set("compression", compression)
+	}
+
+	resp, err := c.Do(ctx, http.MethodGet, "/snapshots/export?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
 )
 
 func GetContainerIpAddress(ctx context.Context, container container.InspectResponse) string {
@@ -20,3 +22,85 @@ func GetContainerIpAddress(ctx context.Context, container container.InspectRespo
 
 	return ""
 }
+
+// Prefix of the labels the runner sets on sandbox containers
+const RUNNER_LABEL_PREFIX = "daytona."
+
+// Marks the containers of sandboxes, as opposed to sidecars and other containers on the runner
+const SANDBOX_LABEL = "daytona.sandbox"
+
+// Containers with this label resolve only the listed comma-separated domains through the runner DNS forwarder
+const DNS_ALLOWED_DOMAINS_LABEL = "daytona.dns_allowed_domains"
+
+// Containers with this label have their HTTP(S) traffic redirected to the runner egress proxy,
+// which only lets it through to the listed comma-separated domains
+const EGRESS_ALLOWED_DOMAINS_LABEL = "daytona.egress_allowed_domains"
+
+// Proxy the egress proxy forwards the allowed traffic of a container through
+const EGRESS_UPSTREAM_PROXY_LABEL = "daytona.egress_upstream_proxy"
+
+// Upstream proxy of a container with its credentials, encrypted with the runner WireGuard key
+const EGRESS_UPSTREAM_PROXY_ENCRYPTED_LABEL = "daytona.egress_upstream_proxy_encrypted"
+
+// Encrypted WireGuard tunnel config of a container
+const WIREGUARD_CONFIG_LABEL = "daytona.wireguard_config"
+
+// Comma-separated names of the env variables of a container delivered encrypted, which are kept
+// out of its backups
+const SECRET_ENV_KEYS_LABEL = "daytona.secret_env_keys"
+
+// Containers with this label run on dedicated CPUs of a single NUMA node
+const CPU_PINNING_LABEL = "daytona.cpu_pinning"
+
+// ID of the sandbox a sidecar container belongs to
+const SIDECAR_OF_LABEL = "daytona.sidecar_of"
+
+// Name of a sidecar container within its sandbox
+const SIDECAR_NAME_LABEL = "daytona.sidecar_name"
+
+// Set on the networks the runner creates, the only ones it removes once no container uses them
+const RUNNER_NETWORK_LABEL = "daytona.runner_network"
+
+// Mode of the nested Docker daemon of a container
+const NESTED_DOCKER_LABEL = "daytona.nested_docker"
+
+// Set on sandboxes the daemon runs provisioning steps in on their first boot
+const PROVISIONING_LABEL = "daytona.provisioning"
+
+// Set on sandboxes the daemon applies the dotfiles of the user in on their first boot
+const DOTFILES_LABEL = "daytona.dotfiles"
+
+// Set on sandboxes configured to download packages through the runner package cache
+const PACKAGE_CACHE_LABEL = "daytona.package_cache"
+
+// Time at which the sandbox expires, in RFC 3339
+const EXPIRES_AT_LABEL = "daytona.expires_at"
+
+// What the runner does with the sandbox once it expires, stop or destroy
+const EXPIRY_POLICY_LABEL = "daytona.expiry_policy"
+
+// FindContainerByIpAddress returns the running container with the label and IP address, or nil
+// if there is none
+func FindContainerByIpAddress(ctx context.Context, apiClient client.APIClient, ipAddress string, label string) (*container.Summary, error) {
+	containers, err := apiClient.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", label)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range containers {
+		// Images committed from sandboxes carry the runner labels without values
+		if c.NetworkSettings == nil || c.Labels[label] == "" {
+			continue
+		}
+
+		for _, network := range c.NetworkSettings.Networks {
+			if network != nil && network.IPAddress == ipAddress {
+				return &c, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -4,3 +4,9 @@
 package common
 
 const DAEMON_PATH = "/usr/local/bin/daytona"
+
+// The daemon of a sandbox requires the secret passed to it in DAEMON_AUTH_TOKEN_ENV on all requests
+const (
+	DAEMON_AUTH_TOKEN_ENV    = "DAYTONA_DAEMON_AUTH_TOKEN"
+	DAEMON_AUTH_TOKEN_HEADER = "X-Daytona-Daemon-Token"
+)
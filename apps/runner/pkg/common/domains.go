@@ -0,0 +1,31 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package common
+
+import "strings"
+
+// ParseDomainList parses a comma-separated list of domains into lowercase domains without
+// leading or trailing dots
+func ParseDomainList(list string) []string {
+	var domains []string
+	for _, domain := range strings.Split(list, ",") {
+		if domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), "."); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// IsDomainAllowed reports if a host name is one of the domains or their subdomains
+func IsDomainAllowed(name string, domains []string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+	for _, domain := range domains {
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
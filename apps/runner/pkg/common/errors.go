@@ -4,19 +4,86 @@
 package common
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/daytonaio/runner/internal/util"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
 	"github.com/gin-gonic/gin"
 
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
 )
 
+// ResourceExhaustedError is returned when the runner refuses work because it lacks capacity.
+// RetryAfter is a hint for when the caller may try again.
+type ResourceExhaustedError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *ResourceExhaustedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %s)", e.Message, e.RetryAfter)
+	}
+	return e.Message
+}
+
+func NewResourceExhaustedError(statusCode int, code, message string, retryAfter time.Duration) error {
+	return &ResourceExhaustedError{
+		StatusCode: statusCode,
+		Code:       code,
+		Message:    message,
+		RetryAfter: retryAfter,
+	}
+}
+
 func HandlePossibleDockerError(ctx *gin.Context, err error) common_errors.ErrorResponse {
+	var exhaustedErr *ResourceExhaustedError
+	if errors.As(err, &exhaustedErr) {
+		if exhaustedErr.RetryAfter > 0 {
+			ctx.Header("Retry-After", strconv.Itoa(int(exhaustedErr.RetryAfter.Seconds())))
+		}
+		return common_errors.ErrorResponse{
+			StatusCode: exhaustedErr.StatusCode,
+			Message:    exhaustedErr.Error(),
+			Code:       exhaustedErr.Code,
+			Timestamp:  time.Now(),
+			Path:       ctx.Request.URL.Path,
+			Method:     ctx.Request.Method,
+		}
+	}
+
+	var conflictErr *sandboxlock.ConflictError
+	if errors.As(err, &conflictErr) {
+		return common_errors.ErrorResponse{
+			StatusCode: http.StatusConflict,
+			Message:    conflictErr.Error(),
+			Code:       "SANDBOX_OPERATION_CONFLICT",
+			Timestamp:  time.Now(),
+			Path:       ctx.Request.URL.Path,
+			Method:     ctx.Request.Method,
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return common_errors.ErrorResponse{
+			StatusCode: http.StatusGatewayTimeout,
+			Message:    fmt.Sprintf("deadline exceeded: %s", err.Error()),
+			Code:       "DEADLINE_EXCEEDED",
+			Timestamp:  time.Now(),
+			Path:       ctx.Request.URL.Path,
+			Method:     ctx.Request.Method,
+		}
+	}
+
 	if errdefs.IsUnauthorized(err) || strings.Contains(err.Error(), "unauthorized") {
 		return common_errors.ErrorResponse{
 			StatusCode: http.StatusUnauthorized,
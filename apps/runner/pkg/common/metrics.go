@@ -36,4 +36,39 @@ var (
 		},
 		[]string{"operation", "status"},
 	)
+
+	// Counter to track copies of sandbox data that differ from their source after copying
+	CopyVerificationFailureCount = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "copy_verification_failure_total",
+			Help: "Total number of sandbox data copies that differ from their source",
+		},
+	)
+
+	// Counter to track container inspections served from the inspect cache or from Docker
+	ContainerInspectCacheCount = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "container_inspect_cache_total",
+			Help: "Total number of container inspections, by whether the inspect cache served them",
+		},
+		[]string{"result"},
+	)
+
+	// Counter to track Docker events dropped because the queue of a monitor subscription was full
+	DockerEventsDroppedCount = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "docker_events_dropped_total",
+			Help: "Total number of Docker events dropped by monitor subscriptions",
+		},
+		[]string{"subscription"},
+	)
+
+	// Counter to track panics of the handlers of monitor subscriptions
+	DockerEventHandlerPanicCount = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "docker_event_handler_panics_total",
+			Help: "Total number of panics of Docker event handlers",
+		},
+		[]string{"subscription"},
+	)
 )
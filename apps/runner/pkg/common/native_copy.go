@@ -0,0 +1,366 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const copyBufferSize = 1024 * 1024
+
+// NativeCopy copies the contents of srcPath to destPath without external tools. Like rsync -aAX it
+// preserves modes, ownership, timestamps, symlinks, device nodes, which include the whiteouts of
+// overlay layers, and extended attributes, which include ACLs and the opaque directories of overlay
+// layers. The holes of sparse files are preserved. Extended attributes the destination filesystem
+// doesn't support are skipped.
+func NativeCopy(ctx context.Context, srcPath, destPath string, config CopyConfig) error {
+	return nativeCopy(ctx, srcPath, destPath, config, false)
+}
+
+func nativeCopy(ctx context.Context, srcPath, destPath string, config CopyConfig, reflink bool) error {
+	if config.StallTimeout <= 0 {
+		config.StallTimeout = defaultCopyStallLimit
+	}
+
+	src := filepath.Clean(srcPath)
+	dest := filepath.Clean(destPath)
+	log.Debugf("native copy from %s to %s", src, dest)
+
+	entries, err := measureEntries(src + "/")
+	if err != nil {
+		return fmt.Errorf("failed to measure %s: %w", src, err)
+	}
+
+	tracker := newCopyTracker(entries, 1)
+
+	copyCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	reportDone := make(chan struct{})
+	go func() {
+		defer close(reportDone)
+		tracker.report(copyCtx, cancel, config.StallTimeout)
+	}()
+
+	c := &nativeCopier{
+		ctx:     copyCtx,
+		buffer:  make([]byte, copyBufferSize),
+		tracker: tracker,
+		reflink: reflink,
+	}
+	err = c.copyTree(src, dest)
+	cancel(nil)
+	<-reportDone
+	if errors.Is(err, ErrReflinkUnsupported) {
+		return err
+	}
+	if err != nil {
+		if cause := context.Cause(copyCtx); errors.Is(cause, errCopyStalled) {
+			return fmt.Errorf("native copy failed: %w", cause)
+		}
+		return fmt.Errorf("native copy failed: %w", err)
+	}
+
+	tracker.complete(ctx)
+	log.Infof("Successfully completed native copy, %d files cloned", c.cloned)
+	return nil
+}
+
+type nativeCopier struct {
+	ctx     context.Context
+	buffer  []byte
+	tracker *copyTracker
+	copied  int64
+	files   int64
+	// Clone the extents of regular files instead of copying their data
+	reflink bool
+	cloned  int64
+	// Whether unsupported extended attributes were already logged
+	xattrsWarned bool
+}
+
+func (c *nativeCopier) copyTree(src, dest string) error {
+	// Directories get their attributes after their contents, which change their mtime
+	var dirs []string
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := c.ctx.Err(); err != nil {
+			return context.Cause(c.ctx)
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		var stat unix.Stat_t
+		if err := unix.Lstat(path, &stat); err != nil {
+			return &fs.PathError{Op: "lstat", Path: path, Err: err}
+		}
+
+		switch d.Type() & fs.ModeType {
+		case fs.ModeDir:
+			err = os.Mkdir(target, 0700)
+			if err != nil && !errors.Is(err, fs.ErrExist) {
+				return err
+			}
+			dirs = append(dirs, rel)
+			c.files++
+			c.tracker.update(0, c.copied, c.tracker.filesTotal-c.files, c.tracker.filesTotal)
+			return nil
+		case 0:
+			err = c.copyFile(path, target, &stat)
+		case fs.ModeSymlink:
+			err = copySymlink(path, target)
+		default:
+			// Device nodes, FIFOs and sockets
+			err = replace(target, func() error {
+				return unix.Mknod(target, stat.Mode, int(stat.Rdev))
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		c.files++
+		c.tracker.update(0, c.copied, c.tracker.filesTotal-c.files, c.tracker.filesTotal)
+
+		return c.copyAttributes(path, target, &stat)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range slices.Backward(dirs) {
+		path := filepath.Join(src, rel)
+
+		var stat unix.Stat_t
+		if err := unix.Lstat(path, &stat); err != nil {
+			return &fs.PathError{Op: "lstat", Path: path, Err: err}
+		}
+
+		if err := c.copyAttributes(path, filepath.Join(dest, rel), &stat); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyFile copies a regular file, seeking over its holes so that they stay holes
+func (c *nativeCopier) copyFile(path, target string, stat *unix.Stat_t) error {
+	srcFile, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	destFile, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	if c.reflink {
+		cloned, err := c.cloneFile(srcFile, destFile, stat)
+		if err != nil {
+			return err
+		}
+		if cloned {
+			return destFile.Close()
+		}
+	}
+
+	fd := int(srcFile.Fd())
+	var offset int64
+	for offset < stat.Size {
+		data, err := unix.Seek(fd, offset, unix.SEEK_DATA)
+		if errors.Is(err, unix.ENXIO) {
+			// Only a hole is left
+			break
+		}
+		if err != nil {
+			// The filesystem can't find holes, the file is copied as a whole
+			data = offset
+		}
+
+		hole, err := unix.Seek(fd, data, unix.SEEK_HOLE)
+		if err != nil {
+			hole = stat.Size
+		}
+
+		if err := c.copyRange(srcFile, destFile, data, hole-data); err != nil {
+			return err
+		}
+		offset = hole
+	}
+
+	// Sets the size of files ending with a hole
+	if err := destFile.Truncate(stat.Size); err != nil {
+		return err
+	}
+
+	return destFile.Close()
+}
+
+func (c *nativeCopier) copyRange(srcFile, destFile *os.File, offset, length int64) error {
+	reader := io.NewSectionReader(srcFile, offset, length)
+	for {
+		if err := c.ctx.Err(); err != nil {
+			return context.Cause(c.ctx)
+		}
+
+		n, err := reader.Read(c.buffer)
+		if n > 0 {
+			if _, err := destFile.WriteAt(c.buffer[:n], offset); err != nil {
+				return err
+			}
+			offset += int64(n)
+			c.copied += int64(n)
+			c.tracker.update(0, c.copied, c.tracker.filesTotal-c.files, c.tracker.filesTotal)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func copySymlink(path, target string) error {
+	link, err := os.Readlink(path)
+	if err != nil {
+		return err
+	}
+	return replace(target, func() error {
+		return os.Symlink(link, target)
+	})
+}
+
+// replace creates a file with create, replacing an existing file that isn't a directory
+func replace(target string, create func() error) error {
+	err := create()
+	if !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	if err := os.Remove(target); err != nil {
+		return err
+	}
+	return create()
+}
+
+// copyAttributes copies the ownership, mode, extended attributes and timestamps of a file. The
+// mode is set after the ownership, as changing the owner clears the setuid and setgid bits.
+func (c *nativeCopier) copyAttributes(path, target string, stat *unix.Stat_t) error {
+	if err := unix.Lchown(target, int(stat.Uid), int(stat.Gid)); err != nil {
+		return &fs.PathError{Op: "lchown", Path: target, Err: err}
+	}
+
+	isSymlink := stat.Mode&unix.S_IFMT == unix.S_IFLNK
+	if !isSymlink {
+		if err := unix.Chmod(target, stat.Mode&07777); err != nil {
+			return &fs.PathError{Op: "chmod", Path: target, Err: err}
+		}
+	}
+
+	if err := c.copyXattrs(path, target); err != nil {
+		return err
+	}
+
+	times := []unix.Timespec{stat.Atim, stat.Mtim}
+	if err := unix.UtimesNanoAt(unix.AT_FDCWD, target, times, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return &fs.PathError{Op: "utimes", Path: target, Err: err}
+	}
+
+	return nil
+}
+
+// Prefix of the extended attributes overlayfs keeps its metadata in
+const overlayXattrPrefix = "trusted.overlay."
+
+func (c *nativeCopier) copyXattrs(path, target string) error {
+	names, err := listXattrs(path)
+	if err != nil {
+		if errors.Is(err, unix.ENOTSUP) {
+			return nil
+		}
+		return &fs.PathError{Op: "llistxattr", Path: path, Err: err}
+	}
+
+	for _, name := range names {
+		value, err := getXattr(path, name)
+		if err != nil {
+			return &fs.PathError{Op: "lgetxattr", Path: path, Err: err}
+		}
+
+		err = unix.Lsetxattr(target, name, value, 0)
+		// Symlinks can't have user attributes, and the destination may not support the attribute.
+		// Overlay attributes mark opaque directories and redirects, without them the copy of an upper
+		// directory exposes files of the image that were deleted or renamed.
+		if (errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EPERM)) && !strings.HasPrefix(name, overlayXattrPrefix) {
+			if !c.xattrsWarned {
+				log.Warnf("Skipping extended attribute %s of %s: %v", name, path, err)
+				c.xattrsWarned = true
+			}
+			continue
+		}
+		if err != nil {
+			return &fs.PathError{Op: "lsetxattr", Path: target, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func listXattrs(path string) ([]string, error) {
+	size, err := unix.Llistxattr(path, nil)
+	if err != nil || size == 0 {
+		return nil, err
+	}
+
+	buffer := make([]byte, size)
+	size, err = unix.Llistxattr(path, buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range strings.SplitSeq(string(buffer[:size]), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func getXattr(path, name string) ([]byte, error) {
+	size, err := unix.Lgetxattr(path, name, nil)
+	if err != nil || size == 0 {
+		return nil, err
+	}
+
+	value := make([]byte, size)
+	size, err = unix.Lgetxattr(path, name, value)
+	if err != nil {
+		return nil, err
+	}
+	return value[:size], nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrReflinkUnsupported is returned by ReflinkCopy when the filesystem can't share extents
+// between files
+var ErrReflinkUnsupported = errors.New("reflinks are not supported")
+
+// ReflinkCopy copies like NativeCopy, but clones the extents of regular files with the FICLONE
+// ioctl instead of copying their data. On filesystems with reflinks, e.g. XFS created with
+// reflink=1, it takes seconds regardless of the size of the files, and the blocks are only
+// duplicated once either file is written. If the first file can't be cloned, ErrReflinkUnsupported
+// is returned before any data is copied, so that the caller copies the data instead.
+func ReflinkCopy(ctx context.Context, srcPath, destPath string, config CopyConfig) error {
+	return nativeCopy(ctx, srcPath, destPath, config, true)
+}
+
+// cloneFile clones a regular file, and returns false if it needs to be copied instead
+func (c *nativeCopier) cloneFile(srcFile, destFile *os.File, stat *unix.Stat_t) (bool, error) {
+	err := unix.IoctlFileClone(int(destFile.Fd()), int(srcFile.Fd()))
+	if err == nil {
+		c.cloned++
+		c.copied += stat.Size
+		c.tracker.update(0, c.copied, c.tracker.filesTotal-c.files, c.tracker.filesTotal)
+		return true, nil
+	}
+
+	if !isCloneUnsupported(err) {
+		return false, &os.PathError{Op: "ficlone", Path: destFile.Name(), Err: err}
+	}
+	if c.cloned == 0 {
+		return false, ErrReflinkUnsupported
+	}
+
+	// Some files can't be cloned even where reflinks are supported, e.g. swap files
+	return false, nil
+}
+
+func isCloneUnsupported(err error) bool {
+	return errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.ENOTTY) || errors.Is(err, unix.EXDEV) || errors.Is(err, unix.EINVAL)
+}
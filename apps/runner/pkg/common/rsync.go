@@ -4,50 +4,457 @@
 package common
 
 import (
+	"bufio"
+	"bytes"
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// Partially transferred files are kept here, so that a retry resumes them. rsync removes the
+	// directory once the transfer completes.
+	rsyncPartialDir = ".daytona-rsync-partial"
+	// Retries of a failed worker, which only transfers what the failed attempt didn't
+	rsyncRetries          = 2
+	copyProgressInterval  = 5 * time.Second
+	defaultCopyWorkers    = 4
+	defaultCopyStallLimit = 5 * time.Minute
+)
+
+// Matches the --info=progress2 lines of rsync, e.g.
+// "  1,238,099  45%  146.38kB/s    0:00:08 (xfr#5, to-chk=4/9)"
+var rsyncProgressRegex = regexp.MustCompile(`^\s*([\d,]+)\s+\d+%.*?(?:\(xfr#\d+, (?:to|ir)-chk=(\d+)/(\d+)\))?\s*$`)
+
+var errCopyStalled = errors.New("copy made no progress")
+
+// CopyConfig configures the copies of RsyncCopy
+type CopyConfig struct {
+	// Parallel rsync processes, the top-level entries of the source are split across them
+	Workers int
+	// Bandwidth of all workers together in KiB/s, 0 for no limit
+	BandwidthLimitKBps int
+	// Copies that make no progress for this long fail, which replaces a timeout of the whole
+	// copy as large sandboxes take arbitrarily long
+	StallTimeout time.Duration
+	// Clone the files of the source with reflinks where the filesystem supports them, see ReflinkCopy
+	Reflink bool
+	// How the copies of sandbox data are compared with their source, see VerifyCopy
+	Verify VerifyMode
+}
+
+// CopyProgress is the progress of a copy
+type CopyProgress struct {
+	BytesTotal  int64
+	BytesCopied int64
+	FilesTotal  int64
+	FilesCopied int64
+	// Estimated time until the copy completes, 0 if unknown
+	Eta time.Duration
+}
+
+type copyProgressKey struct{}
+
+// WithCopyProgress returns a context that reports the progress of the copies made with it
+func WithCopyProgress(ctx context.Context, report func(CopyProgress)) context.Context {
+	return context.WithValue(ctx, copyProgressKey{}, report)
+}
+
 // RsyncCopy copies files from srcPath to destPath using rsync with full attribute preservation.
 // It uses rsync with -aAX flags to preserve permissions, ownership, timestamps, symlinks,
 // devices, ACLs, and extended attributes.
 //
-// The timeout parameter specifies how long to wait for the rsync operation to complete.
-// Trailing slashes are automatically added to paths to ensure contents are copied, not directories.
-func RsyncCopy(ctx context.Context, srcPath, destPath string) error {
-	log.Debugf("rsync copy from %s to %s", srcPath, destPath)
-
-	// Use rsync with -aAX flags:
-	// -a = archive mode (preserves permissions, ownership, timestamps, symlinks, devices)
-	// -A = preserve ACLs
-	// -X = preserve extended attributes (xattrs)
+// The top-level entries of srcPath are split across parallel rsync workers by size. Failed
+// workers are retried and resume the files they transferred partially. The progress is logged
+// and reported to the context, see WithCopyProgress.
+//
+// Hosts without rsync, or whose rsync lacks ACL or xattr support, copy with NativeCopy instead.
+func RsyncCopy(ctx context.Context, srcPath, destPath string, config CopyConfig) error {
+	if config.Workers <= 0 {
+		config.Workers = defaultCopyWorkers
+	}
+	if config.StallTimeout <= 0 {
+		config.StallTimeout = defaultCopyStallLimit
+	}
+
+	if _, err := exec.LookPath("rsync"); err != nil {
+		log.Warnf("rsync is unavailable, copying %s natively: %v", srcPath, err)
+		return NativeCopy(ctx, srcPath, destPath, config)
+	}
+
+	err := rsyncCopy(ctx, srcPath, destPath, config)
+	if err != nil && isRsyncUnsupported(err) {
+		log.Warnf("rsync can't preserve all attributes of %s, copying natively: %v", srcPath, err)
+		return NativeCopy(ctx, srcPath, destPath, config)
+	}
+	return err
+}
+
+func rsyncCopy(ctx context.Context, srcPath, destPath string, config CopyConfig) error {
 	// Trailing slashes ensure we copy contents, not the directory itself
 	src := filepath.Clean(srcPath) + "/"
 	dest := filepath.Clean(destPath) + "/"
-	rsyncCmd := exec.CommandContext(ctx, "rsync", "-aAX", src, dest)
+	log.Debugf("rsync copy from %s to %s", src, dest)
+
+	entries, err := measureEntries(src)
+	if err != nil {
+		return fmt.Errorf("failed to measure %s: %w", src, err)
+	}
+
+	groups := splitEntries(entries, config.Workers)
+
+	bwLimit := 0
+	if config.BandwidthLimitKBps > 0 {
+		bwLimit = max(config.BandwidthLimitKBps/max(len(groups), 1), 1)
+	}
+
+	tracker := newCopyTracker(entries, len(groups))
+
+	copyCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	reportDone := make(chan struct{})
+	go func() {
+		defer close(reportDone)
+		tracker.report(copyCtx, cancel, config.StallTimeout)
+	}()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(groups))
+	for i, group := range groups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = runRsyncWorker(copyCtx, src, dest, group, bwLimit, func(copied, remaining, total int64) {
+				tracker.update(i, copied, remaining, total)
+			})
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		cancel(nil)
+		<-reportDone
+		if cause := context.Cause(copyCtx); errors.Is(cause, errCopyStalled) {
+			return fmt.Errorf("rsync failed: %w", cause)
+		}
+		return fmt.Errorf("rsync failed: %w", err)
+	}
+
+	// The attributes of the top-level directory, copied last as the workers change its mtime
+	err = runRsync(copyCtx, []string{"-aAX", "--no-recursive", "--dirs", src, dest}, nil, nil)
+	cancel(nil)
+	<-reportDone
+	if err != nil {
+		return fmt.Errorf("rsync failed: %w", err)
+	}
+
+	tracker.complete(ctx)
+	log.Info("Successfully completed rsync copy")
+	return nil
+}
+
+// copyEntry is a top-level entry of the source of a copy
+type copyEntry struct {
+	name  string
+	bytes int64
+	files int64
+}
+
+func measureEntries(src string) ([]copyEntry, error) {
+	dirEntries, err := os.ReadDir(src)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]copyEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		entry := copyEntry{name: dirEntry.Name()}
+
+		err := filepath.WalkDir(filepath.Join(src, entry.name), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			entry.files++
+			if d.Type().IsRegular() {
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				entry.bytes += info.Size()
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// splitEntries splits the entries into at most n groups of about the same size, largest first
+func splitEntries(entries []copyEntry, n int) [][]copyEntry {
+	sorted := slices.Clone(entries)
+	slices.SortFunc(sorted, func(a, b copyEntry) int {
+		return cmp.Compare(b.bytes, a.bytes)
+	})
+
+	groups := make([][]copyEntry, min(n, len(sorted)))
+	sizes := make([]int64, len(groups))
+	for _, entry := range sorted {
+		smallest := slices.Index(sizes, slices.Min(sizes))
+		groups[smallest] = append(groups[smallest], entry)
+		sizes[smallest] += entry.bytes
+	}
+
+	return groups
+}
+
+// runRsyncWorker copies a group of top-level entries, retrying with the partial files of failed
+// attempts. It reports the bytes it transferred and the files rsync has left to check.
+func runRsyncWorker(ctx context.Context, src, dest string, group []copyEntry, bwLimit int, progress func(copied, remaining, total int64)) error {
+	// Names are separated by NUL, as they may contain newlines
+	var fileList strings.Builder
+	for _, entry := range group {
+		fileList.WriteString(entry.name)
+		fileList.WriteByte(0)
+	}
+
+	// --files-from disables the recursion of -a
+	args := []string{"-aAX", "--recursive", "--files-from=-", "--from0", "--partial-dir=" + rsyncPartialDir, "--info=progress2", "--no-inc-recursive"}
+	if bwLimit > 0 {
+		args = append(args, fmt.Sprintf("--bwlimit=%d", bwLimit))
+	}
+	args = append(args, src, dest)
+
+	// Bytes of previous attempts, a retry only reports what it transferred itself
+	var transferred int64
+	var err error
+	for attempt := 0; attempt <= rsyncRetries; attempt++ {
+		if attempt > 0 {
+			log.Warnf("Retrying rsync of %d entries of %s: %v", len(group), src, err)
+		}
+
+		var attemptBytes int64
+		err = runRsync(ctx, args, strings.NewReader(fileList.String()), func(copied, remaining, total int64) {
+			attemptBytes = copied
+			progress(transferred+copied, remaining, total)
+		})
+		if err == nil || ctx.Err() != nil || isRsyncUnsupported(err) {
+			return err
+		}
+		transferred += attemptBytes
+	}
+
+	return err
+}
+
+// runRsync runs rsync and reports the progress it prints with --info=progress2
+func runRsync(ctx context.Context, args []string, stdin io.Reader, progress func(copied, remaining, total int64)) error {
+	rsyncCmd := exec.CommandContext(ctx, "rsync", args...)
+	rsyncCmd.Stdin = stdin
 
-	var rsyncOut strings.Builder
 	var rsyncErr strings.Builder
-	rsyncCmd.Stdout = &rsyncOut
 	rsyncCmd.Stderr = &rsyncErr
 
-	log.Debug("Starting rsync...")
-	if err := rsyncCmd.Run(); err != nil {
+	stdout, err := rsyncCmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := rsyncCmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	// Progress lines are terminated by carriage returns
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+	for scanner.Scan() {
+		matches := rsyncProgressRegex.FindStringSubmatch(scanner.Text())
+		if matches == nil || progress == nil {
+			continue
+		}
+
+		copied, _ := strconv.ParseInt(strings.ReplaceAll(matches[1], ",", ""), 10, 64)
+		remaining, total := int64(-1), int64(-1)
+		if matches[2] != "" {
+			remaining, _ = strconv.ParseInt(matches[2], 10, 64)
+			total, _ = strconv.ParseInt(matches[3], 10, 64)
+		}
+		progress(copied, remaining, total)
+	}
+	_, _ = io.Copy(io.Discard, stdout)
+
+	if err := rsyncCmd.Wait(); err != nil {
 		if errMsg := rsyncErr.String(); errMsg != "" {
 			log.Errorf("rsync stderr: %s", errMsg)
 		}
-		return fmt.Errorf("rsync failed: %w", err)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &rsyncError{exitCode: exitErr.ExitCode(), stderr: strings.TrimSpace(rsyncErr.String())}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Exit code of rsync when an option isn't supported
+const rsyncExitUnsupported = 4
+
+type rsyncError struct {
+	exitCode int
+	stderr   string
+}
+
+func (e *rsyncError) Error() string {
+	if e.stderr == "" {
+		return fmt.Sprintf("rsync exited with code %d", e.exitCode)
 	}
+	return fmt.Sprintf("rsync exited with code %d: %s", e.exitCode, e.stderr)
+}
 
-	if outMsg := rsyncOut.String(); outMsg != "" {
-		log.Debugf("rsync output: %s", outMsg)
+// isRsyncUnsupported returns whether rsync failed because it, or the filesystem, doesn't support
+// an attribute it was asked to preserve, e.g. "ACLs are not supported on this client"
+func isRsyncUnsupported(err error) bool {
+	var rsyncErr *rsyncError
+	if !errors.As(err, &rsyncErr) {
+		return false
 	}
+	return rsyncErr.exitCode == rsyncExitUnsupported || strings.Contains(rsyncErr.stderr, "not supported")
+}
 
-	log.Info("Successfully completed rsync copy")
-	return nil
+// copyTracker sums the progress of the workers of a copy
+type copyTracker struct {
+	mutex      sync.Mutex
+	startedAt  time.Time
+	bytesTotal int64
+	filesTotal int64
+	workers    []workerProgress
+}
+
+type workerProgress struct {
+	bytes     int64
+	remaining int64
+	total     int64
+}
+
+func newCopyTracker(entries []copyEntry, workers int) *copyTracker {
+	tracker := &copyTracker{
+		startedAt: time.Now(),
+		workers:   make([]workerProgress, workers),
+	}
+	for _, entry := range entries {
+		tracker.bytesTotal += entry.bytes
+		tracker.filesTotal += entry.files
+	}
+	return tracker
+}
+
+func (t *copyTracker) update(worker int, copied, remaining, total int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.workers[worker].bytes = copied
+	if total >= 0 {
+		t.workers[worker].remaining = remaining
+		t.workers[worker].total = total
+	}
+}
+
+func (t *copyTracker) progress() CopyProgress {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	progress := CopyProgress{
+		BytesTotal: t.bytesTotal,
+		FilesTotal: t.filesTotal,
+	}
+	for _, worker := range t.workers {
+		progress.BytesCopied += worker.bytes
+		progress.FilesCopied += worker.total - worker.remaining
+	}
+	// Files rewritten while the sizes were measured may exceed the totals
+	progress.BytesCopied = min(progress.BytesCopied, progress.BytesTotal)
+	progress.FilesCopied = min(progress.FilesCopied, progress.FilesTotal)
+
+	elapsed := time.Since(t.startedAt)
+	if progress.BytesCopied > 0 {
+		rate := float64(progress.BytesCopied) / elapsed.Seconds()
+		progress.Eta = time.Duration(float64(progress.BytesTotal-progress.BytesCopied) / rate * float64(time.Second)).Round(time.Second)
+	}
+
+	return progress
+}
+
+// report reports the progress until the copy completes, and cancels it once it stalls
+func (t *copyTracker) report(ctx context.Context, cancel context.CancelCauseFunc, stallTimeout time.Duration) {
+	ticker := time.NewTicker(copyProgressInterval)
+	defer ticker.Stop()
+
+	report, _ := ctx.Value(copyProgressKey{}).(func(CopyProgress))
+
+	var last CopyProgress
+	lastChange := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		progress := t.progress()
+		if progress.BytesCopied != last.BytesCopied || progress.FilesCopied != last.FilesCopied {
+			last = progress
+			lastChange = time.Now()
+		} else if time.Since(lastChange) > stallTimeout {
+			cancel(fmt.Errorf("%w for %s", errCopyStalled, stallTimeout))
+			return
+		}
+
+		log.Infof("Copied %d/%d bytes, %d/%d files, %s left", progress.BytesCopied, progress.BytesTotal, progress.FilesCopied, progress.FilesTotal, progress.Eta)
+		if report != nil {
+			report(progress)
+		}
+	}
+}
+
+// complete reports the completed copy
+func (t *copyTracker) complete(ctx context.Context) {
+	report, ok := ctx.Value(copyProgressKey{}).(func(CopyProgress))
+	if !ok {
+		return
+	}
+
+	report(CopyProgress{
+		BytesTotal:  t.bytesTotal,
+		BytesCopied: t.bytesTotal,
+		FilesTotal:  t.filesTotal,
+		FilesCopied: t.filesTotal,
+	})
 }
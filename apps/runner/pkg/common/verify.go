@@ -0,0 +1,228 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type VerifyMode string
+
+const (
+	VerifyNone VerifyMode = "none"
+	// Compares the metadata of all files and samples of their data
+	VerifySampled VerifyMode = "sampled"
+	// Compares the metadata and all data of all files
+	VerifyFull VerifyMode = "full"
+)
+
+const (
+	verifySampleSize  = 64 * 1024
+	verifySampleCount = 8
+	// Mismatches listed in the error of a failed verification
+	maxListedMismatches = 10
+)
+
+// CopyMismatchError is returned when the destination of a copy differs from its source
+type CopyMismatchError struct {
+	// Number of files that differ
+	Count int
+	// The first of them, with what differs
+	Mismatches []string
+}
+
+func (e *CopyMismatchError) Error() string {
+	return fmt.Sprintf("copy differs from its source in %d files: %s", e.Count, strings.Join(e.Mismatches, "; "))
+}
+
+// VerifyCopy compares the files of srcPath with those of destPath, reading both as a stream
+// without keeping a manifest in memory. Files only in destPath are ignored. The type, size,
+// permissions, ownership, link target, device and overlay attributes of every file are compared.
+// The data of regular files is compared as a whole in full mode, and in sampled mode as chunks
+// spread over the file.
+func VerifyCopy(ctx context.Context, srcPath, destPath string, mode VerifyMode) error {
+	if mode == "" || mode == VerifyNone {
+		return nil
+	}
+
+	src := filepath.Clean(srcPath)
+	dest := filepath.Clean(destPath)
+	log.Debugf("Verifying copy of %s in %s (%s)", src, dest, mode)
+
+	mismatches := &CopyMismatchError{}
+	files := 0
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		files++
+
+		mismatch, err := compareFiles(path, filepath.Join(dest, rel), mode)
+		if err != nil {
+			return err
+		}
+		if mismatch != "" {
+			mismatches.Count++
+			if len(mismatches.Mismatches) < maxListedMismatches {
+				mismatches.Mismatches = append(mismatches.Mismatches, rel+": "+mismatch)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify copy: %w", err)
+	}
+
+	if mismatches.Count > 0 {
+		return mismatches
+	}
+
+	log.Infof("Verified copy of %d files of %s (%s)", files, src, mode)
+	return nil
+}
+
+// compareFiles returns what differs between a file and its copy, empty if nothing
+func compareFiles(path, target string, mode VerifyMode) (string, error) {
+	var srcStat, destStat unix.Stat_t
+	if err := unix.Lstat(path, &srcStat); err != nil {
+		return "", &fs.PathError{Op: "lstat", Path: path, Err: err}
+	}
+	if err := unix.Lstat(target, &destStat); err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			return "missing", nil
+		}
+		return "", &fs.PathError{Op: "lstat", Path: target, Err: err}
+	}
+
+	fileType := srcStat.Mode & unix.S_IFMT
+	switch {
+	case destStat.Mode&unix.S_IFMT != fileType:
+		return "type differs", nil
+	case fileType != unix.S_IFLNK && destStat.Mode&07777 != srcStat.Mode&07777:
+		return fmt.Sprintf("mode %o instead of %o", destStat.Mode&07777, srcStat.Mode&07777), nil
+	case destStat.Uid != srcStat.Uid || destStat.Gid != srcStat.Gid:
+		return fmt.Sprintf("owner %d:%d instead of %d:%d", destStat.Uid, destStat.Gid, srcStat.Uid, srcStat.Gid), nil
+	}
+
+	switch fileType {
+	case unix.S_IFREG:
+		if destStat.Size != srcStat.Size {
+			return fmt.Sprintf("size %d instead of %d", destStat.Size, srcStat.Size), nil
+		}
+		srcSum, err := checksum(path, srcStat.Size, mode)
+		if err != nil {
+			return "", err
+		}
+		destSum, err := checksum(target, destStat.Size, mode)
+		if err != nil {
+			return "", err
+		}
+		if !bytes.Equal(srcSum, destSum) {
+			return "content differs", nil
+		}
+	case unix.S_IFLNK:
+		srcLink, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		destLink, err := os.Readlink(target)
+		if err != nil {
+			return "", err
+		}
+		if srcLink != destLink {
+			return fmt.Sprintf("link to %s instead of %s", destLink, srcLink), nil
+		}
+	case unix.S_IFCHR, unix.S_IFBLK:
+		if destStat.Rdev != srcStat.Rdev {
+			return "device differs", nil
+		}
+	}
+
+	return compareOverlayXattrs(path, target)
+}
+
+// compareOverlayXattrs returns which overlay attribute of a file its copy lacks or has another
+// value of, empty if none
+func compareOverlayXattrs(path, target string) (string, error) {
+	names, err := listXattrs(path)
+	if err != nil {
+		if errors.Is(err, unix.ENOTSUP) {
+			return "", nil
+		}
+		return "", &fs.PathError{Op: "llistxattr", Path: path, Err: err}
+	}
+
+	for _, name := range names {
+		if !strings.HasPrefix(name, overlayXattrPrefix) {
+			continue
+		}
+
+		srcValue, err := getXattr(path, name)
+		if err != nil {
+			return "", &fs.PathError{Op: "lgetxattr", Path: path, Err: err}
+		}
+		destValue, err := getXattr(target, name)
+		if errors.Is(err, unix.ENODATA) || errors.Is(err, unix.ENOTSUP) {
+			return fmt.Sprintf("attribute %s missing", name), nil
+		}
+		if err != nil {
+			return "", &fs.PathError{Op: "lgetxattr", Path: target, Err: err}
+		}
+		if !bytes.Equal(srcValue, destValue) {
+			return fmt.Sprintf("attribute %s differs", name), nil
+		}
+	}
+
+	return "", nil
+}
+
+// checksum hashes the data of a file, or in sampled mode chunks spread evenly over it
+func checksum(path string, size int64, mode VerifyMode) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+
+	if mode == VerifyFull || size <= verifySampleSize*verifySampleCount {
+		if _, err := io.Copy(hash, file); err != nil {
+			return nil, err
+		}
+		return hash.Sum(nil), nil
+	}
+
+	// The first and the last chunk are always sampled
+	stride := (size - verifySampleSize) / (verifySampleCount - 1)
+	for i := range int64(verifySampleCount) {
+		if _, err := io.Copy(hash, io.NewSectionReader(file, i*stride, verifySampleSize)); err != nil {
+			return nil, err
+		}
+	}
+
+	return hash.Sum(nil), nil
+}
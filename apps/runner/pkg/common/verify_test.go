@@ -0,0 +1,235 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+const opaqueXattr = overlayXattrPrefix + "opaque"
+
+// Larger than the samples of sampled mode, with a byte at offset 100000 that no sample covers
+const largeFileSize = 1024 * 1024
+
+func TestVerifyCopy(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     VerifyMode
+		xattrs   bool
+		modify   func(t *testing.T, dest string)
+		mismatch string
+	}{
+		{name: "identical copy", mode: VerifyFull},
+		{name: "identical copy sampled", mode: VerifySampled},
+		{
+			name: "files only in the copy",
+			mode: VerifyFull,
+			modify: func(t *testing.T, dest string) {
+				writeTestFile(t, filepath.Join(dest, "extra"), "extra", 0644)
+			},
+		},
+		{
+			name: "missing file",
+			mode: VerifyFull,
+			modify: func(t *testing.T, dest string) {
+				removeTestFile(t, filepath.Join(dest, "dir", "file"))
+			},
+			mismatch: "dir/file: missing",
+		},
+		{
+			name: "mode differs",
+			mode: VerifySampled,
+			modify: func(t *testing.T, dest string) {
+				if err := os.Chmod(filepath.Join(dest, "dir", "file"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mismatch: "dir/file: mode 600 instead of 644",
+		},
+		{
+			name: "size differs",
+			mode: VerifySampled,
+			modify: func(t *testing.T, dest string) {
+				writeTestFile(t, filepath.Join(dest, "dir", "file"), "longer content", 0644)
+			},
+			mismatch: "dir/file: size",
+		},
+		{
+			name: "content differs",
+			mode: VerifySampled,
+			modify: func(t *testing.T, dest string) {
+				writeTestFile(t, filepath.Join(dest, "dir", "file"), "CONTENT", 0644)
+			},
+			mismatch: "dir/file: content differs",
+		},
+		{
+			name: "unsampled content differs in full mode",
+			mode: VerifyFull,
+			modify: func(t *testing.T, dest string) {
+				patchTestFile(t, filepath.Join(dest, "large"), 100000)
+			},
+			mismatch: "large: content differs",
+		},
+		{
+			name: "unsampled content differs in sampled mode",
+			mode: VerifySampled,
+			modify: func(t *testing.T, dest string) {
+				patchTestFile(t, filepath.Join(dest, "large"), 100000)
+			},
+		},
+		{
+			name: "link target differs",
+			mode: VerifyFull,
+			modify: func(t *testing.T, dest string) {
+				removeTestFile(t, filepath.Join(dest, "link"))
+				if err := os.Symlink("large", filepath.Join(dest, "link")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mismatch: "link: link to large instead of dir/file",
+		},
+		{
+			name: "type differs",
+			mode: VerifyFull,
+			modify: func(t *testing.T, dest string) {
+				removeTestFile(t, filepath.Join(dest, "link"))
+				writeTestFile(t, filepath.Join(dest, "link"), "dir/file", 0644)
+			},
+			mismatch: "link: type differs",
+		},
+		{
+			name:   "overlay attribute copied",
+			mode:   VerifyFull,
+			xattrs: true,
+		},
+		{
+			name:   "overlay attribute missing",
+			mode:   VerifyFull,
+			xattrs: true,
+			modify: func(t *testing.T, dest string) {
+				if err := unix.Lremovexattr(filepath.Join(dest, "dir"), opaqueXattr); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mismatch: "dir: attribute " + opaqueXattr + " missing",
+		},
+		{
+			name:   "overlay attribute differs",
+			mode:   VerifyFull,
+			xattrs: true,
+			modify: func(t *testing.T, dest string) {
+				if err := unix.Lsetxattr(filepath.Join(dest, "dir"), opaqueXattr, []byte("x"), 0); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mismatch: "dir: attribute " + opaqueXattr + " differs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := filepath.Join(t.TempDir(), "src")
+			dest := filepath.Join(t.TempDir(), "dest")
+			for _, dir := range []string{src, dest} {
+				createTestTree(t, dir, tt.xattrs)
+			}
+			if tt.modify != nil {
+				tt.modify(t, dest)
+			}
+
+			err := VerifyCopy(context.Background(), src, dest, tt.mode)
+			if tt.mismatch == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var mismatchErr *CopyMismatchError
+			if !errors.As(err, &mismatchErr) {
+				t.Fatalf("expected a CopyMismatchError, got %v", err)
+			}
+			if mismatchErr.Count != 1 || !strings.HasPrefix(mismatchErr.Mismatches[0], tt.mismatch) {
+				t.Fatalf("expected a single mismatch %q, got %v", tt.mismatch, mismatchErr)
+			}
+		})
+	}
+}
+
+func TestVerifyCopyNone(t *testing.T) {
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "file"), "content", 0644)
+
+	for _, mode := range []VerifyMode{"", VerifyNone} {
+		if err := VerifyCopy(context.Background(), src, filepath.Join(t.TempDir(), "missing"), mode); err != nil {
+			t.Fatalf("expected no verification in mode %q, got %v", mode, err)
+		}
+	}
+}
+
+// createTestTree creates a directory with a regular file, a large file and a symlink, and marks
+// the subdirectory opaque like overlayfs does if xattrs is set
+func createTestTree(t *testing.T, dir string, xattrs bool) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(dir, "dir", "file"), "content", 0644)
+	writeTestFile(t, filepath.Join(dir, "large"), strings.Repeat("a", largeFileSize), 0644)
+	if err := os.Symlink("dir/file", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	if xattrs {
+		err := unix.Lsetxattr(filepath.Join(dir, "dir"), opaqueXattr, []byte("y"), 0)
+		if errors.Is(err, unix.EPERM) || errors.Is(err, unix.ENOTSUP) {
+			t.Skipf("trusted extended attributes aren't supported: %v", err)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string, perm os.FileMode) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile doesn't change the mode of existing files, and the umask may mask it
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func patchTestFile(t *testing.T, path string, offset int64) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteAt([]byte("b"), offset); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func removeTestFile(t *testing.T, path string) {
+	t.Helper()
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package devcontainer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Paths devcontainer.json is looked up at in a repository, in order
+var DefaultConfigPaths = []string{".devcontainer/devcontainer.json", ".devcontainer.json"}
+
+// Config holds the parts of devcontainer.json that apply to an existing sandbox. Properties that
+// configure the container itself, such as image or mounts, are set by the sandbox create request.
+type Config struct {
+	Features             map[string]json.RawMessage `json:"features"`
+	OnCreateCommand      Command                    `json:"onCreateCommand"`
+	UpdateContentCommand Command                    `json:"updateContentCommand"`
+	PostCreateCommand    Command                    `json:"postCreateCommand"`
+	ForwardPorts         []Port                     `json:"forwardPorts"`
+	// Variables set to null are removed
+	RemoteEnv  map[string]*string `json:"remoteEnv"`
+	RemoteUser string             `json:"remoteUser"`
+}
+
+// Parse parses devcontainer.json, which may contain comments and trailing commas
+func Parse(data []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(standardize(data), &config); err != nil {
+		return nil, fmt.Errorf("invalid devcontainer.json: %w", err)
+	}
+
+	return &config, nil
+}
+
+// Command is a lifecycle command of devcontainer.json: a string run by a shell, an array run
+// without one, or an object of named commands. Named commands are run in the order of their names
+// rather than in parallel.
+type Command [][]string
+
+func (c *Command) UnmarshalJSON(data []byte) error {
+	*c = nil
+
+	if command, ok := parseCommand(data); ok {
+		if command != nil {
+			*c = [][]string{command}
+		}
+		return nil
+	}
+
+	var named map[string]json.RawMessage
+	if err := json.Unmarshal(data, &named); err != nil {
+		return errors.New("command must be a string, an array or an object")
+	}
+
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		command, ok := parseCommand(named[name])
+		if !ok {
+			return fmt.Errorf("command %s must be a string or an array", name)
+		}
+		if command != nil {
+			*c = append(*c, command)
+		}
+	}
+
+	return nil
+}
+
+// parseCommand returns the arguments of a string or array command, which are nil for an empty
+// command, and whether the command is either
+func parseCommand(data []byte) ([]string, bool) {
+	var shell string
+	if err := json.Unmarshal(data, &shell); err == nil {
+		if shell == "" {
+			return nil, true
+		}
+		return []string{"/bin/sh", "-c", shell}, true
+	}
+
+	var args []string
+	if err := json.Unmarshal(data, &args); err == nil {
+		if len(args) == 0 {
+			return nil, true
+		}
+		return args, true
+	}
+
+	return nil, false
+}
+
+// Port is a forwarded port of devcontainer.json, a port number or host:port
+type Port string
+
+func (p *Port) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*p = Port(strconv.Itoa(number))
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errors.New("forwarded port must be a number or a string")
+	}
+	*p = Port(value)
+
+	return nil
+}
+
+// standardize strips the comments and trailing commas JSONC allows
+func standardize(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && (data[i] != '*' || data[i+1] != '/') {
+				i++
+			}
+			i++
+			out = append(out, ' ')
+		case c == ']' || c == '}':
+			// Drop a comma before the closing bracket, skipping whitespace
+			j := len(out) - 1
+			for j >= 0 && isSpace(out[j]) {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package devcontainer
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Media type of the layer of a feature published to an OCI registry
+const featureLayerMediaType = "application/vnd.devcontainers.layer.v1+tar"
+
+// Features larger than this are rejected, they are install scripts and a small amount of assets
+const maxFeatureSize = 64 * 1024 * 1024
+
+var nonWordRegex = regexp.MustCompile(`[^\w]`)
+
+// IsLocalFeature returns whether a feature is a directory next to devcontainer.json rather
+// than published to a registry
+func IsLocalFeature(id string) bool {
+	return strings.HasPrefix(id, "./") || strings.HasPrefix(id, "../")
+}
+
+type featureMetadata struct {
+	Options map[string]struct {
+		Default any `json:"default"`
+	} `json:"options"`
+}
+
+// FeatureEnv returns the env the install script of a feature is run with: its options, from the
+// user options or their defaults, named as in the feature spec. archive is the tar of the feature
+// directory, which may be nested in a directory.
+func FeatureEnv(archive []byte, userOptions json.RawMessage) ([]string, error) {
+	var metadata featureMetadata
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.Trim(path.Clean("/"+header.Name), "/")
+		if name != "devcontainer-feature.json" && !(strings.Count(name, "/") == 1 && path.Base(name) == "devcontainer-feature.json") {
+			continue
+		}
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(standardize(data), &metadata); err != nil {
+			return nil, fmt.Errorf("invalid devcontainer-feature.json: %w", err)
+		}
+		break
+	}
+
+	options := map[string]any{}
+	for name, option := range metadata.Options {
+		if option.Default != nil {
+			options[name] = option.Default
+		}
+	}
+
+	// A string is a shorthand for the version option
+	var version string
+	if err := json.Unmarshal(userOptions, &version); err == nil {
+		options["version"] = version
+	} else if len(userOptions) > 0 {
+		var values map[string]any
+		if err := json.Unmarshal(userOptions, &values); err != nil {
+			return nil, errors.New("feature options must be a string or an object")
+		}
+		for name, value := range values {
+			options[name] = value
+		}
+	}
+
+	env := make([]string, 0, len(options))
+	for name, value := range options {
+		env = append(env, fmt.Sprintf("%s=%v", strings.ToUpper(nonWordRegex.ReplaceAllString(name, "_")), value))
+	}
+	sort.Strings(env)
+
+	return env, nil
+}
+
+// FetchFeature downloads a feature published to an OCI registry, e.g.
+// ghcr.io/devcontainers/features/node:1, and returns the tar of its directory. Only public
+// features are supported.
+func FetchFeature(ctx context.Context, client *http.Client, id string) ([]byte, error) {
+	registry, repository, reference, err := parseFeatureId(id)
+	if err != nil {
+		return nil, err
+	}
+
+	registryClient := &ociClient{httpClient: client, registry: registry, repository: repository}
+
+	var manifest struct {
+		Layers []struct {
+			MediaType string `json:"mediaType"`
+			Digest    string `json:"digest"`
+			Size      int64  `json:"size"`
+		} `json:"layers"`
+	}
+
+	data, err := registryClient.get(ctx, "manifests/"+reference, "application/vnd.oci.image.manifest.v1+json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manifest of feature %s: %w", id, err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest of feature %s: %w", id, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != featureLayerMediaType {
+			continue
+		}
+		if layer.Size > maxFeatureSize {
+			return nil, fmt.Errorf("feature %s is larger than %d bytes", id, maxFeatureSize)
+		}
+
+		archive, err := registryClient.get(ctx, "blobs/"+layer.Digest, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to download feature %s: %w", id, err)
+		}
+		return archive, nil
+	}
+
+	return nil, fmt.Errorf("%s is not a devcontainer feature", id)
+}
+
+// parseFeatureId splits a feature ID into its registry, repository and tag or digest
+func parseFeatureId(id string) (string, string, string, error) {
+	registry, name, ok := strings.Cut(id, "/")
+	if !ok || !strings.ContainsAny(registry, ".:") {
+		return "", "", "", fmt.Errorf("unsupported feature %s, features must be published to an OCI registry or local", id)
+	}
+
+	if repository, digest, ok := strings.Cut(name, "@"); ok {
+		return registry, repository, digest, nil
+	}
+
+	repository, tag := name, "latest"
+	if i := strings.LastIndex(name, ":"); i != -1 {
+		repository, tag = name[:i], name[i+1:]
+	}
+
+	return registry, repository, tag, nil
+}
+
+// ociClient reads from a registry anonymously, getting a bearer token if the registry asks for one
+type ociClient struct {
+	httpClient *http.Client
+	registry   string
+	repository string
+	token      string
+}
+
+func (c *ociClient) get(ctx context.Context, resource, accept string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/v2/%s/%s", c.registry, c.repository, resource), nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			if err := c.authenticate(ctx, challenge); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("registry responded with %s", resp.Status)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeatureSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxFeatureSize {
+			return nil, fmt.Errorf("response is larger than %d bytes", maxFeatureSize)
+		}
+
+		return data, nil
+	}
+}
+
+// authenticate gets an anonymous token for the realm of a Bearer challenge
+func (c *ociClient) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("unsupported registry authentication %q", scheme)
+	}
+
+	values := map[string]string{}
+	for _, param := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok {
+			values[key] = strings.Trim(value, `"`)
+		}
+	}
+
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Scheme != "https" {
+		return fmt.Errorf("invalid registry token realm %q", values["realm"])
+	}
+
+	query := realm.Query()
+	if service := values["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", c.repository))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token endpoint responded with %s", resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return err
+	}
+
+	c.token = token.Token
+	if c.token == "" {
+		c.token = token.AccessToken
+	}
+
+	return nil
+}
@@ -0,0 +1,360 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package dnsforwarder implements a DNS forwarder sandboxes use as their resolver. Sandboxes
+// labeled with allowed domains can only resolve those domains and their subdomains, and the
+// addresses they resolve to are allowed by the network rules of the sandbox.
+package dnsforwarder
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/netrules"
+	"github.com/docker/docker/client"
+	cmap "github.com/orcaman/concurrent-map/v2"
+	"golang.org/x/net/dns/dnsmessage"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	exchangeTimeout = 5 * time.Second
+	// How long the domain policy of a sandbox address is cached for
+	policyTTL = 30 * time.Second
+)
+
+type Config struct {
+	// Address on the sandbox network the forwarder listens on, e.g. 172.17.0.1:53
+	ListenAddress string
+	// Resolver queries are forwarded to, the first nameserver of /etc/resolv.conf if empty
+	Upstream        string
+	ApiClient       client.APIClient
+	NetRulesManager *netrules.NetRulesManager
+	// Called with the address of the sandbox and the name of each query it sends, if set
+	QueryObserver func(sourceIp string, name string)
+}
+
+type domainPolicy struct {
+	// Network rules of the sandbox are named after its short container ID
+	containerShortId string
+	domains          []string
+}
+
+type cachedPolicy struct {
+	policy    *domainPolicy
+	expiresAt time.Time
+}
+
+type Forwarder struct {
+	config   Config
+	policies cmap.ConcurrentMap[string, cachedPolicy]
+}
+
+func NewForwarder(config Config) (*Forwarder, error) {
+	if config.Upstream == "" {
+		upstream, err := getSystemResolver()
+		if err != nil {
+			return nil, err
+		}
+		config.Upstream = upstream
+	}
+
+	return &Forwarder{
+		config:   config,
+		policies: cmap.New[cachedPolicy](),
+	}, nil
+}
+
+// Start serves DNS over UDP and TCP until the context is canceled
+func (f *Forwarder) Start(ctx context.Context) error {
+	packetConn, err := net.ListenPacket("udp", f.config.ListenAddress)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", f.config.ListenAddress)
+	if err != nil {
+		packetConn.Close()
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		packetConn.Close()
+		listener.Close()
+	}()
+
+	log.Infof("DNS forwarder listening on %s, forwarding to %s", f.config.ListenAddress, f.config.Upstream)
+
+	go f.serveTCP(ctx, listener)
+	f.serveUDP(ctx, packetConn)
+
+	return nil
+}
+
+func (f *Forwarder) serveUDP(ctx context.Context, conn net.PacketConn) {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		query := slices.Clone(buf[:n])
+		go func() {
+			response := f.resolve(ctx, "udp", addr, query)
+			if response != nil {
+				_, _ = conn.WriteTo(response, addr)
+			}
+		}()
+	}
+}
+
+func (f *Forwarder) serveTCP(ctx context.Context, listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		go func() {
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+
+			for {
+				_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+				query, err := readTCPMessage(reader)
+				if err != nil {
+					return
+				}
+
+				response := f.resolve(ctx, "tcp", conn.RemoteAddr(), query)
+				if response == nil || writeTCPMessage(conn, response) != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// resolve answers a query from a sandbox, or returns nil if the query can't be parsed
+func (f *Forwarder) resolve(ctx context.Context, network string, source net.Addr, query []byte) []byte {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil
+	}
+
+	question, err := parser.Question()
+	if err != nil {
+		return nil
+	}
+
+	if f.config.QueryObserver != nil {
+		if host, _, err := net.SplitHostPort(source.String()); err == nil {
+			f.config.QueryObserver(host, question.Name.String())
+		}
+	}
+
+	policy := f.getPolicy(ctx, source)
+	if policy != nil && !common.IsDomainAllowed(question.Name.String(), policy.domains) {
+		log.Debugf("Refused DNS query for %s from sandbox %s", question.Name.String(), policy.containerShortId)
+		return errorResponse(header, question, dnsmessage.RCodeRefused)
+	}
+
+	response, err := f.exchange(ctx, network, query)
+	if err != nil {
+		log.Debugf("Failed to forward DNS query for %s: %v", question.Name.String(), err)
+		return errorResponse(header, question, dnsmessage.RCodeServerFailure)
+	}
+
+	if policy != nil {
+		f.allowAnswers(policy, response)
+	}
+
+	return response
+}
+
+func (f *Forwarder) exchange(ctx context.Context, network string, query []byte) ([]byte, error) {
+	dialer := net.Dialer{Timeout: exchangeTimeout}
+	conn, err := dialer.DialContext(ctx, network, f.config.Upstream)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(exchangeTimeout))
+
+	if network == "tcp" {
+		if err := writeTCPMessage(conn, query); err != nil {
+			return nil, err
+		}
+		return readTCPMessage(bufio.NewReader(conn))
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 65535)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf[:n], nil
+}
+
+// allowAnswers adds the addresses a sandbox resolved to its network rules, so that its allowed
+// domains stay reachable when the rest of its egress is blocked
+func (f *Forwarder) allowAnswers(policy *domainPolicy, response []byte) {
+	var parser dnsmessage.Parser
+	if _, err := parser.Start(response); err != nil {
+		return
+	}
+	if err := parser.SkipAllQuestions(); err != nil {
+		return
+	}
+
+	var addresses []net.IP
+	for {
+		header, err := parser.AnswerHeader()
+		if err != nil {
+			break
+		}
+
+		if header.Type != dnsmessage.TypeA {
+			if err := parser.SkipAnswer(); err != nil {
+				break
+			}
+			continue
+		}
+
+		answer, err := parser.AResource()
+		if err != nil {
+			break
+		}
+		addresses = append(addresses, net.IP(answer.A[:]))
+	}
+
+	if len(addresses) == 0 || f.config.NetRulesManager == nil {
+		return
+	}
+
+	if err := f.config.NetRulesManager.AllowNetworkAddresses(policy.containerShortId, addresses); err != nil {
+		log.Errorf("Failed to allow resolved addresses for sandbox %s: %v", policy.containerShortId, err)
+	}
+}
+
+// getPolicy returns the domain policy of the sandbox with the source address, or nil if the
+// sandbox may resolve any domain
+func (f *Forwarder) getPolicy(ctx context.Context, source net.Addr) *domainPolicy {
+	host, _, err := net.SplitHostPort(source.String())
+	if err != nil {
+		return nil
+	}
+
+	if cached, ok := f.policies.Get(host); ok && time.Now().Before(cached.expiresAt) {
+		return cached.policy
+	}
+
+	c, err := common.FindContainerByIpAddress(ctx, f.config.ApiClient, host, common.DNS_ALLOWED_DOMAINS_LABEL)
+	if err != nil {
+		// Fail closed, a sandbox with a policy must not resolve other domains while Docker is unavailable
+		log.Warnf("Failed to look up the DNS policy of %s: %v", host, err)
+		return &domainPolicy{}
+	}
+
+	var policy *domainPolicy
+	if c != nil {
+		policy = &domainPolicy{
+			containerShortId: c.ID[:12],
+			domains:          common.ParseDomainList(c.Labels[common.DNS_ALLOWED_DOMAINS_LABEL]),
+		}
+	}
+
+	f.policies.Set(host, cachedPolicy{policy: policy, expiresAt: time.Now().Add(policyTTL)})
+	return policy
+}
+
+func errorResponse(header dnsmessage.Header, question dnsmessage.Question, rcode dnsmessage.RCode) []byte {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+		ID:                 header.ID,
+		Response:           true,
+		OpCode:             header.OpCode,
+		RecursionDesired:   header.RecursionDesired,
+		RecursionAvailable: true,
+		RCode:              rcode,
+	})
+
+	if err := builder.StartQuestions(); err != nil {
+		return nil
+	}
+	if err := builder.Question(question); err != nil {
+		return nil
+	}
+
+	response, err := builder.Finish()
+	if err != nil {
+		return nil
+	}
+
+	return response
+}
+
+func readTCPMessage(reader io.Reader) ([]byte, error) {
+	var length uint16
+	if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+
+	message := make([]byte, length)
+	if _, err := io.ReadFull(reader, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func writeTCPMessage(w io.Writer, message []byte) error {
+	buf := make([]byte, 2+len(message))
+	binary.BigEndian.PutUint16(buf, uint16(len(message)))
+	copy(buf[2:], message)
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// getSystemResolver returns the first nameserver of the runner host
+func getSystemResolver() (string, error) {
+	content, err := os.ReadFile("/etc/resolv.conf")
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return net.JoinHostPort(fields[1], "53"), nil
+		}
+	}
+
+	return "", errors.New("no nameserver found in /etc/resolv.conf")
+}
@@ -24,6 +24,10 @@ type backupContext struct {
 var backup_context_map = cmap.New[backupContext]()
 
 func (d *DockerClient) CreateBackup(ctx context.Context, containerId string, backupDto dto.CreateBackupDTO) error {
+	if d.isMicroVM(containerId) {
+		return errMicroVMUnsupported("backup")
+	}
+
 	// Cancel a backup if it's already in progress
 	backup_context, ok := backup_context_map.Get(containerId)
 	if ok {
@@ -32,10 +36,17 @@ func (d *DockerClient) CreateBackup(ctx context.Context, containerId string, bac
 
 	log.Infof("Creating backup for container %s...", containerId)
 
-	return d.createBackup(containerId, backupDto)
+	return d.createBackup(ctx, containerId, backupDto)
 }
 
-func (d *DockerClient) CreateBackupAsync(ctx context.Context, containerId string, backupDto dto.CreateBackupDTO) error {
+// CreateBackupAsync runs the backup in the background with ctx, which outlives the request, and
+// calls release once it completes
+func (d *DockerClient) CreateBackupAsync(ctx context.Context, containerId string, backupDto dto.CreateBackupDTO, release func()) error {
+	if d.isMicroVM(containerId) {
+		release()
+		return errMicroVMUnsupported("backup")
+	}
+
 	// Cancel a backup if it's already in progress
 	backup_context, ok := backup_context_map.Get(containerId)
 	if ok {
@@ -45,7 +56,9 @@ func (d *DockerClient) CreateBackupAsync(ctx context.Context, containerId string
 	log.Infof("Creating backup for container %s...", containerId)
 
 	go func() {
-		err := d.createBackup(containerId, backupDto)
+		defer release()
+
+		err := d.createBackup(ctx, containerId, backupDto)
 		if err != nil {
 			log.Errorf("Error creating backup for container %s: %v", containerId, err)
 		}
@@ -54,8 +67,8 @@ func (d *DockerClient) CreateBackupAsync(ctx context.Context, containerId string
 	return nil
 }
 
-func (d *DockerClient) createBackup(containerId string, backupDto dto.CreateBackupDTO) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(d.backupTimeoutMin)*time.Minute)
+func (d *DockerClient) createBackup(ctx context.Context, containerId string, backupDto dto.CreateBackupDTO) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(d.backupTimeoutMin)*time.Minute)
 
 	defer func() {
 		backupContext, ok := backup_context_map.Get(containerId)
@@ -69,48 +82,59 @@ func (d *DockerClient) createBackup(containerId string, backupDto dto.CreateBack
 
 	d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateInProgress, nil)
 
-	err := d.commitContainer(ctx, containerId, backupDto.Snapshot)
+	targets, err := d.getBackupTargets(ctx, containerId, backupDto)
 	if err != nil {
-		if errors.Is(err, context.Canceled) {
-			d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateNone, nil)
-			log.Infof("Backup for container %s canceled", containerId)
-			return err
-		}
-		if errors.Is(err, context.DeadlineExceeded) {
-			d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateFailed, err)
-			log.Errorf("Backup for container %s timed out during commit", containerId)
-			return err
-		}
-		log.Errorf("Error committing container %s: %v", containerId, err)
 		d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateFailed, err)
 		return err
 	}
 
-	err = d.PushImage(ctx, backupDto.Snapshot, &backupDto.Registry)
-	if err != nil {
-		if errors.Is(err, context.Canceled) {
-			d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateNone, nil)
-			log.Infof("Backup for container %s canceled", containerId)
+	// Sidecars are backed up in the same job, so the backup is complete only once all are pushed
+	for _, target := range targets {
+		err = d.commitContainer(ctx, target.containerId, target.snapshot, target.options)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateNone, nil)
+				log.Infof("Backup for container %s canceled", containerId)
+				return err
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateFailed, err)
+				log.Errorf("Backup for container %s timed out during commit", containerId)
+				return err
+			}
+			log.Errorf("Error committing container %s: %v", target.containerId, err)
+			d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateFailed, err)
 			return err
 		}
-		if errors.Is(err, context.DeadlineExceeded) {
+
+		err = d.PushImage(ctx, target.snapshot, &backupDto.Registry)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateNone, nil)
+				log.Infof("Backup for container %s canceled", containerId)
+				return err
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateFailed, err)
+				log.Errorf("Backup for container %s timed out during push", containerId)
+				return err
+			}
+			log.Errorf("Error pushing image %s: %v", target.snapshot, err)
 			d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateFailed, err)
-			log.Errorf("Backup for container %s timed out during push", containerId)
 			return err
 		}
-		log.Errorf("Error pushing image %s: %v", backupDto.Snapshot, err)
-		d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateFailed, err)
-		return err
 	}
 
 	d.statesCache.SetBackupState(ctx, containerId, enums.BackupStateCompleted, nil)
 
 	log.Infof("Backup (%s) for container %s created successfully", backupDto.Snapshot, containerId)
 
-	err = d.RemoveImage(ctx, backupDto.Snapshot, true)
-	if err != nil {
-		log.Errorf("Error removing image %s: %v", backupDto.Snapshot, err)
-		// Don't set backup to failed because the image is already pushed
+	for _, target := range targets {
+		err = d.RemoveImage(ctx, target.snapshot, true)
+		if err != nil {
+			log.Errorf("Error removing image %s: %v", target.snapshot, err)
+			// Don't set backup to failed because the image is already pushed
+		}
 	}
 
 	return nil
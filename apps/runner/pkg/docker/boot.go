@@ -0,0 +1,81 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"fmt"
+	"regexp"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+var bootVariableRegex = regexp.MustCompile(`\{\{\s*([A-Za-z]+)\s*\}\}`)
+
+// bootVariables returns the variables the boot overrides of a sandbox may contain
+func (d *DockerClient) bootVariables(sandboxDto dto.CreateSandboxDTO) map[string]string {
+	return map[string]string{
+		"sandboxId": sandboxDto.Id,
+		"domain":    d.domain,
+		"user":      sandboxDto.OsUser,
+	}
+}
+
+// resolveBootTemplate replaces the {{variable}} references of a boot override, rejecting unknown
+// variables
+func resolveBootTemplate(value string, variables map[string]string) (string, error) {
+	var err error
+	resolved := bootVariableRegex.ReplaceAllStringFunc(value, func(match string) string {
+		name := bootVariableRegex.FindStringSubmatch(match)[1]
+		variable, ok := variables[name]
+		if !ok {
+			err = common_errors.NewBadRequestError(fmt.Errorf("unknown boot variable %s", name))
+			return match
+		}
+		return variable
+	})
+
+	return resolved, err
+}
+
+func resolveBootTemplates(values []string, variables map[string]string) ([]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	resolved := make([]string, 0, len(values))
+	for _, value := range values {
+		r, err := resolveBootTemplate(value, variables)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, r)
+	}
+
+	return resolved, nil
+}
+
+// resolveBoot returns the boot overrides of a sandbox with their variables resolved, nil if it
+// has none
+func (d *DockerClient) resolveBoot(sandboxDto dto.CreateSandboxDTO) (*dto.SandboxBootDTO, error) {
+	if sandboxDto.Boot == nil {
+		return nil, nil
+	}
+
+	variables := d.bootVariables(sandboxDto)
+	boot := *sandboxDto.Boot
+
+	var err error
+	if boot.Entrypoint, err = resolveBootTemplates(boot.Entrypoint, variables); err != nil {
+		return nil, err
+	}
+	if boot.Args, err = resolveBootTemplates(boot.Args, variables); err != nil {
+		return nil, err
+	}
+	if boot.WorkingDir, err = resolveBootTemplate(boot.WorkingDir, variables); err != nil {
+		return nil, err
+	}
+
+	return &boot, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/mount"
+
+	log "github.com/sirupsen/logrus"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+var errBucketMountsDisabled = common_errors.NewBadRequestError(errors.New("bucket mounts are not enabled on the runner"))
+
+// prepareBucketMounts saves the bucket mounts of a new sandbox and returns the mounts of its
+// container. The buckets are mounted when the sandbox starts.
+func (d *DockerClient) prepareBucketMounts(sandboxDto dto.CreateSandboxDTO) ([]mount.Mount, error) {
+	if len(sandboxDto.BucketMounts) == 0 {
+		return nil, nil
+	}
+	if d.bucketMounts == nil {
+		return nil, errBucketMountsDisabled
+	}
+
+	return d.bucketMounts.Prepare(sandboxDto.Id, sandboxDto.BucketMounts)
+}
+
+// cloneBucketMounts replaces the bucket mounts of the source sandbox in the mounts of its clone
+// with mounts of the same buckets for the clone
+func (d *DockerClient) cloneBucketMounts(sourceId, cloneId string, mounts []mount.Mount) ([]mount.Mount, error) {
+	if d.bucketMounts == nil {
+		return slices.Clone(mounts), nil
+	}
+
+	cloneMounts := make([]mount.Mount, 0, len(mounts))
+	for _, m := range mounts {
+		if m.Type == mount.TypeBind && d.bucketMounts.IsMountPath(sourceId, m.Source) {
+			continue
+		}
+		cloneMounts = append(cloneMounts, m)
+	}
+
+	bucketMounts, err := d.bucketMounts.Clone(sourceId, cloneId)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(cloneMounts, bucketMounts...), nil
+}
+
+// mountBuckets mounts the buckets of a sandbox before it starts, if it has any
+func (d *DockerClient) mountBuckets(ctx context.Context, sandboxId string) error {
+	if d.bucketMounts == nil {
+		return nil
+	}
+
+	return d.bucketMounts.Mount(ctx, sandboxId)
+}
+
+// removeBucketMounts unmounts the buckets of a destroyed sandbox and removes their credentials
+func (d *DockerClient) removeBucketMounts(ctx context.Context, sandboxId string) {
+	if d.bucketMounts == nil {
+		return
+	}
+
+	if err := d.bucketMounts.Remove(ctx, sandboxId); err != nil {
+		log.Errorf("Failed to remove the bucket mounts of sandbox %s: %v", sandboxId, err)
+	}
+}
+
+// UnmountStoppedSandboxBuckets unmounts the buckets of a sandbox whose container died, unless the
+// sandbox was started again since
+func (d *DockerClient) UnmountStoppedSandboxBuckets(ctx context.Context, event events.Message) {
+	if d.bucketMounts == nil {
+		return
+	}
+
+	sandboxId := event.Actor.Attributes["name"]
+	if sandboxId == "" {
+		return
+	}
+
+	d.bucketMounts.Unmount(ctx, sandboxId, time.Unix(0, event.TimeNano))
+}
+
+// GetBucketMounts returns the bucket mounts of a sandbox and their health
+func (d *DockerClient) GetBucketMounts(sandboxId string) ([]dto.BucketMountStatusDTO, error) {
+	if d.bucketMounts == nil {
+		return nil, errBucketMountsDisabled
+	}
+
+	return d.bucketMounts.Status(sandboxId)
+}
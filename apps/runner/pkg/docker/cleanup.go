@@ -0,0 +1,59 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// How long the cleanup of a failed operation may take, also when the operation was canceled
+const cleanupTimeout = 2 * time.Minute
+
+// cleanupContext returns the context to clean up after an operation with. It isn't canceled with
+// the operation, so that a caller giving up doesn't leave renamed or partially created containers
+// behind.
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+}
+
+// releaseIfCanceled destroys a sandbox whose creation failed because it was canceled after its
+// container was created, so that a retry starts from scratch rather than from a half provisioned
+// container. Sandboxes that failed otherwise are kept in the error state for recovery.
+func (d *DockerClient) releaseIfCanceled(ctx context.Context, sandboxId string) {
+	if ctx.Err() == nil {
+		return
+	}
+
+	cleanupCtx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	log.Warnf("Creation of sandbox %s was canceled, removing its container: %v", sandboxId, context.Cause(ctx))
+
+	if err := d.Destroy(cleanupCtx, sandboxId); err != nil {
+		log.Errorf("Failed to remove the partially created sandbox %s: %v", sandboxId, err)
+	}
+}
+
+// restoreRenamedContainer gives a container renamed to be recreated its name back, removing the
+// container that may have been created in its place
+func (d *DockerClient) restoreRenamedContainer(ctx context.Context, oldName, sandboxId string) {
+	cleanupCtx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	err := d.apiClient.ContainerRemove(cleanupCtx, sandboxId, container.RemoveOptions{Force: true})
+	if err != nil && !errdefs.IsNotFound(err) {
+		log.Errorf("Failed to remove the recreated container of sandbox %s: %v", sandboxId, err)
+		return
+	}
+
+	if err := d.apiClient.ContainerRename(cleanupCtx, oldName, sandboxId); err != nil {
+		log.Errorf("Failed to rename container %s back to %s: %v", oldName, sandboxId, err)
+	}
+}
@@ -8,8 +8,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/daytonaio/runner/pkg/bucketmount"
 	"github.com/daytonaio/runner/pkg/cache"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/envelope"
+	"github.com/daytonaio/runner/pkg/firecracker"
 	"github.com/daytonaio/runner/pkg/netrules"
+	"github.com/daytonaio/runner/pkg/topology"
 	"github.com/docker/docker/client"
 	log "github.com/sirupsen/logrus"
 )
@@ -29,9 +34,53 @@ type DockerClientConfig struct {
 	DaemonStartTimeoutSec    int
 	SandboxStartTimeoutSec   int
 	UseSnapshotEntrypoint    bool
+	DaemonAutoUpgrade        bool
+	DaemonSocketsDir         string
 	VolumeCleanupIntervalSec int
 	VolumeCleanupDryRun      bool
+	// How long after being mounted or reserved for a sandbox volume mounts are kept if unused
+	VolumeCleanupGracePeriod time.Duration
 	BackupTimeoutMin         int
+	Compression              CompressionConfig
+	// Copies of the data of sandboxes whose container is recreated
+	Copy common.CopyConfig
+	// Remove unused images when the host lacks the space to copy the data of a sandbox
+	StorageRecoveryImageGC bool
+	LazyPullEnabled        bool
+	// Resolver of sandboxes that don't set their own DNS servers, the Docker default if empty
+	DnsForwarderAddress string
+	// Port of the runner egress proxy, 0 if it is disabled
+	EgressProxyPort int
+	// AES-256 key WireGuard tunnel configs are encrypted with, tunnels are disabled if empty
+	WireGuardKey []byte
+	// Secondary host IPs the sandboxes of an organization egress from, by organization ID
+	OrganizationEgressIps map[string]string
+	// Decrypter of the encrypted env of sandboxes, encrypted env is rejected if nil
+	EnvDecrypter *envelope.Decrypter
+	// Allocator of the CPUs of pinned sandboxes, CPU pinning is disabled if nil
+	CpuAllocator *topology.Allocator
+	// Let sandboxes request /dev/kvm and /dev/net/tun
+	KvmEnabled bool
+	TunEnabled bool
+	// Runs sandboxes of the microVM class, they are rejected if nil
+	MicroVMs *firecracker.Client
+	// Image of the rootless Docker daemon sidecar, e.g. docker:dind-rootless. The dind mode is
+	// disabled if empty.
+	DindImage string
+	// Name of the Sysbox runtime in the Docker daemon config, the sysbox mode is disabled if empty
+	SysboxRuntime string
+	// Domain of the runner, resolved in the boot overrides of sandboxes
+	Domain string
+	// Core dumps of crashed sandbox processes, disabled if nil
+	CoreDumps *CoreDumpsConfig
+	// How long inspect responses of sandbox containers are cached, caching is disabled if 0
+	InspectCacheTTL time.Duration
+	// Limit the data volumes of sandboxes to their storage quota with XFS project quotas
+	VolumeQuotasEnabled bool
+	// Mounts the buckets of sandboxes, bucket mounts are rejected if nil
+	BucketMounts *bucketmount.Service
+	// URL of the runner package cache sandboxes are configured to use, disabled if empty
+	PackageCacheUrl string
 }
 
 func NewDockerClient(config DockerClientConfig) *DockerClient {
@@ -50,7 +99,15 @@ func NewDockerClient(config DockerClientConfig) *DockerClient {
 		config.BackupTimeoutMin = 60
 	}
 
-	return &DockerClient{
+	if config.Compression.Algorithm == "" {
+		config.Compression.Algorithm = CompressionZstd
+	}
+
+	if config.Compression.Workers <= 0 {
+		config.Compression.Workers = 1
+	}
+
+	d := &DockerClient{
 		apiClient:                config.ApiClient,
 		statesCache:              config.StatesCache,
 		logWriter:                config.LogWriter,
@@ -66,10 +123,54 @@ func NewDockerClient(config DockerClientConfig) *DockerClient {
 		daemonStartTimeoutSec:    config.DaemonStartTimeoutSec,
 		sandboxStartTimeoutSec:   config.SandboxStartTimeoutSec,
 		useSnapshotEntrypoint:    config.UseSnapshotEntrypoint,
+		daemonAutoUpgrade:        config.DaemonAutoUpgrade,
+		daemonSocketsDir:         config.DaemonSocketsDir,
 		volumeCleanupIntervalSec: config.VolumeCleanupIntervalSec,
 		volumeCleanupDryRun:      config.VolumeCleanupDryRun,
+		volumeCleanupGracePeriod: config.VolumeCleanupGracePeriod,
+		pendingVolumeMounts:      make(map[string]int),
+		volumeMountsReleasedAt:   make(map[string]time.Time),
 		backupTimeoutMin:         config.BackupTimeoutMin,
+		compression:              config.Compression,
+		copyConfig:               config.Copy,
+		storageRecoveryImageGC:   config.StorageRecoveryImageGC,
+		compressionWorkers:       make(chan struct{}, config.Compression.Workers),
+		lazyPullEnabled:          config.LazyPullEnabled,
+		dnsForwarderAddress:      config.DnsForwarderAddress,
+		egressProxyPort:          config.EgressProxyPort,
+		wireGuardKey:             config.WireGuardKey,
+		organizationEgressIps:    config.OrganizationEgressIps,
+		envDecrypter:             config.EnvDecrypter,
+		cpuAllocator:             config.CpuAllocator,
+		kvmEnabled:               config.KvmEnabled,
+		tunEnabled:               config.TunEnabled,
+		microVMs:                 config.MicroVMs,
+		dindImage:                config.DindImage,
+		sysboxRuntime:            config.SysboxRuntime,
+		domain:                   config.Domain,
+		coreDumps:                config.CoreDumps,
+		volumeQuotasEnabled:      config.VolumeQuotasEnabled,
+		bucketMounts:             config.BucketMounts,
+		packageCacheUrl:          config.PackageCacheUrl,
 	}
+
+	d.daemonTransport = newDaemonRoundTripper(d.dialDaemon)
+
+	d.inspectCache = newInspectCache(config.InspectCacheTTL)
+	if d.inspectCache.enabled() {
+		d.apiClient = &invalidatingAPIClient{APIClient: config.ApiClient, cache: d.inspectCache}
+	}
+
+	return d
+}
+
+// UpdateAWSCredentials replaces the credentials volumes are mounted with, e.g. after they are rotated
+func (d *DockerClient) UpdateAWSCredentials(accessKeyId, secretAccessKey string) {
+	d.awsCredentialsMutex.Lock()
+	defer d.awsCredentialsMutex.Unlock()
+
+	d.awsAccessKeyId = accessKeyId
+	d.awsSecretAccessKey = secretAccessKey
 }
 
 func (d *DockerClient) ApiClient() client.APIClient {
@@ -84,6 +185,7 @@ type DockerClient struct {
 	awsEndpointUrl           string
 	awsAccessKeyId           string
 	awsSecretAccessKey       string
+	awsCredentialsMutex      sync.RWMutex
 	volumeMutexes            map[string]*sync.Mutex
 	volumeMutexesMutex       sync.Mutex
 	daemonPath               string
@@ -93,9 +195,44 @@ type DockerClient struct {
 	daemonStartTimeoutSec    int
 	sandboxStartTimeoutSec   int
 	useSnapshotEntrypoint    bool
+	daemonAutoUpgrade        bool
+	daemonSocketsDir         string
+	daemonTransport          *daemonRoundTripper
 	volumeCleanupIntervalSec int
 	volumeCleanupDryRun      bool
 	backupTimeoutMin         int
 	volumeCleanupMutex       sync.Mutex
 	lastVolumeCleanup        time.Time
+	volumeCleanupGracePeriod time.Duration
+	// Volume mounts reserved for sandboxes being created, by path
+	pendingVolumeMounts      map[string]int
+	volumeMountsReleasedAt   map[string]time.Time
+	pendingVolumeMountsMutex sync.Mutex
+	compression              CompressionConfig
+	compressionWorkers       chan struct{}
+	copyConfig               common.CopyConfig
+	storageRecoveryImageGC   bool
+	lazyPullEnabled          bool
+	lazyPullMutex            sync.Mutex
+	lazyPullChecked          bool
+	lazyPullSnapshotter      string
+	dnsForwarderAddress      string
+	egressProxyPort          int
+	wireGuardKey             []byte
+	organizationEgressIps    map[string]string
+	envDecrypter             *envelope.Decrypter
+	cpuAllocator             *topology.Allocator
+	kvmEnabled               bool
+	tunEnabled               bool
+	microVMs                 *firecracker.Client
+	dindImage                string
+	sysboxRuntime            string
+	domain                   string
+	coreDumps                *CoreDumpsConfig
+	inspectCache             *inspectCache
+	volumeQuotasEnabled      bool
+	bucketMounts             *bucketmount.Service
+	packageCacheUrl          string
+	// IDs of the sandboxes whose provisioning completed
+	provisionedSandboxes sync.Map
 }
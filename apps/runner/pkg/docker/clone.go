@@ -0,0 +1,238 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/common-go/pkg/timer"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/models/enums"
+	"github.com/docker/docker/api/types/container"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Labels of the source sandbox the clone doesn't take over
+var cloneResetLabels = []string{
+	common.WIREGUARD_CONFIG_LABEL,
+	common.EXPIRES_AT_LABEL,
+	common.EXPIRY_POLICY_LABEL,
+}
+
+// GetCloneCreateDto returns the create request a clone amounts to, with the resources and the
+// organization of the source sandbox it keeps, so the clone can be admitted like a create
+func (d *DockerClient) GetCloneCreateDto(ctx context.Context, sourceId string, cloneDto dto.CloneSandboxDTO) (dto.CreateSandboxDTO, error) {
+	source, err := d.ContainerInspect(ctx, sourceId)
+	if err != nil {
+		return dto.CreateSandboxDTO{}, err
+	}
+	if source.Config == nil || source.HostConfig == nil {
+		return dto.CreateSandboxDTO{}, errors.New("source sandbox has no configuration")
+	}
+
+	createDto := dto.CreateSandboxDTO{
+		Id:           cloneDto.Id,
+		CpuQuota:     cloneDto.Cpu,
+		MemoryQuota:  cloneDto.Memory,
+		StorageQuota: cloneDto.Disk,
+		Metadata:     maps.Clone(cloneDto.Metadata),
+	}
+	if createDto.CpuQuota == 0 {
+		createDto.CpuQuota = source.HostConfig.CPUQuota / 100000
+	}
+	if createDto.MemoryQuota == 0 {
+		createDto.MemoryQuota = source.HostConfig.Memory / (1024 * 1024 * 1024)
+	}
+	if createDto.StorageQuota == 0 {
+		if storageGB, err := common.ParseStorageOptSizeGB(source.HostConfig.StorageOpt); err == nil {
+			createDto.StorageQuota = int64(storageGB)
+		}
+	}
+
+	// The clone keeps the organization labels of the source sandbox
+	if orgID := source.Config.Labels["daytona.organization_id"]; orgID != "" {
+		if createDto.Metadata == nil {
+			createDto.Metadata = make(map[string]string)
+		}
+		createDto.Metadata["organizationId"] = orgID
+	}
+
+	return createDto, nil
+}
+
+// Clone creates a new sandbox from a snapshot of the filesystem, volumes and configuration of
+// an existing sandbox and starts it. Resources of 0 are copied from the source sandbox.
+// Volumes are mounted into the clone rather than copied, so both sandboxes share their contents.
+// Returns the version of the daemon of the clone.
+func (d *DockerClient) Clone(ctx context.Context, sourceId string, cloneDto dto.CloneSandboxDTO) (string, error) {
+	defer timer.Timer()()
+
+	if d.isMicroVM(sourceId) {
+		return "", errMicroVMUnsupported("cloning")
+	}
+
+	source, err := d.ContainerInspect(ctx, sourceId)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = d.apiClient.ContainerInspect(ctx, cloneDto.Id)
+	if err == nil {
+		return "", common_errors.NewConflictError(fmt.Errorf("sandbox %s already exists", cloneDto.Id))
+	}
+	if !errdefs.IsNotFound(err) {
+		return "", err
+	}
+
+	d.statesCache.SetSandboxState(ctx, cloneDto.Id, enums.SandboxStateCreating)
+
+	// The clone keeps running on the committed image after it is untagged
+	imageName := fmt.Sprintf("daytona-clone-%s:latest", cloneDto.Id)
+	if err := d.commitContainer(ctx, sourceId, imageName, nil); err != nil {
+		return "", fmt.Errorf("failed to snapshot sandbox %s: %w", sourceId, err)
+	}
+	defer func() {
+		cleanupCtx, cancel := cleanupContext(ctx)
+		defer cancel()
+		if err := d.RemoveImage(cleanupCtx, imageName, true); err != nil {
+			log.Warnf("Failed to remove clone image %s: %v", imageName, err)
+		}
+	}()
+
+	daemonAuthToken, err := generateDaemonAuthToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate daemon auth token: %w", err)
+	}
+
+	containerConfig, hostConfig, err := d.getCloneConfigs(source, sourceId, cloneDto, imageName, daemonAuthToken)
+	if err != nil {
+		return "", err
+	}
+
+	homeVolume, err := d.cloneHomeVolume(ctx, cloneDto.Id, hostConfig)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = d.apiClient.ContainerCreate(ctx, containerConfig, hostConfig, d.getContainerNetworkingConfig(ctx), &v1.Platform{
+		Architecture: "amd64",
+		OS:           "linux",
+	}, cloneDto.Id)
+	if err != nil {
+		if errdefs.IsConflict(err) {
+			return "", common_errors.NewConflictError(fmt.Errorf("sandbox %s already exists", cloneDto.Id))
+		}
+		d.removeBucketMounts(ctx, cloneDto.Id)
+		if homeVolume != "" {
+			d.removeHomeVolume(ctx, homeVolume)
+		}
+		return "", err
+	}
+
+	if err := d.applyVolumeQuotas(ctx, cloneDto.Id); err != nil {
+		log.Errorf("Failed to limit the data volumes of sandbox %s: %v", cloneDto.Id, err)
+	}
+
+	d.statesCache.SetDaemonAuthToken(ctx, cloneDto.Id, daemonAuthToken)
+
+	log.Infof("Cloned sandbox %s to %s", sourceId, cloneDto.Id)
+
+	daemonVersion, err := d.Start(ctx, cloneDto.Id, cloneDto.Metadata)
+	if err != nil {
+		d.releaseIfCanceled(ctx, cloneDto.Id)
+		return "", err
+	}
+
+	return daemonVersion, nil
+}
+
+// getCloneConfigs copies the configuration of the source sandbox, replacing its identity and
+// applying the resources requested for the clone
+func (d *DockerClient) getCloneConfigs(source container.InspectResponse, sourceId string, cloneDto dto.CloneSandboxDTO, imageName string, daemonAuthToken string) (*container.Config, *container.HostConfig, error) {
+	if source.Config == nil || source.HostConfig == nil {
+		return nil, nil, errors.New("source sandbox has no configuration")
+	}
+
+	containerConfig := *source.Config
+	containerConfig.Image = imageName
+	containerConfig.Hostname = cloneDto.Id
+	containerConfig.Labels = maps.Clone(source.Config.Labels)
+	if containerConfig.Labels == nil {
+		containerConfig.Labels = make(map[string]string)
+	}
+	// The tunnel and the expiry belong to the source sandbox. The labels are emptied rather than
+	// removed, as the clone would otherwise inherit them from the labels of the committed image.
+	for _, label := range cloneResetLabels {
+		if _, ok := containerConfig.Labels[label]; ok {
+			containerConfig.Labels[label] = ""
+		}
+	}
+	containerConfig.Env = make([]string, 0, len(source.Config.Env))
+	for _, env := range source.Config.Env {
+		key, _, _ := strings.Cut(env, "=")
+		switch key {
+		case "DAYTONA_SANDBOX_ID":
+			env = "DAYTONA_SANDBOX_ID=" + cloneDto.Id
+		case common.DAEMON_AUTH_TOKEN_ENV:
+			env = common.DAEMON_AUTH_TOKEN_ENV + "=" + daemonAuthToken
+		}
+		containerConfig.Env = append(containerConfig.Env, env)
+	}
+
+	hostConfig := *source.HostConfig
+	hostConfig.StorageOpt = maps.Clone(source.HostConfig.StorageOpt)
+	hostConfig.Binds = slices.Clone(source.HostConfig.Binds)
+
+	// The daemon of the clone must not share the socket directory of the source sandbox
+	sourceSocketBind := d.daemonSocketDir(sourceId) + ":"
+	for i, bind := range hostConfig.Binds {
+		if d.daemonSocketsDir == "" || !strings.HasPrefix(bind, sourceSocketBind) {
+			continue
+		}
+
+		socketBind, err := d.getDaemonSocketBind(cloneDto.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		hostConfig.Binds[i] = socketBind
+	}
+
+	if cloneDto.Cpu > 0 {
+		hostConfig.CPUQuota = cloneDto.Cpu * 100000 // 1 core = 100000
+		hostConfig.CPUPeriod = 100000
+	}
+	if cloneDto.Memory > 0 {
+		hostConfig.Memory = common.GBToBytes(float64(cloneDto.Memory))
+		hostConfig.MemorySwap = hostConfig.Memory // Disable swap
+	}
+	if cloneDto.Disk > 0 && hostConfig.StorageOpt != nil {
+		hostConfig.StorageOpt["size"] = fmt.Sprintf("%dG", cloneDto.Disk)
+		containerConfig.Labels[storageQuotaLabel] = strconv.FormatInt(cloneDto.Disk, 10)
+	}
+
+	// Pinned sandboxes can't share their CPUs with their clones
+	err := d.repinCpus(cloneDto.Id, containerConfig.Labels, source.HostConfig.Resources, cloneDto.Cpu, cloneDto.Memory, &hostConfig.Resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The clone mounts the buckets of the source sandbox on its own mount directories
+	hostConfig.Mounts, err = d.cloneBucketMounts(sourceId, cloneDto.Id, source.HostConfig.Mounts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &containerConfig, &hostConfig, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+type Compression string
+
+const (
+	CompressionNone Compression = "none"
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+type CompressionConfig struct {
+	// Default algorithm of compressed streams
+	Algorithm Compression
+	// Compression level of the algorithm, 0 uses its default level
+	Level int
+	// Number of streams compressed at the same time. Each stream uses a single CPU core.
+	Workers int
+}
+
+// ParseCompression returns the compression algorithm with the given name
+func ParseCompression(name string) (Compression, error) {
+	switch Compression(name) {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return Compression(name), nil
+	}
+
+	return "", fmt.Errorf("unsupported compression: %s", name)
+}
+
+// ContentType returns the media type of streams compressed with the algorithm
+func (c Compression) ContentType() string {
+	switch c {
+	case CompressionGzip:
+		return "application/gzip"
+	case CompressionZstd:
+		return "application/zstd"
+	}
+
+	return "application/x-tar"
+}
+
+// Extension returns the file extension of tar streams compressed with the algorithm
+func (c Compression) Extension() string {
+	switch c {
+	case CompressionGzip:
+		return ".tar.gz"
+	case CompressionZstd:
+		return ".tar.zst"
+	}
+
+	return ".tar"
+}
+
+type compressWriter struct {
+	io.WriteCloser
+	release func()
+}
+
+func (w *compressWriter) Close() error {
+	defer w.release()
+	return w.WriteCloser.Close()
+}
+
+// newCompressWriter returns a writer compressing into w. It waits for a free compression worker,
+// which is held until the writer is closed, so compression can't starve sandboxes of CPU.
+func (d *DockerClient) newCompressWriter(ctx context.Context, w io.Writer, algorithm Compression) (io.WriteCloser, error) {
+	select {
+	case d.compressionWorkers <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	release := sync.OnceFunc(func() {
+		<-d.compressionWorkers
+	})
+
+	var writer io.WriteCloser
+	var err error
+
+	switch algorithm {
+	case CompressionGzip:
+		level := gzip.DefaultCompression
+		if d.compression.Level != 0 {
+			level = d.compression.Level
+		}
+		writer, err = gzip.NewWriterLevel(w, level)
+	case CompressionZstd:
+		level := zstd.SpeedDefault
+		if d.compression.Level != 0 {
+			level = zstd.EncoderLevelFromZstd(d.compression.Level)
+		}
+		writer, err = zstd.NewWriter(w, zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1))
+	default:
+		writer = nopWriteCloser{w}
+	}
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	return &compressWriter{WriteCloser: writer, release: release}, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
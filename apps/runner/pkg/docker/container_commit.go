@@ -9,13 +9,38 @@ import (
 	"io"
 	"strings"
 
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 
 	log "github.com/sirupsen/logrus"
 )
 
-func (d *DockerClient) commitContainer(ctx context.Context, containerId, imageName string) error {
+// commitContainer commits the filesystem of a container to an image. Options that rewrite the
+// image config or its layers squash the container instead of committing it, as do containers
+// with secret env, which docker commit would copy into the image config.
+func (d *DockerClient) commitContainer(ctx context.Context, containerId, imageName string, options *dto.CommitOptionsDTO) error {
+	if options != nil && (options.Squash || options.ScrubEnv || options.DeterministicTimestamps) {
+		return d.squashContainer(ctx, containerId, imageName, *options)
+	}
+
+	c, err := d.apiClient.ContainerInspect(ctx, containerId)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container %s: %w", containerId, err)
+	}
+	if c.Config == nil {
+		return fmt.Errorf("container %s has no config", containerId)
+	}
+
+	if c.Config.Labels[common.SECRET_ENV_KEYS_LABEL] != "" {
+		squashOptions := dto.CommitOptionsDTO{}
+		if options != nil {
+			squashOptions = *options
+		}
+		return d.squashContainer(ctx, containerId, imageName, squashOptions)
+	}
+
 	const maxRetries = 3
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
@@ -24,6 +49,7 @@ func (d *DockerClient) commitContainer(ctx context.Context, containerId, imageNa
 		commitResp, err := d.apiClient.ContainerCommit(ctx, containerId, container.CommitOptions{
 			Reference: imageName,
 			Pause:     false,
+			Config:    commitConfig(c.Config),
 		})
 		if err == nil {
 			log.Infof("Container %s committed successfully with image ID: %s", containerId, commitResp.ID)
@@ -43,17 +69,36 @@ func (d *DockerClient) commitContainer(ctx context.Context, containerId, imageNa
 			log.Errorf("Export/import fallback also failed for container %s: %v", containerId, err)
 		}
 
-		if attempt < maxRetries {
+		if attempt < maxRetries && IsRetryable(err) {
 			log.Warnf("Failed to commit container %s (attempt %d/%d): %v", containerId, attempt, maxRetries, err)
 			continue
 		}
 
-		return fmt.Errorf("failed to commit container after %d attempts: %w", maxRetries, err)
+		return fmt.Errorf("failed to commit container after %d attempts: %w", attempt, err)
 	}
 
 	return nil
 }
 
+// commitConfig returns the image config docker commit is given for a container. Docker adds the
+// container env variables and labels missing from it, so the daemon auth token and the labels the
+// runner manages are kept out of the image by overriding them with empty values. Sandboxes created
+// from the image would otherwise inherit e.g. the expiry of the committed sandbox.
+func commitConfig(config *container.Config) *container.Config {
+	commitConfig := *config
+	commitConfig.Env = append(scrubSecretEnv(config.Env, config.Labels), common.DAEMON_AUTH_TOKEN_ENV+"=")
+
+	commitConfig.Labels = make(map[string]string, len(config.Labels))
+	for key, value := range config.Labels {
+		if strings.HasPrefix(key, common.RUNNER_LABEL_PREFIX) {
+			value = ""
+		}
+		commitConfig.Labels[key] = value
+	}
+
+	return &commitConfig
+}
+
 func (d *DockerClient) exportImportContainer(ctx context.Context, containerId, imageName string) error {
 	log.Infof("Exporting container %s and importing as image %s...", containerId, imageName)
 
@@ -90,9 +135,9 @@ func (d *DockerClient) exportImportContainer(ctx context.Context, containerId, i
 		changes = append(changes, fmt.Sprintf("ENTRYPOINT %s", entrypointStr))
 	}
 
-	// Preserve environment variables
+	// Preserve environment variables, except for the secret ones
 	if len(containerInfo.Config.Env) > 0 {
-		for _, env := range containerInfo.Config.Env {
+		for _, env := range scrubSecretEnv(containerInfo.Config.Env, containerInfo.Config.Labels) {
 			splitEnv := strings.SplitN(env, "=", 2)
 			if len(splitEnv) != 2 {
 				continue
@@ -5,9 +5,13 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
 	"github.com/daytonaio/runner/cmd/runner/config"
 	"github.com/daytonaio/runner/pkg/api/dto"
 	"github.com/daytonaio/runner/pkg/common"
@@ -18,8 +22,8 @@ import (
 	"github.com/docker/docker/api/types/system"
 )
 
-func (d *DockerClient) getContainerConfigs(ctx context.Context, sandboxDto dto.CreateSandboxDTO, volumeMountPathBinds []string) (*container.Config, *container.HostConfig, *network.NetworkingConfig, error) {
-	containerConfig, err := d.getContainerCreateConfig(ctx, sandboxDto)
+func (d *DockerClient) getContainerConfigs(ctx context.Context, sandboxDto dto.CreateSandboxDTO, volumeMountPathBinds []string, daemonAuthToken string) (*container.Config, *container.HostConfig, *network.NetworkingConfig, error) {
+	containerConfig, err := d.getContainerCreateConfig(ctx, sandboxDto, daemonAuthToken)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -33,18 +37,25 @@ func (d *DockerClient) getContainerConfigs(ctx context.Context, sandboxDto dto.C
 	return containerConfig, hostConfig, networkingConfig, nil
 }
 
-func (d *DockerClient) getContainerCreateConfig(ctx context.Context, sandboxDto dto.CreateSandboxDTO) (*container.Config, error) {
+func (d *DockerClient) getContainerCreateConfig(ctx context.Context, sandboxDto dto.CreateSandboxDTO, daemonAuthToken string) (*container.Config, error) {
 	envVars := []string{
 		"DAYTONA_SANDBOX_ID=" + sandboxDto.Id,
 		"DAYTONA_SANDBOX_SNAPSHOT=" + sandboxDto.Snapshot,
 		"DAYTONA_SANDBOX_USER=" + sandboxDto.OsUser,
+		common.DAEMON_AUTH_TOKEN_ENV + "=" + daemonAuthToken,
 	}
 
 	for key, value := range sandboxDto.Env {
+		// The auth token can't be overridden
+		if key == common.DAEMON_AUTH_TOKEN_ENV {
+			continue
+		}
 		envVars = append(envVars, fmt.Sprintf("%s=%s", key, value))
 	}
 
-	labels := make(map[string]string)
+	labels := map[string]string{
+		common.SANDBOX_LABEL: "true",
+	}
 	if sandboxDto.Metadata != nil {
 		if orgID, ok := sandboxDto.Metadata["organizationId"]; ok && orgID != "" {
 			labels["daytona.organization_id"] = orgID
@@ -54,17 +65,119 @@ func (d *DockerClient) getContainerCreateConfig(ctx context.Context, sandboxDto
 		}
 	}
 
+	labels[storageQuotaLabel] = strconv.FormatInt(sandboxDto.StorageQuota, 10)
+
+	if sandboxDto.CpuPinning {
+		labels[common.CPU_PINNING_LABEL] = "true"
+	}
+
+	if sandboxDto.EncryptedEnv != nil {
+		secretEnv, err := d.decryptEnv(*sandboxDto.EncryptedEnv)
+		if err != nil {
+			return nil, err
+		}
+		delete(secretEnv, common.DAEMON_AUTH_TOKEN_ENV)
+
+		for key, value := range secretEnv {
+			envVars = append(envVars, fmt.Sprintf("%s=%s", key, value))
+		}
+		if len(secretEnv) > 0 {
+			labels[common.SECRET_ENV_KEYS_LABEL] = secretEnvKeys(secretEnv)
+		}
+	}
+
+	if sandboxDto.NestedDocker != nil {
+		labels[common.NESTED_DOCKER_LABEL] = sandboxDto.NestedDocker.Mode
+		if sandboxDto.NestedDocker.Mode == dto.NestedDockerModeDind {
+			envVars = append(envVars, "DOCKER_HOST="+dindHost)
+		}
+	}
+
+	if d.usesPackageCache(sandboxDto) {
+		envVars = append(envVars, d.packageCacheEnv(envVars)...)
+		labels[common.PACKAGE_CACHE_LABEL] = "true"
+	}
+
+	if sandboxDto.Provisioning != nil {
+		labels[common.PROVISIONING_LABEL] = "true"
+	}
+
+	if sandboxDto.Dotfiles != nil {
+		labels[common.DOTFILES_LABEL] = "true"
+	}
+
+	if err := setExpiryLabels(labels, sandboxDto.Expiry); err != nil {
+		return nil, err
+	}
+
+	if sandboxDto.Dns != nil && len(sandboxDto.Dns.AllowedDomains) > 0 {
+		labels[common.DNS_ALLOWED_DOMAINS_LABEL] = strings.Join(sandboxDto.Dns.AllowedDomains, ",")
+	}
+
+	if sandboxDto.EgressProxy != nil {
+		if d.egressProxyPort == 0 {
+			return nil, common_errors.NewBadRequestError(errors.New("egress proxy is not enabled on the runner"))
+		}
+		labels[common.EGRESS_ALLOWED_DOMAINS_LABEL] = strings.Join(sandboxDto.EgressProxy.AllowedDomains, ",")
+		if sandboxDto.EgressProxy.UpstreamProxyUrl != "" {
+			err := d.setUpstreamProxyLabels(labels, sandboxDto.EgressProxy.UpstreamProxyUrl)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if sandboxDto.WireGuard != nil {
+		if len(d.wireGuardKey) == 0 {
+			return nil, common_errors.NewBadRequestError(errors.New("WireGuard tunnels are not enabled on the runner"))
+		}
+		wireGuardConfig, err := d.encryptWireGuardConfig(*sandboxDto.WireGuard)
+		if err != nil {
+			return nil, err
+		}
+		labels[common.WIREGUARD_CONFIG_LABEL] = wireGuardConfig
+	}
+
+	socketEnvVars, socketLabels := d.getDaemonSocketEnv()
+	envVars = append(envVars, socketEnvVars...)
+	envVars = append(envVars, d.coreDumpEnv()...)
+	for key, value := range socketLabels {
+		labels[key] = value
+	}
+
+	boot, err := d.resolveBoot(sandboxDto)
+	if err != nil {
+		return nil, err
+	}
+
+	useSnapshotEntrypoint := d.useSnapshotEntrypoint
 	workingDir := ""
+	user := ""
 	cmd := []string{}
 	entrypoint := sandboxDto.Entrypoint
-	if !d.useSnapshotEntrypoint {
+	if boot != nil {
+		if boot.UseSnapshotEntrypoint != nil {
+			useSnapshotEntrypoint = *boot.UseSnapshotEntrypoint
+		}
+		if boot.Entrypoint != nil {
+			entrypoint = boot.Entrypoint
+		}
+		workingDir = boot.WorkingDir
+		user = boot.User
+	}
+
+	if useSnapshotEntrypoint {
+		if boot != nil && boot.Args != nil {
+			cmd = boot.Args
+		}
+	} else {
 		// Inspect image
 		image, err := d.apiClient.ImageInspect(ctx, sandboxDto.Snapshot)
 		if err != nil {
 			return nil, err
 		}
 
-		if image.Config.WorkingDir != "" {
+		if workingDir == "" && image.Config.WorkingDir != "" {
 			workingDir = image.Config.WorkingDir
 		}
 
@@ -73,10 +186,9 @@ func (d *DockerClient) getContainerCreateConfig(ctx context.Context, sandboxDto
 			envVars = append(envVars, "DAYTONA_USER_HOME_AS_WORKDIR=true")
 		}
 
-		entrypoint = []string{common.DAEMON_PATH}
-
-		if len(sandboxDto.Entrypoint) != 0 {
-			cmd = append(cmd, sandboxDto.Entrypoint...)
+		// The daemon starts the entrypoint of the sandbox
+		if len(entrypoint) != 0 {
+			cmd = append(cmd, entrypoint...)
 		} else {
 			if slices.Equal(image.Config.Entrypoint, strslice.StrSlice{common.DAEMON_PATH}) {
 				cmd = append(cmd, image.Config.Cmd...)
@@ -84,12 +196,18 @@ func (d *DockerClient) getContainerCreateConfig(ctx context.Context, sandboxDto
 				cmd = append(cmd, image.Config.Entrypoint...)
 			}
 		}
+		if boot != nil && boot.Args != nil {
+			cmd = append(cmd, boot.Args...)
+		}
+
+		entrypoint = []string{common.DAEMON_PATH}
 	}
 
 	return &container.Config{
 		Hostname:     sandboxDto.Id,
 		Image:        sandboxDto.Snapshot,
 		WorkingDir:   workingDir,
+		User:         user,
 		Env:          envVars,
 		Entrypoint:   entrypoint,
 		Cmd:          cmd,
@@ -109,6 +227,14 @@ func (d *DockerClient) getContainerHostConfig(ctx context.Context, sandboxDto dt
 		binds = append(binds, fmt.Sprintf("%s:/usr/local/lib/daytona-computer-use:ro", d.computerUsePluginPath))
 	}
 
+	socketBind, err := d.getDaemonSocketBind(sandboxDto.Id)
+	if err != nil {
+		return nil, err
+	}
+	if socketBind != "" {
+		binds = append(binds, socketBind)
+	}
+
 	if len(volumeMountPathBinds) > 0 {
 		binds = append(binds, volumeMountPathBinds...)
 	}
@@ -119,6 +245,35 @@ func (d *DockerClient) getContainerHostConfig(ctx context.Context, sandboxDto dt
 		Binds:      binds,
 	}
 
+	if sandboxDto.ReadOnlyRootfs != nil {
+		hostConfig.Mounts, err = readOnlyRootfsMounts(sandboxDto)
+		if err != nil {
+			return nil, err
+		}
+		hostConfig.ReadonlyRootfs = true
+	}
+
+	err = d.setHomeVolumeMount(ctx, sandboxDto, hostConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.dnsForwarderAddress != "" {
+		hostConfig.DNS = []string{d.dnsForwarderAddress}
+	}
+
+	if sandboxDto.Dns != nil {
+		// Allowed domains are enforced by the forwarder, which custom resolvers would bypass
+		if len(sandboxDto.Dns.AllowedDomains) > 0 && (d.dnsForwarderAddress == "" || len(sandboxDto.Dns.Servers) > 0) {
+			return nil, common_errors.NewBadRequestError(errors.New("allowed domains require the runner DNS forwarder and no custom DNS servers"))
+		}
+		if len(sandboxDto.Dns.Servers) > 0 {
+			hostConfig.DNS = sandboxDto.Dns.Servers
+		}
+		hostConfig.DNSSearch = sandboxDto.Dns.Search
+		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, sandboxDto.Dns.ExtraHosts...)
+	}
+
 	if !d.resourceLimitsDisabled {
 		hostConfig.Resources = container.Resources{
 			CPUPeriod:  100000,
@@ -128,11 +283,35 @@ func (d *DockerClient) getContainerHostConfig(ctx context.Context, sandboxDto dt
 		}
 	}
 
+	if sandboxDto.Devices != nil {
+		hostConfig.Devices, err = d.getDeviceMappings(*sandboxDto.Devices)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if sandboxDto.CpuPinning {
+		if err := d.pinCpus(sandboxDto.Id, sandboxDto.CpuQuota, sandboxDto.MemoryQuota, &hostConfig.Resources); err != nil {
+			return nil, err
+		}
+	}
+
+	d.setCoreDumpLimits(hostConfig)
+
 	containerRuntime := config.GetContainerRuntime()
 	if containerRuntime != "" {
 		hostConfig.Runtime = containerRuntime
 	}
 
+	if err := d.validateNestedDocker(sandboxDto); err != nil {
+		return nil, err
+	}
+	// Sysbox isolates the sandbox with user namespaces instead, which privileged mode would undo
+	if sandboxDto.NestedDocker != nil && sandboxDto.NestedDocker.Mode == dto.NestedDockerModeSysbox {
+		hostConfig.Runtime = d.sysboxRuntime
+		hostConfig.Privileged = false
+	}
+
 	info, err := d.apiClient.Info(ctx)
 	if err != nil {
 		return nil, err
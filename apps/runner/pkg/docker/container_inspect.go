@@ -5,10 +5,43 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
 
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/faults"
 	"github.com/docker/docker/api/types/container"
 )
 
+// ContainerInspect inspects the container of a sandbox. microVMs are described as containers.
+// Responses are served from the inspect cache if it is enabled, each caller gets its own copy.
 func (d *DockerClient) ContainerInspect(ctx context.Context, containerId string) (container.InspectResponse, error) {
-	return d.apiClient.ContainerInspect(ctx, containerId)
+	if err := faults.Inject(ctx, faults.PointDockerInspect, containerId); err != nil {
+		return container.InspectResponse{}, err
+	}
+
+	if d.isMicroVM(containerId) {
+		return d.microVMs.Inspect(containerId)
+	}
+
+	if !d.inspectCache.enabled() {
+		return d.apiClient.ContainerInspect(ctx, containerId)
+	}
+
+	if raw, ok := d.inspectCache.get(containerId); ok {
+		var c container.InspectResponse
+		if err := json.Unmarshal(raw, &c); err == nil {
+			common.ContainerInspectCacheCount.WithLabelValues("hit").Inc()
+			return c, nil
+		}
+	}
+	common.ContainerInspectCacheCount.WithLabelValues("miss").Inc()
+
+	generation := d.inspectCache.currentGeneration()
+	c, raw, err := d.apiClient.ContainerInspectWithRaw(ctx, containerId, false)
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+
+	d.inspectCache.set(containerId, c.ID, raw, generation)
+	return c, nil
 }
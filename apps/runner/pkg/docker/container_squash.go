@@ -0,0 +1,267 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const squashedLayerName = "layer.tar"
+
+// squashContainer exports the filesystem of a container and loads it as an image with a single
+// layer. The image config is built from the container config, so the history of the image
+// layers the container was created from is not carried over.
+func (d *DockerClient) squashContainer(ctx context.Context, containerId, imageName string, options dto.CommitOptionsDTO) error {
+	log.Infof("Squashing container %s into image %s...", containerId, imageName)
+
+	c, err := d.apiClient.ContainerInspect(ctx, containerId)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container %s: %w", containerId, err)
+	}
+	if c.Config == nil {
+		return fmt.Errorf("container %s has no config", containerId)
+	}
+
+	env := scrubSecretEnv(c.Config.Env, c.Config.Labels)
+	if options.ScrubEnv {
+		env, err = d.getImageEnv(ctx, c.Image)
+		if err != nil {
+			return err
+		}
+	}
+
+	created := time.Now().UTC()
+	var modTime *time.Time
+	if options.DeterministicTimestamps {
+		created = time.Unix(0, 0).UTC()
+		modTime = &created
+	}
+
+	// The layer is written to disk first, as the image archive needs its size and digest upfront
+	layer, err := os.CreateTemp("", "daytona-squash-*.tar")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(layer.Name())
+	defer layer.Close()
+
+	exportReader, err := d.apiClient.ContainerExport(ctx, containerId)
+	if err != nil {
+		return fmt.Errorf("failed to export container %s: %w", containerId, err)
+	}
+	defer exportReader.Close()
+
+	// docker load decompresses layers, which keeps large sandboxes from filling up the runner disk
+	layerWriter, err := d.newCompressWriter(ctx, layer, d.compression.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	layerHash := sha256.New()
+	err = rewriteLayer(exportReader, io.MultiWriter(layerWriter, layerHash), modTime)
+	if err != nil {
+		layerWriter.Close()
+		return fmt.Errorf("failed to export container %s: %w", containerId, err)
+	}
+
+	if err := layerWriter.Close(); err != nil {
+		return fmt.Errorf("failed to export container %s: %w", containerId, err)
+	}
+
+	imageConfig, err := json.Marshal(v1.Image{
+		Created: &created,
+		Platform: v1.Platform{
+			Architecture: "amd64",
+			OS:           "linux",
+		},
+		Config: v1.ImageConfig{
+			User:         c.Config.User,
+			ExposedPorts: getExposedPorts(c.Config),
+			Env:          env,
+			Entrypoint:   c.Config.Entrypoint,
+			Cmd:          c.Config.Cmd,
+			WorkingDir:   c.Config.WorkingDir,
+			StopSignal:   c.Config.StopSignal,
+		},
+		RootFS: v1.RootFS{
+			Type:    "layers",
+			DiffIDs: []digest.Digest{digest.NewDigest(digest.SHA256, layerHash)},
+		},
+		History: []v1.History{
+			{
+				Created:   &created,
+				CreatedBy: fmt.Sprintf("squashed from sandbox %s", containerId),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	manifest, err := json.Marshal([]map[string]any{
+		{
+			"Config":   "config.json",
+			"RepoTags": []string{withDefaultTag(imageName)},
+			"Layers":   []string{squashedLayerName},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeImageArchive(writer, layer, imageConfig, manifest))
+	}()
+	defer reader.Close()
+
+	resp, err := d.apiClient.ImageLoad(ctx, reader)
+	if err != nil {
+		return fmt.Errorf("failed to load squashed image %s: %w", imageName, err)
+	}
+	defer resp.Body.Close()
+
+	err = jsonmessage.DisplayJSONMessagesStream(resp.Body, io.Discard, 0, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to load squashed image %s: %w", imageName, err)
+	}
+
+	log.Infof("Container %s squashed into image %s", containerId, imageName)
+	return nil
+}
+
+// getImageEnv returns the environment the image of a sandbox was built with
+func (d *DockerClient) getImageEnv(ctx context.Context, imageId string) ([]string, error) {
+	image, err := d.apiClient.ImageInspect(ctx, imageId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image %s: %w", imageId, err)
+	}
+
+	if image.Config == nil {
+		return nil, nil
+	}
+
+	return image.Config.Env, nil
+}
+
+func getExposedPorts(config *container.Config) map[string]struct{} {
+	if len(config.ExposedPorts) == 0 {
+		return nil
+	}
+
+	ports := make(map[string]struct{}, len(config.ExposedPorts))
+	for port := range config.ExposedPorts {
+		ports[string(port)] = struct{}{}
+	}
+	return ports
+}
+
+// rewriteLayer copies a filesystem tar, setting the times of all entries to modTime if set
+func rewriteLayer(src io.Reader, dst io.Writer, modTime *time.Time) error {
+	tr := tar.NewReader(src)
+	tw := tar.NewWriter(dst)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if modTime != nil {
+			header.ModTime = *modTime
+			header.AccessTime = time.Time{}
+			header.ChangeTime = time.Time{}
+			for _, key := range []string{"mtime", "atime", "ctime"} {
+				delete(header.PAXRecords, key)
+			}
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// writeImageArchive writes an archive in the format of docker save with a single layer
+func writeImageArchive(w io.Writer, layer *os.File, imageConfig, manifest []byte) error {
+	info, err := layer.Stat()
+	if err != nil {
+		return err
+	}
+
+	if _, err := layer.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+
+	err = tw.WriteHeader(&tar.Header{
+		Name: squashedLayerName,
+		Mode: 0644,
+		Size: info.Size(),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, layer); err != nil {
+		return err
+	}
+
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"config.json", imageConfig},
+		{"manifest.json", manifest},
+	}
+	for _, file := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name: file.name,
+			Mode: 0644,
+			Size: int64(len(file.content)),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.content); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// withDefaultTag adds the latest tag to image names without one, as image archives require a tag
+func withDefaultTag(imageName string) string {
+	name := imageName[strings.LastIndex(imageName, "/")+1:]
+	if strings.Contains(name, ":") || strings.Contains(name, "@") {
+		return imageName
+	}
+	return imageName + ":latest"
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-units"
+)
+
+// Directory in the sandboxes the kernel writes core dumps to, the daemon serves and cleans it up
+const coreDumpDir = "/var/lib/daytona/cores"
+
+const corePatternPath = "/proc/sys/kernel/core_pattern"
+
+// CorePattern is the kernel.core_pattern the host must be configured with for sandboxes to dump
+// cores. The kernel resolves it in the mount namespace of the crashed process, so host processes
+// only dump if the directory exists on the host.
+const CorePattern = coreDumpDir + "/core.%e.%p.%t"
+
+type CoreDumpsConfig struct {
+	// Size limit of a single core dump in bytes, larger dumps are truncated
+	MaxSize int64
+	// Number of core dumps kept in a sandbox, the oldest are removed
+	MaxCount int
+	// Age after which core dumps are removed, 0 keeps them
+	Retention time.Duration
+}
+
+// CheckCorePattern returns an error if the host isn't configured with CorePattern. The pattern
+// applies to every process of the host, so it is left to the operator to set it, e.g. with
+// sysctl -w kernel.core_pattern=/var/lib/daytona/cores/core.%e.%p.%t
+func CheckCorePattern() error {
+	current, err := os.ReadFile(corePatternPath)
+	if err != nil {
+		return fmt.Errorf("failed to read the core pattern: %w", err)
+	}
+
+	if pattern := strings.TrimSpace(string(current)); pattern != CorePattern {
+		return fmt.Errorf("kernel.core_pattern is %q, set it to %q to enable core dumps", pattern, CorePattern)
+	}
+
+	return nil
+}
+
+// coreDumpEnv returns the env that enables the core dump endpoints and cleanup of the daemon
+func (d *DockerClient) coreDumpEnv() []string {
+	if d.coreDumps == nil {
+		return nil
+	}
+
+	return []string{
+		"DAYTONA_CORE_DUMPS_ENABLED=true",
+		fmt.Sprintf("DAYTONA_CORE_DUMP_MAX_COUNT=%d", d.coreDumps.MaxCount),
+		fmt.Sprintf("DAYTONA_CORE_DUMP_RETENTION_SECONDS=%d", int(d.coreDumps.Retention.Seconds())),
+	}
+}
+
+// setCoreDumpLimits caps the size of the core dumps of a sandbox and keeps the core dump directory
+// writable in sandboxes with a read-only root filesystem
+func (d *DockerClient) setCoreDumpLimits(hostConfig *container.HostConfig) {
+	if d.coreDumps == nil {
+		return
+	}
+
+	hostConfig.Ulimits = append(hostConfig.Ulimits, &units.Ulimit{
+		Name: "core",
+		Soft: d.coreDumps.MaxSize,
+		Hard: d.coreDumps.MaxSize,
+	})
+
+	writable := slices.ContainsFunc(hostConfig.Mounts, func(m mount.Mount) bool {
+		return m.Target == coreDumpDir
+	})
+	if hostConfig.ReadonlyRootfs && !writable {
+		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+			Type:   mount.TypeVolume,
+			Target: coreDumpDir,
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/topology"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// How long CPUs stay allocated to a sandbox without a container, so they aren't released while
+// the container is being created
+const cpuPinningGracePeriod = 10 * time.Minute
+
+// CpuTopology returns the NUMA nodes of the host and their usage by pinned sandboxes, or nil if
+// CPU pinning is disabled
+func (d *DockerClient) CpuTopology() []topology.NodeUsage {
+	if d.cpuAllocator == nil {
+		return nil
+	}
+
+	return d.cpuAllocator.Usage()
+}
+
+// pinCpus allocates dedicated CPUs of a single NUMA node to a sandbox and sets them, along with
+// the memory of the node, on its resources
+func (d *DockerClient) pinCpus(sandboxId string, cpu, memoryGB int64, resources *container.Resources) error {
+	if d.cpuAllocator == nil {
+		return common_errors.NewBadRequestError(errors.New("CPU pinning is not enabled on the runner"))
+	}
+
+	allocation, err := d.cpuAllocator.Allocate(sandboxId, int(cpu), common.GBToBytes(float64(memoryGB)))
+	if err != nil {
+		if errors.Is(err, topology.ErrNoCapacity) {
+			return common_errors.NewCustomError(http.StatusServiceUnavailable, err.Error(), "SERVICE_UNAVAILABLE")
+		}
+		return err
+	}
+
+	resources.CpusetCpus = topology.FormatCpuList(allocation.Cpus)
+	resources.CpusetMems = strconv.Itoa(allocation.Node)
+
+	return nil
+}
+
+// repinCpus moves a pinned sandbox to CPUs that fit its new size. Sandboxes that aren't pinned
+// are left alone.
+func (d *DockerClient) repinCpus(sandboxId string, labels map[string]string, current container.Resources, cpu, memoryGB int64, resources *container.Resources) error {
+	if labels[common.CPU_PINNING_LABEL] != "true" || d.cpuAllocator == nil {
+		return nil
+	}
+
+	if cpu == 0 {
+		cpu = current.CPUQuota / 100000
+	}
+	if memoryGB == 0 {
+		memoryGB = int64(current.Memory / (1024 * 1024 * 1024))
+	}
+
+	return d.pinCpus(sandboxId, cpu, memoryGB, resources)
+}
+
+// RestoreCpuPinning records the CPUs of the existing pinned sandboxes, e.g. after the runner restarts
+func (d *DockerClient) RestoreCpuPinning(ctx context.Context) error {
+	if d.cpuAllocator == nil {
+		return nil
+	}
+
+	containers, err := d.apiClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", common.CPU_PINNING_LABEL+"=true")),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range containers {
+		info, err := d.apiClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			log.Warnf("Failed to inspect pinned sandbox %s: %v", c.ID, err)
+			continue
+		}
+
+		cpus, err := topology.ParseCpuList(info.HostConfig.CpusetCpus)
+		if err != nil {
+			log.Warnf("Failed to restore the CPUs of sandbox %s: %v", info.Name, err)
+			continue
+		}
+
+		node, err := strconv.Atoi(info.HostConfig.CpusetMems)
+		if err != nil {
+			log.Warnf("Failed to restore the NUMA node of sandbox %s: %v", info.Name, err)
+			continue
+		}
+
+		d.cpuAllocator.Restore(strings.TrimPrefix(info.Name, "/"), node, cpus, info.HostConfig.Memory)
+	}
+
+	return nil
+}
+
+// ReleaseOrphanedCpuPinning releases the CPUs of pinned sandboxes that no longer exist
+func (d *DockerClient) ReleaseOrphanedCpuPinning(ctx context.Context) {
+	if d.cpuAllocator == nil {
+		return
+	}
+
+	containers, err := d.apiClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", common.CPU_PINNING_LABEL+"=true")),
+	})
+	if err != nil {
+		log.Errorf("Failed to list pinned sandboxes: %v", err)
+		return
+	}
+
+	var sandboxIds []string
+	for _, c := range containers {
+		for _, name := range c.Names {
+			sandboxIds = append(sandboxIds, strings.TrimPrefix(name, "/"))
+		}
+	}
+
+	for _, sandboxId := range d.cpuAllocator.ReleaseExcept(sandboxIds, cpuPinningGracePeriod) {
+		log.Debugf("Released the pinned CPUs of sandbox %s", sandboxId)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/daytonaio/runner/internal/constants"
 	"github.com/daytonaio/runner/pkg/api/dto"
 	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/faults"
 	"github.com/daytonaio/runner/pkg/models/enums"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 
@@ -33,12 +34,16 @@ func (d *DockerClient) Create(ctx context.Context, sandboxDto dto.CreateSandboxD
 		}
 	}()
 
+	if err := faults.Inject(ctx, faults.PointDockerCreate, sandboxDto.Id); err != nil {
+		return "", "", err
+	}
+
 	state, err := d.DeduceSandboxState(ctx, sandboxDto.Id)
 	if err != nil && state == enums.SandboxStateError {
 		return "", "", err
 	}
 
-	if state == enums.SandboxStateStarted || state == enums.SandboxStatePullingSnapshot || state == enums.SandboxStateStarting {
+	if state == enums.SandboxStateStarted || state == enums.SandboxStatePullingSnapshot || state == enums.SandboxStateStarting || state == enums.SandboxStateProvisioning {
 		daemonVersion, err := d.GetDaemonVersion(ctx, sandboxDto.Id)
 		if err != nil {
 			return "", "", err
@@ -56,6 +61,12 @@ func (d *DockerClient) Create(ctx context.Context, sandboxDto dto.CreateSandboxD
 		return sandboxDto.Id, daemonVersion, nil
 	}
 
+	if sandboxDto.Class == dto.SandboxClassMicroVM {
+		if err := d.validateMicroVM(sandboxDto); err != nil {
+			return "", "", err
+		}
+	}
+
 	d.statesCache.SetSandboxState(ctx, sandboxDto.Id, enums.SandboxStateCreating)
 
 	ctx = context.WithValue(ctx, constants.ID_KEY, sandboxDto.Id)
@@ -72,18 +83,36 @@ func (d *DockerClient) Create(ctx context.Context, sandboxDto dto.CreateSandboxD
 		return "", "", err
 	}
 
+	if sandboxDto.Class == dto.SandboxClassMicroVM {
+		return d.createMicroVM(ctx, sandboxDto)
+	}
+
 	volumeMountPathBinds := make([]string, 0)
 	if sandboxDto.Volumes != nil {
-		volumeMountPathBinds, err = d.getVolumesMountPathBinds(ctx, sandboxDto.Volumes)
+		var releaseVolumeMounts func()
+		volumeMountPathBinds, releaseVolumeMounts, err = d.getVolumesMountPathBinds(ctx, sandboxDto.Id, sandboxDto.Volumes)
 		if err != nil {
 			return "", "", err
 		}
+		// Once the container exists, it keeps its volume mounts from being cleaned up
+		defer releaseVolumeMounts()
+	}
+
+	daemonAuthToken, err := generateDaemonAuthToken()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate daemon auth token: %w", err)
+	}
+
+	containerConfig, hostConfig, networkingConfig, err := d.getContainerConfigs(ctx, sandboxDto, volumeMountPathBinds, daemonAuthToken)
+	if err != nil {
+		return "", "", err
 	}
 
-	containerConfig, hostConfig, networkingConfig, err := d.getContainerConfigs(ctx, sandboxDto, volumeMountPathBinds)
+	bucketMounts, err := d.prepareBucketMounts(sandboxDto)
 	if err != nil {
 		return "", "", err
 	}
+	hostConfig.Mounts = append(hostConfig.Mounts, bucketMounts...)
 
 	c, err := d.apiClient.ContainerCreate(ctx, containerConfig, hostConfig, networkingConfig, &v1.Platform{
 		Architecture: "amd64",
@@ -94,16 +123,47 @@ func (d *DockerClient) Create(ctx context.Context, sandboxDto dto.CreateSandboxD
 		if errdefs.IsConflict(err) {
 			return sandboxDto.Id, "", nil
 		}
+		if len(bucketMounts) > 0 {
+			d.removeBucketMounts(ctx, sandboxDto.Id)
+		}
 		return "", "", err
 	}
 
+	if err := d.applyVolumeQuotas(ctx, sandboxDto.Id); err != nil {
+		log.Errorf("Failed to limit the data volumes of sandbox %s: %v", sandboxDto.Id, err)
+	}
+
+	if len(sandboxDto.Sidecars) > 0 || sandboxDto.NestedDocker != nil {
+		if err := d.createSidecars(ctx, sandboxDto); err != nil {
+			d.releaseIfCanceled(ctx, sandboxDto.Id)
+			return "", "", err
+		}
+	}
+
+	if sandboxDto.Provisioning != nil {
+		if err := d.copyProvisioningSpec(ctx, sandboxDto.Id, sandboxDto.Provisioning); err != nil {
+			d.releaseIfCanceled(ctx, sandboxDto.Id)
+			return "", "", fmt.Errorf("failed to copy provisioning steps: %w", err)
+		}
+	}
+
+	if sandboxDto.Dotfiles != nil {
+		if err := d.copySpecFile(ctx, sandboxDto.Id, dotfilesSpecPath, sandboxDto.Dotfiles); err != nil {
+			d.releaseIfCanceled(ctx, sandboxDto.Id)
+			return "", "", fmt.Errorf("failed to copy dotfiles: %w", err)
+		}
+	}
+
+	d.statesCache.SetDaemonAuthToken(ctx, sandboxDto.Id, daemonAuthToken)
+
 	daemonVersion, err := d.Start(ctx, sandboxDto.Id, sandboxDto.Metadata)
 	if err != nil {
+		d.releaseIfCanceled(ctx, sandboxDto.Id)
 		return "", "", err
 	}
 
 	containerShortId := c.ID[:12]
-	info, err := d.apiClient.ContainerInspect(context.Background(), sandboxDto.Id)
+	info, err := d.apiClient.ContainerInspect(ctx, sandboxDto.Id)
 	if err != nil {
 		log.Errorf("Failed to inspect container: %v", err)
 	}
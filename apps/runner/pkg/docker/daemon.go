@@ -12,6 +12,7 @@ import (
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
 	"github.com/daytonaio/common-go/pkg/timer"
 	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/faults"
 	"github.com/docker/docker/api/types/container"
 )
 
@@ -48,30 +49,49 @@ func (d *DockerClient) startDaytonaDaemon(ctx context.Context, containerId strin
 	return nil
 }
 
-func (d *DockerClient) waitForDaemonRunning(ctx context.Context, containerIP string) (string, error) {
+// waitForDaemonRunning waits until the daemon responds and reports itself as healthy
+func (d *DockerClient) waitForDaemonRunning(ctx context.Context, daemonUrl string, authToken string) (string, error) {
 	defer timer.Timer()()
 
+	if err := faults.Inject(ctx, faults.PointDaemonWait, daemonUrl); err != nil {
+		return "", err
+	}
+
 	// Build the target URL
-	targetURL := fmt.Sprintf("http://%s:2280/version", containerIP)
-	target, err := url.Parse(targetURL)
+	target, err := url.Parse(daemonUrl + "/version")
 	if err != nil {
 		return "", common_errors.NewBadRequestError(fmt.Errorf("failed to parse target URL: %w", err))
 	}
 
+	healthTarget, err := url.Parse(daemonUrl + "/health")
+	if err != nil {
+		return "", common_errors.NewBadRequestError(fmt.Errorf("failed to parse health URL: %w", err))
+	}
+
 	timeout := time.Duration(d.daemonStartTimeoutSec) * time.Second
 	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	var healthErr error
 	for {
 		select {
 		case <-timeoutCtx.Done():
+			if healthErr != nil {
+				return "", fmt.Errorf("timeout waiting for daemon to become healthy: %w", healthErr)
+			}
 			return "", fmt.Errorf("timeout waiting for daemon to start")
 		default:
-			version, err := d.getDaemonVersion(ctx, target)
+			version, err := d.getDaemonVersion(ctx, target, authToken)
 			if err != nil {
 				time.Sleep(5 * time.Millisecond)
 				continue
 			}
+
+			healthErr = d.checkDaemonHealth(ctx, healthTarget, authToken)
+			if healthErr != nil {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
 			return version, nil
 		}
 	}
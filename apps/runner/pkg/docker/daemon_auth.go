@@ -0,0 +1,54 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+)
+
+func generateDaemonAuthToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// GetDaemonAuthToken returns the secret the daemon of a sandbox requires on requests. Tokens
+// missing from the states cache, e.g. after a runner restart, are read from the sandbox
+// environment. Sandboxes created before daemon authentication have no token.
+func (d *DockerClient) GetDaemonAuthToken(ctx context.Context, c container.InspectResponse) string {
+	sandboxId := c.Name[strings.LastIndex(c.Name, "/")+1:]
+
+	cached, err := d.statesCache.Get(ctx, sandboxId)
+	if err == nil && cached.DaemonAuthToken != "" {
+		return cached.DaemonAuthToken
+	}
+
+	if c.Config == nil {
+		return ""
+	}
+
+	for _, env := range c.Config.Env {
+		if token, ok := strings.CutPrefix(env, common.DAEMON_AUTH_TOKEN_ENV+"="); ok {
+			d.statesCache.SetDaemonAuthToken(ctx, sandboxId, token)
+			return token
+		}
+	}
+
+	return ""
+}
+
+func setDaemonAuthHeader(req *http.Request, authToken string) {
+	if authToken != "" {
+		req.Header.Set(common.DAEMON_AUTH_TOKEN_HEADER, authToken)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	cmap "github.com/orcaman/concurrent-map/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	daemonDialTimeout = 5 * time.Second
+	// Consecutive connection failures after which the daemon of a sandbox is considered unreachable
+	daemonBreakerThreshold = 5
+	// How long requests to an unreachable daemon fail fast before one is let through again
+	daemonBreakerCooldown = 10 * time.Second
+	// Idempotent requests are retried this many times if the daemon can't be connected to
+	daemonRetryAttempts = 2
+	daemonRetryDelay    = 200 * time.Millisecond
+	// How long the HTTP/2 support of a daemon is remembered for
+	daemonProtocolTTL = 10 * time.Minute
+)
+
+var ErrDaemonUnreachable = errors.New("sandbox daemon is unreachable")
+
+// daemonBreaker stops requests to a daemon after repeated connection failures, so that callers
+// fail fast instead of each waiting for the connection to time out
+type daemonBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// allow lets a single request through after each cooldown while the breaker is open
+func (b *daemonBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < daemonBreakerThreshold {
+		return true
+	}
+
+	if time.Now().Before(b.openUntil) {
+		return false
+	}
+
+	b.openUntil = time.Now().Add(daemonBreakerCooldown)
+	return true
+}
+
+func (b *daemonBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures == daemonBreakerThreshold {
+		b.openUntil = time.Now().Add(daemonBreakerCooldown)
+	}
+}
+
+type daemonProtocol struct {
+	http2     bool
+	checkedAt time.Time
+}
+
+// daemonRoundTripper sends requests to sandbox daemons. Requests share a single HTTP/2 connection
+// per daemon if the daemon supports it, upgrade requests and older daemons use HTTP/1.1.
+type daemonRoundTripper struct {
+	http1     *http.Transport
+	http2     *http.Transport
+	breakers  cmap.ConcurrentMap[string, *daemonBreaker]
+	protocols cmap.ConcurrentMap[string, daemonProtocol]
+}
+
+func newDaemonRoundTripper(dial func(ctx context.Context, network, addr string) (net.Conn, error)) *daemonRoundTripper {
+	http1 := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+		DialContext:         dial,
+		Protocols:           &http.Protocols{},
+	}
+	http1.Protocols.SetHTTP1(true)
+
+	http2 := &http.Transport{
+		IdleConnTimeout: 90 * time.Second,
+		DialContext:     dial,
+		Protocols:       &http.Protocols{},
+		HTTP2: &http.HTTP2Config{
+			// Detects dead connections, which would otherwise fail all requests multiplexed on them
+			SendPingTimeout: 30 * time.Second,
+			PingTimeout:     15 * time.Second,
+		},
+	}
+	http2.Protocols.SetUnencryptedHTTP2(true)
+
+	return &daemonRoundTripper{
+		http1:     http1,
+		http2:     http2,
+		breakers:  cmap.New[*daemonBreaker](),
+		protocols: cmap.New[daemonProtocol](),
+	}
+}
+
+func (t *daemonRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	breaker := t.breakers.Upsert(req.URL.Host, nil, func(exists bool, valueInMap, _ *daemonBreaker) *daemonBreaker {
+		if exists {
+			return valueInMap
+		}
+		return &daemonBreaker{}
+	})
+
+	attempts := 1
+	if isRetryableDaemonRequest(req) {
+		attempts += daemonRetryAttempts
+	}
+
+	var err error
+	for attempt := range attempts {
+		if attempt > 0 {
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(time.Duration(attempt) * daemonRetryDelay):
+			}
+		}
+
+		if !breaker.allow() {
+			return nil, fmt.Errorf("%w: %s", ErrDaemonUnreachable, req.URL.Host)
+		}
+
+		var resp *http.Response
+		resp, err = t.transport(req).RoundTrip(req)
+		// Requests canceled by the caller say nothing about the daemon
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+
+		breaker.record(err)
+		if err == nil {
+			return resp, nil
+		}
+	}
+
+	return nil, err
+}
+
+func (t *daemonRoundTripper) transport(req *http.Request) *http.Transport {
+	if req.Header.Get("Upgrade") != "" {
+		return t.http1
+	}
+
+	if t.supportsHttp2(req.Context(), req.URL) {
+		return t.http2
+	}
+
+	return t.http1
+}
+
+// supportsHttp2 probes the daemon with an HTTP/2 request the first time it is used, as daemons
+// only accept HTTP/2 without TLS if they expect it
+func (t *daemonRoundTripper) supportsHttp2(ctx context.Context, target *url.URL) bool {
+	protocol, ok := t.protocols.Get(target.Host)
+	if ok && time.Since(protocol.checkedAt) < daemonProtocolTTL {
+		return protocol.http2
+	}
+
+	probeUrl := url.URL{Scheme: target.Scheme, Host: target.Host, Path: "/version"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probeUrl.String(), nil)
+	if err != nil {
+		return false
+	}
+
+	// Any response, including an authentication error, means the daemon speaks HTTP/2
+	resp, err := t.http2.RoundTrip(req)
+	if err == nil {
+		resp.Body.Close()
+	} else if isDaemonDialError(err) || ctx.Err() != nil {
+		return false
+	} else {
+		log.Debugf("Daemon at %s doesn't support HTTP/2: %v", target.Host, err)
+	}
+
+	t.protocols.Set(target.Host, daemonProtocol{http2: err == nil, checkedAt: time.Now()})
+	return err == nil
+}
+
+// isRetryableDaemonRequest reports if a request can be sent again after a failed attempt
+func isRetryableDaemonRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+
+	return (req.Body == nil || req.Body == http.NoBody) && req.Header.Get("Upgrade") == ""
+}
+
+func isDaemonDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+	"github.com/gorilla/websocket"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Containers created with this label serve the daemon API on a unix socket only
+	daemonSocketLabel = "daytona.daemon_socket"
+	// Directory in the container the per-sandbox socket directory is mounted at
+	daemonSocketContainerDir = "/var/run/daytona"
+	daemonSocketName         = "daemon.sock"
+	// Daemon URLs with this host suffix are dialed over the socket of the sandbox
+	daemonSocketHostSuffix = ".daemon.sock"
+)
+
+// daemonSocketDir returns the host directory holding the daemon socket of a sandbox
+func (d *DockerClient) daemonSocketDir(sandboxId string) string {
+	return filepath.Join(d.daemonSocketsDir, sandboxId)
+}
+
+// GetDaemonUrl returns the base URL of the daemon API of a sandbox. Requests to it must be sent
+// with DaemonTransport, as sandboxes using a unix socket have no reachable daemon port.
+func (d *DockerClient) GetDaemonUrl(ctx context.Context, c container.InspectResponse) (string, error) {
+	if c.Config != nil && c.Config.Labels[daemonSocketLabel] == "true" {
+		return fmt.Sprintf("http://%s%s", c.Name[strings.LastIndex(c.Name, "/")+1:], daemonSocketHostSuffix), nil
+	}
+
+	containerIP := common.GetContainerIpAddress(ctx, c)
+	if containerIP == "" {
+		return "", errors.New("sandbox IP not found? Is the sandbox started?")
+	}
+
+	return fmt.Sprintf("http://%s:2280", containerIP), nil
+}
+
+// dialDaemon dials the socket of a sandbox for daemon socket hosts and the address otherwise
+func (d *DockerClient) dialDaemon(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   daemonDialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	if sandboxId, ok := strings.CutSuffix(host, daemonSocketHostSuffix); ok {
+		if d.daemonSocketsDir == "" {
+			return nil, errors.New("sandbox daemon uses a unix socket but DAEMON_SOCKETS_DIR is not set")
+		}
+		return dialer.DialContext(ctx, "unix", filepath.Join(d.daemonSocketDir(sandboxId), daemonSocketName))
+	}
+
+	return dialer.DialContext(ctx, network, addr)
+}
+
+// DaemonTransport returns the transport for requests to daemon URLs. Requests fail with
+// ErrDaemonUnreachable while the daemon is considered unreachable.
+func (d *DockerClient) DaemonTransport() http.RoundTripper {
+	return d.daemonTransport
+}
+
+// DaemonWebsocketDialer returns a websocket dialer for daemon URLs
+func (d *DockerClient) DaemonWebsocketDialer() *websocket.Dialer {
+	return &websocket.Dialer{
+		NetDialContext:   d.dialDaemon,
+		HandshakeTimeout: websocket.DefaultDialer.HandshakeTimeout,
+	}
+}
+
+func (d *DockerClient) daemonHttpClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: d.daemonTransport,
+	}
+}
+
+// getDaemonSocketEnv returns the environment and labels that make the daemon of a new sandbox
+// listen on a unix socket instead of a port on the container network
+func (d *DockerClient) getDaemonSocketEnv() ([]string, map[string]string) {
+	if d.daemonSocketsDir == "" {
+		return nil, nil
+	}
+
+	envVars := []string{
+		"DAYTONA_DAEMON_SOCKET=" + filepath.Join(daemonSocketContainerDir, daemonSocketName),
+		"DAYTONA_DAEMON_TCP_DISABLED=true",
+	}
+	return envVars, map[string]string{daemonSocketLabel: "true"}
+}
+
+// getDaemonSocketBind creates the host directory of the daemon socket of a sandbox and returns
+// its bind, or an empty string if sandboxes use the daemon port
+func (d *DockerClient) getDaemonSocketBind(sandboxId string) (string, error) {
+	if d.daemonSocketsDir == "" {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(d.daemonSocketsDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create daemon sockets directory: %w", err)
+	}
+
+	// The daemon may run as any user of the sandbox, the parent directory keeps other host users out
+	socketDir := d.daemonSocketDir(sandboxId)
+	if err := os.MkdirAll(socketDir, 0777); err != nil {
+		return "", fmt.Errorf("failed to create daemon socket directory: %w", err)
+	}
+	if err := os.Chmod(socketDir, 0777); err != nil {
+		return "", fmt.Errorf("failed to create daemon socket directory: %w", err)
+	}
+
+	return fmt.Sprintf("%s:%s", socketDir, daemonSocketContainerDir), nil
+}
+
+// CleanupOrphanedDaemonSockets removes the socket directories of sandboxes that no longer exist
+func (d *DockerClient) CleanupOrphanedDaemonSockets(ctx context.Context) {
+	if d.daemonSocketsDir == "" {
+		return
+	}
+
+	entries, err := os.ReadDir(d.daemonSocketsDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Errorf("Failed to read daemon sockets directory: %v", err)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		_, err := d.apiClient.ContainerInspect(ctx, entry.Name())
+		if err == nil || !errdefs.IsNotFound(err) {
+			continue
+		}
+
+		if err := os.RemoveAll(d.daemonSocketDir(entry.Name())); err != nil {
+			log.Errorf("Failed to remove daemon socket directory of sandbox %s: %v", entry.Name(), err)
+		}
+	}
+}
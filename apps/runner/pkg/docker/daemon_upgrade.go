@@ -0,0 +1,229 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/common-go/pkg/timer"
+	"github.com/daytonaio/runner/internal"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/docker/docker/api/types/container"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The daemon binary mounted into the sandbox is read-only, so new versions are copied next to it
+const daemonUpgradeDir = "/usr/local/lib"
+
+type daemonUpgradeRequest struct {
+	Path                string `json:"path"`
+	Version             string `json:"version"`
+	Sha256              string `json:"sha256"`
+	DrainTimeoutSeconds int    `json:"drainTimeoutSeconds,omitempty"`
+	Force               bool   `json:"force,omitempty"`
+}
+
+type daemonUpgradeStatus struct {
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+// UpgradeDaemon replaces the daemon of a running sandbox with the daemon embedded in the runner if
+// their versions differ. The daemon drains its sessions and executes the new binary in place, so
+// the sandbox is not restarted. Returns the version of the daemon running after the upgrade.
+func (d *DockerClient) UpgradeDaemon(ctx context.Context, containerId string, upgradeDto dto.UpgradeDaemonDTO) (string, error) {
+	defer timer.Timer()()
+
+	if d.isMicroVM(containerId) {
+		return "", errMicroVMUnsupported("upgrading the daemon")
+	}
+
+	c, err := d.ContainerInspect(ctx, containerId)
+	if err != nil {
+		return "", err
+	}
+
+	if !c.State.Running {
+		return "", common_errors.NewConflictError(errors.New("sandbox is not running"))
+	}
+
+	// The new binary can't be copied into a read-only root filesystem, the sandbox gets the daemon
+	// of the runner when it restarts
+	if c.HostConfig.ReadonlyRootfs {
+		return "", common_errors.NewConflictError(errors.New("the daemon of a sandbox with a read-only root filesystem is upgraded by restarting it"))
+	}
+
+	daemonUrl, err := d.GetDaemonUrl(ctx, c)
+	if err != nil {
+		return "", err
+	}
+
+	authToken := d.GetDaemonAuthToken(ctx, c)
+
+	versionUrl, err := url.Parse(daemonUrl + "/version")
+	if err != nil {
+		return "", err
+	}
+
+	currentVersion, err := d.getDaemonVersion(ctx, versionUrl, authToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to get daemon version: %w", err)
+	}
+
+	if currentVersion == internal.Version {
+		return currentVersion, nil
+	}
+
+	log.Infof("Upgrading daemon of sandbox %s from %s to %s", containerId, currentVersion, internal.Version)
+
+	binary, err := os.ReadFile(d.daemonPath)
+	if err != nil {
+		return "", err
+	}
+
+	checksum := sha256.Sum256(binary)
+	sha := hex.EncodeToString(checksum[:])
+	binaryName := fmt.Sprintf("daytona-daemon-%s", sha[:12])
+
+	if err := d.copyBinaryToContainer(ctx, containerId, binaryName, binary); err != nil {
+		return "", fmt.Errorf("failed to copy daemon binary: %w", err)
+	}
+
+	body, err := json.Marshal(daemonUpgradeRequest{
+		Path:                filepath.Join(daemonUpgradeDir, binaryName),
+		Version:             internal.Version,
+		Sha256:              sha,
+		DrainTimeoutSeconds: upgradeDto.DrainTimeoutSec,
+		Force:               upgradeDto.Force,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	client := d.daemonHttpClient(30 * time.Second)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, daemonUrl+"/upgrade", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setDaemonAuthHeader(req, authToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("daemon rejected the upgrade with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	drainTimeout := time.Duration(upgradeDto.DrainTimeoutSec) * time.Second
+	if drainTimeout <= 0 {
+		drainTimeout = 60 * time.Second
+	}
+
+	return d.waitForDaemonUpgrade(ctx, daemonUrl, authToken, drainTimeout+time.Duration(d.daemonStartTimeoutSec)*time.Second)
+}
+
+func (d *DockerClient) copyBinaryToContainer(ctx context.Context, containerId, name string, binary []byte) error {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0755,
+		Size:    int64(len(binary)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := tw.Write(binary); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return d.apiClient.CopyToContainer(ctx, containerId, daemonUpgradeDir, &archive, container.CopyToContainerOptions{})
+}
+
+// waitForDaemonUpgrade waits until the daemon reports the new version or the upgrade fails
+func (d *DockerClient) waitForDaemonUpgrade(ctx context.Context, daemonUrl string, authToken string, timeout time.Duration) (string, error) {
+	versionUrl, err := url.Parse(daemonUrl + "/version")
+	if err != nil {
+		return "", err
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-timeoutCtx.Done():
+			return "", errors.New("timeout waiting for the daemon upgrade")
+		case <-ticker.C:
+		}
+
+		// Fails while the new daemon is starting
+		version, err := d.getDaemonVersion(ctx, versionUrl, authToken)
+		if err != nil {
+			continue
+		}
+		if version == internal.Version {
+			return version, nil
+		}
+
+		status, err := d.getDaemonUpgradeStatus(ctx, daemonUrl, authToken)
+		if err == nil && status.State == "failed" {
+			return "", fmt.Errorf("daemon upgrade failed: %s", status.Error)
+		}
+	}
+}
+
+func (d *DockerClient) getDaemonUpgradeStatus(ctx context.Context, daemonUrl string, authToken string) (*daemonUpgradeStatus, error) {
+	client := d.daemonHttpClient(1 * time.Second)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, daemonUrl+"/upgrade", nil)
+	if err != nil {
+		return nil, err
+	}
+	setDaemonAuthHeader(req, authToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var status daemonUpgradeStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
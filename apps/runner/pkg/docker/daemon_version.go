@@ -6,13 +6,12 @@ package docker
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 	"time"
-
-	"github.com/daytonaio/runner/pkg/common"
 )
 
 type daemonVersionResponse struct {
@@ -25,25 +24,27 @@ func (d *DockerClient) GetDaemonVersion(ctx context.Context, sandboxId string) (
 		return "", err
 	}
 
-	containerIP := common.GetContainerIpAddress(ctx, c)
-	if containerIP == "" {
-		return "", errors.New("sandbox IP not found? Is the sandbox started?")
+	daemonUrl, err := d.GetDaemonUrl(ctx, c)
+	if err != nil {
+		return "", err
 	}
 
-	targetUrl := fmt.Sprintf("http://%s:2280/version", containerIP)
-	target, err := url.Parse(targetUrl)
+	target, err := url.Parse(daemonUrl + "/version")
 	if err != nil {
 		return "", err
 	}
 
-	return d.getDaemonVersion(ctx, target)
+	return d.getDaemonVersion(ctx, target, d.GetDaemonAuthToken(ctx, c))
 }
 
-func (d *DockerClient) getDaemonVersion(ctx context.Context, targetUrl *url.URL) (string, error) {
-	client := http.Client{
-		Timeout: 1 * time.Second,
+func (d *DockerClient) getDaemonVersion(ctx context.Context, targetUrl *url.URL, authToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetUrl.String(), nil)
+	if err != nil {
+		return "", err
 	}
-	resp, err := client.Get(targetUrl.String())
+	setDaemonAuthHeader(req, authToken)
+
+	resp, err := d.daemonHttpClient(1 * time.Second).Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -57,3 +58,55 @@ func (d *DockerClient) getDaemonVersion(ctx context.Context, targetUrl *url.URL)
 
 	return versionResponse.Version, nil
 }
+
+type daemonHealthCheck struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type daemonHealthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]daemonHealthCheck `json:"checks"`
+}
+
+// checkDaemonHealth returns an error describing the unhealthy subsystems of the daemon. Daemons
+// without a health endpoint are considered healthy once they respond.
+func (d *DockerClient) checkDaemonHealth(ctx context.Context, targetUrl *url.URL, authToken string) error {
+	client := d.daemonHttpClient(5 * time.Second)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetUrl.String(), nil)
+	if err != nil {
+		return err
+	}
+	setDaemonAuthHeader(req, authToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	var health daemonHealthResponse
+	err = json.NewDecoder(resp.Body).Decode(&health)
+	if err != nil {
+		return fmt.Errorf("invalid daemon health response: %w", err)
+	}
+
+	if health.Status != "unhealthy" {
+		return nil
+	}
+
+	unhealthy := []string{}
+	for name, check := range health.Checks {
+		if check.Status == "unhealthy" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s: %s", name, check.Message))
+		}
+	}
+	sort.Strings(unhealthy)
+
+	return fmt.Errorf("daemon is unhealthy (%s)", strings.Join(unhealthy, "; "))
+}
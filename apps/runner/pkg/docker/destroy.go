@@ -16,7 +16,6 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
-	"github.com/daytonaio/common-go/pkg/utils"
 )
 
 func (d *DockerClient) Destroy(ctx context.Context, containerId string) error {
@@ -34,9 +33,20 @@ func (d *DockerClient) Destroy(ctx context.Context, containerId string) error {
 		backup_context.cancel()
 	}
 
+	if d.isMicroVM(containerId) {
+		return d.destroyMicroVM(ctx, containerId)
+	}
+
+	// Sidecars are in the network namespace of the sandbox container, so they are removed first
+	err := d.removeSidecars(ctx, containerId)
+	if err != nil {
+		return err
+	}
+
 	ct, err := d.ContainerInspect(ctx, containerId)
 	if err != nil {
 		if errdefs.IsNotFound(err) {
+			d.removeBucketMounts(ctx, containerId)
 			d.statesCache.SetSandboxState(ctx, containerId, enums.SandboxStateDestroyed)
 			return nil
 		}
@@ -67,11 +77,13 @@ func (d *DockerClient) Destroy(ctx context.Context, containerId string) error {
 				}
 			}()
 
+			d.removeBucketMounts(ctx, containerId)
 			d.statesCache.SetSandboxState(ctx, containerId, enums.SandboxStateDestroyed)
 			return nil
 		}
 
 		if err != nil && errdefs.IsNotFound(err) {
+			d.removeBucketMounts(ctx, containerId)
 			d.statesCache.SetSandboxState(ctx, containerId, enums.SandboxStateDestroyed)
 			return nil
 		}
@@ -81,21 +93,20 @@ func (d *DockerClient) Destroy(ctx context.Context, containerId string) error {
 	}
 
 	// Use exponential backoff helper for container removal
-	err = utils.RetryWithExponentialBackoff(
+	err = retry(
 		ctx,
 		fmt.Sprintf("remove sandbox %s", containerId),
-		utils.DEFAULT_MAX_RETRIES,
-		utils.DEFAULT_BASE_DELAY,
-		utils.DEFAULT_MAX_DELAY,
 		func() error {
 			return d.apiClient.ContainerRemove(ctx, containerId, container.RemoveOptions{
-				Force: true,
+				Force:         true,
+				RemoveVolumes: true,
 			})
 		},
 	)
 	if err != nil {
 		// Handle NotFound error case
 		if errdefs.IsNotFound(err) {
+			d.removeBucketMounts(ctx, containerId)
 			d.statesCache.SetSandboxState(ctx, containerId, enums.SandboxStateDestroyed)
 			return nil
 		}
@@ -110,6 +121,9 @@ func (d *DockerClient) Destroy(ctx context.Context, containerId string) error {
 		}
 	}()
 
+	d.provisionedSandboxes.Delete(containerId)
+	d.removeBucketMounts(ctx, containerId)
+
 	d.statesCache.SetSandboxState(ctx, containerId, enums.SandboxStateDestroyed)
 
 	return nil
@@ -126,13 +140,15 @@ func (d *DockerClient) RemoveDestroyed(ctx context.Context, containerId string)
 		return common_errors.NewBadRequestError(fmt.Errorf("sandbox %s is not in destroyed state", containerId))
 	}
 
+	err = d.removeSidecars(ctx, containerId)
+	if err != nil {
+		return err
+	}
+
 	// Use exponential backoff helper for container removal
-	err = utils.RetryWithExponentialBackoff(
+	err = retry(
 		ctx,
 		fmt.Sprintf("remove sandbox %s", containerId),
-		utils.DEFAULT_MAX_RETRIES,
-		utils.DEFAULT_BASE_DELAY,
-		utils.DEFAULT_MAX_DELAY,
 		func() error {
 			return d.apiClient.ContainerRemove(ctx, containerId, container.RemoveOptions{
 				Force: true,
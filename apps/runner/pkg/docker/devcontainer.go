@@ -0,0 +1,339 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/daytonaio/common-go/pkg/timer"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/devcontainer"
+	"github.com/docker/docker/api/types/container"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Marks a sandbox as provisioned from devcontainer.json, so the lifecycle commands run only once
+// when a create is retried
+const devcontainerAppliedPath = "/.daytona-devcontainer"
+
+// Directory registry features are copied to while they are installed
+const devcontainerFeaturesDir = "/tmp/daytona-devcontainer-features"
+
+// ApplyDevcontainer provisions a started sandbox from the devcontainer.json of a repository. It
+// installs the features, sets the remote env as the sandbox default env and runs the create
+// lifecycle commands, reporting each step to progress.
+func (d *DockerClient) ApplyDevcontainer(ctx context.Context, sandboxId, osUser string, devcontainerDto dto.DevcontainerDTO, progress func(dto.DevcontainerProgressDTO)) (*dto.DevcontainerResultDTO, error) {
+	defer timer.Timer()()
+
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = d.readContainerFile(ctx, sandboxId, devcontainerAppliedPath)
+	applied := err == nil
+
+	if devcontainerDto.Repository != nil && !applied {
+		progress(dto.DevcontainerProgressDTO{Step: "clone", Detail: devcontainerDto.Repository.Url})
+
+		cloneRequest := map[string]string{
+			"url":  devcontainerDto.Repository.Url,
+			"path": devcontainerDto.WorkspaceFolder,
+		}
+		if devcontainerDto.Repository.Branch != "" {
+			cloneRequest["branch"] = devcontainerDto.Repository.Branch
+		}
+		if devcontainerDto.Repository.Username != "" || devcontainerDto.Repository.Password != "" {
+			cloneRequest["username"] = devcontainerDto.Repository.Username
+			cloneRequest["password"] = devcontainerDto.Repository.Password
+		}
+
+		err = d.daemonRequest(ctx, c, http.MethodPost, "/git/clone", cloneRequest, nil, 10*time.Minute)
+		if err != nil {
+			return nil, fmt.Errorf("failed to clone repository: %w", err)
+		}
+	}
+
+	configPath, config, err := d.readDevcontainerConfig(ctx, sandboxId, devcontainerDto)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.DevcontainerResultDTO{
+		RemoteUser: config.RemoteUser,
+	}
+	for _, port := range config.ForwardPorts {
+		result.ForwardPorts = append(result.ForwardPorts, string(port))
+	}
+
+	if applied {
+		return result, nil
+	}
+
+	remoteUser := config.RemoteUser
+	if remoteUser == "" {
+		remoteUser = osUser
+	}
+
+	// Features are installed in the order of their IDs, dependencies between them are not resolved
+	featureIds := make([]string, 0, len(config.Features))
+	for id := range config.Features {
+		featureIds = append(featureIds, id)
+	}
+	sort.Strings(featureIds)
+
+	for i, id := range featureIds {
+		progress(dto.DevcontainerProgressDTO{Step: "features", Detail: id})
+
+		err := d.installDevcontainerFeature(ctx, sandboxId, path.Dir(configPath), fmt.Sprintf("%s/%d", devcontainerFeaturesDir, i), id, config.Features[id], osUser, remoteUser)
+		if err != nil {
+			return nil, fmt.Errorf("failed to install feature %s: %w", id, err)
+		}
+	}
+
+	var remoteEnv []string
+	if len(config.RemoteEnv) > 0 {
+		progress(dto.DevcontainerProgressDTO{Step: "remoteEnv"})
+
+		envs := map[string]string{}
+		query := url.Values{}
+		for name, value := range config.RemoteEnv {
+			if value == nil {
+				query.Add("name", name)
+				continue
+			}
+			envs[name] = *value
+			remoteEnv = append(remoteEnv, name+"="+*value)
+		}
+
+		err := d.daemonRequest(ctx, c, http.MethodPut, "/env", map[string]any{"envs": envs}, nil, 10*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set remote env: %w", err)
+		}
+		if len(query) > 0 {
+			err := d.daemonRequest(ctx, c, http.MethodDelete, "/env?"+query.Encode(), nil, nil, 10*time.Second)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unset remote env: %w", err)
+			}
+		}
+	}
+
+	lifecycleCommands := []struct {
+		step     string
+		commands devcontainer.Command
+	}{
+		{"onCreateCommand", config.OnCreateCommand},
+		{"updateContentCommand", config.UpdateContentCommand},
+		{"postCreateCommand", config.PostCreateCommand},
+	}
+	for _, lifecycle := range lifecycleCommands {
+		for _, command := range lifecycle.commands {
+			progress(dto.DevcontainerProgressDTO{Step: lifecycle.step, Detail: strings.Join(command, " ")})
+
+			err := d.execDevcontainerCommand(ctx, sandboxId, container.ExecOptions{
+				User:       remoteUser,
+				WorkingDir: devcontainerDto.WorkspaceFolder,
+				Env:        remoteEnv,
+				Cmd:        command,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%s failed: %w", lifecycle.step, err)
+			}
+		}
+	}
+
+	if err := d.execDevcontainerCommand(ctx, sandboxId, container.ExecOptions{
+		User: "root",
+		Cmd:  []string{"/bin/sh", "-c", fmt.Sprintf("touch %s && rm -rf %s", devcontainerAppliedPath, devcontainerFeaturesDir)},
+	}); err != nil {
+		log.Warnf("Failed to mark sandbox %s as provisioned: %v", sandboxId, err)
+	}
+
+	return result, nil
+}
+
+// readDevcontainerConfig reads devcontainer.json from the workspace folder and returns its path
+func (d *DockerClient) readDevcontainerConfig(ctx context.Context, sandboxId string, devcontainerDto dto.DevcontainerDTO) (string, *devcontainer.Config, error) {
+	candidates := devcontainer.DefaultConfigPaths
+	if devcontainerDto.ConfigPath != "" {
+		candidates = []string{devcontainerDto.ConfigPath}
+	}
+
+	for _, candidate := range candidates {
+		configPath := path.Join(devcontainerDto.WorkspaceFolder, candidate)
+		data, err := d.readContainerFile(ctx, sandboxId, configPath)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return "", nil, err
+		}
+
+		config, err := devcontainer.Parse(data)
+		if err != nil {
+			return "", nil, common_errors.NewBadRequestError(err)
+		}
+
+		return configPath, config, nil
+	}
+
+	return "", nil, common_errors.NewBadRequestError(fmt.Errorf("no devcontainer.json found in %s", devcontainerDto.WorkspaceFolder))
+}
+
+// installDevcontainerFeature runs the install script of a feature as root. Local features are
+// installed from their directory next to devcontainer.json, registry features are copied to dir.
+func (d *DockerClient) installDevcontainerFeature(ctx context.Context, sandboxId, configDir, dir, id string, options json.RawMessage, containerUser, remoteUser string) error {
+	var archive []byte
+	var err error
+
+	if devcontainer.IsLocalFeature(id) {
+		dir = path.Join(configDir, id)
+		archive, err = d.readContainerArchive(ctx, sandboxId, dir)
+		if err != nil {
+			return err
+		}
+	} else {
+		archive, err = devcontainer.FetchFeature(ctx, &http.Client{Timeout: 5 * time.Minute}, id)
+		if err != nil {
+			return err
+		}
+
+		if err := d.execDevcontainerCommand(ctx, sandboxId, container.ExecOptions{
+			User: "root",
+			Cmd:  []string{"mkdir", "-p", dir},
+		}); err != nil {
+			return err
+		}
+
+		if err := d.apiClient.CopyToContainer(ctx, sandboxId, dir, bytes.NewReader(archive), container.CopyToContainerOptions{}); err != nil {
+			return err
+		}
+	}
+
+	env, err := devcontainer.FeatureEnv(archive, options)
+	if err != nil {
+		return err
+	}
+	env = append(env, "_CONTAINER_USER="+containerUser, "_REMOTE_USER="+remoteUser)
+
+	return d.execDevcontainerCommand(ctx, sandboxId, container.ExecOptions{
+		User:       "root",
+		WorkingDir: dir,
+		Env:        env,
+		Cmd:        []string{"/bin/sh", "-c", "chmod +x ./install.sh && ./install.sh"},
+	})
+}
+
+func (d *DockerClient) execDevcontainerCommand(ctx context.Context, sandboxId string, execOptions container.ExecOptions) error {
+	execOptions.AttachStdout = true
+	execOptions.AttachStderr = true
+
+	result, err := d.execSync(ctx, sandboxId, execOptions, container.ExecStartOptions{})
+	if err != nil {
+		return err
+	}
+
+	if result.ExitCode != 0 {
+		output := strings.TrimSpace(result.StdErr)
+		if output == "" {
+			output = strings.TrimSpace(result.StdOut)
+		}
+		if len(output) > 1024 {
+			output = "..." + output[len(output)-1024:]
+		}
+		return fmt.Errorf("exited with code %d: %s", result.ExitCode, output)
+	}
+
+	return nil
+}
+
+// readContainerFile returns the content of a regular file in a container
+func (d *DockerClient) readContainerFile(ctx context.Context, containerId, filePath string) ([]byte, error) {
+	reader, _, err := d.apiClient.CopyFromContainer(ctx, containerId, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	tarReader := tar.NewReader(reader)
+	header, err := tarReader.Next()
+	if err != nil {
+		return nil, err
+	}
+	if header.Typeflag != tar.TypeReg {
+		return nil, fmt.Errorf("%s is not a regular file", filePath)
+	}
+
+	return io.ReadAll(io.LimitReader(tarReader, 1024*1024))
+}
+
+// readContainerArchive returns the tar of a directory in a container
+func (d *DockerClient) readContainerArchive(ctx context.Context, containerId, dir string) ([]byte, error) {
+	reader, _, err := d.apiClient.CopyFromContainer(ctx, containerId, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(io.LimitReader(reader, 64*1024*1024))
+}
+
+// daemonRequest sends a JSON request to the toolbox API of the daemon of a sandbox and decodes
+// the response into result, if set
+func (d *DockerClient) daemonRequest(ctx context.Context, c container.InspectResponse, method, path string, body any, result any, timeout time.Duration) error {
+	daemonUrl, err := d.GetDaemonUrl(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, daemonUrl+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	setDaemonAuthHeader(req, d.GetDaemonAuthToken(ctx, c))
+
+	resp, err := d.daemonHttpClient(timeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.New(strings.TrimSpace(fmt.Sprintf("daemon responded with status %d: %s", resp.StatusCode, message)))
+	}
+
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+
+	return nil
+}
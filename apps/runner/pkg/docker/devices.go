@@ -0,0 +1,97 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/docker/docker/api/types/container"
+)
+
+const (
+	kvmDevicePath = "/dev/kvm"
+	tunDevicePath = "/dev/net/tun"
+)
+
+// Kernel module parameters reporting if the KVM module lets guests run VMs of their own
+var nestedVirtualizationParameters = []string{
+	"/sys/module/kvm_intel/parameters/nested",
+	"/sys/module/kvm_amd/parameters/nested",
+}
+
+type DeviceSupport struct {
+	// The host supports KVM and sandboxes may request it
+	Kvm bool
+	// VMs started in sandboxes can run VMs of their own
+	NestedVirtualization bool
+	// The host supports TUN devices and sandboxes may request them
+	Tun bool
+}
+
+// DeviceSupport returns the devices sandboxes may request, which must be both enabled on the runner
+// and available on the host
+func (d *DockerClient) DeviceSupport() DeviceSupport {
+	support := DeviceSupport{
+		Kvm: d.kvmEnabled && isCharDevice(kvmDevicePath),
+		Tun: d.tunEnabled && isCharDevice(tunDevicePath),
+	}
+
+	if support.Kvm {
+		for _, path := range nestedVirtualizationParameters {
+			value, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			switch strings.TrimSpace(string(value)) {
+			case "Y", "1":
+				support.NestedVirtualization = true
+			}
+		}
+	}
+
+	return support
+}
+
+// getDeviceMappings validates the devices requested by a sandbox and returns their mappings
+func (d *DockerClient) getDeviceMappings(devices dto.SandboxDevicesDTO) ([]container.DeviceMapping, error) {
+	support := d.DeviceSupport()
+
+	var mappings []container.DeviceMapping
+	if devices.Kvm {
+		if !support.Kvm {
+			return nil, common_errors.NewBadRequestError(errors.New("KVM is not available on the runner"))
+		}
+		mappings = append(mappings, container.DeviceMapping{
+			PathOnHost:        kvmDevicePath,
+			PathInContainer:   kvmDevicePath,
+			CgroupPermissions: "rwm",
+		})
+	}
+
+	if devices.Tun {
+		if !support.Tun {
+			return nil, common_errors.NewBadRequestError(errors.New("TUN devices are not available on the runner"))
+		}
+		mappings = append(mappings, container.DeviceMapping{
+			PathOnHost:        tunDevicePath,
+			PathInContainer:   tunDevicePath,
+			CgroupPermissions: "rwm",
+		})
+	}
+
+	return mappings, nil
+}
+
+func isCharDevice(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+)
+
+// Path the daemon reads the dotfiles from, see the dotfiles package of the daemon
+const dotfilesSpecPath = "/.daytona-dotfiles.json"
+
+func (d *DockerClient) GetDotfilesStatus(ctx context.Context, sandboxId string) (*dto.DotfilesStatusDTO, error) {
+	c, err := d.dotfilesContainer(ctx, sandboxId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var status dto.DotfilesStatusDTO
+	err = d.daemonRequest(ctx, c, http.MethodGet, "/dotfiles", nil, &status, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dotfiles status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// ApplyDotfiles has the daemon apply the dotfiles of the sandbox again, or the given ones instead,
+// in the background. Given dotfiles can be applied to sandboxes created without any.
+func (d *DockerClient) ApplyDotfiles(ctx context.Context, sandboxId string, dotfiles *dto.DotfilesDTO) error {
+	c, err := d.dotfilesContainer(ctx, sandboxId, dotfiles == nil)
+	if err != nil {
+		return err
+	}
+
+	var body any
+	if dotfiles != nil {
+		body = dotfiles
+	}
+
+	err = d.daemonRequest(ctx, c, http.MethodPost, "/dotfiles/apply", body, nil, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to apply dotfiles: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DockerClient) dotfilesContainer(ctx context.Context, sandboxId string, requireDotfiles bool) (container.InspectResponse, error) {
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+
+	if requireDotfiles && c.Config.Labels[common.DOTFILES_LABEL] == "" {
+		return container.InspectResponse{}, common_errors.NewNotFoundError(errors.New("sandbox has no dotfiles"))
+	}
+
+	if !c.State.Running {
+		return container.InspectResponse{}, common_errors.NewBadRequestError(errors.New("sandbox is not running"))
+	}
+
+	return c, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/common"
+)
+
+// setUpstreamProxyLabels labels a container with its upstream proxy. Credentials of the proxy
+// are only kept in a label encrypted with the runner WireGuard key, so they aren't readable by
+// anyone able to inspect the container.
+func (d *DockerClient) setUpstreamProxyLabels(labels map[string]string, upstreamProxyUrl string) error {
+	proxyUrl, err := url.Parse(upstreamProxyUrl)
+	if err != nil {
+		return common_errors.NewBadRequestError(fmt.Errorf("invalid upstream proxy URL: %w", err))
+	}
+
+	if proxyUrl.User != nil {
+		if len(d.wireGuardKey) == 0 {
+			return common_errors.NewBadRequestError(errors.New("upstream proxy credentials require the WireGuard key of the runner to be configured"))
+		}
+
+		encrypted, err := common.EncryptLabel(d.wireGuardKey, []byte(upstreamProxyUrl))
+		if err != nil {
+			return err
+		}
+		labels[common.EGRESS_UPSTREAM_PROXY_ENCRYPTED_LABEL] = encrypted
+		proxyUrl.User = nil
+	}
+
+	labels[common.EGRESS_UPSTREAM_PROXY_LABEL] = proxyUrl.String()
+	return nil
+}
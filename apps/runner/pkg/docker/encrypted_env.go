@@ -0,0 +1,77 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+)
+
+// EnvEncryptionPublicKey returns the PEM encoded public key the control plane encrypts the secret
+// env of sandboxes to, or an empty string if env encryption is disabled
+func (d *DockerClient) EnvEncryptionPublicKey() (string, error) {
+	if d.envDecrypter == nil {
+		return "", nil
+	}
+
+	return d.envDecrypter.PublicKey()
+}
+
+// decryptEnv decrypts the secret env of a sandbox. The values are only ever passed on to the
+// container config, so they must not end up in errors or logs.
+func (d *DockerClient) decryptEnv(encryptedEnv dto.EncryptedEnvDTO) (map[string]string, error) {
+	if d.envDecrypter == nil {
+		return nil, common_errors.NewBadRequestError(errors.New("env encryption is not enabled on the runner"))
+	}
+
+	plaintext, err := d.envDecrypter.Decrypt(encryptedEnv.EncryptedKey, encryptedEnv.Nonce, encryptedEnv.Ciphertext)
+	if err != nil {
+		return nil, common_errors.NewBadRequestError(fmt.Errorf("invalid encrypted env: %w", err))
+	}
+
+	var env map[string]string
+	if err := json.Unmarshal(plaintext, &env); err != nil {
+		return nil, common_errors.NewBadRequestError(errors.New("invalid encrypted env: not a JSON object of strings"))
+	}
+
+	return env, nil
+}
+
+func secretEnvKeys(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
+
+// scrubSecretEnv removes the daemon auth token and the env variables listed in the secret env
+// label of a container
+func scrubSecretEnv(env []string, labels map[string]string) []string {
+	secretKeys := []string{common.DAEMON_AUTH_TOKEN_ENV}
+	if keys := labels[common.SECRET_ENV_KEYS_LABEL]; keys != "" {
+		secretKeys = append(secretKeys, strings.Split(keys, ",")...)
+	}
+
+	scrubbed := make([]string, 0, len(env))
+	for _, variable := range env {
+		key, _, _ := strings.Cut(variable, "=")
+		if slices.Contains(secretKeys, key) {
+			continue
+		}
+		scrubbed = append(scrubbed, variable)
+	}
+
+	return scrubbed
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+
+	"github.com/containerd/errdefs"
+	"github.com/daytonaio/common-go/pkg/utils"
+	"github.com/daytonaio/runner/pkg/common"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// ErrorKind classifies the errors of the Docker daemon and of DockerClient methods by how callers
+// should react to them
+type ErrorKind string
+
+const (
+	ErrorKindNotFound ErrorKind = "not_found"
+	// The request conflicts with the state of the object, e.g. a container name already in use
+	ErrorKindConflict ErrorKind = "conflict"
+	// The host lacks the capacity for the request, e.g. a storage quota is exceeded
+	ErrorKindResourceExhausted ErrorKind = "resource_exhausted"
+	// The failure may not happen again, e.g. the daemon was unavailable
+	ErrorKindRetryable ErrorKind = "retryable"
+	// The request can't succeed, e.g. an invalid image reference
+	ErrorKindFatal ErrorKind = "fatal"
+)
+
+// KindOf classifies an error. Errors that can't be classified are retryable.
+func KindOf(err error) ErrorKind {
+	var exhaustedErr *common.ResourceExhaustedError
+	var notFoundErr *common_errors.NotFoundError
+	var conflictErr *common_errors.ConflictError
+	var badRequestErr *common_errors.BadRequestError
+	var invalidBodyErr *common_errors.InvalidBodyRequestError
+	var unauthorizedErr *common_errors.UnauthorizedError
+	var forbiddenErr *common_errors.ForbiddenError
+
+	switch {
+	case errors.As(err, &exhaustedErr),
+		errdefs.IsResourceExhausted(err),
+		errors.Is(err, syscall.ENOSPC),
+		errors.Is(err, syscall.EDQUOT),
+		strings.Contains(err.Error(), "no space left on device"),
+		strings.Contains(err.Error(), "disk quota exceeded"):
+		return ErrorKindResourceExhausted
+	case errors.As(err, &notFoundErr), errdefs.IsNotFound(err):
+		return ErrorKindNotFound
+	case errors.As(err, &conflictErr), errdefs.IsConflict(err), errdefs.IsAlreadyExists(err):
+		// The daemon reports objects in transition, e.g. a removal in progress, as conflicts
+		if strings.Contains(err.Error(), "in progress") {
+			return ErrorKindRetryable
+		}
+		return ErrorKindConflict
+	case errors.As(err, &badRequestErr),
+		errors.As(err, &invalidBodyErr),
+		errors.As(err, &unauthorizedErr),
+		errors.As(err, &forbiddenErr),
+		errdefs.IsInvalidArgument(err),
+		errdefs.IsUnauthorized(err),
+		errdefs.IsPermissionDenied(err),
+		errdefs.IsNotImplemented(err),
+		errdefs.IsFailedPrecondition(err),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return ErrorKindFatal
+	default:
+		return ErrorKindRetryable
+	}
+}
+
+// IsRetryable returns whether retrying the request that failed with err may succeed
+func IsRetryable(err error) bool {
+	return err != nil && KindOf(err) == ErrorKindRetryable
+}
+
+// retry runs op with the default exponential backoff until it succeeds, the retries run out or it
+// fails with an error that isn't retryable, which is returned right away
+func retry(ctx context.Context, operationName string, op func() error) error {
+	return utils.RetryWithExponentialBackoff(
+		ctx,
+		operationName,
+		utils.DEFAULT_MAX_RETRIES,
+		utils.DEFAULT_BASE_DELAY,
+		utils.DEFAULT_MAX_DELAY,
+		func() error {
+			err := op()
+			if err != nil && !IsRetryable(err) {
+				return utils.Permanent(err)
+			}
+			return err
+		},
+	)
+}
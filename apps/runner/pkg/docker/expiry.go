@@ -0,0 +1,111 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type ExpiringSandbox struct {
+	SandboxId string
+	ExpiresAt time.Time
+	Policy    string
+	Running   bool
+}
+
+// setExpiryLabels records when a sandbox expires on its container, so that the runner enforces it
+// without the control plane, also after it restarts. Sandboxes without an expiry get empty labels,
+// which override the labels of an image committed from an expiring sandbox.
+func setExpiryLabels(labels map[string]string, expiry *dto.SandboxExpiryDTO) error {
+	if expiry == nil {
+		labels[common.EXPIRES_AT_LABEL] = ""
+		labels[common.EXPIRY_POLICY_LABEL] = ""
+		return nil
+	}
+
+	var expiresAt time.Time
+	switch {
+	case expiry.ExpiresAt != nil && expiry.TtlSeconds > 0:
+		return common_errors.NewBadRequestError(errors.New("expiry takes either expiresAt or ttlSeconds"))
+	case expiry.ExpiresAt != nil:
+		expiresAt = *expiry.ExpiresAt
+	case expiry.TtlSeconds > 0:
+		expiresAt = time.Now().Add(time.Duration(expiry.TtlSeconds) * time.Second)
+	default:
+		return common_errors.NewBadRequestError(errors.New("expiry requires expiresAt or ttlSeconds"))
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return common_errors.NewBadRequestError(errors.New("expiry must be in the future"))
+	}
+
+	policy := expiry.Policy
+	if policy == "" {
+		policy = dto.ExpiryPolicyStop
+	}
+
+	labels[common.EXPIRES_AT_LABEL] = expiresAt.UTC().Format(time.RFC3339)
+	labels[common.EXPIRY_POLICY_LABEL] = policy
+	return nil
+}
+
+// ListExpiringSandboxes returns the sandboxes that have an expiry
+func (d *DockerClient) ListExpiringSandboxes(ctx context.Context) ([]ExpiringSandbox, error) {
+	containers, err := d.apiClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", common.EXPIRES_AT_LABEL)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxes := make([]ExpiringSandbox, 0, len(containers))
+	for _, c := range containers {
+		if len(c.Names) == 0 || len(c.Names[0]) < 2 {
+			continue
+		}
+		sandboxId := c.Names[0][1:]
+
+		// Clones of expiring sandboxes have the label without a value
+		if c.Labels[common.EXPIRES_AT_LABEL] == "" {
+			continue
+		}
+
+		expiresAt, err := time.Parse(time.RFC3339, c.Labels[common.EXPIRES_AT_LABEL])
+		if err != nil {
+			log.Warnf("Sandbox %s has an invalid expiry: %v", sandboxId, err)
+			continue
+		}
+
+		sandboxes = append(sandboxes, ExpiringSandbox{
+			SandboxId: sandboxId,
+			ExpiresAt: expiresAt,
+			Policy:    c.Labels[common.EXPIRY_POLICY_LABEL],
+			Running:   c.State == "running",
+		})
+	}
+
+	return sandboxes, nil
+}
+
+// NotifyExpiryWarning tells the daemon of a sandbox that it is about to expire
+func (d *DockerClient) NotifyExpiryWarning(ctx context.Context, sandboxId string, warning dto.SandboxExpiryWarningDTO) error {
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return err
+	}
+
+	return d.daemonRequest(ctx, c, http.MethodPost, "/expiry/warning", warning, nil, 5*time.Second)
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+
+	log "github.com/sirupsen/logrus"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+const (
+	homeVolumePrefix = "daytona-home-"
+	// Home volumes are labeled with their key, the ID of the sandbox whose home they hold
+	homeVolumeKeyLabel = "daytona.home_key"
+)
+
+// Names of Docker volumes must start with an alphanumeric character
+var homeVolumeKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// homeDir returns the home directory of the OS user of a sandbox
+func homeDir(osUser string) string {
+	if osUser == "root" {
+		return "/root"
+	}
+	return "/home/" + osUser
+}
+
+func homeVolumeName(key string) string {
+	return homeVolumePrefix + key
+}
+
+// homeVolumeMount returns the mount of the named volume that backs the home directory of a sandbox.
+// The volume is created for the first sandbox with its key, starting with the home directory of
+// the snapshot, and kept when the sandbox is destroyed so that the sandbox replacing it, e.g. on a
+// newer snapshot, keeps the dotfiles, caches and credentials of the user.
+func (d *DockerClient) homeVolumeMount(ctx context.Context, sandboxDto dto.CreateSandboxDTO) (mount.Mount, error) {
+	key := sandboxDto.PersistentHome.Key
+	if key == "" {
+		key = sandboxDto.Id
+	}
+	if !homeVolumeKeyRegex.MatchString(key) {
+		return mount.Mount{}, common_errors.NewBadRequestError(fmt.Errorf("invalid home volume key %q", key))
+	}
+
+	name := homeVolumeName(key)
+
+	// Creating an existing volume returns it unchanged
+	_, err := d.apiClient.VolumeCreate(ctx, volume.CreateOptions{
+		Name:   name,
+		Driver: "local",
+		Labels: map[string]string{
+			homeVolumeKeyLabel: key,
+		},
+	})
+	if err != nil {
+		return mount.Mount{}, fmt.Errorf("failed to create home volume %s: %w", name, err)
+	}
+
+	return mount.Mount{
+		Type:   mount.TypeVolume,
+		Source: name,
+		Target: homeDir(sandboxDto.OsUser),
+	}, nil
+}
+
+// setHomeVolumeMount backs the home directory of a sandbox with its home volume, replacing the
+// anonymous volume of the home of read-only root filesystems
+func (d *DockerClient) setHomeVolumeMount(ctx context.Context, sandboxDto dto.CreateSandboxDTO, hostConfig *container.HostConfig) error {
+	if sandboxDto.PersistentHome == nil {
+		return nil
+	}
+
+	homeMount, err := d.homeVolumeMount(ctx, sandboxDto)
+	if err != nil {
+		return err
+	}
+
+	hostConfig.Mounts = slices.DeleteFunc(hostConfig.Mounts, func(m mount.Mount) bool {
+		return m.Target == homeMount.Target
+	})
+	hostConfig.Mounts = append(hostConfig.Mounts, homeMount)
+
+	return nil
+}
+
+// cloneHomeVolume gives the clone of a sandbox with a home volume its own home volume, with a copy
+// of the home of the source sandbox. Sandboxes don't share home volumes. Returns the name of the
+// home volume of the clone, empty if the source sandbox has none.
+func (d *DockerClient) cloneHomeVolume(ctx context.Context, cloneId string, hostConfig *container.HostConfig) (string, error) {
+	for i, m := range hostConfig.Mounts {
+		if m.Type != mount.TypeVolume || !strings.HasPrefix(m.Source, homeVolumePrefix) {
+			continue
+		}
+
+		source, err := d.apiClient.VolumeInspect(ctx, m.Source)
+		if err != nil {
+			return "", fmt.Errorf("failed to inspect home volume %s: %w", m.Source, err)
+		}
+
+		name := homeVolumeName(cloneId)
+		clone, err := d.apiClient.VolumeCreate(ctx, volume.CreateOptions{
+			Name:   name,
+			Driver: "local",
+			Labels: map[string]string{
+				homeVolumeKeyLabel: cloneId,
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create home volume %s: %w", name, err)
+		}
+
+		if source.Mountpoint == "" || clone.Mountpoint == "" {
+			return "", errors.New("home volumes have no mount point on the host")
+		}
+
+		err = d.copyOverlayData(ctx, source.Mountpoint, clone.Mountpoint)
+		if err != nil {
+			d.removeHomeVolume(ctx, name)
+			return "", fmt.Errorf("failed to copy home volume %s: %w", m.Source, err)
+		}
+
+		hostConfig.Mounts = slices.Clone(hostConfig.Mounts)
+		hostConfig.Mounts[i].Source = name
+		return name, nil
+	}
+
+	return "", nil
+}
+
+func (d *DockerClient) removeHomeVolume(ctx context.Context, name string) {
+	if err := d.apiClient.VolumeRemove(ctx, name, false); err != nil {
+		log.Warnf("Failed to remove home volume %s: %v", name, err)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/go-units"
+	"golang.org/x/sys/unix"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Free space left beyond the copy of a sandbox, so that recreating it doesn't fill the host
+	hostStorageReserve = 2 * 1024 * 1024 * 1024
+	// Images unused for less long aren't collected to make space, they may be about to be used
+	imageGCMinAge = time.Hour
+)
+
+// ensureCopySpace fails with a ResourceExhaustedError if the filesystem of an upper dir lacks the
+// space for a second copy of it. The check assumes a full copy, as reflinks may be unavailable. If
+// image GC is enabled, unused images are removed to make space first.
+func (d *DockerClient) ensureCopySpace(ctx context.Context, sandboxId, upperDir string) error {
+	used, _, err := dirUsage(ctx, upperDir, 0)
+	if err != nil {
+		return fmt.Errorf("failed to measure the data of sandbox %s: %w", sandboxId, err)
+	}
+	required := used + hostStorageReserve
+
+	available, err := availableBytes(upperDir)
+	if err != nil {
+		return err
+	}
+	if available >= required {
+		return nil
+	}
+
+	if d.storageRecoveryImageGC {
+		freed := d.collectImages(ctx, required-available)
+		log.Infof("Removed unused images of %s to copy the data of sandbox %s", units.HumanSize(float64(freed)), sandboxId)
+
+		available, err = availableBytes(upperDir)
+		if err != nil {
+			return err
+		}
+		if available >= required {
+			return nil
+		}
+	}
+
+	return common.NewResourceExhaustedError(
+		http.StatusInsufficientStorage,
+		"INSUFFICIENT_HOST_STORAGE",
+		fmt.Sprintf("copying the data of sandbox %s needs %s of free host storage, %s is available", sandboxId, units.HumanSize(float64(required)), units.HumanSize(float64(available))),
+		0,
+	)
+}
+
+// collectImages removes unused images, largest first, until about needed bytes are freed. Images
+// share layers, so the freed space is an estimate.
+func (d *DockerClient) collectImages(ctx context.Context, needed int64) int64 {
+	images, err := d.findOrphanedImages(ctx, imageGCMinAge)
+	if err != nil {
+		log.Warnf("Failed to list unused images: %v", err)
+		return 0
+	}
+
+	slices.SortFunc(images, func(a, b Orphan) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+
+	var freed int64
+	for _, img := range images {
+		if freed >= needed {
+			break
+		}
+		if err := img.Remove(ctx); err != nil {
+			log.Warnf("Failed to remove unused image %s: %v", img.Id, err)
+			continue
+		}
+		freed += img.Size
+	}
+
+	return freed
+}
+
+func availableBytes(path string) (int64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("failed to get free space of %s: %w", path, err)
+	}
+	return int64(stat.Bavail) * stat.Bsize, nil
+}
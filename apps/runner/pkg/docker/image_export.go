@@ -0,0 +1,49 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/daytonaio/common-go/pkg/timer"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultCompression returns the compression used when a request doesn't choose one
+func (d *DockerClient) DefaultCompression() Compression {
+	return d.compression.Algorithm
+}
+
+// ExportImage writes an image as a docker save archive compressed with the given algorithm.
+// The archive can be imported with docker load, which detects the compression.
+func (d *DockerClient) ExportImage(ctx context.Context, imageName string, compression Compression, w io.Writer) error {
+	defer timer.Timer()()
+
+	reader, err := d.apiClient.ImageSave(ctx, []string{imageName})
+	if err != nil {
+		return fmt.Errorf("failed to save image %s: %w", imageName, err)
+	}
+	defer reader.Close()
+
+	writer, err := d.newCompressWriter(ctx, w, compression)
+	if err != nil {
+		return err
+	}
+
+	written, err := io.Copy(writer, reader)
+	if err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to export image %s: %w", imageName, err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to export image %s: %w", imageName, err)
+	}
+
+	log.Infof("Exported image %s (%d bytes uncompressed, %s)", imageName, written, compression)
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"strings"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/daytonaio/runner/internal/constants"
 	"github.com/daytonaio/runner/internal/util"
 	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/faults"
 	"github.com/daytonaio/runner/pkg/models/enums"
 
 	"github.com/docker/docker/api/types/image"
@@ -26,6 +28,10 @@ import (
 func (d *DockerClient) PullImage(ctx context.Context, imageName string, reg *dto.RegistryDTO) error {
 	defer timer.Timer()()
 
+	if err := faults.Inject(ctx, faults.PointDockerPull, imageName); err != nil {
+		return err
+	}
+
 	tag := "latest"
 	lastColonIndex := strings.LastIndex(imageName, ":")
 	if lastColonIndex != -1 {
@@ -43,7 +49,11 @@ func (d *DockerClient) PullImage(ctx context.Context, imageName string, reg *dto
 		}
 	}
 
-	log.Infof("Pulling image %s...", imageName)
+	if snapshotter := d.LazyPullSnapshotter(ctx); snapshotter != "" {
+		log.Infof("Pulling image %s lazily with the %s snapshotter...", imageName, snapshotter)
+	} else {
+		log.Infof("Pulling image %s...", imageName)
+	}
 
 	sandboxIdValue := ctx.Value(constants.ID_KEY)
 
@@ -63,6 +73,10 @@ func (d *DockerClient) PullImage(ctx context.Context, imageName string, reg *dto
 
 	err = jsonmessage.DisplayJSONMessagesStream(responseBody, io.Writer(&util.DebugLogWriter{}), 0, true, nil)
 	if err != nil {
+		// Reading the progress fails with an unrelated error when the pull is canceled
+		if ctx.Err() != nil {
+			return fmt.Errorf("pull of image %s canceled: %w", imageName, context.Cause(ctx))
+		}
 		return err
 	}
 
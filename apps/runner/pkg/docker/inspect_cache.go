@@ -0,0 +1,201 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// inspectCache keeps the raw inspect responses of containers, keyed by the name or ID they were
+// inspected with. Entries are invalidated when the runner changes a container and when Docker
+// reports a change, and expire after the TTL in case an event is missed.
+type inspectCache struct {
+	ttl     time.Duration
+	mutex   sync.Mutex
+	entries map[string]inspectCacheEntry
+	// Incremented on every invalidation, responses fetched across one aren't cached
+	generation uint64
+}
+
+type inspectCacheEntry struct {
+	containerId string
+	raw         []byte
+	expiresAt   time.Time
+}
+
+func newInspectCache(ttl time.Duration) *inspectCache {
+	return &inspectCache{
+		ttl:     ttl,
+		entries: make(map[string]inspectCacheEntry),
+	}
+}
+
+func (c *inspectCache) enabled() bool {
+	return c != nil && c.ttl > 0
+}
+
+func (c *inspectCache) get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.raw, true
+}
+
+// currentGeneration returns the generation to pass to set for a response fetched after the call
+func (c *inspectCache) currentGeneration() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.generation
+}
+
+func (c *inspectCache) set(key, containerId string, raw []byte, generation uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// The container may have changed since the response was fetched
+	if generation != c.generation {
+		return
+	}
+
+	c.entries[key] = inspectCacheEntry{
+		containerId: containerId,
+		raw:         raw,
+		expiresAt:   time.Now().Add(c.ttl),
+	}
+}
+
+// invalidate removes the entries of the containers with the given names or IDs
+func (c *inspectCache) invalidate(containers ...string) {
+	if !c.enabled() {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.generation++
+	for key, entry := range c.entries {
+		for _, ctr := range containers {
+			ctr = strings.TrimPrefix(ctr, "/")
+			if ctr == "" {
+				continue
+			}
+			if key == ctr || entry.containerId == ctr || strings.HasPrefix(entry.containerId, ctr) {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+func (c *inspectCache) clear() {
+	if !c.enabled() {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.generation++
+	clear(c.entries)
+}
+
+// ClearInspectCache drops all cached inspect responses, e.g. after Docker events were missed
+func (d *DockerClient) ClearInspectCache() {
+	d.inspectCache.clear()
+}
+
+// InvalidateInspectCache drops the cached inspect response of the container of a Docker event
+func (d *DockerClient) InvalidateInspectCache(_ context.Context, event events.Message) {
+	if event.Type != events.ContainerEventType {
+		return
+	}
+
+	d.inspectCache.invalidate(event.Actor.ID, event.Actor.Attributes["name"], event.Actor.Attributes["oldName"])
+}
+
+// invalidatingAPIClient invalidates the cached inspect responses of the containers it changes, so
+// that the runner reads its own changes without waiting for their events
+type invalidatingAPIClient struct {
+	client.APIClient
+	cache *inspectCache
+}
+
+func (c *invalidatingAPIClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.CreateResponse, error) {
+	defer c.cache.invalidate(containerName)
+	return c.APIClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+}
+
+func (c *invalidatingAPIClient) ContainerStart(ctx context.Context, containerId string, options container.StartOptions) error {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.ContainerStart(ctx, containerId, options)
+}
+
+func (c *invalidatingAPIClient) ContainerStop(ctx context.Context, containerId string, options container.StopOptions) error {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.ContainerStop(ctx, containerId, options)
+}
+
+func (c *invalidatingAPIClient) ContainerRestart(ctx context.Context, containerId string, options container.StopOptions) error {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.ContainerRestart(ctx, containerId, options)
+}
+
+func (c *invalidatingAPIClient) ContainerKill(ctx context.Context, containerId, signal string) error {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.ContainerKill(ctx, containerId, signal)
+}
+
+func (c *invalidatingAPIClient) ContainerPause(ctx context.Context, containerId string) error {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.ContainerPause(ctx, containerId)
+}
+
+func (c *invalidatingAPIClient) ContainerUnpause(ctx context.Context, containerId string) error {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.ContainerUnpause(ctx, containerId)
+}
+
+func (c *invalidatingAPIClient) ContainerRename(ctx context.Context, containerId, newContainerName string) error {
+	defer c.cache.invalidate(containerId, newContainerName)
+	return c.APIClient.ContainerRename(ctx, containerId, newContainerName)
+}
+
+func (c *invalidatingAPIClient) ContainerUpdate(ctx context.Context, containerId string, updateConfig container.UpdateConfig) (container.UpdateResponse, error) {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.ContainerUpdate(ctx, containerId, updateConfig)
+}
+
+func (c *invalidatingAPIClient) ContainerRemove(ctx context.Context, containerId string, options container.RemoveOptions) error {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.ContainerRemove(ctx, containerId, options)
+}
+
+func (c *invalidatingAPIClient) NetworkConnect(ctx context.Context, networkId, containerId string, config *network.EndpointSettings) error {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.NetworkConnect(ctx, networkId, containerId, config)
+}
+
+func (c *invalidatingAPIClient) NetworkDisconnect(ctx context.Context, networkId, containerId string, force bool) error {
+	defer c.cache.invalidate(containerId)
+	return c.APIClient.NetworkDisconnect(ctx, networkId, containerId, force)
+}
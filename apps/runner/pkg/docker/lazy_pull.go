@@ -0,0 +1,54 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Snapshotters of the containerd image store that fetch layer contents on demand. Pulls through
+// them only fetch the manifests and the eStargz TOCs or SOCI indexes, so sandboxes start before
+// the image is downloaded. Layers without an index are fetched in full by the snapshotter.
+var lazyPullSnapshotters = []string{"stargz", "soci"}
+
+const containerdSnapshotterDriverType = "io.containerd.snapshotter.v1"
+
+// LazyPullSnapshotter returns the snapshotter images are lazily pulled with, or an empty string
+// if lazy pulling is disabled or the Docker daemon doesn't use a lazy pulling snapshotter, in
+// which case images are pulled in full
+func (d *DockerClient) LazyPullSnapshotter(ctx context.Context) string {
+	if !d.lazyPullEnabled {
+		return ""
+	}
+
+	d.lazyPullMutex.Lock()
+	defer d.lazyPullMutex.Unlock()
+
+	if d.lazyPullChecked {
+		return d.lazyPullSnapshotter
+	}
+
+	info, err := d.apiClient.Info(ctx)
+	if err != nil {
+		log.Warnf("Failed to check the snapshotter of the Docker daemon: %v", err)
+		return ""
+	}
+
+	containerdStore := slices.ContainsFunc(info.DriverStatus, func(status [2]string) bool {
+		return status[0] == "driver-type" && status[1] == containerdSnapshotterDriverType
+	})
+
+	if containerdStore && slices.Contains(lazyPullSnapshotters, info.Driver) {
+		d.lazyPullSnapshotter = info.Driver
+		log.Infof("Lazy pulling images with the %s snapshotter", info.Driver)
+	} else {
+		log.Warnf("Lazy pulling is enabled but the Docker daemon uses the %s storage driver, pulling images in full", info.Driver)
+	}
+
+	d.lazyPullChecked = true
+	return d.lazyPullSnapshotter
+}
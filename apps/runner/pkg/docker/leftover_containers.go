@@ -0,0 +1,189 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/models/enums"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Matches the names ContainerDiskResize renames the original container of a sandbox to
+var leftoverContainerRegex = regexp.MustCompile(`^(.+)-(recovery|resize)-(\d+)$`)
+
+// How a leftover container was resolved
+const (
+	// The sandbox container was missing, the leftover was renamed back to it
+	LeftoverResolutionRestored = "restored"
+	// The sandbox container was never started, the data was copied to it before removing the leftover
+	LeftoverResolutionCopied = "copied"
+	// The sandbox container was started since and has all data of the leftover
+	LeftoverResolutionRemoved = "removed"
+	// The sandbox container is unhealthy or lacks data of the leftover, which is kept for manual recovery
+	LeftoverResolutionKept = "kept"
+)
+
+// LeftoverContainer is the original container of a sandbox that a failed disk resize or storage
+// recovery left behind
+type LeftoverContainer struct {
+	Name      string
+	SandboxId string
+	Operation string
+	RenamedAt time.Time
+}
+
+// FindLeftoverContainers returns the original containers disk resizes and storage recoveries
+// failed to restore or remove
+func (d *DockerClient) FindLeftoverContainers(ctx context.Context) ([]LeftoverContainer, error) {
+	// Leftovers are found by the name they were renamed to, as containers of older sandboxes carry
+	// no label marking them. Docker matches name filters as unanchored regular expressions.
+	containers, err := d.apiClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("name", "-resize-"), filters.Arg("name", "-recovery-")),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []LeftoverContainer
+	for _, c := range containers {
+		if len(c.Names) == 0 || len(c.Names[0]) < 2 {
+			continue
+		}
+
+		leftover, ok := parseLeftoverContainerName(c.Names[0][1:])
+		if ok {
+			leftovers = append(leftovers, leftover)
+		}
+	}
+
+	return leftovers, nil
+}
+
+// ResolveLeftoverContainer restores the sandbox from a leftover container if its container is
+// missing, and otherwise removes the leftover once the data of the sandbox is safe
+func (d *DockerClient) ResolveLeftoverContainer(ctx context.Context, leftover LeftoverContainer) (string, error) {
+	sandbox, err := d.apiClient.ContainerInspect(ctx, leftover.SandboxId)
+	if err != nil {
+		if !errdefs.IsNotFound(err) {
+			return "", fmt.Errorf("failed to inspect sandbox container: %w", err)
+		}
+
+		// The sandbox container wasn't recreated, the leftover still is the sandbox
+		err = d.apiClient.ContainerRename(ctx, leftover.Name, leftover.SandboxId)
+		if err != nil {
+			return "", fmt.Errorf("failed to rename %s back: %w", leftover.Name, err)
+		}
+		d.statesCache.SetSandboxState(ctx, leftover.SandboxId, enums.SandboxStateStopped)
+		return LeftoverResolutionRestored, nil
+	}
+
+	if sandbox.State == nil || sandbox.State.Dead || sandbox.State.Restarting || sandbox.State.Error != "" {
+		return LeftoverResolutionKept, nil
+	}
+
+	// Data written since the sandbox was started must not be overwritten with the older data of the
+	// leftover, a sandbox that was never started may be missing the data the operation failed to copy
+	startedAt, _ := time.Parse(time.RFC3339Nano, sandbox.State.StartedAt)
+
+	original, err := d.apiClient.ContainerInspect(ctx, leftover.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect %s: %w", leftover.Name, err)
+	}
+
+	if startedAt.Year() > 1 {
+		// The operation may have been interrupted before the copy completed, the leftover then
+		// holds the only complete data of the sandbox
+		if !d.isLeftoverCopied(ctx, original, sandbox) {
+			return LeftoverResolutionKept, nil
+		}
+
+		err = d.removeLeftoverContainer(ctx, leftover)
+		if err != nil {
+			return "", err
+		}
+		return LeftoverResolutionRemoved, nil
+	}
+
+	if upperDir, ok := original.GraphDriver.Data["UpperDir"]; ok && original.GraphDriver.Name == "overlay2" {
+		err = d.copyContainerOverlayData(ctx, upperDir, leftover.SandboxId)
+		if err != nil {
+			return "", fmt.Errorf("failed to copy data of %s: %w", leftover.Name, err)
+		}
+	}
+
+	err = d.removeLeftoverContainer(ctx, leftover)
+	if err != nil {
+		return "", err
+	}
+	return LeftoverResolutionCopied, nil
+}
+
+// isLeftoverCopied returns whether the data of a leftover container was copied to the recreated
+// sandbox container, false if that can't be verified
+func (d *DockerClient) isLeftoverCopied(ctx context.Context, original, sandbox container.InspectResponse) bool {
+	if original.GraphDriver.Name != "overlay2" || sandbox.GraphDriver.Name != "overlay2" {
+		return false
+	}
+	originalUpperDir, ok := original.GraphDriver.Data["UpperDir"]
+	if !ok {
+		return false
+	}
+	sandboxUpperDir, ok := sandbox.GraphDriver.Data["UpperDir"]
+	if !ok {
+		return false
+	}
+
+	// The copy is verified even if verification after copies is disabled
+	mode := d.copyConfig.Verify
+	if mode == "" || mode == common.VerifyNone {
+		mode = common.VerifySampled
+	}
+
+	err := common.VerifyCopy(ctx, originalUpperDir, sandboxUpperDir, mode)
+	if err != nil {
+		log.Warnf("Keeping leftover container %s, its data differs from sandbox %s: %v", original.Name, sandbox.Name, err)
+		return false
+	}
+	return true
+}
+
+func (d *DockerClient) removeLeftoverContainer(ctx context.Context, leftover LeftoverContainer) error {
+	err := d.apiClient.ContainerRemove(ctx, leftover.Name, container.RemoveOptions{Force: true})
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to remove %s: %w", leftover.Name, err)
+	}
+
+	log.Infof("Removed leftover container %s of sandbox %s", leftover.Name, leftover.SandboxId)
+	return nil
+}
+
+func parseLeftoverContainerName(name string) (LeftoverContainer, bool) {
+	matches := leftoverContainerRegex.FindStringSubmatch(name)
+	if matches == nil {
+		return LeftoverContainer{}, false
+	}
+
+	timestamp, err := strconv.ParseInt(matches[3], 10, 64)
+	if err != nil {
+		return LeftoverContainer{}, false
+	}
+
+	return LeftoverContainer{
+		Name:      name,
+		SandboxId: matches[1],
+		Operation: matches[2],
+		RenamedAt: time.Unix(timestamp, 0),
+	}, true
+}
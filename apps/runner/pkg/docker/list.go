@@ -0,0 +1,92 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ListSandboxes returns the sandbox containers on the runner with their state, oldest first. Only
+// the sandboxes with all of the given labels are listed, to avoid deducing the state of the others.
+// MicroVM sandboxes aren't listed.
+func (d *DockerClient) ListSandboxes(ctx context.Context, labels map[string]string) ([]dto.SandboxSummaryDTO, error) {
+	containers, err := d.listSandboxContainers(ctx, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxes := make([]dto.SandboxSummaryDTO, 0, len(containers))
+	for i := len(containers) - 1; i >= 0; i-- {
+		c := containers[i]
+		if len(c.Names) == 0 || len(c.Names[0]) < 2 {
+			continue
+		}
+		sandboxId := c.Names[0][1:]
+
+		state, err := d.DeduceSandboxState(ctx, sandboxId)
+		if err != nil {
+			log.Debugf("Failed to deduce state for sandbox %s: %v", sandboxId, err)
+		}
+
+		storageQuota, _ := strconv.ParseInt(c.Labels[storageQuotaLabel], 10, 64)
+
+		sandboxes = append(sandboxes, dto.SandboxSummaryDTO{
+			Id:           sandboxId,
+			State:        string(state),
+			Snapshot:     c.Image,
+			StorageQuota: storageQuota,
+			CreatedAt:    time.Unix(c.Created, 0),
+		})
+	}
+
+	return sandboxes, nil
+}
+
+// listSandboxContainers returns the sandbox containers with all of the given labels, including the
+// original containers resizes left behind
+func (d *DockerClient) listSandboxContainers(ctx context.Context, labels map[string]string) ([]container.Summary, error) {
+	args := filters.NewArgs()
+	for key, value := range labels {
+		args.Add("label", key+"="+value)
+	}
+
+	containers, err := d.apiClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(containers, func(c container.Summary) bool {
+		return !isSandboxContainer(c.Labels, c.Mounts)
+	}), nil
+}
+
+// isSandboxContainer tells the containers of sandboxes apart from sidecars and other containers on
+// the runner. Sandboxes created before they were labeled are recognized by the daemon binary that
+// is mounted into every sandbox.
+func isSandboxContainer(labels map[string]string, mounts []container.MountPoint) bool {
+	if labels[common.SANDBOX_LABEL] != "" {
+		return true
+	}
+
+	if labels[common.SIDECAR_OF_LABEL] != "" {
+		return false
+	}
+
+	return slices.ContainsFunc(mounts, func(m container.MountPoint) bool {
+		return m.Destination == common.DAEMON_PATH
+	})
+}
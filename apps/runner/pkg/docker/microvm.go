@@ -0,0 +1,202 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/firecracker"
+	"github.com/daytonaio/runner/pkg/models/enums"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// isMicroVM returns whether a sandbox runs as a Firecracker microVM rather than a container
+func (d *DockerClient) isMicroVM(sandboxId string) bool {
+	return d.microVMs != nil && d.microVMs.Exists(sandboxId)
+}
+
+// errMicroVMUnsupported is returned by the operations microVMs don't support
+func errMicroVMUnsupported(operation string) error {
+	return common_errors.NewBadRequestError(fmt.Errorf("%s is not supported for microVM sandboxes", operation))
+}
+
+// validateMicroVM rejects the create options microVMs don't support
+func (d *DockerClient) validateMicroVM(sandboxDto dto.CreateSandboxDTO) error {
+	if d.microVMs == nil {
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes are not enabled on the runner"))
+	}
+
+	switch {
+	case len(sandboxDto.Volumes) > 0:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support volumes"))
+	case sandboxDto.CpuPinning:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support CPU pinning"))
+	case sandboxDto.Devices != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support device passthrough"))
+	case sandboxDto.EgressProxy != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support the egress proxy"))
+	case sandboxDto.WireGuard != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support WireGuard tunnels"))
+	case len(sandboxDto.Sidecars) > 0:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support sidecars"))
+	case sandboxDto.NestedDocker != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support nested Docker"))
+	case sandboxDto.Devcontainer != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support devcontainer provisioning"))
+	case sandboxDto.Boot != nil && sandboxDto.Boot.User != "":
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support overriding the user"))
+	case sandboxDto.ReadOnlyRootfs != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support a read-only root filesystem"))
+	case sandboxDto.Provisioning != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support provisioning steps"))
+	case sandboxDto.Expiry != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support an expiry"))
+	case len(sandboxDto.BucketMounts) > 0:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support bucket mounts"))
+	case sandboxDto.PersistentHome != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support a persistent home"))
+	case sandboxDto.Dotfiles != nil:
+		return common_errors.NewBadRequestError(errors.New("microVM sandboxes don't support dotfiles"))
+	}
+
+	return nil
+}
+
+// createMicroVM creates and starts a sandbox as a microVM, with the same env, entrypoint and
+// network rules it would have as a container
+func (d *DockerClient) createMicroVM(ctx context.Context, sandboxDto dto.CreateSandboxDTO) (string, string, error) {
+	daemonAuthToken, err := generateDaemonAuthToken()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate daemon auth token: %w", err)
+	}
+
+	containerConfig, err := d.getContainerCreateConfig(ctx, sandboxDto, daemonAuthToken)
+	if err != nil {
+		return "", "", err
+	}
+	// The daemon of a microVM can only be reached over its network
+	socketEnvVars, _ := d.getDaemonSocketEnv()
+	containerConfig.Env = slices.DeleteFunc(containerConfig.Env, func(env string) bool {
+		return slices.Contains(socketEnvVars, env)
+	})
+	delete(containerConfig.Labels, daemonSocketLabel)
+
+	err = d.microVMs.Create(ctx, firecracker.CreateOptions{
+		SandboxId: sandboxDto.Id,
+		Config:    containerConfig,
+		Cpus:      sandboxDto.CpuQuota,
+		MemoryGB:  sandboxDto.MemoryQuota,
+		StorageGB: sandboxDto.StorageQuota,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	d.statesCache.SetDaemonAuthToken(ctx, sandboxDto.Id, daemonAuthToken)
+
+	daemonVersion, err := d.Start(ctx, sandboxDto.Id, sandboxDto.Metadata)
+	if err != nil {
+		return "", "", err
+	}
+
+	vm, err := d.microVMs.Inspect(sandboxDto.Id)
+	if err != nil {
+		return "", "", err
+	}
+	ip := common.GetContainerIpAddress(ctx, vm)
+
+	if sandboxDto.NetworkBlockAll != nil && *sandboxDto.NetworkBlockAll {
+		err = d.netRulesManager.SetNetworkRules(vm.ID[:12], ip, "")
+	} else if sandboxDto.NetworkAllowList != nil && *sandboxDto.NetworkAllowList != "" {
+		err = d.netRulesManager.SetNetworkRules(vm.ID[:12], ip, *sandboxDto.NetworkAllowList)
+	}
+	if err != nil {
+		log.Errorf("Failed to update sandbox network settings: %v", err)
+	}
+
+	return vm.ID, daemonVersion, nil
+}
+
+func (d *DockerClient) startMicroVM(ctx context.Context, sandboxId string, metadata map[string]string) (string, error) {
+	if err := d.microVMs.Start(ctx, sandboxId); err != nil {
+		return "", err
+	}
+
+	vm, err := d.microVMs.Inspect(sandboxId)
+	if err != nil {
+		return "", err
+	}
+
+	// Docker events assign the network rules of containers, there are none for microVMs
+	ip := common.GetContainerIpAddress(ctx, vm)
+	if err := d.netRulesManager.AssignNetworkRules(vm.ID[:12], ip); err != nil {
+		log.Errorf("Error assigning network rules: %v", err)
+	}
+
+	if metadata["limitNetworkEgress"] == "true" {
+		if err := d.netRulesManager.SetNetworkLimiter(vm.ID[:12], ip); err != nil {
+			log.Errorf("Failed to set network limiter: %v", err)
+		}
+	}
+
+	daemonUrl, err := d.GetDaemonUrl(ctx, vm)
+	if err != nil {
+		return "", err
+	}
+
+	daemonVersion, err := d.waitForDaemonRunning(ctx, daemonUrl, d.GetDaemonAuthToken(ctx, vm))
+	if err != nil {
+		return "", err
+	}
+
+	d.statesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateStarted)
+	return daemonVersion, nil
+}
+
+func (d *DockerClient) stopMicroVM(ctx context.Context, sandboxId string) error {
+	d.statesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateStopping)
+
+	vm, err := d.microVMs.Inspect(sandboxId)
+	if err != nil {
+		return err
+	}
+
+	if err := d.microVMs.Stop(ctx, sandboxId); err != nil {
+		return err
+	}
+
+	if err := d.netRulesManager.UnassignNetworkRules(vm.ID[:12]); err != nil {
+		log.Errorf("Error unassigning network rules: %v", err)
+	}
+
+	d.statesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateStopped)
+	return nil
+}
+
+func (d *DockerClient) destroyMicroVM(ctx context.Context, sandboxId string) error {
+	d.statesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateDestroying)
+
+	vm, err := d.microVMs.Inspect(sandboxId)
+	if err != nil {
+		return err
+	}
+
+	if err := d.microVMs.Destroy(ctx, sandboxId); err != nil {
+		return err
+	}
+
+	if err := d.netRulesManager.DeleteNetworkRules(vm.ID[:12]); err != nil {
+		log.Errorf("Failed to delete sandbox network settings: %v", err)
+	}
+
+	d.statesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateDestroyed)
+	return nil
+}
@@ -5,20 +5,36 @@ package docker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/daytonaio/runner/pkg/common"
 	"github.com/daytonaio/runner/pkg/netrules"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	monitorMinBackoff = 1 * time.Second
+	monitorMaxBackoff = 30 * time.Second
+	// Streams that stayed open this long reconnect without backoff
+	monitorStableStream = 1 * time.Minute
+	// Events of longer outages may have been dropped by Docker, which keeps a limited backlog
+	monitorMaxReplayGap = 5 * time.Minute
+	// How long the events stream may be down before the monitor reports itself unhealthy
+	monitorUnhealthyAfter = 30 * time.Second
+)
+
 type MonitorOptions struct {
-	OnDestroyEvent func(ctx context.Context)
+	// Called after a full reconciliation, when events may have been missed
+	OnResync func(ctx context.Context)
 }
 
 type DockerMonitor struct {
@@ -27,24 +43,64 @@ type DockerMonitor struct {
 	cancel          context.CancelFunc
 	netRulesManager *netrules.NetRulesManager
 	opts            MonitorOptions
+
+	mutex sync.Mutex
+	// Time of the last handled event, events up to it aren't handled again on replay
+	lastEventNano int64
+	// When the events stream went down, zero while it is open
+	disconnectedAt time.Time
+	lastError      error
+	// Whether Docker was unreachable since the stream went down
+	unreachable bool
+	subscribers []*subscriber
 }
 
 func NewDockerMonitor(apiClient client.APIClient, netRulesManager *netrules.NetRulesManager, opts MonitorOptions) *DockerMonitor {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &DockerMonitor{
+	dm := &DockerMonitor{
 		apiClient:       apiClient,
 		ctx:             ctx,
 		cancel:          cancel,
 		netRulesManager: netRulesManager,
 		opts:            opts,
+		// Down until the stream is first opened
+		disconnectedAt: time.Now(),
 	}
+
+	dm.Subscribe(Subscription{
+		Name:    "netrules",
+		Actions: []events.Action{events.ActionStart, events.ActionStop, events.ActionKill, events.ActionDestroy},
+		Handler: dm.handleContainerEvent,
+		Workers: 4,
+	})
+
+	return dm
 }
 
 func (dm *DockerMonitor) Stop() {
 	dm.cancel()
 }
 
+// Healthy returns an error if the events stream has been down for long, during which containers
+// started or destroyed don't get their network rules set up or removed
+func (dm *DockerMonitor) Healthy() error {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	if dm.disconnectedAt.IsZero() || time.Since(dm.disconnectedAt) < monitorUnhealthyAfter {
+		return nil
+	}
+	if dm.lastError != nil {
+		return fmt.Errorf("docker events stream down since %s: %w", dm.disconnectedAt.Format(time.RFC3339), dm.lastError)
+	}
+	return fmt.Errorf("docker events stream down since %s", dm.disconnectedAt.Format(time.RFC3339))
+}
+
+// Start monitors Docker events until the monitor is stopped. The events stream is reopened
+// whenever it ends, replaying the events since the last handled one. Outages long enough for
+// Docker to have dropped events, or during which Docker was unreachable, e.g. because it
+// restarted, are followed by a full reconciliation.
 func (dm *DockerMonitor) Start() error {
 
 	log.Info("Starting Docker monitor")
@@ -52,29 +108,67 @@ func (dm *DockerMonitor) Start() error {
 	// Start periodic reconciliation
 	go dm.reconcilerLoop()
 
+	backoff := monitorMinBackoff
+
 	// Main monitoring loop
 	for {
+		openedAt := time.Now()
+		err := dm.monitorEvents()
+		if dm.ctx.Err() != nil {
+			log.Info("Context cancelled, stopping monitor...")
+			return dm.ctx.Err()
+		}
+
+		dm.setDisconnected(err)
+
+		if time.Since(openedAt) > monitorStableStream {
+			backoff = monitorMinBackoff
+		}
+		if isConnectionError(err) {
+			log.Warnf("Events stream ended: %v", err)
+		} else {
+			log.Errorf("Events stream failed: %v", err)
+		}
+
+		log.Infof("Reopening events stream in %s...", backoff)
 		select {
+		case <-time.After(backoff):
 		case <-dm.ctx.Done():
 			log.Info("Context cancelled, stopping monitor...")
 			return dm.ctx.Err()
-
-		default:
-			if err := dm.monitorEvents(); err != nil {
-				if isConnectionError(err) {
-					log.Warnf("Events stream ended: %v", err)
-					log.Info("Reopening events stream in 2 seconds...")
-					time.Sleep(2 * time.Second)
-					continue
-				} else {
-					log.Errorf("Fatal error in monitoring: %v", err)
-					return err
-				}
-			}
 		}
+		backoff = min(backoff*2, monitorMaxBackoff)
 	}
 }
 
+func (dm *DockerMonitor) setDisconnected(err error) {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	if dm.disconnectedAt.IsZero() {
+		dm.disconnectedAt = time.Now()
+	}
+	dm.lastError = err
+	// The stream ends with EOF when Docker stops, failing to reconnect means it was down
+	if isConnectionError(err) && !errors.Is(err, io.EOF) {
+		dm.unreachable = true
+	}
+}
+
+// setConnected marks the stream as open. It returns whether events may have been missed since it
+// went down, and the time of the last handled event.
+func (dm *DockerMonitor) setConnected() (bool, time.Time, int64) {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	gap := dm.unreachable || time.Since(dm.disconnectedAt) > monitorMaxReplayGap
+	disconnectedAt := dm.disconnectedAt
+	dm.disconnectedAt = time.Time{}
+	dm.lastError = nil
+	dm.unreachable = false
+	return gap, disconnectedAt, dm.lastEventNano
+}
+
 // isConnectionError checks if the error is related to connection loss
 func isConnectionError(err error) bool {
 	if err == nil {
@@ -97,31 +191,58 @@ func isConnectionError(err error) bool {
 		strings.Contains(errStr, "Cannot connect to the Docker daemon")
 }
 
-// monitorEvents handles the actual event monitoring with proper error handling
+// monitorEvents handles the actual event monitoring with proper error handling. Events since the
+// last handled one are replayed, a full reconciliation is run instead if some may have been lost.
 func (dm *DockerMonitor) monitorEvents() error {
-	// Create event filters to monitor only container create and stop events
+	// Docker must be reachable before the stream is considered open
+	_, err := dm.apiClient.Ping(dm.ctx)
+	if err != nil {
+		return err
+	}
+
+	gap, disconnectedAt, lastEventNano := dm.setConnected()
+
+	// Create event filters to monitor only container lifecycle events
 	eventFilters := events.ListOptions{
 		Filters: filters.NewArgs(
 			filters.Arg("type", "container"),
 			filters.Arg("event", "start"),
 			filters.Arg("event", "stop"),
 			filters.Arg("event", "kill"),
+			filters.Arg("event", "die"),
 			filters.Arg("event", "destroy"),
+			filters.Arg("event", "rename"),
+			filters.Arg("event", "update"),
+			filters.Arg("event", "pause"),
+			filters.Arg("event", "unpause"),
 		),
 	}
 
+	if lastEventNano > 0 && !gap {
+		eventFilters.Since = fmt.Sprintf("%d.%09d", lastEventNano/int64(time.Second), lastEventNano%int64(time.Second))
+		log.Infof("Replaying Docker events since %s", time.Unix(0, lastEventNano).Format(time.RFC3339Nano))
+	}
+
 	// Start listening for events
 	eventsChan, errsChan := dm.apiClient.Events(dm.ctx, eventFilters)
 
 	// Reconnection established successfully
-	dm.reconcileNetworkRules("filter", "DOCKER-USER")
-	dm.reconcileNetworkRules("mangle", "PREROUTING")
+	if lastEventNano > 0 && gap {
+		log.Warnf("Docker events may have been missed since %s, reconciling", disconnectedAt.Format(time.RFC3339))
+		dm.resync()
+	} else {
+		dm.reconcileNetworkRules("filter", "DOCKER-USER")
+		dm.reconcileNetworkRules("mangle", "PREROUTING")
+	}
 
 	for {
 		select {
 		case event := <-eventsChan:
 			log.Debug("Received event", event)
-			dm.handleContainerEvent(event)
+			if !dm.markHandled(event) {
+				continue
+			}
+			dm.dispatch(event)
 
 		case err := <-errsChan:
 			if err != nil {
@@ -135,19 +256,65 @@ func (dm *DockerMonitor) monitorEvents() error {
 	}
 }
 
-func (dm *DockerMonitor) handleContainerEvent(event events.Message) {
+// markHandled records the time of an event, returning false for events handled before a replay
+func (dm *DockerMonitor) markHandled(event events.Message) bool {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	if event.TimeNano != 0 && event.TimeNano <= dm.lastEventNano {
+		return false
+	}
+	dm.lastEventNano = max(dm.lastEventNano, event.TimeNano)
+	return true
+}
+
+// resync reconciles everything that depends on events after some may have been missed: the network
+// rules of the running containers and the chains of removed ones are set up and removed again
+func (dm *DockerMonitor) resync() {
+	containers, err := dm.apiClient.ContainerList(dm.ctx, container.ListOptions{})
+	if err != nil {
+		log.Errorf("Error listing running containers: %v", err)
+	}
+
+	for _, c := range containers {
+		ct, err := dm.apiClient.ContainerInspect(dm.ctx, c.ID)
+		if err != nil {
+			log.Errorf("Error inspecting container %s: %v", c.ID, err)
+			continue
+		}
+		err = dm.netRulesManager.AssignNetworkRules(c.ID[:12], common.GetContainerIpAddress(dm.ctx, ct))
+		if err != nil {
+			log.Errorf("Error assigning network rules of container %s: %v", c.ID, err)
+		}
+	}
+
+	dm.reconcileNetworkRules("filter", "DOCKER-USER")
+	dm.reconcileNetworkRules("mangle", "PREROUTING")
+	dm.reconcileChains("filter")
+	dm.reconcileChains("mangle")
+
+	if dm.opts.OnResync != nil {
+		dm.opts.OnResync(dm.ctx)
+	}
+
+	log.Info("Reconciled state after missed Docker events")
+}
+
+// handleContainerEvent sets up the network rules of started containers and removes those of
+// stopped and destroyed ones
+func (dm *DockerMonitor) handleContainerEvent(ctx context.Context, event events.Message) {
 	containerID := event.Actor.ID
 	action := event.Action
 
 	switch action {
 	case "start":
-		ct, err := dm.apiClient.ContainerInspect(dm.ctx, containerID)
+		ct, err := dm.apiClient.ContainerInspect(ctx, containerID)
 		if err != nil {
 			log.Errorf("Error inspecting container: %v", err)
 			return
 		}
 		shortContainerID := containerID[:12]
-		err = dm.netRulesManager.AssignNetworkRules(shortContainerID, common.GetContainerIpAddress(dm.ctx, ct))
+		err = dm.netRulesManager.AssignNetworkRules(shortContainerID, common.GetContainerIpAddress(ctx, ct))
 		if err != nil {
 			log.Errorf("Error assigning network rules: %v", err)
 		}
@@ -162,14 +329,35 @@ func (dm *DockerMonitor) handleContainerEvent(event events.Message) {
 		if err != nil {
 			log.Errorf("Error removing network limiter: %v", err)
 		}
+		err = dm.netRulesManager.RemoveEgressProxyRedirect(shortContainerID)
+		if err != nil {
+			log.Errorf("Error removing egress proxy redirect: %v", err)
+		}
+		err = dm.netRulesManager.RemoveWireGuardTunnel(shortContainerID)
+		if err != nil {
+			log.Errorf("Error removing WireGuard tunnel: %v", err)
+		}
+		err = dm.netRulesManager.RemoveEgressIp(shortContainerID)
+		if err != nil {
+			log.Errorf("Error removing egress IP: %v", err)
+		}
 	case "destroy":
 		shortContainerID := containerID[:12]
 		err := dm.netRulesManager.DeleteNetworkRules(shortContainerID)
 		if err != nil {
 			log.Errorf("Error deleting network rules: %v", err)
 		}
-		if dm.opts.OnDestroyEvent != nil {
-			go dm.opts.OnDestroyEvent(dm.ctx)
+		err = dm.netRulesManager.RemoveEgressProxyRedirect(shortContainerID)
+		if err != nil {
+			log.Errorf("Error removing egress proxy redirect: %v", err)
+		}
+		err = dm.netRulesManager.RemoveWireGuardTunnel(shortContainerID)
+		if err != nil {
+			log.Errorf("Error removing WireGuard tunnel: %v", err)
+		}
+		err = dm.netRulesManager.RemoveEgressIp(shortContainerID)
+		if err != nil {
+			log.Errorf("Error removing egress IP: %v", err)
 		}
 	}
 }
@@ -0,0 +1,116 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"hash/fnv"
+	"runtime/debug"
+	"slices"
+
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/events"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultSubscriptionQueueSize = 1000
+
+// EventHandler handles a container event the monitor received
+type EventHandler func(ctx context.Context, event events.Message)
+
+// Subscription registers a handler for container events with the monitor. Each subscription has
+// its own workers, so a slow or panicking handler doesn't hold up the others or the events stream.
+type Subscription struct {
+	// Name of the subscription in logs and metrics
+	Name string
+	// Actions of the events to handle, all actions the monitor listens to if empty
+	Actions []events.Action
+	Handler EventHandler
+	// Number of events handled concurrently, 1 if 0. The events of a container are always
+	// handled in order, by the same worker.
+	Workers int
+	// Events each worker queues before dropping new ones, 1000 if 0
+	QueueSize int
+}
+
+type subscriber struct {
+	Subscription
+	queues []chan events.Message
+}
+
+// Subscribe starts the workers of a subscription, which handle events until the monitor is stopped
+func (dm *DockerMonitor) Subscribe(subscription Subscription) {
+	if subscription.Workers <= 0 {
+		subscription.Workers = 1
+	}
+	if subscription.QueueSize <= 0 {
+		subscription.QueueSize = defaultSubscriptionQueueSize
+	}
+
+	sub := &subscriber{Subscription: subscription}
+	for range subscription.Workers {
+		queue := make(chan events.Message, subscription.QueueSize)
+		sub.queues = append(sub.queues, queue)
+		go sub.work(dm.ctx, queue)
+	}
+
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+	dm.subscribers = append(dm.subscribers, sub)
+}
+
+// dispatch queues an event for the subscriptions to its action without waiting for them
+func (dm *DockerMonitor) dispatch(event events.Message) {
+	dm.mutex.Lock()
+	subscribers := dm.subscribers
+	dm.mutex.Unlock()
+
+	for _, sub := range subscribers {
+		if len(sub.Actions) > 0 && !slices.Contains(sub.Actions, event.Action) {
+			continue
+		}
+
+		queue := sub.queues[workerIndex(event.Actor.ID, len(sub.queues))]
+		select {
+		case queue <- event:
+		default:
+			common.DockerEventsDroppedCount.WithLabelValues(sub.Name).Inc()
+			log.Errorf("Dropped %s event of container %s, the queue of the %s subscription is full", event.Action, event.Actor.ID, sub.Name)
+		}
+	}
+}
+
+func (s *subscriber) work(ctx context.Context, queue chan events.Message) {
+	for {
+		select {
+		case event := <-queue:
+			s.handle(ctx, event)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *subscriber) handle(ctx context.Context, event events.Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			common.DockerEventHandlerPanicCount.WithLabelValues(s.Name).Inc()
+			log.Errorf("Handler of the %s subscription panicked on %s event of container %s: %v\n%s", s.Name, event.Action, event.Actor.ID, r, debug.Stack())
+		}
+	}()
+
+	s.Handler(ctx, event)
+}
+
+// workerIndex assigns the events of a container to the same worker
+func workerIndex(containerId string, workers int) int {
+	if workers == 1 {
+		return 0
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(containerId))
+	return int(hash.Sum32() % uint32(workers))
+}
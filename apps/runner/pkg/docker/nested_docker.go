@@ -0,0 +1,136 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// Name of the sidecar running the rootless Docker daemon of a sandbox in the dind mode
+const dindSidecarName = "dind"
+
+// Address the dind sidecar listens on. It shares the network namespace of the sandbox, so the
+// daemon is only reachable from the sandbox and its traffic is subject to the sandbox egress rules.
+const dindHost = "tcp://127.0.0.1:2375"
+
+// Directory Sysbox keeps the /var/lib/docker of its containers in, by container ID
+const sysboxDockerDataDir = "/var/lib/sysbox/docker"
+
+// NestedDockerModes returns the nested Docker modes enabled on the runner
+func (d *DockerClient) NestedDockerModes() []string {
+	modes := []string{}
+	if d.dindImage != "" {
+		modes = append(modes, dto.NestedDockerModeDind)
+	}
+	if d.sysboxRuntime != "" {
+		modes = append(modes, dto.NestedDockerModeSysbox)
+	}
+	return modes
+}
+
+func (d *DockerClient) validateNestedDocker(sandboxDto dto.CreateSandboxDTO) error {
+	if sandboxDto.NestedDocker == nil {
+		return nil
+	}
+
+	if !slices.Contains(d.NestedDockerModes(), sandboxDto.NestedDocker.Mode) {
+		return common_errors.NewBadRequestError(fmt.Errorf("nested Docker mode %s is not enabled on the runner", sandboxDto.NestedDocker.Mode))
+	}
+
+	for _, sidecar := range sandboxDto.Sidecars {
+		if sidecar.Name == dindSidecarName {
+			return common_errors.NewBadRequestError(errors.New("sidecar name dind is reserved for the nested Docker daemon"))
+		}
+	}
+
+	return nil
+}
+
+// getDindSidecar returns the sidecar running the rootless Docker daemon of a sandbox. It is
+// limited to the resources of the sandbox, and the storage it uses counts against the sandbox quota.
+func (d *DockerClient) getDindSidecar(sandboxDto dto.CreateSandboxDTO) (dto.SidecarDTO, *container.HostConfig) {
+	sidecar := dto.SidecarDTO{
+		Name:  dindSidecarName,
+		Image: d.dindImage,
+		Env: map[string]string{
+			// The daemon is only reachable from the sandbox, which doesn't have its client certs
+			"DOCKER_TLS_CERTDIR": "",
+		},
+		Cmd: []string{"--host=" + dindHost, "--tls=false"},
+	}
+
+	// Rootless dockerd still needs to set up user namespaces, which the default seccomp and
+	// AppArmor profiles prevent
+	hostConfig := &container.HostConfig{
+		Privileged: true,
+	}
+	if !d.resourceLimitsDisabled {
+		hostConfig.Resources = container.Resources{
+			CPUPeriod:  100000,
+			CPUQuota:   sandboxDto.CpuQuota * 100000,
+			Memory:     common.GBToBytes(float64(sandboxDto.MemoryQuota)),
+			MemorySwap: common.GBToBytes(float64(sandboxDto.MemoryQuota)),
+		}
+	}
+
+	return sidecar, hostConfig
+}
+
+// getNestedDockerUsage returns the space used by the nested Docker daemon of a sandbox
+func (d *DockerClient) getNestedDockerUsage(ctx context.Context, c container.InspectResponse) (int64, error) {
+	if c.Config == nil {
+		return 0, nil
+	}
+
+	switch c.Config.Labels[common.NESTED_DOCKER_LABEL] {
+	case dto.NestedDockerModeSysbox:
+		used, _, err := dirUsage(ctx, filepath.Join(sysboxDockerDataDir, c.ID), 0)
+		return used, err
+	case dto.NestedDockerModeDind:
+		sidecar, err := d.apiClient.ContainerInspect(ctx, sidecarContainerName(strings.TrimPrefix(c.Name, "/"), dindSidecarName))
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				return 0, nil
+			}
+			return 0, err
+		}
+
+		var used int64
+		if upperDir, ok := sidecar.GraphDriver.Data["UpperDir"]; ok && sidecar.GraphDriver.Name == "overlay2" {
+			layerUsed, _, err := dirUsage(ctx, upperDir, 0)
+			if err != nil {
+				return 0, err
+			}
+			used += layerUsed
+		}
+
+		// The images and containers of the daemon are in the volume of the dind image
+		for _, m := range sidecar.Mounts {
+			if m.Type != mount.TypeVolume {
+				continue
+			}
+			volumeUsed, _, err := dirUsage(ctx, m.Source, 0)
+			if err != nil {
+				return 0, err
+			}
+			used += volumeUsed
+		}
+
+		return used, nil
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,351 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/daytonaio/runner/cmd/runner/config"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/netrules"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// Networks Docker creates itself
+var predefinedNetworks = []string{"bridge", "host", "none"}
+
+// Tables the network rules of sandboxes create chains in
+var chainTables = []string{"filter", "mangle", "nat"}
+
+var anonymousVolumeRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Orphan is a resource of the host that isn't associated with a sandbox anymore
+type Orphan struct {
+	dto.OrphanResourceDTO
+	remove func(ctx context.Context) error
+}
+
+func (o *Orphan) Remove(ctx context.Context) error {
+	return o.remove(ctx)
+}
+
+// FindOrphans returns the orphaned resources of a kind that are older than minAge. Sandbox
+// containers are orphaned if they are stopped and not in known, the IDs of the sandboxes the
+// control plane assigned to the runner, and home volumes if they are unused and their key is not.
+func (d *DockerClient) FindOrphans(ctx context.Context, kind string, known map[string]bool, minAge time.Duration) ([]Orphan, error) {
+	switch kind {
+	case dto.OrphanKindContainer:
+		return d.findOrphanedContainers(ctx, known, minAge)
+	case dto.OrphanKindSidecar:
+		return d.findOrphanedSidecars(ctx)
+	case dto.OrphanKindVolume:
+		return d.findOrphanedVolumes(ctx, minAge)
+	case dto.OrphanKindVolumeMount:
+		return d.findOrphanedVolumeMounts(ctx)
+	case dto.OrphanKindNetwork:
+		return d.findOrphanedNetworks(ctx, minAge)
+	case dto.OrphanKindChain:
+		return d.findOrphanedChains(ctx)
+	case dto.OrphanKindImage:
+		return d.findOrphanedImages(ctx, minAge)
+	case dto.OrphanKindHomeVolume:
+		return d.findOrphanedHomeVolumes(ctx, known, minAge)
+	}
+
+	return nil, fmt.Errorf("unknown orphan kind %s", kind)
+}
+
+func (d *DockerClient) findOrphanedContainers(ctx context.Context, known map[string]bool, minAge time.Duration) ([]Orphan, error) {
+	containers, err := d.listSandboxContainers(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	for _, c := range containers {
+		if len(c.Names) == 0 || len(c.Names[0]) < 2 {
+			continue
+		}
+		sandboxId := c.Names[0][1:]
+
+		// Leftovers of disk resizes may hold the only copy of the data, the recovery janitor resolves them
+		if _, ok := parseLeftoverContainerName(sandboxId); ok {
+			continue
+		}
+
+		if known[sandboxId] || c.State == container.StateRunning || time.Since(time.Unix(c.Created, 0)) < minAge {
+			continue
+		}
+
+		orphans = append(orphans, Orphan{
+			OrphanResourceDTO: dto.OrphanResourceDTO{
+				Kind:   dto.OrphanKindContainer,
+				Id:     sandboxId,
+				Reason: fmt.Sprintf("%s sandbox unknown to the control plane", c.State),
+			},
+			remove: func(ctx context.Context) error {
+				return d.Destroy(ctx, sandboxId)
+			},
+		})
+	}
+
+	return orphans, nil
+}
+
+func (d *DockerClient) findOrphanedSidecars(ctx context.Context) ([]Orphan, error) {
+	sidecars, err := d.apiClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", common.SIDECAR_OF_LABEL)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	for _, sidecar := range sidecars {
+		sandboxId := sidecar.Labels[common.SIDECAR_OF_LABEL]
+
+		_, err := d.apiClient.ContainerInspect(ctx, sandboxId)
+		if err == nil {
+			continue
+		}
+		if !errdefs.IsNotFound(err) {
+			return nil, err
+		}
+
+		sidecarId := sidecar.ID
+		orphans = append(orphans, Orphan{
+			OrphanResourceDTO: dto.OrphanResourceDTO{
+				Kind:   dto.OrphanKindSidecar,
+				Id:     sidecarId[:12],
+				Reason: fmt.Sprintf("sidecar %s of removed sandbox %s", sidecar.Labels[common.SIDECAR_NAME_LABEL], sandboxId),
+			},
+			remove: func(ctx context.Context) error {
+				return d.apiClient.ContainerRemove(ctx, sidecarId, container.RemoveOptions{
+					Force:         true,
+					RemoveVolumes: true,
+				})
+			},
+		})
+	}
+
+	return orphans, nil
+}
+
+func (d *DockerClient) findOrphanedVolumes(ctx context.Context, minAge time.Duration) ([]Orphan, error) {
+	volumes, err := d.apiClient.VolumeList(ctx, volume.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("dangling", "true")),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	for _, v := range volumes.Volumes {
+		// Named volumes may belong to the operator, sandboxes only get anonymous ones
+		if !anonymousVolumeRegex.MatchString(v.Name) {
+			continue
+		}
+		if createdAt, err := time.Parse(time.RFC3339, v.CreatedAt); err == nil && time.Since(createdAt) < minAge {
+			continue
+		}
+
+		name := v.Name
+		orphans = append(orphans, Orphan{
+			OrphanResourceDTO: dto.OrphanResourceDTO{
+				Kind:   dto.OrphanKindVolume,
+				Id:     name,
+				Reason: "anonymous volume no container uses",
+			},
+			remove: func(ctx context.Context) error {
+				return d.apiClient.VolumeRemove(ctx, name, false)
+			},
+		})
+	}
+
+	return orphans, nil
+}
+
+func (d *DockerClient) findOrphanedHomeVolumes(ctx context.Context, known map[string]bool, minAge time.Duration) ([]Orphan, error) {
+	volumes, err := d.apiClient.VolumeList(ctx, volume.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("dangling", "true"),
+			filters.Arg("label", homeVolumeKeyLabel),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	for _, v := range volumes.Volumes {
+		// The control plane may still create a sandbox that takes over the volume
+		if known[v.Labels[homeVolumeKeyLabel]] {
+			continue
+		}
+		if createdAt, err := time.Parse(time.RFC3339, v.CreatedAt); err == nil && time.Since(createdAt) < minAge {
+			continue
+		}
+
+		name := v.Name
+		orphans = append(orphans, Orphan{
+			OrphanResourceDTO: dto.OrphanResourceDTO{
+				Kind:   dto.OrphanKindHomeVolume,
+				Id:     name,
+				Reason: fmt.Sprintf("home volume of sandbox %s unknown to the control plane", v.Labels[homeVolumeKeyLabel]),
+			},
+			remove: func(ctx context.Context) error {
+				return d.apiClient.VolumeRemove(ctx, name, false)
+			},
+		})
+	}
+
+	return orphans, nil
+}
+
+func (d *DockerClient) findOrphanedVolumeMounts(ctx context.Context) ([]Orphan, error) {
+	dirs, err := d.FindOrphanedVolumeMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := make([]Orphan, 0, len(dirs))
+	for _, dir := range dirs {
+		orphans = append(orphans, Orphan{
+			OrphanResourceDTO: dto.OrphanResourceDTO{
+				Kind:   dto.OrphanKindVolumeMount,
+				Id:     dir,
+				Reason: "volume mount no container uses",
+			},
+			remove: func(ctx context.Context) error {
+				d.RemoveVolumeMount(dir)
+				return nil
+			},
+		})
+	}
+
+	return orphans, nil
+}
+
+func (d *DockerClient) findOrphanedNetworks(ctx context.Context, minAge time.Duration) ([]Orphan, error) {
+	// Other networks may belong to the operator, e.g. the one sandboxes are connected to
+	networks, err := d.apiClient.NetworkList(ctx, network.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("dangling", "true"),
+			filters.Arg("label", common.RUNNER_NETWORK_LABEL),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	for _, n := range networks {
+		if slices.Contains(predefinedNetworks, n.Name) || n.Name == config.GetContainerNetwork() || n.Ingress || time.Since(n.Created) < minAge {
+			continue
+		}
+
+		networkId := n.ID
+		orphans = append(orphans, Orphan{
+			OrphanResourceDTO: dto.OrphanResourceDTO{
+				Kind:   dto.OrphanKindNetwork,
+				Id:     n.Name,
+				Reason: "network no container is connected to",
+			},
+			remove: func(ctx context.Context) error {
+				return d.apiClient.NetworkRemove(ctx, networkId)
+			},
+		})
+	}
+
+	return orphans, nil
+}
+
+func (d *DockerClient) findOrphanedChains(ctx context.Context) ([]Orphan, error) {
+	var orphans []Orphan
+	for _, table := range chainTables {
+		chains, err := d.netRulesManager.ListDaytonaChains(table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the chains of the %s table: %w", table, err)
+		}
+
+		for _, chain := range chains {
+			// Chains not named after a container aren't known to be unused
+			containerId, ok := netrules.ChainContainerId(chain)
+			if !ok {
+				continue
+			}
+
+			_, err := d.apiClient.ContainerInspect(ctx, containerId)
+			if err == nil {
+				continue
+			}
+			if !errdefs.IsNotFound(err) {
+				return nil, err
+			}
+
+			table, chain := table, chain
+			orphans = append(orphans, Orphan{
+				OrphanResourceDTO: dto.OrphanResourceDTO{
+					Kind:   dto.OrphanKindChain,
+					Id:     table + "/" + chain,
+					Reason: fmt.Sprintf("chain of removed container %s", containerId),
+				},
+				remove: func(ctx context.Context) error {
+					return d.netRulesManager.DeleteChain(table, chain)
+				},
+			})
+		}
+	}
+
+	return orphans, nil
+}
+
+func (d *DockerClient) findOrphanedImages(ctx context.Context, minAge time.Duration) ([]Orphan, error) {
+	images, err := d.apiClient.ImageList(ctx, image.ListOptions{ContainerCount: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	for _, img := range images {
+		if img.Containers != 0 || time.Since(time.Unix(img.Created, 0)) < minAge {
+			continue
+		}
+		if d.dindImage != "" && slices.Contains(img.RepoTags, d.dindImage) {
+			continue
+		}
+
+		id := img.ID
+		name := id
+		if len(img.RepoTags) > 0 {
+			name = img.RepoTags[0]
+		}
+
+		orphans = append(orphans, Orphan{
+			OrphanResourceDTO: dto.OrphanResourceDTO{
+				Kind:   dto.OrphanKindImage,
+				Id:     name,
+				Reason: "image no container uses",
+				Size:   img.Size,
+			},
+			remove: func(ctx context.Context) error {
+				_, err := d.apiClient.ImageRemove(ctx, id, image.RemoveOptions{PruneChildren: true, Force: true})
+				return err
+			},
+		})
+	}
+
+	return orphans, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// usesPackageCache returns whether a sandbox is configured to download packages through the
+// runner package cache. Sandboxes with restricted networks aren't, the cache would let them
+// reach the registries.
+func (d *DockerClient) usesPackageCache(sandboxDto dto.CreateSandboxDTO) bool {
+	switch {
+	case d.packageCacheUrl == "":
+		return false
+	case sandboxDto.NetworkBlockAll != nil && *sandboxDto.NetworkBlockAll:
+		return false
+	case sandboxDto.NetworkAllowList != nil && *sandboxDto.NetworkAllowList != "":
+		return false
+	case sandboxDto.EgressProxy != nil:
+		return false
+	case sandboxDto.Dns != nil && len(sandboxDto.Dns.AllowedDomains) > 0:
+		return false
+	}
+	return true
+}
+
+// packageCacheEnv returns the env pointing the package managers of a sandbox to the package
+// cache, except for the variables the sandbox sets itself
+func (d *DockerClient) packageCacheEnv(envVars []string) []string {
+	baseUrl := strings.TrimSuffix(d.packageCacheUrl, "/")
+
+	host := baseUrl
+	if parsed, err := url.Parse(baseUrl); err == nil {
+		host = parsed.Hostname()
+	}
+
+	cacheEnv := []string{
+		"NPM_CONFIG_REGISTRY=" + baseUrl + "/npm/",
+		"YARN_NPM_REGISTRY_SERVER=" + baseUrl + "/npm",
+		// Yarn and pip refuse registries served over plain HTTP unless they are trusted
+		"YARN_UNSAFE_HTTP_WHITELIST=" + host,
+		"PIP_INDEX_URL=" + baseUrl + "/pypi/simple/",
+		"PIP_TRUSTED_HOST=" + host,
+		"UV_DEFAULT_INDEX=" + baseUrl + "/pypi/simple/",
+		// Falls back to direct downloads if the cache fails
+		"GOPROXY=" + baseUrl + "/go|direct",
+	}
+
+	set := make(map[string]bool, len(envVars))
+	for _, env := range envVars {
+		key, _, _ := strings.Cut(env, "=")
+		set[key] = true
+	}
+
+	result := make([]string, 0, len(cacheEnv))
+	for _, env := range cacheEnv {
+		key, _, _ := strings.Cut(env, "=")
+		if !set[key] {
+			result = append(result, env)
+		}
+	}
+	return result
+}
+
+// IsPackageCacheClient returns whether the sandbox with the address may use the package cache.
+// The network of a sandbox can be restricted after it was created, which the cache is checked
+// against as well.
+func (d *DockerClient) IsPackageCacheClient(ctx context.Context, ip string) bool {
+	c, err := common.FindContainerByIpAddress(ctx, d.apiClient, ip, common.PACKAGE_CACHE_LABEL)
+	if err != nil {
+		log.Warnf("Failed to look up the package cache access of %s: %v", ip, err)
+		return false
+	}
+	if c == nil {
+		return false
+	}
+
+	if d.netRulesManager != nil {
+		restricted, err := d.netRulesManager.HasNetworkRules(c.ID[:12])
+		if err != nil {
+			log.Warnf("Failed to check the network rules of sandbox %s: %v", c.ID[:12], err)
+			return false
+		}
+		if restricted {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+// ListSandboxProcesses returns the processes the daemon of a running sandbox reports
+func (d *DockerClient) ListSandboxProcesses(ctx context.Context, sandboxId string) ([]dto.SandboxProcessDTO, error) {
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []dto.SandboxProcessDTO
+	err = d.daemonRequest(ctx, c, http.MethodGet, "/process/list?sort=cpu", nil, &processes, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/models/enums"
+	"github.com/docker/docker/api/types/container"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Path the daemon reads the provisioning steps from, see the provisioning package of the daemon
+const provisioningSpecPath = "/.daytona-provisioning.json"
+
+// copyProvisioningSpec writes the provisioning steps into a created sandbox, for the daemon to run
+// once it first starts
+func (d *DockerClient) copyProvisioningSpec(ctx context.Context, sandboxId string, provisioning *dto.ProvisioningDTO) error {
+	return d.copySpecFile(ctx, sandboxId, provisioningSpecPath, provisioning)
+}
+
+// copySpecFile writes a spec the daemon reads on boot into a created sandbox, as JSON
+func (d *DockerClient) copySpecFile(ctx context.Context, sandboxId, path string, value any) error {
+	spec, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    path[1:],
+		Mode:    0644,
+		Size:    int64(len(spec)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := tw.Write(spec); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return d.apiClient.CopyToContainer(ctx, sandboxId, "/", &archive, container.CopyToContainerOptions{})
+}
+
+func (d *DockerClient) GetProvisioningStatus(ctx context.Context, sandboxId string) (*dto.ProvisioningStatusDTO, error) {
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Config.Labels[common.PROVISIONING_LABEL] == "" {
+		return nil, common_errors.NewNotFoundError(errors.New("sandbox has no provisioning steps"))
+	}
+
+	if !c.State.Running {
+		return nil, common_errors.NewBadRequestError(errors.New("sandbox is not running"))
+	}
+
+	return d.getProvisioningStatus(ctx, c)
+}
+
+func (d *DockerClient) getProvisioningStatus(ctx context.Context, c container.InspectResponse) (*dto.ProvisioningStatusDTO, error) {
+	var status dto.ProvisioningStatusDTO
+	err := d.daemonRequest(ctx, c, http.MethodGet, "/provisioning", nil, &status, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provisioning status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// provisioningState returns the state of a running sandbox with provisioning steps, provisioning
+// until they all succeeded
+func (d *DockerClient) provisioningState(ctx context.Context, sandboxId string, c container.InspectResponse) (enums.SandboxState, error) {
+	if _, ok := d.provisionedSandboxes.Load(sandboxId); ok {
+		return enums.SandboxStateStarted, nil
+	}
+
+	status, err := d.getProvisioningStatus(ctx, c)
+	if err != nil {
+		// The daemon may not be up yet
+		log.Debugf("Failed to get provisioning status of sandbox %s: %v", sandboxId, err)
+		return enums.SandboxStateProvisioning, nil
+	}
+
+	switch status.State {
+	case "succeeded":
+		d.provisionedSandboxes.Store(sandboxId, struct{}{})
+		return enums.SandboxStateStarted, nil
+	case "failed":
+		for _, step := range status.Steps {
+			if step.State == "failed" {
+				return enums.SandboxStateError, fmt.Errorf("provisioning step %s failed: %s", step.Name, step.Error)
+			}
+		}
+		return enums.SandboxStateError, errors.New("provisioning failed")
+	default:
+		return enums.SandboxStateProvisioning, nil
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// readOnlyRootfsMounts returns the volumes of the writable paths of a sandbox with a read-only root
+// filesystem. They are anonymous, so they are removed with the sandbox.
+func readOnlyRootfsMounts(sandboxDto dto.CreateSandboxDTO) ([]mount.Mount, error) {
+	if sandboxDto.Devcontainer != nil {
+		return nil, common_errors.NewBadRequestError(errors.New("devcontainer provisioning requires a writable root filesystem"))
+	}
+	if sandboxDto.Provisioning != nil {
+		return nil, common_errors.NewBadRequestError(errors.New("provisioning steps require a writable root filesystem"))
+	}
+
+	// The daemon keeps its state in the home of the user
+	paths := []string{"/tmp", homeDir(sandboxDto.OsUser)}
+	for _, writablePath := range sandboxDto.ReadOnlyRootfs.WritablePaths {
+		writablePath = path.Clean(writablePath)
+		if writablePath == "/" {
+			return nil, common_errors.NewBadRequestError(errors.New("the root path can't be writable"))
+		}
+		if writablePath == common.DAEMON_PATH || strings.HasPrefix(common.DAEMON_PATH, writablePath+"/") {
+			return nil, common_errors.NewBadRequestError(fmt.Errorf("writable path %s would hide the daemon", writablePath))
+		}
+		if !slices.Contains(paths, writablePath) {
+			paths = append(paths, writablePath)
+		}
+	}
+
+	mounts := make([]mount.Mount, 0, len(paths))
+	for _, writablePath := range paths {
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeVolume,
+			Target: writablePath,
+		})
+	}
+
+	return mounts, nil
+}
@@ -13,6 +13,10 @@ import (
 )
 
 func (d *DockerClient) RecoverSandbox(ctx context.Context, sandboxId string, recoverDto dto.RecoverSandboxDTO) error {
+	if d.isMicroVM(sandboxId) {
+		return errMicroVMUnsupported("recovery")
+	}
+
 	// Deduce recovery type from error reason
 	recoveryType := common.DeduceRecoveryType(recoverDto.ErrorReason)
 	if recoveryType == models.UnknownRecoveryType {
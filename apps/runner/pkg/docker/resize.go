@@ -5,10 +5,10 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/daytonaio/common-go/pkg/utils"
 	"github.com/daytonaio/runner/pkg/api/dto"
 	"github.com/daytonaio/runner/pkg/common"
 	"github.com/daytonaio/runner/pkg/models/enums"
@@ -20,6 +20,10 @@ import (
 )
 
 func (d *DockerClient) Resize(ctx context.Context, sandboxId string, sandboxDto dto.ResizeSandboxDTO) error {
+	if d.isMicroVM(sandboxId) {
+		return errMicroVMUnsupported("resizing")
+	}
+
 	// Handle disk resize (requires container recreation)
 	// Value of 0 means "don't change" (minimum valid value is 1)
 	if sandboxDto.Disk > 0 {
@@ -65,6 +69,20 @@ func (d *DockerClient) Resize(ctx context.Context, sandboxId string, sandboxDto
 		resources.MemorySwap = resources.Memory // Disable swap
 	}
 
+	if d.cpuAllocator != nil {
+		containerInfo, err := d.ContainerInspect(ctx, sandboxId)
+		if err != nil {
+			d.statesCache.SetSandboxState(ctx, sandboxId, originalState)
+			return fmt.Errorf("failed to inspect container: %w", err)
+		}
+
+		err = d.repinCpus(sandboxId, containerInfo.Config.Labels, containerInfo.HostConfig.Resources, sandboxDto.Cpu, sandboxDto.Memory, &resources)
+		if err != nil {
+			d.statesCache.SetSandboxState(ctx, sandboxId, originalState)
+			return err
+		}
+	}
+
 	_, err = d.apiClient.ContainerUpdate(ctx, sandboxId, container.UpdateConfig{
 		Resources: resources,
 	})
@@ -110,6 +128,14 @@ func (d *DockerClient) ContainerDiskResize(ctx context.Context, sandboxId string
 		return fmt.Errorf("%s requires XFS filesystem, current filesystem: %s", operationName, filesystem)
 	}
 
+	// The old container is kept until its data is copied, the host needs space for both
+	if overlayDiffPath != "" {
+		err = d.ensureCopySpace(ctx, sandboxId, overlayDiffPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Rename container after validation checks to reduce error handling complexity
 	timestamp := time.Now().Unix()
 	oldName := fmt.Sprintf("%s-%s-%d", sandboxId, operationName, timestamp)
@@ -142,12 +168,15 @@ func (d *DockerClient) ContainerDiskResize(ctx context.Context, sandboxId string
 		log.Debugf("Setting memory to %dGB", memory)
 	}
 
-	err = utils.RetryWithExponentialBackoff(
+	err = d.repinCpus(sandboxId, originalContainer.Config.Labels, originalContainer.HostConfig.Resources, cpu, memory, &newHostConfig.Resources)
+	if err != nil {
+		d.restoreRenamedContainer(ctx, oldName, sandboxId)
+		return err
+	}
+
+	err = retry(
 		ctx,
 		fmt.Sprintf("create sandbox %s", sandboxId),
-		utils.DEFAULT_MAX_RETRIES,
-		utils.DEFAULT_BASE_DELAY,
-		utils.DEFAULT_MAX_DELAY,
 		func() error {
 			_, createErr := d.apiClient.ContainerCreate(
 				ctx,
@@ -164,21 +193,25 @@ func (d *DockerClient) ContainerDiskResize(ctx context.Context, sandboxId string
 		},
 	)
 	if err != nil {
-		_ = d.apiClient.ContainerRename(ctx, oldName, sandboxId)
+		// The container may have been created if the request was canceled while it was in flight
+		d.restoreRenamedContainer(ctx, oldName, sandboxId)
 		return fmt.Errorf("failed to create new container: %w", err)
 	}
 
 	d.statesCache.SetSandboxState(ctx, sandboxId, enums.SandboxStateStopped)
 
+	if err := d.applyVolumeQuotas(ctx, sandboxId); err != nil {
+		log.Errorf("Failed to limit the data volumes of sandbox %s: %v", sandboxId, err)
+	}
+
 	// Copy data directly between overlay2 layers using rsync
 	if overlayDiffPath != "" {
 		log.Debug("Copying data directly between overlay2 layers using rsync")
 		err = d.copyContainerOverlayData(ctx, overlayDiffPath, sandboxId)
 		if err != nil {
 			log.Errorf("Failed to copy overlay data: %v", err)
-			log.Warnf("Old container preserved as %s for manual data recovery", oldName)
-			_ = d.apiClient.ContainerRemove(ctx, sandboxId, container.RemoveOptions{Force: true})
-			_ = d.apiClient.ContainerRename(ctx, oldName, sandboxId)
+			log.Warnf("Restoring the old container %s of sandbox %s", oldName, sandboxId)
+			d.restoreRenamedContainer(ctx, oldName, sandboxId)
 			return fmt.Errorf("failed to copy data: %w", err)
 		}
 		log.Debugf("Data copy completed")
@@ -186,9 +219,11 @@ func (d *DockerClient) ContainerDiskResize(ctx context.Context, sandboxId string
 		log.Warn("Could not determine old container overlay2 path, skipping data copy")
 	}
 
-	// Remove old container after successful data copy
+	// Remove old container after successful data copy, also if the caller gave up meanwhile
 	log.Debugf("Removing old container %s", oldName)
-	err = d.apiClient.ContainerRemove(ctx, oldName, container.RemoveOptions{Force: true})
+	cleanupCtx, cancel := cleanupContext(ctx)
+	defer cancel()
+	err = d.apiClient.ContainerRemove(cleanupCtx, oldName, container.RemoveOptions{Force: true})
 	if err != nil {
 		log.Warnf("Failed to remove old container %s: %v", oldName, err)
 	}
@@ -221,9 +256,30 @@ func (d *DockerClient) copyContainerOverlayData(ctx context.Context, oldContaine
 
 	log.Debugf("Copying overlay data from %s to %s", oldContainerOverlayPath, newUpperDir)
 
-	// Use rsync with timeout to copy data
-	copyCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
-	defer cancel()
+	err = d.copyOverlayData(ctx, oldContainerOverlayPath, newUpperDir)
+	if err != nil {
+		return err
+	}
+
+	// Verified while the old container still exists, so that it is kept if the copy differs
+	err = common.VerifyCopy(ctx, oldContainerOverlayPath, newUpperDir, d.copyConfig.Verify)
+	var mismatchErr *common.CopyMismatchError
+	if errors.As(err, &mismatchErr) {
+		common.CopyVerificationFailureCount.Inc()
+	}
+	return err
+}
+
+func (d *DockerClient) copyOverlayData(ctx context.Context, src, dest string) error {
+	// Both upper dirs are on the XFS filesystem of Docker, which shares their extents if it was
+	// created with reflink=1
+	if d.copyConfig.Reflink {
+		err := common.ReflinkCopy(ctx, src, dest, d.copyConfig)
+		if !errors.Is(err, common.ErrReflinkUnsupported) {
+			return err
+		}
+		log.Info("Filesystem doesn't support reflinks, copying overlay data")
+	}
 
-	return common.RsyncCopy(copyCtx, oldContainerOverlayPath, newUpperDir)
+	return common.RsyncCopy(ctx, src, dest, d.copyConfig)
 }
@@ -0,0 +1,39 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/models/enums"
+)
+
+// ContainerRuntime is the part of DockerClient the sandbox services and the job executor depend
+// on, so that they can be run against the in-memory runtime of the testsupport package
+type ContainerRuntime interface {
+	// Sandboxes
+	Create(ctx context.Context, sandboxDto dto.CreateSandboxDTO) (string, string, error)
+	Start(ctx context.Context, containerId string, metadata map[string]string) (string, error)
+	Stop(ctx context.Context, containerId string) error
+	Destroy(ctx context.Context, containerId string) error
+	Resize(ctx context.Context, sandboxId string, sandboxDto dto.ResizeSandboxDTO) error
+	RecoverSandbox(ctx context.Context, sandboxId string, recoverDto dto.RecoverSandboxDTO) error
+	UpdateNetworkSettings(ctx context.Context, containerId string, updateNetworkSettingsDto dto.UpdateNetworkSettingsDTO) error
+	ApplyDevcontainer(ctx context.Context, sandboxId, osUser string, devcontainerDto dto.DevcontainerDTO, progress func(dto.DevcontainerProgressDTO)) (*dto.DevcontainerResultDTO, error)
+	DeduceSandboxState(ctx context.Context, sandboxId string) (enums.SandboxState, error)
+	ListSandboxes(ctx context.Context, labels map[string]string) ([]dto.SandboxSummaryDTO, error)
+
+	// Backups
+	CreateBackup(ctx context.Context, containerId string, backupDto dto.CreateBackupDTO) error
+
+	// Snapshots
+	PullSnapshot(ctx context.Context, req dto.PullSnapshotRequestDTO) error
+	BuildSnapshot(ctx context.Context, req dto.BuildSnapshotRequestDTO) error
+	RemoveImage(ctx context.Context, imageName string, force bool) error
+	GetImageInfo(ctx context.Context, imageName string) (*ImageInfo, error)
+	InspectImageInRegistry(ctx context.Context, imageName string, registry *dto.RegistryDTO) (*ImageDigest, error)
+}
+
+var _ ContainerRuntime = (*DockerClient)(nil)
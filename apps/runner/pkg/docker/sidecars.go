@@ -0,0 +1,297 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/containerd/errdefs"
+	"github.com/daytonaio/common-go/pkg/timer"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+func sidecarContainerName(sandboxId, name string) string {
+	return sandboxId + "-" + name
+}
+
+// createSidecars creates the sidecar containers of a sandbox in the network namespace of its
+// container, which must exist. They are started along with the sandbox.
+func (d *DockerClient) createSidecars(ctx context.Context, sandboxDto dto.CreateSandboxDTO) error {
+	defer timer.Timer()()
+
+	for _, sidecar := range sandboxDto.Sidecars {
+		labels := map[string]string{}
+		// Sidecars count against the organization quota, but not as sandboxes
+		if orgID := sandboxDto.Metadata["organizationId"]; orgID != "" {
+			labels["daytona.organization_id"] = orgID
+		}
+
+		hostConfig := &container.HostConfig{}
+		if !d.resourceLimitsDisabled {
+			hostConfig.Resources = container.Resources{
+				CPUPeriod:  100000,
+				CPUQuota:   sidecar.CpuQuota * 100000,
+				Memory:     common.GBToBytes(float64(sidecar.MemoryQuota)),
+				MemorySwap: common.GBToBytes(float64(sidecar.MemoryQuota)),
+			}
+		}
+
+		if err := d.createSidecar(ctx, sandboxDto.Id, sidecar, labels, hostConfig); err != nil {
+			return err
+		}
+	}
+
+	if sandboxDto.NestedDocker != nil && sandboxDto.NestedDocker.Mode == dto.NestedDockerModeDind {
+		sidecar, hostConfig := d.getDindSidecar(sandboxDto)
+		if err := d.createSidecar(ctx, sandboxDto.Id, sidecar, nil, hostConfig); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DockerClient) createSidecar(ctx context.Context, sandboxId string, sidecar dto.SidecarDTO, labels map[string]string, hostConfig *container.HostConfig) error {
+	if err := d.PullImage(ctx, sidecar.Image, sidecar.Registry); err != nil {
+		return fmt.Errorf("failed to pull image of sidecar %s: %w", sidecar.Name, err)
+	}
+
+	env := make([]string, 0, len(sidecar.Env))
+	for key, value := range sidecar.Env {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[common.SIDECAR_OF_LABEL] = sandboxId
+	labels[common.SIDECAR_NAME_LABEL] = sidecar.Name
+
+	hostConfig.NetworkMode = container.NetworkMode("container:" + sandboxId)
+	hostConfig.RestartPolicy = container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3}
+
+	_, err := d.apiClient.ContainerCreate(ctx, &container.Config{
+		Image:      sidecar.Image,
+		Env:        env,
+		Entrypoint: sidecar.Entrypoint,
+		Cmd:        sidecar.Cmd,
+		Labels:     labels,
+	}, hostConfig, nil, &v1.Platform{
+		Architecture: "amd64",
+		OS:           "linux",
+	}, sidecarContainerName(sandboxId, sidecar.Name))
+	if err != nil && !errdefs.IsConflict(err) {
+		return fmt.Errorf("failed to create sidecar %s: %w", sidecar.Name, err)
+	}
+
+	return nil
+}
+
+func (d *DockerClient) listSidecars(ctx context.Context, sandboxId string) ([]container.Summary, error) {
+	return d.apiClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", common.SIDECAR_OF_LABEL+"="+sandboxId)),
+	})
+}
+
+// startSidecars starts the sidecars of a sandbox after its container. Sidecars still running
+// from before the sandbox container was started are in a stale network namespace, so they are
+// restarted if restart is set.
+func (d *DockerClient) startSidecars(ctx context.Context, sandboxId string, restart bool) error {
+	sidecars, err := d.listSidecars(ctx, sandboxId)
+	if err != nil {
+		return err
+	}
+
+	for _, sidecar := range sidecars {
+		name := sidecar.Labels[common.SIDECAR_NAME_LABEL]
+
+		if sidecar.State == container.StateRunning {
+			if !restart {
+				continue
+			}
+			timeout := 10
+			if err := d.apiClient.ContainerRestart(ctx, sidecar.ID, container.StopOptions{Timeout: &timeout}); err != nil {
+				return fmt.Errorf("failed to restart sidecar %s: %w", name, err)
+			}
+			continue
+		}
+
+		if err := d.apiClient.ContainerStart(ctx, sidecar.ID, container.StartOptions{}); err != nil {
+			return fmt.Errorf("failed to start sidecar %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// stopSidecars stops the sidecars of a sandbox, which is done before its container is stopped
+func (d *DockerClient) stopSidecars(ctx context.Context, sandboxId string) error {
+	sidecars, err := d.listSidecars(ctx, sandboxId)
+	if err != nil {
+		return err
+	}
+
+	for _, sidecar := range sidecars {
+		if sidecar.State != container.StateRunning && sidecar.State != container.StateRestarting {
+			continue
+		}
+
+		if err := d.stopContainerWithRetry(ctx, sidecar.ID, 10); err != nil {
+			return fmt.Errorf("failed to stop sidecar %s: %w", sidecar.Labels[common.SIDECAR_NAME_LABEL], err)
+		}
+	}
+
+	return nil
+}
+
+func (d *DockerClient) removeSidecars(ctx context.Context, sandboxId string) error {
+	sidecars, err := d.listSidecars(ctx, sandboxId)
+	if err != nil {
+		return err
+	}
+
+	for _, sidecar := range sidecars {
+		err := d.apiClient.ContainerRemove(ctx, sidecar.ID, container.RemoveOptions{
+			Force:         true,
+			RemoveVolumes: true,
+		})
+		if err != nil && !errdefs.IsNotFound(err) {
+			return fmt.Errorf("failed to remove sidecar %s: %w", sidecar.Labels[common.SIDECAR_NAME_LABEL], err)
+		}
+	}
+
+	return nil
+}
+
+type backupTarget struct {
+	containerId string
+	snapshot    string
+	options     *dto.CommitOptionsDTO
+}
+
+// getBackupTargets returns the containers a backup commits, the sandbox container first and then
+// the sidecars it has a snapshot for
+func (d *DockerClient) getBackupTargets(ctx context.Context, sandboxId string, backupDto dto.CreateBackupDTO) ([]backupTarget, error) {
+	targets := []backupTarget{{containerId: sandboxId, snapshot: backupDto.Snapshot, options: backupDto.CommitOptions}}
+	if len(backupDto.SidecarSnapshots) == 0 {
+		return targets, nil
+	}
+
+	sidecars, err := d.listSidecars(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	sidecarIds := make(map[string]string, len(sidecars))
+	for _, sidecar := range sidecars {
+		sidecarIds[sidecar.Labels[common.SIDECAR_NAME_LABEL]] = sidecar.ID
+	}
+
+	names := make([]string, 0, len(backupDto.SidecarSnapshots))
+	for name := range backupDto.SidecarSnapshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sidecarId, ok := sidecarIds[name]
+		if !ok {
+			return nil, common_errors.NewBadRequestError(fmt.Errorf("sandbox %s has no sidecar %s", sandboxId, name))
+		}
+		targets = append(targets, backupTarget{containerId: sidecarId, snapshot: backupDto.SidecarSnapshots[name]})
+	}
+
+	return targets, nil
+}
+
+// GetSandboxStats returns the resource usage of a sandbox container and its sidecars
+func (d *DockerClient) GetSandboxStats(ctx context.Context, sandboxId string) (*dto.SandboxStatsDTO, error) {
+	defer timer.Timer()()
+
+	if d.isMicroVM(sandboxId) {
+		return nil, errMicroVMUnsupported("stats")
+	}
+
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	sidecars, err := d.listSidecars(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &dto.SandboxStatsDTO{
+		Containers: make([]dto.SandboxContainerStatsDTO, 0, len(sidecars)+1),
+	}
+
+	sandboxStats, err := d.containerStats(ctx, c.ID, c.State != nil && c.State.Running, stats)
+	if err != nil {
+		return nil, err
+	}
+	stats.Containers = append(stats.Containers, *sandboxStats)
+
+	for _, sidecar := range sidecars {
+		sidecarStats, err := d.containerStats(ctx, sidecar.ID, sidecar.State == container.StateRunning, nil)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		sidecarStats.Sidecar = sidecar.Labels[common.SIDECAR_NAME_LABEL]
+		stats.Containers = append(stats.Containers, *sidecarStats)
+	}
+
+	for _, containerStats := range stats.Containers {
+		stats.CpuUsageNs += containerStats.CpuUsageNs
+		stats.MemoryUsageBytes += containerStats.MemoryUsageBytes
+	}
+
+	return stats, nil
+}
+
+// containerStats samples the usage of a running container. The network traffic of the sandbox is
+// added to totals if set, sidecars share the network namespace of the sandbox container.
+func (d *DockerClient) containerStats(ctx context.Context, containerId string, running bool, totals *dto.SandboxStatsDTO) (*dto.SandboxContainerStatsDTO, error) {
+	containerStats := &dto.SandboxContainerStatsDTO{Running: running}
+	if !running {
+		return containerStats, nil
+	}
+
+	reader, err := d.apiClient.ContainerStatsOneShot(ctx, containerId)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Body.Close()
+
+	var response container.StatsResponse
+	if err := json.NewDecoder(reader.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode stats of container %s: %w", containerId, err)
+	}
+
+	containerStats.CpuUsageNs = response.CPUStats.CPUUsage.TotalUsage
+	containerStats.MemoryUsageBytes = response.MemoryStats.Usage
+	containerStats.MemoryLimitBytes = response.MemoryStats.Limit
+
+	if totals != nil {
+		for _, network := range response.Networks {
+			totals.RxBytes += network.RxBytes
+			totals.TxBytes += network.TxBytes
+		}
+	}
+
+	return containerStats, nil
+}
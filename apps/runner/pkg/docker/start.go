@@ -6,11 +6,15 @@ package docker
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 	"time"
 
 	"github.com/daytonaio/common-go/pkg/timer"
+	"github.com/daytonaio/runner/internal"
+	"github.com/daytonaio/runner/pkg/api/dto"
 	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/faults"
 	"github.com/daytonaio/runner/pkg/models/enums"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/strslice"
@@ -28,26 +32,64 @@ func (d *DockerClient) Start(ctx context.Context, containerId string, metadata m
 		backup_context.cancel()
 	}
 
+	if err := faults.Inject(ctx, faults.PointDockerStart, containerId); err != nil {
+		return "", err
+	}
+
+	if d.isMicroVM(containerId) {
+		return d.startMicroVM(ctx, containerId, metadata)
+	}
+
 	c, err := d.ContainerInspect(ctx, containerId)
 	if err != nil {
 		return "", err
 	}
 
 	if c.State.Running {
-		containerIP := common.GetContainerIpAddress(ctx, c)
-		if containerIP == "" {
-			return "", errors.New("sandbox IP not found? Is the sandbox started?")
+		daemonUrl, err := d.GetDaemonUrl(ctx, c)
+		if err != nil {
+			return "", err
 		}
 
-		daemonVersion, err := d.waitForDaemonRunning(ctx, containerIP)
+		daemonVersion, err := d.waitForDaemonRunning(ctx, daemonUrl, d.GetDaemonAuthToken(ctx, c))
 		if err != nil {
 			return "", err
 		}
 
+		// Sandboxes that kept running across a runner upgrade still run the previous daemon
+		if d.daemonAutoUpgrade && daemonVersion != internal.Version {
+			go func() {
+				if _, err := d.UpgradeDaemon(context.Background(), containerId, dto.UpgradeDaemonDTO{}); err != nil {
+					log.Errorf("Failed to upgrade daemon of sandbox %s: %v", containerId, err)
+				}
+			}()
+		}
+
+		if err := d.startSidecars(ctx, containerId, false); err != nil {
+			return "", err
+		}
+
+		if err := d.mountBuckets(ctx, containerId); err != nil {
+			return "", err
+		}
+
 		d.statesCache.SetSandboxState(ctx, containerId, enums.SandboxStateStarted)
 		return daemonVersion, nil
 	}
 
+	// The socket directory is gone if the host was cleaned up while the sandbox was stopped
+	if c.Config != nil && c.Config.Labels[daemonSocketLabel] == "true" {
+		if _, err := d.getDaemonSocketBind(containerId); err != nil {
+			return "", err
+		}
+	}
+
+	// Buckets mounted once the container runs would show up in the sandbox too, but its processes
+	// may start using the mount paths before
+	if err := d.mountBuckets(ctx, containerId); err != nil {
+		return "", err
+	}
+
 	err = d.apiClient.ContainerStart(ctx, containerId, container.StartOptions{})
 	if err != nil {
 		return "", err
@@ -69,6 +111,41 @@ func (d *DockerClient) Start(ctx context.Context, containerId string, metadata m
 		return "", errors.New("sandbox IP not found? Is the sandbox started?")
 	}
 
+	if c.Config.Labels[common.EGRESS_ALLOWED_DOMAINS_LABEL] != "" && d.egressProxyPort != 0 {
+		err = d.netRulesManager.SetEgressProxyRedirect(c.ID[:12], containerIP, d.egressProxyPort)
+		if err != nil {
+			return "", fmt.Errorf("failed to redirect sandbox traffic to the egress proxy: %w", err)
+		}
+	}
+
+	if egressIp, ok := d.organizationEgressIps[c.Config.Labels["daytona.organization_id"]]; ok {
+		err = d.netRulesManager.SetEgressIp(c.ID[:12], containerIP, egressIp)
+		if err != nil {
+			return "", fmt.Errorf("failed to pin sandbox egress IP: %w", err)
+		}
+	}
+
+	if wireGuardConfig := c.Config.Labels[common.WIREGUARD_CONFIG_LABEL]; wireGuardConfig != "" {
+		tunnel, err := d.decryptWireGuardConfig(wireGuardConfig)
+		if err != nil {
+			return "", err
+		}
+		err = d.netRulesManager.SetWireGuardTunnel(c.ID[:12], containerIP, *tunnel)
+		if err != nil {
+			return "", fmt.Errorf("failed to attach sandbox to its WireGuard tunnel: %w", err)
+		}
+	}
+
+	// Sidecars join the network namespace of the sandbox container, so they start after it
+	if err := d.startSidecars(ctx, containerId, true); err != nil {
+		return "", err
+	}
+
+	daemonUrl, err := d.GetDaemonUrl(ctx, c)
+	if err != nil {
+		return "", err
+	}
+
 	if !slices.Equal(c.Config.Entrypoint, strslice.StrSlice{common.DAEMON_PATH}) {
 		processesCtx := context.Background()
 		go func() {
@@ -81,7 +158,7 @@ func (d *DockerClient) Start(ctx context.Context, containerId string, metadata m
 	// If daemon is the sandbox entrypoint (common.DAEMON_PATH), it is started as part of the sandbox;
 	// Otherwise, the daemon is started separately above.
 	// In either case, we wait for it here.
-	daemonVersion, err := d.waitForDaemonRunning(ctx, containerIP)
+	daemonVersion, err := d.waitForDaemonRunning(ctx, daemonUrl, d.GetDaemonAuthToken(ctx, c))
 	if err != nil {
 		return "", err
 	}
@@ -9,8 +9,12 @@ import (
 	"strings"
 
 	"github.com/containerd/errdefs"
+	"github.com/daytonaio/runner/pkg/common"
 	"github.com/daytonaio/runner/pkg/models/enums"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+
+	log "github.com/sirupsen/logrus"
 )
 
 func (d *DockerClient) DeduceSandboxState(ctx context.Context, sandboxId string) (enums.SandboxState, error) {
@@ -31,9 +35,12 @@ func (d *DockerClient) DeduceSandboxState(ctx context.Context, sandboxId string)
 		return enums.SandboxStateCreating, nil
 
 	case "running":
-		if d.isContainerPullingImage(container.ID) {
+		if d.isContainerPullingImage(ctx, container.ID) {
 			return enums.SandboxStatePullingSnapshot, nil
 		}
+		if container.Config.Labels[common.PROVISIONING_LABEL] != "" {
+			return d.provisioningState(ctx, sandboxId, container)
+		}
 		return enums.SandboxStateStarted, nil
 
 	case "paused":
@@ -61,14 +68,14 @@ func (d *DockerClient) DeduceSandboxState(ctx context.Context, sandboxId string)
 }
 
 // isContainerPullingImage checks if the container is still in image pulling phase
-func (d *DockerClient) isContainerPullingImage(containerId string) bool {
+func (d *DockerClient) isContainerPullingImage(ctx context.Context, containerId string) bool {
 	options := container.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Tail:       "10", // Look at last 10 lines
 	}
 
-	logs, err := d.apiClient.ContainerLogs(context.Background(), containerId, options)
+	logs, err := d.apiClient.ContainerLogs(ctx, containerId, options)
 	if err != nil {
 		return false
 	}
@@ -83,3 +90,48 @@ func (d *DockerClient) isContainerPullingImage(containerId string) bool {
 		strings.Contains(logContent, "Downloading") ||
 		strings.Contains(logContent, "Extracting")
 }
+
+// SyncSandboxState updates the cached state of a sandbox after an event of its container, e.g.
+// when it exited on its own because it crashed or ran out of memory
+func (d *DockerClient) SyncSandboxState(ctx context.Context, event events.Message) {
+	sandboxId := event.Actor.Attributes["name"]
+	if sandboxId == "" {
+		return
+	}
+	if _, ok := parseLeftoverContainerName(sandboxId); ok {
+		return
+	}
+	if !d.isSandboxEvent(ctx, event) {
+		return
+	}
+
+	state, err := d.DeduceSandboxState(ctx, sandboxId)
+	if err != nil {
+		log.Warnf("Sandbox %s is in state %s after %s event: %v", sandboxId, state, event.Action, err)
+	}
+
+	d.statesCache.SetSandboxState(ctx, sandboxId, state)
+}
+
+// isSandboxEvent returns whether a container event is of a sandbox container. Events carry the
+// labels of the container, containers of older sandboxes without the sandbox label are inspected.
+func (d *DockerClient) isSandboxEvent(ctx context.Context, event events.Message) bool {
+	if event.Actor.Attributes[common.SANDBOX_LABEL] != "" {
+		return true
+	}
+	if event.Actor.Attributes[common.SIDECAR_OF_LABEL] != "" {
+		return false
+	}
+
+	c, err := d.ContainerInspect(ctx, event.Actor.ID)
+	if err != nil {
+		// The container was removed, it was a sandbox if the runner knew its state
+		_, err = d.statesCache.Get(ctx, event.Actor.Attributes["name"])
+		return err == nil
+	}
+	if c.Config == nil {
+		return false
+	}
+
+	return isSandboxContainer(c.Config.Labels, c.Mounts)
+}
@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/daytonaio/common-go/pkg/utils"
 	"github.com/daytonaio/runner/pkg/models/enums"
 	"github.com/docker/docker/api/types/container"
 
@@ -36,6 +35,15 @@ func (d *DockerClient) Stop(ctx context.Context, containerId string) error {
 		backup_context.cancel()
 	}
 
+	if d.isMicroVM(containerId) {
+		return d.stopMicroVM(ctx, containerId)
+	}
+
+	err = d.stopSidecars(ctx, containerId)
+	if err != nil {
+		return err
+	}
+
 	err = d.stopContainerWithRetry(ctx, containerId, 2)
 	if err != nil {
 		return err
@@ -63,8 +71,8 @@ func (d *DockerClient) Stop(ctx context.Context, containerId string) error {
 }
 
 // stopContainerWithRetry attempts to stop the specified container by sending a stop signal,
-// retrying the operation with exponential backoff up to a maximum number of attempts.
-// If stopping fails after all retries, it falls back to forcefully killing the container.
+// retrying the operation with exponential backoff up to a maximum number of attempts while its
+// failures are retryable. If stopping fails, it falls back to forcefully killing the container.
 //
 // Parameters:
 //   - ctx: context for cancellation and timeout
@@ -74,12 +82,9 @@ func (d *DockerClient) Stop(ctx context.Context, containerId string) error {
 // Returns an error if the container could not be stopped or killed.
 func (d *DockerClient) stopContainerWithRetry(ctx context.Context, containerId string, timeout int) error {
 	// Use exponential backoff helper for container stopping
-	err := utils.RetryWithExponentialBackoff(
+	err := retry(
 		ctx,
 		fmt.Sprintf("stop sandbox %s", containerId),
-		utils.DEFAULT_MAX_RETRIES,
-		utils.DEFAULT_BASE_DELAY,
-		utils.DEFAULT_MAX_DELAY,
 		func() error {
 			return d.apiClient.ContainerStop(ctx, containerId, container.StopOptions{
 				Signal:  "SIGKILL",
@@ -88,7 +93,7 @@ func (d *DockerClient) stopContainerWithRetry(ctx context.Context, containerId s
 		},
 	)
 	if err != nil {
-		log.Warnf("Failed to stop sandbox %s for %d attempts: %v", containerId, utils.DEFAULT_MAX_RETRIES, err)
+		log.Warnf("Failed to stop sandbox %s: %v", containerId, err)
 		log.Warnf("Trying to kill sandbox %s", containerId)
 		err = d.apiClient.ContainerKill(ctx, containerId, "KILL")
 		if err != nil {
@@ -0,0 +1,74 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+// Containers are labeled with the storage quota they were created with in GB, as storage recovery
+// needs it to limit the expansion
+const storageQuotaLabel = "daytona.storage_quota"
+
+var errNoOriginalStorageQuota = errors.New("sandbox has no original storage quota label")
+
+// NotifyStoragePressure reports to the daemon of a sandbox that its storage usage reached a threshold
+func (d *DockerClient) NotifyStoragePressure(ctx context.Context, sandboxId string, event dto.StoragePressureEventDTO) error {
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return err
+	}
+
+	daemonUrl, err := d.GetDaemonUrl(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, daemonUrl+"/files/usage/pressure", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setDaemonAuthHeader(req, d.GetDaemonAuthToken(ctx, c))
+
+	resp, err := d.daemonHttpClient(5 * time.Second).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("daemon responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// GetOriginalStorageQuota returns the storage quota in GB the sandbox was created with
+func (d *DockerClient) GetOriginalStorageQuota(ctx context.Context, sandboxId string) (float64, error) {
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.Config == nil || c.Config.Labels[storageQuotaLabel] == "" {
+		return 0, errNoOriginalStorageQuota
+	}
+
+	return strconv.ParseFloat(c.Config.Labels[storageQuotaLabel], 64)
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/timer"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// Volumes are backed by object storage, so walking them is stopped after this many entries
+const maxVolumeUsageEntries = 100_000
+
+var errUsageLimitReached = errors.New("usage entry limit reached")
+
+// GetStorageUsage measures the space used by a sandbox. Files written in the sandbox are counted
+// from the overlay upper dir of its container, volumes from their mounts on the runner and data
+// volumes from their project quotas.
+func (d *DockerClient) GetStorageUsage(ctx context.Context, sandboxId string) (*dto.SandboxStorageUsageDTO, error) {
+	defer timer.Timer()()
+
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &dto.SandboxStorageUsageDTO{
+		Volumes:   []dto.VolumeStorageUsageDTO{},
+		SampledAt: time.Now(),
+	}
+
+	if c.HostConfig != nil && c.HostConfig.StorageOpt != nil {
+		storageGB, err := common.ParseStorageOptSizeGB(c.HostConfig.StorageOpt)
+		if err == nil {
+			usage.QuotaBytes = common.GBToBytes(storageGB)
+		}
+	}
+
+	if upperDir, ok := c.GraphDriver.Data["UpperDir"]; ok && c.GraphDriver.Name == "overlay2" {
+		usage.UsedBytes, _, err = dirUsage(ctx, upperDir, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	usage.NestedDockerBytes, err = d.getNestedDockerUsage(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	usage.UsedBytes += usage.NestedDockerBytes
+
+	volumesPath := filepath.Join(getVolumeMountBasePath(), volumeMountPrefix)
+	for _, m := range c.Mounts {
+		if m.Type != mount.TypeBind || !strings.HasPrefix(m.Source, volumesPath) {
+			continue
+		}
+
+		used, complete, err := dirUsage(ctx, m.Source, maxVolumeUsageEntries)
+		if err != nil {
+			return nil, err
+		}
+
+		usage.Volumes = append(usage.Volumes, dto.VolumeStorageUsageDTO{
+			MountPath: m.Destination,
+			UsedBytes: used,
+			Partial:   !complete,
+		})
+	}
+
+	// Data volumes are on the runner, their quota accounts for their usage if they have one
+	for _, m := range c.Mounts {
+		if m.Type != mount.TypeVolume || m.Source == "" {
+			continue
+		}
+
+		volumeUsage := dto.VolumeStorageUsageDTO{
+			MountPath: m.Destination,
+			Name:      m.Name,
+		}
+
+		if d.volumeQuotasEnabled {
+			used, quota, err := getVolumeQuota(ctx, m.Source, m.Name)
+			if err == nil && quota > 0 {
+				volumeUsage.UsedBytes = used
+				volumeUsage.QuotaBytes = quota
+				usage.Volumes = append(usage.Volumes, volumeUsage)
+				continue
+			}
+		}
+
+		used, _, err := dirUsage(ctx, m.Source, 0)
+		if err != nil {
+			return nil, err
+		}
+		volumeUsage.UsedBytes = used
+		usage.Volumes = append(usage.Volumes, volumeUsage)
+	}
+
+	return usage, nil
+}
+
+// dirUsage returns the disk space allocated to the files under path, like du. If maxEntries is
+// positive, the walk stops after as many entries and the usage is reported as incomplete.
+func dirUsage(ctx context.Context, path string, maxEntries int) (int64, bool, error) {
+	var used int64
+	entries := 0
+
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Files removed while walking
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		entries++
+		if maxEntries > 0 && entries > maxEntries {
+			return errUsageLimitReached
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			used += stat.Blocks * 512
+		} else {
+			used += info.Size()
+		}
+
+		return nil
+	})
+	if errors.Is(err, errUsageLimitReached) {
+		return used, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return used, true, nil
+}
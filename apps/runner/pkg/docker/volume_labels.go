@@ -0,0 +1,125 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Directory of the labels of volume mounts, next to the mounts themselves
+const volumeLabelsDir = ".daytona-volume-labels"
+
+// volumeMountLabel describes a volume mount directory. It is kept outside of the directory, which
+// holds the contents of the volume once mounted.
+type volumeMountLabel struct {
+	VolumeId string  `json:"volumeId"`
+	Subpath  *string `json:"subpath,omitempty"`
+	// The sandbox the volume was last mounted for
+	SandboxId string    `json:"sandboxId"`
+	MountedAt time.Time `json:"mountedAt"`
+}
+
+func volumeMountLabelPath(mountPath string) string {
+	return filepath.Join(getVolumeMountBasePath(), volumeLabelsDir, filepath.Base(mountPath)+".json")
+}
+
+func writeVolumeMountLabel(mountPath string, label volumeMountLabel) {
+	content, err := json.Marshal(label)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(volumeMountLabelPath(mountPath)), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(volumeMountLabelPath(mountPath), content, 0600)
+	}
+	if err != nil {
+		log.Warnf("Failed to label volume mount %s: %v", mountPath, err)
+	}
+}
+
+// readVolumeMountLabel returns the label of a volume mount, or one with the modification time of
+// the directory for mounts made before labels were written
+func readVolumeMountLabel(mountPath string) volumeMountLabel {
+	var label volumeMountLabel
+
+	content, err := os.ReadFile(volumeMountLabelPath(mountPath))
+	if err == nil {
+		err = json.Unmarshal(content, &label)
+	}
+	if err == nil {
+		return label
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		log.Warnf("Failed to read the label of volume mount %s: %v", mountPath, err)
+	}
+
+	if info, err := os.Stat(mountPath); err == nil {
+		label.MountedAt = info.ModTime()
+	}
+	return label
+}
+
+func removeVolumeMountLabel(mountPath string) {
+	err := os.Remove(volumeMountLabelPath(mountPath))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warnf("Failed to remove the label of volume mount %s: %v", mountPath, err)
+	}
+}
+
+// reserveVolumeMounts marks volume mounts as pending until the returned function is called, so
+// that cleanups don't remove them before the container of the sandbox that uses them is created
+func (d *DockerClient) reserveVolumeMounts(mountPaths []string) func() {
+	d.pendingVolumeMountsMutex.Lock()
+	defer d.pendingVolumeMountsMutex.Unlock()
+
+	for _, path := range mountPaths {
+		d.pendingVolumeMounts[normalizePath(path)]++
+	}
+
+	return func() {
+		d.pendingVolumeMountsMutex.Lock()
+		defer d.pendingVolumeMountsMutex.Unlock()
+
+		for _, path := range mountPaths {
+			path = normalizePath(path)
+			d.pendingVolumeMounts[path]--
+			if d.pendingVolumeMounts[path] <= 0 {
+				delete(d.pendingVolumeMounts, path)
+			}
+			d.volumeMountsReleasedAt[path] = time.Now()
+		}
+	}
+}
+
+// isVolumeMountPending returns whether a volume mount is reserved for a sandbox, or was within the
+// grace period, in case a cleanup listed the containers before the container of the sandbox existed
+func (d *DockerClient) isVolumeMountPending(mountPath string) bool {
+	d.pendingVolumeMountsMutex.Lock()
+	defer d.pendingVolumeMountsMutex.Unlock()
+
+	return d.isVolumeMountPendingLocked(mountPath)
+}
+
+func (d *DockerClient) isVolumeMountPendingLocked(mountPath string) bool {
+	path := normalizePath(mountPath)
+	if d.pendingVolumeMounts[path] > 0 {
+		return true
+	}
+
+	releasedAt, ok := d.volumeMountsReleasedAt[path]
+	if !ok {
+		return false
+	}
+	if time.Since(releasedAt) >= d.volumeCleanupGracePeriod {
+		delete(d.volumeMountsReleasedAt, path)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os/exec"
+	"strings"
+
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/mount"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Docker assigns the project IDs of container layer quotas sequentially from the ID of its data
+// directory. Data volume quotas use IDs derived from the volume name above this base instead.
+const volumeProjectIdBase = 1 << 30
+
+func volumeProjectId(volumeName string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(volumeName))
+	return volumeProjectIdBase + hash.Sum32()%volumeProjectIdBase
+}
+
+// applyVolumeQuotas limits each data volume of a sandbox to the storage quota of the sandbox with
+// an XFS project quota. Data volumes, e.g. the writable paths of read-only root filesystems and
+// the VOLUME paths of images, aren't part of the container layer the storage quota applies to.
+func (d *DockerClient) applyVolumeQuotas(ctx context.Context, sandboxId string) error {
+	if !d.volumeQuotasEnabled {
+		return nil
+	}
+
+	// Project quotas need XFS, like the storage quotas of container layers
+	info, err := d.apiClient.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get docker info: %w", err)
+	}
+	if d.getFilesystem(info) != "xfs" {
+		return nil
+	}
+
+	c, err := d.ContainerInspect(ctx, sandboxId)
+	if err != nil {
+		return err
+	}
+
+	if c.HostConfig == nil || c.HostConfig.StorageOpt == nil {
+		return nil
+	}
+	storageGB, err := common.ParseStorageOptSizeGB(c.HostConfig.StorageOpt)
+	if err != nil {
+		return err
+	}
+	quotaBytes := common.GBToBytes(storageGB)
+
+	for _, m := range c.Mounts {
+		if m.Type != mount.TypeVolume || m.Driver != "local" || m.Source == "" {
+			continue
+		}
+
+		err = setProjectQuota(ctx, m.Source, volumeProjectId(m.Name), quotaBytes)
+		if err != nil {
+			return fmt.Errorf("failed to limit volume %s: %w", m.Name, err)
+		}
+		log.Debugf("Limited volume %s of sandbox %s to %d bytes", m.Name, sandboxId, quotaBytes)
+	}
+
+	return nil
+}
+
+// getVolumeQuota returns the space used by a data volume and the limit of its project quota, as
+// accounted by the quota. The limit is 0 if the volume has no quota.
+func getVolumeQuota(ctx context.Context, path, volumeName string) (int64, int64, error) {
+	mountPoint, err := filesystemMountPoint(ctx, path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	output, err := xfsQuota(ctx, mountPoint, fmt.Sprintf("quota -p -N -b %d", volumeProjectId(volumeName)))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// <filesystem> <used KiB> <soft limit KiB> <hard limit KiB> <warnings> <grace> <mount point>
+	fields := strings.Fields(output)
+	if len(fields) < 4 {
+		return 0, 0, nil
+	}
+
+	var usedKiB, hardKiB int64
+	_, err = fmt.Sscan(fields[1], &usedKiB)
+	if err == nil {
+		_, err = fmt.Sscan(fields[3], &hardKiB)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse quota %q: %w", output, err)
+	}
+
+	return usedKiB * 1024, hardKiB * 1024, nil
+}
+
+// setProjectQuota assigns a directory and its contents to a project, new files inherit it, and
+// limits the space the project may use
+func setProjectQuota(ctx context.Context, path string, projectId uint32, limitBytes int64) error {
+	mountPoint, err := filesystemMountPoint(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	_, err = xfsQuota(ctx, mountPoint, fmt.Sprintf("project -s -p %s %d", path, projectId))
+	if err != nil {
+		return err
+	}
+
+	_, err = xfsQuota(ctx, mountPoint, fmt.Sprintf("limit -p bhard=%d %d", limitBytes, projectId))
+	return err
+}
+
+func xfsQuota(ctx context.Context, mountPoint, command string) (string, error) {
+	output, err := exec.CommandContext(ctx, "xfs_quota", "-x", "-c", command, mountPoint).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("xfs_quota %q failed: %w: %s", command, err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func filesystemMountPoint(ctx context.Context, path string) (string, error) {
+	output, err := exec.CommandContext(ctx, "findmnt", "-n", "-o", "TARGET", "--target", path).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the filesystem of %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
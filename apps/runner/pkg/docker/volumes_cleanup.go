@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/daytonaio/runner/pkg/api/dto"
 	"github.com/docker/docker/api/types/container"
 	log "github.com/sirupsen/logrus"
 )
@@ -35,27 +36,94 @@ func (d *DockerClient) CleanupOrphanedVolumeMounts(ctx context.Context) {
 	dryRun := d.volumeCleanupDryRun
 	log.Infof("Volume cleanup dry-run: %v", dryRun)
 
+	orphaned, err := d.FindOrphanedVolumeMounts(ctx)
+	if err != nil {
+		log.Errorf("Volume cleanup aborted: %v", err)
+		return
+	}
+
+	for _, dir := range orphaned {
+		if dryRun {
+			log.Infof("[DRY-RUN] Would clean orphaned volume mount: %s", dir)
+		} else {
+			log.Infof("Cleaning orphaned volume mount: %s", dir)
+			d.unmountAndRemoveDir(dir)
+		}
+	}
+}
+
+// FindOrphanedVolumeMounts returns the volume mount directories no container uses, that aren't
+// reserved for a sandbox being created and were mounted longer than the grace period ago
+func (d *DockerClient) FindOrphanedVolumeMounts(ctx context.Context) ([]string, error) {
+	plan, err := d.PlanVolumeMountCleanup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphaned []string
+	for _, mount := range plan.Mounts {
+		if mount.Action == dto.VolumeMountActionRemove {
+			orphaned = append(orphaned, mount.Path)
+		}
+	}
+
+	return orphaned, nil
+}
+
+// PlanVolumeMountCleanup returns the volume mount directories with what the next cleanup would
+// do with them and why, without changing anything
+func (d *DockerClient) PlanVolumeMountCleanup(ctx context.Context) (dto.VolumeMountCleanupDTO, error) {
+	plan := dto.VolumeMountCleanupDTO{
+		DryRun:             d.volumeCleanupDryRun,
+		GracePeriodSeconds: int64(d.volumeCleanupGracePeriod.Seconds()),
+		Mounts:             []dto.VolumeMountDTO{},
+		GeneratedAt:        time.Now(),
+	}
+
 	mountDirs, err := filepath.Glob(filepath.Join(getVolumeMountBasePath(), volumeMountPrefix+"*"))
 	if err != nil || len(mountDirs) == 0 {
-		return
+		return plan, nil
 	}
 
 	inUse, err := d.getInUseVolumeMounts(ctx)
 	if err != nil {
-		log.Errorf("Volume cleanup aborted: %v", err)
-		return
+		return plan, err
 	}
 
 	for _, dir := range mountDirs {
-		if !inUse[normalizePath(dir)] {
-			if dryRun {
-				log.Infof("[DRY-RUN] Would clean orphaned volume mount: %s", dir)
-			} else {
-				log.Infof("Cleaning orphaned volume mount: %s", dir)
-				d.unmountAndRemoveDir(dir)
-			}
+		label := readVolumeMountLabel(dir)
+		mount := dto.VolumeMountDTO{
+			Path:      dir,
+			VolumeId:  label.VolumeId,
+			Subpath:   label.Subpath,
+			SandboxId: label.SandboxId,
+			Action:    dto.VolumeMountActionKeep,
 		}
+		if !label.MountedAt.IsZero() {
+			mount.MountedAt = &label.MountedAt
+		}
+
+		switch {
+		case inUse[normalizePath(dir)]:
+			mount.Reason = "used by a container"
+		case d.isVolumeMountPending(dir):
+			mount.Reason = "reserved for a sandbox being created"
+		case time.Since(label.MountedAt) < d.volumeCleanupGracePeriod:
+			mount.Reason = "mounted within the grace period"
+		default:
+			mount.Action = dto.VolumeMountActionRemove
+			mount.Reason = "no container uses it"
+		}
+
+		plan.Mounts = append(plan.Mounts, mount)
 	}
+
+	return plan, nil
+}
+
+// RemoveVolumeMount unmounts and removes a volume mount directory
+func (d *DockerClient) RemoveVolumeMount(dir string) {
+	d.unmountAndRemoveDir(dir)
 }
 
 func (d *DockerClient) getInUseVolumeMounts(ctx context.Context) (map[string]bool, error) {
@@ -87,6 +155,15 @@ func (d *DockerClient) unmountAndRemoveDir(path string) {
 		return
 	}
 
+	// Sandboxes reserve their mounts under the same lock, a mount can't be reserved while it is removed
+	d.pendingVolumeMountsMutex.Lock()
+	defer d.pendingVolumeMountsMutex.Unlock()
+
+	if d.isVolumeMountPendingLocked(cleanPath) {
+		log.Infof("Skipping removal of %s, it is reserved for a sandbox being created", cleanPath)
+		return
+	}
+
 	if d.isDirectoryMounted(cleanPath) {
 		if err := exec.Command("umount", cleanPath).Run(); err != nil {
 			log.Errorf("Failed to unmount %s: %v", cleanPath, err)
@@ -96,5 +173,8 @@ func (d *DockerClient) unmountAndRemoveDir(path string) {
 
 	if err := os.RemoveAll(cleanPath); err != nil {
 		log.Errorf("Failed to remove %s: %v", cleanPath, err)
+		return
 	}
+
+	removeVolumeMountLabel(cleanPath)
 }
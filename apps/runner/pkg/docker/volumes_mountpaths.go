@@ -31,9 +31,22 @@ func getVolumeMountBasePath() string {
 	return "/mnt"
 }
 
-func (d *DockerClient) getVolumesMountPathBinds(ctx context.Context, volumes []dto.VolumeDTO) ([]string, error) {
+// getVolumesMountPathBinds mounts the volumes of a sandbox and returns their binds. The mounts are
+// reserved for the sandbox until release is called, after its container is created.
+func (d *DockerClient) getVolumesMountPathBinds(ctx context.Context, sandboxId string, volumes []dto.VolumeDTO) (binds []string, release func(), err error) {
 	volumeMountPathBinds := make([]string, 0)
 
+	mountPaths := make([]string, 0, len(volumes))
+	for _, vol := range volumes {
+		mountPaths = append(mountPaths, d.getRunnerVolumeMountPath(volumeMountPrefix+vol.VolumeId, vol.Subpath))
+	}
+	release = d.reserveVolumeMounts(mountPaths)
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
 	for _, vol := range volumes {
 		volumeIdPrefixed := fmt.Sprintf("%s%s", volumeMountPrefix, vol.VolumeId)
 		runnerVolumeMountPath := d.getRunnerVolumeMountPath(volumeIdPrefixed, vol.Subpath)
@@ -67,7 +80,7 @@ func (d *DockerClient) getVolumesMountPathBinds(ctx context.Context, volumes []d
 
 		err := os.MkdirAll(runnerVolumeMountPath, 0755)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create mount directory %s: %s", runnerVolumeMountPath, err)
+			return nil, nil, fmt.Errorf("failed to create mount directory %s: %s", runnerVolumeMountPath, err)
 		}
 
 		log.Infof("mounting S3 volume %s (subpath: %s) to %s", volumeIdPrefixed, subpathStr, runnerVolumeMountPath)
@@ -75,21 +88,28 @@ func (d *DockerClient) getVolumesMountPathBinds(ctx context.Context, volumes []d
 		cmd := d.getMountCmd(ctx, volumeIdPrefixed, vol.Subpath, runnerVolumeMountPath)
 		err = cmd.Run()
 		if err != nil {
-			return nil, fmt.Errorf("failed to mount S3 volume %s (subpath: %s) to %s: %s", volumeIdPrefixed, subpathStr, runnerVolumeMountPath, err)
+			return nil, nil, fmt.Errorf("failed to mount S3 volume %s (subpath: %s) to %s: %s", volumeIdPrefixed, subpathStr, runnerVolumeMountPath, err)
 		}
 
 		// Wait for FUSE mount to be fully ready before proceeding
 		err = d.waitForMountReady(ctx, runnerVolumeMountPath)
 		if err != nil {
-			return nil, fmt.Errorf("mount %s not ready after mounting: %s", runnerVolumeMountPath, err)
+			return nil, nil, fmt.Errorf("mount %s not ready after mounting: %s", runnerVolumeMountPath, err)
 		}
 
 		log.Infof("mounted S3 volume %s (subpath: %s) to %s", volumeIdPrefixed, subpathStr, runnerVolumeMountPath)
 
+		writeVolumeMountLabel(runnerVolumeMountPath, volumeMountLabel{
+			VolumeId:  vol.VolumeId,
+			Subpath:   vol.Subpath,
+			SandboxId: sandboxId,
+			MountedAt: time.Now(),
+		})
+
 		volumeMountPathBinds = append(volumeMountPathBinds, fmt.Sprintf("%s/:%s/", runnerVolumeMountPath, vol.MountPath))
 	}
 
-	return volumeMountPathBinds, nil
+	return volumeMountPathBinds, release, nil
 }
 
 func (d *DockerClient) getRunnerVolumeMountPath(volumeId string, subpath *string) string {
@@ -178,6 +198,9 @@ func (d *DockerClient) getMountCmd(ctx context.Context, volume string, subpath *
 		cmd.Env = append(cmd.Env, "AWS_ENDPOINT_URL="+d.awsEndpointUrl)
 	}
 
+	d.awsCredentialsMutex.RLock()
+	defer d.awsCredentialsMutex.RUnlock()
+
 	if d.awsAccessKeyId != "" {
 		cmd.Env = append(cmd.Env, "AWS_ACCESS_KEY_ID="+d.awsAccessKeyId)
 	}
@@ -0,0 +1,55 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/netrules"
+)
+
+// encryptWireGuardConfig encrypts the tunnel config of a sandbox with the runner WireGuard key, so
+// the keys of the tunnel aren't readable from the container labels
+func (d *DockerClient) encryptWireGuardConfig(config dto.WireGuardConfigDTO) (string, error) {
+	if len(d.wireGuardKey) == 0 {
+		return "", errors.New("WireGuard tunnels are not enabled on the runner")
+	}
+
+	plaintext, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	return common.EncryptLabel(d.wireGuardKey, plaintext)
+}
+
+func (d *DockerClient) decryptWireGuardConfig(encrypted string) (*netrules.WireGuardTunnel, error) {
+	if len(d.wireGuardKey) == 0 {
+		return nil, errors.New("WireGuard tunnels are not enabled on the runner")
+	}
+
+	plaintext, err := common.DecryptLabel(d.wireGuardKey, encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt WireGuard config: %w", err)
+	}
+
+	var config dto.WireGuardConfigDTO
+	if err := json.Unmarshal(plaintext, &config); err != nil {
+		return nil, err
+	}
+
+	return &netrules.WireGuardTunnel{
+		PrivateKey:             config.PrivateKey,
+		Address:                config.Address,
+		PeerPublicKey:          config.PeerPublicKey,
+		PresharedKey:           config.PresharedKey,
+		PeerEndpoint:           config.PeerEndpoint,
+		AllowedIps:             config.AllowedIps,
+		PersistentKeepaliveSec: config.PersistentKeepaliveSec,
+	}, nil
+}
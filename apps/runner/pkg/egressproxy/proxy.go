@@ -0,0 +1,423 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package egressproxy implements the proxy the HTTP and HTTPS traffic of sandboxes with an
+// egress policy is redirected to. Connections are only let through to the allowed domains of the
+// sandbox, either directly or through the upstream proxy of the sandbox. HTTPS traffic is not
+// decrypted, its domain is taken from the TLS server name.
+package egressproxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	cmap "github.com/orcaman/concurrent-map/v2"
+	"golang.org/x/net/http/httpguts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	dialTimeout      = 10 * time.Second
+	handshakeTimeout = 10 * time.Second
+	// How long the egress policy of a sandbox address is cached for
+	policyTTL = 30 * time.Second
+	// TLS records start with this content type for handshake messages
+	tlsHandshakeRecordType = 0x16
+)
+
+type Config struct {
+	Port      int
+	ApiClient client.APIClient
+	// Decrypts the credentials of upstream proxies kept in container labels
+	WireGuardKey []byte
+}
+
+type egressPolicy struct {
+	sandboxId      string
+	allowedDomains []string
+	upstreamProxy  *url.URL
+}
+
+type cachedPolicy struct {
+	policy    *egressPolicy
+	expiresAt time.Time
+}
+
+type Proxy struct {
+	config   Config
+	policies cmap.ConcurrentMap[string, cachedPolicy]
+	traffic  cmap.ConcurrentMap[string, *sandboxTraffic]
+}
+
+func NewProxy(config Config) *Proxy {
+	return &Proxy{
+		config:   config,
+		policies: cmap.New[cachedPolicy](),
+		traffic:  cmap.New[*sandboxTraffic](),
+	}
+}
+
+// Start accepts redirected connections until the context is canceled
+func (p *Proxy) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", p.config.Port))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	go p.pruneTraffic(ctx)
+
+	log.Infof("Egress proxy listening on port %d", p.config.Port)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+
+		go p.handle(ctx, conn)
+	}
+}
+
+func (p *Proxy) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	policy := p.getPolicy(ctx, conn.RemoteAddr())
+	if policy == nil {
+		return
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	reader := bufio.NewReader(conn)
+
+	first, err := reader.Peek(1)
+	if err != nil {
+		return
+	}
+
+	if first[0] == tlsHandshakeRecordType {
+		p.handleTLS(ctx, conn, reader, policy)
+	} else {
+		p.handleHTTP(ctx, conn, reader, policy)
+	}
+}
+
+func (p *Proxy) handleTLS(ctx context.Context, conn net.Conn, reader *bufio.Reader, policy *egressPolicy) {
+	var clientHello bytes.Buffer
+	serverName, err := readServerName(io.TeeReader(reader, &clientHello))
+	if err != nil {
+		log.Debugf("Rejected TLS connection from sandbox %s: %v", policy.sandboxId, err)
+		return
+	}
+
+	if !common.IsDomainAllowed(serverName, policy.allowedDomains) {
+		log.Debugf("Rejected HTTPS connection from sandbox %s to %s", policy.sandboxId, serverName)
+		return
+	}
+
+	upstream, upstreamReader, err := p.dialTunnel(ctx, policy, net.JoinHostPort(serverName, "443"))
+	if err != nil {
+		log.Debugf("Failed to connect sandbox %s to %s: %v", policy.sandboxId, serverName, err)
+		return
+	}
+	defer upstream.Close()
+
+	traffic := p.domainTraffic(policy.sandboxId, serverName)
+	_ = conn.SetReadDeadline(time.Time{})
+
+	if _, err := upstream.Write(clientHello.Bytes()); err != nil {
+		return
+	}
+	traffic.SentBytes.Add(int64(clientHello.Len()))
+
+	splice(conn, reader, upstream, upstreamReader, traffic)
+}
+
+// handleHTTP forwards a single request, so every request on a connection is checked against the
+// allowed domains. Connections are closed after the response, except for protocol upgrades.
+func (p *Proxy) handleHTTP(ctx context.Context, conn net.Conn, reader *bufio.Reader, policy *egressPolicy) {
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		return
+	}
+	// The body is streamed to the upstream, which takes as long as the upload
+	_ = conn.SetReadDeadline(time.Time{})
+
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if host == "" || !common.IsDomainAllowed(host, policy.allowedDomains) {
+		log.Debugf("Rejected HTTP request from sandbox %s to %s", policy.sandboxId, host)
+		writeForbidden(conn, host)
+		return
+	}
+
+	address := req.Host
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "80")
+	}
+
+	upgrade := httpguts.HeaderValuesContainsToken(req.Header["Connection"], "upgrade")
+	if !upgrade {
+		req.Close = true
+	}
+
+	traffic := p.domainTraffic(policy.sandboxId, host)
+	var upstream net.Conn
+	if policy.upstreamProxy != nil {
+		upstream, err = dial(ctx, policy.upstreamProxy.Host)
+		if err != nil {
+			log.Debugf("Failed to connect sandbox %s to its upstream proxy: %v", policy.sandboxId, err)
+			return
+		}
+		defer upstream.Close()
+
+		req.URL.Scheme = "http"
+		req.URL.Host = req.Host
+		if auth := proxyAuthorization(policy.upstreamProxy); auth != "" {
+			req.Header.Set("Proxy-Authorization", auth)
+		}
+		err = req.WriteProxy(&countingWriter{w: upstream, count: &traffic.SentBytes})
+	} else {
+		upstream, err = dial(ctx, address)
+		if err != nil {
+			log.Debugf("Failed to connect sandbox %s to %s: %v", policy.sandboxId, address, err)
+			return
+		}
+		defer upstream.Close()
+
+		err = req.Write(&countingWriter{w: upstream, count: &traffic.SentBytes})
+	}
+	if err != nil {
+		return
+	}
+
+	upstreamReader := bufio.NewReader(upstream)
+	resp, err := http.ReadResponse(upstreamReader, req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if upgrade && resp.StatusCode == http.StatusSwitchingProtocols {
+		if err := resp.Write(&countingWriter{w: conn, count: &traffic.ReceivedBytes}); err != nil {
+			return
+		}
+		// The connection no longer carries HTTP once the protocol is switched
+		splice(conn, reader, upstream, upstreamReader, traffic)
+		return
+	}
+
+	resp.Close = true
+	_ = resp.Write(&countingWriter{w: conn, count: &traffic.ReceivedBytes})
+}
+
+// dialTunnel connects to an address directly or through a CONNECT tunnel of the upstream proxy
+func (p *Proxy) dialTunnel(ctx context.Context, policy *egressPolicy, address string) (net.Conn, io.Reader, error) {
+	if policy.upstreamProxy == nil {
+		conn, err := dial(ctx, address)
+		return conn, conn, err
+	}
+
+	conn, err := dial(ctx, policy.upstreamProxy.Host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if auth := proxyAuthorization(policy.upstreamProxy); auth != "" {
+		req.Header.Set("Proxy-Authorization", auth)
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, nil, fmt.Errorf("upstream proxy responded with %s", resp.Status)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	return conn, reader, nil
+}
+
+// dial connects to an address unless it resolves to a loopback or link-local address, which
+// would let sandboxes reach services of the runner host or cloud metadata endpoints
+func dial(ctx context.Context, address string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isBlockedIP(ip) {
+				return fmt.Errorf("connections to %s are not allowed", host)
+			}
+			return nil
+		},
+	}
+
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+func isBlockedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// getPolicy returns the egress policy of the sandbox with the source address, or nil if the
+// address doesn't belong to a sandbox with an egress policy
+func (p *Proxy) getPolicy(ctx context.Context, source net.Addr) *egressPolicy {
+	host, _, err := net.SplitHostPort(source.String())
+	if err != nil {
+		return nil
+	}
+
+	if cached, ok := p.policies.Get(host); ok && time.Now().Before(cached.expiresAt) {
+		return cached.policy
+	}
+
+	c, err := common.FindContainerByIpAddress(ctx, p.config.ApiClient, host, common.EGRESS_ALLOWED_DOMAINS_LABEL)
+	if err != nil {
+		log.Warnf("Failed to look up the egress policy of %s: %v", host, err)
+		return nil
+	}
+
+	var policy *egressPolicy
+	if c != nil {
+		policy = &egressPolicy{
+			sandboxId:      getSandboxId(c),
+			allowedDomains: common.ParseDomainList(c.Labels[common.EGRESS_ALLOWED_DOMAINS_LABEL]),
+		}
+
+		policy.upstreamProxy, err = p.getUpstreamProxy(c)
+		if err != nil {
+			log.Warnf("Invalid upstream proxy of sandbox %s: %v", policy.sandboxId, err)
+			return nil
+		}
+	}
+
+	p.policies.Set(host, cachedPolicy{policy: policy, expiresAt: time.Now().Add(policyTTL)})
+	return policy
+}
+
+// getUpstreamProxy returns the upstream proxy of a container, with its credentials if they are
+// kept in the encrypted label
+func (p *Proxy) getUpstreamProxy(c *container.Summary) (*url.URL, error) {
+	upstreamProxy := c.Labels[common.EGRESS_UPSTREAM_PROXY_LABEL]
+	if encrypted := c.Labels[common.EGRESS_UPSTREAM_PROXY_ENCRYPTED_LABEL]; encrypted != "" {
+		if len(p.config.WireGuardKey) == 0 {
+			return nil, errors.New("the WireGuard key of the runner is required to decrypt the credentials")
+		}
+		plaintext, err := common.DecryptLabel(p.config.WireGuardKey, encrypted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt upstream proxy: %w", err)
+		}
+		upstreamProxy = string(plaintext)
+	}
+
+	if upstreamProxy == "" {
+		return nil, nil
+	}
+
+	return url.Parse(upstreamProxy)
+}
+
+func getSandboxId(c *container.Summary) string {
+	if len(c.Names) > 0 {
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+	return c.ID
+}
+
+func proxyAuthorization(proxyUrl *url.URL) string {
+	if proxyUrl.User == nil {
+		return ""
+	}
+
+	password, _ := proxyUrl.User.Password()
+	credentials := proxyUrl.User.Username() + ":" + password
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+}
+
+func writeForbidden(conn net.Conn, host string) {
+	body := fmt.Sprintf("egress to %s is not allowed\n", host)
+	resp := &http.Response{
+		StatusCode:    http.StatusForbidden,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"text/plain"}},
+		ContentLength: int64(len(body)),
+		Body:          io.NopCloser(strings.NewReader(body)),
+		Close:         true,
+	}
+	_ = resp.Write(conn)
+}
+
+// splice copies data in both directions until either side closes the connection
+func splice(client net.Conn, clientReader io.Reader, upstream net.Conn, upstreamReader io.Reader, traffic *DomainTraffic) {
+	done := make(chan struct{}, 2)
+
+	go func() {
+		_, _ = io.Copy(&countingWriter{w: upstream, count: &traffic.SentBytes}, clientReader)
+		closeWrite(upstream)
+		done <- struct{}{}
+	}()
+
+	go func() {
+		_, _ = io.Copy(&countingWriter{w: client, count: &traffic.ReceivedBytes}, upstreamReader)
+		closeWrite(client)
+		done <- struct{}{}
+	}()
+
+	<-done
+	<-done
+}
+
+func closeWrite(conn net.Conn) {
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.CloseWrite()
+		return
+	}
+	_ = conn.Close()
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package egressproxy
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DomainTraffic counts the bytes a sandbox exchanged with a domain through the proxy
+type DomainTraffic struct {
+	SentBytes     atomic.Int64
+	ReceivedBytes atomic.Int64
+}
+
+type sandboxTraffic struct {
+	mu      sync.Mutex
+	domains map[string]*DomainTraffic
+}
+
+func (p *Proxy) domainTraffic(sandboxId, domain string) *DomainTraffic {
+	sandbox := p.traffic.Upsert(sandboxId, nil, func(exists bool, valueInMap, _ *sandboxTraffic) *sandboxTraffic {
+		if exists {
+			return valueInMap
+		}
+		return &sandboxTraffic{domains: make(map[string]*DomainTraffic)}
+	})
+
+	sandbox.mu.Lock()
+	defer sandbox.mu.Unlock()
+
+	domain = strings.ToLower(domain)
+	traffic, ok := sandbox.domains[domain]
+	if !ok {
+		traffic = &DomainTraffic{}
+		sandbox.domains[domain] = traffic
+	}
+
+	return traffic
+}
+
+// DomainTrafficStats is a snapshot of the traffic of a sandbox to a domain
+type DomainTrafficStats struct {
+	Domain        string
+	SentBytes     int64
+	ReceivedBytes int64
+}
+
+// GetTraffic returns the bytes sent and received by a sandbox through the proxy per domain
+func (p *Proxy) GetTraffic(sandboxId string) []DomainTrafficStats {
+	sandbox, ok := p.traffic.Get(sandboxId)
+	if !ok {
+		return []DomainTrafficStats{}
+	}
+
+	sandbox.mu.Lock()
+	defer sandbox.mu.Unlock()
+
+	stats := make([]DomainTrafficStats, 0, len(sandbox.domains))
+	for domain, traffic := range sandbox.domains {
+		stats = append(stats, DomainTrafficStats{
+			Domain:        domain,
+			SentBytes:     traffic.SentBytes.Load(),
+			ReceivedBytes: traffic.ReceivedBytes.Load(),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Domain < stats[j].Domain
+	})
+
+	return stats
+}
+
+// pruneTraffic periodically drops the traffic of sandboxes that no longer exist
+func (p *Proxy) pruneTraffic(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		containers, err := p.config.ApiClient.ContainerList(ctx, container.ListOptions{All: true})
+		if err != nil {
+			log.Warnf("Failed to list sandboxes to prune egress traffic: %v", err)
+			continue
+		}
+
+		existing := make(map[string]bool, len(containers))
+		for _, c := range containers {
+			existing[getSandboxId(&c)] = true
+		}
+
+		for _, sandboxId := range p.traffic.Keys() {
+			if !existing[sandboxId] {
+				p.traffic.Remove(sandboxId)
+			}
+		}
+	}
+}
+
+type countingWriter struct {
+	w     io.Writer
+	count *atomic.Int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.count.Add(int64(n))
+	return n, err
+}
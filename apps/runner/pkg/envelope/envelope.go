@@ -0,0 +1,137 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package envelope decrypts sandbox secrets the control plane encrypts to the public key of the
+// runner. Secrets are encrypted with a random AES-256-GCM data key, which is encrypted with
+// RSA-OAEP (SHA-256) to the runner key, so only the runner the sandbox is scheduled on can read them.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const keyBits = 3072
+
+type Decrypter struct {
+	privateKey *rsa.PrivateKey
+}
+
+// LoadOrCreateKey loads the runner key from a PEM file, generating it on first use
+func LoadOrCreateKey(path string) (*Decrypter, error) {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		return parseKey(content)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read envelope key: %w", err)
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to save envelope key: %w", err)
+	}
+
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save envelope key: %w", err)
+	}
+
+	log.Infof("Generated envelope key %s", path)
+	return &Decrypter{privateKey: privateKey}, nil
+}
+
+func parseKey(content []byte) (*Decrypter, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("envelope key is not PEM encoded")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse envelope key: %w", err)
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("envelope key is not an RSA key")
+	}
+
+	return &Decrypter{privateKey: privateKey}, nil
+}
+
+// PublicKey returns the PEM encoded public key secrets are encrypted to
+func (d *Decrypter) PublicKey() (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(&d.privateKey.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// Decrypt decrypts an envelope of base64 encoded parts. Errors don't include any of the plaintext.
+func (d *Decrypter) Decrypt(encryptedKey, nonce, ciphertext string) ([]byte, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(encryptedKey)
+	if err != nil {
+		return nil, errors.New("encrypted key is not base64 encoded")
+	}
+
+	nonceBytes, err := base64.StdEncoding.DecodeString(nonce)
+	if err != nil {
+		return nil, errors.New("nonce is not base64 encoded")
+	}
+
+	ciphertextBytes, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, errors.New("ciphertext is not base64 encoded")
+	}
+
+	dataKey, err := rsa.DecryptOAEP(sha256.New(), nil, d.privateKey, keyBytes, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt the data key, it may be encrypted to another runner")
+	}
+
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, errors.New("invalid data key")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonceBytes) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce size")
+	}
+
+	plaintext, err := gcm.Open(nil, nonceBytes, ciphertextBytes, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt the envelope")
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type envelope struct {
+	encryptedKey, nonce, ciphertext string
+}
+
+// seal encrypts plaintext to the public key the way the control plane does
+func seal(t *testing.T, publicKeyPem string, plaintext []byte) envelope {
+	t.Helper()
+
+	block, _ := pem.Decode([]byte(publicKeyPem))
+	if block == nil {
+		t.Fatal("public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataKey := make([]byte, 32)
+	nonce := make([]byte, 12)
+	_, _ = rand.Read(dataKey)
+	_, _ = rand.Read(nonce)
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key.(*rsa.PublicKey), dataKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aesBlock, _ := aes.NewCipher(dataKey)
+	gcm, _ := cipher.NewGCM(aesBlock)
+
+	return envelope{
+		encryptedKey: base64.StdEncoding.EncodeToString(encryptedKey),
+		nonce:        base64.StdEncoding.EncodeToString(nonce),
+		ciphertext:   base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "envelope.pem")
+	decrypter, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherDecrypter, err := LoadOrCreateKey(filepath.Join(t.TempDir(), "other.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := decrypter.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPublicKey, err := otherDecrypter.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte(`{"API_KEY":"secret"}`)
+	valid := seal(t, publicKey, plaintext)
+	tampered := valid
+	tampered.ciphertext = seal(t, publicKey, []byte(`{"API_KEY":"other"}`)).ciphertext
+
+	tests := []struct {
+		name     string
+		envelope envelope
+		err      string
+	}{
+		{name: "valid envelope", envelope: valid},
+		{name: "encrypted to another runner", envelope: seal(t, otherPublicKey, plaintext), err: "encrypted to another runner"},
+		{name: "ciphertext of another data key", envelope: tampered, err: "failed to decrypt the envelope"},
+		{name: "invalid key encoding", envelope: envelope{encryptedKey: "!", nonce: valid.nonce, ciphertext: valid.ciphertext}, err: "encrypted key is not base64 encoded"},
+		{name: "invalid nonce encoding", envelope: envelope{encryptedKey: valid.encryptedKey, nonce: "!", ciphertext: valid.ciphertext}, err: "nonce is not base64 encoded"},
+		{name: "invalid ciphertext encoding", envelope: envelope{encryptedKey: valid.encryptedKey, nonce: valid.nonce, ciphertext: "!"}, err: "ciphertext is not base64 encoded"},
+		{name: "invalid nonce size", envelope: envelope{encryptedKey: valid.encryptedKey, nonce: base64.StdEncoding.EncodeToString([]byte("short")), ciphertext: valid.ciphertext}, err: "invalid nonce size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decrypted, err := decrypter.Decrypt(tt.envelope.encryptedKey, tt.envelope.nonce, tt.envelope.ciphertext)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				if strings.Contains(err.Error(), "secret") {
+					t.Fatalf("error contains the plaintext: %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(decrypted) != string(plaintext) {
+				t.Fatalf("expected %q, got %q", plaintext, decrypted)
+			}
+		})
+	}
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "envelope.pem")
+
+	created, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected key file mode 0600, got %o", info.Mode().Perm())
+	}
+
+	loaded, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createdPublicKey, _ := created.PublicKey()
+	loadedPublicKey, _ := loaded.PublicKey()
+	if createdPublicKey != loadedPublicKey {
+		t.Fatal("loaded key differs from the created key")
+	}
+
+	invalidPath := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidPath, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOrCreateKey(invalidPath); err == nil {
+		t.Fatal("expected an error for a key file that isn't PEM encoded")
+	}
+}
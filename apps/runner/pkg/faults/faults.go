@@ -0,0 +1,40 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package faults injects failures and delays into the operations of the runner, for integration
+// tests and game days to exercise its recovery paths. Faults are only injected by runners built
+// with the faultinjection build tag, Inject is a no-op otherwise.
+package faults
+
+// Operations faults can be injected into
+const (
+	// Pulling an image, the target is the image
+	PointDockerPull = "docker.pull"
+	// Inspecting a container, the target is the container ID or name
+	PointDockerInspect = "docker.inspect"
+	// Creating a sandbox, the target is the sandbox ID
+	PointDockerCreate = "docker.create"
+	// Starting a sandbox, the target is the sandbox ID
+	PointDockerStart = "docker.start"
+	// Waiting for the daemon of a sandbox to start, the target is the daemon URL
+	PointDaemonWait = "daemon.wait"
+	// Executing a job, the target is the job type
+	PointExecutorJob = "executor.job"
+)
+
+var Points = []string{
+	PointDockerPull,
+	PointDockerInspect,
+	PointDockerCreate,
+	PointDockerStart,
+	PointDaemonWait,
+	PointExecutorJob,
+}
+
+// Errors of well-known failures, any other error of a fault is returned as is
+const (
+	// The disk of the sandbox or the runner is full
+	ErrorEnospc = "enospc"
+	// The operation timed out
+	ErrorTimeout = "timeout"
+)
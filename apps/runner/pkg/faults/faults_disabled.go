@@ -0,0 +1,33 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+//go:build !faultinjection
+
+package faults
+
+import (
+	"context"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+// Enabled is whether the runner was built with fault injection
+const Enabled = false
+
+func Inject(ctx context.Context, point, target string) error {
+	return nil
+}
+
+func Add(fault dto.FaultDTO) dto.FaultDTO {
+	return fault
+}
+
+func List() []dto.FaultDTO {
+	return []dto.FaultDTO{}
+}
+
+func Remove(id string) bool {
+	return false
+}
+
+func Clear() {}
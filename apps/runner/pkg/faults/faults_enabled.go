@@ -0,0 +1,132 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+//go:build faultinjection
+
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Enabled is whether the runner was built with fault injection
+const Enabled = true
+
+var (
+	mutex  sync.Mutex
+	nextId int
+	faults []*dto.FaultDTO
+)
+
+// Inject delays an operation and returns the error of the first fault matching it, if any
+func Inject(ctx context.Context, point, target string) error {
+	fault := match(point, target)
+	if fault == nil {
+		return nil
+	}
+
+	log.Warnf("Injecting fault %s into %s of %s", fault.Id, point, target)
+
+	if fault.DelayMs > 0 {
+		select {
+		case <-time.After(time.Duration(fault.DelayMs) * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	switch fault.Error {
+	case "":
+		return nil
+	case ErrorEnospc:
+		return fmt.Errorf("fault injected into %s: write %s: %w", point, target, syscall.ENOSPC)
+	case ErrorTimeout:
+		return fmt.Errorf("fault injected into %s of %s: %w", point, target, context.DeadlineExceeded)
+	default:
+		return fmt.Errorf("fault injected into %s of %s: %w", point, target, errors.New(fault.Error))
+	}
+}
+
+// match returns a copy of the first fault matching an operation, and uses up one of its
+// injections
+func match(point, target string) *dto.FaultDTO {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for i, fault := range faults {
+		if fault.Point != point || (fault.Target != "" && fault.Target != target) {
+			continue
+		}
+		if fault.Probability > 0 && rand.Float64() >= fault.Probability {
+			continue
+		}
+
+		matched := *fault
+		if fault.Count > 0 {
+			fault.Count--
+			if fault.Count == 0 {
+				faults = append(faults[:i], faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+
+	return nil
+}
+
+// Add adds a fault and returns it with its ID
+func Add(fault dto.FaultDTO) dto.FaultDTO {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	nextId++
+	fault.Id = strconv.Itoa(nextId)
+	faults = append(faults, &fault)
+
+	return fault
+}
+
+func List() []dto.FaultDTO {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	list := make([]dto.FaultDTO, 0, len(faults))
+	for _, fault := range faults {
+		list = append(list, *fault)
+	}
+
+	return list
+}
+
+// Remove removes a fault, it returns false if it doesn't exist
+func Remove(id string) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for i, fault := range faults {
+		if fault.Id == id {
+			faults = append(faults[:i], faults[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func Clear() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	faults = nil
+}
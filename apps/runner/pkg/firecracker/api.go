@@ -0,0 +1,107 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package firecracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// apiClient configures a Firecracker process through its API socket
+type apiClient struct {
+	httpClient *http.Client
+}
+
+func newApiClient(socketPath string) *apiClient {
+	return &apiClient{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+type bootSource struct {
+	KernelImagePath string `json:"kernel_image_path"`
+	BootArgs        string `json:"boot_args"`
+}
+
+type drive struct {
+	DriveId      string `json:"drive_id"`
+	PathOnHost   string `json:"path_on_host"`
+	IsRootDevice bool   `json:"is_root_device"`
+	IsReadOnly   bool   `json:"is_read_only"`
+}
+
+type machineConfig struct {
+	VcpuCount  int64 `json:"vcpu_count"`
+	MemSizeMib int64 `json:"mem_size_mib"`
+}
+
+type networkInterface struct {
+	IfaceId     string `json:"iface_id"`
+	HostDevName string `json:"host_dev_name"`
+	GuestMac    string `json:"guest_mac,omitempty"`
+}
+
+type action struct {
+	ActionType string `json:"action_type"`
+}
+
+func (c *apiClient) put(ctx context.Context, path string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, "http://firecracker"+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("firecracker responded to PUT %s with %s: %s", path, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
+
+// waitForSocket waits until the Firecracker process creates its API socket
+func waitForSocket(ctx context.Context, socketPath string) error {
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if _, err := os.Stat(socketPath); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("firecracker API socket %s was not created: %w", socketPath, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
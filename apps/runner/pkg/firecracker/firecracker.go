@@ -0,0 +1,450 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package firecracker runs sandboxes as Firecracker microVMs, for tenants that need hardware
+// virtualization isolation. The rootfs of a microVM is converted from the snapshot image and an
+// init script starts the daemon in it, so sandboxes keep the daemon toolbox of containers.
+//
+// Each microVM is connected to the host over its own TAP interface with a /30 network, and is
+// reachable from the runner at its guest address.
+package firecracker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/netrules"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	vmStateFile    = "vm.json"
+	rootfsFile     = "rootfs.ext4"
+	apiSocketFile  = "firecracker.sock"
+	consoleLogFile = "console.log"
+	// How long microVMs are given to shut down before Firecracker is killed
+	stopTimeout = 30 * time.Second
+)
+
+type Config struct {
+	BinaryPath string
+	// Uncompressed Linux kernel the microVMs boot
+	KernelPath string
+	// Directory holding the rootfs and state of each microVM
+	DataDir    string
+	DaemonPath string
+	// Network the microVMs are addressed from, split into a /30 per microVM
+	NetworkCidr     string
+	ApiClient       client.APIClient
+	NetRulesManager *netrules.NetRulesManager
+}
+
+type CreateOptions struct {
+	SandboxId string
+	// Config of the sandbox as a container, the microVM runs its entrypoint with its env
+	Config    *container.Config
+	Cpus      int64
+	MemoryGB  int64
+	StorageGB int64
+}
+
+// vmState is persisted along with the rootfs of a microVM, so microVMs survive runner restarts
+type vmState struct {
+	Id           string            `json:"id"`
+	SandboxId    string            `json:"sandboxId"`
+	NetworkIndex int               `json:"networkIndex"`
+	Cpus         int64             `json:"cpus"`
+	MemoryGB     int64             `json:"memoryGB"`
+	Config       *container.Config `json:"config"`
+	Pid          int               `json:"pid,omitempty"`
+	Created      time.Time         `json:"created"`
+	StartedAt    time.Time         `json:"startedAt,omitempty"`
+	FinishedAt   time.Time         `json:"finishedAt,omitempty"`
+}
+
+type Client struct {
+	config  Config
+	network *net.IPNet
+	// Guards the state files and the network indexes of the microVMs
+	mutex sync.Mutex
+}
+
+func NewClient(config Config) (*Client, error) {
+	_, ipNet, err := net.ParseCIDR(config.NetworkCidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid microVM network: %w", err)
+	}
+	if ipNet.IP.To4() == nil {
+		return nil, errors.New("the microVM network must be an IPv4 network")
+	}
+
+	if _, err := os.Stat(config.KernelPath); err != nil {
+		return nil, fmt.Errorf("microVM kernel not found: %w", err)
+	}
+
+	if err := os.MkdirAll(config.DataDir, 0700); err != nil {
+		return nil, err
+	}
+
+	if err := config.NetRulesManager.SetMicroVMNetwork(ipNet.String()); err != nil {
+		return nil, fmt.Errorf("failed to set up the microVM network: %w", err)
+	}
+
+	return &Client{config: config, network: ipNet}, nil
+}
+
+// Exists returns whether a sandbox is a microVM
+func (c *Client) Exists(sandboxId string) bool {
+	_, err := os.Stat(filepath.Join(c.vmDir(sandboxId), vmStateFile))
+	return err == nil
+}
+
+// Create builds the rootfs of a microVM without starting it
+func (c *Client) Create(ctx context.Context, opts CreateOptions) error {
+	c.mutex.Lock()
+	networkIndex, err := c.freeNetworkIndex()
+	if err != nil {
+		c.mutex.Unlock()
+		return err
+	}
+
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		c.mutex.Unlock()
+		return err
+	}
+
+	state := &vmState{
+		Id:           hex.EncodeToString(id),
+		SandboxId:    opts.SandboxId,
+		NetworkIndex: networkIndex,
+		Cpus:         opts.Cpus,
+		MemoryGB:     opts.MemoryGB,
+		Config:       opts.Config,
+		Created:      time.Now(),
+	}
+
+	// The state is saved upfront to reserve the network index
+	if err := os.MkdirAll(c.vmDir(opts.SandboxId), 0700); err != nil {
+		c.mutex.Unlock()
+		return err
+	}
+	err = c.saveState(state)
+	c.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = c.buildRootfs(ctx, opts.SandboxId, opts.Config, filepath.Join(c.vmDir(opts.SandboxId), rootfsFile), opts.StorageGB)
+	if err != nil {
+		_ = os.RemoveAll(c.vmDir(opts.SandboxId))
+		return fmt.Errorf("failed to build the rootfs of microVM %s: %w", opts.SandboxId, err)
+	}
+
+	return nil
+}
+
+// Start boots a microVM, unless it is already running
+func (c *Client) Start(ctx context.Context, sandboxId string) error {
+	state, err := c.loadState(sandboxId)
+	if err != nil {
+		return err
+	}
+
+	if c.isRunning(state.Pid) {
+		return nil
+	}
+
+	hostAddress, guestAddress := c.addresses(state.NetworkIndex)
+	tap, err := c.config.NetRulesManager.CreateMicroVMTap(state.Id[:12], hostAddress)
+	if err != nil {
+		return fmt.Errorf("failed to create the TAP interface of microVM %s: %w", sandboxId, err)
+	}
+
+	dir := c.vmDir(sandboxId)
+	socketPath := filepath.Join(dir, apiSocketFile)
+	_ = os.Remove(socketPath)
+
+	consoleLog, err := os.OpenFile(filepath.Join(dir, consoleLogFile), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer consoleLog.Close()
+
+	cmd := exec.Command(c.config.BinaryPath, "--api-sock", socketPath, "--id", state.Id[:12])
+	cmd.Stdout = consoleLog
+	cmd.Stderr = consoleLog
+	// microVMs keep running across runner restarts
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start firecracker: %w", err)
+	}
+	go func() {
+		_ = cmd.Wait()
+	}()
+
+	err = c.boot(ctx, socketPath, state, tap, guestAddress, hostAddress.IP)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("failed to boot microVM %s: %w", sandboxId, err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	state.Pid = cmd.Process.Pid
+	state.StartedAt = time.Now()
+	return c.saveState(state)
+}
+
+func (c *Client) boot(ctx context.Context, socketPath string, state *vmState, tap string, guestAddress net.IP, gateway net.IP) error {
+	socketCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := waitForSocket(socketCtx, socketPath); err != nil {
+		return err
+	}
+
+	api := newApiClient(socketPath)
+
+	bootArgs := fmt.Sprintf("console=ttyS0 reboot=k panic=1 pci=off init=%s/init ip=%s::%s:255.255.255.252::eth0:off",
+		guestDaytonaDir, guestAddress, gateway)
+
+	err := api.put(ctx, "/boot-source", bootSource{KernelImagePath: c.config.KernelPath, BootArgs: bootArgs})
+	if err != nil {
+		return err
+	}
+
+	err = api.put(ctx, "/drives/rootfs", drive{
+		DriveId:      "rootfs",
+		PathOnHost:   filepath.Join(c.vmDir(state.SandboxId), rootfsFile),
+		IsRootDevice: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = api.put(ctx, "/machine-config", machineConfig{VcpuCount: state.Cpus, MemSizeMib: state.MemoryGB * 1024})
+	if err != nil {
+		return err
+	}
+
+	err = api.put(ctx, "/network-interfaces/eth0", networkInterface{IfaceId: "eth0", HostDevName: tap})
+	if err != nil {
+		return err
+	}
+
+	return api.put(ctx, "/actions", action{ActionType: "InstanceStart"})
+}
+
+// Stop shuts a microVM down with Ctrl+Alt+Del, which the init script handles, and kills it if it
+// doesn't stop in time
+func (c *Client) Stop(ctx context.Context, sandboxId string) error {
+	state, err := c.loadState(sandboxId)
+	if err != nil {
+		return err
+	}
+
+	if c.isRunning(state.Pid) {
+		api := newApiClient(filepath.Join(c.vmDir(sandboxId), apiSocketFile))
+		if err := api.put(ctx, "/actions", action{ActionType: "SendCtrlAltDel"}); err != nil {
+			log.Warnf("Failed to shut microVM %s down, killing it: %v", sandboxId, err)
+		}
+
+		deadline := time.Now().Add(stopTimeout)
+		for c.isRunning(state.Pid) && time.Now().Before(deadline) {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(200 * time.Millisecond):
+			}
+		}
+
+		if c.isRunning(state.Pid) {
+			if err := syscall.Kill(state.Pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+				return err
+			}
+		}
+	}
+
+	if err := c.config.NetRulesManager.RemoveMicroVMTap(state.Id[:12]); err != nil {
+		log.Warnf("Failed to remove the TAP interface of microVM %s: %v", sandboxId, err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	state.Pid = 0
+	state.FinishedAt = time.Now()
+	return c.saveState(state)
+}
+
+// Destroy stops a microVM and removes its rootfs
+func (c *Client) Destroy(ctx context.Context, sandboxId string) error {
+	if !c.Exists(sandboxId) {
+		return nil
+	}
+
+	if err := c.Stop(ctx, sandboxId); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(c.vmDir(sandboxId))
+}
+
+// Inspect describes a microVM as a container, so it is handled like one by the rest of the runner
+func (c *Client) Inspect(sandboxId string) (container.InspectResponse, error) {
+	state, err := c.loadState(sandboxId)
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+
+	running := c.isRunning(state.Pid)
+	status := "created"
+	switch {
+	case running:
+		status = "running"
+	case !state.StartedAt.IsZero():
+		status = "exited"
+	}
+
+	_, guestAddress := c.addresses(state.NetworkIndex)
+
+	return container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:      state.Id,
+			Name:    "/" + sandboxId,
+			Created: state.Created.Format(time.RFC3339Nano),
+			Image:   state.Config.Image,
+			State: &container.State{
+				Status:     status,
+				Running:    running,
+				Pid:        state.Pid,
+				StartedAt:  state.StartedAt.Format(time.RFC3339Nano),
+				FinishedAt: state.FinishedAt.Format(time.RFC3339Nano),
+			},
+			HostConfig: &container.HostConfig{
+				Resources: container.Resources{
+					CPUPeriod: 100000,
+					CPUQuota:  state.Cpus * 100000,
+					Memory:    state.MemoryGB * 1024 * 1024 * 1024,
+				},
+			},
+		},
+		Config: state.Config,
+		NetworkSettings: &container.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"bridge": {IPAddress: guestAddress.String()},
+			},
+		},
+	}, nil
+}
+
+func (c *Client) vmDir(sandboxId string) string {
+	return filepath.Join(c.config.DataDir, sandboxId)
+}
+
+func (c *Client) loadState(sandboxId string) (*vmState, error) {
+	content, err := os.ReadFile(filepath.Join(c.vmDir(sandboxId), vmStateFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("microVM %s not found: %w", sandboxId, err)
+		}
+		return nil, err
+	}
+
+	var state vmState
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("invalid state of microVM %s: %w", sandboxId, err)
+	}
+
+	return &state, nil
+}
+
+// saveState must be called with the mutex held
+func (c *Client) saveState(state *vmState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(c.vmDir(state.SandboxId), vmStateFile)
+	if err := os.WriteFile(path+".tmp", content, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// freeNetworkIndex returns the first /30 of the network no microVM uses. It must be called with
+// the mutex held.
+func (c *Client) freeNetworkIndex() (int, error) {
+	entries, err := os.ReadDir(c.config.DataDir)
+	if err != nil {
+		return 0, err
+	}
+
+	used := map[int]bool{}
+	for _, entry := range entries {
+		state, err := c.loadState(entry.Name())
+		if err != nil {
+			continue
+		}
+		used[state.NetworkIndex] = true
+	}
+
+	ones, bits := c.network.Mask.Size()
+	size := (1 << (bits - ones)) / 4
+	for index := 0; index < size; index++ {
+		if !used[index] {
+			return index, nil
+		}
+	}
+
+	return 0, errors.New("the microVM network has no free addresses")
+}
+
+// addresses returns the host and guest addresses of the /30 with the index
+func (c *Client) addresses(index int) (*net.IPNet, net.IP) {
+	base := binary.BigEndian.Uint32(c.network.IP.To4()) + uint32(index*4)
+
+	host := make(net.IP, 4)
+	binary.BigEndian.PutUint32(host, base+1)
+	guest := make(net.IP, 4)
+	binary.BigEndian.PutUint32(guest, base+2)
+
+	return &net.IPNet{IP: host, Mask: net.CIDRMask(30, 32)}, guest
+}
+
+func (c *Client) isRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	// The process may have been replaced by another one with the same PID
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return false
+	}
+
+	binary, _, _ := bytes.Cut(cmdline, []byte{0})
+	return string(binary) == c.config.BinaryPath
+}
@@ -0,0 +1,360 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package firecracker
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/docker/docker/api/types/container"
+)
+
+// Directory in the rootfs holding the init script and env of the microVM
+const guestDaytonaDir = "/.daytona"
+
+var envKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// buildRootfs converts the filesystem of an image into an ext4 disk image, with the daemon and an
+// init script that starts it injected
+func (c *Client) buildRootfs(ctx context.Context, sandboxId string, config *container.Config, rootfsPath string, sizeGB int64) error {
+	buildDir, err := os.MkdirTemp(filepath.Dir(rootfsPath), "rootfs-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(buildDir)
+
+	if err := c.exportImage(ctx, config.Image, buildDir); err != nil {
+		return fmt.Errorf("failed to export image %s: %w", config.Image, err)
+	}
+
+	daemonPath, err := securePath(buildDir, common.DAEMON_PATH)
+	if err != nil {
+		return err
+	}
+
+	if err := copyFile(c.config.DaemonPath, daemonPath, 0755); err != nil {
+		return fmt.Errorf("failed to inject the daemon: %w", err)
+	}
+
+	if err := writeGuestFiles(buildDir, sandboxId, config); err != nil {
+		return err
+	}
+
+	rootfs, err := os.Create(rootfsPath)
+	if err != nil {
+		return err
+	}
+	// The disk image is sparse, so only the written blocks take up space on the host
+	err = rootfs.Truncate(common.GBToBytes(float64(sizeGB)))
+	rootfs.Close()
+	if err != nil {
+		return err
+	}
+
+	output, err := exec.CommandContext(ctx, "mkfs.ext4", "-F", "-q", "-L", "rootfs", "-d", buildDir, rootfsPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("mkfs.ext4 failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// exportImage extracts the filesystem of an image by exporting a container created from it
+func (c *Client) exportImage(ctx context.Context, image, dir string) error {
+	created, err := c.config.ApiClient.ContainerCreate(ctx, &container.Config{Image: image, Entrypoint: []string{"/"}}, nil, nil, nil, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.config.ApiClient.ContainerRemove(context.Background(), created.ID, container.RemoveOptions{Force: true})
+	}()
+
+	reader, err := c.config.ApiClient.ContainerExport(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return extractTar(reader, dir)
+}
+
+func extractTar(reader io.Reader, dir string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := securePath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		// Later entries replace earlier ones, which must not be followed if they are symlinks
+		if info, err := os.Lstat(target); err == nil && (header.Typeflag != tar.TypeDir || !info.IsDir()) {
+			if err := os.RemoveAll(target); err != nil {
+				return err
+			}
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode).Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, tarReader)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			source, err := securePath(dir, header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := os.Link(source, target); err != nil {
+				return err
+			}
+		default:
+			// Device nodes are created by devtmpfs in the guest
+			continue
+		}
+
+		if err := os.Lchown(target, header.Uid, header.Gid); err != nil {
+			return err
+		}
+		// chown clears the setuid and setgid bits
+		if header.Typeflag != tar.TypeSymlink {
+			if err := os.Chmod(target, os.FileMode(header.Mode).Perm()|modeBits(header.Mode)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func modeBits(mode int64) os.FileMode {
+	var bits os.FileMode
+	if mode&04000 != 0 {
+		bits |= os.ModeSetuid
+	}
+	if mode&02000 != 0 {
+		bits |= os.ModeSetgid
+	}
+	if mode&01000 != 0 {
+		bits |= os.ModeSticky
+	}
+	return bits
+}
+
+// securePath joins a path of an archive to the directory it is extracted to, rejecting paths
+// that escape it, also through symlinks extracted before
+func securePath(dir, name string) (string, error) {
+	target := filepath.Join(dir, filepath.Clean("/"+name))
+	if target != dir && !strings.HasPrefix(target, dir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid path %s in image", name)
+	}
+
+	for parent := filepath.Dir(target); parent != dir && len(parent) > len(dir); parent = filepath.Dir(parent) {
+		info, err := os.Lstat(parent)
+		if err != nil {
+			continue
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("invalid path %s in image, %s is a symlink", name, strings.TrimPrefix(parent, dir))
+		}
+	}
+
+	return target, nil
+}
+
+// writeGuestFiles writes the init script, env, hostname and resolvers of the microVM
+func writeGuestFiles(rootDir, sandboxId string, config *container.Config) error {
+	daytonaDir, err := securePath(rootDir, guestDaytonaDir)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(daytonaDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(daytonaDir, 0700); err != nil {
+		return err
+	}
+
+	var env strings.Builder
+	for _, variable := range config.Env {
+		key, value, _ := strings.Cut(variable, "=")
+		if !envKeyRegex.MatchString(key) {
+			continue
+		}
+		env.WriteString(key + "=" + shellQuote(value) + "\n")
+	}
+	if err := os.WriteFile(filepath.Join(daytonaDir, "env"), []byte(env.String()), 0600); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(daytonaDir, "init"), []byte(initScript(sandboxId, config)), 0700); err != nil {
+		return err
+	}
+
+	if err := writeGuestFile(rootDir, "/etc/hostname", sandboxId+"\n"); err != nil {
+		return err
+	}
+
+	return writeGuestFile(rootDir, "/etc/resolv.conf", hostResolvers())
+}
+
+// writeGuestFile replaces a file of the rootfs. The image may ship it as a symlink, e.g.
+// /etc/resolv.conf to the systemd-resolved stub, which is replaced rather than followed.
+func writeGuestFile(rootDir, path, content string) error {
+	target, err := securePath(rootDir, path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+
+	return os.WriteFile(target, []byte(content), 0644)
+}
+
+// initScript returns the script run as PID 1 of the microVM. It mounts the kernel filesystems,
+// starts the daemon and the entrypoint of the sandbox, and reboots the microVM, which stops
+// Firecracker, once it is asked to shut down with Ctrl+Alt+Del.
+func initScript(sandboxId string, config *container.Config) string {
+	command := append(append([]string{}, config.Entrypoint...), config.Cmd...)
+
+	var start strings.Builder
+	if len(config.Entrypoint) == 0 || config.Entrypoint[0] != common.DAEMON_PATH {
+		start.WriteString(shellQuote(common.DAEMON_PATH) + " &\n")
+	}
+	if len(command) > 0 {
+		quoted := make([]string, 0, len(command))
+		for _, arg := range command {
+			quoted = append(quoted, shellQuote(arg))
+		}
+		start.WriteString(strings.Join(quoted, " ") + " &\n")
+	}
+
+	workingDir := config.WorkingDir
+	if workingDir == "" {
+		workingDir = "/"
+	}
+
+	return fmt.Sprintf(`#!/bin/sh
+mount -t proc proc /proc
+mount -t sysfs sysfs /sys
+mount -t devtmpfs devtmpfs /dev 2>/dev/null
+mkdir -p /dev/pts /dev/shm
+mount -t devpts devpts /dev/pts
+mount -t tmpfs tmpfs /dev/shm
+mount -t tmpfs tmpfs /tmp
+hostname %[1]s
+ip link set lo up 2>/dev/null || ifconfig lo up 2>/dev/null
+
+shutdown() {
+	kill -TERM -1
+	sleep 2
+	sync
+	echo u > /proc/sysrq-trigger
+	echo b > /proc/sysrq-trigger
+}
+trap shutdown INT TERM
+
+set -a
+. %[2]s/env
+set +a
+
+cd %[3]s 2>/dev/null || cd /
+%[4]swait
+shutdown
+`, shellQuote(sandboxId), guestDaytonaDir, shellQuote(workingDir), start.String())
+}
+
+// hostResolvers returns the resolvers of the host, unless they are only reachable from it
+func hostResolvers() string {
+	content, err := os.ReadFile("/etc/resolv.conf")
+	if err != nil {
+		return "nameserver 1.1.1.1\n"
+	}
+
+	var resolvers strings.Builder
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "nameserver" || strings.HasPrefix(fields[1], "127.") || fields[1] == "::1" {
+			continue
+		}
+		resolvers.WriteString(line + "\n")
+	}
+
+	if resolvers.Len() == 0 {
+		return "nameserver 1.1.1.1\n"
+	}
+
+	return resolvers.String()
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func copyFile(source, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,369 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package layercache implements a pull-through registry mirror that serves image layers from a
+// local digest-addressed cache and from peer runners before fetching them from the registry.
+//
+// The Docker daemon uses it once it is configured as a registry mirror, e.g. with
+// "registry-mirrors": ["http://127.0.0.1:5050"] for Docker Hub. Other registries are mirrored
+// through containerd hosts.toml files, which send the mirrored registry in the ns query parameter.
+// Manifests are always fetched from the registry, so tags and access checks stay authoritative.
+package layercache
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Header peers authenticate their requests with, requests without it must come from the runner
+	peerTokenHeader = "X-Daytona-Layer-Cache-Token"
+	// How long peers are given to report if they have a layer
+	peerLookupTimeout = 500 * time.Millisecond
+	defaultRegistry   = "registry-1.docker.io"
+)
+
+type Config struct {
+	ListenAddress string
+	CacheDir      string
+	MaxSizeBytes  int64
+	// Base URLs of the layer caches of other runners, e.g. http://10.0.1.12:5050
+	Peers     []string
+	PeerToken string
+	// Registry mirrored for requests without the ns query parameter
+	Upstream string
+}
+
+type Service struct {
+	config     Config
+	store      *blobStore
+	httpClient *http.Client
+	// Guards the peers and upstream of the config, which can be updated at runtime
+	mirrorsMutex sync.RWMutex
+}
+
+func NewService(config Config) (*Service, error) {
+	if config.Upstream == "" {
+		config.Upstream = defaultRegistry
+	}
+
+	store, err := newBlobStore(config.CacheDir, config.MaxSizeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create layer cache directory: %w", err)
+	}
+
+	return &Service{
+		config:     config,
+		store:      store,
+		httpClient: &http.Client{},
+	}, nil
+}
+
+// UpdateMirrors changes the peers and the upstream registry of the cache at runtime
+func (s *Service) UpdateMirrors(peers []string, upstream string) {
+	if upstream == "" {
+		upstream = defaultRegistry
+	}
+
+	s.mirrorsMutex.Lock()
+	defer s.mirrorsMutex.Unlock()
+
+	s.config.Peers = peers
+	s.config.Upstream = upstream
+}
+
+func (s *Service) mirrors() ([]string, string) {
+	s.mirrorsMutex.RLock()
+	defer s.mirrorsMutex.RUnlock()
+
+	return s.config.Peers, s.config.Upstream
+}
+
+func (s *Service) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.config.ListenAddress,
+		Handler:           http.HandlerFunc(s.handle),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	log.Infof("Layer cache listening on %s with %d peers", s.config.ListenAddress, len(s.config.Peers))
+
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Service) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	peerRequest := r.Header.Get(peerTokenHeader) != ""
+	if peerRequest {
+		if s.config.PeerToken == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(peerTokenHeader)), []byte(s.config.PeerToken)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	} else if !isLoopback(r.RemoteAddr) {
+		// Cached layers are served without registry credentials, so only the local daemon may use the mirror
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if r.URL.Path == "/v2" || r.URL.Path == "/v2/" {
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if dgst, ok := parseBlobPath(r.URL.Path); ok {
+		s.serveBlob(w, r, dgst, peerRequest)
+		return
+	}
+
+	if peerRequest {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	s.proxy(w, r, nil)
+}
+
+// serveBlob serves a layer from the cache, a peer or the registry, in that order. Peers are
+// only served from their cache, so lookups don't cascade through the fleet.
+func (s *Service) serveBlob(w http.ResponseWriter, r *http.Request, dgst digest.Digest, peerRequest bool) {
+	if s.serveCached(w, r, dgst) {
+		return
+	}
+
+	if peerRequest {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodGet && r.Header.Get("Range") == "" {
+		if s.fetchFromPeers(r.Context(), dgst) && s.serveCached(w, r, dgst) {
+			return
+		}
+
+		blob, err := s.store.create(dgst)
+		if err != nil {
+			log.Warnf("Failed to cache layer %s: %v", dgst, err)
+		}
+		s.proxy(w, r, blob)
+		return
+	}
+
+	s.proxy(w, r, nil)
+}
+
+func (s *Service) serveCached(w http.ResponseWriter, r *http.Request, dgst digest.Digest) bool {
+	f, err := s.store.open(dgst)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", dgst.String())
+	http.ServeContent(w, r, "", time.Time{}, f)
+	return true
+}
+
+// fetchFromPeers adds a layer to the cache from the first peer that has it
+func (s *Service) fetchFromPeers(ctx context.Context, dgst digest.Digest) bool {
+	peers, _ := s.mirrors()
+	if len(peers) == 0 || s.config.PeerToken == "" {
+		return false
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, peerLookupTimeout)
+	defer cancel()
+
+	found := make(chan string, len(peers))
+	for _, peer := range peers {
+		go func() {
+			resp, err := s.peerRequest(lookupCtx, http.MethodHead, peer, dgst)
+			if err != nil {
+				found <- ""
+				return
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				found <- ""
+				return
+			}
+			found <- peer
+		}()
+	}
+
+	var peer string
+	for range peers {
+		if peer = <-found; peer != "" {
+			break
+		}
+	}
+	if peer == "" {
+		return false
+	}
+
+	resp, err := s.peerRequest(ctx, http.MethodGet, peer, dgst)
+	if err != nil {
+		log.Warnf("Failed to fetch layer %s from peer %s: %v", dgst, peer, err)
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	blob, err := s.store.create(dgst)
+	if err != nil {
+		log.Warnf("Failed to cache layer %s: %v", dgst, err)
+		return false
+	}
+
+	if _, err := io.Copy(blob, resp.Body); err != nil {
+		blob.abort()
+		log.Warnf("Failed to fetch layer %s from peer %s: %v", dgst, peer, err)
+		return false
+	}
+
+	if err := blob.commit(); err != nil {
+		log.Warnf("Failed to cache layer %s from peer %s: %v", dgst, peer, err)
+		return false
+	}
+
+	log.Debugf("Fetched layer %s from peer %s", dgst, peer)
+	return true
+}
+
+func (s *Service) peerRequest(ctx context.Context, method, peer string, dgst digest.Digest) (*http.Response, error) {
+	// Peers look blobs up by digest only, so the repository name is a placeholder
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v2/_/blobs/%s", strings.TrimSuffix(peer, "/"), dgst), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(peerTokenHeader, s.config.PeerToken)
+
+	return s.httpClient.Do(req)
+}
+
+// proxy forwards a request to the mirrored registry, including its credentials. If blob is set,
+// a successful response is also written to the cache.
+func (s *Service) proxy(w http.ResponseWriter, r *http.Request, blob *blobWriter) {
+	committed := false
+	defer func() {
+		if blob != nil && !committed {
+			blob.abort()
+		}
+	}()
+
+	upstreamUrl := s.upstreamUrl(r.URL)
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, upstreamUrl.String(), nil)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	for _, header := range []string{"Authorization", "Accept", "Range", "User-Agent"} {
+		for _, value := range r.Header.Values(header) {
+			req.Header.Add(header, value)
+		}
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		log.Warnf("Failed to reach registry %s: %v", upstreamUrl.Host, err)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	if blob == nil || resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(w, resp.Body)
+		return
+	}
+
+	_, err = io.Copy(io.MultiWriter(w, blob), resp.Body)
+	if err != nil {
+		return
+	}
+
+	if err := blob.commit(); err != nil {
+		log.Warnf("Failed to cache layer %s: %v", blob.digest, err)
+	}
+	committed = true
+}
+
+func (s *Service) upstreamUrl(requestUrl *url.URL) *url.URL {
+	query := requestUrl.Query()
+
+	registry := query.Get("ns")
+	query.Del("ns")
+	if registry == "" || registry == "docker.io" {
+		_, registry = s.mirrors()
+	}
+
+	return &url.URL{
+		Scheme:   "https",
+		Host:     registry,
+		Path:     requestUrl.Path,
+		RawQuery: query.Encode(),
+	}
+}
+
+// parseBlobPath returns the digest of /v2/<name>/blobs/<digest> requests
+func parseBlobPath(path string) (digest.Digest, bool) {
+	index := strings.LastIndex(path, "/blobs/")
+	if !strings.HasPrefix(path, "/v2/") || index == -1 {
+		return "", false
+	}
+
+	dgst, err := digest.Parse(path[index+len("/blobs/"):])
+	if err != nil || dgst.Algorithm() != digest.SHA256 {
+		return "", false
+	}
+
+	return dgst, true
+}
+
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package layercache
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// blobStore keeps blobs on disk addressed by their digest and evicts the least recently used
+// blobs once the cache grows over its maximum size
+type blobStore struct {
+	dir      string
+	maxSize  int64
+	evicting sync.Mutex
+}
+
+func newBlobStore(dir string, maxSize int64) (*blobStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0700); err != nil {
+		return nil, err
+	}
+
+	return &blobStore{
+		dir:     dir,
+		maxSize: maxSize,
+	}, nil
+}
+
+func (s *blobStore) path(dgst digest.Digest) string {
+	return filepath.Join(s.dir, dgst.Algorithm().String(), dgst.Encoded())
+}
+
+// open returns the cached blob with the digest and marks it as recently used
+func (s *blobStore) open(dgst digest.Digest) (*os.File, error) {
+	path := s.path(dgst)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return f, nil
+}
+
+// create returns a writer for a blob. The blob is only added to the cache if the written content
+// matches its digest.
+func (s *blobStore) create(dgst digest.Digest) (*blobWriter, error) {
+	f, err := os.CreateTemp(filepath.Join(s.dir, "tmp"), dgst.Encoded()+"-*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &blobWriter{
+		File:     f,
+		store:    s,
+		digest:   dgst,
+		verifier: dgst.Verifier(),
+	}, nil
+}
+
+// evict removes the least recently used blobs until the cache fits its maximum size
+func (s *blobStore) evict() {
+	if !s.evicting.TryLock() {
+		return
+	}
+	defer s.evicting.Unlock()
+
+	type blob struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var blobs []blob
+	var total int64
+
+	_ = filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if entry.Name() == "tmp" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+
+		blobs = append(blobs, blob{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+
+	if total <= s.maxSize {
+		return
+	}
+
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].modTime.Before(blobs[j].modTime)
+	})
+
+	for _, b := range blobs {
+		if total <= s.maxSize {
+			break
+		}
+
+		if err := os.Remove(b.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Warnf("Failed to evict cached layer %s: %v", b.path, err)
+			continue
+		}
+		total -= b.size
+	}
+}
+
+type blobWriter struct {
+	*os.File
+	store    *blobStore
+	digest   digest.Digest
+	verifier digest.Verifier
+}
+
+func (w *blobWriter) Write(p []byte) (int, error) {
+	n, err := w.File.Write(p)
+	_, _ = w.verifier.Write(p[:n])
+	return n, err
+}
+
+// commit adds the blob to the cache if its content matches the digest
+func (w *blobWriter) commit() error {
+	defer os.Remove(w.Name())
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if !w.verifier.Verified() {
+		return errors.New("blob content doesn't match its digest")
+	}
+
+	path := w.store.path(w.digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	if err := os.Rename(w.Name(), path); err != nil {
+		return err
+	}
+
+	go w.store.evict()
+	return nil
+}
+
+func (w *blobWriter) abort() {
+	w.Close()
+	os.Remove(w.Name())
+}
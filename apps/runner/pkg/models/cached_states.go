@@ -11,4 +11,7 @@ type CachedStates struct {
 	SandboxState      enums.SandboxState
 	BackupState       enums.BackupState
 	BackupErrorReason *string
+	DaemonAuthToken   string
+	// Version of the states cache when the sandbox or backup state last changed
+	Version uint64
 }
@@ -18,6 +18,7 @@ const (
 	SandboxStateError           SandboxState = "error"
 	SandboxStateUnknown         SandboxState = "unknown"
 	SandboxStatePullingSnapshot SandboxState = "pulling_snapshot"
+	SandboxStateProvisioning    SandboxState = "provisioning"
 )
 
 func (s SandboxState) String() string {
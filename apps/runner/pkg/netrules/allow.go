@@ -0,0 +1,28 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package netrules
+
+import "net"
+
+// AllowNetworkAddresses allows traffic to the given addresses in the rules of a container, ahead
+// of its allow list. Containers without network rules are not restricted, so nothing is added.
+func (manager *NetRulesManager) AllowNetworkAddresses(name string, addresses []net.IP) error {
+	chainName := formatChainName(name)
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	exists, err := manager.ipt.ChainExists("filter", chainName)
+	if err != nil || !exists {
+		return err
+	}
+
+	for _, address := range addresses {
+		if err := manager.ipt.InsertUnique("filter", chainName, 1, "-j", "RETURN", "-d", address.String()+"/32", "-p", "all"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package netrules
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeleteChain removes a Daytona chain of a table, after the rules jumping to it from other chains
+func (manager *NetRulesManager) DeleteChain(table string, chain string) error {
+	if !strings.HasPrefix(chain, ChainPrefix) {
+		return fmt.Errorf("%s is not a Daytona chain", chain)
+	}
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	chains, err := manager.ipt.ListChains(table)
+	if err != nil {
+		return err
+	}
+
+	for _, parent := range chains {
+		if parent == chain {
+			continue
+		}
+
+		rules, err := manager.ipt.List(table, parent)
+		if err != nil {
+			return err
+		}
+
+		for _, rule := range rules {
+			if !jumpsTo(rule, chain) {
+				continue
+			}
+
+			args, err := ParseRuleArguments(rule)
+			if err != nil {
+				continue
+			}
+
+			if err := manager.ipt.Delete(table, parent, args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return manager.ipt.ClearAndDeleteChain(table, chain)
+}
@@ -3,8 +3,6 @@
 
 package netrules
 
-import "strings"
-
 // DeleteNetworkRules completely removes network rules for a container
 func (manager *NetRulesManager) DeleteNetworkRules(name string) error {
 	// Add prefix to chain name
@@ -21,7 +19,7 @@ func (manager *NetRulesManager) DeleteNetworkRules(name string) error {
 
 	// Find and remove rules that reference our chain
 	for _, rule := range rules {
-		if strings.Contains(rule, chainName) {
+		if jumpsTo(rule, chainName) {
 			// Parse the rule to extract arguments
 			args, err := ParseRuleArguments(rule)
 			if err != nil {
@@ -0,0 +1,109 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package netrules
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+// Suffix of the nat chain pinning the egress IP of a container, the unsuffixed chain is jumped to from PREROUTING
+const egressIpChainSuffix = "-SNAT"
+
+// SetEgressIp makes the traffic of a container leave the host from the given address instead of the
+// primary address of the host. The address must be assigned to an interface of the host.
+func (manager *NetRulesManager) SetEgressIp(name string, sourceIp string, egressIp string) error {
+	chainName := formatChainName(name) + egressIpChainSuffix
+
+	outInterface, err := getAddressInterface(egressIp)
+	if err != nil {
+		return err
+	}
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	// Create the chain (ignores if already exists)
+	err = manager.ipt.NewChain("nat", chainName)
+	if err != nil && !strings.Contains(err.Error(), "Chain already exists") {
+		return err
+	}
+
+	// Clear existing rules to ensure clean state
+	if err := manager.ipt.ClearChain("nat", chainName); err != nil {
+		return err
+	}
+
+	if err := manager.ipt.AppendUnique("nat", chainName, "-j", "SNAT", "--to-source", egressIp); err != nil {
+		return err
+	}
+
+	// Inserted first, so it takes precedence over the Docker masquerade rule
+	return manager.ipt.InsertUnique("nat", "POSTROUTING", 1, "-j", chainName, "-s", sourceIp, "-o", outInterface)
+}
+
+// RemoveEgressIp removes the egress IP of a container
+func (manager *NetRulesManager) RemoveEgressIp(name string) error {
+	chainName := formatChainName(name) + egressIpChainSuffix
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	exists, err := manager.ipt.ChainExists("nat", chainName)
+	if err != nil || !exists {
+		return err
+	}
+
+	rules, err := manager.ipt.List("nat", "POSTROUTING")
+	if err != nil {
+		return err
+	}
+
+	// Find and remove rules that reference our chain
+	for _, rule := range rules {
+		if strings.Contains(rule, chainName) {
+			args, err := ParseRuleArguments(rule)
+			if err != nil {
+				// Skip malformed rules
+				continue
+			}
+
+			if err := manager.ipt.Delete("nat", "POSTROUTING", args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return manager.ipt.ClearAndDeleteChain("nat", chainName)
+}
+
+// getAddressInterface returns the name of the host interface an address is assigned to
+func getAddressInterface(address string) (string, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return "", fmt.Errorf("invalid egress IP %s", address)
+	}
+
+	addrs, err := netlink.AddrList(nil, netlink.FAMILY_V4)
+	if err != nil {
+		return "", fmt.Errorf("failed to list addresses: %w", err)
+	}
+
+	for _, addr := range addrs {
+		if !addr.IP.Equal(ip) {
+			continue
+		}
+
+		link, err := netlink.LinkByIndex(addr.LinkIndex)
+		if err != nil {
+			return "", fmt.Errorf("failed to get link: %w", err)
+		}
+		return link.Attrs().Name, nil
+	}
+
+	return "", fmt.Errorf("egress IP %s is not assigned to the host", address)
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package netrules
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Suffix of the filter chain dropping the traffic of a container the egress proxy doesn't check
+const egressProxyChainSuffix = "-EGRESS"
+
+// SetEgressProxyRedirect redirects the HTTP and HTTPS traffic of a container to the egress proxy
+// listening on the given port of the runner. Other traffic leaving the host is dropped, except for
+// DNS, which the DNS forwarder or the resolvers of the sandbox restrict.
+func (manager *NetRulesManager) SetEgressProxyRedirect(name string, sourceIp string, proxyPort int) error {
+	chainName := formatChainName(name)
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if err := manager.setEgressDrop(chainName+egressProxyChainSuffix, sourceIp); err != nil {
+		return err
+	}
+
+	// Create the chain (ignores if already exists)
+	err := manager.ipt.NewChain("nat", chainName)
+	if err != nil && !strings.Contains(err.Error(), "Chain already exists") {
+		return err
+	}
+
+	// Clear existing rules to ensure clean state
+	if err := manager.ipt.ClearChain("nat", chainName); err != nil {
+		return err
+	}
+
+	for _, port := range []string{"80", "443"} {
+		if err := manager.ipt.AppendUnique("nat", chainName, "-p", "tcp", "--dport", port, "-j", "REDIRECT", "--to-ports", strconv.Itoa(proxyPort)); err != nil {
+			return err
+		}
+	}
+
+	// Assign the rules to the container IP
+	return manager.ipt.InsertUnique("nat", "PREROUTING", 1, "-j", chainName, "-s", sourceIp, "-p", "tcp")
+}
+
+// RemoveEgressProxyRedirect removes the egress proxy redirect of a container
+func (manager *NetRulesManager) RemoveEgressProxyRedirect(name string) error {
+	chainName := formatChainName(name)
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if err := manager.removeEgressDrop(chainName + egressProxyChainSuffix); err != nil {
+		return err
+	}
+
+	exists, err := manager.ipt.ChainExists("nat", chainName)
+	if err != nil || !exists {
+		return err
+	}
+
+	rules, err := manager.ipt.List("nat", "PREROUTING")
+	if err != nil {
+		return err
+	}
+
+	// Find and remove rules that reference our chain
+	for _, rule := range rules {
+		if strings.Contains(rule, chainName) {
+			args, err := ParseRuleArguments(rule)
+			if err != nil {
+				// Skip malformed rules
+				continue
+			}
+
+			if err := manager.ipt.Delete("nat", "PREROUTING", args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return manager.ipt.ClearAndDeleteChain("nat", chainName)
+}
+
+// setEgressDrop drops the forwarded traffic of a container other than DNS. Redirected traffic is
+// delivered to the proxy on the host and isn't forwarded.
+func (manager *NetRulesManager) setEgressDrop(chainName string, sourceIp string) error {
+	err := manager.ipt.NewChain("filter", chainName)
+	if err != nil && !strings.Contains(err.Error(), "Chain already exists") {
+		return err
+	}
+
+	if err := manager.ipt.ClearChain("filter", chainName); err != nil {
+		return err
+	}
+
+	for _, protocol := range []string{"udp", "tcp"} {
+		if err := manager.ipt.AppendUnique("filter", chainName, "-p", protocol, "--dport", "53", "-j", "RETURN"); err != nil {
+			return err
+		}
+	}
+
+	if err := manager.ipt.AppendUnique("filter", chainName, "-j", "DROP", "-p", "all"); err != nil {
+		return err
+	}
+
+	return manager.ipt.InsertUnique("filter", "DOCKER-USER", 1, "-j", chainName, "-s", sourceIp, "-p", "all")
+}
+
+func (manager *NetRulesManager) removeEgressDrop(chainName string) error {
+	exists, err := manager.ipt.ChainExists("filter", chainName)
+	if err != nil || !exists {
+		return err
+	}
+
+	rules, err := manager.ipt.List("filter", "DOCKER-USER")
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if !jumpsTo(rule, chainName) {
+			continue
+		}
+
+		args, err := ParseRuleArguments(rule)
+		if err != nil {
+			continue
+		}
+
+		if err := manager.ipt.Delete("filter", "DOCKER-USER", args...); err != nil {
+			return err
+		}
+	}
+
+	return manager.ipt.ClearAndDeleteChain("filter", chainName)
+}
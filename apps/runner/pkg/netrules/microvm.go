@@ -0,0 +1,86 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package netrules
+
+import (
+	"errors"
+	"net"
+
+	"github.com/vishvananda/netlink"
+)
+
+// Prefix of the TAP interfaces of microVMs
+const microVMTapPrefix = "fc-"
+
+// SetMicroVMNetwork lets microVMs addressed from the network reach the outside world. Docker drops
+// forwarded traffic that isn't from its bridges, so the TAP interfaces are accepted explicitly.
+func (manager *NetRulesManager) SetMicroVMNetwork(cidr string) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if err := manager.ipt.AppendUnique("nat", "POSTROUTING", "-s", cidr, "!", "-d", cidr, "-j", "MASQUERADE"); err != nil {
+		return err
+	}
+
+	if err := manager.ipt.AppendUnique("filter", "FORWARD", "-i", microVMTapPrefix+"+", "-j", "ACCEPT"); err != nil {
+		return err
+	}
+
+	return manager.ipt.AppendUnique("filter", "FORWARD", "-o", microVMTapPrefix+"+", "-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT")
+}
+
+// CreateMicroVMTap creates the TAP interface of a microVM with the host end of its point-to-point
+// network, e.g. 172.31.0.1/30, and returns its name
+func (manager *NetRulesManager) CreateMicroVMTap(name string, hostAddress *net.IPNet) (string, error) {
+	interfaceName := microVMTapPrefix + name
+	if len(interfaceName) > 15 {
+		interfaceName = interfaceName[:15]
+	}
+
+	if err := manager.RemoveMicroVMTap(name); err != nil {
+		return "", err
+	}
+
+	tap := &netlink.Tuntap{
+		LinkAttrs: netlink.LinkAttrs{Name: interfaceName},
+		Mode:      netlink.TUNTAP_MODE_TAP,
+		// Firecracker expects a TAP without packet information and with virtio net headers
+		Flags: netlink.TUNTAP_NO_PI | netlink.TUNTAP_VNET_HDR,
+	}
+	if err := netlink.LinkAdd(tap); err != nil {
+		return "", err
+	}
+	// The TAP is persistent, its queues are released so that Firecracker can attach to it
+	for _, fd := range tap.Fds {
+		fd.Close()
+	}
+
+	if err := netlink.AddrAdd(tap, &netlink.Addr{IPNet: hostAddress}); err != nil {
+		return "", err
+	}
+
+	if err := netlink.LinkSetUp(tap); err != nil {
+		return "", err
+	}
+
+	return interfaceName, nil
+}
+
+func (manager *NetRulesManager) RemoveMicroVMTap(name string) error {
+	interfaceName := microVMTapPrefix + name
+	if len(interfaceName) > 15 {
+		interfaceName = interfaceName[:15]
+	}
+
+	link, err := netlink.LinkByName(interfaceName)
+	if err != nil {
+		var notFound netlink.LinkNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+
+	return netlink.LinkDel(link)
+}
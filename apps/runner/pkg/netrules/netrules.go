@@ -118,6 +118,14 @@ func (manager *NetRulesManager) ListDaytonaChains(table string) ([]string, error
 	return daytonaChains, nil
 }
 
+// ListChainRules returns the rules of a chain of a table
+func (manager *NetRulesManager) ListChainRules(table string, chain string) ([]string, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	return manager.ipt.List(table, chain)
+}
+
 // ClearAndDeleteChain deletes a specific table chain
 func (manager *NetRulesManager) ClearAndDeleteChain(table string, name string) error {
 	manager.mu.Lock()
@@ -49,3 +49,11 @@ func (manager *NetRulesManager) SetNetworkRules(name string, sourceIp string, ne
 
 	return nil
 }
+
+// HasNetworkRules returns whether the network of a container is restricted by network rules
+func (manager *NetRulesManager) HasNetworkRules(name string) (bool, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	return manager.ipt.ChainExists("filter", formatChainName(name))
+}
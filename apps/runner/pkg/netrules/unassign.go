@@ -3,8 +3,6 @@
 
 package netrules
 
-import "strings"
-
 // UnassignNetworkRules removes network rules assignment from a container
 func (manager *NetRulesManager) UnassignNetworkRules(name string) error {
 	// Add prefix to chain name
@@ -21,7 +19,7 @@ func (manager *NetRulesManager) UnassignNetworkRules(name string) error {
 
 	// Find and remove rules that reference our chain
 	for _, rule := range rules {
-		if strings.Contains(rule, chainName) {
+		if jumpsTo(rule, chainName) {
 			// Parse the rule to extract arguments
 			args, err := ParseRuleArguments(rule)
 			if err != nil {
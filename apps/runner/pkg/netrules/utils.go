@@ -6,6 +6,7 @@ package netrules
 import (
 	"fmt"
 	"net"
+	"regexp"
 	"strings"
 )
 
@@ -14,6 +15,9 @@ const (
 	ChainPrefix = "DAYTONA-SB-"
 )
 
+// Matches the short container IDs sandbox chains are named after
+var shortContainerIdRegex = regexp.MustCompile(`^[0-9a-f]{12}$`)
+
 // ParseCidrNetworks parses a comma-separated list of CIDR networks and returns them as an array
 func parseCidrNetworks(networks string) ([]*net.IPNet, error) {
 	networkList := strings.Split(networks, ",")
@@ -49,6 +53,11 @@ func ParseRuleArguments(rule string) ([]string, error) {
 	return nil, fmt.Errorf("invalid rule format: %s", rule)
 }
 
+// jumpsTo returns whether a rule jumps to a chain, and not to another chain its name is a prefix of
+func jumpsTo(rule string, chain string) bool {
+	return strings.HasSuffix(rule, "-j "+chain) || strings.Contains(rule, "-j "+chain+" ")
+}
+
 // formatChainName adds the DAYTONA-SB- prefix to a chain name if it doesn't already have it
 func formatChainName(name string) string {
 	if strings.HasPrefix(name, ChainPrefix) {
@@ -56,3 +65,20 @@ func formatChainName(name string) string {
 	}
 	return ChainPrefix + name
 }
+
+// ChainContainerId returns the short ID of the container a sandbox chain belongs to, and false if
+// the chain isn't named after a container
+func ChainContainerId(chain string) (string, bool) {
+	if !strings.HasPrefix(chain, ChainPrefix) {
+		return "", false
+	}
+
+	containerId := strings.TrimPrefix(chain, ChainPrefix)
+	for _, suffix := range []string{egressIpChainSuffix, egressProxyChainSuffix} {
+		containerId = strings.TrimSuffix(containerId, suffix)
+	}
+	if !shortContainerIdRegex.MatchString(containerId) {
+		return "", false
+	}
+	return containerId, true
+}
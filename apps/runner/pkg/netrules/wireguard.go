@@ -0,0 +1,230 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package netrules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	wireGuardInterfacePrefix = "wg-"
+	// Routing tables of sandbox tunnels are allocated above this ID
+	wireGuardTableBase    = 100000
+	wireGuardRulePriority = 1000
+)
+
+// WireGuardTunnel is the configuration of the sandbox end of a WireGuard tunnel
+type WireGuardTunnel struct {
+	PrivateKey             string
+	Address                string
+	PeerPublicKey          string
+	PresharedKey           string
+	PeerEndpoint           string
+	AllowedIps             []string
+	PersistentKeepaliveSec int
+}
+
+// SetWireGuardTunnel brings up a WireGuard interface for a container and routes the traffic of
+// the container to the allowed IPs of the tunnel through it. The routes live in a routing table
+// only looked up for packets from the container, so the host keeps its own routes.
+func (manager *NetRulesManager) SetWireGuardTunnel(name string, sourceIp string, tunnel WireGuardTunnel) error {
+	interfaceName := wireGuardInterfacePrefix + name
+
+	source := net.ParseIP(sourceIp)
+	if source == nil {
+		return fmt.Errorf("invalid source IP %s", sourceIp)
+	}
+
+	address, _, err := net.ParseCIDR(tunnel.Address)
+	if err != nil {
+		return err
+	}
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	err = netlink.LinkAdd(&netlink.Wireguard{LinkAttrs: netlink.LinkAttrs{Name: interfaceName}})
+	if err != nil && !errors.Is(err, syscall.EEXIST) {
+		return fmt.Errorf("failed to create interface %s: %w", interfaceName, err)
+	}
+
+	link, err := netlink.LinkByName(interfaceName)
+	if err != nil {
+		return err
+	}
+	table := wireGuardTable(link)
+
+	// Keys are passed through stdin so they don't show up in the process list
+	cmd := exec.Command("wg", "setconf", interfaceName, "/dev/stdin")
+	cmd.Stdin = strings.NewReader(tunnel.config())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to configure interface %s: %w: %s", interfaceName, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// A host address keeps the kernel from adding a route to the tunnel network to the main table
+	err = netlink.AddrReplace(link, &netlink.Addr{IPNet: &net.IPNet{IP: address, Mask: net.CIDRMask(32, 32)}})
+	if err != nil {
+		return err
+	}
+
+	if err := netlink.LinkSetUp(link); err != nil {
+		return err
+	}
+
+	// Replies come from networks the main table routes elsewhere, which strict reverse path filtering drops
+	err = os.WriteFile(fmt.Sprintf("/proc/sys/net/ipv4/conf/%s/rp_filter", interfaceName), []byte("2"), 0644)
+	if err != nil {
+		return err
+	}
+
+	for _, allowedIp := range tunnel.AllowedIps {
+		_, dst, err := net.ParseCIDR(allowedIp)
+		if err != nil {
+			return err
+		}
+
+		err = netlink.RouteReplace(&netlink.Route{
+			LinkIndex: link.Attrs().Index,
+			Dst:       dst,
+			Scope:     netlink.SCOPE_LINK,
+			Table:     table,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to route %s through interface %s: %w", allowedIp, interfaceName, err)
+		}
+	}
+
+	// Rules left behind for the table, or for the container with a table of an earlier interface
+	if err := deleteWireGuardRules(table); err != nil {
+		return err
+	}
+	if err := deleteWireGuardSourceRules(source); err != nil {
+		return err
+	}
+
+	rule := netlink.NewRule()
+	rule.Src = &net.IPNet{IP: source, Mask: net.CIDRMask(32, 32)}
+	rule.Table = table
+	rule.Priority = wireGuardRulePriority
+	if err := netlink.RuleAdd(rule); err != nil {
+		return err
+	}
+
+	// The peer only knows the tunnel address of the sandbox
+	return manager.ipt.AppendUnique("nat", "POSTROUTING", "-s", sourceIp, "-o", interfaceName, "-j", "SNAT", "--to-source", address.String())
+}
+
+// RemoveWireGuardTunnel removes the WireGuard interface of a container and its routes
+func (manager *NetRulesManager) RemoveWireGuardTunnel(name string) error {
+	interfaceName := wireGuardInterfacePrefix + name
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	rules, err := manager.ipt.List("nat", "POSTROUTING")
+	if err != nil {
+		return err
+	}
+
+	// Find and remove rules that reference our interface
+	for _, rule := range rules {
+		if strings.Contains(rule, "-o "+interfaceName+" ") {
+			args, err := ParseRuleArguments(rule)
+			if err != nil {
+				// Skip malformed rules
+				continue
+			}
+
+			if err := manager.ipt.Delete("nat", "POSTROUTING", args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	link, err := netlink.LinkByName(interfaceName)
+	if err != nil {
+		var notFound netlink.LinkNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := deleteWireGuardRules(wireGuardTable(link)); err != nil {
+		return err
+	}
+
+	// Routes of the interface are removed along with it
+	return netlink.LinkDel(link)
+}
+
+func deleteWireGuardRules(table int) error {
+	rules, err := netlink.RuleListFiltered(netlink.FAMILY_V4, &netlink.Rule{Table: table}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if err := netlink.RuleDel(&rule); err != nil && !errors.Is(err, syscall.ENOENT) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteWireGuardSourceRules removes the tunnel rules of a source address, whatever their table
+func deleteWireGuardSourceRules(source net.IP) error {
+	rules, err := netlink.RuleListFiltered(netlink.FAMILY_V4, &netlink.Rule{
+		Src:      &net.IPNet{IP: source, Mask: net.CIDRMask(32, 32)},
+		Priority: wireGuardRulePriority,
+	}, netlink.RT_FILTER_SRC|netlink.RT_FILTER_PRIORITY)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if err := netlink.RuleDel(&rule); err != nil && !errors.Is(err, syscall.ENOENT) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// wireGuardTable returns the routing table of the tunnel of a container. It is derived from the
+// index of the interface, which no other interface of the host has while it exists, so tunnels of
+// different containers never share a table and removing the rules of one leaves the others.
+func wireGuardTable(link netlink.Link) int {
+	return wireGuardTableBase + link.Attrs().Index
+}
+
+// config returns the tunnel configuration in the format of wg setconf
+func (tunnel WireGuardTunnel) config() string {
+	var config strings.Builder
+
+	fmt.Fprintf(&config, "[Interface]\nPrivateKey = %s\n\n", tunnel.PrivateKey)
+	fmt.Fprintf(&config, "[Peer]\nPublicKey = %s\n", tunnel.PeerPublicKey)
+	if tunnel.PresharedKey != "" {
+		fmt.Fprintf(&config, "PresharedKey = %s\n", tunnel.PresharedKey)
+	}
+	fmt.Fprintf(&config, "Endpoint = %s\n", tunnel.PeerEndpoint)
+	fmt.Fprintf(&config, "AllowedIPs = %s\n", strings.Join(tunnel.AllowedIps, ", "))
+	if tunnel.PersistentKeepaliveSec > 0 {
+		fmt.Fprintf(&config, "PersistentKeepalive = %d\n", tunnel.PersistentKeepaliveSec)
+	}
+
+	return config.String()
+}
@@ -0,0 +1,498 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package packagecache implements a pull-through cache of npm, PyPI and Go module downloads that
+// the sandboxes of the runner are configured to use.
+//
+// Package archives, e.g. npm tarballs, PyPI distributions and Go module zips, never change once
+// published and are served from the cache until they are evicted. Metadata, e.g. the versions of a
+// package, is fetched again once it is older than the metadata TTL, and served stale if the
+// registry can't be reached. Requests with credentials are forwarded without being cached, so
+// private packages are never served to other sandboxes.
+package packagecache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	cmap "github.com/orcaman/concurrent-map/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// How long the access of a sandbox address is cached for
+	accessTTL = 30 * time.Second
+	// How long a download from a registry may take
+	fetchTimeout = 10 * time.Minute
+	// Metadata larger than this is forwarded without being cached
+	maxMetadataSize = 64 * 1024 * 1024
+)
+
+var (
+	requestCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "runner_package_cache_requests_total",
+		Help: "Requests of sandboxes to the package cache, by ecosystem and result: hit, miss, stale, bypass, forbidden or error",
+	}, []string{"ecosystem", "result"})
+	servedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "runner_package_cache_served_bytes_total",
+		Help: "Bytes the package cache served to sandboxes, by ecosystem",
+	}, []string{"ecosystem"})
+	upstreamBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "runner_package_cache_upstream_bytes_total",
+		Help: "Bytes the package cache downloaded from registries, by ecosystem",
+	}, []string{"ecosystem"})
+	evictionCount = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "runner_package_cache_evictions_total",
+		Help: "Entries evicted from the package cache to keep it within its maximum size",
+	})
+	cacheSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "runner_package_cache_size_bytes",
+		Help: "Size of the package cache on disk as of its last eviction check",
+	})
+)
+
+type Config struct {
+	ListenAddress string
+	CacheDir      string
+	MaxSizeBytes  int64
+	// How long metadata is served from the cache before it is fetched again
+	MetadataTTL time.Duration
+	// URL sandboxes reach the cache at, which download URLs in metadata are rewritten to
+	Url         string
+	NpmRegistry string
+	PypiIndex   string
+	PypiFiles   string
+	GoProxy     string
+	// Returns whether the sandbox with the address may use the cache
+	Authorize func(ctx context.Context, ip string) bool
+}
+
+type entryKind int
+
+const (
+	// Forwarded without being cached
+	kindUncached entryKind = iota
+	kindMetadata
+	kindImmutable
+)
+
+// upstream is a registry mirrored under a path prefix of the cache
+type upstream struct {
+	ecosystem string
+	prefix    string
+	url       string
+	kind      func(path string) entryKind
+	// Pairs of URLs replaced in metadata, so that downloads also go through the cache
+	rewrites []string
+}
+
+type Service struct {
+	config     Config
+	store      *entryStore
+	upstreams  []upstream
+	httpClient *http.Client
+	access     cmap.ConcurrentMap[string, cachedAccess]
+	fillsMutex sync.Mutex
+	fills      map[string]*fill
+}
+
+type cachedAccess struct {
+	allowed   bool
+	expiresAt time.Time
+}
+
+// fill is a download of an entry into the cache, which concurrent requests for it wait for
+type fill struct {
+	done chan struct{}
+	err  error
+}
+
+// upstreamError is a response of a registry other than 200, relayed to the client
+type upstreamError struct {
+	statusCode  int
+	contentType string
+	body        []byte
+}
+
+func (e *upstreamError) Error() string {
+	return fmt.Sprintf("registry responded with status %d", e.statusCode)
+}
+
+var errTooLarge = errors.New("response is too large to cache")
+
+func NewService(config Config) (*Service, error) {
+	store, err := newEntryStore(config.CacheDir, config.MaxSizeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create package cache directory: %w", err)
+	}
+
+	baseUrl := strings.TrimSuffix(config.Url, "/")
+
+	return &Service{
+		config: config,
+		store:  store,
+		upstreams: []upstream{
+			{
+				ecosystem: "npm",
+				prefix:    "/npm/",
+				url:       strings.TrimSuffix(config.NpmRegistry, "/"),
+				kind:      npmEntryKind,
+				rewrites:  []string{strings.TrimSuffix(config.NpmRegistry, "/") + "/", baseUrl + "/npm/"},
+			},
+			{
+				ecosystem: "pypi",
+				prefix:    "/pypi/files/",
+				url:       strings.TrimSuffix(config.PypiFiles, "/"),
+				kind:      func(string) entryKind { return kindImmutable },
+			},
+			{
+				ecosystem: "pypi",
+				prefix:    "/pypi/",
+				url:       strings.TrimSuffix(config.PypiIndex, "/"),
+				kind:      func(string) entryKind { return kindMetadata },
+				rewrites:  []string{strings.TrimSuffix(config.PypiFiles, "/") + "/", baseUrl + "/pypi/files/"},
+			},
+			{
+				ecosystem: "go",
+				prefix:    "/go/",
+				url:       strings.TrimSuffix(config.GoProxy, "/"),
+				kind:      goEntryKind,
+			},
+		},
+		httpClient: &http.Client{},
+		access:     cmap.New[cachedAccess](),
+		fills:      make(map[string]*fill),
+	}, nil
+}
+
+// npmEntryKind caches tarballs for good and package documents as metadata. Other endpoints, e.g.
+// search and audit, are under /-/ and not cached.
+func npmEntryKind(path string) entryKind {
+	switch {
+	case strings.Contains(path, "/-/") && strings.HasSuffix(path, ".tgz"):
+		return kindImmutable
+	case strings.HasPrefix(path, "/-/"):
+		return kindUncached
+	default:
+		return kindMetadata
+	}
+}
+
+// goEntryKind caches the files of module versions for good and the version lists as metadata.
+// Checksum database requests are not cached.
+func goEntryKind(path string) entryKind {
+	switch {
+	case strings.HasPrefix(path, "/sumdb/"):
+		return kindUncached
+	case strings.Contains(path, "/@v/") && (strings.HasSuffix(path, ".info") || strings.HasSuffix(path, ".mod") || strings.HasSuffix(path, ".zip")):
+		return kindImmutable
+	default:
+		return kindMetadata
+	}
+}
+
+func (s *Service) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.config.ListenAddress,
+		Handler:           http.HandlerFunc(s.handle),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	log.Infof("Package cache listening on %s", s.config.ListenAddress)
+
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Service) handle(w http.ResponseWriter, r *http.Request) {
+	var upstream *upstream
+	for i := range s.upstreams {
+		if strings.HasPrefix(r.URL.EscapedPath(), s.upstreams[i].prefix) {
+			upstream = &s.upstreams[i]
+			break
+		}
+	}
+	if upstream == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !s.authorized(r) {
+		requestCount.WithLabelValues(upstream.ecosystem, "forbidden").Inc()
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// Escaped, e.g. the names of scoped npm packages are sent as @scope%2fname
+	path := "/" + strings.TrimPrefix(r.URL.EscapedPath(), upstream.prefix)
+	kind := upstream.kind(path)
+
+	cacheable := (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+		kind != kindUncached &&
+		r.Header.Get("Authorization") == "" &&
+		r.Header.Get("Range") == ""
+	if !cacheable {
+		requestCount.WithLabelValues(upstream.ecosystem, "bypass").Inc()
+		s.forward(w, r, upstream, path)
+		return
+	}
+
+	key := upstream.ecosystem + path + "?" + r.URL.RawQuery
+	if kind == kindMetadata {
+		// Registries serve different metadata formats depending on the Accept header
+		key += "|" + r.Header.Get("Accept")
+	}
+	immutable := kind == kindImmutable
+
+	stale, err := s.store.open(key, immutable)
+	if err == nil && (immutable || time.Since(stale.fetchedAt) < s.config.MetadataTTL) {
+		s.serveEntry(w, r, upstream, stale, "hit")
+		return
+	}
+	if err != nil {
+		stale = nil
+	}
+	defer func() {
+		if stale != nil {
+			stale.Close()
+		}
+	}()
+
+	err = s.fill(r, upstream, path, key, kind)
+	if err == nil {
+		if e, err := s.store.open(key, immutable); err == nil {
+			s.serveEntry(w, r, upstream, e, "miss")
+			return
+		}
+	}
+
+	var upstreamErr *upstreamError
+	switch {
+	case errors.As(err, &upstreamErr) && upstreamErr.statusCode < 500:
+		requestCount.WithLabelValues(upstream.ecosystem, "miss").Inc()
+		if upstreamErr.contentType != "" {
+			w.Header().Set("Content-Type", upstreamErr.contentType)
+		}
+		w.WriteHeader(upstreamErr.statusCode)
+		_, _ = w.Write(upstreamErr.body)
+	case stale != nil:
+		log.Debugf("Serving stale %s metadata %s: %v", upstream.ecosystem, path, err)
+		s.serveEntry(w, r, upstream, stale, "stale")
+		stale = nil
+	case errors.Is(err, errTooLarge) || err == nil:
+		requestCount.WithLabelValues(upstream.ecosystem, "bypass").Inc()
+		s.forward(w, r, upstream, path)
+	default:
+		log.Warnf("Failed to fetch %s package %s: %v", upstream.ecosystem, path, err)
+		requestCount.WithLabelValues(upstream.ecosystem, "error").Inc()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+}
+
+// authorized returns whether the request comes from a sandbox allowed to use the cache. Sandboxes
+// with restricted networks are not, the cache would let them reach the registries.
+func (s *Service) authorized(r *http.Request) bool {
+	if s.config.Authorize == nil {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+
+	if cached, ok := s.access.Get(host); ok && time.Now().Before(cached.expiresAt) {
+		return cached.allowed
+	}
+
+	allowed := s.config.Authorize(r.Context(), host)
+	s.access.Set(host, cachedAccess{allowed: allowed, expiresAt: time.Now().Add(accessTTL)})
+	return allowed
+}
+
+func (s *Service) serveEntry(w http.ResponseWriter, r *http.Request, upstream *upstream, e *entry, result string) {
+	defer e.Close()
+
+	requestCount.WithLabelValues(upstream.ecosystem, result).Inc()
+	if r.Method == http.MethodGet {
+		servedBytes.WithLabelValues(upstream.ecosystem).Add(float64(e.size))
+	}
+
+	if e.contentType != "" {
+		w.Header().Set("Content-Type", e.contentType)
+	}
+	w.Header().Set("X-Daytona-Package-Cache", result)
+	http.ServeContent(w, r, "", time.Time{}, e.body())
+}
+
+// fill downloads an entry into the cache. Concurrent requests for an entry wait for the same
+// download, which isn't canceled if the client that started it goes away.
+func (s *Service) fill(r *http.Request, upstream *upstream, path, key string, kind entryKind) error {
+	s.fillsMutex.Lock()
+	if f, ok := s.fills[key]; ok {
+		s.fillsMutex.Unlock()
+		select {
+		case <-f.done:
+			return f.err
+		case <-r.Context().Done():
+			return r.Context().Err()
+		}
+	}
+
+	f := &fill{done: make(chan struct{})}
+	s.fills[key] = f
+	s.fillsMutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), fetchTimeout)
+	defer cancel()
+
+	f.err = s.download(ctx, r, upstream, path, key, kind)
+
+	s.fillsMutex.Lock()
+	delete(s.fills, key)
+	s.fillsMutex.Unlock()
+	close(f.done)
+
+	return f.err
+}
+
+func (s *Service) download(ctx context.Context, r *http.Request, upstream *upstream, path, key string, kind entryKind) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.upstreamUrl(upstream, path, r.URL.RawQuery), nil)
+	if err != nil {
+		return err
+	}
+	for _, header := range []string{"Accept", "User-Agent"} {
+		if value := r.Header.Get(header); value != "" {
+			req.Header.Set(header, value)
+		}
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &upstreamError{statusCode: resp.StatusCode, contentType: resp.Header.Get("Content-Type"), body: body}
+	}
+
+	limit := s.config.MaxSizeBytes / 4
+	if kind == kindMetadata {
+		limit = min(limit, maxMetadataSize)
+	}
+	if resp.ContentLength > limit {
+		return errTooLarge
+	}
+
+	writer, err := s.store.create(key, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+
+	body := io.LimitReader(resp.Body, limit+1)
+	if kind == kindMetadata && len(upstream.rewrites) > 0 {
+		content, err := io.ReadAll(body)
+		if err == nil && int64(len(content)) > limit {
+			err = errTooLarge
+		}
+		if err != nil {
+			writer.abort()
+			return err
+		}
+		upstreamBytes.WithLabelValues(upstream.ecosystem).Add(float64(len(content)))
+		body = bytes.NewReader([]byte(strings.NewReplacer(upstream.rewrites...).Replace(string(content))))
+	}
+
+	written, err := io.Copy(writer, body)
+	if err == nil && written > limit {
+		err = errTooLarge
+	}
+	if err != nil {
+		writer.abort()
+		return err
+	}
+	if kind == kindImmutable || len(upstream.rewrites) == 0 {
+		upstreamBytes.WithLabelValues(upstream.ecosystem).Add(float64(written))
+	}
+
+	return writer.commit()
+}
+
+// forward proxies a request to the registry without caching it, including its credentials
+func (s *Service) forward(w http.ResponseWriter, r *http.Request, upstream *upstream, path string) {
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, s.upstreamUrl(upstream, path, r.URL.RawQuery), r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	req.ContentLength = r.ContentLength
+	for _, header := range []string{"Authorization", "Accept", "Content-Type", "Range", "User-Agent", "Npm-Command", "Npm-Session"} {
+		for _, value := range r.Header.Values(header) {
+			req.Header.Add(header, value)
+		}
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		log.Warnf("Failed to reach %s registry: %v", upstream.ecosystem, err)
+		requestCount.WithLabelValues(upstream.ecosystem, "error").Inc()
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for _, header := range []string{"Content-Type", "Content-Range", "Accept-Ranges", "Cache-Control", "Etag", "Last-Modified", "Location", "Www-Authenticate"} {
+		for _, value := range resp.Header.Values(header) {
+			w.Header().Add(header, value)
+		}
+	}
+	w.Header().Set("X-Daytona-Package-Cache", "bypass")
+	w.WriteHeader(resp.StatusCode)
+
+	written, _ := io.Copy(w, resp.Body)
+	servedBytes.WithLabelValues(upstream.ecosystem).Add(float64(written))
+	upstreamBytes.WithLabelValues(upstream.ecosystem).Add(float64(written))
+}
+
+func (s *Service) upstreamUrl(upstream *upstream, path, rawQuery string) string {
+	upstreamUrl, err := url.Parse(upstream.url + path)
+	if err != nil {
+		return upstream.url + path
+	}
+	upstreamUrl.RawQuery = rawQuery
+	return upstreamUrl.String()
+}
+
+// Url returns the base URL of the cache from its listen address, for sandboxes to reach it at
+func Url(listenAddress string) (string, error) {
+	host, port, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return "", err
+	}
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		return "", errors.New("package cache must listen on an address sandboxes can reach, e.g. the Docker bridge gateway")
+	}
+	return "http://" + net.JoinHostPort(host, port), nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package packagecache
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// entryStore keeps responses on disk addressed by the hash of their key and evicts the least
+// recently used entries once the cache grows over its maximum size. Each entry file starts with
+// the content type of the response on its own line, followed by the body.
+type entryStore struct {
+	dir      string
+	maxSize  int64
+	evicting sync.Mutex
+}
+
+func newEntryStore(dir string, maxSize int64) (*entryStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0700); err != nil {
+		return nil, err
+	}
+
+	store := &entryStore{
+		dir:     dir,
+		maxSize: maxSize,
+	}
+	go store.evict()
+
+	return store, nil
+}
+
+func (s *entryStore) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	encoded := hex.EncodeToString(hash[:])
+	return filepath.Join(s.dir, encoded[:2], encoded)
+}
+
+type entry struct {
+	*os.File
+	contentType string
+	// Where the body starts and its size
+	offset int64
+	size   int64
+	// When the entry was fetched from the upstream
+	fetchedAt time.Time
+}
+
+func (e *entry) body() io.ReadSeeker {
+	return io.NewSectionReader(e.File, e.offset, e.size)
+}
+
+// open returns the cached entry with the key. Immutable entries are marked as recently used, the
+// modification time of the others is the time they were fetched at, which their freshness is
+// checked against.
+func (s *entryStore) open(key string, immutable bool) (*entry, error) {
+	path := s.path(key)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	contentType, err := bufio.NewReader(io.LimitReader(f, 1024)).ReadString('\n')
+	if err != nil {
+		f.Close()
+		return nil, errors.New("invalid cache entry")
+	}
+
+	if immutable {
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+	}
+
+	offset := int64(len(contentType))
+	return &entry{
+		File:        f,
+		contentType: strings.TrimSuffix(contentType, "\n"),
+		offset:      offset,
+		size:        info.Size() - offset,
+		fetchedAt:   info.ModTime(),
+	}, nil
+}
+
+// create returns a writer for an entry, which is only added to the cache once committed
+func (s *entryStore) create(key, contentType string) (*entryWriter, error) {
+	f, err := os.CreateTemp(filepath.Join(s.dir, "tmp"), "entry-*")
+	if err != nil {
+		return nil, err
+	}
+
+	contentType = strings.ReplaceAll(contentType, "\n", "")
+	if _, err := f.WriteString(contentType + "\n"); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	return &entryWriter{
+		File:  f,
+		store: s,
+		key:   key,
+	}, nil
+}
+
+// evict removes the least recently used entries until the cache fits its maximum size
+func (s *entryStore) evict() {
+	if !s.evicting.TryLock() {
+		return
+	}
+	defer s.evicting.Unlock()
+
+	type cached struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var entries []cached
+	var total int64
+
+	_ = filepath.WalkDir(s.dir, func(path string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if dirEntry.IsDir() {
+			if dirEntry.Name() == "tmp" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return nil
+		}
+
+		entries = append(entries, cached{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+
+	if total > s.maxSize {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].modTime.Before(entries[j].modTime)
+		})
+
+		for _, e := range entries {
+			if total <= s.maxSize {
+				break
+			}
+
+			if err := os.Remove(e.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				log.Warnf("Failed to evict cached package %s: %v", e.path, err)
+				continue
+			}
+			total -= e.size
+			evictionCount.Inc()
+		}
+	}
+
+	cacheSize.Set(float64(total))
+}
+
+type entryWriter struct {
+	*os.File
+	store *entryStore
+	key   string
+}
+
+// commit adds the entry to the cache, replacing the previous one with the key
+func (w *entryWriter) commit() error {
+	defer os.Remove(w.Name())
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	path := w.store.path(w.key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	if err := os.Rename(w.Name(), path); err != nil {
+		return err
+	}
+
+	go w.store.evict()
+	return nil
+}
+
+func (w *entryWriter) abort() {
+	w.Close()
+	os.Remove(w.Name())
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/runner/cmd/runner/config"
+	"github.com/daytonaio/runner/internal"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/docker/docker/api/types"
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// Tables and chains listed by the net rules, besides the chains of the sandboxes
+var netRulesTables = []string{"filter", "mangle", "nat"}
+
+// ListNetRules returns the network rule chains of the sandboxes with their rules, along with the
+// DOCKER-USER rules jumping to them
+func (r *Runner) ListNetRules() ([]dto.NetRulesChainDTO, error) {
+	jumps, err := r.NetRulesManager.ListDaytonaRules("filter", "DOCKER-USER")
+	if err != nil {
+		return nil, err
+	}
+
+	chains := []dto.NetRulesChainDTO{{Table: "filter", Chain: "DOCKER-USER", Rules: jumps}}
+	for _, table := range netRulesTables {
+		names, err := r.NetRulesManager.ListDaytonaChains(table)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			rules, err := r.NetRulesManager.ListChainRules(table, name)
+			if err != nil {
+				return nil, err
+			}
+			chains = append(chains, dto.NetRulesChainDTO{Table: table, Chain: name, Rules: rules})
+		}
+	}
+
+	return chains, nil
+}
+
+// diagnosticsPart is a file of the diagnostics bundle. Values other than bytes are written to it
+// as JSON.
+type diagnosticsPart struct {
+	name string
+	get  func() (any, error)
+}
+
+// WriteDiagnostics writes a gzipped tar of the state of the runner, for debugging it without
+// access to the host: its recent logs, redacted config, sandboxes, jobs, network rules, disk
+// usage and profiles. Parts that can't be collected are listed in errors.txt.
+func (r *Runner) WriteDiagnostics(ctx context.Context, w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	var failures []string
+	collect := func(name string, get func() (any, error)) error {
+		value, err := get()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			return nil
+		}
+
+		content, ok := value.([]byte)
+		if !ok {
+			content, err = json.MarshalIndent(value, "", "  ")
+			if err != nil {
+				return err
+			}
+		}
+
+		return writeTarFile(tarWriter, name, content)
+	}
+
+	parts := []diagnosticsPart{
+		{"version.json", func() (any, error) {
+			return map[string]string{"version": internal.Version}, nil
+		}},
+		{"config.json", func() (any, error) {
+			cfg, err := config.GetConfig()
+			if err != nil {
+				return nil, err
+			}
+			return cfg.Redacted(), nil
+		}},
+		{"logs.txt", func() (any, error) {
+			return []byte(strings.Join(r.Logs.Lines(), "\n") + "\n"), nil
+		}},
+		{"metrics.json", func() (any, error) {
+			return r.MetricsCollector.Collect(ctx)
+		}},
+		{"sandboxes.json", func() (any, error) {
+			return r.Docker.ListSandboxes(ctx, nil)
+		}},
+		{"jobs.json", func() (any, error) {
+			return r.Jobs.List(0), nil
+		}},
+		{"drain.json", func() (any, error) {
+			return dto.DrainStatusDTO{Draining: r.Admission.Draining()}, nil
+		}},
+		{"netrules.json", func() (any, error) {
+			return r.ListNetRules()
+		}},
+		{"orphans.json", func() (any, error) {
+			return r.Reaper.Report(ctx), nil
+		}},
+		{"docker-info.json", func() (any, error) {
+			return r.Docker.ApiClient().Info(ctx)
+		}},
+		{"docker-version.json", func() (any, error) {
+			return r.Docker.ApiClient().ServerVersion(ctx)
+		}},
+		{"disk-usage.json", func() (any, error) {
+			return r.diskUsage(ctx)
+		}},
+		{"ruleset.txt", func() (any, error) {
+			return firewallRuleset(ctx)
+		}},
+		{"goroutines.txt", func() (any, error) {
+			return profile("goroutine", 2)
+		}},
+		{"heap.pprof", func() (any, error) {
+			return profile("heap", 0)
+		}},
+	}
+	if r.AbuseDetection != nil {
+		parts = append(parts, diagnosticsPart{"abuse-events.json", func() (any, error) {
+			return r.AbuseDetection.ListEvents(""), nil
+		}})
+	}
+
+	for _, part := range parts {
+		if err := collect(part.name, part.get); err != nil {
+			return err
+		}
+	}
+
+	if len(failures) > 0 {
+		if err := writeTarFile(tarWriter, "errors.txt", []byte(strings.Join(failures, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+// diskUsage returns the usage of the disk of the Docker data, along with what Docker uses it for
+func (r *Runner) diskUsage(ctx context.Context) (any, error) {
+	info, err := r.Docker.ApiClient().Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := disk.UsageWithContext(ctx, info.DockerRootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerUsage, err := r.Docker.ApiClient().DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var images, containers, volumes, buildCache int64
+	for _, image := range dockerUsage.Images {
+		images += image.Size
+	}
+	for _, container := range dockerUsage.Containers {
+		containers += container.SizeRw
+	}
+	for _, volume := range dockerUsage.Volumes {
+		if volume.UsageData != nil && volume.UsageData.Size > 0 {
+			volumes += volume.UsageData.Size
+		}
+	}
+	for _, cache := range dockerUsage.BuildCache {
+		buildCache += cache.Size
+	}
+
+	return map[string]any{
+		"host": host,
+		"docker": map[string]int64{
+			"layersBytes":     dockerUsage.LayersSize,
+			"imagesBytes":     images,
+			"containersBytes": containers,
+			"volumesBytes":    volumes,
+			"buildCacheBytes": buildCache,
+		},
+	}, nil
+}
+
+// firewallRuleset returns the nftables ruleset of the host, or its iptables rules if nft isn't
+// installed
+func firewallRuleset(ctx context.Context) ([]byte, error) {
+	output, err := exec.CommandContext(ctx, "nft", "list", "ruleset").Output()
+	if err == nil {
+		return output, nil
+	}
+
+	output, iptablesErr := exec.CommandContext(ctx, "iptables-save").Output()
+	if iptablesErr != nil {
+		return nil, fmt.Errorf("nft: %v, iptables-save: %w", err, iptablesErr)
+	}
+
+	return output, nil
+}
+
+func profile(name string, debug int) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := pprof.Lookup(name).WriteTo(&buffer, debug); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func writeTarFile(tarWriter *tar.Writer, name string, content []byte) error {
+	err := tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tarWriter.Write(content)
+	return err
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package runner
+
+import (
+	"github.com/daytonaio/runner/cmd/runner/config"
+	"github.com/daytonaio/runner/pkg/admission"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ReloadConfig reloads the runner config and applies its hot-reloadable fields to the running
+// services. The config is left unchanged if the reloaded one is invalid. It returns the
+// environment variables that changed.
+func (r *Runner) ReloadConfig() ([]string, error) {
+	changed, err := config.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.LogLevel != "" {
+		// Validated by the reload
+		level, _ := log.ParseLevel(cfg.LogLevel)
+		log.SetLevel(level)
+	}
+
+	r.Admission.UpdateLimits(admission.AdmissionControllerConfig{
+		Enabled:               cfg.AdmissionControlEnabled,
+		CPUOvercommitRatio:    cfg.CPUOvercommitRatio,
+		MemoryOvercommitRatio: cfg.MemoryOvercommitRatio,
+		CPUUsageThreshold:     cfg.AdmissionCPUUsageThreshold,
+		MemoryUsageThreshold:  cfg.AdmissionMemoryUsageThreshold,
+		RetryAfter:            cfg.AdmissionRetryAfter,
+	})
+
+	r.StorageUsage.UpdatePressureSettings(cfg.StoragePressureThresholds, cfg.StoragePressureAutoRecover)
+
+	if r.LayerCache != nil {
+		r.LayerCache.UpdateMirrors(cfg.LayerCachePeers, cfg.LayerCacheUpstream)
+	}
+
+	log.Infof("Runner config reloaded, changed: %v", changed)
+	return changed, nil
+}
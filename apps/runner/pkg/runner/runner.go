@@ -7,9 +7,15 @@ import (
 	"log"
 
 	"github.com/daytonaio/runner/internal/metrics"
+	"github.com/daytonaio/runner/internal/util"
+	"github.com/daytonaio/runner/pkg/admission"
+	"github.com/daytonaio/runner/pkg/audit"
 	"github.com/daytonaio/runner/pkg/cache"
 	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/egressproxy"
+	"github.com/daytonaio/runner/pkg/layercache"
 	"github.com/daytonaio/runner/pkg/netrules"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
 	"github.com/daytonaio/runner/pkg/services"
 	"github.com/daytonaio/runner/pkg/sshgateway"
 )
@@ -21,6 +27,17 @@ type RunnerInstanceConfig struct {
 	SandboxService    *services.SandboxService
 	NetRulesManager   *netrules.NetRulesManager
 	SSHGatewayService *sshgateway.Service
+	Admission         *admission.AdmissionController
+	OrganizationQuota *services.OrganizationQuotaService
+	StorageUsage      *services.StorageUsageService
+	AbuseDetection    *services.AbuseDetectionService
+	Audit             *audit.Collector
+	EgressProxy       *egressproxy.Proxy
+	LayerCache        *layercache.Service
+	Reaper            *services.OrphanReaperService
+	Jobs              *services.JobHistoryService
+	Logs              *util.LogBuffer
+	Locks             *sandboxlock.Manager
 }
 
 type Runner struct {
@@ -30,6 +47,17 @@ type Runner struct {
 	SandboxService    *services.SandboxService
 	NetRulesManager   *netrules.NetRulesManager
 	SSHGatewayService *sshgateway.Service
+	Admission         *admission.AdmissionController
+	OrganizationQuota *services.OrganizationQuotaService
+	StorageUsage      *services.StorageUsageService
+	AbuseDetection    *services.AbuseDetectionService
+	Audit             *audit.Collector
+	EgressProxy       *egressproxy.Proxy
+	LayerCache        *layercache.Service
+	Reaper            *services.OrphanReaperService
+	Jobs              *services.JobHistoryService
+	Logs              *util.LogBuffer
+	Locks             *sandboxlock.Manager
 }
 
 var runner *Runner
@@ -51,6 +79,17 @@ func GetInstance(config *RunnerInstanceConfig) *Runner {
 			MetricsCollector:  config.MetricsCollector,
 			NetRulesManager:   config.NetRulesManager,
 			SSHGatewayService: config.SSHGatewayService,
+			Admission:         config.Admission,
+			OrganizationQuota: config.OrganizationQuota,
+			StorageUsage:      config.StorageUsage,
+			AbuseDetection:    config.AbuseDetection,
+			Audit:             config.Audit,
+			EgressProxy:       config.EgressProxy,
+			LayerCache:        config.LayerCache,
+			Reaper:            config.Reaper,
+			Jobs:              config.Jobs,
+			Logs:              config.Logs,
+			Locks:             config.Locks,
 		}
 	}
 
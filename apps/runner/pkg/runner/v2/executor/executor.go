@@ -18,22 +18,48 @@ import (
 
 	apiclient "github.com/daytonaio/daytona/libs/api-client-go"
 	"github.com/daytonaio/runner/internal/metrics"
+	"github.com/daytonaio/runner/pkg/admission"
+	"github.com/daytonaio/runner/pkg/api/dto"
 	runnerapiclient "github.com/daytonaio/runner/pkg/apiclient"
+	"github.com/daytonaio/runner/pkg/common"
 	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/faults"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
+	"github.com/daytonaio/runner/pkg/services"
 )
 
 type ExecutorConfig struct {
-	Docker    *docker.DockerClient
-	Collector *metrics.Collector
-	Logger    *slog.Logger
+	Docker            docker.ContainerRuntime
+	Collector         *metrics.Collector
+	Admission         *admission.AdmissionController
+	OrganizationQuota *services.OrganizationQuotaService
+	Jobs              *services.JobHistoryService
+	Locks             *sandboxlock.Manager
+	Logger            *slog.Logger
+}
+
+// Operations of the sandbox jobs, which lock their sandbox while they run
+var sandboxJobOperations = map[apiclient.JobType]sandboxlock.Operation{
+	apiclient.JOBTYPE_CREATE_SANDBOX:                  sandboxlock.OperationCreate,
+	apiclient.JOBTYPE_START_SANDBOX:                   sandboxlock.OperationStart,
+	apiclient.JOBTYPE_STOP_SANDBOX:                    sandboxlock.OperationStop,
+	apiclient.JOBTYPE_DESTROY_SANDBOX:                 sandboxlock.OperationDestroy,
+	apiclient.JOBTYPE_RESIZE_SANDBOX:                  sandboxlock.OperationResize,
+	apiclient.JOBTYPE_CREATE_BACKUP:                   sandboxlock.OperationBackup,
+	apiclient.JOBTYPE_UPDATE_SANDBOX_NETWORK_SETTINGS: sandboxlock.OperationNetworkSettings,
+	apiclient.JOBTYPE_RECOVER_SANDBOX:                 sandboxlock.OperationRecover,
 }
 
 // Executor handles job execution
 type Executor struct {
 	log       *slog.Logger
 	client    *apiclient.APIClient
-	docker    *docker.DockerClient
+	docker    docker.ContainerRuntime
 	collector *metrics.Collector
+	admission *admission.AdmissionController
+	orgQuota  *services.OrganizationQuotaService
+	jobs      *services.JobHistoryService
+	locks     *sandboxlock.Manager
 }
 
 // NewExecutor creates a new job executor
@@ -48,6 +74,10 @@ func NewExecutor(cfg *ExecutorConfig) (*Executor, error) {
 		client:    apiClient,
 		docker:    cfg.Docker,
 		collector: cfg.Collector,
+		admission: cfg.Admission,
+		orgQuota:  cfg.OrganizationQuota,
+		jobs:      cfg.Jobs,
+		locks:     cfg.Locks,
 	}, nil
 }
 
@@ -79,9 +109,22 @@ func (e *Executor) Execute(ctx context.Context, job *apiclient.Job) {
 	}
 
 	jobLog.Info("Executing job")
+	e.jobs.Start(job.GetId(), string(job.GetType()), job.GetResourceType(), job.GetResourceId())
+
+	// Jobs that recreate a sandbox container report the progress of copying its data
+	ctx = common.WithCopyProgress(ctx, func(progress common.CopyProgress) {
+		e.jobs.SetProgress(job.GetId(), dto.JobProgressDTO{
+			BytesTotal:  progress.BytesTotal,
+			BytesCopied: progress.BytesCopied,
+			FilesTotal:  progress.FilesTotal,
+			FilesCopied: progress.FilesCopied,
+			EtaSeconds:  int64(progress.Eta.Seconds()),
+		})
+	})
 
 	// Execute the job based on type
 	resultMetadata, err := e.executeJob(ctx, job)
+	e.jobs.Finish(job.GetId(), err)
 
 	// Update job status
 	status := apiclient.JOBSTATUS_COMPLETED
@@ -122,6 +165,23 @@ func (e *Executor) executeJob(ctx context.Context, job *apiclient.Job) (any, err
 		span.SetAttributes(attribute.String("resource.id", resourceId))
 	}
 
+	if err := faults.Inject(ctx, faults.PointExecutorJob, string(job.GetType())); err != nil {
+		span.RecordError(err)
+		span.SetAttributes(attribute.Bool("error", true))
+		return nil, err
+	}
+
+	if operation, ok := sandboxJobOperations[job.GetType()]; ok && job.GetResourceId() != "" {
+		lockCtx, release, err := e.locks.Acquire(ctx, job.GetResourceId(), operation)
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(attribute.Bool("error", true))
+			return nil, err
+		}
+		defer release()
+		ctx = lockCtx
+	}
+
 	// Dispatch to handler
 	var resultMetadata any
 	var err error
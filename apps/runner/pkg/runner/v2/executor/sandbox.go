@@ -8,6 +8,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
 	apiclient "github.com/daytonaio/daytona/libs/api-client-go"
 	"github.com/daytonaio/runner/pkg/api/dto"
@@ -21,6 +22,19 @@ func (e *Executor) createSandbox(ctx context.Context, job *apiclient.Job) (any,
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
+	err = e.admission.AdmitCreate(ctx, createSandboxDto)
+	if err != nil {
+		common.ContainerOperationCount.WithLabelValues("create", string(common.PrometheusOperationStatusFailure)).Inc()
+		return nil, err
+	}
+
+	releaseQuota, err := e.orgQuota.ReserveCreate(ctx, createSandboxDto)
+	if err != nil {
+		common.ContainerOperationCount.WithLabelValues("create", string(common.PrometheusOperationStatusFailure)).Inc()
+		return nil, err
+	}
+	defer releaseQuota()
+
 	_, daemonVersion, err := e.docker.Create(ctx, createSandboxDto)
 	if err != nil {
 		// TODO: is this needed?
@@ -31,9 +45,23 @@ func (e *Executor) createSandbox(ctx context.Context, job *apiclient.Job) (any,
 
 	common.ContainerOperationCount.WithLabelValues("create", string(common.PrometheusOperationStatusSuccess)).Inc()
 
-	return dto.StartSandboxResponse{
+	response := dto.StartSandboxResponse{
 		DaemonVersion: daemonVersion,
-	}, nil
+	}
+
+	if createSandboxDto.Devcontainer != nil {
+		// Provisioning steps are reported as the progress of the create job
+		response.Devcontainer, err = e.docker.ApplyDevcontainer(ctx, createSandboxDto.Id, createSandboxDto.OsUser, *createSandboxDto.Devcontainer, func(progress dto.DevcontainerProgressDTO) {
+			if err := e.updateJobStatus(ctx, job.GetId(), apiclient.JOBSTATUS_IN_PROGRESS, progress, nil); err != nil {
+				e.log.Warn("Failed to report devcontainer progress", slog.String("job_id", job.GetId()), slog.Any("error", err))
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to provision sandbox from devcontainer.json: %w", err)
+		}
+	}
+
+	return response, nil
 }
 
 func (e *Executor) startSandbox(ctx context.Context, job *apiclient.Job) (any, error) {
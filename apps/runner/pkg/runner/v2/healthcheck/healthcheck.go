@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync/atomic"
 	"time"
 
 	apiclient "github.com/daytonaio/daytona/libs/api-client-go"
@@ -26,6 +27,18 @@ type HealthcheckServiceConfig struct {
 	ApiPort    int
 	ProxyPort  int
 	TlsEnabled bool
+	// How long the runner holds its lease after a successful healthcheck. The control plane stops
+	// scheduling on runners that don't report within the lease, so they shouldn't take on jobs either.
+	LeaseDuration time.Duration
+	// Subsystems the runner can't take on jobs without. No healthchecks are sent while one of them
+	// is unhealthy, so that the runner loses its lease.
+	Checks []Check
+}
+
+// Check reports the health of a subsystem of the runner
+type Check struct {
+	Name    string
+	Healthy func() error
 }
 
 // Service handles healthcheck reporting to the API
@@ -39,6 +52,10 @@ type Service struct {
 	apiPort    int
 	proxyPort  int
 	tlsEnabled bool
+	// Unix nanoseconds of the end of the lease
+	leaseExpiresAt atomic.Int64
+	leaseDuration  time.Duration
+	checks         []Check
 }
 
 // NewService creates a new healthcheck service
@@ -49,15 +66,17 @@ func NewService(cfg *HealthcheckServiceConfig) (*Service, error) {
 	}
 
 	return &Service{
-		log:        cfg.Logger.With(slog.String("component", "healthcheck")),
-		client:     apiClient,
-		interval:   cfg.Interval,
-		timeout:    cfg.Timeout,
-		collector:  cfg.Collector,
-		domain:     cfg.Domain,
-		apiPort:    cfg.ApiPort,
-		proxyPort:  cfg.ProxyPort,
-		tlsEnabled: cfg.TlsEnabled,
+		log:           cfg.Logger.With(slog.String("component", "healthcheck")),
+		client:        apiClient,
+		interval:      cfg.Interval,
+		timeout:       cfg.Timeout,
+		collector:     cfg.Collector,
+		domain:        cfg.Domain,
+		apiPort:       cfg.ApiPort,
+		proxyPort:     cfg.ProxyPort,
+		tlsEnabled:    cfg.TlsEnabled,
+		leaseDuration: cfg.LeaseDuration,
+		checks:        cfg.Checks,
 	}, nil
 }
 
@@ -79,6 +98,9 @@ func (s *Service) Start(ctx context.Context) {
 		case <-ticker.C:
 			if err := s.sendHealthcheck(ctx); err != nil {
 				s.log.Warn("Failed to send healthcheck", slog.Any("error", err))
+				if !s.HasLease() {
+					s.log.Error("Runner lease expired, no healthcheck was accepted within the lease duration", slog.Duration("leaseDuration", s.leaseDuration))
+				}
 				// Continue trying - don't crash
 			}
 		}
@@ -87,6 +109,12 @@ func (s *Service) Start(ctx context.Context) {
 
 // sendHealthcheck sends a healthcheck to the API
 func (s *Service) sendHealthcheck(ctx context.Context) error {
+	for _, check := range s.checks {
+		if err := check.Healthy(); err != nil {
+			return fmt.Errorf("%s is unhealthy: %w", check.Name, err)
+		}
+	}
+
 	// Create context with timeout
 	reqCtx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
@@ -134,6 +162,19 @@ func (s *Service) sendHealthcheck(ctx context.Context) error {
 		return err
 	}
 
+	if s.leaseDuration > 0 {
+		s.leaseExpiresAt.Store(time.Now().Add(s.leaseDuration).UnixNano())
+	}
+
 	s.log.Debug("Healthcheck sent successfully")
 	return nil
 }
+
+// HasLease reports if a healthcheck was accepted within the lease duration. Runners without a
+// lease duration always hold their lease.
+func (s *Service) HasLease() bool {
+	if s.leaseDuration <= 0 {
+		return true
+	}
+	return time.Now().UnixNano() < s.leaseExpiresAt.Load()
+}
@@ -14,6 +14,7 @@ import (
 	apiclient "github.com/daytonaio/daytona/libs/api-client-go"
 	runnerapiclient "github.com/daytonaio/runner/pkg/apiclient"
 	"github.com/daytonaio/runner/pkg/runner/v2/executor"
+	"github.com/daytonaio/runner/pkg/runner/v2/healthcheck"
 )
 
 type PollerServiceConfig struct {
@@ -21,6 +22,7 @@ type PollerServiceConfig struct {
 	PollLimit   int
 	Logger      *slog.Logger
 	Executor    *executor.Executor
+	Healthcheck *healthcheck.Service
 }
 
 // Service handles job polling from the API
@@ -29,6 +31,7 @@ type Service struct {
 	pollTimeout time.Duration
 	pollLimit   int
 	executor    *executor.Executor
+	healthcheck *healthcheck.Service
 	client      *apiclient.APIClient
 }
 
@@ -44,6 +47,7 @@ func NewService(cfg *PollerServiceConfig) (*Service, error) {
 		pollTimeout: cfg.PollTimeout,
 		pollLimit:   cfg.PollLimit,
 		executor:    cfg.Executor,
+		healthcheck: cfg.Healthcheck,
 		client:      apiClient,
 	}, nil
 }
@@ -73,6 +77,12 @@ func (s *Service) Start(ctx context.Context) {
 			s.log.Info("Job poller stopped")
 			return
 		default:
+			// Jobs aren't taken on without a lease, the control plane may have moved them elsewhere
+			if s.healthcheck != nil && !s.healthcheck.HasLease() {
+				time.Sleep(time.Second)
+				continue
+			}
+
 			// Poll for jobs
 			jobs, err := s.pollJobs(ctx)
 			if err != nil {
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2025 Daytona Platforms Inc.
+ * SPDX-License-Identifier: AGPL-3.0
+ */
+
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	apiclient "github.com/daytonaio/daytona/libs/api-client-go"
+	runnerapiclient "github.com/daytonaio/runner/pkg/apiclient"
+)
+
+type RegistrationServiceConfig struct {
+	ApiUrl string
+	// Token allowed to create runners, used instead of a provisioned runner token
+	RegistrationToken string
+	RegionId          string
+	Name              string
+	// File the credentials of the registered runner are kept in, so restarts don't register it again
+	CredentialsFile string
+	Logger          *slog.Logger
+}
+
+// Credentials of a registered runner
+type Credentials struct {
+	Id     string `json:"id"`
+	ApiKey string `json:"apiKey"`
+}
+
+// Service registers the runner with the control plane, so runners started by auto-scaling
+// groups join the fleet without being provisioned upfront
+type Service struct {
+	log             *slog.Logger
+	client          *apiclient.APIClient
+	regionId        string
+	name            string
+	credentialsFile string
+}
+
+// NewService creates a new registration service
+func NewService(cfg *RegistrationServiceConfig) *Service {
+	clientConfig := apiclient.NewConfiguration()
+	clientConfig.Servers = apiclient.ServerConfigurations{
+		{
+			URL: cfg.ApiUrl,
+		},
+	}
+	clientConfig.AddDefaultHeader("Authorization", "Bearer "+cfg.RegistrationToken)
+	clientConfig.AddDefaultHeader(runnerapiclient.DaytonaSourceHeader, "runner")
+	clientConfig.HTTPClient = &http.Client{
+		Transport: http.DefaultTransport,
+	}
+
+	return &Service{
+		log:             cfg.Logger.With(slog.String("component", "registration")),
+		client:          apiclient.NewAPIClient(clientConfig),
+		regionId:        cfg.RegionId,
+		name:            cfg.Name,
+		credentialsFile: cfg.CredentialsFile,
+	}
+}
+
+// Register returns the credentials of the runner, registering it if it hasn't been registered yet
+func (s *Service) Register(ctx context.Context) (*Credentials, error) {
+	credentials, err := s.loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if credentials != nil {
+		s.log.Info("Using existing runner registration", slog.String("runnerId", credentials.Id))
+		return credentials, nil
+	}
+
+	resp, _, err := s.client.RunnersAPI.CreateRunner(ctx).CreateRunner(*apiclient.NewCreateRunner(s.regionId, s.name)).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to register runner: %w", err)
+	}
+
+	credentials = &Credentials{
+		Id:     resp.Id,
+		ApiKey: resp.ApiKey,
+	}
+
+	if err := s.saveCredentials(credentials); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Registered runner", slog.String("runnerId", credentials.Id), slog.String("regionId", s.regionId), slog.String("name", s.name))
+	return credentials, nil
+}
+
+// Deregister removes the runner from the control plane, e.g. when its instance is scaled in
+func (s *Service) Deregister(ctx context.Context) error {
+	credentials, err := s.loadCredentials()
+	if err != nil || credentials == nil {
+		return err
+	}
+
+	_, err = s.client.RunnersAPI.DeleteRunner(ctx, credentials.Id).Execute()
+	if err != nil {
+		return fmt.Errorf("failed to deregister runner: %w", err)
+	}
+
+	if err := os.Remove(s.credentialsFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	s.log.Info("Deregistered runner", slog.String("runnerId", credentials.Id))
+	return nil
+}
+
+func (s *Service) loadCredentials() (*Credentials, error) {
+	content, err := os.ReadFile(s.credentialsFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read runner credentials: %w", err)
+	}
+
+	var credentials Credentials
+	if err := json.Unmarshal(content, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to parse runner credentials: %w", err)
+	}
+
+	return &credentials, nil
+}
+
+func (s *Service) saveCredentials(credentials *Credentials) error {
+	content, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.credentialsFile), 0700); err != nil {
+		return fmt.Errorf("failed to save runner credentials: %w", err)
+	}
+
+	if err := os.WriteFile(s.credentialsFile, content, 0600); err != nil {
+		return fmt.Errorf("failed to save runner credentials: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package sandboxlock serializes the operations on a sandbox, which the API handlers, the job
+// executor and storage recovery otherwise run concurrently. Operations that can't run after the one
+// in progress are rejected with a ConflictError, operations that supersede it cancel it, and the
+// others wait for it.
+package sandboxlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Operation string
+
+const (
+	OperationCreate          Operation = "create"
+	OperationStart           Operation = "start"
+	OperationStop            Operation = "stop"
+	OperationDestroy         Operation = "destroy"
+	OperationBackup          Operation = "backup"
+	OperationResize          Operation = "resize"
+	OperationClone           Operation = "clone"
+	OperationRecover         Operation = "recover"
+	OperationStorageRecovery Operation = "storage-recovery"
+	OperationNetworkSettings Operation = "network-settings"
+	OperationDaemonUpgrade   Operation = "daemon-upgrade"
+	OperationLeftoverCleanup Operation = "leftover-cleanup"
+)
+
+// Operations that recreate the container of a sandbox or clean up after that, of which only one may
+// run at a time
+var recreating = []Operation{OperationResize, OperationRecover, OperationStorageRecovery, OperationLeftoverCleanup}
+
+// ConflictError is returned when an operation is rejected because of the operation in progress
+type ConflictError struct {
+	SandboxId  string
+	Operation  Operation
+	InProgress Operation
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("cannot %s sandbox %s while %s is in progress", e.Operation, e.SandboxId, e.InProgress)
+}
+
+// ErrPreempted is the cause of the context of an operation canceled by a superseding one
+var ErrPreempted = errors.New("operation preempted")
+
+type lock struct {
+	operation Operation
+	since     time.Time
+	cancel    context.CancelCauseFunc
+	released  chan struct{}
+}
+
+type Manager struct {
+	mutex sync.Mutex
+	locks map[string]*lock
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		locks: map[string]*lock{},
+	}
+}
+
+// Acquire locks a sandbox for an operation, waiting for the operation in progress unless the new
+// one is rejected or supersedes it. The operation runs with the returned context, which is canceled
+// if a superseding operation is requested, and calls release when done.
+func (m *Manager) Acquire(ctx context.Context, sandboxId string, operation Operation) (context.Context, func(), error) {
+	for {
+		m.mutex.Lock()
+
+		held, ok := m.locks[sandboxId]
+		if !ok {
+			lockCtx, cancel := context.WithCancelCause(ctx)
+			acquired := &lock{
+				operation: operation,
+				since:     time.Now(),
+				cancel:    cancel,
+				released:  make(chan struct{}),
+			}
+			m.locks[sandboxId] = acquired
+			m.mutex.Unlock()
+
+			return lockCtx, m.releaseFunc(sandboxId, acquired), nil
+		}
+
+		switch {
+		case rejects(operation, held.operation):
+			m.mutex.Unlock()
+			return nil, nil, &ConflictError{
+				SandboxId:  sandboxId,
+				Operation:  operation,
+				InProgress: held.operation,
+			}
+		case preempts(operation, held.operation):
+			log.Infof("Canceling %s of sandbox %s for %s", held.operation, sandboxId, operation)
+			held.cancel(fmt.Errorf("%w by %s", ErrPreempted, operation))
+		}
+
+		released := held.released
+		m.mutex.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("waiting for %s of sandbox %s: %w", held.operation, sandboxId, context.Cause(ctx))
+		}
+	}
+}
+
+// InProgress returns the operation in progress on a sandbox, if any
+func (m *Manager) InProgress(sandboxId string) (Operation, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	held, ok := m.locks[sandboxId]
+	if !ok {
+		return "", false
+	}
+	return held.operation, true
+}
+
+func (m *Manager) releaseFunc(sandboxId string, acquired *lock) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+
+			acquired.cancel(nil)
+			if m.locks[sandboxId] == acquired {
+				delete(m.locks, sandboxId)
+			}
+			close(acquired.released)
+
+			log.Debugf("%s of sandbox %s held its lock for %s", acquired.operation, sandboxId, time.Since(acquired.since))
+		})
+	}
+}
+
+// rejects returns whether an operation is rejected while another one is in progress. Nothing but
+// destroying again may follow a destroy, and a sandbox is recreated once at a time.
+func rejects(operation, inProgress Operation) bool {
+	if inProgress == OperationDestroy {
+		return operation != OperationDestroy
+	}
+	return slices.Contains(recreating, operation) && slices.Contains(recreating, inProgress)
+}
+
+// Operations that cancel a backup in progress, as they did before backups took the lock
+var preemptingBackup = []Operation{OperationStart, OperationStop, OperationDestroy, OperationBackup}
+
+// preempts returns whether an operation cancels the one in progress
+func preempts(operation, inProgress Operation) bool {
+	return inProgress == OperationBackup && slices.Contains(preemptingBackup, operation)
+}
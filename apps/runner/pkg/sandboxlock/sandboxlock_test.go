@@ -0,0 +1,121 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package sandboxlock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcquire(t *testing.T) {
+	tests := []struct {
+		name       string
+		inProgress Operation
+		operation  Operation
+		rejected   bool
+		preempted  bool
+	}{
+		{name: "start waits for stop", inProgress: OperationStop, operation: OperationStart},
+		{name: "destroy waits for resize", inProgress: OperationResize, operation: OperationDestroy},
+		{name: "destroy after destroy waits", inProgress: OperationDestroy, operation: OperationDestroy},
+		{name: "start after destroy is rejected", inProgress: OperationDestroy, operation: OperationStart, rejected: true},
+		{name: "backup after destroy is rejected", inProgress: OperationDestroy, operation: OperationBackup, rejected: true},
+		{name: "resize during recover is rejected", inProgress: OperationRecover, operation: OperationResize, rejected: true},
+		{name: "cleanup during storage recovery is rejected", inProgress: OperationStorageRecovery, operation: OperationLeftoverCleanup, rejected: true},
+		{name: "stop preempts backup", inProgress: OperationBackup, operation: OperationStop, preempted: true},
+		{name: "destroy preempts backup", inProgress: OperationBackup, operation: OperationDestroy, preempted: true},
+		{name: "backup preempts backup", inProgress: OperationBackup, operation: OperationBackup, preempted: true},
+		{name: "resize waits for backup", inProgress: OperationBackup, operation: OperationResize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager()
+
+			heldCtx, release, err := manager.Acquire(context.Background(), "sandbox", tt.inProgress)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			_, _, err = manager.Acquire(ctx, "sandbox", tt.operation)
+			if tt.rejected {
+				var conflict *ConflictError
+				if !errors.As(err, &conflict) {
+					t.Fatalf("expected a ConflictError, got %v", err)
+				}
+				if conflict.Operation != tt.operation || conflict.InProgress != tt.inProgress {
+					t.Fatalf("unexpected conflict: %v", conflict)
+				}
+				return
+			}
+
+			// The operation waits for the one in progress until its context expires
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected the operation to wait, got %v", err)
+			}
+
+			preempted := errors.Is(context.Cause(heldCtx), ErrPreempted)
+			if preempted != tt.preempted {
+				t.Fatalf("expected preempted %v, got %v (cause %v)", tt.preempted, preempted, context.Cause(heldCtx))
+			}
+		})
+	}
+}
+
+func TestAcquireAfterRelease(t *testing.T) {
+	manager := NewManager()
+
+	_, release, err := manager.Acquire(context.Background(), "sandbox", OperationStop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan error)
+	go func() {
+		_, release, err := manager.Acquire(context.Background(), "sandbox", OperationStart)
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+
+	if operation, ok := manager.InProgress("sandbox"); !ok || operation != OperationStop {
+		t.Fatalf("expected stop in progress, got %q", operation)
+	}
+
+	release()
+	// Releasing twice doesn't release the lock of the next operation
+	release()
+
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("start didn't acquire the lock released by stop")
+	}
+
+	if operation, ok := manager.InProgress("sandbox"); ok {
+		t.Fatalf("expected no operation in progress, got %q", operation)
+	}
+
+	// Locks of other sandboxes are independent
+	_, releaseOther, err := manager.Acquire(context.Background(), "other", OperationResize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer releaseOther()
+	_, release, err = manager.Acquire(context.Background(), "sandbox", OperationResize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
@@ -0,0 +1,308 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
+	"github.com/docker/docker/api/types/container"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Events kept for the abuse events endpoint
+const maxAbuseEvents = 1000
+
+type AbuseDetectionServiceConfig struct {
+	Docker   *docker.DockerClient
+	Interval time.Duration
+	// CPU usage in percent of the quota of a sandbox that counts as high
+	CpuThreshold int
+	// How long the CPU usage of a sandbox must stay high to raise the cpu signal
+	CpuWindow time.Duration
+	// Domains of mining pools, including their subdomains. Queries are only seen for sandboxes
+	// resolving through the runner DNS forwarder.
+	PoolDomains []string
+	// Names of miner processes
+	ProcessNames []string
+	// Stop sandboxes once they raised SuspendSignals different signals
+	AutoSuspend    bool
+	SuspendSignals int
+	Locks          *sandboxlock.Manager
+}
+
+type sandboxAbuseState struct {
+	lastCpuUsageNs uint64
+	lastSampledAt  time.Time
+	highCpuSince   time.Time
+	// Signals raised for the sandbox, so that each is only reported once
+	signals map[string]bool
+}
+
+// AbuseDetectionService watches running sandboxes for signs of cryptocurrency mining: sustained
+// high CPU usage, queries for the domains of mining pools and processes named after miners
+type AbuseDetectionService struct {
+	docker         *docker.DockerClient
+	interval       time.Duration
+	cpuThreshold   int
+	cpuWindow      time.Duration
+	poolDomains    []string
+	processNames   []string
+	autoSuspend    bool
+	suspendSignals int
+	locks          *sandboxlock.Manager
+
+	mutex  sync.Mutex
+	states map[string]*sandboxAbuseState
+	// Pool domains queried since the last sample, by sandbox IP address
+	poolQueries map[string]string
+	events      []dto.AbuseEventDTO
+}
+
+func NewAbuseDetectionService(config AbuseDetectionServiceConfig) *AbuseDetectionService {
+	poolDomains := make([]string, 0, len(config.PoolDomains))
+	for _, domain := range config.PoolDomains {
+		poolDomains = append(poolDomains, strings.ToLower(domain))
+	}
+
+	processNames := make([]string, 0, len(config.ProcessNames))
+	for _, name := range config.ProcessNames {
+		processNames = append(processNames, strings.ToLower(name))
+	}
+
+	return &AbuseDetectionService{
+		docker:         config.Docker,
+		interval:       config.Interval,
+		cpuThreshold:   config.CpuThreshold,
+		cpuWindow:      config.CpuWindow,
+		poolDomains:    poolDomains,
+		processNames:   processNames,
+		autoSuspend:    config.AutoSuspend,
+		suspendSignals: config.SuspendSignals,
+		locks:          config.Locks,
+		states:         make(map[string]*sandboxAbuseState),
+		poolQueries:    make(map[string]string),
+	}
+}
+
+// ObserveDnsQuery records a DNS query of a sandbox, it is passed to the DNS forwarder
+func (s *AbuseDetectionService) ObserveDnsQuery(sourceIp string, name string) {
+	if !common.IsDomainAllowed(name, s.poolDomains) {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.poolQueries[sourceIp] = strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+// ListEvents returns the signals raised on the runner, of a sandbox if its ID is set, oldest first
+func (s *AbuseDetectionService) ListEvents(sandboxId string) []dto.AbuseEventDTO {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	events := []dto.AbuseEventDTO{}
+	for _, event := range s.events {
+		if sandboxId == "" || event.SandboxId == sandboxId {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// StartDetection starts a background goroutine that checks the running sandboxes
+func (s *AbuseDetectionService) StartDetection(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.detect(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (s *AbuseDetectionService) detect(ctx context.Context) {
+	containers, err := s.docker.ApiClient().ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		log.Errorf("Failed to list sandboxes for abuse detection: %v", err)
+		return
+	}
+
+	s.mutex.Lock()
+	poolQueries := s.poolQueries
+	s.poolQueries = make(map[string]string)
+	s.mutex.Unlock()
+
+	running := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		if len(c.Names) == 0 || len(c.Names[0]) < 2 {
+			continue
+		}
+		if _, ok := c.Labels[common.SIDECAR_OF_LABEL]; ok {
+			continue
+		}
+		sandboxId := c.Names[0][1:]
+		running[sandboxId] = true
+
+		state := s.getState(sandboxId)
+		organizationId := c.Labels["daytona.organization_id"]
+
+		if c.NetworkSettings != nil {
+			for _, network := range c.NetworkSettings.Networks {
+				if network == nil {
+					continue
+				}
+				if domain, ok := poolQueries[network.IPAddress]; ok {
+					s.raise(ctx, sandboxId, organizationId, state, dto.AbuseSignalPoolDomain, domain)
+				}
+			}
+		}
+
+		cpuPercent, ok := s.sampleCpu(ctx, sandboxId, state)
+		if !ok {
+			continue
+		}
+
+		if cpuPercent < float64(s.cpuThreshold) {
+			state.highCpuSince = time.Time{}
+			continue
+		}
+
+		if state.highCpuSince.IsZero() {
+			state.highCpuSince = time.Now()
+		}
+		if time.Since(state.highCpuSince) >= s.cpuWindow {
+			s.raise(ctx, sandboxId, organizationId, state, dto.AbuseSignalCpu, fmt.Sprintf("%.0f%% of the CPU quota for %s", cpuPercent, time.Since(state.highCpuSince).Round(time.Minute)))
+		}
+
+		// Miners keep the CPU busy, so the processes of idle sandboxes aren't listed
+		s.checkProcesses(ctx, sandboxId, organizationId, state)
+	}
+
+	// States of stopped sandboxes are dropped, their signals are raised again if they recur
+	s.mutex.Lock()
+	for sandboxId := range s.states {
+		if !running[sandboxId] {
+			delete(s.states, sandboxId)
+		}
+	}
+	s.mutex.Unlock()
+}
+
+func (s *AbuseDetectionService) getState(sandboxId string) *sandboxAbuseState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, ok := s.states[sandboxId]
+	if !ok {
+		state = &sandboxAbuseState{signals: make(map[string]bool)}
+		s.states[sandboxId] = state
+	}
+
+	return state
+}
+
+// sampleCpu returns the CPU usage of a sandbox since the last sample in percent of its quota. It
+// returns false for the first sample and for sandboxes without a CPU quota.
+func (s *AbuseDetectionService) sampleCpu(ctx context.Context, sandboxId string, state *sandboxAbuseState) (float64, bool) {
+	c, err := s.docker.ContainerInspect(ctx, sandboxId)
+	if err != nil || c.HostConfig == nil || c.HostConfig.CPUQuota <= 0 || c.HostConfig.CPUPeriod <= 0 {
+		return 0, false
+	}
+	cpus := float64(c.HostConfig.CPUQuota) / float64(c.HostConfig.CPUPeriod)
+
+	stats, err := s.docker.GetSandboxStats(ctx, sandboxId)
+	if err != nil {
+		log.Debugf("Failed to sample CPU usage of sandbox %s: %v", sandboxId, err)
+		return 0, false
+	}
+
+	now := time.Now()
+	lastCpuUsageNs, lastSampledAt := state.lastCpuUsageNs, state.lastSampledAt
+	state.lastCpuUsageNs, state.lastSampledAt = stats.CpuUsageNs, now
+
+	if lastSampledAt.IsZero() || stats.CpuUsageNs < lastCpuUsageNs {
+		return 0, false
+	}
+
+	elapsed := now.Sub(lastSampledAt)
+	return float64(stats.CpuUsageNs-lastCpuUsageNs) / float64(elapsed.Nanoseconds()) / cpus * 100, true
+}
+
+func (s *AbuseDetectionService) checkProcesses(ctx context.Context, sandboxId, organizationId string, state *sandboxAbuseState) {
+	processes, err := s.docker.ListSandboxProcesses(ctx, sandboxId)
+	if err != nil {
+		log.Debugf("Failed to list processes of sandbox %s: %v", sandboxId, err)
+		return
+	}
+
+	for _, process := range processes {
+		if slices.Contains(s.processNames, strings.ToLower(process.Name)) {
+			s.raise(ctx, sandboxId, organizationId, state, dto.AbuseSignalProcess, process.Name)
+			return
+		}
+	}
+}
+
+// raise records a signal of a sandbox the first time it is seen, and suspends the sandbox if it
+// raised enough different signals
+func (s *AbuseDetectionService) raise(ctx context.Context, sandboxId, organizationId string, state *sandboxAbuseState, signal, detail string) {
+	if state.signals[signal] {
+		return
+	}
+	state.signals[signal] = true
+
+	suspend := s.autoSuspend && len(state.signals) >= s.suspendSignals
+
+	log.Warnf("Possible abuse by sandbox %s: %s signal (%s)", sandboxId, signal, detail)
+
+	s.mutex.Lock()
+	s.events = append(s.events, dto.AbuseEventDTO{
+		SandboxId:      sandboxId,
+		OrganizationId: organizationId,
+		Signal:         signal,
+		Detail:         detail,
+		Suspended:      suspend,
+		Time:           time.Now(),
+	})
+	if len(s.events) > maxAbuseEvents {
+		s.events = s.events[len(s.events)-maxAbuseEvents:]
+	}
+	s.mutex.Unlock()
+
+	if suspend {
+		log.Warnf("Suspending sandbox %s after %d abuse signals", sandboxId, len(state.signals))
+		if err := s.suspend(ctx, sandboxId); err != nil {
+			log.Errorf("Failed to suspend sandbox %s: %v", sandboxId, err)
+		}
+	}
+}
+
+// suspend stops a sandbox under its lock, waiting for the operation in progress or canceling a backup
+func (s *AbuseDetectionService) suspend(ctx context.Context, sandboxId string) error {
+	lockCtx, release, err := s.locks.Acquire(ctx, sandboxId, sandboxlock.OperationStop)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return s.docker.Stop(lockCtx, sandboxId)
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package services
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+// Jobs kept for the jobs endpoint
+const maxJobs = 500
+
+const (
+	JobStatusInProgress = "IN_PROGRESS"
+	JobStatusCompleted  = "COMPLETED"
+	JobStatusFailed     = "FAILED"
+)
+
+// JobHistoryService keeps the jobs the runner executed last, for operators to follow what the
+// runner is doing. Jobs are only executed with version 2 of the runner API.
+type JobHistoryService struct {
+	mutex    sync.Mutex
+	sequence int64
+	// Oldest first
+	jobs []*dto.JobDTO
+}
+
+func NewJobHistoryService() *JobHistoryService {
+	return &JobHistoryService{}
+}
+
+// Start records a job the runner started executing
+func (s *JobHistoryService) Start(id, jobType, resourceType, resourceId string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sequence++
+	s.jobs = append(s.jobs, &dto.JobDTO{
+		Sequence:     s.sequence,
+		Id:           id,
+		Type:         jobType,
+		ResourceType: resourceType,
+		ResourceId:   resourceId,
+		Status:       JobStatusInProgress,
+		StartedAt:    time.Now(),
+	})
+
+	if len(s.jobs) > maxJobs {
+		s.jobs = s.jobs[len(s.jobs)-maxJobs:]
+	}
+}
+
+// SetProgress records the progress of a job in progress
+func (s *JobHistoryService) SetProgress(id string, progress dto.JobProgressDTO) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		job := s.jobs[i]
+		if job.Id != id || job.FinishedAt != nil {
+			continue
+		}
+
+		s.sequence++
+		job.Sequence = s.sequence
+		job.Progress = &progress
+		return
+	}
+}
+
+// Finish records the outcome of a job, it failed if err is set
+func (s *JobHistoryService) Finish(id string, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		job := s.jobs[i]
+		if job.Id != id || job.FinishedAt != nil {
+			continue
+		}
+
+		now := time.Now()
+		s.sequence++
+		job.Sequence = s.sequence
+		job.FinishedAt = &now
+		job.Status = JobStatusCompleted
+		if err != nil {
+			job.Status = JobStatusFailed
+			job.Error = err.Error()
+		}
+		return
+	}
+}
+
+// List returns the jobs updated after the given sequence, in the order of their last update
+func (s *JobHistoryService) List(since int64) []dto.JobDTO {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	jobs := []dto.JobDTO{}
+	for _, job := range s.jobs {
+		if job.Sequence > since {
+			jobs = append(jobs, *job)
+		}
+	}
+
+	slices.SortFunc(jobs, func(a, b dto.JobDTO) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+
+	return jobs
+}
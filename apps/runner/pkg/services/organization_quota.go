@@ -0,0 +1,255 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+const organizationIdLabel = "daytona.organization_id"
+
+type organizationReservation struct {
+	organizationId string
+	cpu            float64
+	memory         float64
+	disk           float64
+}
+
+// OrganizationQuotaService tracks per-organization resource consumption on the runner
+// and enforces the quotas supplied by the control plane
+type OrganizationQuotaService struct {
+	docker *docker.DockerClient
+
+	mutex  sync.Mutex
+	quotas map[string]dto.OrganizationQuotaDTO
+	// Sandboxes that passed the quota check but whose containers may not exist yet
+	reservations map[string]organizationReservation
+	// Serialize the quota checks of each organization, held while its usage is computed
+	organizationLocks map[string]*sync.Mutex
+}
+
+func NewOrganizationQuotaService(docker *docker.DockerClient) *OrganizationQuotaService {
+	return &OrganizationQuotaService{
+		docker:            docker,
+		quotas:            make(map[string]dto.OrganizationQuotaDTO),
+		reservations:      make(map[string]organizationReservation),
+		organizationLocks: make(map[string]*sync.Mutex),
+	}
+}
+
+func (s *OrganizationQuotaService) SetQuota(organizationId string, quota dto.OrganizationQuotaDTO) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.quotas[organizationId] = quota
+}
+
+func (s *OrganizationQuotaService) RemoveQuota(organizationId string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.quotas, organizationId)
+}
+
+func (s *OrganizationQuotaService) GetUsage(ctx context.Context, organizationId string) (*dto.OrganizationUsageDTO, error) {
+	usage, _, err := s.getUsage(ctx, organizationId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if quota, ok := s.quotas[organizationId]; ok {
+		usage.Quota = &quota
+	}
+
+	return usage, nil
+}
+
+// ReserveCreate checks the organization quota for a new sandbox and reserves its resources.
+// The returned release function must be called once the create operation has finished.
+func (s *OrganizationQuotaService) ReserveCreate(ctx context.Context, sandboxDto dto.CreateSandboxDTO) (func(), error) {
+	release := func() {}
+
+	organizationId := sandboxDto.Metadata["organizationId"]
+	if organizationId == "" {
+		return release, nil
+	}
+
+	if sandboxDto.OrganizationQuota != nil {
+		s.SetQuota(organizationId, *sandboxDto.OrganizationQuota)
+	}
+
+	// Hold the lock of the organization while checking so concurrent creates can't both squeeze
+	// under the quota. Creates of other organizations don't wait for its usage to be computed.
+	organizationLock := s.organizationLock(organizationId)
+	organizationLock.Lock()
+	defer organizationLock.Unlock()
+
+	s.mutex.Lock()
+	quota, ok := s.quotas[organizationId]
+	s.mutex.Unlock()
+	if !ok {
+		return release, nil
+	}
+
+	usage, exists, err := s.getUsage(ctx, organizationId, sandboxDto.Id)
+	if err != nil {
+		return release, common.NewResourceExhaustedError(
+			http.StatusServiceUnavailable,
+			"ORGANIZATION_USAGE_UNAVAILABLE",
+			fmt.Sprintf("failed to check quota of organization %s: %v", organizationId, err),
+			5*time.Second,
+		)
+	}
+
+	// The sandbox is already accounted for (e.g. a retried create)
+	if exists {
+		return release, nil
+	}
+
+	s.mutex.Lock()
+	for id, r := range s.reservations {
+		if r.organizationId != organizationId || id == sandboxDto.Id {
+			continue
+		}
+		usage.SandboxCount++
+		usage.Cpu += r.cpu
+		usage.Memory += r.memory
+		usage.Disk += r.disk
+	}
+	s.mutex.Unlock()
+
+	err = checkOrganizationQuota(organizationId, quota, usage, sandboxDto)
+	if err != nil {
+		return release, err
+	}
+
+	cpu, memory := requestedCpuMemory(sandboxDto)
+	s.mutex.Lock()
+	s.reservations[sandboxDto.Id] = organizationReservation{
+		organizationId: organizationId,
+		cpu:            float64(cpu),
+		memory:         float64(memory),
+		disk:           float64(sandboxDto.StorageQuota),
+	}
+	s.mutex.Unlock()
+
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		delete(s.reservations, sandboxDto.Id)
+	}, nil
+}
+
+// organizationLock returns the lock of an organization. Locks are kept for the lifetime of the
+// runner, which only ever sees a bounded number of organizations.
+func (s *OrganizationQuotaService) organizationLock(organizationId string) *sync.Mutex {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	lock, ok := s.organizationLocks[organizationId]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.organizationLocks[organizationId] = lock
+	}
+	return lock
+}
+
+func checkOrganizationQuota(organizationId string, quota dto.OrganizationQuotaDTO, usage *dto.OrganizationUsageDTO, sandboxDto dto.CreateSandboxDTO) error {
+	var reason string
+
+	cpu, memory := requestedCpuMemory(sandboxDto)
+
+	switch {
+	case quota.MaxSandboxes > 0 && usage.SandboxCount+1 > quota.MaxSandboxes:
+		reason = fmt.Sprintf("sandbox count limit of %d reached", quota.MaxSandboxes)
+	case quota.MaxCpu > 0 && usage.Cpu+float64(cpu) > float64(quota.MaxCpu):
+		reason = fmt.Sprintf("CPU limit of %d vCPU exceeded (%.0f in use, %d requested)", quota.MaxCpu, usage.Cpu, cpu)
+	case quota.MaxMemory > 0 && usage.Memory+float64(memory) > float64(quota.MaxMemory):
+		reason = fmt.Sprintf("memory limit of %d GiB exceeded (%.0f in use, %d requested)", quota.MaxMemory, usage.Memory, memory)
+	case quota.MaxDisk > 0 && usage.Disk+float64(sandboxDto.StorageQuota) > float64(quota.MaxDisk):
+		reason = fmt.Sprintf("disk limit of %d GiB exceeded (%.0f in use, %d requested)", quota.MaxDisk, usage.Disk, sandboxDto.StorageQuota)
+	default:
+		return nil
+	}
+
+	return common.NewResourceExhaustedError(
+		http.StatusForbidden,
+		"ORGANIZATION_QUOTA_EXCEEDED",
+		fmt.Sprintf("organization %s quota on runner exceeded: %s", organizationId, reason),
+		0,
+	)
+}
+
+// getUsage sums up resources of all containers belonging to the organization.
+// CPU and memory are counted for running containers only, disk for all of them.
+// It also reports whether a container named sandboxId is among them.
+func (s *OrganizationQuotaService) getUsage(ctx context.Context, organizationId string, sandboxId string) (*dto.OrganizationUsageDTO, bool, error) {
+	containers, err := s.docker.ApiClient().ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", organizationIdLabel, organizationId))),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	usage := &dto.OrganizationUsageDTO{
+		OrganizationId: organizationId,
+	}
+	exists := false
+
+	for _, ctr := range containers {
+		if sandboxId != "" && slices.Contains(ctr.Names, "/"+sandboxId) {
+			exists = true
+		}
+
+		// Sidecars count against the CPU and memory quota, but not as sandboxes
+		if _, ok := ctr.Labels[common.SIDECAR_OF_LABEL]; !ok {
+			usage.SandboxCount++
+		}
+
+		info, err := s.docker.ContainerInspect(ctx, ctr.ID)
+		if err != nil || info.HostConfig == nil {
+			continue
+		}
+
+		if ctr.State == "running" {
+			usage.Cpu += float64(info.HostConfig.CPUQuota) / 100000
+			usage.Memory += float64(info.HostConfig.Memory) / (1024 * 1024 * 1024)
+		}
+
+		if info.HostConfig.StorageOpt != nil {
+			storageGB, err := common.ParseStorageOptSizeGB(info.HostConfig.StorageOpt)
+			if err == nil {
+				usage.Disk += storageGB
+			}
+		}
+	}
+
+	return usage, exists, nil
+}
+
+// requestedCpuMemory returns the CPUs and memory a new sandbox requests, including its sidecars
+func requestedCpuMemory(sandboxDto dto.CreateSandboxDTO) (int64, int64) {
+	cpu, memory := sandboxDto.CpuQuota, sandboxDto.MemoryQuota
+	for _, sidecar := range sandboxDto.Sidecars {
+		cpu += sidecar.CpuQuota
+		memory += sidecar.MemoryQuota
+	}
+	return cpu, memory
+}
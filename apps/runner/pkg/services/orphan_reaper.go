@@ -0,0 +1,231 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	apiclient "github.com/daytonaio/daytona/libs/api-client-go"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	runnerapiclient "github.com/daytonaio/runner/pkg/apiclient"
+	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var AllOrphanKinds = []string{
+	dto.OrphanKindContainer,
+	dto.OrphanKindSidecar,
+	dto.OrphanKindVolume,
+	dto.OrphanKindVolumeMount,
+	dto.OrphanKindNetwork,
+	dto.OrphanKindChain,
+	dto.OrphanKindImage,
+	dto.OrphanKindHomeVolume,
+}
+
+// Kinds of orphaned resources found with the sandboxes the control plane knows
+var knownSandboxKinds = []string{
+	dto.OrphanKindContainer,
+	dto.OrphanKindHomeVolume,
+}
+
+type OrphanReaperServiceConfig struct {
+	Docker   *docker.DockerClient
+	Interval time.Duration
+	// Kinds of orphaned resources removed by the periodic reaping
+	Kinds []string
+	// Only report the orphaned resources instead of removing them
+	DryRun bool
+	// Resources younger than this are never orphaned, so that those being created aren't removed
+	MinAge time.Duration
+	Locks  *sandboxlock.Manager
+}
+
+// OrphanReaperService removes the resources a crash of the runner, or of a sandbox operation,
+// leaves behind on the host
+type OrphanReaperService struct {
+	docker   *docker.DockerClient
+	interval time.Duration
+	kinds    []string
+	dryRun   bool
+	minAge   time.Duration
+	locks    *sandboxlock.Manager
+	client   *apiclient.APIClient
+
+	// Serializes reaping, so that a manual run doesn't race the periodic one
+	mutex sync.Mutex
+}
+
+func NewOrphanReaperService(config OrphanReaperServiceConfig) *OrphanReaperService {
+	return &OrphanReaperService{
+		docker:   config.Docker,
+		interval: config.Interval,
+		kinds:    config.Kinds,
+		dryRun:   config.DryRun,
+		minAge:   config.MinAge,
+		locks:    config.Locks,
+	}
+}
+
+// StartReaping starts a background goroutine that removes orphaned resources of the configured kinds
+func (s *OrphanReaperService) StartReaping(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				report := s.reap(ctx, s.kinds, s.dryRun)
+				s.logReport(report)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Report returns the orphaned resources of all kinds without removing them
+func (s *OrphanReaperService) Report(ctx context.Context) *dto.OrphanReportDTO {
+	return s.reap(ctx, AllOrphanKinds, true)
+}
+
+// Reap removes the orphaned resources of the given kinds, or of the configured ones if empty.
+// Nothing is removed if the reaper is configured as a dry run, unless dryRun overrides it.
+func (s *OrphanReaperService) Reap(ctx context.Context, kinds []string, dryRun *bool) *dto.OrphanReportDTO {
+	if len(kinds) == 0 {
+		kinds = s.kinds
+	}
+
+	isDryRun := s.dryRun
+	if dryRun != nil {
+		isDryRun = *dryRun
+	}
+
+	report := s.reap(ctx, kinds, isDryRun)
+	s.logReport(report)
+	return report
+}
+
+func (s *OrphanReaperService) reap(ctx context.Context, kinds []string, dryRun bool) *dto.OrphanReportDTO {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	report := &dto.OrphanReportDTO{
+		DryRun:      dryRun,
+		Resources:   []dto.OrphanResourceDTO{},
+		Errors:      map[string]string{},
+		GeneratedAt: time.Now(),
+	}
+
+	var known map[string]bool
+	if slices.ContainsFunc(kinds, isKnownSandboxKind) {
+		var err error
+		known, err = s.knownSandboxes(ctx)
+		if err != nil {
+			// Without the sandboxes of the control plane every stopped sandbox would look orphaned
+			for _, kind := range kinds {
+				if isKnownSandboxKind(kind) {
+					report.Errors[kind] = err.Error()
+				}
+			}
+			kinds = slices.DeleteFunc(slices.Clone(kinds), isKnownSandboxKind)
+		}
+	}
+
+	for _, kind := range kinds {
+		orphans, err := s.docker.FindOrphans(ctx, kind, known, s.minAge)
+		if err != nil {
+			report.Errors[kind] = err.Error()
+			continue
+		}
+
+		for _, orphan := range orphans {
+			if !dryRun {
+				if err := s.remove(ctx, orphan); err != nil {
+					orphan.Error = err.Error()
+				} else {
+					orphan.Removed = true
+				}
+			}
+			report.Resources = append(report.Resources, orphan.OrphanResourceDTO)
+		}
+	}
+
+	return report
+}
+
+// remove removes an orphaned resource. Sandboxes are destroyed under their lock, so that an
+// operation that started since they were found isn't raced.
+func (s *OrphanReaperService) remove(ctx context.Context, orphan docker.Orphan) error {
+	if orphan.Kind != dto.OrphanKindContainer {
+		return orphan.Remove(ctx)
+	}
+
+	// A sandbox an operation is running on isn't orphaned
+	if inProgress, ok := s.locks.InProgress(orphan.Id); ok {
+		return fmt.Errorf("%s of the sandbox is in progress", inProgress)
+	}
+
+	lockCtx, release, err := s.locks.Acquire(ctx, orphan.Id, sandboxlock.OperationDestroy)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return orphan.Remove(lockCtx)
+}
+
+func isKnownSandboxKind(kind string) bool {
+	return slices.Contains(knownSandboxKinds, kind)
+}
+
+// knownSandboxes returns the IDs of the sandboxes the control plane assigned to the runner
+func (s *OrphanReaperService) knownSandboxes(ctx context.Context) (map[string]bool, error) {
+	if s.client == nil {
+		client, err := runnerapiclient.GetApiClient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get API client: %w", err)
+		}
+		s.client = client
+	}
+
+	sandboxes, _, err := s.client.SandboxAPI.GetSandboxesForRunner(ctx).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sandboxes from API: %w", err)
+	}
+
+	known := make(map[string]bool, len(sandboxes))
+	for _, sandbox := range sandboxes {
+		if sandbox.Id == "" || (sandbox.State != nil && *sandbox.State == apiclient.SANDBOXSTATE_DESTROYED) {
+			continue
+		}
+		known[sandbox.Id] = true
+	}
+
+	return known, nil
+}
+
+func (s *OrphanReaperService) logReport(report *dto.OrphanReportDTO) {
+	for kind, err := range report.Errors {
+		log.Errorf("Failed to reap orphaned %s resources: %s", kind, err)
+	}
+
+	for _, resource := range report.Resources {
+		switch {
+		case report.DryRun:
+			log.Infof("[DRY-RUN] Would remove orphaned %s %s: %s", resource.Kind, resource.Id, resource.Reason)
+		case resource.Error != "":
+			log.Errorf("Failed to remove orphaned %s %s: %s", resource.Kind, resource.Id, resource.Error)
+		default:
+			log.Infof("Removed orphaned %s %s: %s", resource.Kind, resource.Id, resource.Reason)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	leftoverContainers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "runner_leftover_containers",
+		Help: "Original containers failed disk resizes and storage recoveries left behind",
+	})
+	leftoverContainersResolved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "runner_leftover_containers_resolved_total",
+		Help: "Leftover containers resolved by the recovery janitor, by resolution",
+	}, []string{"resolution"})
+	leftoverContainerFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "runner_leftover_container_failures_total",
+		Help: "Leftover containers the recovery janitor failed to resolve",
+	})
+)
+
+type RecoveryJanitorServiceConfig struct {
+	Docker   *docker.DockerClient
+	Interval time.Duration
+	// How long after a disk resize or storage recovery its leftover container is resolved
+	GracePeriod time.Duration
+	Locks       *sandboxlock.Manager
+}
+
+// RecoveryJanitorService resolves the original containers that failed disk resizes and storage
+// recoveries leave behind as "<sandbox>-<operation>-<timestamp>", which otherwise accumulate
+type RecoveryJanitorService struct {
+	docker      *docker.DockerClient
+	interval    time.Duration
+	gracePeriod time.Duration
+	locks       *sandboxlock.Manager
+}
+
+func NewRecoveryJanitorService(config RecoveryJanitorServiceConfig) *RecoveryJanitorService {
+	return &RecoveryJanitorService{
+		docker:      config.Docker,
+		interval:    config.Interval,
+		gracePeriod: config.GracePeriod,
+		locks:       config.Locks,
+	}
+}
+
+// StartCleanup starts a background goroutine that resolves leftover containers
+func (s *RecoveryJanitorService) StartCleanup(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.cleanup(ctx)
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (s *RecoveryJanitorService) cleanup(ctx context.Context) {
+	leftovers, err := s.docker.FindLeftoverContainers(ctx)
+	if err != nil {
+		log.Errorf("Failed to list leftover containers: %v", err)
+		return
+	}
+
+	remaining := 0
+	for _, leftover := range leftovers {
+		if time.Since(leftover.RenamedAt) < s.gracePeriod {
+			remaining++
+			continue
+		}
+
+		resolution, err := s.resolve(ctx, leftover)
+		if err != nil {
+			remaining++
+			if !errors.Is(err, errSandboxBusy) {
+				leftoverContainerFailures.Inc()
+				log.Errorf("Failed to resolve leftover container %s: %v", leftover.Name, err)
+			}
+			continue
+		}
+
+		leftoverContainersResolved.WithLabelValues(resolution).Inc()
+		if resolution == docker.LeftoverResolutionKept {
+			remaining++
+			log.Warnf("Keeping leftover container %s, sandbox %s is unhealthy", leftover.Name, leftover.SandboxId)
+			continue
+		}
+
+		log.Infof("Resolved leftover container %s of %s of sandbox %s: %s", leftover.Name, leftover.Operation, leftover.SandboxId, resolution)
+	}
+
+	leftoverContainers.Set(float64(remaining))
+}
+
+var errSandboxBusy = errors.New("sandbox busy")
+
+func (s *RecoveryJanitorService) resolve(ctx context.Context, leftover docker.LeftoverContainer) (string, error) {
+	// Leftovers are resolved on the next pass rather than waiting for the sandbox
+	if _, ok := s.locks.InProgress(leftover.SandboxId); ok {
+		return "", errSandboxBusy
+	}
+
+	lockCtx, release, err := s.locks.Acquire(ctx, leftover.SandboxId, sandboxlock.OperationLeftoverCleanup)
+	if err != nil {
+		var conflictErr *sandboxlock.ConflictError
+		if errors.As(err, &conflictErr) {
+			return "", errSandboxBusy
+		}
+		return "", err
+	}
+	defer release()
+
+	return s.docker.ResolveLeftoverContainer(lockCtx, leftover)
+}
@@ -16,10 +16,10 @@ import (
 
 type SandboxService struct {
 	statesCache *cache.StatesCache
-	docker      *docker.DockerClient
+	docker      docker.ContainerRuntime
 }
 
-func NewSandboxService(statesCache *cache.StatesCache, docker *docker.DockerClient) *SandboxService {
+func NewSandboxService(statesCache *cache.StatesCache, docker docker.ContainerRuntime) *SandboxService {
 	return &SandboxService{
 		statesCache: statesCache,
 		docker:      docker,
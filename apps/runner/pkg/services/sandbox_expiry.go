@@ -0,0 +1,160 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type SandboxExpiryServiceConfig struct {
+	Docker   *docker.DockerClient
+	Interval time.Duration
+	// How long before the expiry of a sandbox its daemon is warned
+	WarningBefore time.Duration
+	Locks         *sandboxlock.Manager
+}
+
+// SandboxExpiryService stops or destroys sandboxes once they expire. The expiry is read from the
+// sandbox containers, so it is enforced without the control plane and survives runner restarts.
+type SandboxExpiryService struct {
+	docker        *docker.DockerClient
+	interval      time.Duration
+	warningBefore time.Duration
+	locks         *sandboxlock.Manager
+
+	mutex sync.Mutex
+	// Expiry each sandbox was warned about, so that it is only warned once
+	warned map[string]time.Time
+}
+
+func NewSandboxExpiryService(config SandboxExpiryServiceConfig) *SandboxExpiryService {
+	return &SandboxExpiryService{
+		docker:        config.Docker,
+		interval:      config.Interval,
+		warningBefore: config.WarningBefore,
+		locks:         config.Locks,
+		warned:        make(map[string]time.Time),
+	}
+}
+
+// StartExpiryCheck starts a background goroutine that enforces the expiry of sandboxes
+func (s *SandboxExpiryService) StartExpiryCheck(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.check(ctx)
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (s *SandboxExpiryService) check(ctx context.Context) {
+	sandboxes, err := s.docker.ListExpiringSandboxes(ctx)
+	if err != nil {
+		log.Errorf("Failed to list expiring sandboxes: %v", err)
+		return
+	}
+
+	present := make(map[string]bool, len(sandboxes))
+	for _, sandbox := range sandboxes {
+		present[sandbox.SandboxId] = true
+
+		untilExpiry := time.Until(sandbox.ExpiresAt)
+		if untilExpiry > 0 {
+			if untilExpiry <= s.warningBefore && sandbox.Running {
+				s.warn(ctx, sandbox)
+			}
+			continue
+		}
+
+		s.expire(ctx, sandbox)
+	}
+
+	s.mutex.Lock()
+	for sandboxId := range s.warned {
+		if !present[sandboxId] {
+			delete(s.warned, sandboxId)
+		}
+	}
+	s.mutex.Unlock()
+}
+
+func (s *SandboxExpiryService) warn(ctx context.Context, sandbox docker.ExpiringSandbox) {
+	s.mutex.Lock()
+	alreadyWarned := s.warned[sandbox.SandboxId].Equal(sandbox.ExpiresAt)
+	s.warned[sandbox.SandboxId] = sandbox.ExpiresAt
+	s.mutex.Unlock()
+
+	if alreadyWarned {
+		return
+	}
+
+	log.Infof("Sandbox %s expires at %s, it will be %s", sandbox.SandboxId, sandbox.ExpiresAt.Format(time.RFC3339), expiryAction(sandbox.Policy))
+
+	err := s.docker.NotifyExpiryWarning(ctx, sandbox.SandboxId, dto.SandboxExpiryWarningDTO{
+		ExpiresAt: sandbox.ExpiresAt,
+		Policy:    sandbox.Policy,
+		Time:      time.Now(),
+	})
+	if err != nil {
+		log.Debugf("Failed to warn the daemon of sandbox %s about its expiry: %v", sandbox.SandboxId, err)
+	}
+}
+
+func (s *SandboxExpiryService) expire(ctx context.Context, sandbox docker.ExpiringSandbox) {
+	operation := sandboxlock.OperationStop
+	if sandbox.Policy == dto.ExpiryPolicyDestroy {
+		operation = sandboxlock.OperationDestroy
+	} else if !sandbox.Running {
+		return
+	}
+
+	// Expired sandboxes are stopped or destroyed on the next check rather than waiting for the sandbox
+	if inProgress, ok := s.locks.InProgress(sandbox.SandboxId); ok {
+		log.Debugf("Sandbox %s expired during %s, retrying on the next check", sandbox.SandboxId, inProgress)
+		return
+	}
+
+	lockCtx, release, err := s.locks.Acquire(ctx, sandbox.SandboxId, operation)
+	if err != nil {
+		log.Debugf("Sandbox %s expired, failed to lock it: %v", sandbox.SandboxId, err)
+		return
+	}
+	defer release()
+
+	switch sandbox.Policy {
+	case dto.ExpiryPolicyDestroy:
+		log.Infof("Sandbox %s expired, destroying it", sandbox.SandboxId)
+		if err := s.docker.Destroy(lockCtx, sandbox.SandboxId); err != nil {
+			log.Errorf("Failed to destroy expired sandbox %s: %v", sandbox.SandboxId, err)
+		}
+	default:
+		log.Infof("Sandbox %s expired, stopping it", sandbox.SandboxId)
+		if err := s.docker.Stop(lockCtx, sandbox.SandboxId); err != nil {
+			log.Errorf("Failed to stop expired sandbox %s: %v", sandbox.SandboxId, err)
+		}
+	}
+}
+
+func expiryAction(policy string) string {
+	if policy == dto.ExpiryPolicyDestroy {
+		return "destroyed"
+	}
+	return "stopped"
+}
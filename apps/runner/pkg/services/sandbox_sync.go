@@ -12,18 +12,20 @@ import (
 	runnerapiclient "github.com/daytonaio/runner/pkg/apiclient"
 	"github.com/daytonaio/runner/pkg/docker"
 	"github.com/daytonaio/runner/pkg/models/enums"
-	"github.com/docker/docker/api/types/container"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
 	log "github.com/sirupsen/logrus"
 )
 
 type SandboxSyncServiceConfig struct {
-	Docker   *docker.DockerClient
+	Docker   docker.ContainerRuntime
 	Interval time.Duration
+	Locks    *sandboxlock.Manager
 }
 
 type SandboxSyncService struct {
-	docker   *docker.DockerClient
+	docker   docker.ContainerRuntime
 	interval time.Duration
+	locks    *sandboxlock.Manager
 	client   *apiclient.APIClient
 }
 
@@ -31,34 +33,31 @@ func NewSandboxSyncService(config SandboxSyncServiceConfig) *SandboxSyncService
 	return &SandboxSyncService{
 		docker:   config.Docker,
 		interval: config.Interval,
+		locks:    config.Locks,
 	}
 }
 
 func (s *SandboxSyncService) GetLocalContainerStates(ctx context.Context) (map[string]enums.SandboxState, error) {
-	containers, err := s.docker.ApiClient().ContainerList(ctx, container.ListOptions{
-		All: true, // Include stopped containers
-	})
+	sandboxes, err := s.docker.ListSandboxes(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
 
 	containerStates := make(map[string]enums.SandboxState)
 
-	for _, container := range containers {
-		// Extract sandbox ID from container name or labels
-		sandboxId := s.extractSandboxId(container)
-		if sandboxId == "" {
-			continue // Skip non-sandbox containers
+	for _, sandbox := range sandboxes {
+		// The state of a sandbox that failed to be deduced isn't synced
+		state := enums.SandboxState(sandbox.State)
+		if state == enums.SandboxStateError {
+			continue
 		}
 
-		// Get the current state of this container
-		state, err := s.docker.DeduceSandboxState(ctx, sandboxId)
-		if err != nil {
-			log.Debugf("Failed to deduce state for sandbox %s: %v", sandboxId, err)
+		// The state of a sandbox changes until its operation completes, which syncs it
+		if _, ok := s.locks.InProgress(sandbox.Id); ok {
 			continue
 		}
 
-		containerStates[sandboxId] = state
+		containerStates[sandbox.Id] = state
 	}
 
 	return containerStates, nil
@@ -171,15 +170,6 @@ func (s *SandboxSyncService) StartSyncProcess(ctx context.Context) {
 	}()
 }
 
-func (s *SandboxSyncService) extractSandboxId(container container.Summary) string {
-	if len(container.Names) > 0 && len(container.Names[0]) > 1 {
-		name := container.Names[0][1:] // Remove leading "/"
-		return name
-	}
-
-	return ""
-}
-
 func (s *SandboxSyncService) convertToApiState(localState enums.SandboxState) apiclient.SandboxState {
 	switch localState {
 	case enums.SandboxStateCreating:
@@ -202,6 +192,9 @@ func (s *SandboxSyncService) convertToApiState(localState enums.SandboxState) ap
 		return apiclient.SANDBOXSTATE_ERROR
 	case enums.SandboxStatePullingSnapshot:
 		return apiclient.SANDBOXSTATE_PULLING_SNAPSHOT
+	// The API has no provisioning state, the toolbox of the sandbox is usable while it provisions
+	case enums.SandboxStateProvisioning:
+		return apiclient.SANDBOXSTATE_STARTED
 	default:
 		return apiclient.SANDBOXSTATE_UNKNOWN
 	}
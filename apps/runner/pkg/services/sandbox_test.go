@@ -0,0 +1,113 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/cache"
+	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/models/enums"
+	"github.com/daytonaio/runner/pkg/testsupport"
+)
+
+const testSnapshot = "ubuntu:22.04"
+
+func newTestSandboxService(t *testing.T) (*SandboxService, *testsupport.FakeRuntime) {
+	t.Helper()
+
+	runtime := testsupport.NewFakeRuntime()
+	runtime.AddImage(testSnapshot, docker.ImageInfo{Size: testsupport.FakeImageSize})
+
+	return NewSandboxService(cache.GetStatesCache(1), runtime), runtime
+}
+
+func createTestSandbox(t *testing.T, runtime *testsupport.FakeRuntime, sandboxId string) {
+	t.Helper()
+
+	if _, _, err := runtime.Create(context.Background(), dto.CreateSandboxDTO{Id: sandboxId, Snapshot: testSnapshot}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetSandboxStatesInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, runtime *testsupport.FakeRuntime)
+		expected enums.SandboxState
+	}{
+		{
+			name: "started sandbox",
+			setup: func(t *testing.T, runtime *testsupport.FakeRuntime) {
+				createTestSandbox(t, runtime, "sandbox")
+			},
+			expected: enums.SandboxStateStarted,
+		},
+		{
+			name: "stopped sandbox",
+			setup: func(t *testing.T, runtime *testsupport.FakeRuntime) {
+				createTestSandbox(t, runtime, "sandbox")
+				if err := runtime.Stop(context.Background(), "sandbox"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expected: enums.SandboxStateStopped,
+		},
+		{
+			name:     "unknown sandbox",
+			setup:    func(t *testing.T, runtime *testsupport.FakeRuntime) {},
+			expected: enums.SandboxStateDestroyed,
+		},
+		{
+			name: "failed state deduction",
+			setup: func(t *testing.T, runtime *testsupport.FakeRuntime) {
+				createTestSandbox(t, runtime, "sandbox")
+				runtime.FailOn("DeduceSandboxState", errors.New("docker is unavailable"))
+			},
+			expected: enums.SandboxStateError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, runtime := newTestSandboxService(t)
+			tt.setup(t, runtime)
+
+			info := service.GetSandboxStatesInfo(context.Background(), "sandbox")
+			if info.SandboxState != tt.expected {
+				t.Fatalf("expected state %s, got %s", tt.expected, info.SandboxState)
+			}
+		})
+	}
+}
+
+func TestRemoveDestroyedSandbox(t *testing.T) {
+	service, runtime := newTestSandboxService(t)
+	createTestSandbox(t, runtime, "sandbox")
+
+	runtime.FailOn("Destroy", errors.New("device or resource busy"))
+	if err := service.RemoveDestroyedSandbox(context.Background(), "sandbox"); err == nil {
+		t.Fatal("expected the destroy error to be returned")
+	}
+	if _, ok := runtime.Sandbox("sandbox"); !ok {
+		t.Fatal("sandbox was removed although destroying it failed")
+	}
+
+	runtime.FailOn("Destroy", nil)
+	if err := service.RemoveDestroyedSandbox(context.Background(), "sandbox"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := runtime.Sandbox("sandbox"); ok {
+		t.Fatal("sandbox was not removed")
+	}
+
+	// Destroyed sandboxes aren't destroyed again
+	runtime.FailOn("Destroy", errors.New("unexpected destroy"))
+	if err := service.RemoveDestroyedSandbox(context.Background(), "sandbox"); err != nil {
+		t.Fatal(err)
+	}
+}
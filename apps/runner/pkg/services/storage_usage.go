@@ -0,0 +1,260 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/sandboxlock"
+	"github.com/docker/docker/api/types/container"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	volumeUsageBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "runner_sandbox_volume_usage_bytes",
+		Help: "Space used by the volumes of running sandboxes, by sandbox and volume mount path",
+	}, []string{"sandbox", "mount_path"})
+	volumeQuotaBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "runner_sandbox_volume_quota_bytes",
+		Help: "Limits of the data volumes of running sandboxes, by sandbox and volume mount path",
+	}, []string{"sandbox", "mount_path"})
+)
+
+type StorageUsageServiceConfig struct {
+	Docker   *docker.DockerClient
+	Interval time.Duration
+	// Usage in percent of the quota at which sandboxes are warned about storage pressure
+	PressureThresholds []int
+	// Expand the storage of sandboxes reaching the highest threshold before they run out of space
+	PressureAutoRecover bool
+	Locks               *sandboxlock.Manager
+}
+
+// StorageUsageService periodically measures the storage used by running sandboxes, as walking
+// their filesystems is too slow to do on every request, and warns sandboxes running out of storage
+type StorageUsageService struct {
+	docker              *docker.DockerClient
+	interval            time.Duration
+	pressureThresholds  []int
+	pressureAutoRecover bool
+	locks               *sandboxlock.Manager
+
+	mutex sync.Mutex
+	usage map[string]*dto.SandboxStorageUsageDTO
+	// Highest threshold each sandbox was warned about
+	pressureLevels map[string]int
+}
+
+func NewStorageUsageService(config StorageUsageServiceConfig) *StorageUsageService {
+	thresholds := slices.Clone(config.PressureThresholds)
+	slices.Sort(thresholds)
+
+	return &StorageUsageService{
+		docker:              config.Docker,
+		interval:            config.Interval,
+		pressureThresholds:  thresholds,
+		pressureAutoRecover: config.PressureAutoRecover,
+		locks:               config.Locks,
+		usage:               make(map[string]*dto.SandboxStorageUsageDTO),
+		pressureLevels:      make(map[string]int),
+	}
+}
+
+// UpdatePressureSettings changes the storage pressure thresholds and auto recovery at runtime
+func (s *StorageUsageService) UpdatePressureSettings(thresholds []int, autoRecover bool) {
+	thresholds = slices.Clone(thresholds)
+	slices.Sort(thresholds)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.pressureThresholds = thresholds
+	s.pressureAutoRecover = autoRecover
+}
+
+// GetSandboxStorageUsage returns the last sample of the sandbox, measuring it if there is no recent one
+func (s *StorageUsageService) GetSandboxStorageUsage(ctx context.Context, sandboxId string) (*dto.SandboxStorageUsageDTO, error) {
+	s.mutex.Lock()
+	usage, ok := s.usage[sandboxId]
+	s.mutex.Unlock()
+
+	if ok && time.Since(usage.SampledAt) < 2*s.interval {
+		return usage, nil
+	}
+
+	usage, err := s.docker.GetStorageUsage(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+	usage.PressureThresholdPercent = s.pressureLevel(usage)
+
+	s.mutex.Lock()
+	s.usage[sandboxId] = usage
+	s.mutex.Unlock()
+
+	return usage, nil
+}
+
+// StartSampling starts a background goroutine that samples the storage usage of running sandboxes
+func (s *StorageUsageService) StartSampling(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.sample(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (s *StorageUsageService) sample(ctx context.Context) {
+	containers, err := s.docker.ApiClient().ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		log.Errorf("Failed to list sandboxes for storage usage sampling: %v", err)
+		return
+	}
+
+	usage := make(map[string]*dto.SandboxStorageUsageDTO, len(containers))
+	for _, c := range containers {
+		if len(c.Names) == 0 || len(c.Names[0]) < 2 {
+			continue
+		}
+		if _, ok := c.Labels[common.SIDECAR_OF_LABEL]; ok {
+			continue
+		}
+		sandboxId := c.Names[0][1:]
+
+		sandboxUsage, err := s.docker.GetStorageUsage(ctx, sandboxId)
+		if err != nil {
+			log.Debugf("Failed to sample storage usage of sandbox %s: %v", sandboxId, err)
+			continue
+		}
+		sandboxUsage.PressureThresholdPercent = s.pressureLevel(sandboxUsage)
+		usage[sandboxId] = sandboxUsage
+
+		s.checkPressure(ctx, sandboxId, sandboxUsage)
+	}
+
+	volumeUsageBytes.Reset()
+	volumeQuotaBytes.Reset()
+	for sandboxId, sandboxUsage := range usage {
+		for _, volume := range sandboxUsage.Volumes {
+			volumeUsageBytes.WithLabelValues(sandboxId, volume.MountPath).Set(float64(volume.UsedBytes))
+			if volume.QuotaBytes > 0 {
+				volumeQuotaBytes.WithLabelValues(sandboxId, volume.MountPath).Set(float64(volume.QuotaBytes))
+			}
+		}
+	}
+
+	// Samples of stopped sandboxes are dropped, they are measured again on request
+	s.mutex.Lock()
+	s.usage = usage
+	for sandboxId := range s.pressureLevels {
+		if _, ok := usage[sandboxId]; !ok {
+			delete(s.pressureLevels, sandboxId)
+		}
+	}
+	s.mutex.Unlock()
+}
+
+// pressureLevel returns the highest threshold the usage is at or above, 0 if none
+func (s *StorageUsageService) pressureLevel(usage *dto.SandboxStorageUsageDTO) int {
+	if usage.QuotaBytes <= 0 {
+		return 0
+	}
+
+	percent := usage.UsedBytes * 100 / usage.QuotaBytes
+
+	s.mutex.Lock()
+	thresholds := s.pressureThresholds
+	s.mutex.Unlock()
+
+	level := 0
+	for _, threshold := range thresholds {
+		if percent >= int64(threshold) {
+			level = threshold
+		}
+	}
+
+	return level
+}
+
+// checkPressure warns the sandbox once each time its usage rises above a threshold
+func (s *StorageUsageService) checkPressure(ctx context.Context, sandboxId string, usage *dto.SandboxStorageUsageDTO) {
+	level := usage.PressureThresholdPercent
+
+	s.mutex.Lock()
+	previousLevel := s.pressureLevels[sandboxId]
+	s.pressureLevels[sandboxId] = level
+	autoRecover := s.pressureAutoRecover && len(s.pressureThresholds) > 0 && level == s.pressureThresholds[len(s.pressureThresholds)-1]
+	s.mutex.Unlock()
+
+	if level <= previousLevel {
+		return
+	}
+
+	log.Warnf("Storage usage of sandbox %s reached %d%% of its quota (%d of %d bytes)", sandboxId, level, usage.UsedBytes, usage.QuotaBytes)
+
+	err := s.docker.NotifyStoragePressure(ctx, sandboxId, dto.StoragePressureEventDTO{
+		ThresholdPercent: level,
+		UsedBytes:        usage.UsedBytes,
+		QuotaBytes:       usage.QuotaBytes,
+		Time:             usage.SampledAt,
+	})
+	if err != nil {
+		log.Debugf("Failed to notify the daemon of sandbox %s about storage pressure: %v", sandboxId, err)
+	}
+
+	if autoRecover {
+		go s.recoverStorage(sandboxId)
+	}
+}
+
+// recoverStorage expands the storage of a sandbox, which restarts it
+func (s *StorageUsageService) recoverStorage(sandboxId string) {
+	// Skip the recovery rather than wait, the sandbox is checked again on the next pass
+	if operation, ok := s.locks.InProgress(sandboxId); ok {
+		log.Infof("Skipping storage recovery of sandbox %s while %s is in progress", sandboxId, operation)
+		return
+	}
+
+	ctx, release, err := s.locks.Acquire(context.Background(), sandboxId, sandboxlock.OperationStorageRecovery)
+	if err != nil {
+		log.Warnf("Skipping storage recovery of sandbox %s: %v", sandboxId, err)
+		return
+	}
+	defer release()
+
+	originalQuota, err := s.docker.GetOriginalStorageQuota(ctx, sandboxId)
+	if err != nil {
+		log.Warnf("Skipping storage recovery of sandbox %s: %v", sandboxId, err)
+		return
+	}
+
+	log.Infof("Expanding storage of sandbox %s before it runs out of space", sandboxId)
+
+	if err := s.docker.RecoverFromStorageLimit(ctx, sandboxId, originalQuota); err != nil {
+		log.Errorf("Failed to recover storage of sandbox %s: %v", sandboxId, err)
+	}
+
+	// The sandbox is stopped for the recovery even if it failed
+	if _, err := s.docker.Start(ctx, sandboxId, nil); err != nil {
+		log.Errorf("Failed to start sandbox %s after storage recovery: %v", sandboxId, err)
+	}
+}
@@ -10,4 +10,5 @@ import (
 // ObjectStorageClient defines the interface for object storage operations
 type ObjectStorageClient interface {
 	GetObject(ctx context.Context, organizationId, hash string) ([]byte, error)
+	PutObject(ctx context.Context, objectPath string, data []byte) error
 }
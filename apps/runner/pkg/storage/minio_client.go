@@ -4,6 +4,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -80,3 +81,12 @@ func (m *minioClient) GetObject(ctx context.Context, organizationId, hash string
 
 	return data, nil
 }
+
+func (m *minioClient) PutObject(ctx context.Context, objectPath string, data []byte) error {
+	_, err := m.client.PutObject(ctx, m.bucketName, objectPath, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to put object to storage: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,507 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package testsupport provides in-memory implementations of the dependencies of the runner
+// services, so that they can be unit-tested without a Docker daemon.
+package testsupport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/daytonaio/runner/pkg/docker"
+	"github.com/daytonaio/runner/pkg/models/enums"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+)
+
+// Daemon version reported by the sandboxes of the fake runtime
+const FakeDaemonVersion = "v0.0.0-fake"
+
+// Size of the images the fake runtime pulls, builds or backs up, in bytes
+const FakeImageSize = 100 * 1024 * 1024
+
+// FakeSandbox is a sandbox of the fake runtime
+type FakeSandbox struct {
+	Id              string
+	ContainerId     string
+	State           enums.SandboxState
+	Snapshot        string
+	CpuQuota        int64
+	MemoryQuota     int64
+	StorageQuota    int64
+	Volumes         []string
+	NetworkSettings *dto.UpdateNetworkSettingsDTO
+	Metadata        map[string]string
+	// Container labels, only the organization label the docker runtime sets from the metadata
+	Labels    map[string]string
+	CreatedAt time.Time
+}
+
+// FakeRuntime is an in-memory docker.ContainerRuntime. It behaves deterministically: container
+// IDs are derived from the sandbox IDs, sandboxes are created a second apart from the Unix epoch
+// and operations on unknown sandboxes or images fail with not found errors, like Docker does.
+type FakeRuntime struct {
+	mutex     sync.Mutex
+	sandboxes map[string]*FakeSandbox
+	images    map[string]docker.ImageInfo
+	registry  map[string]docker.ImageDigest
+	volumes   map[string]bool
+	errors    map[string]error
+	created   int
+}
+
+var _ docker.ContainerRuntime = (*FakeRuntime)(nil)
+
+func NewFakeRuntime() *FakeRuntime {
+	return &FakeRuntime{
+		sandboxes: map[string]*FakeSandbox{},
+		images:    map[string]docker.ImageInfo{},
+		registry:  map[string]docker.ImageDigest{},
+		volumes:   map[string]bool{},
+		errors:    map[string]error{},
+	}
+}
+
+// AddImage adds a local image, as if it was pulled
+func (r *FakeRuntime) AddImage(imageName string, info docker.ImageInfo) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.images[imageName] = info
+}
+
+// AddRegistryImage adds an image to the registries, where it can be inspected and pulled from
+func (r *FakeRuntime) AddRegistryImage(imageName string, digest docker.ImageDigest) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.registry[imageName] = digest
+}
+
+// FailOn makes every call of a method, e.g. "Create", fail with an error until it is called
+// again with a nil error
+func (r *FakeRuntime) FailOn(method string, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err == nil {
+		delete(r.errors, method)
+		return
+	}
+	r.errors[method] = err
+}
+
+// SetState overrides the state of a sandbox, e.g. to simulate a crashed container
+func (r *FakeRuntime) SetState(sandboxId string, state enums.SandboxState) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sandbox, err := r.sandbox(sandboxId)
+	if err != nil {
+		return err
+	}
+	sandbox.State = state
+
+	return nil
+}
+
+// Sandbox returns a copy of a sandbox
+func (r *FakeRuntime) Sandbox(sandboxId string) (FakeSandbox, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sandbox, ok := r.sandboxes[sandboxId]
+	if !ok {
+		return FakeSandbox{}, false
+	}
+
+	return *sandbox, true
+}
+
+// Images returns the names of the local images, sorted
+func (r *FakeRuntime) Images() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	names := make([]string, 0, len(r.images))
+	for name := range r.images {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Volumes returns the IDs of the volumes mounted by sandboxes so far, sorted
+func (r *FakeRuntime) Volumes() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	ids := make([]string, 0, len(r.volumes))
+	for id := range r.volumes {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+func (r *FakeRuntime) Create(ctx context.Context, sandboxDto dto.CreateSandboxDTO) (string, string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.errors["Create"]; err != nil {
+		return "", "", err
+	}
+
+	if sandbox, ok := r.sandboxes[sandboxDto.Id]; ok {
+		if sandbox.State != enums.SandboxStateStarted {
+			return "", "", common_errors.NewConflictError(fmt.Errorf("sandbox %s already exists in state %s", sandboxDto.Id, sandbox.State))
+		}
+		return sandbox.ContainerId, FakeDaemonVersion, nil
+	}
+
+	if _, ok := r.images[sandboxDto.Snapshot]; !ok {
+		return "", "", fmt.Errorf("image %s: %w", sandboxDto.Snapshot, errdefs.ErrNotFound)
+	}
+
+	volumes := make([]string, 0, len(sandboxDto.Volumes))
+	for _, volume := range sandboxDto.Volumes {
+		r.volumes[volume.VolumeId] = true
+		volumes = append(volumes, volume.VolumeId)
+	}
+
+	hash := sha256.Sum256([]byte(sandboxDto.Id))
+	sandbox := &FakeSandbox{
+		Id:           sandboxDto.Id,
+		ContainerId:  hex.EncodeToString(hash[:]),
+		State:        enums.SandboxStateStarted,
+		Snapshot:     sandboxDto.Snapshot,
+		CpuQuota:     sandboxDto.CpuQuota,
+		MemoryQuota:  sandboxDto.MemoryQuota,
+		StorageQuota: sandboxDto.StorageQuota,
+		Volumes:      volumes,
+		Metadata:     sandboxDto.Metadata,
+		Labels:       map[string]string{},
+		CreatedAt:    time.Unix(int64(r.created), 0).UTC(),
+	}
+	if orgId := sandboxDto.Metadata["organizationId"]; orgId != "" {
+		sandbox.Labels["daytona.organization_id"] = orgId
+	}
+	r.created++
+	r.sandboxes[sandbox.Id] = sandbox
+
+	return sandbox.ContainerId, FakeDaemonVersion, nil
+}
+
+func (r *FakeRuntime) Start(ctx context.Context, containerId string, metadata map[string]string) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sandbox, err := r.call("Start", containerId)
+	if err != nil {
+		return "", err
+	}
+	sandbox.State = enums.SandboxStateStarted
+	if metadata != nil {
+		sandbox.Metadata = metadata
+	}
+
+	return FakeDaemonVersion, nil
+}
+
+func (r *FakeRuntime) Stop(ctx context.Context, containerId string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sandbox, err := r.call("Stop", containerId)
+	if err != nil {
+		return err
+	}
+	sandbox.State = enums.SandboxStateStopped
+
+	return nil
+}
+
+func (r *FakeRuntime) Destroy(ctx context.Context, containerId string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.errors["Destroy"]; err != nil {
+		return err
+	}
+
+	// Destroying a sandbox that doesn't exist succeeds, like it does with Docker
+	delete(r.sandboxes, containerId)
+
+	return nil
+}
+
+func (r *FakeRuntime) Resize(ctx context.Context, sandboxId string, sandboxDto dto.ResizeSandboxDTO) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sandbox, err := r.call("Resize", sandboxId)
+	if err != nil {
+		return err
+	}
+
+	if sandboxDto.Cpu > 0 {
+		sandbox.CpuQuota = sandboxDto.Cpu
+	}
+	if sandboxDto.Memory > 0 {
+		sandbox.MemoryQuota = sandboxDto.Memory
+	}
+	if sandboxDto.Disk > 0 {
+		if sandboxDto.Disk < sandbox.StorageQuota {
+			return common_errors.NewBadRequestError(fmt.Errorf("the disk of sandbox %s can't be shrunk", sandboxId))
+		}
+		sandbox.StorageQuota = sandboxDto.Disk
+	}
+
+	return nil
+}
+
+func (r *FakeRuntime) RecoverSandbox(ctx context.Context, sandboxId string, recoverDto dto.RecoverSandboxDTO) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sandbox, err := r.call("RecoverSandbox", sandboxId)
+	if err != nil {
+		return err
+	}
+	sandbox.State = enums.SandboxStateStarted
+
+	return nil
+}
+
+func (r *FakeRuntime) UpdateNetworkSettings(ctx context.Context, containerId string, updateNetworkSettingsDto dto.UpdateNetworkSettingsDTO) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sandbox, err := r.call("UpdateNetworkSettings", containerId)
+	if err != nil {
+		return err
+	}
+	sandbox.NetworkSettings = &updateNetworkSettingsDto
+
+	return nil
+}
+
+func (r *FakeRuntime) ApplyDevcontainer(ctx context.Context, sandboxId, osUser string, devcontainerDto dto.DevcontainerDTO, progress func(dto.DevcontainerProgressDTO)) (*dto.DevcontainerResultDTO, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, err := r.call("ApplyDevcontainer", sandboxId); err != nil {
+		return nil, err
+	}
+
+	if progress != nil {
+		progress(dto.DevcontainerProgressDTO{Step: "clone"})
+	}
+
+	return &dto.DevcontainerResultDTO{RemoteUser: osUser}, nil
+}
+
+func (r *FakeRuntime) DeduceSandboxState(ctx context.Context, sandboxId string) (enums.SandboxState, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if sandboxId == "" {
+		return enums.SandboxStateUnknown, nil
+	}
+
+	if err := r.errors["DeduceSandboxState"]; err != nil {
+		return enums.SandboxStateError, err
+	}
+
+	sandbox, ok := r.sandboxes[sandboxId]
+	if !ok {
+		return enums.SandboxStateDestroyed, nil
+	}
+
+	return sandbox.State, nil
+}
+
+func (r *FakeRuntime) ListSandboxes(ctx context.Context, labels map[string]string) ([]dto.SandboxSummaryDTO, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.errors["ListSandboxes"]; err != nil {
+		return nil, err
+	}
+
+	sandboxes := make([]dto.SandboxSummaryDTO, 0, len(r.sandboxes))
+	for _, sandbox := range r.sandboxes {
+		if !hasLabels(sandbox.Labels, labels) {
+			continue
+		}
+		sandboxes = append(sandboxes, dto.SandboxSummaryDTO{
+			Id:           sandbox.Id,
+			State:        string(sandbox.State),
+			Snapshot:     sandbox.Snapshot,
+			StorageQuota: sandbox.StorageQuota,
+			CreatedAt:    sandbox.CreatedAt,
+		})
+	}
+	slices.SortFunc(sandboxes, func(a, b dto.SandboxSummaryDTO) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return sandboxes, nil
+}
+
+func (r *FakeRuntime) CreateBackup(ctx context.Context, containerId string, backupDto dto.CreateBackupDTO) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, err := r.call("CreateBackup", containerId); err != nil {
+		return err
+	}
+
+	r.addImage(backupDto.Snapshot)
+	r.registry[backupDto.Snapshot] = docker.ImageDigest{Digest: imageDigest(backupDto.Snapshot), Size: FakeImageSize}
+
+	return nil
+}
+
+func (r *FakeRuntime) PullSnapshot(ctx context.Context, req dto.PullSnapshotRequestDTO) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.errors["PullSnapshot"]; err != nil {
+		return err
+	}
+
+	if _, ok := r.images[req.Snapshot]; !ok {
+		if _, ok := r.registry[req.Snapshot]; !ok {
+			return fmt.Errorf("image %s: %w", req.Snapshot, errdefs.ErrNotFound)
+		}
+		r.addImage(req.Snapshot)
+	}
+
+	if req.DestinationRef != nil {
+		r.registry[*req.DestinationRef] = docker.ImageDigest{Digest: imageDigest(req.Snapshot), Size: FakeImageSize}
+	}
+
+	return nil
+}
+
+func (r *FakeRuntime) BuildSnapshot(ctx context.Context, req dto.BuildSnapshotRequestDTO) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.errors["BuildSnapshot"]; err != nil {
+		return err
+	}
+
+	r.addImage(req.Snapshot)
+
+	return nil
+}
+
+func (r *FakeRuntime) RemoveImage(ctx context.Context, imageName string, force bool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.errors["RemoveImage"]; err != nil {
+		return err
+	}
+
+	if !force {
+		for _, sandbox := range r.sandboxes {
+			if sandbox.Snapshot == imageName {
+				return common_errors.NewConflictError(fmt.Errorf("image %s is used by sandbox %s", imageName, sandbox.Id))
+			}
+		}
+	}
+
+	// Removing an image that doesn't exist succeeds, like it does with DockerClient
+	delete(r.images, imageName)
+
+	return nil
+}
+
+func (r *FakeRuntime) GetImageInfo(ctx context.Context, imageName string) (*docker.ImageInfo, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.errors["GetImageInfo"]; err != nil {
+		return nil, err
+	}
+
+	info, ok := r.images[imageName]
+	if !ok {
+		return nil, fmt.Errorf("image %s: %w", imageName, errdefs.ErrNotFound)
+	}
+
+	return &info, nil
+}
+
+func (r *FakeRuntime) InspectImageInRegistry(ctx context.Context, imageName string, registry *dto.RegistryDTO) (*docker.ImageDigest, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.errors["InspectImageInRegistry"]; err != nil {
+		return nil, err
+	}
+
+	digest, ok := r.registry[imageName]
+	if !ok {
+		return nil, fmt.Errorf("image %s: %w", imageName, errdefs.ErrNotFound)
+	}
+
+	return &digest, nil
+}
+
+// call returns the sandbox a method is called on, or the error the method fails with
+func (r *FakeRuntime) call(method, sandboxId string) (*FakeSandbox, error) {
+	if err := r.errors[method]; err != nil {
+		return nil, err
+	}
+
+	return r.sandbox(sandboxId)
+}
+
+func (r *FakeRuntime) sandbox(sandboxId string) (*FakeSandbox, error) {
+	sandbox, ok := r.sandboxes[sandboxId]
+	if !ok {
+		return nil, fmt.Errorf("sandbox %s: %w", sandboxId, errdefs.ErrNotFound)
+	}
+
+	return sandbox, nil
+}
+
+func (r *FakeRuntime) addImage(imageName string) {
+	if _, ok := r.images[imageName]; ok {
+		return
+	}
+
+	r.images[imageName] = docker.ImageInfo{
+		Size: FakeImageSize,
+		Hash: imageDigest(imageName),
+	}
+}
+
+// imageDigest returns the digest of an image, derived from its name
+func imageDigest(imageName string) string {
+	hash := sha256.Sum256([]byte(imageName))
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+func hasLabels(labels, required map[string]string) bool {
+	for key, value := range required {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package topology
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrNoCapacity = errors.New("no NUMA node has enough free CPUs and memory")
+
+type Allocation struct {
+	Node        int
+	Cpus        []int
+	MemoryBytes int64
+	createdAt   time.Time
+}
+
+type NodeUsage struct {
+	Node
+	FreeCpus             int
+	AllocatedMemoryBytes int64
+}
+
+// Allocator hands out dedicated CPUs to sandboxes, all on one NUMA node per sandbox
+type Allocator struct {
+	nodes []Node
+	// CPUs left to the host and unpinned sandboxes
+	reserved    []int
+	allocations map[string]Allocation
+	mutex       sync.Mutex
+}
+
+func NewAllocator(nodes []Node, reservedCpus []int) *Allocator {
+	return &Allocator{
+		nodes:       nodes,
+		reserved:    reservedCpus,
+		allocations: map[string]Allocation{},
+	}
+}
+
+// Allocate assigns CPUs and memory of a single node to a sandbox, replacing its previous
+// allocation unless it already has the same size. The node with the fewest free CPUs that fits
+// the sandbox is used, which keeps larger blocks of CPUs free for larger sandboxes.
+func (a *Allocator) Allocate(sandboxId string, cpuCount int, memoryBytes int64) (Allocation, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	previous, hadPrevious := a.allocations[sandboxId]
+	if hadPrevious && len(previous.Cpus) == cpuCount && previous.MemoryBytes == memoryBytes {
+		return previous, nil
+	}
+	delete(a.allocations, sandboxId)
+
+	var best *Allocation
+	bestFree := 0
+	for _, node := range a.nodes {
+		free := a.freeCpus(node)
+		if len(free) < cpuCount || node.MemoryBytes-a.allocatedMemory(node.Id) < memoryBytes {
+			continue
+		}
+
+		if best == nil || len(free) < bestFree {
+			best = &Allocation{Node: node.Id, Cpus: free[:cpuCount], MemoryBytes: memoryBytes, createdAt: time.Now()}
+			bestFree = len(free)
+		}
+	}
+
+	if best == nil {
+		if hadPrevious {
+			a.allocations[sandboxId] = previous
+		}
+		return Allocation{}, fmt.Errorf("failed to pin %d CPUs: %w", cpuCount, ErrNoCapacity)
+	}
+
+	a.allocations[sandboxId] = *best
+	return *best, nil
+}
+
+// Restore records the allocation of an existing sandbox, e.g. after the runner restarts
+func (a *Allocator) Restore(sandboxId string, node int, cpus []int, memoryBytes int64) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.allocations[sandboxId] = Allocation{Node: node, Cpus: cpus, MemoryBytes: memoryBytes, createdAt: time.Now()}
+}
+
+// ReleaseExcept releases the allocations of sandboxes other than the given ones that are older
+// than the grace period, which leaves alone sandboxes whose containers are still being created
+func (a *Allocator) ReleaseExcept(sandboxIds []string, gracePeriod time.Duration) []string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	var released []string
+	for sandboxId, allocation := range a.allocations {
+		if slices.Contains(sandboxIds, sandboxId) || time.Since(allocation.createdAt) < gracePeriod {
+			continue
+		}
+		delete(a.allocations, sandboxId)
+		released = append(released, sandboxId)
+	}
+
+	return released
+}
+
+func (a *Allocator) Release(sandboxId string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	delete(a.allocations, sandboxId)
+}
+
+// Usage returns the nodes of the host along with their free CPUs and allocated memory
+func (a *Allocator) Usage() []NodeUsage {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	usage := make([]NodeUsage, 0, len(a.nodes))
+	for _, node := range a.nodes {
+		usage = append(usage, NodeUsage{
+			Node:                 node,
+			FreeCpus:             len(a.freeCpus(node)),
+			AllocatedMemoryBytes: a.allocatedMemory(node.Id),
+		})
+	}
+
+	return usage
+}
+
+func (a *Allocator) freeCpus(node Node) []int {
+	var free []int
+	for _, cpu := range node.Cpus {
+		if slices.Contains(a.reserved, cpu) || a.isAllocated(cpu) {
+			continue
+		}
+		free = append(free, cpu)
+	}
+
+	return free
+}
+
+func (a *Allocator) isAllocated(cpu int) bool {
+	for _, allocation := range a.allocations {
+		if slices.Contains(allocation.Cpus, cpu) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *Allocator) allocatedMemory(node int) int64 {
+	var allocated int64
+	for _, allocation := range a.allocations {
+		if allocation.Node == node {
+			allocated += allocation.MemoryBytes
+		}
+	}
+
+	return allocated
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package topology discovers the NUMA nodes of the host and allocates dedicated CPUs of a single
+// node to sandboxes, so their threads don't migrate across the host and their memory stays local.
+package topology
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const nodesPath = "/sys/devices/system/node"
+
+type Node struct {
+	Id          int
+	Cpus        []int
+	MemoryBytes int64
+}
+
+// Discover reads the NUMA nodes of the host from sysfs. Hosts without NUMA support are reported
+// as a single node with all online CPUs.
+func Discover() ([]Node, error) {
+	paths, err := filepath.Glob(filepath.Join(nodesPath, "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return discoverSingleNode()
+	}
+
+	nodes := make([]Node, 0, len(paths))
+	for _, path := range paths {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(path), "node"))
+		if err != nil {
+			continue
+		}
+
+		cpuList, err := os.ReadFile(filepath.Join(path, "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+
+		cpus, err := ParseCpuList(strings.TrimSpace(string(cpuList)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the CPUs of NUMA node %d: %w", id, err)
+		}
+
+		// Memory-only nodes can't run sandboxes
+		if len(cpus) == 0 {
+			continue
+		}
+
+		memoryBytes, err := readNodeMemory(filepath.Join(path, "meminfo"))
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, Node{Id: id, Cpus: cpus, MemoryBytes: memoryBytes})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Id < nodes[j].Id })
+
+	return nodes, nil
+}
+
+func discoverSingleNode() ([]Node, error) {
+	cpuList, err := os.ReadFile("/sys/devices/system/cpu/online")
+	if err != nil {
+		return nil, err
+	}
+
+	cpus, err := ParseCpuList(strings.TrimSpace(string(cpuList)))
+	if err != nil {
+		return nil, err
+	}
+
+	memoryBytes, err := readNodeMemory("/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+
+	return []Node{{Id: 0, Cpus: cpus, MemoryBytes: memoryBytes}}, nil
+}
+
+// readNodeMemory reads MemTotal from a meminfo file, which is prefixed with the node in sysfs,
+// e.g. "Node 0 MemTotal:       65839400 kB"
+func readNodeMemory(path string) (int64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			if field != "MemTotal:" || i+1 >= len(fields) {
+				continue
+			}
+
+			kb, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid MemTotal in %s: %w", path, err)
+			}
+			return kb * 1024, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no MemTotal in %s", path)
+}
+
+// ParseCpuList parses a CPU list in the kernel format, e.g. 0-3,8,10-11
+func ParseCpuList(list string) ([]int, error) {
+	var cpus []int
+	if list == "" {
+		return cpus, nil
+	}
+
+	for _, part := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU list %q", list)
+		}
+
+		end := start
+		if isRange {
+			end, err = strconv.Atoi(last)
+			if err != nil || end < start {
+				return nil, fmt.Errorf("invalid CPU list %q", list)
+			}
+		}
+
+		for cpu := start; cpu <= end; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+
+	return cpus, nil
+}
+
+// FormatCpuList formats CPUs in the kernel CPU list format
+func FormatCpuList(cpus []int) string {
+	sorted := append([]int(nil), cpus...)
+	sort.Ints(sorted)
+
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+
+		if i == j {
+			parts = append(parts, strconv.Itoa(sorted[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		}
+		i = j + 1
+	}
+
+	return strings.Join(parts, ",")
+}
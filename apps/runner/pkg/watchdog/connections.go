@@ -0,0 +1,75 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package watchdog
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/docker/docker/client"
+)
+
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// ConnectionCounter counts the open connections of a dialer, e.g. those of the pool of the
+// Docker client
+type ConnectionCounter struct {
+	open atomic.Int64
+}
+
+// Wrap returns a dial function that counts the connections of dial until they are closed
+func (c *ConnectionCounter) Wrap(dial DialContextFunc) DialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		c.open.Add(1)
+		return &countedConn{Conn: conn, counter: c}, nil
+	}
+}
+
+func (c *ConnectionCounter) Open() int64 {
+	return c.open.Load()
+}
+
+type countedConn struct {
+	net.Conn
+	counter *ConnectionCounter
+	once    sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(func() {
+		c.counter.open.Add(-1)
+	})
+	return c.Conn.Close()
+}
+
+// DockerDialContext returns a dial function for the Docker host of the environment, to replace
+// the one of the Docker client, which dials its host regardless of the address it is asked for
+func DockerDialContext() (DialContextFunc, error) {
+	host := os.Getenv(client.EnvOverrideHost)
+	if host == "" {
+		host = client.DefaultDockerHost
+	}
+
+	hostUrl, err := client.ParseHostURL(host)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer net.Dialer
+	if hostUrl.Scheme == "unix" {
+		return func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", hostUrl.Path)
+		}, nil
+	}
+
+	return dialer.DialContext, nil
+}
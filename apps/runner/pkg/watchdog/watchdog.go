@@ -0,0 +1,227 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package watchdog
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Stacks with the most new goroutines logged along with a goroutine growth warning
+const maxLoggedStacks = 10
+
+// The goroutines and open file descriptors of the runner are exported by the default collectors
+// of the Prometheus registry, as go_goroutines and process_open_fds
+var dockerClientConnections = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "runner_docker_client_connections",
+	Help: "Open connections of the Docker client of the runner to the Docker daemon",
+})
+
+type Config struct {
+	Logger   *slog.Logger
+	Interval time.Duration
+	// Growth over the last baseline above which a warning is logged, 0 disables the check
+	GoroutineGrowth  int
+	FdGrowth         int
+	ConnectionGrowth int
+	// Connections of the Docker client, nil if they aren't counted
+	DockerConnections *ConnectionCounter
+}
+
+type sample struct {
+	goroutines  int
+	fds         int
+	connections int
+	// Goroutine counts by stack
+	stacks map[string]int
+}
+
+// Watchdog samples the goroutines, open file descriptors and Docker client connections of the
+// runner and warns when they keep growing, which points at a leak long before it ends in an OOM
+type Watchdog struct {
+	log    *slog.Logger
+	config Config
+	// Counts growth is measured against
+	baseline *sample
+}
+
+func NewWatchdog(config Config) *Watchdog {
+	return &Watchdog{
+		log:    config.Logger.With(slog.String("component", "watchdog")),
+		config: config,
+	}
+}
+
+// Start samples the runner until the context is canceled
+func (w *Watchdog) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.check(w.sample())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watchdog) sample() *sample {
+	s := &sample{
+		goroutines: runtime.NumGoroutine(),
+		fds:        openFds(),
+		stacks:     goroutineStacks(),
+	}
+
+	if w.config.DockerConnections != nil {
+		s.connections = int(w.config.DockerConnections.Open())
+		dockerClientConnections.Set(float64(s.connections))
+	}
+
+	return s
+}
+
+func (w *Watchdog) check(current *sample) {
+	if w.baseline == nil {
+		w.baseline = current
+		return
+	}
+
+	// Each count is measured against its value at the last warning about it, so that only further
+	// growth is reported, or against a lower value it went back down to
+	if exceeds(current.goroutines, w.baseline.goroutines, w.config.GoroutineGrowth) {
+		w.log.Warn("Goroutine count grew",
+			slog.Int("goroutines", current.goroutines),
+			slog.Int("baseline", w.baseline.goroutines),
+			slog.Any("new_stacks", stackGrowth(w.baseline.stacks, current.stacks)),
+		)
+		w.baseline.goroutines, w.baseline.stacks = current.goroutines, current.stacks
+	} else if current.goroutines < w.baseline.goroutines {
+		w.baseline.goroutines, w.baseline.stacks = current.goroutines, current.stacks
+	}
+
+	if exceeds(current.fds, w.baseline.fds, w.config.FdGrowth) {
+		w.log.Warn("Open file descriptor count grew",
+			slog.Int("fds", current.fds),
+			slog.Int("baseline", w.baseline.fds),
+		)
+		w.baseline.fds = current.fds
+	} else if current.fds < w.baseline.fds {
+		w.baseline.fds = current.fds
+	}
+
+	if w.config.DockerConnections == nil {
+		return
+	}
+
+	if exceeds(current.connections, w.baseline.connections, w.config.ConnectionGrowth) {
+		w.log.Warn("Docker client connection count grew",
+			slog.Int("connections", current.connections),
+			slog.Int("baseline", w.baseline.connections),
+		)
+		w.baseline.connections = current.connections
+	} else if current.connections < w.baseline.connections {
+		w.baseline.connections = current.connections
+	}
+}
+
+func exceeds(current, baseline, growth int) bool {
+	return growth > 0 && current-baseline >= growth
+}
+
+// stackGrowth returns the stacks with the most new goroutines, as "<count> <stack>"
+func stackGrowth(before, after map[string]int) []string {
+	type growth struct {
+		stack string
+		count int
+	}
+
+	var grown []growth
+	for stack, count := range after {
+		if count > before[stack] {
+			grown = append(grown, growth{stack: stack, count: count - before[stack]})
+		}
+	}
+
+	slices.SortFunc(grown, func(a, b growth) int {
+		return cmp.Compare(b.count, a.count)
+	})
+
+	var stacks []string
+	for i, g := range grown {
+		if i == maxLoggedStacks {
+			break
+		}
+		stacks = append(stacks, "+"+strconv.Itoa(g.count)+" "+g.stack)
+	}
+
+	return stacks
+}
+
+// goroutineStacks returns the goroutine counts by stack, as the functions of the stack from the
+// innermost, so that goroutines started at different addresses of the same code are grouped
+func goroutineStacks() map[string]int {
+	var profile bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&profile, 1); err != nil {
+		return nil
+	}
+
+	stacks := map[string]int{}
+
+	// The profile groups goroutines by stack:
+	// 3 @ 0x43e2ae 0x44e0f8 ...
+	// #	0x44e0f7	time.Sleep+0x117	/usr/local/go/src/runtime/time.go:195
+	var count int
+	var frames []string
+	flush := func() {
+		if count > 0 {
+			stacks[strings.Join(frames, " < ")] += count
+		}
+		count, frames = 0, nil
+	}
+
+	scanner := bufio.NewScanner(&profile)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.Contains(line, " @ "):
+			flush()
+			count, _ = strconv.Atoi(strings.Fields(line)[0])
+		case strings.HasPrefix(line, "#"):
+			fields := strings.Fields(line)
+			if len(fields) >= 3 {
+				function, _, _ := strings.Cut(fields[2], "+0x")
+				frames = append(frames, function)
+			}
+		}
+	}
+	flush()
+
+	return stacks
+}
+
+func openFds() int {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0
+	}
+
+	return len(entries)
+}
@@ -0,0 +1,368 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+//go:build e2e
+
+// Package e2e runs the sandbox lifecycle against a real runner binary and Docker daemon. The
+// tests need root, as the runner manages iptables rules, and are run with
+//
+//	go test -tags e2e ./test/e2e -runner ../../dist/apps/runner -storage overlay2-xfs
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+// Storage backends of the environment matrix
+const (
+	// overlay2 on XFS mounted with pquota, where the storage quota of sandboxes is enforced
+	StorageOverlay2Xfs = "overlay2-xfs"
+	// Any other storage driver or backing filesystem, where it isn't
+	StorageOther = "other"
+)
+
+const registryImage = "registry:2"
+
+var (
+	runnerBinary = flag.String("runner", "../../dist/apps/runner", "Path of the runner binary")
+	snapshot     = flag.String("snapshot", "daytonaio/sandbox:0.5.0", "Snapshot the sandboxes are created from")
+	storage      = flag.String("storage", "", "Storage backend the environment is expected to have, overlay2-xfs or other. Detected if empty.")
+)
+
+// Environment is the runner under test, along with the registry backups are pushed to
+type Environment struct {
+	Docker *client.Client
+	// Storage backend of the Docker daemon, one of the Storage constants
+	Storage string
+
+	apiUrl      string
+	apiToken    string
+	registryUrl string
+	httpClient  *http.Client
+}
+
+// Setup starts a registry and the runner, which are stopped once the test completes
+func Setup(t *testing.T) *Environment {
+	t.Helper()
+
+	if os.Geteuid() != 0 {
+		t.Skip("the runner e2e tests need to run as root")
+	}
+
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		t.Fatalf("failed to create Docker client: %v", err)
+	}
+	t.Cleanup(func() { dockerClient.Close() })
+
+	env := &Environment{
+		Docker:     dockerClient,
+		apiToken:   "e2e-" + strconv.FormatInt(time.Now().UnixNano(), 36),
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+
+	env.Storage = env.detectStorage(t)
+	if *storage != "" && *storage != env.Storage {
+		t.Fatalf("expected the %s storage backend, the Docker daemon uses %s", *storage, env.Storage)
+	}
+
+	env.startRegistry(t)
+	env.startRunner(t)
+
+	return env
+}
+
+// detectStorage returns the storage backend of the Docker daemon
+func (e *Environment) detectStorage(t *testing.T) string {
+	info, err := e.Docker.Info(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get Docker info: %v", err)
+	}
+
+	if info.Driver != "overlay2" {
+		return StorageOther
+	}
+
+	for _, status := range info.DriverStatus {
+		if status[0] == "Backing Filesystem" && status[1] == "xfs" {
+			return StorageOverlay2Xfs
+		}
+	}
+
+	return StorageOther
+}
+
+// startRegistry starts a registry on the host network, where the Docker daemon can push to it
+// without TLS
+func (e *Environment) startRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	port := freePort(t)
+	e.registryUrl = fmt.Sprintf("localhost:%d", port)
+
+	reader, err := e.Docker.ImagePull(ctx, registryImage, image.PullOptions{})
+	if err != nil {
+		t.Fatalf("failed to pull %s: %v", registryImage, err)
+	}
+	_, _ = io.Copy(io.Discard, reader)
+	reader.Close()
+
+	created, err := e.Docker.ContainerCreate(ctx, &container.Config{
+		Image: registryImage,
+		Env:   []string{fmt.Sprintf("REGISTRY_HTTP_ADDR=127.0.0.1:%d", port)},
+	}, &container.HostConfig{
+		NetworkMode: "host",
+		AutoRemove:  true,
+	}, nil, nil, "")
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = e.Docker.ContainerRemove(context.Background(), created.ID, container.RemoveOptions{Force: true})
+	})
+
+	if err := e.Docker.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		t.Fatalf("failed to start registry: %v", err)
+	}
+
+	waitFor(t, 30*time.Second, "registry", func() error {
+		resp, err := http.Get(fmt.Sprintf("http://%s/v2/", e.registryUrl))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("registry responded with %s", resp.Status)
+		}
+		return nil
+	})
+}
+
+// startRunner starts the runner binary with the v1 API, which serves the sandbox operations
+// without a control plane
+func (e *Environment) startRunner(t *testing.T) {
+	binary, err := filepath.Abs(*runnerBinary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(binary); err != nil {
+		t.Fatalf("runner binary not found, build it with `nx build runner` or pass -runner: %v", err)
+	}
+
+	port := freePort(t)
+	e.apiUrl = fmt.Sprintf("http://127.0.0.1:%d", port)
+
+	dir := t.TempDir()
+	logFile, err := os.Create(filepath.Join(dir, "runner.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, binary)
+	cmd.Env = append(os.Environ(),
+		"ENVIRONMENT=development",
+		"API_VERSION=1",
+		"API_PORT="+strconv.Itoa(port),
+		"DAYTONA_RUNNER_TOKEN="+e.apiToken,
+		"LOG_FILE_PATH="+filepath.Join(dir, "daemon.log"),
+		"DAEMON_SOCKETS_DIR="+filepath.Join(dir, "sockets"),
+		"REAPER_ENABLED=false",
+	)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = 30 * time.Second
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start runner: %v", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-exited
+		logFile.Close()
+
+		if t.Failed() {
+			logs, _ := os.ReadFile(logFile.Name())
+			t.Logf("runner logs:\n%s", logs)
+		}
+	})
+
+	waitFor(t, time.Minute, "runner", func() error {
+		select {
+		case <-exited:
+			t.Fatalf("runner exited: %s", cmd.ProcessState)
+		default:
+		}
+		return e.Do(http.MethodGet, "/", nil, nil)
+	})
+}
+
+// Do sends a request to the runner API and decodes its response into result, if not nil
+func (e *Environment) Do(method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, e.apiUrl+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+e.apiToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s %s responded with %s: %s", method, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// Registry returns the registry backups are pushed to
+func (e *Environment) Registry() *dto.RegistryDTO {
+	return &dto.RegistryDTO{Url: e.registryUrl}
+}
+
+// RegistryImage returns the reference of an image in the registry
+func (e *Environment) RegistryImage(name string) string {
+	return fmt.Sprintf("%s/e2e/%s:latest", e.registryUrl, name)
+}
+
+// CreateSandbox creates a sandbox from the snapshot of the test run, which is destroyed once the
+// test completes
+func (e *Environment) CreateSandbox(t *testing.T, sandboxId string, modify func(*dto.CreateSandboxDTO)) {
+	t.Helper()
+
+	createDto := dto.CreateSandboxDTO{
+		Id:           sandboxId,
+		UserId:       "e2e",
+		Snapshot:     *snapshot,
+		OsUser:       "daytona",
+		CpuQuota:     1,
+		MemoryQuota:  1,
+		StorageQuota: 3,
+	}
+	if modify != nil {
+		modify(&createDto)
+	}
+
+	t.Cleanup(func() {
+		_ = e.Do(http.MethodPost, "/sandboxes/"+sandboxId+"/destroy", nil, nil)
+		_ = e.Do(http.MethodDelete, "/sandboxes/"+sandboxId, nil, nil)
+	})
+
+	if err := e.Do(http.MethodPost, "/sandboxes", createDto, nil); err != nil {
+		t.Fatalf("failed to create sandbox %s: %v", sandboxId, err)
+	}
+}
+
+// Exec runs a command in a sandbox through the toolbox of its daemon
+func (e *Environment) Exec(t *testing.T, sandboxId, command string) (int, string) {
+	t.Helper()
+
+	var response struct {
+		ExitCode int    `json:"exitCode"`
+		Result   string `json:"result"`
+	}
+	timeout := 120
+	err := e.Do(http.MethodPost, "/sandboxes/"+sandboxId+"/toolbox/process/execute", map[string]any{
+		"command": command,
+		"timeout": timeout,
+	}, &response)
+	if err != nil {
+		t.Fatalf("failed to execute %q in sandbox %s: %v", command, sandboxId, err)
+	}
+
+	return response.ExitCode, response.Result
+}
+
+// SandboxInfo returns the state of a sandbox
+func (e *Environment) SandboxInfo(t *testing.T, sandboxId string) SandboxInfo {
+	t.Helper()
+
+	var info SandboxInfo
+	if err := e.Do(http.MethodGet, "/sandboxes/"+sandboxId, nil, &info); err != nil {
+		t.Fatalf("failed to get sandbox %s: %v", sandboxId, err)
+	}
+
+	return info
+}
+
+type SandboxInfo struct {
+	State       string  `json:"state"`
+	BackupState string  `json:"backupState"`
+	BackupError *string `json:"backupError,omitempty"`
+}
+
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// waitFor retries a check until it succeeds or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, what string, check func() error) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := check()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s: %v", what, err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+//go:build e2e
+
+package e2e
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/api/dto"
+)
+
+func TestSandboxLifecycle(t *testing.T) {
+	env := Setup(t)
+
+	sandboxId := "e2e-lifecycle-" + time.Now().Format("150405")
+	env.CreateSandbox(t, sandboxId, nil)
+
+	if info := env.SandboxInfo(t, sandboxId); info.State != "started" {
+		t.Fatalf("expected the sandbox to be started after create, it is %s", info.State)
+	}
+
+	t.Run("exec", func(t *testing.T) {
+		exitCode, output := env.Exec(t, sandboxId, "echo hello")
+		if exitCode != 0 || strings.TrimSpace(output) != "hello" {
+			t.Fatalf("unexpected result of echo: exit code %d, output %q", exitCode, output)
+		}
+
+		exitCode, _ = env.Exec(t, sandboxId, "sh -c 'exit 3'")
+		if exitCode != 3 {
+			t.Fatalf("expected exit code 3, got %d", exitCode)
+		}
+	})
+
+	t.Run("stop and start", func(t *testing.T) {
+		env.Exec(t, sandboxId, "sh -c 'echo persisted > /home/daytona/marker'")
+
+		if err := env.Do(http.MethodPost, "/sandboxes/"+sandboxId+"/stop", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if info := env.SandboxInfo(t, sandboxId); info.State != "stopped" {
+			t.Fatalf("expected the sandbox to be stopped, it is %s", info.State)
+		}
+
+		if err := env.Do(http.MethodPost, "/sandboxes/"+sandboxId+"/start", map[string]string{}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, output := env.Exec(t, sandboxId, "cat /home/daytona/marker"); strings.TrimSpace(output) != "persisted" {
+			t.Fatalf("the filesystem of the sandbox didn't survive a restart, marker contains %q", output)
+		}
+	})
+
+	t.Run("backup and restore", func(t *testing.T) {
+		env.Exec(t, sandboxId, "sh -c 'echo backed-up > /home/daytona/backup-marker'")
+
+		backup := env.RegistryImage(sandboxId)
+		err := env.Do(http.MethodPost, "/sandboxes/"+sandboxId+"/backup", dto.CreateBackupDTO{
+			Registry: *env.Registry(),
+			Snapshot: backup,
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		waitFor(t, 10*time.Minute, "backup", func() error {
+			info := env.SandboxInfo(t, sandboxId)
+			switch info.BackupState {
+			case "COMPLETED":
+				return nil
+			case "FAILED":
+				t.Fatalf("backup failed: %v", deref(info.BackupError))
+			}
+			return errBackupState(info.BackupState)
+		})
+
+		// Restore from the registry rather than from the image the backup left on the runner
+		if err := env.Do(http.MethodPost, "/snapshots/remove?snapshot="+url.QueryEscape(backup), nil, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		restoredId := sandboxId + "-restored"
+		env.CreateSandbox(t, restoredId, func(createDto *dto.CreateSandboxDTO) {
+			createDto.Snapshot = backup
+			createDto.Registry = env.Registry()
+		})
+
+		if _, output := env.Exec(t, restoredId, "cat /home/daytona/backup-marker"); strings.TrimSpace(output) != "backed-up" {
+			t.Fatalf("the restored sandbox is missing the files of the backup, marker contains %q", output)
+		}
+	})
+
+	t.Run("destroy", func(t *testing.T) {
+		if err := env.Do(http.MethodPost, "/sandboxes/"+sandboxId+"/destroy", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if info := env.SandboxInfo(t, sandboxId); info.State != "destroyed" {
+			t.Fatalf("expected the sandbox to be destroyed, it is %s", info.State)
+		}
+
+		// Destroying a destroyed sandbox succeeds
+		if err := env.Do(http.MethodPost, "/sandboxes/"+sandboxId+"/destroy", nil, nil); err != nil {
+			t.Fatalf("destroying a destroyed sandbox failed: %v", err)
+		}
+	})
+}
+
+func TestStorageQuota(t *testing.T) {
+	env := Setup(t)
+	if env.Storage != StorageOverlay2Xfs {
+		t.Skipf("the storage quota is only enforced on %s, the Docker daemon uses %s", StorageOverlay2Xfs, env.Storage)
+	}
+
+	sandboxId := "e2e-quota-" + time.Now().Format("150405")
+	env.CreateSandbox(t, sandboxId, func(createDto *dto.CreateSandboxDTO) {
+		createDto.StorageQuota = 1
+	})
+
+	exitCode, output := env.Exec(t, sandboxId, "dd if=/dev/zero of=/home/daytona/fill bs=1M count=2048")
+	if exitCode == 0 {
+		t.Fatalf("writing 2GB to a sandbox with a 1GB quota succeeded: %s", output)
+	}
+	if !strings.Contains(output, "No space left on device") && !strings.Contains(output, "Disk quota exceeded") {
+		t.Fatalf("unexpected error writing past the quota: %s", output)
+	}
+
+	// The sandbox stays usable once the space is freed
+	env.Exec(t, sandboxId, "rm -f /home/daytona/fill")
+	if exitCode, output := env.Exec(t, sandboxId, "touch /home/daytona/after-quota"); exitCode != 0 {
+		t.Fatalf("the sandbox isn't writable after freeing space: %s", output)
+	}
+}
+
+type errBackupState string
+
+func (e errBackupState) Error() string {
+	return "backup is " + string(e)
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
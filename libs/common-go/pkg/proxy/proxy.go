@@ -37,6 +37,12 @@ var proxyTransport = &http.Transport{
 //	@Failure		500			{object}	string	"Internal server error"
 //	@Router			/workspaces/{workspaceId}/{projectId}/toolbox/{path} [get]
 func NewProxyRequestHandler(getProxyTarget func(*gin.Context) (targetUrl *url.URL, extraHeaders map[string]string, err error), modifyResponse func(*http.Response) error) gin.HandlerFunc {
+	return NewProxyRequestHandlerWithTransport(getProxyTarget, modifyResponse, proxyTransport)
+}
+
+// NewProxyRequestHandlerWithTransport is like NewProxyRequestHandler but sends the proxied requests
+// with the given transport, for targets that are not reachable with a regular TCP dialer
+func NewProxyRequestHandlerWithTransport(getProxyTarget func(*gin.Context) (targetUrl *url.URL, extraHeaders map[string]string, err error), modifyResponse func(*http.Response) error, transport http.RoundTripper) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		target, extraHeaders, err := getProxyTarget(ctx)
 		if err != nil {
@@ -59,7 +65,7 @@ func NewProxyRequestHandler(getProxyTarget func(*gin.Context) (targetUrl *url.UR
 					req.Header.Add(key, value)
 				}
 			},
-			Transport:      proxyTransport,
+			Transport:      transport,
 			ModifyResponse: modifyResponse,
 		}
 
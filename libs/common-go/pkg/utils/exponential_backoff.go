@@ -5,6 +5,7 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -18,7 +19,29 @@ const (
 	DEFAULT_MAX_DELAY   time.Duration = 5 * time.Second
 )
 
-// RetryWithExponentialBackoff executes a function with exponential backoff retry logic
+// PermanentError is a failure RetryWithExponentialBackoff doesn't retry
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks an error as not worth retrying, e.g. because the request was invalid
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// RetryWithExponentialBackoff executes a function with exponential backoff retry logic. Errors
+// marked with Permanent are returned right away, unwrapped.
 func RetryWithExponentialBackoff(ctx context.Context, operationName string, maxRetries int, baseDelay, maxDelay time.Duration, operationFunc func() error) error {
 	if maxRetries <= 1 {
 		log.Debugf("Invalid max retries value: %d. Using default value: %d", maxRetries, DEFAULT_MAX_RETRIES)
@@ -34,6 +57,11 @@ func RetryWithExponentialBackoff(ctx context.Context, operationName string, maxR
 			return nil
 		}
 
+		var permanentErr *PermanentError
+		if errors.As(err, &permanentErr) {
+			return permanentErr.Err
+		}
+
 		if attempt < maxRetries-1 {
 			// Calculate exponential backoff delay
 			delay := min(baseDelay*time.Duration(1<<attempt), maxDelay)
@@ -0,0 +1,207 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"slices"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+	"github.com/godbus/dbus/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	atspiRegistry   = "org.a11y.atspi.Registry"
+	atspiRootPath   = "/org/a11y/atspi/accessible/root"
+	atspiAccessible = "org.a11y.atspi.Accessible"
+	atspiComponent  = "org.a11y.atspi.Component"
+	// Coordinates relative to the screen
+	atspiCoordTypeScreen = uint32(0)
+
+	// Limits that keep a walk over large applications bounded
+	maxAccessibilityNodes    = 5000
+	accessibilityWalkTimeout = 15 * time.Second
+)
+
+// Names of the AtspiStateType values, indexed by their bit in the state set
+var atspiStates = []string{
+	"invalid", "active", "armed", "busy", "checked", "collapsed", "defunct", "editable", "enabled",
+	"expandable", "expanded", "focusable", "focused", "has-tooltip", "horizontal", "iconified", "modal",
+	"multi-line", "multiselectable", "opaque", "pressed", "resizable", "selectable", "selected",
+	"sensitive", "showing", "single-line", "stale", "transient", "vertical", "visible",
+	"manages-descendants", "indeterminate", "required", "truncated", "animated", "invalid-entry",
+	"supports-autocompletion", "selectable-text", "is-default", "visited", "checkable", "has-popup",
+	"read-only",
+}
+
+type accessibleRef struct {
+	Name string
+	Path dbus.ObjectPath
+}
+
+type accessibilityWalker struct {
+	ctx      context.Context
+	conn     *dbus.Conn
+	region   image.Rectangle
+	maxDepth int
+	visited  int
+}
+
+func (u *ComputerUse) GetAccessibilityTree(req *computeruse.AccessibilityTreeRequest) (*computeruse.AccessibilityTreeResponse, error) {
+	var region image.Rectangle
+	if req.Width > 0 && req.Height > 0 {
+		region = image.Rect(req.X, req.Y, req.X+req.Width, req.Y+req.Height)
+	}
+
+	nodes, err := u.accessibilityTree(region, req.MaxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &computeruse.AccessibilityTreeResponse{
+		Nodes: nodes,
+	}, nil
+}
+
+// accessibilityTree returns a node per application with the accessible objects of its windows
+func (u *ComputerUse) accessibilityTree(region image.Rectangle, maxDepth int) ([]computeruse.AccessibilityNode, error) {
+	conn, err := accessibilityBus()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), accessibilityWalkTimeout)
+	defer cancel()
+
+	w := &accessibilityWalker{
+		ctx:      ctx,
+		conn:     conn,
+		region:   region,
+		maxDepth: maxDepth,
+	}
+
+	apps, err := w.children(accessibleRef{Name: atspiRegistry, Path: atspiRootPath})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accessible applications: %w", err)
+	}
+
+	nodes := []computeruse.AccessibilityNode{}
+	for _, app := range apps {
+		// Skip applications without visible windows
+		node, ok := w.walk(app, 1)
+		if ok && (len(node.Children) > 0 || maxDepth == 1) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes, nil
+}
+
+// accessibilityBus connects to the accessibility bus, whose address is provided by the session bus
+func accessibilityBus() (*dbus.Conn, error) {
+	session, err := dbus.SessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the session bus: %w", err)
+	}
+
+	bus := session.Object("org.a11y.Bus", "/org/a11y/bus")
+
+	// Toolkits only expose their widgets once accessibility is enabled
+	if err := bus.SetProperty("org.a11y.Status.IsEnabled", dbus.MakeVariant(true)); err != nil {
+		log.Debugf("Failed to enable accessibility: %v", err)
+	}
+
+	var address string
+	if err := bus.Call("org.a11y.Bus.GetAddress", 0).Store(&address); err != nil {
+		return nil, fmt.Errorf("accessibility bus is not available: %w", err)
+	}
+
+	return dbus.Connect(address)
+}
+
+// walk returns the node of an accessible object and its descendants. Objects that are not
+// showing or are outside of the region are skipped with their descendants.
+func (w *accessibilityWalker) walk(ref accessibleRef, depth int) (computeruse.AccessibilityNode, bool) {
+	var node computeruse.AccessibilityNode
+
+	if w.visited >= maxAccessibilityNodes || w.ctx.Err() != nil {
+		return node, false
+	}
+	w.visited++
+
+	obj := w.conn.Object(ref.Name, ref.Path)
+
+	if err := obj.CallWithContext(w.ctx, atspiAccessible+".GetRoleName", 0).Store(&node.Role); err != nil {
+		return node, false
+	}
+	if name, err := obj.GetProperty(atspiAccessible + ".Name"); err == nil {
+		node.Name, _ = name.Value().(string)
+	}
+	if description, err := obj.GetProperty(atspiAccessible + ".Description"); err == nil {
+		node.Description, _ = description.Value().(string)
+	}
+
+	var states []uint32
+	if err := obj.CallWithContext(w.ctx, atspiAccessible+".GetState", 0).Store(&states); err == nil {
+		node.States = stateNames(states)
+	}
+
+	// Applications don't implement the component interface and have no extents
+	var extents struct{ X, Y, Width, Height int32 }
+	if err := obj.CallWithContext(w.ctx, atspiComponent+".GetExtents", 0, atspiCoordTypeScreen).Store(&extents); err == nil {
+		node.Position = computeruse.Position{X: int(extents.X), Y: int(extents.Y)}
+		node.Size = computeruse.Size{Width: int(extents.Width), Height: int(extents.Height)}
+
+		if !slices.Contains(node.States, "showing") {
+			return node, false
+		}
+
+		bounds := image.Rect(node.X, node.Y, node.X+node.Width, node.Y+node.Height)
+		if !w.region.Empty() && !bounds.Overlaps(w.region) {
+			return node, false
+		}
+	}
+
+	if w.maxDepth > 0 && depth >= w.maxDepth {
+		return node, true
+	}
+
+	children, err := w.children(ref)
+	if err != nil {
+		return node, true
+	}
+
+	for _, child := range children {
+		if childNode, ok := w.walk(child, depth+1); ok {
+			node.Children = append(node.Children, childNode)
+		}
+	}
+
+	return node, true
+}
+
+func (w *accessibilityWalker) children(ref accessibleRef) ([]accessibleRef, error) {
+	var children []accessibleRef
+	err := w.conn.Object(ref.Name, ref.Path).CallWithContext(w.ctx, atspiAccessible+".GetChildren", 0).Store(&children)
+	return children, err
+}
+
+// stateNames converts an AT-SPI state set, a bit set split in 32 bit words, to state names
+func stateNames(states []uint32) []string {
+	names := []string{}
+	for word, bits := range states {
+		for bit := 0; bit < 32; bit++ {
+			index := word*32 + bit
+			if bits&(1<<bit) != 0 && index < len(atspiStates) {
+				names = append(names, atspiStates[index])
+			}
+		}
+	}
+	return names
+}
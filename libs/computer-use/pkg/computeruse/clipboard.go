@@ -0,0 +1,135 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+)
+
+const defaultClipboardMimeType = "text/plain"
+
+func (u *ComputerUse) GetClipboard(req *computeruse.ClipboardRequest) (*computeruse.ClipboardResponse, error) {
+	mimeType := req.MimeType
+	if mimeType == "" {
+		mimeType = defaultClipboardMimeType
+	}
+
+	targets, err := u.readClipboard("TARGETS")
+	if err != nil {
+		// The clipboard has no owner
+		return &computeruse.ClipboardResponse{
+			MimeType:       mimeType,
+			AvailableTypes: []string{},
+		}, nil
+	}
+
+	availableTypes := []string{}
+	for _, target := range strings.Split(string(targets), "\n") {
+		// Skip X11 specific targets like TIMESTAMP or UTF8_STRING
+		if target = strings.TrimSpace(target); strings.Contains(target, "/") {
+			availableTypes = append(availableTypes, target)
+		}
+	}
+
+	// Plain text is offered as UTF8_STRING by most applications
+	target := mimeType
+	if mimeType == defaultClipboardMimeType {
+		target = "UTF8_STRING"
+	}
+
+	content, err := u.readClipboard(target)
+	if err != nil {
+		return nil, fmt.Errorf("clipboard has no %s content", mimeType)
+	}
+
+	response := &computeruse.ClipboardResponse{
+		MimeType:       mimeType,
+		AvailableTypes: availableTypes,
+	}
+
+	if strings.HasPrefix(mimeType, "text/") {
+		response.Text = string(content)
+	} else {
+		response.Data = base64.StdEncoding.EncodeToString(content)
+	}
+
+	return response, nil
+}
+
+func (u *ComputerUse) SetClipboard(req *computeruse.SetClipboardRequest) (*computeruse.Empty, error) {
+	mimeType := req.MimeType
+	if mimeType == "" {
+		mimeType = defaultClipboardMimeType
+	}
+
+	var content []byte
+	if strings.HasPrefix(mimeType, "text/") {
+		content = []byte(req.Text)
+	} else {
+		var err error
+		content, err = base64.StdEncoding.DecodeString(req.Data)
+		if err != nil {
+			return nil, fmt.Errorf("data must be base64 encoded: %w", err)
+		}
+	}
+
+	// Without a target xclip offers plain text in all the text targets applications ask for
+	args := []string{}
+	if mimeType != defaultClipboardMimeType {
+		args = append(args, "-t", mimeType)
+	}
+
+	if err := u.writeClipboard(content, args...); err != nil {
+		return nil, err
+	}
+
+	return new(computeruse.Empty), nil
+}
+
+// readClipboard prints the clipboard content in a target with xclip
+func (u *ComputerUse) readClipboard(target string) ([]byte, error) {
+	cmd := u.xclipCommand("-o", "-t", target)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("xclip failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return output, nil
+}
+
+// writeClipboard sets the clipboard content with xclip, which forks to serve it until another
+// application takes over the clipboard. The output of the fork is not captured, as waiting for
+// it would block until then.
+func (u *ComputerUse) writeClipboard(content []byte, args ...string) error {
+	cmd := u.xclipCommand(append([]string{"-i"}, args...)...)
+	cmd.Stdin = bytes.NewReader(content)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("xclip failed: %w", err)
+	}
+
+	return nil
+}
+
+func (u *ComputerUse) xclipCommand(args ...string) *exec.Cmd {
+	display := u.xDisplay
+	if display == "" {
+		display = os.Getenv("DISPLAY")
+	}
+
+	cmd := exec.Command("xclip", append([]string{"-selection", "clipboard"}, args...)...)
+	cmd.Env = append(os.Environ(), "DISPLAY="+display)
+	return cmd
+}
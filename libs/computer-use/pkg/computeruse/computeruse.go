@@ -39,6 +39,10 @@ type ComputerUse struct {
 	processes map[string]*Process
 	mu        sync.RWMutex
 	configDir string
+	// X display name, e.g. ":0"
+	xDisplay string
+	// Sizes of the virtual displays, laid out left to right
+	displays []computeruse.Size
 }
 
 var _ computeruse.IComputerUse = &ComputerUse{}
@@ -148,11 +152,14 @@ func (c *ComputerUse) initializeProcesses(homeDir string) {
 	// Get D-Bus session address from environment
 	dbusAddress := os.Getenv("DBUS_SESSION_BUS_ADDRESS")
 
+	c.xDisplay = display
+	c.displays = c.loadDisplayLayout(vncResolution)
+
 	// Process 1: Xvfb (X Virtual Framebuffer)
 	c.processes["xvfb"] = &Process{
 		Name:        "xvfb",
 		Command:     "/usr/bin/Xvfb",
-		Args:        []string{display, "-screen", "0", vncResolution + "x24"},
+		Args:        []string{display, "-screen", "0", xvfbScreenArg(c.displays)},
 		User:        user,
 		Priority:    100,
 		AutoRestart: true,
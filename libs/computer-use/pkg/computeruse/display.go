@@ -92,7 +92,11 @@ func (u *ComputerUse) TakeCompressedScreenshot(req *computeruse.CompressedScreen
 		Scale:   req.Scale,
 	}
 
-	bounds := screenshot.GetDisplayBounds(0)
+	bounds, err := u.displayBounds(req.Display)
+	if err != nil {
+		return nil, err
+	}
+
 	img, err := screenshot.CaptureRect(bounds)
 	if err != nil {
 		return nil, err
@@ -106,6 +110,8 @@ func (u *ComputerUse) TakeCompressedScreenshot(req *computeruse.CompressedScreen
 	mouseX, mouseY := 0, 0
 	if req.ShowCursor {
 		mouseX, mouseY = robotgo.Location()
+		mouseX -= bounds.Min.X
+		mouseY -= bounds.Min.Y
 		drawCursor(rgbaImg, mouseX, mouseY)
 	}
 
@@ -140,7 +146,12 @@ func (u *ComputerUse) TakeCompressedRegionScreenshot(req *computeruse.Compressed
 		Scale:   req.Scale,
 	}
 
-	rect := image.Rect(req.X, req.Y, req.X+req.Width, req.Y+req.Height)
+	x, y, err := u.toScreen(req.Display, req.X, req.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	rect := image.Rect(x, y, x+req.Width, y+req.Height)
 	img, err := screenshot.CaptureRect(rect)
 	if err != nil {
 		return nil, err
@@ -154,8 +165,8 @@ func (u *ComputerUse) TakeCompressedRegionScreenshot(req *computeruse.Compressed
 	mouseX, mouseY := 0, 0
 	if req.ShowCursor {
 		absoluteMouseX, absoluteMouseY := robotgo.Location()
-		mouseX = absoluteMouseX - req.X
-		mouseY = absoluteMouseY - req.Y
+		mouseX = absoluteMouseX - x
+		mouseY = absoluteMouseY - y
 
 		if mouseX >= 0 && mouseX < req.Width && mouseY >= 0 && mouseY < req.Height {
 			drawCursor(rgbaImg, mouseX, mouseY)
@@ -186,6 +197,8 @@ func (u *ComputerUse) TakeCompressedRegionScreenshot(req *computeruse.Compressed
 }
 
 func (u *ComputerUse) GetDisplayInfo() (*computeruse.DisplayInfoResponse, error) {
+	u.syncMonitors()
+
 	n := screenshot.NumActiveDisplays()
 	displays := make([]computeruse.DisplayInfo, n)
 
@@ -0,0 +1,166 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+	"github.com/go-vgo/robotgo"
+	"github.com/kbinani/screenshot"
+)
+
+const (
+	elementSourceAccessibility = "accessibility"
+	elementSourceOcr           = "ocr"
+)
+
+type elementMatch struct {
+	name   string
+	role   string
+	bounds image.Rectangle
+}
+
+// ClickElement finds an element by text and role, in the accessibility tree first and then
+// with OCR, and clicks its center
+func (u *ComputerUse) ClickElement(req *computeruse.ClickElementRequest) (*computeruse.ClickElementResponse, error) {
+	if req.Text == "" && req.Role == "" {
+		return nil, errors.New("text or role is required")
+	}
+	if req.Index < 0 {
+		return nil, errors.New("index must not be negative")
+	}
+
+	sources := []string{elementSourceAccessibility, elementSourceOcr}
+	switch req.Source {
+	case "":
+	case elementSourceAccessibility, elementSourceOcr:
+		sources = []string{req.Source}
+	default:
+		return nil, fmt.Errorf("invalid source %q", req.Source)
+	}
+
+	// OCR has no notion of roles
+	if req.Role != "" {
+		if req.Source == elementSourceOcr {
+			return nil, errors.New("role can't be used with the ocr source")
+		}
+		sources = []string{elementSourceAccessibility}
+	}
+
+	var lookupErrors []string
+	for _, source := range sources {
+		var matches []elementMatch
+		var err error
+		if source == elementSourceAccessibility {
+			matches, err = u.findAccessibleElements(req.Text, req.Role)
+		} else {
+			matches, err = findTextOnScreen(req.Text)
+		}
+		if err != nil {
+			lookupErrors = append(lookupErrors, fmt.Sprintf("%s: %v", source, err))
+			continue
+		}
+
+		if req.Index >= len(matches) {
+			continue
+		}
+
+		match := matches[req.Index]
+		clickAt(match.bounds, req.Button, req.Double)
+
+		return &computeruse.ClickElementResponse{
+			Source:   source,
+			Name:     match.name,
+			Role:     match.role,
+			Position: computeruse.Position{X: match.bounds.Min.X, Y: match.bounds.Min.Y},
+			Size:     computeruse.Size{Width: match.bounds.Dx(), Height: match.bounds.Dy()},
+		}, nil
+	}
+
+	if len(lookupErrors) > 0 {
+		return nil, fmt.Errorf("element not found (%s)", strings.Join(lookupErrors, "; "))
+	}
+	return nil, errors.New("element not found")
+}
+
+func (u *ComputerUse) findAccessibleElements(text, role string) ([]elementMatch, error) {
+	nodes, err := u.accessibilityTree(image.Rectangle{}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	text = strings.ToLower(text)
+	matches := []elementMatch{}
+
+	var visit func(node computeruse.AccessibilityNode)
+	visit = func(node computeruse.AccessibilityNode) {
+		if node.Width > 0 && node.Height > 0 && slices.Contains(node.States, "showing") &&
+			(role == "" || strings.EqualFold(node.Role, role)) &&
+			(text == "" || strings.Contains(strings.ToLower(node.Name), text)) {
+			matches = append(matches, elementMatch{
+				name:   node.Name,
+				role:   node.Role,
+				bounds: image.Rect(node.X, node.Y, node.X+node.Width, node.Y+node.Height),
+			})
+		}
+		for _, child := range node.Children {
+			visit(child)
+		}
+	}
+
+	for _, node := range nodes {
+		visit(node)
+	}
+
+	return matches, nil
+}
+
+// findTextOnScreen returns the runs of consecutive words on a line that contain the text
+func findTextOnScreen(text string) ([]elementMatch, error) {
+	text = strings.ToLower(text)
+	matches := []elementMatch{}
+
+	for i := 0; i < screenshot.NumActiveDisplays(); i++ {
+		words, err := recognizeText(screenshot.GetDisplayBounds(i), "")
+		if err != nil {
+			return nil, err
+		}
+
+		for start := 0; start < len(words); start++ {
+			joined := ""
+			bounds := image.Rectangle{}
+			for end := start; end < len(words) && words[end].line == words[start].line; end++ {
+				if end > start {
+					joined += " "
+				}
+				joined += strings.ToLower(words[end].text)
+				bounds = bounds.Union(words[end].bounds)
+
+				if strings.Contains(joined, text) {
+					matches = append(matches, elementMatch{name: joined, bounds: bounds})
+					start = end
+					break
+				}
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+func clickAt(bounds image.Rectangle, button string, double bool) {
+	if button == "" {
+		button = "left"
+	}
+
+	robotgo.Move(bounds.Min.X+bounds.Dx()/2, bounds.Min.Y+bounds.Dy()/2)
+	time.Sleep(100 * time.Millisecond)
+	robotgo.Click(button, double)
+}
@@ -12,6 +12,10 @@ import (
 )
 
 func (u *ComputerUse) TypeText(req *computeruse.KeyboardTypeRequest) (*computeruse.Empty, error) {
+	if err := u.focusDisplay(req.Display); err != nil {
+		return nil, err
+	}
+
 	if req.Delay > 0 {
 		robotgo.TypeStr(req.Text, req.Delay)
 	} else {
@@ -22,6 +26,10 @@ func (u *ComputerUse) TypeText(req *computeruse.KeyboardTypeRequest) (*computeru
 }
 
 func (u *ComputerUse) PressKey(req *computeruse.KeyboardPressRequest) (*computeruse.Empty, error) {
+	if err := u.focusDisplay(req.Display); err != nil {
+		return nil, err
+	}
+
 	if len(req.Modifiers) > 0 {
 		err := robotgo.KeyTap(req.Key, req.Modifiers)
 		if err != nil {
@@ -38,6 +46,10 @@ func (u *ComputerUse) PressKey(req *computeruse.KeyboardPressRequest) (*computer
 }
 
 func (u *ComputerUse) PressHotkey(req *computeruse.KeyboardHotkeyRequest) (*computeruse.Empty, error) {
+	if err := u.focusDisplay(req.Display); err != nil {
+		return nil, err
+	}
+
 	keys := strings.Split(req.Keys, "+")
 	if len(keys) < 2 {
 		return nil, fmt.Errorf("invalid hotkey format")
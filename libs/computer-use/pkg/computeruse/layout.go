@@ -0,0 +1,288 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+	"github.com/go-vgo/robotgo"
+	"github.com/kbinani/screenshot"
+	log "github.com/sirupsen/logrus"
+)
+
+// Virtual displays are RandR monitors laid out left to right on a single Xvfb screen, so
+// x11vnc and noVNC show all of them. The layout is stored so it survives restarts.
+
+const (
+	maxDisplays       = 8
+	maxDisplayWidth   = 7680
+	maxDisplayHeight  = 4320
+	monitorNamePrefix = "daytona-"
+)
+
+func (c *ComputerUse) layoutPath() string {
+	return filepath.Join(c.configDir, "displays.json")
+}
+
+// loadDisplayLayout returns the stored layout, or a single display with the default resolution
+func (c *ComputerUse) loadDisplayLayout(defaultResolution string) []computeruse.Size {
+	content, err := os.ReadFile(c.layoutPath())
+	if err == nil {
+		var layout []computeruse.Size
+		if err := json.Unmarshal(content, &layout); err == nil && len(layout) > 0 {
+			return layout
+		}
+		log.Warnf("Ignoring invalid display layout in %s", c.layoutPath())
+	}
+
+	width, height := 1024, 768
+	if w, h, ok := strings.Cut(defaultResolution, "x"); ok {
+		if v, err := strconv.Atoi(w); err == nil {
+			width = v
+		}
+		if v, err := strconv.Atoi(h); err == nil {
+			height = v
+		}
+	}
+
+	return []computeruse.Size{{Width: width, Height: height}}
+}
+
+func screenSize(layout []computeruse.Size) (int, int) {
+	width, height := 0, 0
+	for _, d := range layout {
+		width += d.Width
+		height = max(height, d.Height)
+	}
+	return width, height
+}
+
+func xvfbScreenArg(layout []computeruse.Size) string {
+	width, height := screenSize(layout)
+	return fmt.Sprintf("%dx%dx24", width, height)
+}
+
+func (c *ComputerUse) AddDisplay(req *computeruse.AddDisplayRequest) (*computeruse.DisplayInfoResponse, error) {
+	if err := validateDisplaySize(req.Size); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	layout := append([]computeruse.Size{}, c.displays...)
+	c.mu.RUnlock()
+
+	if len(layout) >= maxDisplays {
+		return nil, fmt.Errorf("at most %d displays are supported", maxDisplays)
+	}
+
+	if err := c.applyDisplayLayout(append(layout, req.Size)); err != nil {
+		return nil, err
+	}
+
+	return c.GetDisplayInfo()
+}
+
+func (c *ComputerUse) ResizeDisplay(req *computeruse.ResizeDisplayRequest) (*computeruse.DisplayInfoResponse, error) {
+	if err := validateDisplaySize(req.Size); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	layout := append([]computeruse.Size{}, c.displays...)
+	c.mu.RUnlock()
+
+	if req.ID < 0 || req.ID >= len(layout) {
+		return nil, fmt.Errorf("display %d not found", req.ID)
+	}
+	layout[req.ID] = req.Size
+
+	if err := c.applyDisplayLayout(layout); err != nil {
+		return nil, err
+	}
+
+	return c.GetDisplayInfo()
+}
+
+func validateDisplaySize(size computeruse.Size) error {
+	if size.Width < 320 || size.Height < 240 || size.Width > maxDisplayWidth || size.Height > maxDisplayHeight {
+		return fmt.Errorf("display size must be between 320x240 and %dx%d", maxDisplayWidth, maxDisplayHeight)
+	}
+	return nil
+}
+
+// applyDisplayLayout resizes the X screen to fit the layout and defines a monitor per display.
+// The layout is stored and used for the Xvfb arguments even if the running X server can't be
+// resized, in which case it is applied when Xvfb restarts.
+func (c *ComputerUse) applyDisplayLayout(layout []computeruse.Size) error {
+	content, err := json.Marshal(layout)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.layoutPath(), content, 0644); err != nil {
+		return fmt.Errorf("failed to save display layout: %w", err)
+	}
+
+	c.mu.Lock()
+	c.displays = layout
+	if xvfb, ok := c.processes["xvfb"]; ok {
+		xvfb.Args = []string{c.xDisplay, "-screen", "0", xvfbScreenArg(layout)}
+	}
+	c.mu.Unlock()
+
+	width, height := screenSize(layout)
+	if output, err := c.xrandr("--fb", fmt.Sprintf("%dx%d", width, height)); err != nil {
+		return fmt.Errorf("the X server can't be resized (%s), restart the xvfb process to apply the new layout", strings.TrimSpace(output))
+	}
+
+	return c.configureMonitors()
+}
+
+// configureMonitors replaces the monitors of the X screen with one monitor per display
+func (c *ComputerUse) configureMonitors() error {
+	c.mu.RLock()
+	layout := append([]computeruse.Size{}, c.displays...)
+	c.mu.RUnlock()
+
+	output, err := c.xrandr("--listmonitors")
+	if err != nil {
+		return fmt.Errorf("failed to list monitors: %s", strings.TrimSpace(output))
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// Monitor lines look like " 0: +*daytona-0 1024/270x768/203+0+0  screen"
+		name := strings.TrimLeft(fields[1], "+*")
+		if strings.HasPrefix(name, monitorNamePrefix) {
+			if out, err := c.xrandr("--delmonitor", name); err != nil {
+				log.Warnf("Failed to remove monitor %s: %s", name, strings.TrimSpace(out))
+			}
+		}
+	}
+
+	if len(layout) < 2 {
+		return nil
+	}
+
+	screenOutput, err := c.primaryOutput()
+	if err != nil {
+		return err
+	}
+
+	x := 0
+	for i, d := range layout {
+		// The first monitor takes over the output, which hides its automatic full screen monitor
+		output := "none"
+		if i == 0 {
+			output = screenOutput
+		}
+
+		geometry := fmt.Sprintf("%d/%dx%d/%d+%d+0", d.Width, d.Width*254/960, d.Height, d.Height*254/960, x)
+		if out, err := c.xrandr("--setmonitor", monitorNamePrefix+strconv.Itoa(i), geometry, output); err != nil {
+			return fmt.Errorf("failed to configure display %d: %s", i, strings.TrimSpace(out))
+		}
+		x += d.Width
+	}
+
+	return nil
+}
+
+// syncMonitors configures the monitors again if they were lost, e.g. after Xvfb restarted
+func (c *ComputerUse) syncMonitors() {
+	c.mu.RLock()
+	expected := len(c.displays)
+	c.mu.RUnlock()
+
+	if expected < 2 || screenshot.NumActiveDisplays() == expected {
+		return
+	}
+
+	if err := c.configureMonitors(); err != nil {
+		log.Warnf("Failed to configure displays: %v", err)
+	}
+}
+
+func (c *ComputerUse) primaryOutput() (string, error) {
+	output, err := c.xrandr("--query")
+	if err != nil {
+		return "", fmt.Errorf("failed to query outputs: %s", strings.TrimSpace(output))
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[1] == "connected" {
+			return fields[0], nil
+		}
+	}
+
+	return "", errors.New("no connected output found")
+}
+
+func (c *ComputerUse) xrandr(args ...string) (string, error) {
+	cmd := exec.Command("xrandr", args...)
+	cmd.Env = append(os.Environ(), "DISPLAY="+c.xDisplay)
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// displayBounds returns the bounds of a display, the first display if none is given
+func (c *ComputerUse) displayBounds(display *int) (image.Rectangle, error) {
+	c.syncMonitors()
+
+	id := 0
+	if display != nil {
+		id = *display
+	}
+
+	if id < 0 || id >= screenshot.NumActiveDisplays() {
+		return image.Rectangle{}, fmt.Errorf("display %d not found", id)
+	}
+
+	return screenshot.GetDisplayBounds(id), nil
+}
+
+// toScreen converts coordinates relative to a display to screen coordinates
+func (c *ComputerUse) toScreen(display *int, x, y int) (int, int, error) {
+	if display == nil {
+		return x, y, nil
+	}
+
+	bounds, err := c.displayBounds(display)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return bounds.Min.X + x, bounds.Min.Y + y, nil
+}
+
+// focusDisplay moves the pointer to the center of a display unless it is already on it, so
+// keyboard input goes to that display when the window manager focuses windows under the pointer
+func (c *ComputerUse) focusDisplay(display *int) error {
+	if display == nil {
+		return nil
+	}
+
+	bounds, err := c.displayBounds(display)
+	if err != nil {
+		return err
+	}
+
+	x, y := robotgo.Location()
+	if !image.Pt(x, y).In(bounds) {
+		robotgo.Move(bounds.Min.X+bounds.Dx()/2, bounds.Min.Y+bounds.Dy()/2)
+	}
+
+	return nil
+}
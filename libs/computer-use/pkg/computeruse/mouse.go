@@ -28,7 +28,12 @@ func (u *ComputerUse) GetMousePosition() (*computeruse.MousePositionResponse, er
 }
 
 func (u *ComputerUse) MoveMouse(req *computeruse.MouseMoveRequest) (*computeruse.MousePositionResponse, error) {
-	robotgo.Move(req.X, req.Y)
+	x, y, err := u.toScreen(req.Display, req.X, req.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	robotgo.Move(x, y)
 
 	// Small delay to ensure movement completes
 	time.Sleep(50 * time.Millisecond)
@@ -50,8 +55,13 @@ func (u *ComputerUse) Click(req *computeruse.MouseClickRequest) (*computeruse.Mo
 		req.Button = "left"
 	}
 
+	x, y, err := u.toScreen(req.Display, req.X, req.Y)
+	if err != nil {
+		return nil, err
+	}
+
 	// Move mouse to position first
-	robotgo.Move(req.X, req.Y)
+	robotgo.Move(x, y)
 	time.Sleep(100 * time.Millisecond) // Wait for mouse to move
 
 	// Perform the click
@@ -90,8 +100,17 @@ func (u *ComputerUse) Drag(req *computeruse.MouseDragRequest) (*computeruse.Mous
 		req.Button = "left"
 	}
 
+	startX, startY, err := u.toScreen(req.Display, req.StartX, req.StartY)
+	if err != nil {
+		return nil, err
+	}
+	endX, endY, err := u.toScreen(req.Display, req.EndX, req.EndY)
+	if err != nil {
+		return nil, err
+	}
+
 	// Move to start position
-	robotgo.Move(req.StartX, req.StartY)
+	robotgo.Move(startX, startY)
 	time.Sleep(100 * time.Millisecond)
 
 	// Click to focus window before drag
@@ -99,7 +118,7 @@ func (u *ComputerUse) Drag(req *computeruse.MouseDragRequest) (*computeruse.Mous
 	time.Sleep(100 * time.Millisecond)
 
 	// Ensure mouse button is up before starting
-	err := robotgo.MouseUp(req.Button)
+	err = robotgo.MouseUp(req.Button)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +132,7 @@ func (u *ComputerUse) Drag(req *computeruse.MouseDragRequest) (*computeruse.Mous
 	time.Sleep(300 * time.Millisecond) // Increased delay
 
 	// Move to end position while holding (smoothly)
-	moveMouseSmoothly(req.StartX, req.StartY, req.EndX, req.EndY, 20)
+	moveMouseSmoothly(startX, startY, endX, endY, 20)
 	time.Sleep(100 * time.Millisecond)
 
 	// Release mouse button
@@ -140,8 +159,13 @@ func (u *ComputerUse) Scroll(req *computeruse.MouseScrollRequest) (*computeruse.
 		req.Amount = 3
 	}
 
+	x, y, err := u.toScreen(req.Display, req.X, req.Y)
+	if err != nil {
+		return nil, err
+	}
+
 	// Move mouse to scroll position
-	robotgo.Move(req.X, req.Y)
+	robotgo.Move(x, y)
 	time.Sleep(50 * time.Millisecond)
 
 	// Perform scroll
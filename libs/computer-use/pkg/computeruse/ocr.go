@@ -0,0 +1,156 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+	"github.com/kbinani/screenshot"
+)
+
+// ocrWord is a recognized word in screen coordinates
+type ocrWord struct {
+	text       string
+	confidence float64
+	bounds     image.Rectangle
+	// Words with the same line key are on the same line
+	line string
+}
+
+func (u *ComputerUse) RecognizeText(req *computeruse.OcrRequest) (*computeruse.OcrResponse, error) {
+	var rect image.Rectangle
+	if req.Width > 0 && req.Height > 0 {
+		x, y, err := u.toScreen(req.Display, req.X, req.Y)
+		if err != nil {
+			return nil, err
+		}
+		rect = image.Rect(x, y, x+req.Width, y+req.Height)
+	} else {
+		bounds, err := u.displayBounds(req.Display)
+		if err != nil {
+			return nil, err
+		}
+		rect = bounds
+	}
+
+	words, err := recognizeText(rect, req.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	// Words are reported relative to the display if one is targeted
+	originX, originY, err := u.toScreen(req.Display, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &computeruse.OcrResponse{
+		Words: make([]computeruse.OcrWord, 0, len(words)),
+	}
+
+	var text strings.Builder
+	for i, word := range words {
+		if i > 0 {
+			if word.line != words[i-1].line {
+				text.WriteString("\n")
+			} else {
+				text.WriteString(" ")
+			}
+		}
+		text.WriteString(word.text)
+
+		response.Words = append(response.Words, computeruse.OcrWord{
+			Text:       word.text,
+			Confidence: word.confidence,
+			Position:   computeruse.Position{X: word.bounds.Min.X - originX, Y: word.bounds.Min.Y - originY},
+			Size:       computeruse.Size{Width: word.bounds.Dx(), Height: word.bounds.Dy()},
+		})
+	}
+	response.Text = text.String()
+
+	return response, nil
+}
+
+// recognizeText captures a region of the screen and runs tesseract on it
+func recognizeText(rect image.Rectangle, language string) ([]ocrWord, error) {
+	img, err := screenshot.CaptureRect(rect)
+	if err != nil {
+		return nil, err
+	}
+
+	var input bytes.Buffer
+	if err := png.Encode(&input, img); err != nil {
+		return nil, err
+	}
+
+	if language == "" {
+		language = "eng"
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("tesseract", "stdin", "stdout", "-l", language, "tsv")
+	cmd.Stdin = &input
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("tesseract failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseTesseractTsv(&stdout, rect.Min)
+}
+
+// parseTesseractTsv parses the words of a tesseract TSV report, whose columns are level,
+// page_num, block_num, par_num, line_num, word_num, left, top, width, height, conf and text
+func parseTesseractTsv(r io.Reader, offset image.Point) ([]ocrWord, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tesseract output: %w", err)
+	}
+
+	words := []ocrWord{}
+	for i, record := range records {
+		// Skip the header and everything but words, which are level 5
+		if i == 0 || len(record) < 12 || record[0] != "5" {
+			continue
+		}
+
+		text := strings.TrimSpace(record[11])
+		if text == "" {
+			continue
+		}
+
+		values := make([]int, 4)
+		for j := range values {
+			if values[j], err = strconv.Atoi(record[6+j]); err != nil {
+				return nil, fmt.Errorf("invalid tesseract output: %q", strings.Join(record, "\t"))
+			}
+		}
+		confidence, _ := strconv.ParseFloat(record[10], 64)
+
+		left, top := offset.X+values[0], offset.Y+values[1]
+		words = append(words, ocrWord{
+			text:       text,
+			confidence: confidence,
+			bounds:     image.Rect(left, top, left+values[2], top+values[3]),
+			line:       strings.Join(record[1:5], "."),
+		})
+	}
+
+	return words, nil
+}
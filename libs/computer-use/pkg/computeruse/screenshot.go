@@ -79,7 +79,11 @@ func (u *ComputerUse) TakeScreenshot(req *computeruse.ScreenshotRequest) (*compu
 	display := os.Getenv("DISPLAY")
 	log.Infof("TakeScreenshot: DISPLAY=%s", display)
 
-	bounds := screenshot.GetDisplayBounds(0)
+	bounds, err := u.displayBounds(req.Display)
+	if err != nil {
+		return nil, err
+	}
+
 	img, err := screenshot.CaptureRect(bounds)
 	if err != nil {
 		log.Errorf("TakeScreenshot error: %v", err)
@@ -94,6 +98,8 @@ func (u *ComputerUse) TakeScreenshot(req *computeruse.ScreenshotRequest) (*compu
 	mouseX, mouseY := 0, 0
 	if req.ShowCursor {
 		mouseX, mouseY = robotgo.Location()
+		mouseX -= bounds.Min.X
+		mouseY -= bounds.Min.Y
 		drawCursor(rgbaImg, mouseX, mouseY)
 	}
 
@@ -128,7 +134,12 @@ func (u *ComputerUse) TakeRegionScreenshot(req *computeruse.RegionScreenshotRequ
 	display := os.Getenv("DISPLAY")
 	log.Infof("TakeRegionScreenshot: DISPLAY=%s", display)
 
-	rect := image.Rect(req.X, req.Y, req.X+req.Width, req.Y+req.Height)
+	x, y, err := u.toScreen(req.Display, req.X, req.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	rect := image.Rect(x, y, x+req.Width, y+req.Height)
 	img, err := screenshot.CaptureRect(rect)
 	if err != nil {
 		log.Errorf("TakeRegionScreenshot error: %v", err)
@@ -144,8 +155,8 @@ func (u *ComputerUse) TakeRegionScreenshot(req *computeruse.RegionScreenshotRequ
 	if req.ShowCursor {
 		absoluteMouseX, absoluteMouseY := robotgo.Location()
 		// Convert to relative coordinates within the region
-		mouseX = absoluteMouseX - req.X
-		mouseY = absoluteMouseY - req.Y
+		mouseX = absoluteMouseX - x
+		mouseY = absoluteMouseY - y
 
 		// Only draw if cursor is within the region
 		if mouseX >= 0 && mouseX < req.Width && mouseY >= 0 && mouseY < req.Height {